@@ -8,14 +8,17 @@ npm clean-install
 npm run build
 popd
 
-(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -tags lgpl)
+# The sqlite tag adds the optional SQLite database backend (--dbbackend sqlite)
+# and requires cgo. Build sqlitemigrate with the same tag to copy an existing
+# bolt database into SQLite.
+(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -tags lgpl,sqlite)
 #
 # Mac-specific issue with older Golang versions (resolved with `codesign`), see discussions
 # here for details: https://github.com/golang/go/issues/63997
-#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -tags lgpl && codesign -s - -f ./bisonw)
+#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -tags lgpl,sqlite && codesign -s - -f ./bisonw)
 #
 # to specify OS and Architecture
-#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on GOOS=darwin GOARCH=arm64 go build -tags lgpl)
+#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on GOOS=darwin GOARCH=arm64 go build -tags lgpl,sqlite)
 #
 # -race build
-#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -race -tags lgpl)
+#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -race -tags lgpl,sqlite)
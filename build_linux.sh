@@ -7,11 +7,12 @@ npm clean-install
 npm run build
 popd
 
-#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on GOOS=linux GOARCH=amd64 go build -tags lgpl)
-(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -tags lgpl)
+#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on GOOS=linux GOARCH=amd64 go build -tags lgpl,sqlite)
+# The sqlite tag adds the optional SQLite database backend and requires cgo.
+(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -tags lgpl,sqlite)
 #
 # Note, this is -race build, to be used for testing only!
-#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -race -tags lgpl)
+#(cd client/cmd/bisonw/ && CGO_ENABLED=1 GO111MODULE=on go build -race -tags lgpl,sqlite)
 # TODO, previously was CGO_ENABLED=0 ?
 
 # Run Bison binary with:
@@ -10,8 +10,11 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"decred.org/dcrdex/client/asset/extwallet"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db/sqlite"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/client/rpcserver"
 	"decred.org/dcrdex/client/webserver"
@@ -24,6 +27,8 @@ import (
 const (
 	defaultRPCCertFile = "rpc.cert"
 	defaultRPCKeyFile  = "rpc.key"
+	defaultAPIKeysFile = "rpcapikeys.json"
+	defaultClientCerts = "rpcclientcerts.json"
 	defaultMainnetHost = "127.0.0.1"
 	defaultTestnetHost = "127.0.0.2"
 	defaultSimnetHost  = "127.0.0.3"
@@ -33,6 +38,11 @@ const (
 	defaultWebPort     = "5758"
 	defaultLogLevel    = "debug"
 	configFilename     = "dexc.conf"
+	defaultLocalesDir  = "locales"
+	// defaultNoteRetention is how long notifications are kept by default.
+	defaultNoteRetention = 90 * 24 * time.Hour
+	// defaultBackupsKept is how many encrypted backups are kept by default.
+	defaultBackupsKept = 10
 )
 
 var (
@@ -42,11 +52,16 @@ var (
 
 // RPCConfig encapsulates the configuration needed for the RPC server.
 type RPCConfig struct {
-	RPCAddr string `long:"rpcaddr" description:"RPC server listen address"`
-	RPCUser string `long:"rpcuser" description:"RPC server user name"`
-	RPCPass string `long:"rpcpass" description:"RPC server password"`
-	RPCCert string `long:"rpccert" description:"RPC server certificate file location"`
-	RPCKey  string `long:"rpckey" description:"RPC server key file location"`
+	RPCAddr    string `long:"rpcaddr" description:"RPC server listen address"`
+	RPCUser    string `long:"rpcuser" description:"RPC server user name"`
+	RPCPass    string `long:"rpcpass" description:"RPC server password"`
+	RPCCert    string `long:"rpccert" description:"RPC server certificate file location"`
+	RPCKey     string `long:"rpckey" description:"RPC server key file location"`
+	RPCAPIKeys string `long:"rpcapikeys" description:"RPC server API keys file location"`
+	// RPCClientCerts is the allowlist of TLS client certificates. The file is
+	// managed with the addclientcert and revokeclientcert commands, but can
+	// also be edited while the RPC server is not running.
+	RPCClientCerts string `long:"rpcclientcerts" description:"RPC server TLS client certificate allowlist file location"`
 	// CertHosts is a list of hosts given to certgen.NewTLSCertPair for the
 	// "Subject Alternate Name" values of the generated TLS certificate. It is
 	// set automatically, not via the config file or cli args.
@@ -84,6 +99,8 @@ func (cfg *RPCConfig) RPC(c *core.Core, marketMaker *mm.MarketMaker, log dex.Log
 		Pass:        cfg.RPCPass,
 		Cert:        cfg.RPCCert,
 		Key:         cfg.RPCKey,
+		APIKeys:     cfg.RPCAPIKeys,
+		ClientCerts: cfg.RPCClientCerts,
 		BWVersion:   bwVersion,
 		CertHosts: []string{
 			defaultTestnetHost, defaultSimnetHost, defaultMainnetHost,
@@ -95,6 +112,7 @@ func (cfg *RPCConfig) RPC(c *core.Core, marketMaker *mm.MarketMaker, log dex.Log
 // CoreConfig encapsulates the settings specific to core.Core.
 type CoreConfig struct {
 	DBPath       string `long:"db" description:"Database filepath. Database will be created if it does not exist."`
+	DBBackend    string `long:"dbbackend" description:"Database backend, bolt (default) or sqlite. sqlite is only available in builds with -tags sqlite, which require cgo. Use sqlitemigrate, built with the same tag, to copy an existing bolt database."`
 	Onion        string `long:"onion" description:"Proxy for .onion addresses, if torproxy not set (eg. 127.0.0.1:9050)."`
 	TorProxy     string `long:"torproxy" description:"Connect via TOR (eg. 127.0.0.1:9050)."`
 	TorIsolation bool   `long:"torisolation" description:"Enable TOR circuit isolation."`
@@ -106,8 +124,18 @@ type CoreConfig struct {
 	NoAutoWalletLock   bool `long:"no-wallet-lock" description:"Disable locking of wallets on shutdown or logout. Use this if you want your external wallets to stay unlocked after closing the DEX app."`
 	NoAutoDBBackup     bool `long:"no-db-backup" description:"Disable creation of a database backup on shutdown."`
 	UnlockCoinsOnLogin bool `long:"release-wallet-coins" description:"On login or wallet creation, instruct the wallet to release any coins that it may have locked."`
+	PreferCBOR         bool `long:"cbor" description:"Request the binary CBOR message encoding from DEX servers, which reduces bandwidth and parsing overhead. Servers that do not support it will use JSON."`
+	QUIC               bool `long:"quic" description:"Connect to DEX servers with QUIC, which reconnects faster on lossy networks, if they advertise it. The first connection to a server is a websocket, which learns whether the server accepts QUIC. Servers reached through a proxy or Tor are always connected with websockets."`
+
+	NoteRetention time.Duration `long:"noteretention" description:"How long to keep notifications in the database, e.g. 720h for 30 days. Set to 0 to keep notifications forever."`
+
+	BackupDir      string        `long:"backupdir" description:"Directory for the encrypted database backups. Default is an encrypted-backups folder next to the database."`
+	BackupInterval time.Duration `long:"backupinterval" description:"How often to create an encrypted backup of the database while logged in, e.g. 24h. Set to 0 to disable scheduled backups."`
+	BackupsKept    int           `long:"backupskept" description:"Number of encrypted backups to keep. Older backups are deleted. Set to 0 to keep all backups."`
 
 	ExtensionModeFile string `long:"extension-mode-file" description:"path to a file that specifies options for running core as an extension."`
+
+	ExtWallets []string `long:"extwallet" description:"Register an asset whose wallet is an external process implementing the extwallet JSON-RPC interface, as assetID:conversionfactor[:name], e.g. 128:1000000000000:Monero. The asset ID is the asset's BIP-44 coin type. Create the wallet with the External wallet type. May be repeated."`
 }
 
 // WebConfig encapsulates the configuration needed for the web server.
@@ -117,6 +145,7 @@ type WebConfig struct {
 	SiteDir     string `long:"sitedir" description:"Path to the 'site' directory with packaged web files. Unspecified = default is good in most cases."`
 	NoEmbedSite bool   `long:"no-embed-site" description:"Use on-disk UI files instead of embedded resources. This also reloads the html template with every request. For development purposes."`
 	HTTPProfile bool   `long:"httpprof" description:"Start HTTP profiler on /pprof."`
+	GuestPass   string `long:"guestpass" description:"Enable read-only guest access to public market data with this password. Guests log in on the /guest page, and cannot see balances, wallets, or orders."`
 	// Deprecated
 	Experimental bool `long:"experimental" description:"DEPRECATED: Enable experimental features"`
 }
@@ -150,13 +179,16 @@ type Config struct {
 	ConfigPath string `long:"config" description:"Path to an INI configuration file."`
 	// Testnet and Simnet are used to set the derivative CoreConfig.Net
 	// dex.Network field.
-	Testnet    bool   `long:"testnet" description:"use testnet"`
-	Simnet     bool   `long:"simnet" description:"use simnet"`
-	RPCOn      bool   `long:"rpc" description:"turn on the rpc server"`
-	NoWeb      bool   `long:"noweb" description:"disable the web server."`
-	CPUProfile string `long:"cpuprofile" description:"File for CPU profiling."`
-	ShowVer    bool   `short:"V" long:"version" description:"Display version information and exit"`
-	Language   string `long:"lang" description:"BCP 47 tag for preferred language, e.g. en-GB, fr, zh-CN"`
+	Testnet     bool   `long:"testnet" description:"use testnet"`
+	Simnet      bool   `long:"simnet" description:"use simnet"`
+	RPCOn       bool   `long:"rpc" description:"turn on the rpc server"`
+	NoWeb       bool   `long:"noweb" description:"disable the web server."`
+	CtlSocket   string `long:"ctlsocket" description:"Path of a unix socket for local control of the app, e.g. by a service manager. Only the user running the app may use the socket. The commands are status, lock, unlock, shutdown, loglevel, and backup. See bwctl --ctlsocket. Disabled if not set."`
+	CPUProfile  string `long:"cpuprofile" description:"File for CPU profiling."`
+	ShowVer     bool   `short:"V" long:"version" description:"Display version information and exit"`
+	Maintenance bool   `long:"maintenance" description:"Check the consistency of the database, compact it, and exit."`
+	Language    string `long:"lang" description:"BCP 47 tag for preferred language, e.g. en-GB, fr, zh-CN"`
+	LocalesDir  string `long:"localesdir" description:"Directory of JSON notification translation files named by language tag, e.g. pt-BR.json. Default is the locales directory in appdata."`
 }
 
 // Web creates a configuration for the webserver. This is a Config method
@@ -196,6 +228,7 @@ func (cfg *Config) Web(c *core.Core, mm *mm.MarketMaker, log dex.Logger, utc boo
 		NoEmbed:       cfg.NoEmbedSite,
 		HttpProf:      cfg.HTTPProfile,
 		Language:      cfg.Language,
+		GuestPass:     cfg.GuestPass,
 	}
 }
 
@@ -205,15 +238,23 @@ func (cfg *Config) Web(c *core.Core, mm *mm.MarketMaker, log dex.Logger, utc boo
 func (cfg *Config) Core(log dex.Logger) *core.Config {
 	return &core.Config{
 		DBPath:             cfg.DBPath,
+		DBBackend:          cfg.DBBackend,
 		Net:                cfg.Net,
 		Logger:             log,
 		Onion:              cfg.Onion,
 		TorProxy:           cfg.TorProxy,
 		TorIsolation:       cfg.TorIsolation,
 		Language:           cfg.Language,
+		LocalesDir:         cfg.LocalesDir,
+		NoteRetention:      cfg.NoteRetention,
 		UnlockCoinsOnLogin: cfg.UnlockCoinsOnLogin,
 		NoAutoWalletLock:   cfg.NoAutoWalletLock,
 		NoAutoDBBackup:     cfg.NoAutoDBBackup,
+		BackupDir:          cfg.BackupDir,
+		BackupInterval:     cfg.BackupInterval,
+		BackupsKept:        cfg.BackupsKept,
+		PreferCBOR:         cfg.PreferCBOR,
+		QUIC:               cfg.QUIC,
 		ExtensionModeFile:  cfg.ExtensionModeFile,
 		TheOneHost:         cfg.TheOneHost,
 	}
@@ -223,6 +264,10 @@ var DefaultConfig = Config{
 	AppData:    defaultApplicationDirectory,
 	ConfigPath: defaultConfigPath,
 	LogConfig:  LogConfig{DebugLevel: defaultLogLevel},
+	CoreConfig: CoreConfig{
+		NoteRetention: defaultNoteRetention,
+		BackupsKept:   defaultBackupsKept,
+	},
 	RPCConfig: RPCConfig{
 		CertHosts: []string{defaultTestnetHost, defaultSimnetHost, defaultMainnetHost},
 	},
@@ -302,6 +347,22 @@ func ResolveConfig(appData string, cfg *Config) error {
 
 	cfg.AppData = appData
 
+	switch cfg.DBBackend {
+	case "", core.DBBackendBolt:
+	case core.DBBackendSQLite:
+		if !sqlite.Supported() {
+			return sqlite.ErrNoDriver
+		}
+	default:
+		return fmt.Errorf("unknown database backend %q", cfg.DBBackend)
+	}
+
+	for _, def := range cfg.ExtWallets {
+		if err := extwallet.RegisterConfigured(def); err != nil {
+			return fmt.Errorf("error registering external wallet asset: %w", err)
+		}
+	}
+
 	var defaultDBPath, defaultLogPath, defaultMMEventLogDBPath, defaultMMConfigPath string
 	switch {
 	case cfg.Testnet:
@@ -333,8 +394,19 @@ func ResolveConfig(appData string, cfg *Config) error {
 		cfg.RPCKey = filepath.Join(appData, defaultRPCKeyFile)
 	}
 
+	if cfg.RPCAPIKeys == "" {
+		cfg.RPCAPIKeys = filepath.Join(appData, defaultAPIKeysFile)
+	}
+
+	if cfg.RPCClientCerts == "" {
+		cfg.RPCClientCerts = filepath.Join(appData, defaultClientCerts)
+	}
+
 	if cfg.DBPath == "" {
 		cfg.DBPath = defaultDBPath
+		if cfg.DBBackend == core.DBBackendSQLite {
+			cfg.DBPath = strings.TrimSuffix(defaultDBPath, filepath.Ext(defaultDBPath)) + ".sqlite"
+		}
 	}
 
 	if cfg.LogPath == "" {
@@ -348,6 +420,16 @@ func ResolveConfig(appData string, cfg *Config) error {
 	if cfg.MMConfig.EventLogDBPath == "" {
 		cfg.MMConfig.EventLogDBPath = defaultMMEventLogDBPath
 	}
+
+	if cfg.LocalesDir == "" {
+		cfg.LocalesDir = filepath.Join(appData, defaultLocalesDir)
+	} else {
+		cfg.LocalesDir = dex.CleanAndExpandPath(cfg.LocalesDir)
+	}
+
+	if cfg.CtlSocket != "" {
+		cfg.CtlSocket = dex.CleanAndExpandPath(cfg.CtlSocket)
+	}
 	return nil
 }
 
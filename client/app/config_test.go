@@ -0,0 +1,35 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package app
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/extwallet"
+)
+
+func TestResolveConfigExtWallets(t *testing.T) {
+	const xmrID = 128
+	cfg := &Config{CoreConfig: CoreConfig{ExtWallets: []string{"128:1000000000000:Monero"}}}
+	if err := ResolveConfig(t.TempDir(), cfg); err != nil {
+		t.Fatalf("ResolveConfig error: %v", err)
+	}
+	ra := asset.Asset(xmrID)
+	if ra == nil || ra.Info.Name != "Monero" {
+		t.Fatalf("external wallet asset not registered")
+	}
+	if _, err := asset.WalletDef(xmrID, extwallet.WalletTypeExternal); err != nil {
+		t.Fatalf("no external wallet definition: %v", err)
+	}
+
+	// The asset is already registered.
+	if err := ResolveConfig(t.TempDir(), cfg); err == nil {
+		t.Fatalf("no error for registering an external wallet asset twice")
+	}
+	cfg.ExtWallets = []string{"128"}
+	if err := ResolveConfig(t.TempDir(), cfg); err == nil {
+		t.Fatalf("no error for an invalid external wallet definition")
+	}
+}
@@ -184,7 +184,7 @@ var (
 		Tab:               "External",
 		Description:       "Connect to bitcoind",
 		DefaultConfigPath: dexbtc.SystemConfigPath("bitcoin"),
-		ConfigOpts:        append(RPCConfigOpts("Bitcoin", "8332"), CommonConfigOpts("BTC", false)...),
+		ConfigOpts:        append(append(RPCConfigOpts("Bitcoin", "8332"), CommonConfigOpts("BTC", false)...), trucPackagesOpt),
 		MultiFundingOpts:  MultiFundingOpts,
 	}
 	spvWalletDefinition = &asset.WalletDefinition{
@@ -415,6 +415,10 @@ type BTCCloneCFG struct {
 	// NumericGetRawRPC uses a numeric boolean indicator for the
 	// getrawtransaction RPC.
 	NumericGetRawRPC bool
+	// InstantSend causes an unmined swap transaction that is InstantSend
+	// locked to be counted as having one confirmation, since it can't be
+	// double spent. Only used by the RPC wallet. For Dash.
+	InstantSend bool
 	// TxVersion is an optional function that returns a version to use for
 	// new transactions.
 	TxVersion func() int32
@@ -451,6 +455,7 @@ type WalletConfig struct {
 	RedeemConfTarget uint64  `ini:"redeemconftarget"`
 	ActivelyUsed     bool    `ini:"special_activelyUsed"` // injected by core
 	ApiFeeFallback   bool    `ini:"apifeefallback"`
+	TRUCPackages     bool    `ini:"trucpackages"`
 }
 
 func readBaseWalletConfig(walletCfg *WalletConfig) (*baseWalletConfig, error) {
@@ -484,6 +489,7 @@ func readBaseWalletConfig(walletCfg *WalletConfig) (*baseWalletConfig, error) {
 	cfg.redeemConfTarget = walletCfg.RedeemConfTarget
 	cfg.useSplitTx = walletCfg.UseSplitTx
 	cfg.apiFeeFallback = walletCfg.ApiFeeFallback
+	cfg.trucPackages = walletCfg.TRUCPackages
 
 	return cfg, nil
 }
@@ -732,6 +738,7 @@ type baseWalletConfig struct {
 	redeemConfTarget uint64
 	useSplitTx       bool
 	apiFeeFallback   bool
+	trucPackages     bool
 }
 
 // feeRateCache wraps a ExternalFeeEstimator function and caches results.
@@ -838,6 +845,11 @@ type baseWallet struct {
 	txHistoryDB atomic.Value // *BadgerTxDB
 
 	ar *AddressRecycler
+
+	// trucPackages are the v3 packages with unmined parents, keyed by parent
+	// txid.
+	trucMtx      sync.Mutex
+	trucPackages map[chainhash.Hash]*trucPackage
 }
 
 func (w *baseWallet) fallbackFeeRate() uint64 {
@@ -903,6 +915,7 @@ var _ asset.Accelerator = (*ExchangeWalletAccelerator)(nil)
 var _ asset.Accelerator = (*ExchangeWalletSPV)(nil)
 var _ asset.Withdrawer = (*baseWallet)(nil)
 var _ asset.FeeRater = (*baseWallet)(nil)
+var _ asset.TargetFeeRater = (*baseWallet)(nil)
 var _ asset.Rescanner = (*ExchangeWalletSPV)(nil)
 var _ asset.LogFiler = (*ExchangeWalletSPV)(nil)
 var _ asset.Recoverer = (*ExchangeWalletSPV)(nil)
@@ -998,6 +1011,18 @@ func (btc *baseWallet) FeeRate() (rate uint64, tooLow bool) {
 	return rate, tooLow
 }
 
+// TargetFeeRate satisfies asset.TargetFeeRater.
+func (btc *baseWallet) TargetFeeRate(confTarget uint64) (uint64, error) {
+	rate, _, err := btc.feeRate(confTarget, btc.feeRateLimit())
+	if err != nil {
+		return 0, err
+	}
+	if rate == 0 {
+		return 0, fmt.Errorf("no fee rate available for a %d block target", confTarget)
+	}
+	return rate, nil
+}
+
 // FeeRateSwap is same as FeeRate but for swaps.
 func (btc *baseWallet) FeeRateSwap() (rate uint64, tooLow bool) {
 	rate, tooLow, err := btc.feeRate(1, 2*btc.feeRateLimit())
@@ -1215,6 +1240,7 @@ func newRPCWallet(requester RawRequester, cfg *BTCCloneCFG, parsedCfg *RPCWallet
 		hashTx:             btc.hashTx,
 		numericGetRawTxRPC: cfg.NumericGetRawRPC,
 		manualMedianTime:   cfg.ManualMedianTime,
+		instantSend:        cfg.InstantSend,
 
 		legacyValidateAddressRPC: cfg.LegacyValidateAddressRPC,
 		omitRPCOptionsArg:        cfg.OmitRPCOptionsArg,
@@ -3747,6 +3773,16 @@ func (btc *baseWallet) addTxToHistory(wt *asset.WalletTransaction, txHash *chain
 // are NOT manually unlocked because they're auto-unlocked when the transaction
 // is broadcasted.
 func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, uint64, error) {
+	receipts, changeCoin, fees, err := btc.swap(swaps, !swaps.LockChange && btc.trucPackagesEnabled())
+	if errors.Is(err, errPackageFailed) {
+		btc.log.Warnf("Sending a standard swap transaction: %v", err)
+		return btc.swap(swaps, false)
+	}
+	return receipts, changeCoin, fees, err
+}
+
+// swap sends the swaps, as a v3 package if allowed and possible.
+func (btc *baseWallet) swap(swaps *asset.Swaps, allowTRUC bool) ([]asset.Receipt, asset.Coin, uint64, error) {
 	if swaps.FeeRate == 0 {
 		return nil, nil, 0, fmt.Errorf("cannot send swap with with zero fee rate")
 	}
@@ -3833,11 +3869,28 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 		btc.log.Errorf("ignoring invalid fee bump factor, %s: %v", float64PtrStr(customCfg.FeeBump), err)
 	}
 
+	// Swaps with change that is not chained into further swaps can be sent
+	// as a v3 package, whose fees can be bumped.
+	var pkg *trucPackage
+	if allowTRUC {
+		pkg, err = btc.trucSwapPackage(baseTx.Copy(), changeAddr, totalIn, totalOut, feeRate)
+		if err != nil {
+			btc.log.Warnf("Unable to create swap package. Sending a standard swap transaction: %v", err)
+		}
+	}
+
 	// Sign, add change, but don't send the transaction yet until
 	// the individual swap refund txs are prepared and signed.
-	msgTx, change, fees, err := btc.signTxAndAddChange(baseTx, changeAddr, totalIn, totalOut, feeRate)
-	if err != nil {
-		return nil, nil, 0, err
+	var msgTx *wire.MsgTx
+	var change *Output
+	var fees uint64
+	if pkg != nil {
+		msgTx, change, fees = pkg.parent, pkg.childOutput(btc), pkg.fees
+	} else {
+		msgTx, change, fees, err = btc.signTxAndAddChange(baseTx, changeAddr, totalIn, totalOut, feeRate)
+		if err != nil {
+			return nil, nil, 0, err
+		}
 	}
 	txHash := btc.hashTx(msgTx)
 
@@ -3865,7 +3918,13 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 	}
 
 	// Refund txs prepared and signed. Can now broadcast the swap(s).
-	_, err = btc.broadcastTx(msgTx)
+	if pkg != nil {
+		if err = btc.submitTRUCPackage(pkg); err != nil {
+			err = fmt.Errorf("%w: %v", errPackageFailed, err)
+		}
+	} else {
+		_, err = btc.broadcastTx(msgTx)
+	}
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -3917,6 +3976,17 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 
 // Redeem sends the redemption transaction, completing the atomic swap.
 func (btc *baseWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error) {
+	coinIDs, out, fees, err := btc.redeem(form, btc.trucPackagesEnabled())
+	if errors.Is(err, errPackageFailed) {
+		btc.log.Warnf("Sending a standard redeem transaction: %v", err)
+		return btc.redeem(form, false)
+	}
+	return coinIDs, out, fees, err
+}
+
+// redeem sends the redemption transaction, as a v3 package if allowed and
+// possible.
+func (btc *baseWallet) redeem(form *asset.RedeemForm, allowTRUC bool) ([]dex.Bytes, asset.Coin, uint64, error) {
 	// Create a transaction that spends the referenced contract.
 	msgTx := wire.NewMsgTx(btc.txVersion())
 	var totalIn uint64
@@ -4006,6 +4076,16 @@ func (btc *baseWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin,
 	}
 	msgTx.AddTxOut(txOut)
 
+	// A v3 package redemption pays no fee in the redeem transaction. The fees
+	// are paid by a child transaction that spends the redeem output.
+	_, isP2WPKH := redeemAddr.(*btcutil.AddressWitnessPubKeyHash)
+	useTRUC := allowTRUC && isP2WPKH
+	var anchorIdx uint32
+	if useTRUC {
+		txOut.Value = int64(totalIn)
+		anchorIdx = addAnchor(msgTx)
+	}
+
 	if btc.segwit {
 		// NewTxSigHashes uses the PrevOutFetcher only for detecting a taproot
 		// output, so we can provide a dummy that always returns a wire.TxOut
@@ -4034,7 +4114,12 @@ func (btc *baseWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin,
 	}
 
 	// Send the transaction.
-	txHash, err := btc.broadcastTx(msgTx)
+	var txHash *chainhash.Hash
+	if useTRUC {
+		txHash, fee, err = btc.sendTRUCRedeem(msgTx, anchorIdx, redeemAddr, feeRate)
+	} else {
+		txHash, err = btc.broadcastTx(msgTx)
+	}
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -4773,6 +4858,7 @@ func (btc *intermediaryWallet) reportNewTip(ctx context.Context, newTip *BlockVe
 	btc.emit.TipChange(uint64(newTip.Height))
 
 	go btc.syncTxHistory(uint64(newTip.Height))
+	go btc.bumpTRUCPackages()
 
 	btc.rf.ReportNewTip(ctx, prevTip, newTip)
 }
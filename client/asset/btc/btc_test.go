@@ -136,6 +136,8 @@ type testData struct {
 	badSendHash   *chainhash.Hash
 	sendErr       error
 	sentRawTx     *wire.MsgTx
+	sentPackage   []*wire.MsgTx
+	packageErr    error
 	txOutRes      *btcjson.GetTxOutResult
 	txOutErr      error
 	sigIncomplete bool
@@ -151,6 +153,7 @@ type testData struct {
 
 	mempoolTxs        map[chainhash.Hash]*wire.MsgTx
 	rawVerboseErr     error
+	instantLocked     bool // for verbose getrawtransaction
 	lockedCoins       []*RPCOutpoint
 	estFeeErr         error
 	listLockUnspent   []*RPCOutpoint
@@ -295,6 +298,23 @@ func (c *tRawRequester) RawRequest(_ context.Context, method string, params []js
 			return nil, c.sendErr
 		}
 		return json.Marshal(c.badSendHash.String())
+	case methodSubmitPackage:
+		if c.packageErr != nil {
+			return nil, c.packageErr
+		}
+		var txHexes []string
+		if err := json.Unmarshal(params[0], &txHexes); err != nil {
+			return nil, err
+		}
+		c.sentPackage = nil
+		for _, txHex := range txHexes {
+			tx, err := msgTxFromHex(txHex)
+			if err != nil {
+				return nil, err
+			}
+			c.sentPackage = append(c.sentPackage, tx)
+		}
+		return json.Marshal(map[string]any{"package_msg": "success"})
 	case methodGetTxOut:
 		return encodeOrError(c.txOutRes, c.txOutErr)
 	case methodGetBestBlockHash:
@@ -339,6 +359,16 @@ func (c *tRawRequester) RawRequest(_ context.Context, method string, params []js
 		if err != nil {
 			return nil, err
 		}
+		var verbose bool
+		if len(params) > 1 {
+			json.Unmarshal(params[1], &verbose) // numeric arg leaves false
+		}
+		if verbose {
+			return json.Marshal(map[string]any{
+				"txid":        hashStr,
+				"instantlock": c.instantLocked,
+			})
+		}
 		msgTx := c.mempoolTxs[*txHash]
 		if msgTx == nil {
 			return nil, fmt.Errorf("transaction not found")
@@ -4174,6 +4204,50 @@ func testConfirmations(t *testing.T, segwit bool, walletType string) {
 	}
 }
 
+func TestInstantSendConfirmations(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(false, walletTypeRPC)
+	defer shutdown()
+
+	var txHash chainhash.Hash
+	copy(txHash[:], randBytes(32))
+	coinID := ToCoinID(&txHash, 0)
+	_, _, _, contract, _, _, _ := makeSwapContract(false, time.Hour*12)
+	node.txOutRes = &btcjson.GetTxOutResult{Confirmations: 0}
+	node.instantLocked = true
+
+	checkConfs := func(tag string, expConfs uint32) {
+		t.Helper()
+		confs, spent, err := wallet.SwapConfirmations(context.Background(), coinID, contract, time.Now())
+		if err != nil {
+			t.Fatalf("%s: SwapConfirmations error: %v", tag, err)
+		}
+		if spent {
+			t.Fatalf("%s: swap reported spent", tag)
+		}
+		if confs != expConfs {
+			t.Fatalf("%s: expected %d confirmations, got %d", tag, expConfs, confs)
+		}
+	}
+
+	// Locks are ignored unless the wallet is InstantSend-aware.
+	checkConfs("not aware", 0)
+
+	wallet.node.(*rpcClient).instantSend = true
+	checkConfs("locked", 1)
+
+	node.instantLocked = false
+	checkConfs("unlocked", 0)
+
+	// A lookup error is not fatal.
+	node.instantLocked = true
+	node.rawVerboseErr = tErr
+	checkConfs("lookup error", 0)
+	node.rawVerboseErr = nil
+
+	node.txOutRes = &btcjson.GetTxOutResult{Confirmations: 3}
+	checkConfs("mined", 3)
+}
+
 func TestSendEdges(t *testing.T) {
 	runRubric(t, testSendEdges)
 }
@@ -6513,3 +6587,149 @@ func TestFeeRateCache(t *testing.T) {
 		t.Fatal("counter not incremented for recovered rate")
 	}
 }
+
+func TestTRUCPackages(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	node.walletCfg.trucPackages = true
+	wallet.node.(*rpcClient).packageRelay = true
+
+	privBytes, _ := hex.DecodeString("b07209eec1a8fb6cfe5cb6ace36567406971a75c330db7101fb21bc679bc5330")
+	privKey, _ := btcec.PrivKeyFromBytes(privBytes)
+	wif, _ := btcutil.NewWIF(privKey, &chaincfg.MainNetParams, true)
+	node.changeAddr = tP2WPKHAddr
+	node.newAddress = tP2WPKHAddr
+	node.privKeyForAddr = wif
+	node.signFunc = func(tx *wire.MsgTx) {
+		signFunc(tx, 0, true)
+	}
+
+	checkPackage := func(pkg []*wire.MsgTx, fees uint64, feeRate uint64) {
+		t.Helper()
+		if len(pkg) != 2 {
+			t.Fatalf("expected 2 transactions in package, got %d", len(pkg))
+		}
+		parent, child := pkg[0], pkg[1]
+		if parent.Version != trucTxVersion || child.Version != trucTxVersion {
+			t.Fatalf("wrong versions %d, %d", parent.Version, child.Version)
+		}
+		parentHash := parent.TxHash()
+		if len(child.TxIn) != 2 || child.TxIn[1].PreviousOutPoint.Hash != parentHash {
+			t.Fatalf("child does not spend the anchor")
+		}
+		anchor := parent.TxOut[child.TxIn[1].PreviousOutPoint.Index]
+		if anchor.Value != 0 || !bytes.Equal(anchor.PkScript, anchorScript) {
+			t.Fatalf("child does not spend an anchor output")
+		}
+		fundIdx := child.TxIn[0].PreviousOutPoint.Index
+		if child.TxIn[0].PreviousOutPoint.Hash != parentHash {
+			t.Fatalf("child does not spend a parent output")
+		}
+		if childFees := uint64(parent.TxOut[fundIdx].Value - child.TxOut[0].Value); childFees != fees {
+			t.Fatalf("reported fees %d, child paid %d", fees, childFees)
+		}
+		minFees := feeRate * (dexbtc.MsgTxVBytes(parent) + dexbtc.MsgTxVBytes(child))
+		if fees < minFees {
+			t.Fatalf("package fees %d less than minimum %d", fees, minFees)
+		}
+	}
+
+	// Redeem
+	secret, _, _, contract, addr, _, lockTime := makeSwapContract(true, time.Hour*12)
+	redemptions := &asset.RedeemForm{
+		Redemptions: []*asset.Redemption{{
+			Spends: &asset.AuditInfo{
+				Coin:       NewOutput(tTxHash, 0, toSatoshi(1)),
+				Contract:   contract,
+				Recipient:  addr.String(),
+				Expiration: lockTime,
+			},
+			Secret: secret,
+		}},
+	}
+	coinIDs, _, fees, err := wallet.Redeem(redemptions)
+	if err != nil {
+		t.Fatalf("redeem error: %v", err)
+	}
+	checkPackage(node.sentPackage, fees, optimalFeeRate)
+	parentHash := node.sentPackage[0].TxHash()
+	if txHash, _, _ := decodeCoinID(coinIDs[0]); txHash == nil || *txHash != parentHash {
+		t.Fatalf("wrong redeem coin ID")
+	}
+	if len(wallet.trucPackages) != 1 || wallet.trucPackages[parentHash] == nil {
+		t.Fatalf("redeem package not tracked")
+	}
+
+	// A package error falls back to a standard transaction.
+	node.sentRawTx = nil
+	node.packageErr = tErr
+	if _, _, _, err = wallet.Redeem(redemptions); err != nil {
+		t.Fatalf("redeem fallback error: %v", err)
+	}
+	if node.sentRawTx == nil || node.sentRawTx.Version == trucTxVersion {
+		t.Fatalf("standard redeem not sent after package error")
+	}
+	node.packageErr = nil
+
+	// Swap
+	swaps := &asset.Swaps{
+		Inputs: asset.Coins{NewOutput(tTxHash, 0, toSatoshi(3))},
+		Contracts: []*asset.Contract{{
+			Address:    tP2WPKHAddr,
+			Value:      toSatoshi(1),
+			SecretHash: randBytes(32),
+			LockTime:   uint64(time.Now().Unix()),
+		}},
+		LockChange: true,
+		FeeRate:    tBTC.MaxFeeRate,
+	}
+	// Swaps with locked change are not sent as packages.
+	node.sentPackage = nil
+	if _, _, _, err = wallet.Swap(swaps); err != nil {
+		t.Fatalf("swap error: %v", err)
+	}
+	if node.sentPackage != nil {
+		t.Fatalf("package sent for swap with locked change")
+	}
+	swaps.LockChange = false
+	_, changeCoin, fees, err := wallet.Swap(swaps)
+	if err != nil {
+		t.Fatalf("swap error: %v", err)
+	}
+	checkPackage(node.sentPackage, fees, tBTC.MaxFeeRate)
+	if txHash, _, _ := decodeCoinID(changeCoin.ID()); *txHash != node.sentPackage[1].TxHash() {
+		t.Fatalf("change coin is not the child output")
+	}
+
+	// Fee bumping. The child is replaced once the fee rate rises enough.
+	pkg := wallet.trucPackages[parentHash]
+	node.getTransactionMap[parentHash.String()] = &GetTransactionResult{}
+	if err := wallet.makeTRUCChild(pkg, optimalFeeRate-1); err != nil {
+		t.Fatalf("makeTRUCChild error: %v", err)
+	}
+	node.sentRawTx = nil
+	wallet.bumpTRUCPackages()
+	if node.sentRawTx != nil {
+		t.Fatalf("child replaced for a small fee rate increase")
+	}
+	if err := wallet.makeTRUCChild(pkg, optimalFeeRate/2); err != nil {
+		t.Fatalf("makeTRUCChild error: %v", err)
+	}
+	oldChild := pkg.child
+	wallet.bumpTRUCPackages()
+	if node.sentRawTx == nil || node.sentRawTx.TxHash() == oldChild.TxHash() {
+		t.Fatalf("child not replaced")
+	}
+	if pkg.feeRate != optimalFeeRate {
+		t.Fatalf("wrong bumped fee rate %d", pkg.feeRate)
+	}
+	checkPackage([]*wire.MsgTx{pkg.parent, node.sentRawTx}, pkg.fees, optimalFeeRate)
+
+	// Mined packages are no longer tracked.
+	node.getTransactionMap[parentHash.String()] = &GetTransactionResult{Confirmations: 1}
+	wallet.bumpTRUCPackages()
+	if wallet.trucPackages[parentHash] != nil {
+		t.Fatalf("mined package still tracked")
+	}
+}
@@ -61,6 +61,7 @@ const (
 	methodGetBlockchainInfo  = "getblockchaininfo"
 	methodFundRawTransaction = "fundrawtransaction"
 	methodListSinceBlock     = "listsinceblock"
+	methodSubmitPackage      = "submitpackage"
 )
 
 // IsTxNotFoundErr will return true if the error indicates that the requested
@@ -118,6 +119,7 @@ type rpcCore struct {
 	hashTx             func(*wire.MsgTx) *chainhash.Hash
 	numericGetRawTxRPC bool
 	manualMedianTime   bool
+	instantSend        bool
 	addrFunc           func() (btcutil.Address, error)
 
 	deserializeBlock         func([]byte) (*wire.MsgBlock, error)
@@ -136,9 +138,12 @@ type rpcClient struct {
 	*rpcCore
 	ctx         context.Context
 	descriptors bool // set on connect like ctx
+	// packageRelay is set on connect if the node can relay v3 packages.
+	packageRelay bool
 }
 
 var _ Wallet = (*rpcClient)(nil)
+var _ packageRelayer = (*rpcClient)(nil)
 
 // newRPCClient is the constructor for a rpcClient.
 func newRPCClient(cfg *rpcCore) *rpcClient {
@@ -193,6 +198,7 @@ func (wc *rpcClient) connect(ctx context.Context, _ *sync.WaitGroup) error {
 		}
 		wc.log.Debug("Using a descriptor wallet.")
 	}
+	wc.packageRelay = netVer >= minPackageRelayVersion
 	return nil
 }
 
@@ -302,6 +308,58 @@ func (wc *rpcClient) SendRawTransaction(tx *wire.MsgTx) (*chainhash.Hash, error)
 	return chainhash.NewHashFromStr(txid)
 }
 
+// packageRelaySupported is true if the node can relay v3 packages with
+// zero-fee parents. Part of the packageRelayer interface.
+func (wc *rpcClient) packageRelaySupported() bool {
+	return wc.packageRelay
+}
+
+// submitPackage submits the transactions, parents first, as a package. Part of
+// the packageRelayer interface.
+func (wc *rpcClient) submitPackage(txs []*wire.MsgTx) error {
+	rawTxs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		b, err := wc.serializeTx(tx)
+		if err != nil {
+			return fmt.Errorf("tx serialization error: %w", err)
+		}
+		rawTxs = append(rawTxs, hex.EncodeToString(b))
+	}
+	var res struct {
+		PackageMsg string `json:"package_msg"`
+		TxResults  map[string]struct {
+			TxID  string `json:"txid"`
+			Error string `json:"error"`
+		} `json:"tx-results"`
+	}
+	if err := wc.call(methodSubmitPackage, anylist{rawTxs}, &res); err != nil {
+		return err
+	}
+	if res.PackageMsg != "success" {
+		errs := []string{res.PackageMsg}
+		for _, r := range res.TxResults {
+			if r.Error != "" {
+				errs = append(errs, fmt.Sprintf("%s: %s", r.TxID, r.Error))
+			}
+		}
+		return errors.New(strings.Join(errs, "; "))
+	}
+	if !wc.unlockSpends {
+		return nil
+	}
+	var ops []*Output
+	for _, tx := range txs {
+		for _, txIn := range tx.TxIn {
+			prevOut := &txIn.PreviousOutPoint
+			ops = append(ops, &Output{Pt: NewOutPoint(&prevOut.Hash, prevOut.Index)})
+		}
+	}
+	if err := wc.lockUnspent(true, ops); err != nil {
+		wc.log.Warnf("error unlocking spent outputs: %v", err)
+	}
+	return nil
+}
+
 // sendRawTransaction sends the MsgTx.
 func (wc *rpcClient) sendRawTransaction(tx *wire.MsgTx) (txHash *chainhash.Hash, err error) {
 	if wc.legacyRawSends {
@@ -1001,6 +1059,14 @@ func (wc *rpcClient) swapConfirmations(txHash *chainhash.Hash, vout uint32, _ []
 	// Check for an unspent output.
 	txOut, err := wc.getTxOutput(txHash, vout)
 	if err == nil && txOut != nil {
+		if txOut.Confirmations == 0 && wc.instantSend {
+			locked, err := wc.instantLocked(txHash)
+			if err != nil {
+				wc.log.Errorf("Error checking InstantSend lock for %s: %v", txHash, err)
+			} else if locked {
+				return 1, false, nil
+			}
+		}
 		return uint32(txOut.Confirmations), false, nil
 	}
 	// Check wallet transactions.
@@ -1014,6 +1080,21 @@ func (wc *rpcClient) swapConfirmations(txHash *chainhash.Hash, vout uint32, _ []
 	return uint32(tx.Confirmations), true, nil
 }
 
+// instantLocked checks whether the transaction is InstantSend locked.
+func (wc *rpcClient) instantLocked(txHash *chainhash.Hash) (bool, error) {
+	var tx struct {
+		InstantLock bool `json:"instantlock"`
+	}
+	args := anylist{txHash.String(), true}
+	if wc.numericGetRawTxRPC {
+		args[1] = 1
+	}
+	if err := wc.call(methodGetRawTransaction, args, &tx); err != nil {
+		return false, err
+	}
+	return tx.InstantLock, nil
+}
+
 // getBlockHeader gets the *rpcBlockHeader for the specified block hash.
 func (wc *rpcClient) getRPCBlockHeader(blockHash *chainhash.Hash) (*BlockHeader, error) {
 	blkHeader := new(BlockHeader)
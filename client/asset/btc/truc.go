@@ -0,0 +1,278 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Swap and redeem transactions can be sent as v3 (TRUC, topologically
+// restricted until confirmation) packages. The parent transaction pays no fee
+// and has a zero-value pay-to-anchor (P2A) output. A child transaction spends
+// the anchor and one of the parent's wallet outputs and pays the fee for both.
+// Because the parent txid does not depend on the fees, the child can be
+// replaced at a higher fee rate when fees spike without changing the swap or
+// redeem coin IDs that were reported to the server and the counterparty.
+
+const (
+	trucTxVersion = 3
+	// minPackageRelayVersion is the bitcoind version that relays 1-parent-1-child
+	// TRUC packages with a zero-fee parent and ephemeral dust anchor outputs.
+	minPackageRelayVersion = 290000
+	// trucBumpThreshold is how much the fee rate must rise above a package's
+	// fee rate before its child transaction is replaced.
+	trucBumpThreshold = 1.25
+
+	trucPackagesKey = "trucpackages"
+)
+
+// anchorScript is the pay-to-anchor output script, OP_1 <0x4e73>.
+var anchorScript = []byte{txscript.OP_1, txscript.OP_DATA_2, 0x4e, 0x73}
+
+var trucPackagesOpt = &asset.ConfigOption{
+	Key:         trucPackagesKey,
+	DisplayName: "Fee-bumpable swaps (v3 packages)",
+	Description: "Send swap and redeem transactions as v3 (TRUC) packages " +
+		"with an anchor output, so that their fees can be raised if network " +
+		"fees spike before they are mined. Requires Bitcoin Core 29.0 or newer. " +
+		"Swaps that lock their change for further matches are sent normally.",
+	IsBoolean:    true,
+	DefaultValue: false,
+}
+
+// packageRelayer is satisfied by a Wallet that can submit transaction
+// packages.
+type packageRelayer interface {
+	packageRelaySupported() bool
+	submitPackage(txs []*wire.MsgTx) error
+}
+
+// trucPackage is a zero-fee v3 parent transaction and the child transaction
+// that pays its fees.
+type trucPackage struct {
+	parent     *wire.MsgTx
+	parentHash chainhash.Hash
+	parentSize uint64
+	anchorIdx  uint32
+	// fundIdx is the index of the parent's wallet output that is spent by the
+	// child.
+	fundIdx   uint32
+	fundValue uint64
+	fundAddr  btcutil.Address
+	// childAddr receives the child's output.
+	childAddr btcutil.Address
+
+	child   *wire.MsgTx
+	feeRate uint64
+	fees    uint64
+}
+
+func (pkg *trucPackage) childOutput(btc *baseWallet) *Output {
+	return NewOutput(btc.hashTx(pkg.child), 0, uint64(pkg.child.TxOut[0].Value))
+}
+
+func (btc *baseWallet) trucPackagesEnabled() bool {
+	if !btc.cfgV.Load().(*baseWalletConfig).trucPackages || !btc.segwit {
+		return false
+	}
+	pr, is := btc.node.(packageRelayer)
+	return is && pr.packageRelaySupported()
+}
+
+// addAnchor converts the unsigned transaction to a v3 transaction with an
+// anchor output, returning the anchor's output index.
+func addAnchor(tx *wire.MsgTx) uint32 {
+	tx.Version = trucTxVersion
+	tx.AddTxOut(wire.NewTxOut(0, anchorScript))
+	return uint32(len(tx.TxOut) - 1)
+}
+
+// newTRUCPackage creates a package for the signed parent, and creates the
+// child transaction at the fee rate. The fund output must pay to a P2WPKH
+// address of the wallet.
+func (btc *baseWallet) newTRUCPackage(parent *wire.MsgTx, anchorIdx, fundIdx uint32, fundAddr btcutil.Address, feeRate uint64) (*trucPackage, error) {
+	if _, is := fundAddr.(*btcutil.AddressWitnessPubKeyHash); !is {
+		return nil, fmt.Errorf("package fees cannot be paid from a %T", fundAddr)
+	}
+	childAddr, err := btc.node.changeAddress()
+	if err != nil {
+		return nil, fmt.Errorf("error creating change address: %w", err)
+	}
+	pkg := &trucPackage{
+		parent:     parent,
+		parentHash: *btc.hashTx(parent),
+		parentSize: btc.calcTxSize(parent),
+		anchorIdx:  anchorIdx,
+		fundIdx:    fundIdx,
+		fundValue:  uint64(parent.TxOut[fundIdx].Value),
+		fundAddr:   fundAddr,
+		childAddr:  childAddr,
+	}
+	if err := btc.makeTRUCChild(pkg, feeRate); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// makeTRUCChild creates and signs a child transaction that pays the fees for
+// the package at the fee rate.
+func (btc *baseWallet) makeTRUCChild(pkg *trucPackage, feeRate uint64) error {
+	pkScript, err := txscript.PayToAddrScript(pkg.childAddr)
+	if err != nil {
+		return fmt.Errorf("error creating child output script: %w", err)
+	}
+	child := wire.NewMsgTx(trucTxVersion)
+	child.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&pkg.parentHash, pkg.fundIdx), nil, nil))
+	child.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&pkg.parentHash, pkg.anchorIdx), nil, nil))
+	txOut := wire.NewTxOut(int64(pkg.fundValue), pkScript)
+	child.AddTxOut(txOut)
+
+	// Sign once to get the size, then again with the fees deducted.
+	if err := btc.signTRUCChild(child, pkg); err != nil {
+		return err
+	}
+	fees := feeRate * (pkg.parentSize + btc.calcTxSize(child))
+	if fees >= pkg.fundValue {
+		return fmt.Errorf("package fees %d exceed the value of the funding output %d", fees, pkg.fundValue)
+	}
+	txOut.Value = int64(pkg.fundValue - fees)
+	if btc.IsDust(txOut, feeRate) {
+		return fmt.Errorf("package child output is dust")
+	}
+	if err := btc.signTRUCChild(child, pkg); err != nil {
+		return err
+	}
+	pkg.child = child
+	pkg.feeRate = feeRate
+	pkg.fees = fees
+	return nil
+}
+
+// signTRUCChild signs the child's fund input. The anchor input needs no
+// signature.
+func (btc *baseWallet) signTRUCChild(child *wire.MsgTx, pkg *trucPackage) error {
+	addrStr, err := btc.stringAddr(pkg.fundAddr, btc.chainParams)
+	if err != nil {
+		return err
+	}
+	privKey, err := btc.node.privKeyForAddress(addrStr)
+	if err != nil {
+		return fmt.Errorf("error retrieving key for package fee input: %w", err)
+	}
+	defer privKey.Zero()
+	sigHashes := txscript.NewTxSigHashes(child, new(txscript.CannedPrevOutputFetcher))
+	child.TxIn[0].Witness, err = txscript.WitnessSignature(child, sigHashes, 0, int64(pkg.fundValue),
+		pkg.parent.TxOut[pkg.fundIdx].PkScript, txscript.SigHashAll, privKey, true)
+	if err != nil {
+		return fmt.Errorf("error signing package fee input: %w", err)
+	}
+	return nil
+}
+
+// submitTRUCPackage submits the parent and child as a package, and tracks the
+// package for fee bumping until the parent is mined.
+func (btc *baseWallet) submitTRUCPackage(pkg *trucPackage) error {
+	pr, is := btc.node.(packageRelayer)
+	if !is {
+		return errors.New("wallet cannot submit packages")
+	}
+	if err := pr.submitPackage([]*wire.MsgTx{pkg.parent, pkg.child}); err != nil {
+		return fmt.Errorf("submitpackage error: %w, parent: %x, child: %x",
+			err, btc.wireBytes(pkg.parent), btc.wireBytes(pkg.child))
+	}
+	btc.trucMtx.Lock()
+	if btc.trucPackages == nil {
+		btc.trucPackages = make(map[chainhash.Hash]*trucPackage)
+	}
+	btc.trucPackages[pkg.parentHash] = pkg
+	btc.trucMtx.Unlock()
+	btc.log.Debugf("Sent package with parent %s and child %s at %d sats/vB (fees = %d)",
+		pkg.parentHash, btc.hashTx(pkg.child), pkg.feeRate, pkg.fees)
+	return nil
+}
+
+// bumpTRUCPackages replaces the child transactions of unmined packages if the
+// fee rate has risen significantly since they were sent. Packages with mined
+// parents are no longer tracked.
+func (btc *baseWallet) bumpTRUCPackages() {
+	btc.trucMtx.Lock()
+	defer btc.trucMtx.Unlock()
+	if len(btc.trucPackages) == 0 {
+		return
+	}
+	feeRate, _, err := btc.feeRate(1, btc.feeRateLimit())
+	if err != nil || feeRate == 0 {
+		btc.log.Warnf("Unable to get a fee rate to check package fees: %v", err)
+		return
+	}
+	for parentHash, pkg := range btc.trucPackages {
+		tx, err := btc.node.getWalletTransaction(&parentHash)
+		if err != nil {
+			btc.log.Errorf("Error getting package parent transaction %s: %v", parentHash, err)
+			continue
+		}
+		if tx.Confirmations > 0 {
+			delete(btc.trucPackages, parentHash)
+			continue
+		}
+		if float64(feeRate) < float64(pkg.feeRate)*trucBumpThreshold || feeRate <= pkg.feeRate {
+			continue
+		}
+		old := *pkg
+		if err := btc.makeTRUCChild(pkg, feeRate); err != nil {
+			btc.log.Errorf("Error creating replacement child for package with parent %s: %v", parentHash, err)
+			continue
+		}
+		if _, err := btc.broadcastTx(pkg.child); err != nil {
+			btc.log.Errorf("Error sending replacement child for package with parent %s: %v", parentHash, err)
+			*pkg = old
+			continue
+		}
+		btc.log.Infof("Raised fee rate of package with parent %s from %d to %d sats/vB with child %s",
+			parentHash, old.feeRate, feeRate, btc.hashTx(pkg.child))
+	}
+}
+
+// errPackageFailed is returned when a package could not be sent. Nothing has
+// been broadcast, and the transactions can be sent without a package.
+var errPackageFailed = errors.New("package not sent")
+
+// trucSwapPackage creates a package for the unsigned swap transaction. The
+// parent's change output funds the child.
+func (btc *baseWallet) trucSwapPackage(baseTx *wire.MsgTx, changeAddr btcutil.Address,
+	totalIn, totalOut, feeRate uint64) (*trucPackage, error) {
+
+	anchorIdx := addAnchor(baseTx)
+	parent, change, _, err := btc.signTxAndAddChange(baseTx, changeAddr, totalIn, totalOut, 0)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		return nil, errors.New("no change output to pay package fees")
+	}
+	return btc.newTRUCPackage(parent, anchorIdx, change.vout(), changeAddr, feeRate)
+}
+
+// sendTRUCRedeem creates and submits a package for the signed, zero-fee redeem
+// transaction. The redeem output funds the child. The fees paid by the child
+// are returned.
+func (btc *baseWallet) sendTRUCRedeem(msgTx *wire.MsgTx, anchorIdx uint32, redeemAddr btcutil.Address,
+	feeRate uint64) (*chainhash.Hash, uint64, error) {
+
+	pkg, err := btc.newTRUCPackage(msgTx, anchorIdx, 0, redeemAddr, feeRate)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", errPackageFailed, err)
+	}
+	if err := btc.submitTRUCPackage(pkg); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", errPackageFailed, err)
+	}
+	return &pkg.parentHash, pkg.fees, nil
+}
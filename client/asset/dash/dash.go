@@ -161,6 +161,7 @@ func newWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 		SingularWallet:           false, // wallet can have "" as a path but also a name like "gamma"
 		UnlockSpends:             false,
 		ConstantDustLimit:        0,
+		InstantSend:              true, // InstantSend locked swaps count as confirmed
 		AssetID:                  BipID,
 	}
 
@@ -44,10 +44,13 @@ type rpcConfig struct {
 	PrimaryAccount string `ini:"account"`
 	UnmixedAccount string `ini:"unmixedaccount"`
 	TradingAccount string `ini:"tradingaccount"`
-	RPCUser        string `ini:"username"`
-	RPCPass        string `ini:"password"`
-	RPCListen      string `ini:"rpclisten"`
-	RPCCert        string `ini:"rpccert"`
+	// ChangeHoldAccount is an optional unmixed account that holds trade change
+	// rather than sending it to the UnmixedAccount for mixing.
+	ChangeHoldAccount string `ini:"changeholdaccount"`
+	RPCUser           string `ini:"username"`
+	RPCPass           string `ini:"password"`
+	RPCListen         string `ini:"rpclisten"`
+	RPCCert           string `ini:"rpccert"`
 }
 
 func loadRPCConfig(settings map[string]string, network dex.Network) (*rpcConfig, *chaincfg.Params, error) {
@@ -100,6 +103,16 @@ func loadRPCConfig(settings map[string]string, network dex.Network) (*rpcConfig,
 			return nil, nil, fmt.Errorf("Temporary Trading Account should not be the same as Change Account")
 		}
 	}
+	if cfg.ChangeHoldAccount != "" {
+		switch cfg.ChangeHoldAccount {
+		case cfg.PrimaryAccount, cfg.UnmixedAccount, cfg.TradingAccount:
+			return nil, nil, fmt.Errorf("Change Holding Account should not be the same as any other account")
+		}
+		if cfg.UnmixedAccount == "" {
+			return nil, nil, fmt.Errorf("Change Holding Account should only be set if %q is a mixed account",
+				cfg.PrimaryAccount)
+		}
+	}
 
 	return cfg, chainParams, nil
 }
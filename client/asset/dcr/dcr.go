@@ -201,6 +201,13 @@ var (
 			Description: "dcrwallet account to temporarily store split tx outputs or change from chained swaps in " +
 				"multi-lot orders. This should only be set if 'Change Account Name' is set.",
 		},
+		{
+			Key:         "changeholdaccount",
+			DisplayName: "Change Holding Account",
+			Description: "Optional dcrwallet account to hold the change from trades instead of sending it to the " +
+				"'Change Account Name' account to be mixed. Funds in this account are not used for trading. This " +
+				"should only be set if 'Change Account Name' is set.",
+		},
 		{
 			Key:         "username",
 			DisplayName: "RPC Username",
@@ -615,6 +622,7 @@ type vsp struct {
 
 // rescanProgress is the progress of an asynchronous rescan.
 type rescanProgress struct {
+	startHeight    int64
 	scannedThrough int64
 }
 
@@ -700,6 +708,7 @@ func (dcr *ExchangeWallet) config() *exchangeWalletConfig {
 // Check that ExchangeWallet satisfies the Wallet interface.
 var _ asset.Wallet = (*ExchangeWallet)(nil)
 var _ asset.FeeRater = (*ExchangeWallet)(nil)
+var _ asset.TargetFeeRater = (*ExchangeWallet)(nil)
 var _ asset.Withdrawer = (*ExchangeWallet)(nil)
 var _ asset.LiveReconfigurer = (*ExchangeWallet)(nil)
 var _ asset.TxFeeEstimator = (*ExchangeWallet)(nil)
@@ -1133,6 +1142,17 @@ func (dcr *ExchangeWallet) depositAccount() string {
 	return accts.PrimaryAccount
 }
 
+// tradeChangeAccount returns the account that receives the change from order
+// funding and swap transactions. For mixed accounts, change is sent to the
+// unmixed account to be mixed, or to the change holding account if one is
+// configured, so that identifiable change is never used to fund orders.
+func (dcr *ExchangeWallet) tradeChangeAccount() string {
+	if acct := dcr.wallet.Accounts().ChangeHoldAccount; acct != "" {
+		return acct
+	}
+	return dcr.depositAccount()
+}
+
 // fundingAccounts returns the primary account along with any configured trading
 // account which may contain spendable outputs (split tx outputs or chained swap
 // change).
@@ -1149,6 +1169,9 @@ func (dcr *ExchangeWallet) allAccounts() []string {
 	if accts.UnmixedAccount == "" {
 		return []string{accts.PrimaryAccount}
 	}
+	if accts.ChangeHoldAccount != "" {
+		return []string{accts.PrimaryAccount, accts.TradingAccount, accts.UnmixedAccount, accts.ChangeHoldAccount}
+	}
 	return []string{accts.PrimaryAccount, accts.TradingAccount, accts.UnmixedAccount}
 }
 
@@ -1206,12 +1229,22 @@ func (dcr *ExchangeWallet) balance() (*asset.Balance, error) {
 		return nil, err
 	}
 
+	unmixed := toAtoms(unmixedAcctBal.Total)
+	if accts.ChangeHoldAccount != "" {
+		// Held change is unmixed and not available for trading.
+		holdAcctBal, err := dcr.wallet.AccountBalance(dcr.ctx, 0, accts.ChangeHoldAccount)
+		if err != nil {
+			return nil, err
+		}
+		unmixed += toAtoms(holdAcctBal.Total)
+	}
+
 	bal.Available += toAtoms(tradingAcctBal.Spendable) - tradingAcctLocked
-	bal.Immature += toAtoms(unmixedAcctBal.Total)
+	bal.Immature += unmixed
 	bal.Locked += tradingAcctLocked
 
 	bal.Other[asset.BalanceCategoryUnmixed] = asset.CustomBalance{
-		Amount: toAtoms(unmixedAcctBal.Total),
+		Amount: unmixed,
 	}
 
 	return bal, nil
@@ -1274,6 +1307,11 @@ func (dcr *ExchangeWallet) FeeRate() (rate uint64, tooLow bool) {
 	return rate, false // DCR fees are never too low in practice
 }
 
+// TargetFeeRate satisfies asset.TargetFeeRater.
+func (dcr *ExchangeWallet) TargetFeeRate(confTarget uint64) (uint64, error) {
+	return dcr.feeRate(confTarget)
+}
+
 // FeeRateSwap is same as FeeRate but for swaps.
 func (dcr *ExchangeWallet) FeeRateSwap() (rate uint64, tooLow bool) {
 	return dcr.FeeRate()
@@ -2252,7 +2290,7 @@ func (dcr *ExchangeWallet) submitMultiSplitTx(fundingCoins asset.Coins, _ /* spe
 		baseTx.AddTxOut(txOut)
 	}
 
-	tx, err := dcr.sendWithReturn(baseTx, splitTxFeeRate, -1)
+	tx, err := dcr.sendWithReturn(baseTx, splitTxFeeRate, -1, dcr.tradeChangeAccount())
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2590,14 +2628,25 @@ func (dcr *ExchangeWallet) spendableUTXOs() ([]*compositeUTXO, error) {
 	}
 	if accts.TradingAccount != "" {
 		// Trading account may contain spendable utxos such as unspent split tx
-		// outputs that are unlocked/returned. TODO: Care should probably be
-		// taken to ensure only unspent split tx outputs are selected and other
-		// unmixed outputs in the trading account are ignored.
+		// outputs that are unlocked/returned. Only the split tx outputs, which
+		// are on the external branch, are selected. Change from chained swaps
+		// on the internal branch would link orders, and is left for ReturnCoins
+		// to transfer to the trade change account.
 		tradingAcctSpendables, err := dcr.wallet.Unspents(dcr.ctx, accts.TradingAccount)
 		if err != nil {
 			return nil, err
 		}
-		unspents = append(unspents, tradingAcctSpendables...)
+		for _, unspent := range tradingAcctSpendables {
+			addrInfo, err := dcr.wallet.AddressInfo(dcr.ctx, unspent.Address)
+			if err != nil {
+				return nil, fmt.Errorf("error getting address info for trading account output %s:%d: %w",
+					unspent.TxID, unspent.Vout, err)
+			}
+			if addrInfo.Branch == acctInternalBranch {
+				continue
+			}
+			unspents = append(unspents, unspent)
+		}
 	}
 	if len(unspents) == 0 {
 		return nil, fmt.Errorf("insufficient funds. 0 DCR available to spend in account %q", accts.PrimaryAccount)
@@ -2799,7 +2848,7 @@ func (dcr *ExchangeWallet) split(value uint64, lots uint64, coins asset.Coins, i
 	dcr.fundingMtx.Lock()         // before generating the new output in sendCoins
 	defer dcr.fundingMtx.Unlock() // after locking it (wallet and map)
 
-	msgTx, sentVal, err := dcr.sendCoins(coins, addr, addr2, reqFunds, extraOutput, splitFeeRate, false)
+	msgTx, sentVal, err := dcr.sendCoins(coins, addr, addr2, reqFunds, extraOutput, splitFeeRate, false, dcr.tradeChangeAccount())
 	if err != nil {
 		return nil, false, 0, fmt.Errorf("error sending split transaction: %w", err)
 	}
@@ -2879,10 +2928,10 @@ func (dcr *ExchangeWallet) unlockFundingCoins(fCoins []*fundingCoin) error {
 
 // ReturnCoins unlocks coins. This would be necessary in the case of a canceled
 // order. Coins belonging to the tradingAcct, if configured, are transferred to
-// the unmixed account with the exception of unspent split tx outputs which are
-// kept in the tradingAcct and may later be used to fund future orders. If
-// called with a nil slice, all coins are returned and none are moved to the
-// unmixed account.
+// the trade change account (the unmixed account, or the change holding account
+// if configured) with the exception of unspent split tx outputs which are kept
+// in the tradingAcct and may later be used to fund future orders. If called
+// with a nil slice, all coins are returned and none are moved.
 func (dcr *ExchangeWallet) ReturnCoins(unspents asset.Coins) error {
 	if unspents == nil { // not just empty to make this harder to do accidentally
 		dcr.log.Debugf("Returning all coins.")
@@ -2907,10 +2956,10 @@ func (dcr *ExchangeWallet) ReturnCoins(unspents asset.Coins) error {
 	}
 
 	// If any of these coins belong to the trading account, transfer them to the
-	// unmixed account to be re-mixed into the primary account before being
-	// re-selected for funding future orders. This doesn't apply to unspent
-	// split tx outputs, which should remain in the trading account and be
-	// selected from there for funding future orders.
+	// trade change account to be re-mixed into the primary account (or held)
+	// rather than being re-selected for funding future orders. This doesn't
+	// apply to unspent split tx outputs, which should remain in the trading
+	// account and be selected from there for funding future orders.
 	var coinsToTransfer []asset.Coin
 	for _, coin := range returnedCoins {
 		if coin.addr == "" {
@@ -2939,13 +2988,14 @@ func (dcr *ExchangeWallet) ReturnCoins(unspents asset.Coins) error {
 	}
 
 	if len(coinsToTransfer) > 0 {
-		tx, totalSent, err := dcr.sendAll(coinsToTransfer, accts.UnmixedAccount)
+		changeAcct := dcr.tradeChangeAccount()
+		tx, totalSent, err := dcr.sendAll(coinsToTransfer, changeAcct)
 		if err != nil {
 			dcr.log.Errorf("unable to transfer unlocked swapped change from temp trading "+
-				"account to unmixed account: %v", err)
+				"account to %q account: %v", changeAcct, err)
 		} else {
-			dcr.log.Infof("Transferred %s from temp trading account to unmixed account in tx %s.",
-				dcrutil.Amount(totalSent), tx.TxHash())
+			dcr.log.Infof("Transferred %s from temp trading account to %q account in tx %s.",
+				dcrutil.Amount(totalSent), changeAcct, tx.TxHash())
 		}
 	}
 
@@ -3171,7 +3221,7 @@ func (dcr *ExchangeWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin
 	defer dcr.fundingMtx.Unlock() // hold until after returnCoins and lockFundingCoins(change)
 	// Sign the tx but don't send the transaction yet until
 	// the individual swap refund txs are prepared and signed.
-	changeAcct := dcr.depositAccount()
+	changeAcct := dcr.tradeChangeAccount()
 	tradingAccount := dcr.wallet.Accounts().TradingAccount
 	if swaps.LockChange && tradingAccount != "" {
 		// Change will likely be used to fund more swaps, send to trading
@@ -4976,7 +5026,7 @@ func (dcr *ExchangeWallet) withdraw(addr stdaddr.Address, val, feeRate uint64) (
 			amount(val), addr, feeRate, err)
 	}
 
-	msgTx, sentVal, err := dcr.sendCoins(coins, addr, nil, val, 0, feeRate, true)
+	msgTx, sentVal, err := dcr.sendCoins(coins, addr, nil, val, 0, feeRate, true, dcr.depositAccount())
 	if err != nil {
 		if _, retErr := dcr.returnCoins(coins); retErr != nil {
 			dcr.log.Errorf("Failed to unlock coins: %v", retErr)
@@ -5000,7 +5050,7 @@ func (dcr *ExchangeWallet) sendToAddress(addr stdaddr.Address, amt, feeRate uint
 			amount(amt), feeRate, err)
 	}
 
-	msgTx, sentVal, err := dcr.sendCoins(coins, addr, nil, amt, 0, feeRate, false)
+	msgTx, sentVal, err := dcr.sendCoins(coins, addr, nil, amt, 0, feeRate, false, dcr.depositAccount())
 	if err != nil {
 		if _, retErr := dcr.returnCoins(coins); retErr != nil {
 			dcr.log.Errorf("Failed to unlock coins: %v", retErr)
@@ -5029,8 +5079,10 @@ func (dcr *ExchangeWallet) sendToAddress(addr stdaddr.Address, amt, feeRate uint
 // arguments, if addr2 is non-nil. Note that to omit the extra output, the
 // *interface* must be nil, not just the concrete type, so be cautious with
 // concrete address types because a nil pointer wrap into a non-nil std.Address!
+//
+// Change is sent to the changeAcct.
 func (dcr *ExchangeWallet) sendCoins(coins asset.Coins, addr, addr2 stdaddr.Address, val, val2, feeRate uint64,
-	subtract bool) (*wire.MsgTx, uint64, error) {
+	subtract bool, changeAcct string) (*wire.MsgTx, uint64, error) {
 	baseTx := wire.NewMsgTx()
 	_, err := dcr.addInputCoins(baseTx, coins)
 	if err != nil {
@@ -5050,7 +5102,7 @@ func (dcr *ExchangeWallet) sendCoins(coins asset.Coins, addr, addr2 stdaddr.Addr
 		feeSource = -1 // subtract from change
 	}
 
-	tx, err := dcr.sendWithReturn(baseTx, feeRate, feeSource)
+	tx, err := dcr.sendWithReturn(baseTx, feeRate, feeSource, changeAcct)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -5075,7 +5127,7 @@ func (dcr *ExchangeWallet) sendAll(coins asset.Coins, destAcct string) (*wire.Ms
 	baseTx.AddTxOut(txOut)
 
 	feeRate := dcr.targetFeeRateWithFallback(2, 0)
-	tx, err := dcr.sendWithReturn(baseTx, feeRate, 0) // subtract from vout 0
+	tx, err := dcr.sendWithReturn(baseTx, feeRate, 0, dcr.depositAccount()) // subtract from vout 0
 	return tx, uint64(txOut.Value), err
 }
 
@@ -5132,11 +5184,12 @@ func (dcr *ExchangeWallet) makeChangeOut(changeAcct string, val uint64) (*wire.T
 	return newTxOut(int64(val), changeScriptVersion, changeScript), changeAddr, nil
 }
 
-// sendWithReturn sends the unsigned transaction, adding a change output unless
-// the amount is dust. subtractFrom indicates the output from which fees should
-// be subtracted, where -1 indicates fees should come out of a change output.
-func (dcr *ExchangeWallet) sendWithReturn(baseTx *wire.MsgTx, feeRate uint64, subtractFrom int32) (*wire.MsgTx, error) {
-	signedTx, _, _, _, err := dcr.signTxAndAddChange(baseTx, feeRate, subtractFrom, dcr.depositAccount())
+// sendWithReturn sends the unsigned transaction, adding a change output to the
+// changeAcct unless the amount is dust. subtractFrom indicates the output from
+// which fees should be subtracted, where -1 indicates fees should come out of a
+// change output.
+func (dcr *ExchangeWallet) sendWithReturn(baseTx *wire.MsgTx, feeRate uint64, subtractFrom int32, changeAcct string) (*wire.MsgTx, error) {
+	signedTx, _, _, _, err := dcr.signTxAndAddChange(baseTx, feeRate, subtractFrom, changeAcct)
 	if err != nil {
 		return nil, err
 	}
@@ -3776,7 +3776,7 @@ func TestSendEdges(t *testing.T) {
 	node.changeAddr = tPKHAddr
 
 	for _, tt := range tests {
-		tx, err := wallet.sendWithReturn(newBaseTx(tt.funding), feeRate, -1)
+		tx, err := wallet.sendWithReturn(newBaseTx(tt.funding), feeRate, -1, wallet.depositAccount())
 		if err != nil {
 			t.Fatalf("sendWithReturn error: %v", err)
 		}
@@ -5056,3 +5056,71 @@ func TestRescanSync(t *testing.T) {
 	checkProgress(true, 1)
 
 }
+
+func TestTradeChangeAccount(t *testing.T) {
+	wallet, node, shutdown := tNewWallet()
+	defer shutdown()
+
+	rpcw := wallet.wallet.(*rpcWallet)
+	rpcw.chainParams = tChainParams
+	const unmixedAcct, tradingAcct, holdAcct = "unmixed", "trading", "hold"
+
+	if acct := wallet.tradeChangeAccount(); acct != tAcctName {
+		t.Fatalf("expected change to %q account without mixing, got %q", tAcctName, acct)
+	}
+	accts := XCWalletAccounts{
+		PrimaryAccount: tAcctName,
+		UnmixedAccount: unmixedAcct,
+		TradingAccount: tradingAcct,
+	}
+	rpcw.accountsV.Store(accts)
+	if acct := wallet.tradeChangeAccount(); acct != unmixedAcct {
+		t.Fatalf("expected change to be mixed, got %q account", acct)
+	}
+	accts.ChangeHoldAccount = holdAcct
+	rpcw.accountsV.Store(accts)
+	if acct := wallet.tradeChangeAccount(); acct != holdAcct {
+		t.Fatalf("expected change to be held, got %q account", acct)
+	}
+
+	// Change in the trading account is not used for funding, but split tx
+	// outputs are.
+	node.validateAddress = make(map[string]*walletjson.ValidateAddressResult)
+	var vout uint32
+	addUnspent := func(acct string, branch uint32) {
+		addr, _ := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(randBytes(20), tChainParams)
+		node.validateAddress[addr.String()] = &walletjson.ValidateAddressResult{
+			IsValid: true,
+			IsMine:  true,
+			Account: acct,
+			Branch:  &branch,
+		}
+		node.unspent = append(node.unspent, walletjson.ListUnspentResult{
+			TxID:          tTxID,
+			Vout:          vout,
+			Address:       addr.String(),
+			Account:       acct,
+			Amount:        1,
+			Confirmations: 1,
+			ScriptPubKey:  hex.EncodeToString(tP2PKHScript),
+			Spendable:     true,
+		})
+		vout++
+	}
+	addUnspent(tAcctName, acctInternalBranch)
+	addUnspent(tradingAcct, acctInternalBranch)
+	addUnspent(tradingAcct, 0)
+
+	utxos, err := wallet.spendableUTXOs()
+	if err != nil {
+		t.Fatalf("spendableUTXOs error: %v", err)
+	}
+	if len(utxos) != 2 {
+		t.Fatalf("expected 2 spendable utxos, got %d", len(utxos))
+	}
+	for _, utxo := range utxos {
+		if utxo.rpc.Vout == 1 {
+			t.Fatalf("trading account change selected for funding")
+		}
+	}
+}
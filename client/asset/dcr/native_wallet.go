@@ -71,7 +71,7 @@ type NativeWallet struct {
 
 // NativeWallet must also satisfy the following interface(s).
 var _ asset.FundsMixer = (*NativeWallet)(nil)
-var _ asset.Rescanner = (*NativeWallet)(nil)
+var _ asset.RescanProgresser = (*NativeWallet)(nil)
 
 func initNativeWallet(ew *ExchangeWallet) (*NativeWallet, error) {
 	spvWallet, ok := ew.wallet.(*spvWallet)
@@ -307,6 +307,30 @@ func (w *NativeWallet) birthdayBlockHeight(ctx context.Context, bday uint64) int
 	return int32(firstBlockAfterBday - 1)
 }
 
+// RescanProgress returns the progress of the running rescan, or nil if there is
+// no rescan running. Part of the asset.RescanProgresser interface.
+func (w *NativeWallet) RescanProgress() *asset.RescanProgress {
+	w.rescan.RLock()
+	progress := w.rescan.progress
+	w.rescan.RUnlock()
+	if progress == nil {
+		return nil
+	}
+	tip := w.cachedBestBlock().height
+	if tip < progress.scannedThrough {
+		tip = progress.scannedThrough
+	}
+	height := progress.scannedThrough
+	if height < progress.startHeight {
+		height = progress.startHeight
+	}
+	return &asset.RescanProgress{
+		StartHeight:  uint64(progress.startHeight),
+		Height:       uint64(height),
+		TargetHeight: uint64(tip),
+	}
+}
+
 // Rescan initiates a rescan of the wallet from height 0. Rescan only blocks
 // long enough for the first asynchronous update, either an error or after the
 // first 2000 blocks are scanned.
@@ -334,9 +358,16 @@ func (w *NativeWallet) Rescan(ctx context.Context, bday uint64) (err error) {
 		bdayHeight = 0
 	}
 
+	w.rescan.Lock()
+	w.rescan.progress.startHeight = int64(bdayHeight)
+	w.rescan.Unlock()
+
 	setProgress := func(height int32) {
 		w.rescan.Lock()
-		w.rescan.progress = &rescanProgress{scannedThrough: int64(height)}
+		w.rescan.progress = &rescanProgress{
+			startHeight:    int64(bdayHeight),
+			scannedThrough: int64(height),
+		}
 		w.rescan.Unlock()
 	}
 
@@ -213,9 +213,10 @@ func newRPCWallet(settings map[string]string, logger dex.Logger, net dex.Network
 	rpcw.rpcClient = newCombinedClient(nodeRPCClient, chainParams)
 
 	rpcw.accountsV.Store(XCWalletAccounts{
-		PrimaryAccount: cfg.PrimaryAccount,
-		UnmixedAccount: cfg.UnmixedAccount,
-		TradingAccount: cfg.TradingAccount,
+		PrimaryAccount:    cfg.PrimaryAccount,
+		UnmixedAccount:    cfg.UnmixedAccount,
+		TradingAccount:    cfg.TradingAccount,
+		ChangeHoldAccount: cfg.ChangeHoldAccount,
 	})
 
 	return rpcw, nil
@@ -256,9 +257,10 @@ func (w *rpcWallet) Reconfigure(ctx context.Context, cfg *asset.WalletConfig, ne
 	defer func() {
 		if allOk { // update the account names as the last step
 			w.accountsV.Store(XCWalletAccounts{
-				PrimaryAccount: rpcCfg.PrimaryAccount,
-				UnmixedAccount: rpcCfg.UnmixedAccount,
-				TradingAccount: rpcCfg.TradingAccount,
+				PrimaryAccount:    rpcCfg.PrimaryAccount,
+				UnmixedAccount:    rpcCfg.UnmixedAccount,
+				TradingAccount:    rpcCfg.TradingAccount,
+				ChangeHoldAccount: rpcCfg.ChangeHoldAccount,
 			})
 		}
 	}()
@@ -271,7 +273,8 @@ func (w *rpcWallet) Reconfigure(ctx context.Context, cfg *asset.WalletConfig, ne
 		rpcCfg.RPCListen == w.rpcCfg.Host &&
 		rpcCfg.PrimaryAccount == currentAccts.PrimaryAccount &&
 		rpcCfg.UnmixedAccount == currentAccts.UnmixedAccount &&
-		rpcCfg.TradingAccount == currentAccts.TradingAccount {
+		rpcCfg.TradingAccount == currentAccts.TradingAccount &&
+		rpcCfg.ChangeHoldAccount == currentAccts.ChangeHoldAccount {
 		allOk = true
 		return false, nil
 	}
@@ -292,7 +295,7 @@ func (w *rpcWallet) Reconfigure(ctx context.Context, cfg *asset.WalletConfig, ne
 		}
 	}()
 
-	for _, acctName := range []string{rpcCfg.PrimaryAccount, rpcCfg.TradingAccount, rpcCfg.UnmixedAccount} {
+	for _, acctName := range []string{rpcCfg.PrimaryAccount, rpcCfg.TradingAccount, rpcCfg.UnmixedAccount, rpcCfg.ChangeHoldAccount} {
 		if acctName == "" {
 			continue
 		}
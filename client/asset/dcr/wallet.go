@@ -67,6 +67,10 @@ type XCWalletAccounts struct {
 	PrimaryAccount string
 	UnmixedAccount string
 	TradingAccount string
+	// ChangeHoldAccount, if set, receives the change from trade funding and
+	// swap transactions instead of the UnmixedAccount, where it would be
+	// mixed. Funds in the ChangeHoldAccount are not used for trading.
+	ChangeHoldAccount string
 }
 
 // ListTransactionsResult is similar to the walletjson.ListTransactionsResult,
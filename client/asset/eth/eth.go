@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"os"
 	"os/exec"
@@ -422,13 +423,18 @@ var _ asset.DynamicSwapper = (*ETHWallet)(nil)
 var _ asset.DynamicSwapper = (*TokenWallet)(nil)
 var _ asset.Authenticator = (*ETHWallet)(nil)
 var _ asset.TokenApprover = (*TokenWallet)(nil)
+var _ asset.TokenAllowanceManager = (*TokenWallet)(nil)
 var _ asset.WalletHistorian = (*ETHWallet)(nil)
 var _ asset.WalletHistorian = (*TokenWallet)(nil)
 
 type baseWallet struct {
 	// The asset subsystem starts with Connect(ctx). This ctx will be initialized
 	// in parent ETHWallet once and re-used in child TokenWallet instances.
-	ctx        context.Context
+	ctx context.Context
+	// wg tracks the goroutines of the wallet and its token wallets, and is
+	// returned from ETHWallet.Connect.
+	wg sync.WaitGroup
+
 	net        dex.Network
 	node       ethFetcher
 	addr       common.Address
@@ -461,11 +467,7 @@ type baseWallet struct {
 	walletsMtx sync.RWMutex
 	wallets    map[uint32]*assetWallet
 
-	nonceMtx            sync.RWMutex
-	pendingTxs          []*extendedWalletTx
-	confirmedNonceAt    *big.Int
-	nextNonceAt         *big.Int
-	recoveryRequestSent bool
+	nonceManager
 
 	balances struct {
 		sync.Mutex
@@ -510,6 +512,10 @@ type assetWallet struct {
 	findRedemptionMtx  sync.RWMutex
 	findRedemptionReqs map[[32]byte]*findRedemptionRequest
 
+	redeemBatchMtx  sync.Mutex
+	redeemQueues    map[uint32]*redeemQueue // contract version -> queue
+	rejectedRedeems map[[32]byte]bool       // secret hashes
+
 	approvalsMtx     sync.RWMutex
 	pendingApprovals map[uint32]*pendingApproval
 	approvalCache    map[uint32]bool
@@ -930,8 +936,7 @@ func (w *ETHWallet) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 
 	w.nonceMtx.Lock()
 	w.pendingTxs = pendingTxs
-	w.confirmedNonceAt = confirmedNonce
-	w.nextNonceAt = nextNonce
+	w.syncNonces(confirmedNonce, nextNonce)
 	w.nonceMtx.Unlock()
 
 	if w.log.Level() <= dex.LevelDebug {
@@ -954,18 +959,16 @@ func (w *ETHWallet) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 	atomic.StoreInt64(&w.tipAtConnect, height.Int64())
 	w.log.Infof("Connected to eth (%s), at height %d", w.walletType, height)
 
-	var wg sync.WaitGroup
-
-	wg.Add(1)
+	w.wg.Add(1)
 	go func() {
-		defer wg.Done()
+		defer w.wg.Done()
 		w.monitorBlocks(ctx)
 		w.node.shutdown()
 	}()
 
-	wg.Add(1)
+	w.wg.Add(1)
 	go func() {
-		defer wg.Done()
+		defer w.wg.Done()
 		w.monitorPeers(ctx)
 	}()
 
@@ -976,7 +979,7 @@ func (w *ETHWallet) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 		w.connected.Store(false)
 	}()
 
-	return &wg, nil
+	return &w.wg, nil
 }
 
 // Connect waits for context cancellation and closes the WaitGroup. Satisfies
@@ -1102,22 +1105,7 @@ func (w *assetWallet) withNonce(ctx context.Context, f transactionGenerator) (er
 	if err = nonceIsSane(w.pendingTxs, w.nextNonceAt); err != nil {
 		return err
 	}
-	nonce := func() *big.Int {
-		n := new(big.Int).Set(w.confirmedNonceAt)
-		for _, pendingTx := range w.pendingTxs {
-			if pendingTx.Nonce.Cmp(n) < 0 {
-				continue
-			}
-			if pendingTx.Nonce.Cmp(n) == 0 {
-				n.Add(n, big.NewInt(1))
-			} else {
-				break
-			}
-		}
-		return n
-	}
-
-	n := nonce()
+	n := w.nextNonce()
 	w.log.Trace("Nonce chosen for tx generator =", n)
 
 	// Make a first attempt with our best-known nonce.
@@ -1128,9 +1116,8 @@ func (w *assetWallet) withNonce(ctx context.Context, f transactionGenerator) (er
 		if err != nil {
 			return fmt.Errorf("error during too-low nonce recovery: %v", err)
 		}
-		w.confirmedNonceAt = confirmedNonceAt
-		w.nextNonceAt = pendingNonceAt
-		if newNonce := nonce(); newNonce != n {
+		w.syncNonces(confirmedNonceAt, pendingNonceAt)
+		if newNonce := w.nextNonce(); newNonce.Cmp(n) != 0 {
 			n = newNonce
 			// Try again.
 			tx, txType, amt, recipient, err = f(n)
@@ -1145,10 +1132,7 @@ func (w *assetWallet) withNonce(ctx context.Context, f transactionGenerator) (er
 
 	if tx != nil {
 		et := w.extendedTx(tx, txType, amt, recipient)
-		w.pendingTxs = append(w.pendingTxs, et)
-		if n.Cmp(w.nextNonceAt) >= 0 {
-			w.nextNonceAt.Add(n, big.NewInt(1))
-		}
+		w.addPendingTx(et)
 		w.emitTransactionNote(et.WalletTransaction, true)
 		w.log.Tracef("Transaction %s generated for nonce %s", et.ID, n)
 	}
@@ -2454,7 +2438,6 @@ func (w *assetWallet) Redeem(form *asset.RedeemForm, feeWallet *assetWallet, non
 	if g == nil {
 		return fail(fmt.Errorf("no gas table"))
 	}
-	gasLimit := g.Redeem * n
 
 	/* We could get a gas estimate via RPC, but this will reveal the secret key
 	   before submitting the redeem transaction. This is not OK for maker.
@@ -2482,14 +2465,9 @@ func (w *assetWallet) Redeem(form *asset.RedeemForm, feeWallet *assetWallet, non
 	}
 	*/
 
-	// Fetch up-to-date fee rate, we'll want to use it instead of form.FeeSuggestion since
-	// it better reflects current networking conditions.
-	maxFee, tipRate, _, err := w.recommendedMaxFeeRate(w.ctx)
-	if err != nil {
-		return fail(fmt.Errorf("Error fetching recommended max fee rate: %w", err))
-	}
-	maxFeeGwei := dexeth.WeiToGweiCeil(maxFee)
-	tx, err := w.redeem(w.ctx, form.Redemptions, maxFeeGwei, tipRate, gasLimit, contractVer)
+	// Redemptions of other matches that become redeemable at the same time
+	// are sent in the same transaction to save gas.
+	tx, batchSize, err := w.batchRedeem(contractVer, form.Redemptions)
 	if err != nil {
 		return fail(fmt.Errorf("Redeem: redeem error: %w", err))
 	}
@@ -2507,8 +2485,9 @@ func (w *assetWallet) Redeem(form *asset.RedeemForm, feeWallet *assetWallet, non
 	}
 
 	// This is still a fee estimate. If we add a redemption confirmation method
-	// as has been discussed, then maybe the fees can be updated there.
-	fees := g.RedeemN(len(form.Redemptions)) * form.FeeSuggestion
+	// as has been discussed, then maybe the fees can be updated there. The
+	// fees of a batch are split between its redemptions.
+	fees := g.RedeemN(batchSize) * form.FeeSuggestion * n / uint64(batchSize)
 
 	return txs, outputCoin, fees, nil
 }
@@ -2624,40 +2603,7 @@ func (w *TokenWallet) ApproveToken(assetVer uint32, onConfirm func()) (string, e
 		return "", asset.ErrApprovalPending
 	}
 
-	maxFeeRate, tipRate, _, err := w.recommendedMaxFeeRate(w.ctx)
-	if err != nil {
-		return "", fmt.Errorf("error calculating approval fee rate: %w", err)
-	}
-	feeRateGwei := dexeth.WeiToGweiCeil(maxFeeRate)
-	approvalGas, err := w.approvalGas(unlimitedAllowance, assetVer)
-	if err != nil {
-		return "", fmt.Errorf("error calculating approval gas: %w", err)
-	}
-
-	ethBal, err := w.parent.balance()
-	if err != nil {
-		return "", fmt.Errorf("error getting eth balance: %w", err)
-	}
-	if ethBal.Available < approvalGas*feeRateGwei {
-		return "", fmt.Errorf("insufficient fee balance for approval. required: %d, available: %d",
-			approvalGas*feeRateGwei, ethBal.Available)
-	}
-
-	tx, err := w.approveToken(w.ctx, unlimitedAllowance, approvalGas, maxFeeRate, tipRate, assetVer)
-	if err != nil {
-		return "", fmt.Errorf("error approving token: %w", err)
-	}
-
-	w.approvalsMtx.Lock()
-	defer w.approvalsMtx.Unlock()
-
-	delete(w.approvalCache, assetVer)
-	w.pendingApprovals[assetVer] = &pendingApproval{
-		txHash:    tx.Hash(),
-		onConfirm: onConfirm,
-	}
-
-	return tx.Hash().Hex(), nil
+	return w.sendApproval(assetVer, unlimitedAllowance, onConfirm)
 }
 
 // UnapproveToken removes the approval for a specific version of the token's
@@ -2674,12 +2620,32 @@ func (w *TokenWallet) UnapproveToken(assetVer uint32, onConfirm func()) (string,
 		return "", asset.ErrApprovalPending
 	}
 
+	return w.sendApproval(assetVer, big.NewInt(0), onConfirm)
+}
+
+// SetTokenAllowance sends an approval transaction setting the allowance for a
+// version of the token's swap contract. An allowance of zero revokes the
+// approval. Part of the asset.TokenAllowanceManager interface.
+func (w *TokenWallet) SetTokenAllowance(assetVer uint32, allowance uint64, onConfirm func()) (string, error) {
+	approvalStatus, err := w.approvalStatus(assetVer)
+	if err != nil {
+		return "", fmt.Errorf("error checking approval status: %w", err)
+	}
+	if approvalStatus == asset.Pending {
+		return "", asset.ErrApprovalPending
+	}
+	return w.sendApproval(assetVer, w.evmify(allowance), onConfirm)
+}
+
+// sendApproval sends an approval transaction for the amount, and tracks it
+// until it is confirmed.
+func (w *TokenWallet) sendApproval(assetVer uint32, amount *big.Int, onConfirm func()) (string, error) {
 	maxFeeRate, tipRate, _, err := w.recommendedMaxFeeRate(w.ctx)
 	if err != nil {
 		return "", fmt.Errorf("error calculating approval fee rate: %w", err)
 	}
 	feeRateGwei := dexeth.WeiToGweiCeil(maxFeeRate)
-	approvalGas, err := w.approvalGas(big.NewInt(0), assetVer)
+	approvalGas, err := w.approvalGas(amount, assetVer)
 	if err != nil {
 		return "", fmt.Errorf("error calculating approval gas: %w", err)
 	}
@@ -2689,13 +2655,13 @@ func (w *TokenWallet) UnapproveToken(assetVer uint32, onConfirm func()) (string,
 		return "", fmt.Errorf("error getting eth balance: %w", err)
 	}
 	if ethBal.Available < approvalGas*feeRateGwei {
-		return "", fmt.Errorf("insufficient eth balance for unapproval. required: %d, available: %d",
+		return "", fmt.Errorf("insufficient fee balance for approval. required: %d, available: %d",
 			approvalGas*feeRateGwei, ethBal.Available)
 	}
 
-	tx, err := w.approveToken(w.ctx, big.NewInt(0), approvalGas, maxFeeRate, tipRate, assetVer)
+	tx, err := w.approveToken(w.ctx, amount, approvalGas, maxFeeRate, tipRate, assetVer)
 	if err != nil {
-		return "", fmt.Errorf("error unapproving token: %w", err)
+		return "", fmt.Errorf("error sending approval: %w", err)
 	}
 
 	w.approvalsMtx.Lock()
@@ -2710,6 +2676,40 @@ func (w *TokenWallet) UnapproveToken(assetVer uint32, onConfirm func()) (string,
 	return tx.Hash().Hex(), nil
 }
 
+// TokenAllowances returns the allowance granted to each version of the
+// token's swap contract. Part of the asset.TokenAllowanceManager interface.
+func (w *TokenWallet) TokenAllowances() ([]*asset.TokenAllowance, error) {
+	maxAtoms := w.evmify(math.MaxUint64)
+	versions := w.Info().SupportedVersions
+	allowances := make([]*asset.TokenAllowance, 0, len(versions))
+	for _, ver := range versions {
+		contractAddr, found := w.versionedContracts[ver]
+		if !found {
+			continue
+		}
+		allowance, err := w.tokenAllowance(ver)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving allowance for version %d: %w", ver, err)
+		}
+		status, err := w.approvalStatus(ver)
+		if err != nil {
+			return nil, fmt.Errorf("error checking approval status for version %d: %w", ver, err)
+		}
+		ta := &asset.TokenAllowance{
+			Version: ver,
+			Spender: contractAddr.String(),
+			Status:  status,
+		}
+		if allowance.Cmp(maxAtoms) > 0 {
+			ta.Unlimited = true
+		} else {
+			ta.Allowance = w.atomize(allowance)
+		}
+		allowances = append(allowances, ta)
+	}
+	return allowances, nil
+}
+
 // ApprovalFee returns the estimated fee for an approval transaction.
 func (w *TokenWallet) ApprovalFee(assetVer uint32, approve bool) (uint64, error) {
 	var allowance *big.Int
@@ -3863,6 +3863,7 @@ func (w *assetWallet) confirmRedemption(coinID dex.Bytes, redemption *asset.Rede
 			confirmStatus = confStatus(0, w.finalizeConfs, txHash)
 		}
 		if s.receipt != nil && s.receipt.Status != types.ReceiptStatusSuccessful && confirmStatus.Confs >= w.finalizeConfs {
+			w.redeemRejected(secretHash)
 			return nil, asset.ErrTxRejected
 		}
 		return confirmStatus, nil
@@ -3904,6 +3905,7 @@ func (w *assetWallet) confirmRedemption(coinID dex.Bytes, redemption *asset.Rede
 			return nil, asset.ErrSwapRefunded
 		}
 
+		w.redeemRejected(secretHash)
 		err = fmt.Errorf("tx %s failed to redeem %s funds", txHash, dex.BipIDSymbol(w.assetID))
 		return nil, errors.Join(err, asset.ErrTxRejected)
 	}
@@ -4756,6 +4758,9 @@ func (w *baseWallet) checkPendingTxs() {
 		}
 	}
 
+	// Periodically reconcile our nonces with the providers' view.
+	w.resyncNonces()
+
 	// If we have missing nonces, send an alert.
 	if !w.recoveryRequestSent && len(findMissingNonces(w.confirmedNonceAt, w.nextNonceAt, w.pendingTxs)) != 0 {
 		w.recoveryRequestSent = true
@@ -4790,8 +4795,12 @@ func (w *baseWallet) checkPendingTxs() {
 		// Recheck how transaction fees compare to network conditions. Note, fee tip can be
 		// a large chunk of total fees (on Polygon in particular) - take it into account too,
 		// propose new fees to the user if ours are below what network currently expects.
+		// A stuck tx, one that no provider has seen for a long time, is
+		// blocking every later nonce, and is replaced regardless of how its
+		// fees compare.
 		const feeCheckInterval = 2 * time.Minute
-		if time.Since(pendingTx.lastFeeCheck) < feeCheckInterval {
+		stuck := w.isStuck(pendingTx)
+		if !stuck && time.Since(pendingTx.lastFeeCheck) < feeCheckInterval {
 			continue
 		}
 		pendingTx.lastFeeCheck = time.Now()
@@ -4800,14 +4809,19 @@ func (w *baseWallet) checkPendingTxs() {
 			w.log.Errorf("Error decoding raw tx %s for fee check: %v", pendingTx.ID, err)
 			continue
 		}
-		currentFeeRate, err := w.currentFeeRate(w.ctx)
-		if err != nil {
-			w.log.Errorf("Error getting network fees: %v", err)
-			continue
-		}
-		if tx.GasFeeCap().Cmp(currentFeeRate) >= 0 {
-			w.log.Tracef("Pending transacton %s fees seem fine with respect to current netowrk conditions", pendingTx.ID)
-			continue
+		if stuck {
+			w.log.Warnf("Pending transaction %s at nonce %s has not been seen by any provider for %s and is blocking later transactions",
+				pendingTx.ID, pendingTx.Nonce, pendingTx.age().Truncate(time.Second))
+		} else {
+			currentFeeRate, err := w.currentFeeRate(w.ctx)
+			if err != nil {
+				w.log.Errorf("Error getting network fees: %v", err)
+				continue
+			}
+			if tx.GasFeeCap().Cmp(currentFeeRate) >= 0 {
+				w.log.Tracef("Pending transacton %s fees seem fine with respect to current netowrk conditions", pendingTx.ID)
+				continue
+			}
 		}
 		pendingTx.feesBumps++ // gotta bump the fees then
 		baseRate, tipRate, err := w.currentNetworkFees(w.ctx)
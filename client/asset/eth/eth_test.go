@@ -337,6 +337,7 @@ type tContractor struct {
 	refundableErr     error
 	lastRedeemOpts    *bind.TransactOpts
 	lastRedeems       []*asset.Redemption
+	redeemFunc        func([]*asset.Redemption) (*types.Transaction, error)
 	lastRefund        struct {
 		// tx          *types.Transaction
 		secretHash [32]byte
@@ -363,6 +364,9 @@ func (c *tContractor) initiate(*bind.TransactOpts, []*asset.Contract) (*types.Tr
 func (c *tContractor) redeem(txOpts *bind.TransactOpts, redeems []*asset.Redemption) (*types.Transaction, error) {
 	c.lastRedeemOpts = txOpts
 	c.lastRedeems = redeems
+	if c.redeemFunc != nil {
+		return c.redeemFunc(redeems)
+	}
 	return c.redeemTx, c.redeemErr
 }
 
@@ -967,15 +971,17 @@ func TestCheckForNewBlocks(t *testing.T) {
 		w := &ETHWallet{
 			assetWallet: &assetWallet{
 				baseWallet: &baseWallet{
-					node:             node,
-					addr:             node.address(),
-					ctx:              ctx,
-					log:              tLogger,
-					currentTip:       header0,
-					confirmedNonceAt: new(big.Int),
-					nextNonceAt:      new(big.Int),
-					txDB:             &tTxDB{},
-					finalizeConfs:    txConfsNeededToConfirm,
+					node:       node,
+					addr:       node.address(),
+					ctx:        ctx,
+					log:        tLogger,
+					currentTip: header0,
+					nonceManager: nonceManager{
+						confirmedNonceAt: new(big.Int),
+						nextNonceAt:      new(big.Int),
+					},
+					txDB:          &tTxDB{},
+					finalizeConfs: txConfsNeededToConfirm,
 				},
 				log:     tLogger.SubLogger("ETH"),
 				emit:    emit,
@@ -1160,21 +1166,23 @@ func tassetWallet(assetID uint32) (asset.Wallet, *assetWallet, *tMempoolNode, co
 
 	aw := &assetWallet{
 		baseWallet: &baseWallet{
-			baseChainID:      BipID,
-			chainID:          dexeth.ChainIDs[dex.Simnet],
-			tokens:           dexeth.Tokens,
-			addr:             node.addr,
-			net:              dex.Simnet,
-			node:             node,
-			ctx:              ctx,
-			log:              tLogger,
-			gasFeeLimitV:     defaultGasFeeLimit,
-			nextNonceAt:      new(big.Int),
-			confirmedNonceAt: new(big.Int),
-			pendingTxs:       make([]*extendedWalletTx, 0),
-			txDB:             &tTxDB{},
-			currentTip:       &types.Header{Number: new(big.Int)},
-			finalizeConfs:    txConfsNeededToConfirm,
+			baseChainID:  BipID,
+			chainID:      dexeth.ChainIDs[dex.Simnet],
+			tokens:       dexeth.Tokens,
+			addr:         node.addr,
+			net:          dex.Simnet,
+			node:         node,
+			ctx:          ctx,
+			log:          tLogger,
+			gasFeeLimitV: defaultGasFeeLimit,
+			nonceManager: nonceManager{
+				nextNonceAt:      new(big.Int),
+				confirmedNonceAt: new(big.Int),
+				pendingTxs:       make([]*extendedWalletTx, 0),
+			},
+			txDB:          &tTxDB{},
+			currentTip:    &types.Header{Number: new(big.Int)},
+			finalizeConfs: txConfsNeededToConfirm,
 		},
 		versionedGases:     versionedGases,
 		maxSwapGas:         versionedGases[0].Swap,
@@ -3317,6 +3325,231 @@ func testRedeem(t *testing.T, assetID uint32) {
 	}
 }
 
+func TestRedeemBatch(t *testing.T) {
+	w, eth, node, shutdown := tassetWallet(BipID)
+	defer shutdown()
+
+	node.bal = dexeth.GweiToWei(10e9)
+
+	newForm := func() *asset.RedeemForm {
+		var secret [32]byte
+		copy(secret[:], encode.RandomBytes(32))
+		secretHash := sha256.Sum256(secret[:])
+		node.tContractor.swapMap[secretHash] = &dexeth.SwapState{
+			BlockHeight: 1,
+			LockTime:    time.Now(),
+			Initiator:   testAddressB,
+			Participant: testAddressA,
+			Value:       dexeth.GweiToWei(1e9),
+			State:       dexeth.SSInitiated,
+		}
+		return &asset.RedeemForm{
+			Redemptions: []*asset.Redemption{{
+				Spends: &asset.AuditInfo{
+					Contract:   dexeth.EncodeContractData(0, secretHash),
+					SecretHash: secretHash[:],
+					Coin:       &coin{id: randomHash()},
+				},
+				Secret: secret[:],
+			}},
+			FeeSuggestion: 100,
+		}
+	}
+
+	// The contractor's redeem blocks until released if block is set, and
+	// fails any transaction with a bad redemption. The queue may be sending
+	// while the test checks results, so the fields are protected by mtx.
+	var mtx sync.Mutex
+	var calls [][]*asset.Redemption
+	var sent []*types.Transaction
+	inFlight, release := make(chan struct{}, 1), make(chan struct{})
+	var block bool
+	var badSecret dex.Bytes
+	node.tContractor.redeemFunc = func(redeems []*asset.Redemption) (*types.Transaction, error) {
+		mtx.Lock()
+		calls = append(calls, redeems)
+		blocking := block
+		block = false
+		mtx.Unlock()
+		if blocking {
+			inFlight <- struct{}{}
+			<-release
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		for _, r := range redeems {
+			if bytes.Equal(r.Secret, badSecret) {
+				return nil, errors.New("test error")
+			}
+		}
+		tx := types.NewTx(&types.DynamicFeeTx{
+			Nonce: node.tContractor.lastRedeemOpts.Nonce.Uint64(),
+			Data:  []byte{byte(len(calls))},
+		})
+		sent = append(sent, tx)
+		return tx, nil
+	}
+	reset := func() {
+		mtx.Lock()
+		calls, sent = nil, nil
+		block = true
+		mtx.Unlock()
+	}
+	callSizes := func() (sizes []int) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		for _, c := range calls {
+			sizes = append(sizes, len(c))
+		}
+		return
+	}
+
+	type redeemResult struct {
+		txs  []dex.Bytes
+		fees uint64
+		err  error
+	}
+	redeem := func(form *asset.RedeemForm) <-chan *redeemResult {
+		c := make(chan *redeemResult, 1)
+		go func() {
+			txs, _, fees, err := w.Redeem(form)
+			c <- &redeemResult{txs, fees, err}
+		}()
+		return c
+	}
+	waitQueued := func(n int) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			eth.redeemBatchMtx.Lock()
+			var queued int
+			if q := eth.redeemQueues[0]; q != nil {
+				queued = len(q.pending)
+			}
+			eth.redeemBatchMtx.Unlock()
+			if queued == n {
+				return
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+		t.Fatalf("redemptions not queued")
+	}
+	// txHash is the hash of the nth successfully sent transaction.
+	txHash := func(n int) dex.Bytes {
+		mtx.Lock()
+		defer mtx.Unlock()
+		h := sent[n-1].Hash()
+		return h[:]
+	}
+
+	// With no other redemption in flight, a redemption is sent immediately,
+	// and the redemptions queued while it is sent are sent together.
+	// The forms are created up front, since the test contractor's swapMap
+	// is not safe for concurrent use.
+	const n = 3
+	forms := make([]*asset.RedeemForm, n+5)
+	for i := range forms {
+		forms[i] = newForm()
+	}
+	reset()
+	first := redeem(forms[n])
+	<-inFlight
+	results := make([]<-chan *redeemResult, n)
+	for i := range results {
+		results[i] = redeem(forms[i])
+	}
+	waitQueued(n)
+	release <- struct{}{}
+	if res := <-first; res.err != nil || !bytes.Equal(res.txs[0], txHash(1)) || res.fees != ethGases.RedeemN(1)*100 {
+		t.Fatalf("wrong first redemption result: %+v", res)
+	}
+	var totalFees uint64
+	for _, c := range results {
+		res := <-c
+		if res.err != nil {
+			t.Fatalf("Redeem error: %v", res.err)
+		}
+		if !bytes.Equal(res.txs[0], txHash(2)) {
+			t.Fatalf("wrong redemption tx")
+		}
+		totalFees += res.fees
+	}
+	if sizes := callSizes(); len(sizes) != 2 || sizes[1] != n {
+		t.Fatalf("expected %d redemptions in the second transaction, got %v", n, sizes)
+	}
+	if gasLimit := node.tContractor.lastRedeemOpts.GasLimit; gasLimit != ethGases.Redeem*n {
+		t.Fatalf("expected gas limit %d, got %d", ethGases.Redeem*n, gasLimit)
+	}
+	// The batch's fees are split between the redemptions, with rounding.
+	if expFees := ethGases.RedeemN(n) * 100; totalFees > expFees || expFees-totalFees >= n {
+		t.Fatalf("expected total fees %d, got %d", expFees, totalFees)
+	}
+
+	// If a batch fails, the redemptions are retried separately.
+	reset()
+	first = redeem(forms[n+1])
+	<-inFlight
+	goodForm, badForm := forms[n+2], forms[n+3]
+	mtx.Lock()
+	badSecret = badForm.Redemptions[0].Secret
+	mtx.Unlock()
+	good, bad := redeem(goodForm), redeem(badForm)
+	waitQueued(2)
+	release <- struct{}{}
+	<-first
+	if res := <-good; res.err != nil || !bytes.Equal(res.txs[0], txHash(2)) || res.fees != ethGases.RedeemN(1)*100 {
+		t.Fatalf("wrong retried redemption result: %+v", res)
+	}
+	if res := <-bad; res.err == nil {
+		t.Fatalf("no error for bad redemption")
+	}
+	if sizes := callSizes(); len(sizes) != 4 || sizes[1] != 2 || sizes[2] != 1 || sizes[3] != 1 {
+		t.Fatalf("wrong redemption transactions %v", sizes)
+	}
+
+	// A redemption that was rejected is not batched with others.
+	mtx.Lock()
+	badSecret = nil
+	mtx.Unlock()
+	reset()
+	first = redeem(forms[n+4])
+	<-inFlight
+	other := redeem(forms[0])
+	waitQueued(1)
+	var secretHash [32]byte
+	copy(secretHash[:], goodForm.Redemptions[0].Spends.SecretHash)
+	eth.redeemRejected(secretHash)
+	rejected := redeem(goodForm)
+	release <- struct{}{}
+	for _, c := range []<-chan *redeemResult{first, other, rejected} {
+		if res := <-c; res.err != nil {
+			t.Fatalf("Redeem error: %v", res.err)
+		}
+	}
+	if sizes := callSizes(); len(sizes) != 3 || sizes[1] != 1 || sizes[2] != 1 {
+		t.Fatalf("wrong redemption transactions %v", sizes)
+	}
+
+	// Queued redemptions fail when the wallet is shut down, and the wallet's
+	// WaitGroup waits for the queue.
+	reset()
+	first = redeem(forms[1])
+	<-inFlight
+	queued := redeem(forms[2])
+	waitQueued(1)
+	shutdown()
+	release <- struct{}{}
+	if res := <-first; res.err != nil {
+		t.Fatalf("Redeem error for in-flight redemption: %v", res.err)
+	}
+	if res := <-queued; !errors.Is(res.err, context.Canceled) {
+		t.Fatalf("wrong error for queued redemption after shutdown: %v", res.err)
+	}
+	eth.wg.Wait()
+	if sizes := callSizes(); len(sizes) != 1 {
+		t.Fatalf("wrong redemption transactions %v", sizes)
+	}
+}
+
 func TestMaxOrder(t *testing.T) {
 	const baseFee, tip = 42, 2
 
@@ -4498,7 +4731,7 @@ func testSend(t *testing.T, assetID uint32) {
 	node.sendTxTx = tx
 	node.tokenContractor.transferTx = tx
 
-	maxFeeRate, _, _ := eth.recommendedMaxFeeRate(eth.ctx)
+	maxFeeRate, _, _, _ := eth.recommendedMaxFeeRate(eth.ctx)
 	ethFees := dexeth.WeiToGwei(maxFeeRate) * defaultSendGasLimit
 	tokenFees := dexeth.WeiToGwei(maxFeeRate) * tokenGases.Transfer
 
@@ -4796,7 +5029,7 @@ func testEstimateSendTxFee(t *testing.T, assetID uint32) {
 	w, eth, node, shutdown := tassetWallet(assetID)
 	defer shutdown()
 
-	maxFeeRate, _, _ := eth.recommendedMaxFeeRate(eth.ctx)
+	maxFeeRate, _, _, _ := eth.recommendedMaxFeeRate(eth.ctx)
 	ethFees := dexeth.WeiToGwei(maxFeeRate) * defaultSendGasLimit
 	tokenFees := dexeth.WeiToGwei(maxFeeRate) * tokenGases.Transfer
 
@@ -5327,3 +5560,223 @@ func parseRecoveryID(c asset.Coin) []byte {
 func randomHash() common.Hash {
 	return common.BytesToHash(encode.RandomBytes(20))
 }
+
+func TestReconcileNonceViews(t *testing.T) {
+	tests := []struct {
+		name          string
+		views         []*nonceView
+		wantConfirmed uint64
+		wantPending   uint64
+		wantDivergent int
+	}{{
+		name:          "single provider",
+		views:         []*nonceView{{"a", 5, 7}},
+		wantConfirmed: 5,
+		wantPending:   7,
+	}, {
+		name:          "agreement",
+		views:         []*nonceView{{"a", 5, 7}, {"b", 5, 7}, {"c", 5, 7}},
+		wantConfirmed: 5,
+		wantPending:   7,
+	}, {
+		name:          "lagging provider",
+		views:         []*nonceView{{"a", 4, 9}, {"b", 5, 7}, {"c", 5, 7}},
+		wantConfirmed: 5,
+		wantPending:   7,
+		wantDivergent: 1,
+	}, {
+		name:          "one provider with phantom pending tx",
+		views:         []*nonceView{{"a", 5, 9}, {"b", 5, 7}, {"c", 5, 7}},
+		wantConfirmed: 5,
+		wantPending:   7,
+		wantDivergent: 1,
+	}, {
+		name:          "majority ahead",
+		views:         []*nonceView{{"a", 5, 9}, {"b", 5, 8}, {"c", 5, 7}},
+		wantConfirmed: 5,
+		wantPending:   8,
+		wantDivergent: 2,
+	}, {
+		name:          "two providers disagree",
+		views:         []*nonceView{{"a", 5, 8}, {"b", 5, 6}},
+		wantConfirmed: 5,
+		wantPending:   6,
+		wantDivergent: 1,
+	}, {
+		name:          "pending below confirmed",
+		views:         []*nonceView{{"a", 5, 3}},
+		wantConfirmed: 5,
+		wantPending:   5,
+		wantDivergent: 1,
+	}}
+	for _, tt := range tests {
+		confirmed, pending, divergent := reconcileNonceViews(tt.views)
+		if confirmed != tt.wantConfirmed {
+			t.Fatalf("%s: wanted confirmed nonce %d, got %d", tt.name, tt.wantConfirmed, confirmed)
+		}
+		if pending != tt.wantPending {
+			t.Fatalf("%s: wanted pending nonce %d, got %d", tt.name, tt.wantPending, pending)
+		}
+		if len(divergent) != tt.wantDivergent {
+			t.Fatalf("%s: wanted %d divergent providers, got %d", tt.name, tt.wantDivergent, len(divergent))
+		}
+	}
+}
+
+func TestNonceManager(t *testing.T) {
+	newTx := func(nonce int64) *extendedWalletTx {
+		return &extendedWalletTx{
+			WalletTransaction: &asset.WalletTransaction{},
+			Nonce:             big.NewInt(nonce),
+			SubmissionTime:    uint64(time.Now().Unix()),
+		}
+	}
+	m := &nonceManager{pendingTxs: []*extendedWalletTx{newTx(3), newTx(4)}}
+
+	// Initialization at connect.
+	m.syncNonces(big.NewInt(3), big.NewInt(5))
+	if m.confirmedNonceAt.Int64() != 3 || m.nextNonceAt.Int64() != 5 {
+		t.Fatalf("wrong initial nonces %s, %s", m.confirmedNonceAt, m.nextNonceAt)
+	}
+	if n := m.nextNonce(); n.Int64() != 5 {
+		t.Fatalf("wanted next nonce 5, got %s", n)
+	}
+
+	// A lagging provider view doesn't move the confirmed nonce backwards.
+	m.syncNonces(big.NewInt(2), big.NewInt(2))
+	if m.confirmedNonceAt.Int64() != 3 || m.nextNonceAt.Int64() != 3 {
+		t.Fatalf("wrong nonces after lagging sync %s, %s", m.confirmedNonceAt, m.nextNonceAt)
+	}
+
+	m.addPendingTx(newTx(5))
+	if m.nextNonceAt.Int64() != 6 {
+		t.Fatalf("wanted next nonce at 6 after adding tx, got %s", m.nextNonceAt)
+	}
+	if n := m.nextNonce(); n.Int64() != 6 {
+		t.Fatalf("wanted next nonce 6, got %s", n)
+	}
+
+	// Stuck tx detection.
+	tx := m.pendingTxs[0]
+	if m.isStuck(tx) {
+		t.Fatalf("new tx is stuck")
+	}
+	tx.SubmissionTime = uint64(time.Now().Add(-stuckTxAge - time.Minute).Unix())
+	if !m.isStuck(tx) {
+		t.Fatalf("old unindexed tx not stuck")
+	}
+	tx.indexed = true
+	if m.isStuck(tx) {
+		t.Fatalf("indexed tx is stuck")
+	}
+	tx.indexed = false
+	m.syncNonces(big.NewInt(4), big.NewInt(7))
+	if m.isStuck(tx) {
+		t.Fatalf("tx below the confirmed nonce is stuck")
+	}
+}
+
+func TestBumpFees(t *testing.T) {
+	_, eth, node, shutdown := tassetWallet(BipID)
+	defer shutdown()
+
+	node.baseFee = dexeth.GweiToWei(10)
+	node.tip = dexeth.GweiToWei(2)
+
+	to := common.BytesToAddress(encode.RandomBytes(20))
+	signedTx := func(nonce, gasFeeCap, gasTipCap uint64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			Nonce:     nonce,
+			GasFeeCap: dexeth.GweiToWei(gasFeeCap),
+			GasTipCap: dexeth.GweiToWei(gasTipCap),
+			Gas:       50_000,
+			To:        &to,
+			ChainID:   node.chainConfig().ChainID,
+		}), signer, node.privKey)
+		if err != nil {
+			t.Fatalf("tx signing error: %v", err)
+		}
+		return tx
+	}
+
+	now := uint64(time.Now().Unix())
+	tx0 := eth.extendedTx(signedTx(0, 20, 1), asset.Swap, 1, nil)
+	tx0.SubmissionTime = now - 600
+	tx1 := eth.extendedTx(signedTx(1, 40, 1), asset.Redeem, 1, nil)
+	tx1.SubmissionTime = now - 600
+	minedTx := eth.extendedTx(signedTx(2, 20, 1), asset.Swap, 1, nil)
+	minedTx.BlockNumber = 1
+	eth.pendingTxs = []*extendedWalletTx{tx0, tx1, minedTx}
+	coinIDs := []dex.Bytes{tx0.txHash[:], tx1.txHash[:], minedTx.txHash[:]}
+
+	currentRate, suggestedRange, early, err := eth.PreBumpFees(coinIDs, 30)
+	if err != nil {
+		t.Fatalf("PreBumpFees error: %v", err)
+	}
+	if currentRate != 40 {
+		t.Fatalf("wrong current rate %d", currentRate)
+	}
+	// The minimum replacement rate for tx1 is 44 gwei.
+	if suggestedRange.Start.Y != 45 {
+		t.Fatalf("wrong suggested start rate %f", suggestedRange.Start.Y)
+	}
+	if early != nil {
+		t.Fatalf("unexpected early acceleration")
+	}
+
+	fees, err := eth.BumpFeesEstimate(coinIDs, 50)
+	if err != nil {
+		t.Fatalf("BumpFeesEstimate error: %v", err)
+	}
+	if expFees := uint64((50-20)*50_000 + (50-40)*50_000); fees != expFees {
+		t.Fatalf("wrong fees estimate. wanted %d, got %d", expFees, fees)
+	}
+
+	// Too low to replace tx1.
+	if _, err := eth.BumpFees(coinIDs[1:2], 42); err == nil {
+		t.Fatalf("no error for a fee rate too low to replace")
+	}
+
+	// Only tx0 needs replacing at 40 gwei.
+	replacement := signedTx(0, 40, 2)
+	node.sendTxTx = replacement
+	node.sentTxs = 0
+	replacementIDs, err := eth.BumpFees(coinIDs, 40)
+	if err != nil {
+		t.Fatalf("BumpFees error: %v", err)
+	}
+	if len(replacementIDs) != 1 || replacementIDs[0] != replacement.Hash().String() {
+		t.Fatalf("wrong replacement IDs %v", replacementIDs)
+	}
+	if node.sentTxs != 1 {
+		t.Fatalf("expected 1 tx sent, got %d", node.sentTxs)
+	}
+	if tx0.NonceReplacement != replacement.Hash().String() || !tx0.FeeReplacement {
+		t.Fatalf("replaced tx not marked as replaced")
+	}
+	if eth.pendingTxs[0].ID != replacement.Hash().String() || eth.pendingTxs[0].feesBumps != 1 {
+		t.Fatalf("replacement not tracked")
+	}
+
+	// The original coin ID now resolves to the replacement, which was just
+	// submitted.
+	eth.txDB.(*tTxDB).txToGet = tx0
+	_, _, early, err = eth.PreBumpFees(coinIDs[:1], 30)
+	if err != nil {
+		t.Fatalf("PreBumpFees error for replaced tx: %v", err)
+	}
+	if early == nil || !early.WasAccelerated {
+		t.Fatalf("expected early acceleration for recent replacement")
+	}
+
+	// Send error.
+	node.sendTxErr = errors.New("test error")
+	if _, err := eth.BumpFees(coinIDs[1:2], 50); err == nil {
+		t.Fatalf("no error for send error")
+	}
+
+	// Nothing unmined.
+	if _, err := eth.BumpFees(coinIDs[2:], 50); err == nil {
+		t.Fatalf("no error when no unmined txs")
+	}
+}
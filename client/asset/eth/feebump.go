@@ -0,0 +1,235 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// minTimeBeforeFeeBump is how long after a transaction or its last
+// replacement is broadcast before a fee bump is no longer considered early.
+const minTimeBeforeFeeBump = 2 * time.Minute
+
+var _ asset.FeeBumper = (*ETHWallet)(nil)
+var _ asset.FeeBumper = (*TokenWallet)(nil)
+
+// pendingTxsForCoins finds the pending transactions for the coin IDs, which
+// are transaction hashes. A coin ID of a transaction that has since been
+// replaced resolves to the pending transaction with the same nonce. Coin IDs
+// of mined or unknown transactions are skipped.
+//
+// nonceMtx must be held.
+func (w *assetWallet) pendingTxsForCoins(coinIDs []dex.Bytes) ([]*extendedWalletTx, error) {
+	txs := make([]*extendedWalletTx, 0, len(coinIDs))
+	have := make(map[common.Hash]bool, len(coinIDs))
+	add := func(pendingTx *extendedWalletTx) {
+		if pendingTx.BlockNumber > 0 || have[pendingTx.txHash] {
+			return
+		}
+		have[pendingTx.txHash] = true
+		txs = append(txs, pendingTx)
+	}
+	for _, coinID := range coinIDs {
+		if len(coinID) != common.HashLength {
+			return nil, fmt.Errorf("invalid coin ID %s", coinID)
+		}
+		txHash := common.BytesToHash(coinID)
+		if _, pendingTx := pendingTxWithID(txHash.String(), w.pendingTxs); pendingTx != nil {
+			add(pendingTx)
+			continue
+		}
+		wt, err := w.txDB.getTx(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("error getting transaction %s: %w", txHash, err)
+		}
+		if wt == nil || wt.Nonce == nil {
+			continue
+		}
+		for _, pendingTx := range w.pendingTxs {
+			if pendingTx.Nonce.Cmp(wt.Nonce) == 0 {
+				add(pendingTx)
+				break
+			}
+		}
+	}
+	return txs, nil
+}
+
+// minReplacementFeeRate is the lowest max fee rate that the network will
+// accept for a replacement of the transaction. Nodes require a replacement to
+// raise both the fee cap and the tip cap by at least 10%.
+func minReplacementFeeRate(tx interface{ GasFeeCap() *big.Int }) *big.Int {
+	r := new(big.Int).Mul(tx.GasFeeCap(), big.NewInt(11))
+	r.Div(r, big.NewInt(10))
+	return r.Add(r, big.NewInt(1))
+}
+
+// PreBumpFees returns the highest max fee rate of the unmined transactions
+// with the coin IDs, and a suggested range for the new fee rate. Part of the
+// asset.FeeBumper interface.
+func (w *assetWallet) PreBumpFees(coinIDs []dex.Bytes, feeSuggestion uint64) (uint64, *asset.XYRange, *asset.EarlyAcceleration, error) {
+	w.nonceMtx.RLock()
+	defer w.nonceMtx.RUnlock()
+	pendingTxs, err := w.pendingTxsForCoins(coinIDs)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if len(pendingTxs) == 0 {
+		return 0, nil, nil, errors.New("no unmined transactions to bump")
+	}
+
+	var currentRate, minRate uint64
+	var lastSubmission uint64
+	var wasBumped bool
+	for _, pendingTx := range pendingTxs {
+		tx, err := pendingTx.tx()
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("error decoding transaction %s: %w", pendingTx.ID, err)
+		}
+		if r := dexeth.WeiToGweiCeil(tx.GasFeeCap()); r > currentRate {
+			currentRate = r
+		}
+		if r := dexeth.WeiToGweiCeil(minReplacementFeeRate(tx)); r > minRate {
+			minRate = r
+		}
+		if pendingTx.SubmissionTime > lastSubmission {
+			lastSubmission = pendingTx.SubmissionTime
+			wasBumped = pendingTx.feesBumps > 0
+		}
+	}
+
+	startRate := minRate
+	if feeSuggestion > startRate {
+		startRate = feeSuggestion
+	}
+	suggestedRange := &asset.XYRange{
+		Start: asset.XYRangePoint{
+			Label: "Min",
+			X:     1,
+			Y:     float64(startRate),
+		},
+		End: asset.XYRangePoint{
+			Label: "5X",
+			X:     5,
+			Y:     float64(startRate * 5),
+		},
+		XUnit: "X",
+		YUnit: "gwei/gas",
+	}
+
+	var early *asset.EarlyAcceleration
+	if timePast := time.Since(time.Unix(int64(lastSubmission), 0)); timePast < minTimeBeforeFeeBump {
+		early = &asset.EarlyAcceleration{
+			TimePast:       uint64(timePast.Seconds()),
+			WasAccelerated: wasBumped,
+		}
+	}
+
+	return currentRate, suggestedRange, early, nil
+}
+
+// BumpFeesEstimate returns the most additional fees that would be paid if the
+// unmined transactions with the coin IDs are replaced at newFeeRate. Part of
+// the asset.FeeBumper interface.
+func (w *assetWallet) BumpFeesEstimate(coinIDs []dex.Bytes, newFeeRate uint64) (uint64, error) {
+	w.nonceMtx.RLock()
+	defer w.nonceMtx.RUnlock()
+	pendingTxs, err := w.pendingTxsForCoins(coinIDs)
+	if err != nil {
+		return 0, err
+	}
+	var extra uint64
+	for _, pendingTx := range pendingTxs {
+		tx, err := pendingTx.tx()
+		if err != nil {
+			return 0, fmt.Errorf("error decoding transaction %s: %w", pendingTx.ID, err)
+		}
+		if currentRate := dexeth.WeiToGweiCeil(tx.GasFeeCap()); newFeeRate > currentRate {
+			extra += (newFeeRate - currentRate) * tx.Gas()
+		}
+	}
+	return extra, nil
+}
+
+// BumpFees replaces the unmined transactions with the coin IDs with the same
+// transactions at a max fee rate of newFeeRate. Transactions that already pay
+// newFeeRate are not replaced. The IDs of the replacements are returned. Part
+// of the asset.FeeBumper interface.
+func (w *assetWallet) BumpFees(coinIDs []dex.Bytes, newFeeRate uint64) ([]string, error) {
+	w.nonceMtx.Lock()
+	defer w.nonceMtx.Unlock()
+	pendingTxs, err := w.pendingTxsForCoins(coinIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(pendingTxs) == 0 {
+		return nil, errors.New("no unmined transactions to bump")
+	}
+
+	_, networkTipRate, err := w.currentNetworkFees(w.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting network fees: %w", err)
+	}
+	maxFeeRate := dexeth.GweiToWei(newFeeRate)
+
+	replacementIDs := make([]string, 0, len(pendingTxs))
+	for _, pendingTx := range pendingTxs {
+		tx, err := pendingTx.tx()
+		if err != nil {
+			return replacementIDs, fmt.Errorf("error decoding transaction %s: %w", pendingTx.ID, err)
+		}
+		if tx.GasFeeCap().Cmp(maxFeeRate) >= 0 {
+			continue
+		}
+		if minRate := minReplacementFeeRate(tx); maxFeeRate.Cmp(minRate) < 0 {
+			return replacementIDs, fmt.Errorf("fee rate %d gwei is too low to replace transaction %s. need at least %d gwei",
+				newFeeRate, pendingTx.ID, dexeth.WeiToGweiCeil(minRate))
+		}
+		// The tip must also be raised by 10%.
+		tipRate := new(big.Int).Mul(tx.GasTipCap(), big.NewInt(11))
+		tipRate.Div(tipRate, big.NewInt(10)).Add(tipRate, big.NewInt(1))
+		if networkTipRate.Cmp(tipRate) > 0 {
+			tipRate.Set(networkTipRate)
+		}
+		if tipRate.Cmp(maxFeeRate) > 0 {
+			tipRate.Set(maxFeeRate)
+		}
+		addr := tx.To()
+		if addr == nil {
+			return replacementIDs, fmt.Errorf("transaction %s has no recipient", pendingTx.ID)
+		}
+		txOpts, err := w.node.txOpts(w.ctx, 0, tx.Gas(), maxFeeRate, tipRate, new(big.Int).SetUint64(tx.Nonce()))
+		if err != nil {
+			return replacementIDs, fmt.Errorf("error preparing tx opts: %w", err)
+		}
+		txOpts.Value = tx.Value()
+		newTx, err := w.node.sendTransaction(w.ctx, txOpts, *addr, tx.Data())
+		if err != nil {
+			return replacementIDs, fmt.Errorf("error sending replacement for transaction %s: %w", pendingTx.ID, err)
+		}
+
+		newPendingTx := w.extendAndStoreTx(newTx, pendingTx.Type, pendingTx.Amount, pendingTx.TokenID, pendingTx.Recipient)
+		newPendingTx.feesBumps = pendingTx.feesBumps + 1
+		pendingTx.NonceReplacement = newPendingTx.ID
+		pendingTx.FeeReplacement = true
+		w.tryStoreDBTx(pendingTx)
+		idx, _ := pendingTxWithID(pendingTx.ID, w.pendingTxs)
+		w.pendingTxs[idx] = newPendingTx
+		if pendingTx.actionRequested {
+			w.emit.ActionResolved(pendingTx.ID)
+		}
+		w.log.Infof("Replaced transaction %s with %s at a max fee rate of %d gwei",
+			pendingTx.ID, newPendingTx.ID, newFeeRate)
+		replacementIDs = append(replacementIDs, newPendingTx.ID)
+	}
+	return replacementIDs, nil
+}
@@ -898,18 +898,27 @@ func (m *multiRPCClient) transactionAndReceipt(ctx context.Context, txHash commo
 
 // nonce gets the best next nonce for the account.
 func (m *multiRPCClient) nonce(ctx context.Context) (confirmed, pending *big.Int, _ error) {
-	confirmed, pending = new(big.Int), new(big.Int)
-	return confirmed, pending, m.withAll(ctx, func(ctx context.Context, p *provider) error {
+	var views []*nonceView
+	err := m.withAll(ctx, func(ctx context.Context, p *provider) error {
 		confirmedAt, err := p.ec.NonceAt(ctx, m.creds.addr, nil)
-		if err == nil && confirmed.Uint64() < confirmedAt {
-			confirmed.SetUint64(confirmedAt)
+		if err != nil {
+			return err
 		}
 		pendingAt, err := p.ec.PendingNonceAt(ctx, m.creds.addr)
-		if err == nil && pending.Uint64() < pendingAt {
-			pending.SetUint64(pendingAt)
+		if err != nil {
+			return err
 		}
-		return err
+		views = append(views, &nonceView{host: p.host, confirmed: confirmedAt, pending: pendingAt})
+		return nil
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+	confirmedAt, pendingAt, divergent := reconcileNonceViews(views)
+	if len(divergent) > 0 {
+		m.log.Warnf("Providers %v disagree with reconciled nonces: confirmed %d, pending %d", divergent, confirmedAt, pendingAt)
+	}
+	return new(big.Int).SetUint64(confirmedAt), new(big.Int).SetUint64(pendingAt), nil
 }
 
 type rpcTransaction struct {
@@ -0,0 +1,161 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+const (
+	// nonceSyncInterval is how often the nonce manager re-syncs its nonces
+	// with the providers' views.
+	nonceSyncInterval = 5 * time.Minute
+	// stuckTxAge is how long a transaction at the confirmed nonce can go
+	// unseen by all providers before it is considered stuck. A stuck
+	// transaction blocks every transaction with a higher nonce, so it must be
+	// replaced.
+	stuckTxAge = 20 * time.Minute
+)
+
+// nonceView is a provider's view of the account's nonces.
+type nonceView struct {
+	host      string
+	confirmed uint64
+	pending   uint64
+}
+
+// reconcileNonceViews combines the nonce views of multiple providers. The
+// confirmed nonce is the highest reported, since a provider reporting a lower
+// confirmed nonce just hasn't caught up. The pending nonce is taken only from
+// providers that are synced to the confirmed nonce, and is the highest pending
+// nonce that a majority of them agree on. A transaction lingering in one
+// provider's mempool after being dropped by the others would otherwise open a
+// nonce gap that can't be filled. The hosts of providers that disagree with
+// the reconciled nonces are returned.
+func reconcileNonceViews(views []*nonceView) (confirmed, pending uint64, divergent []string) {
+	for _, v := range views {
+		if v.confirmed > confirmed {
+			confirmed = v.confirmed
+		}
+	}
+	pendings := make([]uint64, 0, len(views))
+	for _, v := range views {
+		if v.confirmed == confirmed {
+			pendings = append(pendings, v.pending)
+		}
+	}
+	sort.Slice(pendings, func(i, j int) bool { return pendings[i] > pendings[j] })
+	if len(pendings) > 0 {
+		pending = pendings[len(pendings)/2]
+	}
+	if pending < confirmed {
+		pending = confirmed
+	}
+	for _, v := range views {
+		if v.confirmed != confirmed || v.pending != pending {
+			divergent = append(divergent, v.host)
+		}
+	}
+	return confirmed, pending, divergent
+}
+
+// nonceManager tracks the account nonces and the transactions using pending
+// nonces. All fields are protected by the nonceMtx.
+type nonceManager struct {
+	nonceMtx   sync.RWMutex
+	pendingTxs []*extendedWalletTx
+	// confirmedNonceAt is the nonce of the next transaction to be mined.
+	confirmedNonceAt *big.Int
+	// nextNonceAt is the providers' reconciled pending nonce, advanced as
+	// transactions are sent.
+	nextNonceAt         *big.Int
+	recoveryRequestSent bool
+	lastNonceSync       time.Time
+}
+
+// nextNonce is the lowest nonce at or above the confirmed nonce that is not
+// used by a pending transaction.
+//
+// nonceMtx must be held.
+func (m *nonceManager) nextNonce() *big.Int {
+	n := new(big.Int).Set(m.confirmedNonceAt)
+	for _, pendingTx := range m.pendingTxs {
+		if pendingTx.Nonce.Cmp(n) < 0 {
+			continue
+		}
+		if pendingTx.Nonce.Cmp(n) == 0 {
+			n.Add(n, big.NewInt(1))
+		} else {
+			break
+		}
+	}
+	return n
+}
+
+// addPendingTx starts tracking a newly broadcast transaction.
+//
+// nonceMtx must be held.
+func (m *nonceManager) addPendingTx(pendingTx *extendedWalletTx) {
+	m.pendingTxs = append(m.pendingTxs, pendingTx)
+	if pendingTx.Nonce.Cmp(m.nextNonceAt) >= 0 {
+		m.nextNonceAt = new(big.Int).Add(pendingTx.Nonce, big.NewInt(1))
+	}
+}
+
+// syncNonces updates the nonces with the providers' reconciled view. The
+// confirmed nonce never goes backwards, since providers may lag. The pending
+// nonce is reset to the providers' view, so that a nonce gap reported earlier
+// by a since-corrected provider is forgotten. Our own pending transactions
+// are accounted for separately by findMissingNonces. syncNonces is also used
+// to initialize the nonces when the wallet connects, with pendingTxs loaded
+// from the database.
+//
+// nonceMtx must be held.
+func (m *nonceManager) syncNonces(confirmed, pending *big.Int) {
+	m.lastNonceSync = time.Now()
+	if m.confirmedNonceAt == nil || confirmed.Cmp(m.confirmedNonceAt) > 0 {
+		m.confirmedNonceAt = new(big.Int).Set(confirmed)
+	}
+	m.nextNonceAt = new(big.Int).Set(pending)
+	if m.nextNonceAt.Cmp(m.confirmedNonceAt) < 0 {
+		m.nextNonceAt.Set(m.confirmedNonceAt)
+	}
+}
+
+// isStuck checks whether the pending transaction is blocking the account. A
+// transaction is stuck if it is at the confirmed nonce, but hasn't been seen
+// by any provider for stuckTxAge.
+//
+// nonceMtx must be held.
+func (m *nonceManager) isStuck(pendingTx *extendedWalletTx) bool {
+	return pendingTx.Nonce.Cmp(m.confirmedNonceAt) == 0 && !pendingTx.indexed &&
+		pendingTx.BlockNumber == 0 && pendingTx.age() > stuckTxAge
+}
+
+// resyncNonces fetches the providers' nonce view if it's been nonceSyncInterval
+// since the last sync. If a missing nonces action was requested, and there
+// are no missing nonces after the sync, the request is resolved.
+//
+// nonceMtx must be held.
+func (w *baseWallet) resyncNonces() {
+	if time.Since(w.lastNonceSync) < nonceSyncInterval {
+		return
+	}
+	confirmed, pending, err := w.node.nonce(w.ctx)
+	if err != nil {
+		w.log.Errorf("Error syncing nonces: %v", err)
+		return
+	}
+	w.syncNonces(confirmed, pending)
+	if w.recoveryRequestSent && len(findMissingNonces(w.confirmedNonceAt, w.nextNonceAt, w.pendingTxs)) == 0 {
+		w.log.Infof("Nonce gap resolved. Confirmed nonce %s, next nonce %s", w.confirmedNonceAt, w.nextNonceAt)
+		w.recoveryRequestSent = false
+		w.requestAction(asset.ActionResolved, w.missingNoncesActionID(), nil, nil)
+	}
+}
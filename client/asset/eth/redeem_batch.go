@@ -0,0 +1,201 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/client/asset"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// redeemRequest is the set of redemptions from one Redeem call, waiting to be
+// sent, possibly in a transaction with the redemptions of other calls.
+type redeemRequest struct {
+	redemptions []*asset.Redemption
+	// done is closed when the request's transaction is sent or fails, after
+	// tx, batchSize and err are set.
+	done chan struct{}
+	tx   *types.Transaction
+	// batchSize is the total number of redemptions in tx.
+	batchSize int
+	err       error
+}
+
+// redeemQueue is the queue of redeem requests for one contract version.
+type redeemQueue struct {
+	// sending is true while a goroutine is sending the queued requests.
+	sending bool
+	pending []*redeemRequest
+}
+
+// batchRedeem sends the redemptions and returns the transaction and the total
+// number of redemptions in it. If no other redemption transaction of the
+// contract version is being sent, the redemptions are sent immediately.
+// Otherwise they are queued, and all the redemptions queued while a
+// transaction is being sent go together in the next transaction, up to the
+// number that fits in a transaction. Core ticks the trades of an asset
+// concurrently, so matches that become redeemable at the same block are
+// mostly redeemed together. Redemptions of a swap whose last redemption
+// transaction was rejected are always sent in their own transaction.
+func (w *assetWallet) batchRedeem(contractVer uint32, redemptions []*asset.Redemption) (*types.Transaction, int, error) {
+	req := &redeemRequest{
+		redemptions: redemptions,
+		done:        make(chan struct{}),
+	}
+
+	w.redeemBatchMtx.Lock()
+	if w.redeemAlone(redemptions) {
+		w.redeemBatchMtx.Unlock()
+		tx, err := w.sendRedeemTx(contractVer, redemptions)
+		return tx, len(redemptions), err
+	}
+	if w.redeemQueues == nil {
+		w.redeemQueues = make(map[uint32]*redeemQueue)
+	}
+	q := w.redeemQueues[contractVer]
+	if q == nil {
+		q = new(redeemQueue)
+		w.redeemQueues[contractVer] = q
+	}
+	q.pending = append(q.pending, req)
+	if !q.sending {
+		q.sending = true
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.sendRedeemQueue(contractVer, q)
+		}()
+	}
+	w.redeemBatchMtx.Unlock()
+
+	<-req.done
+	if req.err != nil {
+		return nil, 0, req.err
+	}
+	return req.tx, req.batchSize, nil
+}
+
+// redeemAlone checks whether any of the redemptions' swaps were last redeemed
+// in a rejected transaction, and if so forgets them, since they will now be
+// redeemed on their own. The redeemBatchMtx MUST be held.
+func (w *assetWallet) redeemAlone(redemptions []*asset.Redemption) bool {
+	var alone bool
+	for _, r := range redemptions {
+		var secretHash [32]byte
+		copy(secretHash[:], r.Spends.SecretHash)
+		if w.rejectedRedeems[secretHash] {
+			delete(w.rejectedRedeems, secretHash)
+			alone = true
+		}
+	}
+	return alone
+}
+
+// redeemRejected records that a redemption transaction for the swap was
+// rejected, so that the swap is redeemed in its own transaction next time,
+// and a bad redemption in a batch can't cause the others to fail again.
+func (w *assetWallet) redeemRejected(secretHash [32]byte) {
+	w.redeemBatchMtx.Lock()
+	defer w.redeemBatchMtx.Unlock()
+	if w.rejectedRedeems == nil {
+		w.rejectedRedeems = make(map[[32]byte]bool)
+	}
+	w.rejectedRedeems[secretHash] = true
+}
+
+// sendRedeemQueue sends the queued redeem requests in batches until the queue
+// is empty. If the wallet is shut down, the queued requests fail.
+func (w *assetWallet) sendRedeemQueue(contractVer uint32, q *redeemQueue) {
+	var maxRedeems int
+	if w.maxRedeemGas > 0 {
+		_, n := w.maxSwapsAndRedeems()
+		maxRedeems = int(n)
+	}
+	for {
+		w.redeemBatchMtx.Lock()
+		if len(q.pending) == 0 {
+			q.sending = false
+			w.redeemBatchMtx.Unlock()
+			return
+		}
+		select {
+		case <-w.ctx.Done():
+			reqs := q.pending
+			q.pending, q.sending = nil, false
+			w.redeemBatchMtx.Unlock()
+			for _, req := range reqs {
+				req.err = w.ctx.Err()
+				close(req.done)
+			}
+			return
+		default:
+		}
+		// A batch is never larger than fits in a transaction, but always has
+		// at least one request.
+		n, count := 1, len(q.pending[0].redemptions)
+		for ; n < len(q.pending); n++ {
+			count += len(q.pending[n].redemptions)
+			if maxRedeems > 0 && count > maxRedeems {
+				break
+			}
+		}
+		reqs := q.pending[:n:n]
+		q.pending = q.pending[n:]
+		w.redeemBatchMtx.Unlock()
+
+		w.sendRedeemBatch(contractVer, reqs)
+	}
+}
+
+// sendRedeemBatch sends the redemptions of the requests in a single
+// transaction. If that fails, each request's redemptions are retried in their
+// own transaction, so that one bad redemption does not fail the others.
+func (w *assetWallet) sendRedeemBatch(contractVer uint32, reqs []*redeemRequest) {
+	var redemptions []*asset.Redemption
+	for _, req := range reqs {
+		redemptions = append(redemptions, req.redemptions...)
+	}
+	tx, err := w.sendRedeemTx(contractVer, redemptions)
+	if err == nil || len(reqs) == 1 {
+		if err == nil && len(reqs) > 1 {
+			w.log.Infof("Sent %d redemptions in transaction %s", len(redemptions), tx.Hash())
+		}
+		for _, req := range reqs {
+			req.tx, req.batchSize, req.err = tx, len(redemptions), err
+			close(req.done)
+		}
+		return
+	}
+
+	w.log.Warnf("Error sending %d batched redemptions, retrying separately: %v", len(redemptions), err)
+	for _, req := range reqs {
+		req.tx, req.err = w.sendRedeemTx(contractVer, req.redemptions)
+		req.batchSize = len(req.redemptions)
+		close(req.done)
+	}
+}
+
+// sendRedeemTx sends a transaction redeeming the redemptions.
+func (w *assetWallet) sendRedeemTx(contractVer uint32, redemptions []*asset.Redemption) (*types.Transaction, error) {
+	if err := w.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g := w.gases(contractVer)
+	if g == nil {
+		return nil, fmt.Errorf("no gas table")
+	}
+	gasLimit := g.Redeem * uint64(len(redemptions))
+
+	// Fetch up-to-date fee rate, we'll want to use it instead of the form's
+	// FeeSuggestion since it better reflects current networking conditions.
+	maxFee, tipRate, _, err := w.recommendedMaxFeeRate(w.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching recommended max fee rate: %w", err)
+	}
+	maxFeeGwei := dexeth.WeiToGweiCeil(maxFee)
+	return w.redeem(w.ctx, redemptions, maxFeeGwei, tipRate, gasLimit, contractVer)
+}
@@ -0,0 +1,780 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package extwallet is a generic asset driver for wallets that run in an
+// external process. The external wallet implements a JSON-RPC 2.0 interface
+// over HTTP(S), and the driver translates asset.Wallet calls into requests.
+// This allows wallets and custodians that dcrdex doesn't support natively to
+// be used for trading by implementing the interface described here, and
+// registering the asset with Register, or with an extwallet entry in the
+// application config (see RegisterConfigured).
+//
+// Requests are POSTed to the configured URL, with HTTP basic authorization if
+// a user or password is configured. Parameters are always passed by name as
+// a JSON object. Binary data such as coin IDs, contracts, and secrets are
+// hex-encoded strings. Times are unix seconds. Amounts are in the asset's
+// atomic units, and fee rates are in atomic units per the asset's fee rate
+// unit (e.g. sats/vB). Unless noted, the semantics of each method are those of
+// the corresponding asset.Wallet method.
+//
+// Coins are objects {"id": hex, "txid": string, "value": int}. Audit info is
+// {"recipient": string, "expiration": int, "coin": coin, "contract": hex,
+// "secrethash": hex}. A redemption is {"spends": auditinfo, "secret": hex}.
+//
+//	getinfo {} -> {"protocolversion", "assetid", "name", "version"}
+//	bestblock {} -> {"height", "hash"}
+//	syncstatus {} -> {"synced", "targetheight", "blocks", "peers"}
+//	balance {} -> {"available", "immature", "locked"}
+//	fundorder {"version", "value", "maxswapcount", "maxfeerate", "immediate",
+//	  "feesuggestion", "options", "redeemversion", "redeemassetid"}
+//	  -> {"coins", "redeemscripts", "fees"}
+//	fundmultiorder {"version", "values": [{"value", "maxswapcount"}],
+//	  "maxfeerate", "feesuggestion", "options", "redeemversion",
+//	  "redeemassetid", "maxlock"} -> {"orders": [fundorder result], "fees"}
+//	maxorder {"lotsize", "feesuggestion", "assetversion", "maxfeerate",
+//	  "redeemversion", "redeemassetid"} -> {"lots", "value", "maxFees",
+//	  "realisticWorstCase", "realisticBestCase", "feeReservesPerLot"}
+//	preswap {"version", "lotsize", "lots", "maxfeerate", "immediate",
+//	  "feesuggestion", "selectedoptions", "redeemversion", "redeemassetid"}
+//	  -> {"estimate": maxorder result, "options"}
+//	preredeem {"version", "lots", "feesuggestion", "selectedoptions"}
+//	  -> {"estimate": {"realisticBestCase", "realisticWorstCase"}, "options"}
+//	returncoins {"coinids"} -> {}, where null coinids means all coins
+//	fundingcoins {"coinids"} -> {"coins"}
+//	swap {"version", "inputs", "contracts": [{"address", "value",
+//	  "secrethash", "locktime"}], "feerate", "lockchange", "options"}
+//	  -> {"receipts": [{"coin", "contract", "expiration", "signedrefund"}],
+//	  "changecoin", "fees"}
+//	redeem {"redemptions", "feesuggestion", "options"}
+//	  -> {"coinids", "out", "fees"}
+//	signmessage {"coin", "msg"} -> {"pubkeys", "sigs"}
+//	auditcontract {"coinid", "contract", "txdata", "rebroadcast"} -> auditinfo
+//	contractlocktimeexpired {"contract"} -> {"expired", "locktime"}
+//	locktimeexpired {"locktime"} -> {"expired"}
+//	findredemption {"coinid", "contract"}
+//	  -> {"found", "redemptioncoinid", "secret"}
+//	refund {"coinid", "contract", "feerate"} -> {"coinid"}
+//	depositaddress {} -> {"address"}
+//	redemptionaddress {} -> {"address"}
+//	ownsaddress {"address"} -> {"result"}
+//	validateaddress {"address"} -> {"result"}
+//	swapconfirmations {"coinid", "contract", "matchtime"} -> {"confs", "spent"}
+//	confirmredemption {"coinid", "redemption", "feesuggestion"}
+//	  -> {"confs", "req", "coinid"}
+//	send {"address", "value", "feerate"} -> coin
+//	singlelotswaprefundfees {"version", "feerate", "usesafetxsize"}
+//	  -> {"fees", "refundfees"}
+//	singlelotredeemfees {"version", "feerate"} -> {"fees"}
+//	standardsendfee {"feerate"} -> {"fees"}
+//	maxfundingfees {"numtrades", "feerate", "options"} -> {"fees"}
+//
+// findredemption must not block. The driver polls it until the redemption is
+// found. A wallet that cannot find or refund a coin because it is unknown or
+// already spent must respond with error code ErrCodeCoinNotFound, and a
+// wallet that doesn't support an operation responds with ErrCodeUnsupported.
+// Secret hashes are SHA-256 hashes of the secret.
+package extwallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+const (
+	// WalletTypeExternal is the wallet type of external wallets.
+	WalletTypeExternal = "external"
+
+	requestTimeout = 30 * time.Second
+	tipPollPeriod  = 5 * time.Second
+)
+
+var configOpts = []*asset.ConfigOption{
+	{
+		Key:         "rpcaddress",
+		DisplayName: "JSON-RPC URL",
+		Description: "The URL of the external wallet's JSON-RPC endpoint, e.g. https://127.0.0.1:7777",
+	},
+	{
+		Key:         "rpcuser",
+		DisplayName: "JSON-RPC Username",
+		Description: "Username for HTTP basic authorization, if required",
+	},
+	{
+		Key:         "rpcpassword",
+		DisplayName: "JSON-RPC Password",
+		Description: "Password for HTTP basic authorization, if required",
+		NoEcho:      true,
+	},
+	{
+		Key:         "rpccert",
+		DisplayName: "TLS Certificate",
+		Description: "Path to the external wallet's TLS certificate, if it is self-signed",
+	},
+}
+
+// Register registers an asset whose wallet is provided by an external process.
+// Register must be called before any wallets are loaded, and the asset must
+// not already have a registered driver.
+func Register(assetID uint32, name string, ui dex.UnitInfo, versions []uint32) {
+	asset.Register(assetID, NewDriver(assetID, name, ui, versions))
+}
+
+// RegisterConfigured registers an asset from an external wallet definition in
+// the application config. The definition has the form
+// assetID:conversionfactor[:name], e.g. "128:1000000000000:Monero". The asset
+// ID is the asset's BIP-0044 coin type, which determines its symbol, and must
+// not have a native driver. The conventional unit is the upper case symbol,
+// and the name defaults to it. Fee rates are displayed per byte. The asset has
+// a single version, 0.
+func RegisterConfigured(def string) error {
+	parts := strings.SplitN(def, ":", 3)
+	if len(parts) < 2 {
+		return fmt.Errorf("external wallet definition %q is not assetID:conversionfactor[:name]", def)
+	}
+	assetID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid asset ID %q: %w", parts[0], err)
+	}
+	symbol := dex.BipIDSymbol(uint32(assetID))
+	if symbol == "" {
+		return fmt.Errorf("asset ID %d is not a known coin type", assetID)
+	}
+	convFactor, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || convFactor == 0 {
+		return fmt.Errorf("invalid conversion factor %q", parts[1])
+	}
+	if asset.Asset(uint32(assetID)) != nil {
+		return fmt.Errorf("asset %s (%d) is already registered", symbol, assetID)
+	}
+	unit := strings.ToUpper(symbol)
+	name := unit
+	if len(parts) == 3 && parts[2] != "" {
+		name = parts[2]
+	}
+	ui := dex.UnitInfo{
+		AtomicUnit: "atoms",
+		Conventional: dex.Denomination{
+			Unit:             unit,
+			ConversionFactor: convFactor,
+		},
+		FeeRateDenom: "B",
+	}
+	Register(uint32(assetID), name, ui, []uint32{0})
+	return nil
+}
+
+// Driver implements asset.Driver for external wallets.
+type Driver struct {
+	assetID uint32
+	info    *asset.WalletInfo
+}
+
+// NewDriver creates a Driver for the asset.
+func NewDriver(assetID uint32, name string, ui dex.UnitInfo, versions []uint32) *Driver {
+	return &Driver{
+		assetID: assetID,
+		info: &asset.WalletInfo{
+			Name:              name,
+			SupportedVersions: versions,
+			UnitInfo:          ui,
+			AvailableWallets: []*asset.WalletDefinition{{
+				Type:        WalletTypeExternal,
+				Tab:         "External",
+				Description: "Connect to an external wallet over JSON-RPC",
+				ConfigOpts:  configOpts,
+				NoAuth:      true,
+			}},
+		},
+	}
+}
+
+// Open opens the external wallet.
+func (d *Driver) Open(cfg *asset.WalletConfig, logger dex.Logger, net dex.Network) (asset.Wallet, error) {
+	return NewWallet(d.assetID, d.info, cfg, logger)
+}
+
+// DecodeCoinID creates a human-readable representation of a coin ID. Coin IDs
+// are opaque to the driver, so the hex encoding is returned.
+func (d *Driver) DecodeCoinID(coinID []byte) (string, error) {
+	return dex.Bytes(coinID).String(), nil
+}
+
+// Info returns basic information about the wallet and asset.
+func (d *Driver) Info() *asset.WalletInfo {
+	return d.info
+}
+
+// ExchangeWallet is an asset.Wallet backed by an external wallet.
+type ExchangeWallet struct {
+	assetID     uint32
+	info        *asset.WalletInfo
+	log         dex.Logger
+	rpc         *rpcClient
+	emit        *asset.WalletEmitter
+	peersChange func(uint32, error)
+
+	ctx      context.Context
+	tip      atomic.Uint64
+	startTip uint64
+}
+
+var _ asset.Wallet = (*ExchangeWallet)(nil)
+
+// NewWallet constructs an ExchangeWallet for the asset. The wallet is not
+// contacted until Connect.
+func NewWallet(assetID uint32, info *asset.WalletInfo, cfg *asset.WalletConfig, logger dex.Logger) (*ExchangeWallet, error) {
+	if cfg.Type != WalletTypeExternal {
+		return nil, fmt.Errorf("unknown wallet type %q", cfg.Type)
+	}
+	endpoint := cfg.Settings["rpcaddress"]
+	if endpoint == "" {
+		return nil, errors.New("no external wallet URL configured")
+	}
+	rpc, err := newRPCClient(endpoint, cfg.Settings["rpcuser"], cfg.Settings["rpcpassword"], cfg.Settings["rpccert"])
+	if err != nil {
+		return nil, err
+	}
+	return &ExchangeWallet{
+		assetID:     assetID,
+		info:        info,
+		log:         logger,
+		rpc:         rpc,
+		emit:        cfg.Emit,
+		peersChange: cfg.PeersChange,
+		ctx:         context.Background(), // replaced in Connect
+	}, nil
+}
+
+func (w *ExchangeWallet) call(method string, params, result any) error {
+	return w.rpc.call(w.ctx, method, params, result)
+}
+
+// Connect connects to the external wallet, checks that it implements a
+// compatible protocol for the asset, and begins monitoring for new blocks.
+// Satisfies dex.Connector.
+func (w *ExchangeWallet) Connect(ctx context.Context) (*sync.WaitGroup, error) {
+	w.ctx = ctx
+	var info walletInfo
+	if err := w.call(methodGetInfo, nil, &info); err != nil {
+		return nil, fmt.Errorf("error getting external wallet info: %w", err)
+	}
+	if info.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("external wallet implements protocol version %d, expected %d",
+			info.ProtocolVersion, ProtocolVersion)
+	}
+	if info.AssetID != w.assetID {
+		return nil, fmt.Errorf("external wallet is for asset %d, not %d", info.AssetID, w.assetID)
+	}
+	var tip bestBlock
+	if err := w.call(methodBestBlock, nil, &tip); err != nil {
+		return nil, fmt.Errorf("error getting best block: %w", err)
+	}
+	w.tip.Store(tip.Height)
+	w.startTip = tip.Height
+	w.log.Infof("Connected to external %s wallet %q (%s) at height %d", w.info.Name, info.Name, info.Version, tip.Height)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.monitorBlocks(ctx)
+	}()
+	return &wg, nil
+}
+
+// monitorBlocks polls the external wallet for new blocks, emitting tip
+// changes.
+func (w *ExchangeWallet) monitorBlocks(ctx context.Context) {
+	ticker := time.NewTicker(tipPollPeriod)
+	defer ticker.Stop()
+	var lastPeers uint32
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		var tip bestBlock
+		if err := w.call(methodBestBlock, nil, &tip); err != nil {
+			w.log.Errorf("Error getting best block: %v", err)
+			continue
+		}
+		if w.tip.Swap(tip.Height) != tip.Height {
+			w.log.Debugf("New tip %d (%s)", tip.Height, tip.Hash)
+			w.emit.TipChange(tip.Height)
+		}
+		if w.peersChange == nil {
+			continue
+		}
+		var ss syncStatus
+		if err := w.call(methodSyncStatus, nil, &ss); err != nil {
+			w.peersChange(0, err)
+			continue
+		}
+		if ss.Peers != lastPeers {
+			lastPeers = ss.Peers
+			w.peersChange(ss.Peers, nil)
+		}
+	}
+}
+
+// Info returns basic information about the wallet and asset.
+func (w *ExchangeWallet) Info() *asset.WalletInfo {
+	return w.info
+}
+
+// Balance returns the wallet balance.
+func (w *ExchangeWallet) Balance() (*asset.Balance, error) {
+	var bal asset.Balance
+	return &bal, w.call(methodBalance, nil, &bal)
+}
+
+func fundOrderCoins(res *fundOrderResult) (asset.Coins, []dex.Bytes, error) {
+	if len(res.RedeemScripts) == 0 {
+		res.RedeemScripts = make([]dex.Bytes, len(res.Coins))
+	}
+	if len(res.RedeemScripts) != len(res.Coins) {
+		return nil, nil, fmt.Errorf("external wallet returned %d coins but %d redeem scripts",
+			len(res.Coins), len(res.RedeemScripts))
+	}
+	return assetCoins(res.Coins), res.RedeemScripts, nil
+}
+
+// FundOrder selects and locks coins for the order.
+func (w *ExchangeWallet) FundOrder(ord *asset.Order) (asset.Coins, []dex.Bytes, uint64, error) {
+	var res fundOrderResult
+	err := w.call(methodFundOrder, &fundOrderParams{
+		Version:       ord.Version,
+		Value:         ord.Value,
+		MaxSwapCount:  ord.MaxSwapCount,
+		MaxFeeRate:    ord.MaxFeeRate,
+		Immediate:     ord.Immediate,
+		FeeSuggestion: ord.FeeSuggestion,
+		Options:       ord.Options,
+		RedeemVersion: ord.RedeemVersion,
+		RedeemAssetID: ord.RedeemAssetID,
+	}, &res)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	coins, redeemScripts, err := fundOrderCoins(&res)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return coins, redeemScripts, res.Fees, nil
+}
+
+// FundMultiOrder funds multiple orders at once.
+func (w *ExchangeWallet) FundMultiOrder(ord *asset.MultiOrder, maxLock uint64) ([]asset.Coins, [][]dex.Bytes, uint64, error) {
+	values := make([]*multiOrderValue, 0, len(ord.Values))
+	for _, v := range ord.Values {
+		values = append(values, &multiOrderValue{Value: v.Value, MaxSwapCount: v.MaxSwapCount})
+	}
+	var res fundMultiOrderResult
+	err := w.call(methodFundMultiOrder, &fundMultiOrderParams{
+		Version:       ord.Version,
+		Values:        values,
+		MaxFeeRate:    ord.MaxFeeRate,
+		FeeSuggestion: ord.FeeSuggestion,
+		Options:       ord.Options,
+		RedeemVersion: ord.RedeemVersion,
+		RedeemAssetID: ord.RedeemAssetID,
+		MaxLock:       maxLock,
+	}, &res)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(res.Orders) > len(ord.Values) {
+		return nil, nil, 0, fmt.Errorf("external wallet funded %d orders, but only %d were requested",
+			len(res.Orders), len(ord.Values))
+	}
+	allCoins := make([]asset.Coins, 0, len(res.Orders))
+	allRedeemScripts := make([][]dex.Bytes, 0, len(res.Orders))
+	for _, o := range res.Orders {
+		coins, redeemScripts, err := fundOrderCoins(o)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		allCoins = append(allCoins, coins)
+		allRedeemScripts = append(allRedeemScripts, redeemScripts)
+	}
+	return allCoins, allRedeemScripts, res.Fees, nil
+}
+
+// MaxOrder generates information about the maximum order size and associated
+// fees that the wallet can support.
+func (w *ExchangeWallet) MaxOrder(form *asset.MaxOrderForm) (*asset.SwapEstimate, error) {
+	var est asset.SwapEstimate
+	return &est, w.call(methodMaxOrder, &maxOrderParams{
+		LotSize:       form.LotSize,
+		FeeSuggestion: form.FeeSuggestion,
+		AssetVersion:  form.AssetVersion,
+		MaxFeeRate:    form.MaxFeeRate,
+		RedeemVersion: form.RedeemVersion,
+		RedeemAssetID: form.RedeemAssetID,
+	}, &est)
+}
+
+// PreSwap gets order estimates based on the available funds.
+func (w *ExchangeWallet) PreSwap(form *asset.PreSwapForm) (*asset.PreSwap, error) {
+	var res asset.PreSwap
+	err := w.call(methodPreSwap, &preSwapParams{
+		Version:         form.Version,
+		LotSize:         form.LotSize,
+		Lots:            form.Lots,
+		MaxFeeRate:      form.MaxFeeRate,
+		Immediate:       form.Immediate,
+		FeeSuggestion:   form.FeeSuggestion,
+		SelectedOptions: form.SelectedOptions,
+		RedeemVersion:   form.RedeemVersion,
+		RedeemAssetID:   form.RedeemAssetID,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Estimate == nil {
+		return nil, errors.New("no swap estimate")
+	}
+	return &res, nil
+}
+
+// PreRedeem generates an estimate of the range of redemption fees that could
+// be assessed.
+func (w *ExchangeWallet) PreRedeem(form *asset.PreRedeemForm) (*asset.PreRedeem, error) {
+	var res asset.PreRedeem
+	err := w.call(methodPreRedeem, &preRedeemParams{
+		Version:         form.Version,
+		Lots:            form.Lots,
+		FeeSuggestion:   form.FeeSuggestion,
+		SelectedOptions: form.SelectedOptions,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Estimate == nil {
+		return nil, errors.New("no redeem estimate")
+	}
+	return &res, nil
+}
+
+func coinIDs(coins asset.Coins) []dex.Bytes {
+	ids := make([]dex.Bytes, 0, len(coins))
+	for _, c := range coins {
+		ids = append(ids, c.ID())
+	}
+	return ids
+}
+
+// ReturnCoins unlocks coins. A nil Coins unlocks all coins.
+func (w *ExchangeWallet) ReturnCoins(coins asset.Coins) error {
+	var ids []dex.Bytes
+	if coins != nil {
+		ids = coinIDs(coins)
+	}
+	return w.call(methodReturnCoins, &coinIDsParams{CoinIDs: ids}, nil)
+}
+
+// FundingCoins gets and locks funding coins for the coin IDs.
+func (w *ExchangeWallet) FundingCoins(ids []dex.Bytes) (asset.Coins, error) {
+	var res coinsResult
+	if err := w.call(methodFundingCoins, &coinIDsParams{CoinIDs: ids}, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Coins) != len(ids) {
+		return nil, fmt.Errorf("external wallet returned %d of %d funding coins", len(res.Coins), len(ids))
+	}
+	return assetCoins(res.Coins), nil
+}
+
+// Swap sends the swaps in a single transaction.
+func (w *ExchangeWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, uint64, error) {
+	inputs := make([]*coin, 0, len(swaps.Inputs))
+	for _, c := range swaps.Inputs {
+		inputs = append(inputs, wireCoin(c))
+	}
+	contracts := make([]*contract, 0, len(swaps.Contracts))
+	for _, c := range swaps.Contracts {
+		contracts = append(contracts, &contract{
+			Address:    c.Address,
+			Value:      c.Value,
+			SecretHash: c.SecretHash,
+			LockTime:   c.LockTime,
+		})
+	}
+	var res swapResult
+	err := w.call(methodSwap, &swapParams{
+		Version:    swaps.Version,
+		Inputs:     inputs,
+		Contracts:  contracts,
+		FeeRate:    swaps.FeeRate,
+		LockChange: swaps.LockChange,
+		Options:    swaps.Options,
+	}, &res)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(res.Receipts) != len(contracts) {
+		return nil, nil, 0, fmt.Errorf("external wallet returned %d receipts for %d contracts",
+			len(res.Receipts), len(contracts))
+	}
+	receipts := make([]asset.Receipt, 0, len(res.Receipts))
+	for _, r := range res.Receipts {
+		if r.Coin == nil {
+			return nil, nil, 0, errors.New("receipt has no swap coin")
+		}
+		receipts = append(receipts, &extReceipt{r})
+	}
+	var change asset.Coin
+	if res.ChangeCoin != nil {
+		change = res.ChangeCoin.toAsset()
+	}
+	return receipts, change, res.Fees, nil
+}
+
+func wireRedemption(r *asset.Redemption) *redemption {
+	return &redemption{
+		Spends: wireAuditInfo(r.Spends),
+		Secret: r.Secret,
+	}
+}
+
+// Redeem sends the redemption transaction.
+func (w *ExchangeWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error) {
+	redemptions := make([]*redemption, 0, len(form.Redemptions))
+	for _, r := range form.Redemptions {
+		redemptions = append(redemptions, wireRedemption(r))
+	}
+	var res redeemResult
+	err := w.call(methodRedeem, &redeemParams{
+		Redemptions:   redemptions,
+		FeeSuggestion: form.FeeSuggestion,
+		Options:       form.Options,
+	}, &res)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if res.Out == nil {
+		return nil, nil, 0, errors.New("no redemption output")
+	}
+	return res.CoinIDs, res.Out.toAsset(), res.Fees, nil
+}
+
+// SignMessage signs the message with the private key associated with the
+// specified Coin.
+func (w *ExchangeWallet) SignMessage(c asset.Coin, msg dex.Bytes) (pubkeys, sigs []dex.Bytes, err error) {
+	var res signMessageResult
+	if err := w.call(methodSignMessage, &signMessageParams{Coin: wireCoin(c), Msg: msg}, &res); err != nil {
+		return nil, nil, err
+	}
+	if len(res.PubKeys) != len(res.Sigs) {
+		return nil, nil, fmt.Errorf("external wallet returned %d pubkeys and %d signatures", len(res.PubKeys), len(res.Sigs))
+	}
+	return res.PubKeys, res.Sigs, nil
+}
+
+// AuditContract retrieves information about a swap contract.
+func (w *ExchangeWallet) AuditContract(coinID, contract, txData dex.Bytes, rebroadcast bool) (*asset.AuditInfo, error) {
+	var ai auditInfo
+	err := w.call(methodAuditContract, &auditContractParams{
+		CoinID:      coinID,
+		Contract:    contract,
+		TxData:      txData,
+		Rebroadcast: rebroadcast,
+	}, &ai)
+	if err != nil {
+		return nil, err
+	}
+	if ai.Coin == nil {
+		return nil, errors.New("audit info has no swap coin")
+	}
+	return ai.toAsset(), nil
+}
+
+// ContractLockTimeExpired returns true if the specified contract's locktime
+// has expired.
+func (w *ExchangeWallet) ContractLockTimeExpired(ctx context.Context, contract dex.Bytes) (bool, time.Time, error) {
+	var res lockTimeResult
+	if err := w.rpc.call(ctx, methodContractLockTimeExpired, &contractParams{Contract: contract}, &res); err != nil {
+		return false, time.Time{}, err
+	}
+	return res.Expired, time.Unix(res.LockTime, 0), nil
+}
+
+// LockTimeExpired returns true if the specified locktime has expired.
+func (w *ExchangeWallet) LockTimeExpired(ctx context.Context, lockTime time.Time) (bool, error) {
+	var res lockTimeResult
+	if err := w.rpc.call(ctx, methodLockTimeExpired, &lockTimeParams{LockTime: lockTime.Unix()}, &res); err != nil {
+		return false, err
+	}
+	return res.Expired, nil
+}
+
+// FindRedemption polls the external wallet until it finds the input that
+// spends the specified contract, or the context is canceled.
+func (w *ExchangeWallet) FindRedemption(ctx context.Context, coinID, contract dex.Bytes) (redemptionCoin, secret dex.Bytes, err error) {
+	for {
+		var res findRedemptionResult
+		err := w.rpc.call(ctx, methodFindRedemption, &swapParamsID{CoinID: coinID, Contract: contract}, &res)
+		if err != nil {
+			return nil, nil, err
+		}
+		if res.Found {
+			return res.RedemptionCoinID, res.Secret, nil
+		}
+		select {
+		case <-time.After(tipPollPeriod):
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("context canceled while searching for redemption of %s", coinID)
+		}
+	}
+}
+
+// Refund refunds a contract.
+func (w *ExchangeWallet) Refund(coinID, contract dex.Bytes, feeRate uint64) (dex.Bytes, error) {
+	var res coinIDResult
+	err := w.call(methodRefund, &refundParams{CoinID: coinID, Contract: contract, FeeRate: feeRate}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.CoinID, nil
+}
+
+// DepositAddress returns an address for depositing funds into the wallet.
+func (w *ExchangeWallet) DepositAddress() (string, error) {
+	var res addressResult
+	return res.Address, w.call(methodDepositAddress, nil, &res)
+}
+
+// OwnsDepositAddress indicates if the address belongs to the wallet.
+func (w *ExchangeWallet) OwnsDepositAddress(addr string) (bool, error) {
+	var res boolResult
+	return res.Result, w.call(methodOwnsAddress, &addressParams{Address: addr}, &res)
+}
+
+// RedemptionAddress gets an address for use in redeeming the counterparty's
+// swap.
+func (w *ExchangeWallet) RedemptionAddress() (string, error) {
+	var res addressResult
+	return res.Address, w.call(methodRedemptionAddress, nil, &res)
+}
+
+// SwapConfirmations gets the number of confirmations and the spend status for
+// the specified swap.
+func (w *ExchangeWallet) SwapConfirmations(ctx context.Context, coinID dex.Bytes, contract dex.Bytes, matchTime time.Time) (uint32, bool, error) {
+	var res swapConfsResult
+	err := w.rpc.call(ctx, methodSwapConfirmations, &swapConfsParams{
+		CoinID:    coinID,
+		Contract:  contract,
+		MatchTime: matchTime.Unix(),
+	}, &res)
+	return res.Confs, res.Spent, err
+}
+
+// ValidateSecret checks that the secret hashes to the secret hash.
+func (w *ExchangeWallet) ValidateSecret(secret, secretHash []byte) bool {
+	h := sha256.Sum256(secret)
+	return bytes.Equal(h[:], secretHash)
+}
+
+// SyncStatus is information about the blockchain sync status.
+func (w *ExchangeWallet) SyncStatus() (*asset.SyncStatus, error) {
+	var ss syncStatus
+	if err := w.call(methodSyncStatus, nil, &ss); err != nil {
+		return nil, err
+	}
+	return &asset.SyncStatus{
+		Synced:         ss.Synced,
+		TargetHeight:   ss.TargetHeight,
+		StartingBlocks: w.startTip,
+		Blocks:         ss.Blocks,
+	}, nil
+}
+
+// RegFeeConfirmations is not supported by external wallets.
+func (w *ExchangeWallet) RegFeeConfirmations(context.Context, dex.Bytes) (uint32, error) {
+	return 0, asset.ErrUnsupported
+}
+
+// Send sends the exact value to the specified address.
+func (w *ExchangeWallet) Send(addr string, value, feeRate uint64) (asset.Coin, error) {
+	var c coin
+	if err := w.call(methodSend, &sendParams{Address: addr, Value: value, FeeRate: feeRate}, &c); err != nil {
+		return nil, err
+	}
+	return c.toAsset(), nil
+}
+
+// ValidateAddress checks that the provided address is valid.
+func (w *ExchangeWallet) ValidateAddress(addr string) bool {
+	var res boolResult
+	if err := w.call(methodValidateAddress, &addressParams{Address: addr}, &res); err != nil {
+		w.log.Errorf("Error validating address: %v", err)
+		return false
+	}
+	return res.Result
+}
+
+// ConfirmRedemption checks the status of a redemption.
+func (w *ExchangeWallet) ConfirmRedemption(coinID dex.Bytes, r *asset.Redemption, feeSuggestion uint64) (*asset.ConfirmRedemptionStatus, error) {
+	var res confirmRedemptionResult
+	err := w.call(methodConfirmRedemption, &confirmRedemptionParams{
+		CoinID:        coinID,
+		Redemption:    wireRedemption(r),
+		FeeSuggestion: feeSuggestion,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &asset.ConfirmRedemptionStatus{
+		Confs:  res.Confs,
+		Req:    res.Req,
+		CoinID: res.CoinID,
+	}, nil
+}
+
+// SingleLotSwapRefundFees returns the fees for a swap and refund transaction
+// for a single lot.
+func (w *ExchangeWallet) SingleLotSwapRefundFees(version uint32, feeRate uint64, useSafeTxSize bool) (uint64, uint64, error) {
+	var res feesResult
+	err := w.call(methodSwapRefundFees, &feesParams{Version: version, FeeRate: feeRate, UseSafeTxSize: useSafeTxSize}, &res)
+	return res.Fees, res.RefundFees, err
+}
+
+// SingleLotRedeemFees returns the fees for a redeem transaction for a single
+// lot.
+func (w *ExchangeWallet) SingleLotRedeemFees(version uint32, feeRate uint64) (uint64, error) {
+	var res feesResult
+	return res.Fees, w.call(methodRedeemFees, &feesParams{Version: version, FeeRate: feeRate}, &res)
+}
+
+// StandardSendFee returns the fee for a "standard" send tx.
+func (w *ExchangeWallet) StandardSendFee(feeRate uint64) uint64 {
+	var res feesResult
+	if err := w.call(methodStandardSendFee, &feesParams{FeeRate: feeRate}, &res); err != nil {
+		w.log.Errorf("Error getting standard send fee: %v", err)
+		return 0
+	}
+	return res.Fees
+}
+
+// MaxFundingFees returns the max fees that could be paid for funding a swap.
+func (w *ExchangeWallet) MaxFundingFees(numTrades uint32, feeRate uint64, options map[string]string) uint64 {
+	var res feesResult
+	err := w.call(methodMaxFundingFees, &feesParams{NumTrades: numTrades, FeeRate: feeRate, Options: options}, &res)
+	if err != nil {
+		w.log.Errorf("Error getting max funding fees: %v", err)
+		return 0
+	}
+	return res.Fees
+}
@@ -0,0 +1,267 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package extwallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+const tAssetID = 1_000_001
+
+var tLogger = dex.StdOutLogger("T", dex.LevelTrace)
+
+type tRequest struct {
+	Method string          `json:"method"`
+	ID     uint64          `json:"id"`
+	Params json.RawMessage `json:"params"`
+}
+
+// tServer is an external wallet that responds with canned results.
+type tServer struct {
+	mtx     sync.Mutex
+	results map[string]any
+	errs    map[string]*RPCError
+	params  map[string]json.RawMessage
+	auth    string
+}
+
+func newTServer() *tServer {
+	return &tServer{
+		results: map[string]any{
+			methodGetInfo:   &walletInfo{ProtocolVersion: ProtocolVersion, AssetID: tAssetID, Name: "test", Version: "1.0"},
+			methodBestBlock: &bestBlock{Height: 100, Hash: "abcd"},
+		},
+		errs:   make(map[string]*RPCError),
+		params: make(map[string]json.RawMessage),
+	}
+}
+
+func (s *tServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req tRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.auth = r.Header.Get("Authorization")
+	s.params[req.Method] = req.Params
+	resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+	if rpcErr := s.errs[req.Method]; rpcErr != nil {
+		resp["error"] = rpcErr
+	} else if res, found := s.results[req.Method]; found {
+		resp["result"] = res
+	} else {
+		resp["error"] = &RPCError{Code: -32601, Message: "method not found"}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func tWallet(t *testing.T) (*ExchangeWallet, *tServer, func()) {
+	t.Helper()
+	srv := newTServer()
+	httpSrv := httptest.NewServer(srv)
+	drv := NewDriver(tAssetID, "Test", dex.UnitInfo{Conventional: dex.Denomination{ConversionFactor: 1e8}}, []uint32{0})
+	cfg := &asset.WalletConfig{
+		Type: WalletTypeExternal,
+		Settings: map[string]string{
+			"rpcaddress":  httpSrv.URL,
+			"rpcuser":     "user",
+			"rpcpassword": "pass",
+		},
+		Emit: asset.NewWalletEmitter(make(chan asset.WalletNotification, 16), tAssetID, tLogger),
+	}
+	wallet, err := drv.Open(cfg, tLogger, dex.Simnet)
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	return wallet.(*ExchangeWallet), srv, httpSrv.Close
+}
+
+func TestConnect(t *testing.T) {
+	w, srv, shutdown := tWallet(t)
+	defer shutdown()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv.results[methodGetInfo] = &walletInfo{ProtocolVersion: ProtocolVersion + 1, AssetID: tAssetID}
+	if _, err := w.Connect(ctx); err == nil {
+		t.Fatalf("no error for wrong protocol version")
+	}
+	srv.results[methodGetInfo] = &walletInfo{ProtocolVersion: ProtocolVersion, AssetID: tAssetID + 1}
+	if _, err := w.Connect(ctx); err == nil {
+		t.Fatalf("no error for wrong asset")
+	}
+	srv.results[methodGetInfo] = &walletInfo{ProtocolVersion: ProtocolVersion, AssetID: tAssetID}
+	wg, err := w.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	if w.tip.Load() != 100 {
+		t.Fatalf("wrong tip %d", w.tip.Load())
+	}
+	if srv.auth == "" {
+		t.Fatalf("no authorization header")
+	}
+	cancel()
+	wg.Wait()
+}
+
+func TestFundAndSwap(t *testing.T) {
+	w, srv, shutdown := tWallet(t)
+	defer shutdown()
+
+	fundCoin := &coin{ID: dex.Bytes{0x01}, TxID: "01", Value: 5e8}
+	srv.results[methodFundOrder] = &fundOrderResult{Coins: []*coin{fundCoin}, Fees: 100}
+	coins, redeemScripts, fees, err := w.FundOrder(&asset.Order{Value: 1e8, MaxSwapCount: 1})
+	if err != nil {
+		t.Fatalf("FundOrder error: %v", err)
+	}
+	if len(coins) != 1 || len(redeemScripts) != 1 || fees != 100 || coins[0].Value() != 5e8 {
+		t.Fatalf("wrong funding result")
+	}
+	var fundParams fundOrderParams
+	if err := json.Unmarshal(srv.params[methodFundOrder], &fundParams); err != nil {
+		t.Fatalf("error decoding fundorder params: %v", err)
+	}
+	if fundParams.Value != 1e8 || fundParams.MaxSwapCount != 1 {
+		t.Fatalf("wrong fundorder params %+v", fundParams)
+	}
+
+	srv.results[methodSwap] = &swapResult{
+		Receipts: []*receipt{{
+			Coin:       &coin{ID: dex.Bytes{0x02}, Value: 1e8},
+			Contract:   dex.Bytes{0x03},
+			Expiration: 1e9,
+		}},
+		ChangeCoin: &coin{ID: dex.Bytes{0x04}, Value: 4e8 - 200},
+		Fees:       200,
+	}
+	swaps := &asset.Swaps{
+		Inputs:    coins,
+		Contracts: []*asset.Contract{{Address: "addr", Value: 1e8, SecretHash: make([]byte, 32), LockTime: 1e9}},
+		FeeRate:   10,
+	}
+	receipts, change, fees, err := w.Swap(swaps)
+	if err != nil {
+		t.Fatalf("Swap error: %v", err)
+	}
+	if len(receipts) != 1 || receipts[0].Expiration().Unix() != 1e9 || change == nil || fees != 200 {
+		t.Fatalf("wrong swap result")
+	}
+	var sp swapParams
+	if err := json.Unmarshal(srv.params[methodSwap], &sp); err != nil {
+		t.Fatalf("error decoding swap params: %v", err)
+	}
+	if len(sp.Inputs) != 1 || sp.Inputs[0].ID.String() != "01" || len(sp.Contracts) != 1 {
+		t.Fatalf("wrong swap params %+v", sp)
+	}
+
+	// Receipt count mismatch.
+	swaps.Contracts = append(swaps.Contracts, swaps.Contracts[0])
+	if _, _, _, err := w.Swap(swaps); err == nil {
+		t.Fatalf("no error for missing receipt")
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	w, srv, shutdown := tWallet(t)
+	defer shutdown()
+
+	srv.errs[methodRefund] = &RPCError{Code: ErrCodeCoinNotFound, Message: "spent"}
+	if _, err := w.Refund(dex.Bytes{0x01}, dex.Bytes{0x02}, 10); !errors.Is(err, asset.CoinNotFoundError) {
+		t.Fatalf("expected CoinNotFoundError, got %v", err)
+	}
+	srv.errs[methodSend] = &RPCError{Code: ErrCodeUnsupported, Message: "no sends"}
+	if _, err := w.Send("addr", 1, 1); !errors.Is(err, asset.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+	srv.errs[methodBalance] = &RPCError{Code: 1, Message: "broken"}
+	if _, err := w.Balance(); err == nil {
+		t.Fatalf("no error for generic RPC error")
+	}
+	// Unimplemented method.
+	if _, err := w.DepositAddress(); err == nil {
+		t.Fatalf("no error for unknown method")
+	}
+}
+
+func TestFindRedemption(t *testing.T) {
+	w, srv, shutdown := tWallet(t)
+	defer shutdown()
+
+	secret := dex.Bytes{0x05}
+	srv.results[methodFindRedemption] = &findRedemptionResult{Found: true, RedemptionCoinID: dex.Bytes{0x06}, Secret: secret}
+	coinID, s, err := w.FindRedemption(context.Background(), dex.Bytes{0x01}, dex.Bytes{0x02})
+	if err != nil {
+		t.Fatalf("FindRedemption error: %v", err)
+	}
+	if coinID.String() != "06" || s.String() != "05" {
+		t.Fatalf("wrong redemption %s, %s", coinID, s)
+	}
+
+	// Not found polls until the context is canceled.
+	srv.mtx.Lock()
+	srv.results[methodFindRedemption] = &findRedemptionResult{}
+	srv.mtx.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := w.FindRedemption(ctx, dex.Bytes{0x01}, dex.Bytes{0x02}); err == nil {
+		t.Fatalf("no error for canceled search")
+	}
+}
+
+func TestRegisterConfigured(t *testing.T) {
+	for _, def := range []string{
+		"",
+		"128",
+		"xmr:1000000000000",
+		"128:0",
+		"128:abc",
+		"4294967295:100000000", // not a known coin type
+	} {
+		if err := RegisterConfigured(def); err == nil {
+			t.Fatalf("no error for definition %q", def)
+		}
+	}
+
+	const xmrID = 128
+	if err := RegisterConfigured("128:1000000000000:Monero"); err != nil {
+		t.Fatalf("RegisterConfigured error: %v", err)
+	}
+	ra := asset.Asset(xmrID)
+	if ra == nil {
+		t.Fatalf("asset not registered")
+	}
+	if ra.Symbol != "xmr" || ra.Info.Name != "Monero" {
+		t.Fatalf("wrong asset %s, %s", ra.Symbol, ra.Info.Name)
+	}
+	ui := ra.Info.UnitInfo
+	if ui.Conventional.Unit != "XMR" || ui.Conventional.ConversionFactor != 1e12 {
+		t.Fatalf("wrong unit info %+v", ui)
+	}
+	if _, err := asset.WalletDef(xmrID, WalletTypeExternal); err != nil {
+		t.Fatalf("WalletDef error: %v", err)
+	}
+	// The name defaults to the unit, and an asset can't be registered twice.
+	if err := RegisterConfigured("128:1000000000000"); err == nil {
+		t.Fatalf("no error for registering an asset twice")
+	}
+	const dashID = 5
+	if err := RegisterConfigured("5:100000000"); err != nil {
+		t.Fatalf("RegisterConfigured error: %v", err)
+	}
+	if name := asset.Asset(dashID).Info.Name; name != "DASH" {
+		t.Fatalf("wrong default name %q", name)
+	}
+}
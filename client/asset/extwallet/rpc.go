@@ -0,0 +1,519 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package extwallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+// ProtocolVersion is the version of the external wallet JSON-RPC protocol
+// spoken by this package. An external wallet reports the protocol version it
+// implements in its getinfo response.
+const ProtocolVersion = 0
+
+// JSON-RPC methods that an external wallet must implement.
+const (
+	methodGetInfo                 = "getinfo"
+	methodBestBlock               = "bestblock"
+	methodSyncStatus              = "syncstatus"
+	methodBalance                 = "balance"
+	methodFundOrder               = "fundorder"
+	methodFundMultiOrder          = "fundmultiorder"
+	methodMaxOrder                = "maxorder"
+	methodPreSwap                 = "preswap"
+	methodPreRedeem               = "preredeem"
+	methodReturnCoins             = "returncoins"
+	methodFundingCoins            = "fundingcoins"
+	methodSwap                    = "swap"
+	methodRedeem                  = "redeem"
+	methodSignMessage             = "signmessage"
+	methodAuditContract           = "auditcontract"
+	methodContractLockTimeExpired = "contractlocktimeexpired"
+	methodLockTimeExpired         = "locktimeexpired"
+	methodFindRedemption          = "findredemption"
+	methodRefund                  = "refund"
+	methodDepositAddress          = "depositaddress"
+	methodOwnsAddress             = "ownsaddress"
+	methodRedemptionAddress       = "redemptionaddress"
+	methodSwapConfirmations       = "swapconfirmations"
+	methodConfirmRedemption       = "confirmredemption"
+	methodSend                    = "send"
+	methodValidateAddress         = "validateaddress"
+	methodSwapRefundFees          = "singlelotswaprefundfees"
+	methodRedeemFees              = "singlelotredeemfees"
+	methodStandardSendFee         = "standardsendfee"
+	methodMaxFundingFees          = "maxfundingfees"
+)
+
+// Error codes that an external wallet uses to indicate conditions that the
+// DEX client must be able to recognize. Any other code is treated as a
+// generic error.
+const (
+	// ErrCodeCoinNotFound indicates that a coin could not be found, or is
+	// already spent. See the asset.CoinNotFoundError.
+	ErrCodeCoinNotFound = -32001
+	// ErrCodeUnsupported indicates that the wallet does not support the
+	// requested operation.
+	ErrCodeUnsupported = -32002
+)
+
+// RPCError is a JSON-RPC error object.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error satisfies the error interface.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("code %d: %s", e.Code, e.Message)
+}
+
+type request struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// rpcClient is an HTTP JSON-RPC 2.0 client. Parameters are always sent
+// by-name, as a JSON object.
+type rpcClient struct {
+	reqID      uint64
+	url        string
+	auth       string
+	httpClient *http.Client
+}
+
+func newRPCClient(endpoint, user, pass, certPath string) (*rpcClient, error) {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	if certPath != "" {
+		pem, err := os.ReadFile(certPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading TLS certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid TLS certificate file %q", certPath)
+		}
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    pool,
+				MinVersion: tls.VersionTLS12,
+			},
+		}
+	}
+	var auth string
+	if user != "" || pass != "" {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+	}
+	return &rpcClient{
+		url:        endpoint,
+		auth:       auth,
+		httpClient: httpClient,
+	}, nil
+}
+
+// call makes a JSON-RPC request. params should be a struct, or nil for methods
+// without parameters. The result is unmarshaled into result if it is non-nil.
+// RPC errors with a recognized code are translated to the corresponding
+// asset package errors.
+func (c *rpcClient) call(ctx context.Context, method string, params, result any) error {
+	if params == nil {
+		params = struct{}{}
+	}
+	reqB, err := json.Marshal(&request{
+		Jsonrpc: "2.0",
+		ID:      atomic.AddUint64(&c.reqID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding %s request: %w", method, err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(reqB))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.auth != "" {
+		httpReq.Header.Set("Authorization", c.auth)
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("%s: %d: %s", method, resp.StatusCode, string(b))
+	}
+	var jsonResp response
+	if err := json.NewDecoder(resp.Body).Decode(&jsonResp); err != nil {
+		return fmt.Errorf("error decoding %s response: %w", method, err)
+	}
+	if jsonResp.Error != nil {
+		switch jsonResp.Error.Code {
+		case ErrCodeCoinNotFound:
+			return fmt.Errorf("%s: %w: %s", method, asset.CoinNotFoundError, jsonResp.Error.Message)
+		case ErrCodeUnsupported:
+			return fmt.Errorf("%s: %w: %s", method, asset.ErrUnsupported, jsonResp.Error.Message)
+		}
+		return fmt.Errorf("%s: %w", method, jsonResp.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if len(jsonResp.Result) == 0 || bytes.Equal(jsonResp.Result, []byte("null")) {
+		return fmt.Errorf("%s: empty result", method)
+	}
+	return json.Unmarshal(jsonResp.Result, result)
+}
+
+// Wire types. Binary data (coin IDs, contracts, secrets, etc.) is encoded as
+// hex strings, and times as unix seconds.
+
+type walletInfo struct {
+	ProtocolVersion uint32 `json:"protocolversion"`
+	AssetID         uint32 `json:"assetid"`
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+}
+
+type bestBlock struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+type syncStatus struct {
+	Synced       bool   `json:"synced"`
+	TargetHeight uint64 `json:"targetheight"`
+	Blocks       uint64 `json:"blocks"`
+	Peers        uint32 `json:"peers"`
+}
+
+type coin struct {
+	ID    dex.Bytes `json:"id"`
+	TxID  string    `json:"txid"`
+	Value uint64    `json:"value"`
+}
+
+func (c *coin) toAsset() *extCoin {
+	return &extCoin{c}
+}
+
+// extCoin satisfies asset.Coin.
+type extCoin struct {
+	c *coin
+}
+
+var _ asset.Coin = (*extCoin)(nil)
+
+func (c *extCoin) ID() dex.Bytes  { return c.c.ID }
+func (c *extCoin) String() string { return c.c.ID.String() }
+func (c *extCoin) Value() uint64  { return c.c.Value }
+func (c *extCoin) TxID() string   { return c.c.TxID }
+
+func wireCoin(c asset.Coin) *coin {
+	if ec, is := c.(*extCoin); is {
+		return ec.c
+	}
+	return &coin{ID: c.ID(), TxID: c.TxID(), Value: c.Value()}
+}
+
+func assetCoins(cs []*coin) asset.Coins {
+	coins := make(asset.Coins, 0, len(cs))
+	for _, c := range cs {
+		coins = append(coins, c.toAsset())
+	}
+	return coins
+}
+
+type coinIDsParams struct {
+	CoinIDs []dex.Bytes `json:"coinids"`
+}
+
+type coinsResult struct {
+	Coins []*coin `json:"coins"`
+}
+
+type fundOrderParams struct {
+	Version       uint32            `json:"version"`
+	Value         uint64            `json:"value"`
+	MaxSwapCount  uint64            `json:"maxswapcount"`
+	MaxFeeRate    uint64            `json:"maxfeerate"`
+	Immediate     bool              `json:"immediate"`
+	FeeSuggestion uint64            `json:"feesuggestion"`
+	Options       map[string]string `json:"options,omitempty"`
+	RedeemVersion uint32            `json:"redeemversion"`
+	RedeemAssetID uint32            `json:"redeemassetid"`
+}
+
+type fundOrderResult struct {
+	Coins         []*coin     `json:"coins"`
+	RedeemScripts []dex.Bytes `json:"redeemscripts"`
+	Fees          uint64      `json:"fees"`
+}
+
+type multiOrderValue struct {
+	Value        uint64 `json:"value"`
+	MaxSwapCount uint64 `json:"maxswapcount"`
+}
+
+type fundMultiOrderParams struct {
+	Version       uint32             `json:"version"`
+	Values        []*multiOrderValue `json:"values"`
+	MaxFeeRate    uint64             `json:"maxfeerate"`
+	FeeSuggestion uint64             `json:"feesuggestion"`
+	Options       map[string]string  `json:"options,omitempty"`
+	RedeemVersion uint32             `json:"redeemversion"`
+	RedeemAssetID uint32             `json:"redeemassetid"`
+	MaxLock       uint64             `json:"maxlock"`
+}
+
+type fundMultiOrderResult struct {
+	Orders []*fundOrderResult `json:"orders"`
+	Fees   uint64             `json:"fees"`
+}
+
+type maxOrderParams struct {
+	LotSize       uint64 `json:"lotsize"`
+	FeeSuggestion uint64 `json:"feesuggestion"`
+	AssetVersion  uint32 `json:"assetversion"`
+	MaxFeeRate    uint64 `json:"maxfeerate"`
+	RedeemVersion uint32 `json:"redeemversion"`
+	RedeemAssetID uint32 `json:"redeemassetid"`
+}
+
+type preSwapParams struct {
+	Version         uint32            `json:"version"`
+	LotSize         uint64            `json:"lotsize"`
+	Lots            uint64            `json:"lots"`
+	MaxFeeRate      uint64            `json:"maxfeerate"`
+	Immediate       bool              `json:"immediate"`
+	FeeSuggestion   uint64            `json:"feesuggestion"`
+	SelectedOptions map[string]string `json:"selectedoptions,omitempty"`
+	RedeemVersion   uint32            `json:"redeemversion"`
+	RedeemAssetID   uint32            `json:"redeemassetid"`
+}
+
+type preRedeemParams struct {
+	Version         uint32            `json:"version"`
+	Lots            uint64            `json:"lots"`
+	FeeSuggestion   uint64            `json:"feesuggestion"`
+	SelectedOptions map[string]string `json:"selectedoptions,omitempty"`
+}
+
+type contract struct {
+	Address    string    `json:"address"`
+	Value      uint64    `json:"value"`
+	SecretHash dex.Bytes `json:"secrethash"`
+	LockTime   uint64    `json:"locktime"`
+}
+
+type swapParams struct {
+	Version    uint32            `json:"version"`
+	Inputs     []*coin           `json:"inputs"`
+	Contracts  []*contract       `json:"contracts"`
+	FeeRate    uint64            `json:"feerate"`
+	LockChange bool              `json:"lockchange"`
+	Options    map[string]string `json:"options,omitempty"`
+}
+
+type receipt struct {
+	Coin         *coin     `json:"coin"`
+	Contract     dex.Bytes `json:"contract"`
+	Expiration   int64     `json:"expiration"`
+	SignedRefund dex.Bytes `json:"signedrefund,omitempty"`
+}
+
+// extReceipt satisfies asset.Receipt.
+type extReceipt struct {
+	r *receipt
+}
+
+var _ asset.Receipt = (*extReceipt)(nil)
+
+func (r *extReceipt) Expiration() time.Time   { return time.Unix(r.r.Expiration, 0) }
+func (r *extReceipt) Coin() asset.Coin        { return r.r.Coin.toAsset() }
+func (r *extReceipt) Contract() dex.Bytes     { return r.r.Contract }
+func (r *extReceipt) SignedRefund() dex.Bytes { return r.r.SignedRefund }
+func (r *extReceipt) String() string {
+	return fmt.Sprintf("swap %s, contract %s", r.r.Coin.ID, r.r.Contract)
+}
+
+type swapResult struct {
+	Receipts   []*receipt `json:"receipts"`
+	ChangeCoin *coin      `json:"changecoin,omitempty"`
+	Fees       uint64     `json:"fees"`
+}
+
+type auditInfo struct {
+	Recipient  string    `json:"recipient"`
+	Expiration int64     `json:"expiration"`
+	Coin       *coin     `json:"coin"`
+	Contract   dex.Bytes `json:"contract"`
+	SecretHash dex.Bytes `json:"secrethash"`
+}
+
+func wireAuditInfo(ai *asset.AuditInfo) *auditInfo {
+	return &auditInfo{
+		Recipient:  ai.Recipient,
+		Expiration: ai.Expiration.Unix(),
+		Coin:       wireCoin(ai.Coin),
+		Contract:   ai.Contract,
+		SecretHash: ai.SecretHash,
+	}
+}
+
+func (ai *auditInfo) toAsset() *asset.AuditInfo {
+	return &asset.AuditInfo{
+		Recipient:  ai.Recipient,
+		Expiration: time.Unix(ai.Expiration, 0),
+		Coin:       ai.Coin.toAsset(),
+		Contract:   ai.Contract,
+		SecretHash: ai.SecretHash,
+	}
+}
+
+type redemption struct {
+	Spends *auditInfo `json:"spends"`
+	Secret dex.Bytes  `json:"secret"`
+}
+
+type redeemParams struct {
+	Redemptions   []*redemption     `json:"redemptions"`
+	FeeSuggestion uint64            `json:"feesuggestion"`
+	Options       map[string]string `json:"options,omitempty"`
+}
+
+type redeemResult struct {
+	CoinIDs []dex.Bytes `json:"coinids"`
+	Out     *coin       `json:"out"`
+	Fees    uint64      `json:"fees"`
+}
+
+type signMessageParams struct {
+	Coin *coin     `json:"coin"`
+	Msg  dex.Bytes `json:"msg"`
+}
+
+type signMessageResult struct {
+	PubKeys []dex.Bytes `json:"pubkeys"`
+	Sigs    []dex.Bytes `json:"sigs"`
+}
+
+type auditContractParams struct {
+	CoinID      dex.Bytes `json:"coinid"`
+	Contract    dex.Bytes `json:"contract"`
+	TxData      dex.Bytes `json:"txdata"`
+	Rebroadcast bool      `json:"rebroadcast"`
+}
+
+type contractParams struct {
+	Contract dex.Bytes `json:"contract"`
+}
+
+type lockTimeResult struct {
+	Expired  bool  `json:"expired"`
+	LockTime int64 `json:"locktime"`
+}
+
+type lockTimeParams struct {
+	LockTime int64 `json:"locktime"`
+}
+
+type swapParamsID struct {
+	CoinID   dex.Bytes `json:"coinid"`
+	Contract dex.Bytes `json:"contract"`
+}
+
+type findRedemptionResult struct {
+	Found            bool      `json:"found"`
+	RedemptionCoinID dex.Bytes `json:"redemptioncoinid,omitempty"`
+	Secret           dex.Bytes `json:"secret,omitempty"`
+}
+
+type refundParams struct {
+	CoinID   dex.Bytes `json:"coinid"`
+	Contract dex.Bytes `json:"contract"`
+	FeeRate  uint64    `json:"feerate"`
+}
+
+type coinIDResult struct {
+	CoinID dex.Bytes `json:"coinid"`
+}
+
+type addressParams struct {
+	Address string `json:"address"`
+}
+
+type addressResult struct {
+	Address string `json:"address"`
+}
+
+type boolResult struct {
+	Result bool `json:"result"`
+}
+
+type swapConfsParams struct {
+	CoinID    dex.Bytes `json:"coinid"`
+	Contract  dex.Bytes `json:"contract"`
+	MatchTime int64     `json:"matchtime"`
+}
+
+type swapConfsResult struct {
+	Confs uint32 `json:"confs"`
+	Spent bool   `json:"spent"`
+}
+
+type confirmRedemptionParams struct {
+	CoinID        dex.Bytes   `json:"coinid"`
+	Redemption    *redemption `json:"redemption"`
+	FeeSuggestion uint64      `json:"feesuggestion"`
+}
+
+type confirmRedemptionResult struct {
+	Confs  uint64    `json:"confs"`
+	Req    uint64    `json:"req"`
+	CoinID dex.Bytes `json:"coinid"`
+}
+
+type sendParams struct {
+	Address string `json:"address"`
+	Value   uint64 `json:"value"`
+	FeeRate uint64 `json:"feerate"`
+}
+
+type feesParams struct {
+	Version       uint32            `json:"version"`
+	FeeRate       uint64            `json:"feerate"`
+	UseSafeTxSize bool              `json:"usesafetxsize,omitempty"`
+	NumTrades     uint32            `json:"numtrades,omitempty"`
+	Options       map[string]string `json:"options,omitempty"`
+}
+
+type feesResult struct {
+	Fees       uint64 `json:"fees"`
+	RefundFees uint64 `json:"refundfees,omitempty"`
+}
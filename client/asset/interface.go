@@ -37,6 +37,7 @@ const (
 	WalletTraitHistorian                              // This wallet can return its transaction history
 	WalletTraitFundsMixer                             // The wallet can mix funds.
 	WalletTraitDynamicSwapper                         // The wallet has dynamic fees.
+	WalletTraitFeeBumper                              // The wallet can accelerate transactions by replacing them with higher fees.
 )
 
 // IsRescanner tests if the WalletTrait has the WalletTraitRescanner bit set.
@@ -150,6 +151,12 @@ func (wt WalletTrait) IsDynamicSwapper() bool {
 	return wt&WalletTraitDynamicSwapper != 0
 }
 
+// IsFeeBumper tests if the WalletTrait has the WalletTraitFeeBumper bit set,
+// which indicates the wallet implements the FeeBumper interface.
+func (wt WalletTrait) IsFeeBumper() bool {
+	return wt&WalletTraitFeeBumper != 0
+}
+
 // DetermineWalletTraits returns the WalletTrait bitset for the provided Wallet.
 func DetermineWalletTraits(w Wallet) (t WalletTrait) {
 	if _, is := w.(Rescanner); is {
@@ -206,6 +213,9 @@ func DetermineWalletTraits(w Wallet) (t WalletTrait) {
 	if _, is := w.(DynamicSwapper); is {
 		t |= WalletTraitDynamicSwapper
 	}
+	if _, is := w.(FeeBumper); is {
+		t |= WalletTraitFeeBumper
+	}
 	return t
 }
 
@@ -681,6 +691,27 @@ type Rescanner interface {
 	Rescan(ctx context.Context, bday /* unix time seconds */ uint64) error
 }
 
+// RescanProgress is the progress of a running rescan, in block heights.
+type RescanProgress struct {
+	// StartHeight is the height that the rescan started from.
+	StartHeight uint64 `json:"startHeight"`
+	// Height is the last height scanned.
+	Height uint64 `json:"height"`
+	// TargetHeight is the height at which the rescan will be complete.
+	TargetHeight uint64 `json:"targetHeight"`
+}
+
+// RescanProgresser is a Rescanner that reports the progress of a rescan
+// separately from its SyncStatus. Rescanners whose SyncStatus reflects the
+// rescan, with StartingBlocks, Blocks and TargetHeight, need not implement
+// RescanProgresser.
+type RescanProgresser interface {
+	Rescanner
+	// RescanProgress returns the progress of the running rescan, or nil if
+	// there is no rescan running.
+	RescanProgress() *RescanProgress
+}
+
 // Recoverer is a wallet implementation with recover functionality.
 type Recoverer interface {
 	// GetRecoveryCfg returns information that will help the wallet get back to
@@ -759,6 +790,17 @@ type FeeRater interface {
 	FeeRateSwap() (rate uint64, tooLow bool)
 }
 
+// TargetFeeRater is a FeeRater that can estimate the fee rate required for a
+// transaction to be mined within a number of blocks. Like FeeRate, the rate is
+// for non-critical transactions such as sends and withdraws, and is capped at
+// the user-configured limit.
+type TargetFeeRater interface {
+	FeeRater
+	// TargetFeeRate returns the fee rate required for a transaction to be
+	// mined within confTarget blocks.
+	TargetFeeRate(confTarget uint64) (uint64, error)
+}
+
 // FundsMixingStats describes the current state of a wallet's funds mixer.
 type FundsMixingStats struct {
 	// Enabled is true if the wallet is configured for funds mixing. The wallet
@@ -858,6 +900,25 @@ type Accelerator interface {
 		requiredForRemainingSwaps, feeSuggestion uint64) (uint64, *XYRange, *EarlyAcceleration, error)
 }
 
+// FeeBumper is a wallet that can accelerate its unmined transactions by
+// replacing them with the same transactions at a higher fee rate. This is the
+// counterpart of the Accelerator for account-based assets, where a
+// transaction can be replaced but not spent before it is mined.
+type FeeBumper interface {
+	// PreBumpFees returns the current fee rate of the unmined transactions
+	// with the coin IDs, and a suggested range for the new fee rate. Coin IDs
+	// of mined transactions are ignored. The feeSuggestion argument is the
+	// current prevailing network rate.
+	PreBumpFees(coinIDs []dex.Bytes, feeSuggestion uint64) (uint64, *XYRange, *EarlyAcceleration, error)
+	// BumpFeesEstimate returns the most additional fees that would be paid if
+	// the unmined transactions with the coin IDs are replaced at newFeeRate.
+	BumpFeesEstimate(coinIDs []dex.Bytes, newFeeRate uint64) (uint64, error)
+	// BumpFees replaces the unmined transactions with the coin IDs with the
+	// same transactions at newFeeRate, and returns the IDs of the
+	// replacements.
+	BumpFees(coinIDs []dex.Bytes, newFeeRate uint64) ([]string, error)
+}
+
 // TokenConfig is required to OpenTokenWallet.
 type TokenConfig struct {
 	// AssetID of the token.
@@ -975,6 +1036,37 @@ type TokenApprover interface {
 	ApprovalFee(assetVer uint32, approval bool) (uint64, error)
 }
 
+// TokenAllowance is the allowance granted to a version of a token's swap
+// contract to spend the wallet's tokens.
+type TokenAllowance struct {
+	Version uint32 `json:"version"`
+	// Spender is the address of the swap contract.
+	Spender string `json:"spender"`
+	// Allowance is the allowance in atomic units of the token. Allowance is
+	// zero if Unlimited is true.
+	Allowance uint64 `json:"allowance"`
+	// Unlimited is true if the allowance is too large to be expressed in
+	// atomic units, which is the case for the approvals sent by ApproveToken.
+	Unlimited bool           `json:"unlimited"`
+	Status    ApprovalStatus `json:"status"`
+}
+
+// TokenAllowanceManager is a TokenApprover that can report and resize the
+// allowances granted to the token's swap contracts.
+type TokenAllowanceManager interface {
+	TokenApprover
+	// TokenAllowances returns the allowance granted to each version of the
+	// token's swap contract.
+	TokenAllowances() ([]*TokenAllowance, error)
+	// SetTokenAllowance sends an approval transaction setting the allowance
+	// for a version of the token's swap contract. An allowance of zero revokes
+	// the approval. The onConfirm callback is called when the transaction is
+	// confirmed. Trading requires the unlimited allowance set by
+	// ApproveToken, so a version with a limited allowance will be reported as
+	// NotApproved.
+	SetTokenAllowance(assetVer uint32, allowance uint64, onConfirm func()) (string, error)
+}
+
 // TicketTransaction represents a ticket transaction.
 type TicketTransaction struct {
 	Hash        string `json:"hash"`
@@ -181,6 +181,12 @@ func mainCore() error {
 
 	<-clientCore.Ready()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		forwardDesktopNotifications(appCtx, clientCore)
+	}()
+
 	defer func() {
 		log.Info("Exiting bisonw main.")
 		cancel()  // no-op with clean rpc/web server setup
@@ -270,6 +270,12 @@ func mainCore() error {
 
 	<-clientCore.Ready()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		forwardDesktopNotifications(appCtx, clientCore)
+	}()
+
 	// TODO: on shutdown, stop market making and wait for trades to be
 	// canceled.
 	marketMaker, err := mm.NewMarketMaker(clientCore, cfg.MMConfig.EventLogDBPath, cfg.MMConfig.BotConfigPath, logMaker.Logger("MM"))
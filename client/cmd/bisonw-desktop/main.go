@@ -48,6 +48,7 @@ when the user simply shuts off their computer.
 package main
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"os"
@@ -55,6 +56,7 @@ import (
 	"runtime"
 
 	_ "decred.org/dcrdex/client/asset/importall"
+	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/dex"
 )
 
@@ -93,6 +95,22 @@ func storeTmpLogo() (tempDir string) {
 	return
 }
 
+// forwardDesktopNotifications sends the core notifications for the topics that
+// the user has opted in to as desktop notifications, so that they are seen
+// even when the window is closed or in the background.
+func forwardDesktopNotifications(ctx context.Context, clientCore *core.Core) {
+	feed := clientCore.DesktopNotificationFeed()
+	defer feed.ReturnFeed()
+	for {
+		select {
+		case n := <-feed.C:
+			sendDesktopNotification(n.Subject(), n.Details())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func main() {
 	// Wrap the actual main so defers run in it.
 	err := mainCore()
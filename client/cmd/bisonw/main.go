@@ -21,6 +21,7 @@ import (
 	"decred.org/dcrdex/client/asset"
 	_ "decred.org/dcrdex/client/asset/importall"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/ctlsock"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/client/rpcserver"
 	"decred.org/dcrdex/client/webserver"
@@ -42,9 +43,9 @@ func runCore(cfg *app.Config) error {
 	asset.SetNetwork(cfg.Net)
 
 	// If explicitly running without web server then you must run the rpc
-	// server.
-	if cfg.NoWeb && !cfg.RPCOn {
-		return fmt.Errorf("cannot run without web server unless --rpc is specified")
+	// server or the control socket.
+	if cfg.NoWeb && !cfg.RPCOn && cfg.CtlSocket == "" {
+		return fmt.Errorf("cannot run without web server unless --rpc or --ctlsocket is specified")
 	}
 
 	if cfg.CPUProfile != "" {
@@ -81,6 +82,15 @@ func runCore(cfg *app.Config) error {
 		}
 	}()
 
+	if cfg.Maintenance {
+		report, err := core.MaintainDB(cfg.Core(logMaker.Logger("CORE")))
+		if err != nil {
+			return fmt.Errorf("database maintenance error: %w", err)
+		}
+		log.Infof("Database maintenance complete. %d problems found.", len(report.Problems))
+		return nil
+	}
+
 	// Prepare the Core.
 	clientCore, err := core.New(cfg.Core(logMaker.Logger("CORE")))
 	if err != nil {
@@ -153,6 +163,33 @@ func runCore(cfg *app.Config) error {
 		}()
 	}
 
+	if cfg.CtlSocket != "" {
+		ctlSrv, err := ctlsock.New(&ctlsock.Config{
+			Path:        cfg.CtlSocket,
+			Core:        clientCore,
+			Version:     app.Version,
+			Shutdown:    cancel,
+			SetLogLevel: logMaker.UpdateLevels,
+			Logger:      logMaker.Logger("CTL"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create control socket server: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cm := dex.NewConnectionMaster(ctlSrv)
+			err := cm.Connect(appCtx)
+			if err != nil {
+				log.Errorf("Error starting control socket server: %v", err)
+				cancel()
+				return
+			}
+			cm.Wait()
+		}()
+	}
+
 	if !cfg.NoWeb {
 		webSrv, err := webserver.New(cfg.Web(clientCore, marketMaker, logMaker.Logger("WEB"), utc))
 		if err != nil {
@@ -38,8 +38,11 @@ type config struct {
 	Config       string   `short:"C" long:"config" description:"Path to configuration file"`
 	RPCUser      string   `short:"u" long:"rpcuser" description:"RPC username"`
 	RPCPass      string   `short:"P" long:"rpcpass" default-mask:"-" description:"RPC password"`
+	APIKey       string   `long:"apikey" default-mask:"-" description:"RPC API key to authenticate with instead of the RPC username and password"`
 	RPCAddr      string   `short:"a" long:"rpcaddr" description:"RPC server to connect to"`
 	RPCCert      string   `short:"c" long:"rpccert" description:"RPC server certificate chain for validation"`
+	ClientCert   string   `long:"clientcert" description:"TLS client certificate to authenticate with instead of the RPC username and password"`
+	ClientKey    string   `long:"clientkey" description:"Private key for the TLS client certificate"`
 	PrintJSON    bool     `short:"j" long:"json" description:"Print json messages sent and received"`
 	Proxy        string   `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser    string   `long:"proxyuser" description:"Username for proxy server"`
@@ -47,6 +50,7 @@ type config struct {
 	PasswordArgs []string `short:"p" long:"passarg" description:"Password arguments to bypass stdin prompts."`
 	Testnet      bool     `long:"testnet" description:"use testnet"`
 	Simnet       bool     `long:"simnet" description:"use simnet"`
+	CtlSocket    string   `long:"ctlsocket" description:"Path of the bisonw control socket. If set, the command is sent to the control socket instead of the RPC server. The commands are status, lock, unlock, shutdown [force], loglevel <level>, and backup."`
 }
 
 // configure parses command line options and a config file if present. Returns
@@ -101,6 +105,10 @@ func configure() (*config, []string, bool, error) {
 		return nil, nil, false, err
 	}
 
+	if cfg.CtlSocket != "" {
+		cfg.CtlSocket = dex.CleanAndExpandPath(cfg.CtlSocket)
+	}
+
 	if cfg.RPCCert == "" {
 		// Check in ~/.dexcctl first.
 		cfg.RPCCert = dex.CleanAndExpandPath(filepath.Join(appDir, defaultRPCCertFile))
@@ -112,6 +120,14 @@ func configure() (*config, []string, bool, error) {
 		cfg.RPCCert = dex.CleanAndExpandPath(cfg.RPCCert)
 	}
 
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		return nil, nil, false, fmt.Errorf("clientcert and clientkey must be specified together")
+	}
+	if cfg.ClientCert != "" {
+		cfg.ClientCert = dex.CleanAndExpandPath(cfg.ClientCert)
+		cfg.ClientKey = dex.CleanAndExpandPath(cfg.ClientKey)
+	}
+
 	if cfg.Simnet && cfg.Testnet {
 		return nil, nil, false, fmt.Errorf("simnet and testnet cannot both be specified")
 	}
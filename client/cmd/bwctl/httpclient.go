@@ -59,6 +59,13 @@ func newHTTPClient(cfg *config, urlStr string) (*http.Client, error) {
 		RootCAs:    pool,
 		ServerName: uri.Hostname(),
 	}
+	if cfg.ClientCert != "" {
+		clientCert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
 
 	// Create and return the new HTTP client potentially configured with a
 	// proxy and TLS.
@@ -89,8 +96,14 @@ func sendPostRequest(marshalledJSON []byte, cfg *config) (*msgjson.Message, erro
 	httpRequest.Close = true
 	httpRequest.Header.Set("Content-Type", "application/json")
 
-	// Configure basic access authorization.
-	httpRequest.SetBasicAuth(cfg.RPCUser, cfg.RPCPass)
+	// Configure API key or basic access authorization. A client certificate
+	// authenticates without either.
+	switch {
+	case cfg.APIKey != "":
+		httpRequest.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	case cfg.ClientCert == "" || cfg.RPCUser != "" || cfg.RPCPass != "":
+		httpRequest.SetBasicAuth(cfg.RPCUser, cfg.RPCPass)
+	}
 
 	// Create the new HTTP client that is configured according to the user-
 	// specified options and submit the request.
@@ -14,6 +14,7 @@ import (
 	"os"
 	"strings"
 
+	"decred.org/dcrdex/client/ctlsock"
 	"decred.org/dcrdex/client/rpcserver"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
@@ -58,6 +59,8 @@ var promptPasswords = map[string][]string{
 	"withdraw":          {"App password:"},
 	"send":              {"App password:"},
 	"appseed":           {"App password:"},
+	"restorebackup":     {"App seed:"},
+	"recoverfromseed":   {"Set new app password:", "App seed:"},
 	"startmarketmaking": {"App password:"},
 	"multitrade":        {"App password:"},
 	"purchasetickets":   {"App password:"},
@@ -167,6 +170,10 @@ func run(ctx context.Context) error {
 		params = append(params, arg)
 	}
 
+	if cfg.CtlSocket != "" {
+		return runCtlSocket(ctx, cfg, args[0], params)
+	}
+
 	// Prompt for passwords.
 	pws, err := promptPWs(ctx, args[0], cfg.PasswordArgs)
 	if err != nil {
@@ -214,22 +221,8 @@ func run(ctx context.Context) error {
 		return errors.New(resp.Error.Message)
 	}
 
-	// Choose how to display the result based on its type.
-	strResult := string(resp.Result)
-	if strings.HasPrefix(strResult, "{") || strings.HasPrefix(strResult, "[") {
-		var dst bytes.Buffer
-		if err := json.Indent(&dst, resp.Result, "", "  "); err != nil {
-			return fmt.Errorf("failed to format result: %v", err)
-		}
-		fmt.Println(dst.String())
-	} else if strings.HasPrefix(strResult, `"`) {
-		var str string
-		if err := json.Unmarshal(resp.Result, &str); err != nil {
-			return fmt.Errorf("failed to unmarshal result: %v", err)
-		}
-		fmt.Println(str)
-	} else if strResult != "null" {
-		fmt.Println(strResult)
+	if err := printResult(resp.Result); err != nil {
+		return err
 	}
 
 	// If this is a version check command, go the extra mile and check for
@@ -248,3 +241,55 @@ func run(ctx context.Context) error {
 	}
 	return nil
 }
+
+// runCtlSocket sends the command to the bisonw control socket instead of the
+// RPC server. The app password for the unlock command is prompted for if it
+// is not given with --passarg.
+func runCtlSocket(ctx context.Context, cfg *config, cmd string, params []string) error {
+	if cmd == ctlsock.CmdUnlock {
+		if len(params) > 0 {
+			return errors.New("the app password is prompted for, or may be given with --passarg")
+		}
+		var pw encode.PassBytes
+		switch len(cfg.PasswordArgs) {
+		case 0:
+			var err error
+			pw, err = admin.PasswordPrompt(ctx, "App password:")
+			if err != nil {
+				return err
+			}
+		case 1:
+			pw = encode.PassBytes(cfg.PasswordArgs[0])
+		default:
+			return fmt.Errorf("wrong number of command-line passwords, expected 1, got %d", len(cfg.PasswordArgs))
+		}
+		params = []string{string(pw)}
+		pw.Clear()
+	}
+	res, err := ctlsock.Send(ctx, cfg.CtlSocket, cmd, params...)
+	if err != nil {
+		return err
+	}
+	return printResult(res)
+}
+
+// printResult prints a result, choosing how to display it based on its type.
+func printResult(result json.RawMessage) error {
+	strResult := string(result)
+	if strings.HasPrefix(strResult, "{") || strings.HasPrefix(strResult, "[") {
+		var dst bytes.Buffer
+		if err := json.Indent(&dst, result, "", "  "); err != nil {
+			return fmt.Errorf("failed to format result: %v", err)
+		}
+		fmt.Println(dst.String())
+	} else if strings.HasPrefix(strResult, `"`) {
+		var str string
+		if err := json.Unmarshal(result, &str); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %v", err)
+		}
+		fmt.Println(str)
+	} else if strResult != "null" && strResult != "" {
+		fmt.Println(strResult)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+//go:build sqlite
+
+// sqlitemigrate copies a Bison Wallet bolt database into a new SQLite database.
+// The application must not be running. Build with -tags sqlite, which requires
+// cgo.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"decred.org/dcrdex/client/db/bolt"
+	"decred.org/dcrdex/client/db/sqlite"
+	"decred.org/dcrdex/dex"
+)
+
+func main() {
+	var srcPath, dstPath string
+	flag.StringVar(&srcPath, "src", "", "Path to the existing bolt database file (dexc.db)")
+	flag.StringVar(&dstPath, "dst", "", "Path of the SQLite database file to create")
+	flag.Parse()
+
+	if srcPath == "" || dstPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if _, err := os.Stat(srcPath); err != nil {
+		fmt.Fprintf(os.Stderr, "bad source database: %v\n", err)
+		os.Exit(1)
+	}
+
+	log := dex.StdOutLogger("MIGRATE", dex.LevelInfo)
+
+	src, err := bolt.NewDB(srcPath, log.SubLogger("BOLT"), bolt.Opts{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error opening source database: %v\n", err)
+		os.Exit(1)
+	}
+	defer src.(*bolt.BoltDB).Close()
+
+	if err := sqlite.Migrate(src, dstPath, log.SubLogger("SQLITE")); err != nil {
+		fmt.Fprintf(os.Stderr, "migration failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Migrated %s to %s\n", srcPath, dstPath)
+}
@@ -7,13 +7,14 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -21,6 +22,7 @@ import (
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
+	dexws "decred.org/dcrdex/dex/ws"
 	"github.com/gorilla/websocket"
 )
 
@@ -40,8 +42,16 @@ const (
 	// DefaultResponseTimeout is the default timeout for responses after a
 	// request is successfully sent.
 	DefaultResponseTimeout = time.Minute
+
+	// rttSmoothing is the inverse weight of a new round-trip time sample in
+	// the moving average.
+	rttSmoothing = 8
 )
 
+// quicDialTimeout is how long to wait for a QUIC connection before falling back
+// to a websocket. It is a var to facilitate testing.
+var quicDialTimeout = 5 * time.Second
+
 // ConnectionStatus represents the current status of the websocket connection.
 type ConnectionStatus uint32
 
@@ -99,6 +109,74 @@ type WsConn interface {
 	Connect(ctx context.Context) (*sync.WaitGroup, error)
 	MessageSource() <-chan *msgjson.Message
 	UpdateURL(string)
+	SetAltEndpoints([]*WsEndpoint)
+	Stats() *ConnectionStats
+}
+
+// WsEndpoint is an alternate address for the server of a WsConn, e.g. an onion
+// address or a regional endpoint.
+type WsEndpoint struct {
+	// URL is the websocket endpoint URL.
+	URL string
+	// NetDialContext specifies an optional dialer context to use for this
+	// endpoint.
+	NetDialContext func(context.Context, string, string) (net.Conn, error)
+}
+
+// endpoint is a WsEndpoint with health measurements used to pick the endpoint
+// for the next connection attempt.
+type endpoint struct {
+	WsEndpoint
+	// failures is the number of consecutive failed connection attempts or
+	// lost connections.
+	failures int
+	// avgRTT is a moving average of request round-trip times while connected
+	// to this endpoint.
+	avgRTT time.Duration
+	// quicPort is the QUIC port advertised by the server in the most recent
+	// websocket handshake with the endpoint, or empty if none was.
+	quicPort string
+	// quicFailed is set when a QUIC connection to the endpoint fails, which
+	// probably means that UDP is blocked.
+	quicFailed bool
+}
+
+// ConnectionStats are measurements of the quality of a WsConn's connection.
+// Round-trip times are measured from the sending of a request to the receipt
+// of its response, so they include the server's handling time. A growing
+// Backlog indicates that the consumer of MessageSource is not keeping up,
+// rather than a problem with the server or the network.
+type ConnectionStats struct {
+	// LastRTT is the round-trip time of the most recently answered request.
+	LastRTT time.Duration `json:"lastRTT"`
+	// AvgRTT is a moving average of request round-trip times.
+	AvgRTT time.Duration `json:"avgRTT"`
+	// MaxRTT is the longest round-trip time observed.
+	MaxRTT time.Duration `json:"maxRTT"`
+	// Responses is the number of requests that received a response.
+	Responses uint64 `json:"responses"`
+	// TimedOut is the number of requests that expired without a response.
+	TimedOut uint64 `json:"timedOut"`
+	// Reconnects is the number of successful reconnects.
+	Reconnects uint32 `json:"reconnects"`
+	// FailedReconnects is the number of failed reconnect attempts.
+	FailedReconnects uint32 `json:"failedReconnects"`
+	// PendingRequests is the number of requests awaiting a response.
+	PendingRequests int `json:"pendingRequests"`
+	// Backlog is the number of received messages waiting to be read from
+	// the MessageSource channel.
+	Backlog int `json:"backlog"`
+	// ConnectedSince is the time that the current connection was
+	// established. It is the zero time if the connection is down.
+	ConnectedSince time.Time `json:"connectedSince"`
+	// Endpoint is the URL of the current or most recent connection.
+	Endpoint string `json:"endpoint"`
+	// Failovers is the number of times a connection was established with a
+	// different endpoint than the previous connection.
+	Failovers uint32 `json:"failovers"`
+	// Transport is "quic" or "websocket" for the current or most recent
+	// connection.
+	Transport string `json:"transport"`
 }
 
 // When the DEX sends a request to the client, a responseHandler is created
@@ -107,6 +185,7 @@ type responseHandler struct {
 	expiration *time.Timer
 	f          func(*msgjson.Message)
 	abort      func() // only to be run at most once, and not if f ran
+	sent       time.Time
 }
 
 // WsCfg is the configuration struct for initializing a WsConn.
@@ -149,6 +228,41 @@ type WsCfg struct {
 
 	// EchoPingData will echo any data from pings as the pong data.
 	EchoPingData bool
+
+	// PreferCBOR requests the CBOR message encoding from the server. Servers
+	// that do not support it will not select the subprotocol, and JSON will
+	// be used.
+	PreferCBOR bool
+
+	// QUIC uses a QUIC connection instead of a websocket with servers that
+	// advertise QUIC in their websocket handshake response. The first
+	// connection to an endpoint is a websocket, and later connections, e.g.
+	// reconnects, use QUIC. The message encoding is negotiated in the QUIC
+	// handshake in the same way as for a websocket. If a QUIC connection
+	// fails, a websocket is used, and QUIC is not tried again with the
+	// endpoint. QUIC is not used with endpoints that have a NetDialContext or
+	// a proxy, or without TLS.
+	QUIC bool
+
+	// AltEndpoints are alternate addresses for the same server. When
+	// connecting, the endpoint with the fewest consecutive failures is tried
+	// first, with ties going to the faster endpoint, then to URL, then to
+	// the order of AltEndpoints. If it fails, the others are tried in turn.
+	AltEndpoints []*WsEndpoint
+}
+
+// wsConnection is a connection to the server. It is satisfied by
+// *websocket.Conn and *ws.QUICConn.
+type wsConnection interface {
+	Subprotocol() string
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	SetReadLimit(limit int64)
+	SetPingHandler(h func(appData string) error)
+	ReadMessage() (int, []byte, error)
+	WriteMessage(messageType int, data []byte) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	Close() error
 }
 
 // wsConn represents a client websocket connection.
@@ -162,10 +276,17 @@ type wsConn struct {
 	cfg    *WsCfg
 	tlsCfg *tls.Config
 	readCh chan *msgjson.Message
-	urlV   atomic.Value // string
+
+	// endpoints[0] is the primary URL from the WsCfg. epIdx is the index of
+	// the current or most recent endpoint.
+	epMtx     sync.RWMutex
+	endpoints []*endpoint
+	epIdx     int
 
 	wsMtx sync.Mutex
-	ws    *websocket.Conn
+	ws    wsConnection
+	// encoding is the message encoding negotiated for ws.
+	encoding msgjson.Encoding
 
 	connectionStatus uint32 // atomic
 
@@ -173,6 +294,10 @@ type wsConn struct {
 	respHandlers map[uint64]*responseHandler
 
 	reconnectCh chan struct{} // trigger for immediate reconnect
+
+	statsMtx       sync.Mutex
+	stats          ConnectionStats
+	connectedSince time.Time
 }
 
 var _ WsConn = (*wsConn)(nil)
@@ -213,17 +338,133 @@ func NewWsConn(cfg *WsCfg) (WsConn, error) {
 		respHandlers: make(map[uint64]*responseHandler),
 		reconnectCh:  make(chan struct{}, 1),
 	}
-	conn.urlV.Store(cfg.URL)
+	conn.endpoints = []*endpoint{{WsEndpoint: WsEndpoint{
+		URL:            cfg.URL,
+		NetDialContext: cfg.NetDialContext,
+	}}}
+	conn.SetAltEndpoints(cfg.AltEndpoints)
 
 	return conn, nil
 }
 
+// UpdateURL updates the primary URL, which is used on the next connection
+// attempt.
 func (conn *wsConn) UpdateURL(uri string) {
-	conn.urlV.Store(uri)
+	conn.epMtx.Lock()
+	conn.endpoints[0].URL = uri
+	conn.epMtx.Unlock()
 }
 
+// SetAltEndpoints replaces the alternate endpoints. The health of endpoints
+// with unchanged URLs is retained. The change takes effect on the next
+// connection attempt.
+func (conn *wsConn) SetAltEndpoints(alts []*WsEndpoint) {
+	conn.epMtx.Lock()
+	defer conn.epMtx.Unlock()
+	cur := conn.endpoints[conn.epIdx].URL
+	old := make(map[string]*endpoint, len(conn.endpoints))
+	for _, ep := range conn.endpoints[1:] {
+		old[ep.URL] = ep
+	}
+	eps := []*endpoint{conn.endpoints[0]}
+	conn.epIdx = 0
+	for _, alt := range alts {
+		ep := &endpoint{WsEndpoint: *alt}
+		if prev, found := old[alt.URL]; found {
+			ep.failures, ep.avgRTT = prev.failures, prev.avgRTT
+		}
+		if alt.URL == cur {
+			conn.epIdx = len(eps)
+		}
+		eps = append(eps, ep)
+	}
+	conn.endpoints = eps
+}
+
+// url is the URL of the current or most recent endpoint.
 func (conn *wsConn) url() string {
-	return conn.urlV.Load().(string)
+	conn.epMtx.RLock()
+	defer conn.epMtx.RUnlock()
+	return conn.endpoints[conn.epIdx].URL
+}
+
+// endpointOrder returns the endpoint indexes in the order that they should be
+// tried. Endpoints with fewer consecutive failures are tried first. Among
+// endpoints with the same number of failures, endpoints with a measured
+// round-trip time are ordered by it, fastest first.
+func (conn *wsConn) endpointOrder() []int {
+	conn.epMtx.RLock()
+	defer conn.epMtx.RUnlock()
+	order := make([]int, len(conn.endpoints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := conn.endpoints[order[i]], conn.endpoints[order[j]]
+		if a.failures != b.failures {
+			return a.failures < b.failures
+		}
+		return a.avgRTT > 0 && b.avgRTT > 0 && a.avgRTT < b.avgRTT
+	})
+	return order
+}
+
+// endpointFailed records a failed connection attempt or a lost connection for
+// the current endpoint.
+func (conn *wsConn) endpointFailed() {
+	conn.epMtx.Lock()
+	conn.endpoints[conn.epIdx].failures++
+	conn.epMtx.Unlock()
+}
+
+// connectAny attempts to connect to each endpoint in order of health until a
+// connection is established. If all endpoints fail, the error from the first
+// attempt is returned, unless another endpoint failed with a certificate
+// error, which takes precedence since it requires user action.
+func (conn *wsConn) connectAny(ctx context.Context) error {
+	var firstErr, certErr error
+	for _, i := range conn.endpointOrder() {
+		conn.epMtx.Lock()
+		if i >= len(conn.endpoints) { // SetAltEndpoints shrank the list
+			conn.epMtx.Unlock()
+			continue
+		}
+		prevIdx := conn.epIdx
+		conn.epIdx = i
+		ep := conn.endpoints[i].WsEndpoint
+		conn.epMtx.Unlock()
+
+		err := conn.connect(ctx, &ep)
+		if err == nil {
+			conn.epMtx.Lock()
+			conn.endpoints[i].failures = 0
+			conn.epMtx.Unlock()
+			if i != prevIdx {
+				conn.log.Infof("Connected to alternate endpoint %s", ep.URL)
+				conn.statsMtx.Lock()
+				conn.stats.Failovers++
+				conn.statsMtx.Unlock()
+			}
+			return nil
+		}
+		conn.endpointFailed()
+		if ctx.Err() != nil {
+			return err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if certErr == nil && (errors.Is(err, ErrInvalidCert) || errors.Is(err, ErrCertRequired)) {
+			certErr = err
+		}
+		conn.log.Debugf("Failed to connect to %s: %v", ep.URL, err)
+	}
+	if certErr != nil {
+		conn.setConnectionStatus(InvalidCert)
+		return certErr
+	}
+	conn.setConnectionStatus(Disconnected)
+	return firstErr
 }
 
 // IsDown indicates if the connection is known to be down.
@@ -241,36 +482,64 @@ func (conn *wsConn) setConnectionStatus(status ConnectionStatus) {
 	}
 }
 
-// connect attempts to establish a websocket connection.
-func (conn *wsConn) connect(ctx context.Context) error {
-	dialer := &websocket.Dialer{
-		HandshakeTimeout: DefaultResponseTimeout,
-		TLSClientConfig:  conn.tlsCfg,
-	}
-	if conn.cfg.NetDialContext != nil {
-		dialer.NetDialContext = conn.cfg.NetDialContext
-	} else {
-		dialer.Proxy = http.ProxyFromEnvironment
+// connect attempts to establish a QUIC or websocket connection with the
+// endpoint. The connection status is not updated on failure. Use connectAny.
+func (conn *wsConn) connect(ctx context.Context, ep *WsEndpoint) error {
+	tlsCfg := conn.tlsCfg
+	if uri, err := url.Parse(ep.URL); err == nil && uri.Hostname() != tlsCfg.ServerName {
+		tlsCfg = tlsCfg.Clone()
+		tlsCfg.ServerName = uri.Hostname()
 	}
 
-	ws, _, err := dialer.DialContext(ctx, conn.url(), conn.cfg.ConnectHeaders)
-	if err != nil {
-		if isErrorInvalidCert(err) {
-			conn.setConnectionStatus(InvalidCert)
-			if len(conn.cfg.Cert) == 0 {
-				return dex.NewError(ErrCertRequired, err.Error())
+	var ws wsConnection
+	transport := "websocket"
+	if quicAddr := conn.quicAddr(ep); quicAddr != "" {
+		qc, err := conn.dialQUIC(ctx, quicAddr, tlsCfg)
+		switch {
+		case err == nil:
+			ws, transport = qc, "quic"
+		case errors.Is(err, ErrInvalidCert), errors.Is(err, ErrCertRequired), errors.Is(err, dexws.ErrQUICRejected):
+			// The server speaks QUIC. A websocket would fail the same way.
+			return err
+		default:
+			if ctx.Err() != nil {
+				return err
 			}
-			return dex.NewError(ErrInvalidCert, err.Error())
+			conn.log.Infof("QUIC connection to %s failed, using a websocket: %v", quicAddr, err)
+			conn.epMtx.Lock()
+			conn.endpoints[conn.epIdx].quicFailed = true
+			conn.epMtx.Unlock()
 		}
-		conn.setConnectionStatus(Disconnected)
-		return err
+	}
+
+	if ws == nil {
+		dialer := &websocket.Dialer{
+			HandshakeTimeout: DefaultResponseTimeout,
+			TLSClientConfig:  tlsCfg,
+		}
+		if ep.NetDialContext != nil {
+			dialer.NetDialContext = ep.NetDialContext
+		} else {
+			dialer.Proxy = http.ProxyFromEnvironment
+		}
+		if conn.cfg.PreferCBOR {
+			dialer.Subprotocols = []string{msgjson.CBORSubprotocol}
+		}
+
+		wsc, resp, err := dialer.DialContext(ctx, ep.URL, conn.cfg.ConnectHeaders)
+		if err != nil {
+			return conn.dialError(err)
+		}
+		ws = wsc
+		conn.setQUICPort(resp.Header.Get(dexws.QUICPortHeader))
 	}
 
 	// Set the initial read deadline for the first ping. Subsequent read
 	// deadlines are set in the ping handler.
-	err = ws.SetReadDeadline(time.Now().Add(conn.cfg.PingWait))
+	err := ws.SetReadDeadline(time.Now().Add(conn.cfg.PingWait))
 	if err != nil {
 		conn.log.Errorf("set read deadline failed: %v", err)
+		ws.Close()
 		return err
 	}
 
@@ -309,7 +578,15 @@ func (conn *wsConn) connect(ctx context.Context) error {
 		conn.close()
 	}
 	conn.ws = ws
+	conn.encoding = msgjson.EncodingFromSubprotocol(ws.Subprotocol())
 	conn.wsMtx.Unlock()
+	conn.statsMtx.Lock()
+	conn.connectedSince = time.Now()
+	conn.stats.Transport = transport
+	conn.statsMtx.Unlock()
+	if conn.cfg.PreferCBOR || conn.cfg.QUIC {
+		conn.log.Debugf("Using %s message encoding over %s with %s", conn.encoding, transport, ep.URL)
+	}
 
 	conn.setConnectionStatus(Connected)
 	conn.wg.Add(1)
@@ -325,6 +602,73 @@ func (conn *wsConn) connect(ctx context.Context) error {
 	return nil
 }
 
+// dialError converts certificate errors from a dial to ErrCertRequired or
+// ErrInvalidCert.
+func (conn *wsConn) dialError(err error) error {
+	if isErrorInvalidCert(err) {
+		if len(conn.cfg.Cert) == 0 {
+			return dex.NewError(ErrCertRequired, err.Error())
+		}
+		return dex.NewError(ErrInvalidCert, err.Error())
+	}
+	return err
+}
+
+// setQUICPort records the QUIC port advertised in a websocket handshake with
+// the current endpoint. An invalid port is ignored.
+func (conn *wsConn) setQUICPort(port string) {
+	if port != "" {
+		if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+			conn.log.Warnf("Invalid QUIC port %q advertised", port)
+			port = ""
+		}
+	}
+	conn.epMtx.Lock()
+	conn.endpoints[conn.epIdx].quicPort = port
+	conn.epMtx.Unlock()
+}
+
+// quicAddr is the host:port to dial for a QUIC connection to the endpoint, or
+// an empty string if QUIC should not be tried. QUIC is only tried if the
+// server advertised it in an earlier websocket handshake.
+func (conn *wsConn) quicAddr(ep *WsEndpoint) string {
+	if !conn.cfg.QUIC || ep.NetDialContext != nil {
+		return ""
+	}
+	conn.epMtx.RLock()
+	port, quicFailed := conn.endpoints[conn.epIdx].quicPort, conn.endpoints[conn.epIdx].quicFailed
+	conn.epMtx.RUnlock()
+	if port == "" || quicFailed {
+		return ""
+	}
+	uri, err := url.Parse(ep.URL)
+	if err != nil || (uri.Scheme != "wss" && uri.Scheme != "https") {
+		return ""
+	}
+	// QUIC cannot go through an HTTP proxy.
+	proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: "https", Host: uri.Host}})
+	if err != nil || proxyURL != nil {
+		return ""
+	}
+	return net.JoinHostPort(uri.Hostname(), port)
+}
+
+// dialQUIC dials a QUIC connection to the server, offering the CBOR encoding
+// first if it is preferred.
+func (conn *wsConn) dialQUIC(ctx context.Context, addr string, tlsCfg *tls.Config) (*dexws.QUICConn, error) {
+	protocols := []string{dexws.QUICProtocolJSON}
+	if conn.cfg.PreferCBOR {
+		protocols = []string{dexws.QUICProtocolCBOR, dexws.QUICProtocolJSON}
+	}
+	ctx, cancel := context.WithTimeout(ctx, quicDialTimeout)
+	defer cancel()
+	qc, err := dexws.DialQUIC(ctx, addr, tlsCfg, protocols, conn.cfg.ConnectHeaders)
+	if err != nil {
+		return nil, conn.dialError(err)
+	}
+	return qc, nil
+}
+
 func (conn *wsConn) SetReadLimit(limit int64) {
 	conn.wsMtx.Lock()
 	ws := conn.ws
@@ -336,6 +680,7 @@ func (conn *wsConn) SetReadLimit(limit int64) {
 
 func (conn *wsConn) handleReadError(err error) {
 	reconnect := func() {
+		conn.endpointFailed()
 		conn.setConnectionStatus(Disconnected)
 		if !conn.cfg.DisableAutoReconnect {
 			conn.reconnectCh <- struct{}{}
@@ -419,30 +764,34 @@ func (conn *wsConn) read(ctx context.Context) {
 
 		// The read itself does not require locking since only this goroutine
 		// uses read functions that are not safe for concurrent use.
-		err := ws.ReadJSON(msg)
+		frameType, msgBytes, err := ws.ReadMessage()
 		// Drop the read error on context cancellation.
 		if ctx.Err() != nil {
 			return
 		}
 		if err != nil {
-			var mErr *json.UnmarshalTypeError
-			if errors.As(err, &mErr) {
-				// JSON decode errors are not fatal, log and proceed.
-				conn.log.Errorf("json decode error: %v", mErr)
-				continue
-			}
 			conn.handleReadError(err)
 			return
 		}
+		// Binary frames are CBOR-encoded messages.
+		enc := msgjson.JSONEncoding
+		if frameType == websocket.BinaryMessage {
+			enc = msgjson.CBOREncoding
+		}
+		if err = msg.Decode(msgBytes, enc); err != nil {
+			// Decode errors are not fatal, log and proceed.
+			conn.log.Errorf("%s decode error: %v", enc, err)
+			continue
+		}
 
 		// If the message is a response, find the handler.
 		if msg.Type == msgjson.Response {
 			handler := conn.respHandler(msg.ID)
 			if handler == nil {
-				b, _ := json.Marshal(msg)
-				conn.log.Errorf("No handler found for response: %v", string(b))
+				conn.log.Errorf("No handler found for response: %v", msg)
 				continue
 			}
+			conn.recordRTT(time.Since(handler.sent))
 			// Run handlers in a goroutine so that other messages can be
 			// received. Include the handler goroutines in the WaitGroup to
 			// allow them to complete if the connection master desires.
@@ -471,7 +820,8 @@ func (conn *wsConn) keepAlive(ctx context.Context) {
 			}
 
 			conn.log.Infof("Attempting to reconnect to %s...", conn.url())
-			err := conn.connect(ctx)
+			err := conn.connectAny(ctx)
+			conn.recordReconnect(err == nil)
 			if err != nil {
 				conn.log.Errorf("Reconnect failed. Scheduling reconnect to %s in %.1f seconds.",
 					conn.url(), rcInt.Seconds())
@@ -511,7 +861,7 @@ func (conn *wsConn) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	var ctxInternal context.Context
 	ctxInternal, conn.cancel = context.WithCancel(ctx)
 
-	err := conn.connect(ctxInternal)
+	err := conn.connectAny(ctxInternal)
 	if err != nil {
 		// If the certificate is invalid or missing, do not start the reconnect
 		// loop, and return an error with no WaitGroup.
@@ -576,8 +926,8 @@ func (conn *wsConn) Stop() {
 // Send pushes outgoing messages over the websocket connection. Sending of the
 // message is synchronous, so a nil error guarantees that the message was
 // successfully sent. A non-nil error may indicate that the connection is known
-// to be down, the message failed to marshall to JSON, or writing to the
-// websocket link failed.
+// to be down, the message failed to marshall, or writing to the websocket link
+// failed.
 func (conn *wsConn) Send(msg *msgjson.Message) error {
 	if conn.IsDown() {
 		return fmt.Errorf("cannot send on a broken connection")
@@ -585,7 +935,7 @@ func (conn *wsConn) Send(msg *msgjson.Message) error {
 
 	// Marshal the Message first so that we don't send junk to the peer even if
 	// it fails to marshal completely, which gorilla/websocket.WriteJSON does.
-	b, err := json.Marshal(msg)
+	b, err := msg.Encode(conn.messageEncoding())
 	if err != nil {
 		conn.log.Errorf("Failed to marshal message: %v", err)
 		return err
@@ -593,7 +943,16 @@ func (conn *wsConn) Send(msg *msgjson.Message) error {
 	return conn.SendRaw(b)
 }
 
-// SendRaw sends a raw byte string over the websocket connection.
+// messageEncoding is the message encoding negotiated for the current
+// connection.
+func (conn *wsConn) messageEncoding() msgjson.Encoding {
+	conn.wsMtx.Lock()
+	defer conn.wsMtx.Unlock()
+	return conn.encoding
+}
+
+// SendRaw sends a raw byte string over the websocket connection. If a message
+// encoding other than JSON was negotiated, the bytes must have that encoding.
 func (conn *wsConn) SendRaw(b []byte) error {
 	if conn.IsDown() {
 		return fmt.Errorf("cannot send on a broken connection")
@@ -607,7 +966,11 @@ func (conn *wsConn) SendRaw(b []byte) error {
 		return err
 	}
 
-	err = conn.ws.WriteMessage(websocket.TextMessage, b)
+	frameType := websocket.TextMessage
+	if conn.encoding == msgjson.CBOREncoding {
+		frameType = websocket.BinaryMessage
+	}
+	err = conn.ws.WriteMessage(frameType, b)
 	if err != nil {
 		conn.log.Errorf("Send: WriteMessage error: %v", err)
 		return err
@@ -657,7 +1020,7 @@ func (conn *wsConn) RequestWithTimeout(msg *msgjson.Message, f func(*msgjson.Mes
 	if msg.Type != msgjson.Request {
 		return fmt.Errorf("Message is not a request: %v", msg.Type)
 	}
-	rawMsg, err := json.Marshal(msg)
+	rawMsg, err := msg.Encode(conn.messageEncoding())
 	if err != nil {
 		conn.log.Errorf("Failed to marshal message: %v", err)
 		return err
@@ -702,6 +1065,9 @@ func (conn *wsConn) logReq(id uint64, respHandler func(*msgjson.Message), expire
 		// (*wsLink).respHandler has not already retrieved the handler function
 		// for execution.
 		if conn.expire(id) {
+			conn.statsMtx.Lock()
+			conn.stats.TimedOut++
+			conn.statsMtx.Unlock()
 			expire()
 		}
 	}
@@ -709,6 +1075,7 @@ func (conn *wsConn) logReq(id uint64, respHandler func(*msgjson.Message), expire
 		expiration: time.AfterFunc(expireTime, doExpire),
 		f:          respHandler,
 		abort:      expire,
+		sent:       time.Now(),
 	}
 }
 
@@ -733,3 +1100,60 @@ func (conn *wsConn) respHandler(id uint64) *responseHandler {
 func (conn *wsConn) MessageSource() <-chan *msgjson.Message {
 	return conn.readCh
 }
+
+// recordRTT adds a request round-trip time to the connection stats.
+func (conn *wsConn) recordRTT(rtt time.Duration) {
+	conn.statsMtx.Lock()
+	st := &conn.stats
+	st.LastRTT = rtt
+	if st.Responses == 0 {
+		st.AvgRTT = rtt
+	} else {
+		st.AvgRTT += (rtt - st.AvgRTT) / rttSmoothing
+	}
+	if rtt > st.MaxRTT {
+		st.MaxRTT = rtt
+	}
+	st.Responses++
+	conn.statsMtx.Unlock()
+
+	conn.epMtx.Lock()
+	defer conn.epMtx.Unlock()
+	ep := conn.endpoints[conn.epIdx]
+	if ep.avgRTT == 0 {
+		ep.avgRTT = rtt
+	} else {
+		ep.avgRTT += (rtt - ep.avgRTT) / rttSmoothing
+	}
+}
+
+// recordReconnect counts a reconnect attempt in the connection stats.
+func (conn *wsConn) recordReconnect(success bool) {
+	conn.statsMtx.Lock()
+	defer conn.statsMtx.Unlock()
+	if success {
+		conn.stats.Reconnects++
+	} else {
+		conn.stats.FailedReconnects++
+	}
+}
+
+// Stats returns a snapshot of the connection's quality measurements.
+func (conn *wsConn) Stats() *ConnectionStats {
+	conn.reqMtx.RLock()
+	pending := len(conn.respHandlers)
+	conn.reqMtx.RUnlock()
+
+	conn.statsMtx.Lock()
+	stats := conn.stats
+	connectedSince := conn.connectedSince
+	conn.statsMtx.Unlock()
+
+	stats.PendingRequests = pending
+	stats.Backlog = len(conn.readCh)
+	stats.Endpoint = conn.url()
+	if !conn.IsDown() {
+		stats.ConnectedSince = connectedSince
+	}
+	return &stats
+}
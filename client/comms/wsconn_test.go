@@ -5,12 +5,15 @@ import (
 	"context"
 	"crypto/elliptic"
 	"encoding/hex"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -18,6 +21,7 @@ import (
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/ws"
 	"github.com/decred/dcrd/certgen"
 	"github.com/gorilla/websocket"
 )
@@ -486,3 +490,441 @@ func TestWsConn(t *testing.T) {
 		t.Error("read source should have been closed")
 	}
 }
+
+// cborEchoHandler responds to requests with the string payload and the
+// message encoding of the link, using the shared WSLink, which selects the CBOR
+// subprotocol if requested.
+func cborEchoHandler(ctx context.Context, t *testing.T, links chan<- *sync.WaitGroup) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.NewConnection(w, r, time.Minute)
+		if err != nil {
+			t.Errorf("NewConnection error: %v", err)
+			return
+		}
+		startEchoLink(ctx, t, r.RemoteAddr, conn, links)
+	}
+}
+
+// quicEchoHandler is like cborEchoHandler, but advertises the QUIC port in the
+// websocket handshake.
+func quicEchoHandler(ctx context.Context, t *testing.T, links chan<- *sync.WaitGroup, quicPort string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := http.Header{ws.QUICPortHeader: []string{quicPort}}
+		conn, err := ws.NewConnectionWithHeader(w, r, time.Minute, header)
+		if err != nil {
+			t.Errorf("NewConnection error: %v", err)
+			return
+		}
+		startEchoLink(ctx, t, r.RemoteAddr, conn, links)
+	}
+}
+
+// startEchoLink starts a WSLink on the connection that responds to requests
+// with the string payload and the message encoding of the link.
+func startEchoLink(ctx context.Context, t *testing.T, addr string, conn ws.Connection, links chan<- *sync.WaitGroup) {
+	var link *ws.WSLink
+	link = ws.NewWSLink(addr, conn, time.Minute, func(msg *msgjson.Message) *msgjson.Error {
+		var payload string
+		if err := msg.Unmarshal(&payload); err != nil {
+			return msgjson.NewError(msgjson.RPCParseError, "bad payload")
+		}
+		resp, _ := msgjson.NewResponse(msg.ID, payload+" "+link.Encoding().String(), nil)
+		if err := link.Send(resp); err != nil {
+			t.Errorf("Send error: %v", err)
+		}
+		return nil
+	}, dex.Disabled)
+	linkDone, err := link.Connect(ctx)
+	if err != nil {
+		t.Errorf("Connect error: %v", err)
+		return
+	}
+	links <- linkDone
+}
+
+// serveQUICEcho accepts QUIC connections on the address of the test server
+// and starts echo links for them.
+func serveQUICEcho(ctx context.Context, t *testing.T, srv *httptest.Server, links chan<- *sync.WaitGroup) *ws.QUICListener {
+	ln, err := ws.ListenQUIC("udp4", srv.Listener.Addr().String(), srv.TLS)
+	if err != nil {
+		t.Fatalf("ListenQUIC error: %v", err)
+	}
+	go func() {
+		for {
+			qc, err := ln.Accept(ctx)
+			if err != nil {
+				return
+			}
+			conn, _, err := ws.AcceptQUIC(ctx, qc, time.Minute)
+			if err != nil {
+				t.Errorf("AcceptQUIC error: %v", err)
+				continue
+			}
+			if err := conn.Accept(); err != nil {
+				t.Errorf("Accept error: %v", err)
+				continue
+			}
+			startEchoLink(ctx, t, qc.RemoteAddr().String(), conn, links)
+		}
+	}()
+	return ln
+}
+
+func TestWsConnCBOR(t *testing.T) {
+	srvCtx, srvCancel := context.WithCancel(context.Background())
+	defer srvCancel()
+
+	links := make(chan *sync.WaitGroup, 1)
+	srv := httptest.NewTLSServer(cborEchoHandler(srvCtx, t, links))
+	defer srv.Close()
+	certB := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	for _, preferCBOR := range []bool{false, true} {
+		wsc, err := NewWsConn(&WsCfg{
+			URL:                  "wss" + strings.TrimPrefix(srv.URL, "https") + "/ws",
+			PingWait:             time.Minute,
+			Cert:                 certB,
+			Logger:               tLogger,
+			DisableAutoReconnect: true,
+			PreferCBOR:           preferCBOR,
+		})
+		if err != nil {
+			t.Fatalf("NewWsConn error: %v", err)
+		}
+		connCtx, connCancel := context.WithCancel(context.Background())
+		wg, err := wsc.Connect(connCtx)
+		if err != nil {
+			t.Fatalf("Connect error: %v", err)
+		}
+
+		wantEnc := msgjson.JSONEncoding
+		if preferCBOR {
+			wantEnc = msgjson.CBOREncoding
+		}
+		if enc := wsc.(*wsConn).messageEncoding(); enc != wantEnc {
+			t.Fatalf("wrong negotiated encoding %s, wanted %s", enc, wantEnc)
+		}
+
+		respC := make(chan string, 1)
+		err = wsc.RequestWithTimeout(makeRequest(wsc.NextID(), "echo", "hello"), func(msg *msgjson.Message) {
+			if msg.Encoding() != wantEnc {
+				t.Errorf("wrong response encoding %s, wanted %s", msg.Encoding(), wantEnc)
+			}
+			var s string
+			if err := msg.UnmarshalResult(&s); err != nil {
+				t.Errorf("UnmarshalResult error: %v", err)
+			}
+			respC <- s
+		}, time.Second*5, func() {
+			respC <- "expired"
+		})
+		if err != nil {
+			t.Fatalf("Request error: %v", err)
+		}
+		if s, want := <-respC, "hello "+wantEnc.String(); s != want {
+			t.Fatalf("wrong response %q, wanted %q", s, want)
+		}
+		connCancel()
+		wg.Wait()
+		// The server's link should go down too.
+		(<-links).Wait()
+	}
+}
+
+func TestWsConnStats(t *testing.T) {
+	srvCtx, srvCancel := context.WithCancel(context.Background())
+	defer srvCancel()
+
+	links := make(chan *sync.WaitGroup, 1)
+	srv := httptest.NewTLSServer(cborEchoHandler(srvCtx, t, links))
+	defer srv.Close()
+	certB := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	wsc, err := NewWsConn(&WsCfg{
+		URL:                  "wss" + strings.TrimPrefix(srv.URL, "https") + "/ws",
+		PingWait:             time.Minute,
+		Cert:                 certB,
+		Logger:               tLogger,
+		DisableAutoReconnect: true,
+	})
+	if err != nil {
+		t.Fatalf("NewWsConn error: %v", err)
+	}
+	if stats := wsc.Stats(); !stats.ConnectedSince.IsZero() || stats.Responses != 0 {
+		t.Fatalf("unexpected stats before connect: %+v", stats)
+	}
+
+	connCtx, connCancel := context.WithCancel(context.Background())
+	wg, err := wsc.Connect(connCtx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+
+	const numReqs = 3
+	for i := 0; i < numReqs; i++ {
+		var resp string
+		if err = sendEcho(wsc, &resp); err != nil {
+			t.Fatalf("request error: %v", err)
+		}
+	}
+
+	// A request that expires before the response arrives.
+	expired := make(chan struct{})
+	err = wsc.RequestWithTimeout(makeRequest(wsc.NextID(), "echo", "late"), func(*msgjson.Message) {
+		t.Errorf("response handler called for expired request")
+	}, time.Nanosecond, func() { close(expired) })
+	if err != nil {
+		t.Fatalf("Request error: %v", err)
+	}
+	<-expired
+
+	stats := wsc.Stats()
+	if stats.Responses != numReqs {
+		t.Fatalf("expected %d responses, got %d", numReqs, stats.Responses)
+	}
+	if stats.TimedOut != 1 {
+		t.Fatalf("expected 1 timed out request, got %d", stats.TimedOut)
+	}
+	if stats.LastRTT <= 0 || stats.AvgRTT <= 0 || stats.MaxRTT < stats.LastRTT {
+		t.Fatalf("bad round-trip times: %+v", stats)
+	}
+	if stats.ConnectedSince.IsZero() || stats.PendingRequests != 0 {
+		t.Fatalf("bad connection stats: %+v", stats)
+	}
+
+	connCancel()
+	wg.Wait()
+	(<-links).Wait()
+	if stats = wsc.Stats(); !stats.ConnectedSince.IsZero() {
+		t.Fatalf("connected time set after disconnect")
+	}
+}
+
+// sendEcho sends an echo request and waits for the response.
+func sendEcho(wsc WsConn, resp *string) error {
+	errC := make(chan error, 1)
+	err := wsc.RequestWithTimeout(makeRequest(wsc.NextID(), "echo", "hi"), func(msg *msgjson.Message) {
+		errC <- msg.UnmarshalResult(resp)
+	}, 5*time.Second, func() {
+		errC <- errors.New("timed out")
+	})
+	if err != nil {
+		return err
+	}
+	return <-errC
+}
+
+func TestWsConnFailover(t *testing.T) {
+	srvCtx, srvCancel := context.WithCancel(context.Background())
+	defer srvCancel()
+
+	links := make(chan *sync.WaitGroup, 1)
+	srv := httptest.NewTLSServer(cborEchoHandler(srvCtx, t, links))
+	defer srv.Close()
+	certB := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	goodURL := "wss" + strings.TrimPrefix(srv.URL, "https") + "/ws"
+
+	// An address with nothing listening.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	deadURL := "wss://" + l.Addr().String() + "/ws"
+	l.Close()
+
+	wsc, err := NewWsConn(&WsCfg{
+		URL:                  deadURL,
+		PingWait:             time.Minute,
+		Cert:                 certB,
+		Logger:               tLogger,
+		DisableAutoReconnect: true,
+		AltEndpoints:         []*WsEndpoint{{URL: deadURL + "2"}, {URL: goodURL}},
+	})
+	if err != nil {
+		t.Fatalf("NewWsConn error: %v", err)
+	}
+	conn := wsc.(*wsConn)
+
+	connCtx, connCancel := context.WithCancel(context.Background())
+	wg, err := wsc.Connect(connCtx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	var resp string
+	if err = sendEcho(wsc, &resp); err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	stats := wsc.Stats()
+	if stats.Endpoint != goodURL || stats.Failovers != 1 {
+		t.Fatalf("wrong endpoint %q, failovers = %d", stats.Endpoint, stats.Failovers)
+	}
+
+	// The healthy endpoint is tried first next time.
+	if order := conn.endpointOrder(); order[0] != 2 {
+		t.Fatalf("wrong endpoint order %v", order)
+	}
+
+	// Replacing the alternates keeps the health of known endpoints.
+	wsc.SetAltEndpoints([]*WsEndpoint{{URL: goodURL}})
+	if conn.url() != goodURL {
+		t.Fatalf("current endpoint not retained")
+	}
+	if order := conn.endpointOrder(); len(order) != 2 || order[0] != 1 {
+		t.Fatalf("wrong endpoint order after update %v", order)
+	}
+	if conn.endpoints[1].avgRTT == 0 {
+		t.Fatalf("endpoint round-trip time not retained")
+	}
+
+	connCancel()
+	wg.Wait()
+	(<-links).Wait()
+
+	// All endpoints down.
+	wsc, _ = NewWsConn(&WsCfg{
+		URL:                  deadURL,
+		PingWait:             time.Minute,
+		Logger:               tLogger,
+		DisableAutoReconnect: true,
+		AltEndpoints:         []*WsEndpoint{{URL: deadURL + "2"}},
+	})
+	if _, err = wsc.Connect(context.Background()); err == nil {
+		t.Fatalf("no error connecting to dead endpoints")
+	}
+	if !wsc.IsDown() {
+		t.Fatalf("connection not down")
+	}
+}
+
+func TestWsConnQUIC(t *testing.T) {
+	srvCtx, srvCancel := context.WithCancel(context.Background())
+	defer srvCancel()
+
+	// The server advertises its QUIC listener in the websocket handshake.
+	links := make(chan *sync.WaitGroup, 1)
+	srv := httptest.NewUnstartedServer(nil)
+	_, quicPort, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	srv.Config.Handler = quicEchoHandler(srvCtx, t, links, quicPort)
+	srv.StartTLS()
+	defer srv.Close()
+	ln := serveQUICEcho(srvCtx, t, srv, links)
+	defer ln.Close()
+	certB := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	wsURL := "wss" + strings.TrimPrefix(srv.URL, "https") + "/ws"
+
+	newConn := func(preferCBOR bool) *wsConn {
+		t.Helper()
+		wsc, err := NewWsConn(&WsCfg{
+			URL:        wsURL,
+			PingWait:   time.Minute,
+			Cert:       certB,
+			Logger:     tLogger,
+			PreferCBOR: preferCBOR,
+			QUIC:       true,
+		})
+		if err != nil {
+			t.Fatalf("NewWsConn error: %v", err)
+		}
+		return wsc.(*wsConn)
+	}
+	// reconnect drops the client's connection, waits for the server's link to
+	// go down, and returns the server's link for the reconnected client.
+	reconnect := func(conn *wsConn, link *sync.WaitGroup) *sync.WaitGroup {
+		t.Helper()
+		conn.wsMtx.Lock()
+		conn.ws.Close()
+		conn.wsMtx.Unlock()
+		link.Wait()
+		select {
+		case link = <-links:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("not reconnected")
+		}
+		for conn.IsDown() {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return link
+	}
+
+	for _, preferCBOR := range []bool{false, true} {
+		conn := newConn(preferCBOR)
+		connCtx, connCancel := context.WithCancel(context.Background())
+		wg, err := conn.Connect(connCtx)
+		if err != nil {
+			t.Fatalf("Connect error: %v", err)
+		}
+		// The first connection is a websocket.
+		if transport := conn.Stats().Transport; transport != "websocket" {
+			t.Fatalf("first connection with %s, not a websocket", transport)
+		}
+		// The reconnect uses the advertised QUIC listener.
+		link := reconnect(conn, <-links)
+		if transport := conn.Stats().Transport; transport != "quic" {
+			t.Fatalf("reconnected with %s, not QUIC", transport)
+		}
+		wantEnc := msgjson.JSONEncoding
+		if preferCBOR {
+			wantEnc = msgjson.CBOREncoding
+		}
+		var resp string
+		if err = sendEcho(conn, &resp); err != nil {
+			t.Fatalf("request error: %v", err)
+		}
+		if want := "hi " + wantEnc.String(); resp != want {
+			t.Fatalf("wrong response %q, wanted %q", resp, want)
+		}
+		connCancel()
+		wg.Wait()
+		// The server's link should go down too.
+		link.Wait()
+	}
+
+	// A server that does not advertise QUIC is connected with a websocket.
+	wsSrv := httptest.NewTLSServer(cborEchoHandler(srvCtx, t, links))
+	defer wsSrv.Close()
+	wsc, err := NewWsConn(&WsCfg{
+		URL:                  "wss" + strings.TrimPrefix(wsSrv.URL, "https") + "/ws",
+		PingWait:             time.Minute,
+		Cert:                 pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: wsSrv.Certificate().Raw}),
+		Logger:               tLogger,
+		DisableAutoReconnect: true,
+		QUIC:                 true,
+	})
+	if err != nil {
+		t.Fatalf("NewWsConn error: %v", err)
+	}
+	connCtx, connCancel := context.WithCancel(context.Background())
+	wg, err := wsc.Connect(connCtx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	conn := wsc.(*wsConn)
+	if addr := conn.quicAddr(&conn.endpoints[0].WsEndpoint); addr != "" {
+		t.Fatalf("QUIC address %s for a server that does not advertise QUIC", addr)
+	}
+	connCancel()
+	wg.Wait()
+	(<-links).Wait()
+
+	// If the QUIC connection fails, a websocket is used, and QUIC is not
+	// tried again.
+	defer func(d time.Duration) { quicDialTimeout = d }(quicDialTimeout)
+	quicDialTimeout = 500 * time.Millisecond
+	conn = newConn(false)
+	connCtx, connCancel = context.WithCancel(context.Background())
+	defer connCancel()
+	if wg, err = conn.Connect(connCtx); err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	ln.Close()
+	link := reconnect(conn, <-links)
+	if transport := conn.Stats().Transport; transport != "websocket" {
+		t.Fatalf("reconnected with %s, not a websocket", transport)
+	}
+	if addr := conn.quicAddr(&conn.endpoints[0].WsEndpoint); addr != "" {
+		t.Fatalf("QUIC retried with %s after a failure", addr)
+	}
+	connCancel()
+	wg.Wait()
+	link.Wait()
+}
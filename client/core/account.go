@@ -338,6 +338,56 @@ func (c *Core) UpdateCert(host string, cert []byte) error {
 	return nil
 }
 
+// UpdateDEXAltHosts sets the alternate addresses for the DEX server at host,
+// e.g. an onion address or regional endpoints. If the server cannot be reached
+// at host, the alternate addresses are tried, preferring the most responsive.
+// The change takes effect on the next connection attempt. A server reached at
+// an alternate address must have the same public key as the DEX at host.
+func (c *Core) UpdateDEXAltHosts(host string, altHosts []string) error {
+	host, err := addrHost(host)
+	if err != nil {
+		return newError(addressParseErr, "error parsing address: %w", err)
+	}
+	acct, err := c.db.Account(host)
+	if err != nil {
+		return err
+	}
+
+	hosts := make([]string, 0, len(altHosts))
+	eps := make([]*comms.WsEndpoint, 0, len(altHosts))
+	seen := map[string]bool{host: true}
+	for _, addr := range altHosts {
+		altHost, err := addrHost(addr)
+		if err != nil {
+			return newError(addressParseErr, "error parsing alternate address %q: %w", addr, err)
+		}
+		if seen[altHost] {
+			continue
+		}
+		seen[altHost] = true
+		ep, err := c.wsEndpoint(altHost)
+		if err != nil {
+			return err
+		}
+		hosts = append(hosts, altHost)
+		eps = append(eps, ep)
+	}
+
+	acct.AltHosts = hosts
+	if err = c.db.UpdateAccountInfo(acct); err != nil {
+		return fmt.Errorf("failed to update account info: %w", err)
+	}
+
+	c.connMtx.RLock()
+	dc, found := c.conns[host]
+	c.connMtx.RUnlock()
+	if found {
+		dc.altHostsV.Store(hosts)
+		dc.SetAltEndpoints(eps)
+	}
+	return nil
+}
+
 // UpdateDEXHost updates the host for a connection to a dex. The dex at oldHost
 // and newHost must be the same dex, which means that the dex at both hosts use
 // the same public key.
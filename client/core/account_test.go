@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"errors"
+	"strings"
 	"testing"
 
 	"decred.org/dcrdex/client/db"
@@ -240,6 +241,67 @@ func TestUpdateCert(t *testing.T) {
 	}
 }
 
+func TestUpdateDEXAltHosts(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	altHosts := []string{"alt.dex.tld:7232", "alt.dex.tld:7232", tDexHost, "other.dex.tld"}
+	if err := tCore.UpdateDEXAltHosts(tDexHost, altHosts); err != nil {
+		t.Fatalf("UpdateDEXAltHosts error: %v", err)
+	}
+	wantHosts := []string{"alt.dex.tld:7232", "other.dex.tld:" + defaultDEXPort}
+	checkHosts := func(tag string, hosts []string) {
+		t.Helper()
+		if strings.Join(hosts, " ") != strings.Join(wantHosts, " ") {
+			t.Fatalf("%s: wrong alternate hosts %v, wanted %v", tag, hosts, wantHosts)
+		}
+	}
+	checkHosts("db", rig.db.acct.AltHosts)
+	xc, err := tCore.Exchange(tDexHost)
+	if err != nil {
+		t.Fatalf("Exchange error: %v", err)
+	}
+	checkHosts("exchange", xc.AltHosts)
+
+	// Onion hosts require Tor.
+	if err = tCore.UpdateDEXAltHosts(tDexHost, []string{"abc.onion:7232"}); err == nil {
+		t.Fatalf("no error for onion host without Tor")
+	}
+	checkHosts("after onion error", rig.db.acct.AltHosts)
+
+	rig.db.updateAccountInfoErr = tErr
+	if err = tCore.UpdateDEXAltHosts(tDexHost, nil); err == nil {
+		t.Fatalf("no error for db update error")
+	}
+	rig.db.updateAccountInfoErr = nil
+	checkHosts("after db error", tCore.conns[tDexHost].altHosts())
+
+	// Clear them.
+	if err = tCore.UpdateDEXAltHosts(tDexHost, nil); err != nil {
+		t.Fatalf("UpdateDEXAltHosts error: %v", err)
+	}
+	wantHosts = nil
+	checkHosts("cleared", tCore.conns[tDexHost].altHosts())
+
+	// A server at an alternate address must be the same DEX.
+	otherKey, _ := secp256k1.GeneratePrivateKey()
+	rig.ws.queueResponse(msgjson.ConfigRoute, func(msg *msgjson.Message, f msgFunc) error {
+		cfg := *rig.dc.cfg
+		cfg.DEXPubKey = otherKey.PubKey().SerializeCompressed()
+		resp, _ := msgjson.NewResponse(msg.ID, &cfg, nil)
+		f(resp)
+		return nil
+	})
+	if _, err = rig.dc.refreshServerConfig(); err == nil {
+		t.Fatalf("no error for config with wrong public key")
+	}
+	rig.queueConfig()
+	if _, err = rig.dc.refreshServerConfig(); err != nil {
+		t.Fatalf("refreshServerConfig error: %v", err)
+	}
+}
+
 func TestUpdateDEXHost(t *testing.T) {
 	newPrivKey, _ := secp256k1.GeneratePrivateKey()
 	newPubKey := newPrivKey.PubKey()
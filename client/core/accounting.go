@@ -0,0 +1,372 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+)
+
+// AccountingFormat is the layout of a match accounting export.
+type AccountingFormat string
+
+const (
+	// AccountingFormatGeneric is a CSV with one row per match and a column for
+	// every field of the MatchRecord.
+	AccountingFormatGeneric AccountingFormat = "generic"
+	// AccountingFormatUniversal is the "universal" CSV layout that is accepted
+	// by most crypto tax tools (Date, Sent Amount, Sent Currency, Received
+	// Amount, Received Currency, Fee Amount, Fee Currency, ..., TxHash). A
+	// completed match is a trade row, and fees that can't be attached to the
+	// trade row, along with the fees of refunded matches, are separate cost
+	// rows.
+	AccountingFormatUniversal AccountingFormat = "universal"
+)
+
+// MatchRecord is the accounting record for a single match. Amounts are in
+// atomic units. The order's fees are apportioned to its matches by quantity.
+type MatchRecord struct {
+	Host        string            `json:"host"`
+	OrderID     dex.Bytes         `json:"orderID"`
+	MatchID     dex.Bytes         `json:"matchID"`
+	Stamp       uint64            `json:"stamp"`
+	Side        order.MatchSide   `json:"side"`
+	Status      order.MatchStatus `json:"status"`
+	Sell        bool              `json:"sell"`
+	BaseID      uint32            `json:"baseID"`
+	QuoteID     uint32            `json:"quoteID"`
+	Qty         uint64            `json:"qty"`
+	Rate        uint64            `json:"rate"`
+	QuoteQty    uint64            `json:"quoteQty"`
+	SentID      uint32            `json:"sentID"`
+	Sent        uint64            `json:"sent"`
+	ReceivedID  uint32            `json:"receivedID"`
+	Received    uint64            `json:"received"`
+	SwapFeeID   uint32            `json:"swapFeeID"`
+	SwapFees    uint64            `json:"swapFees"`
+	RedeemFeeID uint32            `json:"redeemFeeID"`
+	RedeemFees  uint64            `json:"redeemFees"`
+	Swap        *Coin             `json:"swap,omitempty"`
+	CounterSwap *Coin             `json:"counterSwap,omitempty"`
+	Redeem      *Coin             `json:"redeem,omitempty"`
+	Refund      *Coin             `json:"refund,omitempty"`
+	Refunded    bool              `json:"refunded"`
+	Abandoned   bool              `json:"abandoned,omitempty"`
+	// Note and Tags are the user's annotation of the match, or of its order
+	// if the match is not annotated.
+	Note string   `json:"note,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// MatchRecords builds accounting records for the matches of the orders
+// selected by the filter. Cancel order matches are not included.
+func (c *Core) MatchRecords(filter *OrderFilter) ([]*MatchRecord, error) {
+	ords, err := c.Orders(filter)
+	if err != nil {
+		return nil, err
+	}
+	recs := make([]*MatchRecord, 0)
+	for _, ord := range ords {
+		recs = append(recs, matchRecords(ord)...)
+	}
+	return recs, nil
+}
+
+// matchRecords builds the accounting records for the order's matches.
+func matchRecords(ord *Order) []*MatchRecord {
+	var filled uint64
+	for _, m := range ord.Matches {
+		if !m.IsCancel {
+			filled += m.Qty
+		}
+	}
+	if filled == 0 {
+		return nil
+	}
+
+	fromID, toID := ord.QuoteID, ord.BaseID
+	if ord.Sell {
+		fromID, toID = ord.BaseID, ord.QuoteID
+	}
+	var swapFees, redeemFees uint64
+	if ord.FeesPaid != nil {
+		swapFees = ord.FeesPaid.Swap + ord.FeesPaid.Funding + ord.FeesPaid.Refund
+		redeemFees = ord.FeesPaid.Redemption
+	}
+	// share apportions an order's fees to a match by quantity.
+	share := func(fees, qty uint64) uint64 {
+		return uint64(float64(fees) * float64(qty) / float64(filled))
+	}
+
+	recs := make([]*MatchRecord, 0, len(ord.Matches))
+	for _, m := range ord.Matches {
+		if m.IsCancel {
+			continue
+		}
+		quoteQty := calc.BaseToQuote(m.Rate, m.Qty)
+		sent, received := quoteQty, m.Qty
+		if ord.Sell {
+			sent, received = m.Qty, quoteQty
+		}
+		rec := &MatchRecord{
+			Host:        ord.Host,
+			OrderID:     ord.ID,
+			MatchID:     m.MatchID,
+			Stamp:       m.Stamp,
+			Side:        m.Side,
+			Status:      m.Status,
+			Sell:        ord.Sell,
+			BaseID:      ord.BaseID,
+			QuoteID:     ord.QuoteID,
+			Qty:         m.Qty,
+			Rate:        m.Rate,
+			QuoteQty:    quoteQty,
+			SentID:      fromID,
+			Sent:        sent,
+			ReceivedID:  toID,
+			Received:    received,
+			SwapFeeID:   feeAssetID(fromID),
+			SwapFees:    share(swapFees, m.Qty),
+			RedeemFeeID: feeAssetID(toID),
+			RedeemFees:  share(redeemFees, m.Qty),
+			Swap:        m.Swap,
+			CounterSwap: m.CounterSwap,
+			Redeem:      m.Redeem,
+			Refund:      m.Refund,
+			Refunded:    m.Refund != nil,
+			Abandoned:   m.Abandoned,
+		}
+		if rec.Refunded || (rec.Abandoned && rec.Redeem == nil) {
+			rec.Received = 0
+		}
+		if m.Note != "" || len(m.Tags) > 0 {
+			rec.Note, rec.Tags = m.Note, m.Tags
+		} else {
+			rec.Note, rec.Tags = ord.Note, ord.Tags
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+// feeAssetID is the ID of the asset that fees are paid in for transactions of
+// the specified asset, which is the parent chain's asset for tokens.
+func feeAssetID(assetID uint32) uint32 {
+	if token := asset.TokenInfo(assetID); token != nil {
+		return token.ParentID
+	}
+	return assetID
+}
+
+// txIDFromCoin strips the output index, if any, from the coin's string ID.
+func txIDFromCoin(coin *Coin) string {
+	if coin == nil {
+		return ""
+	}
+	txID, _, _ := strings.Cut(coin.StringID, ":")
+	return txID
+}
+
+// WriteMatchRecords writes the records to w as a CSV in the specified format.
+// Amounts are written in conventional units.
+func WriteMatchRecords(w io.Writer, recs []*MatchRecord, format AccountingFormat, useCRLF bool) error {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.UseCRLF = useCRLF
+
+	unitInfos := make(map[uint32]*dex.UnitInfo)
+	units := func(assetID uint32) *dex.UnitInfo {
+		ui, found := unitInfos[assetID]
+		if !found {
+			u, err := asset.UnitInfo(assetID)
+			if err != nil {
+				u = dex.UnitInfo{Conventional: dex.Denomination{ConversionFactor: 1e8}}
+			}
+			ui = &u
+			unitInfos[assetID] = ui
+		}
+		return ui
+	}
+
+	var err error
+	switch format {
+	case AccountingFormatGeneric, "":
+		err = writeGenericMatchRecords(csvWriter, recs, units)
+	case AccountingFormatUniversal:
+		err = writeUniversalMatchRecords(csvWriter, recs, units)
+	default:
+		return fmt.Errorf("unknown accounting format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func writeGenericMatchRecords(csvWriter *csv.Writer, recs []*MatchRecord, units func(uint32) *dex.UnitInfo) error {
+	amt := func(assetID uint32, v uint64) string {
+		return units(assetID).ConventionalString(v)
+	}
+	err := csvWriter.Write([]string{
+		"Time",
+		"Host",
+		"Order ID",
+		"Match ID",
+		"Market",
+		"Side",
+		"Maker/Taker",
+		"Status",
+		"Base Quantity",
+		"Rate",
+		"Quote Quantity",
+		"Sent",
+		"Sent Asset",
+		"Received",
+		"Received Asset",
+		"Swap Fees",
+		"Swap Fees Asset",
+		"Redeem Fees",
+		"Redeem Fees Asset",
+		"Swap Tx",
+		"Counterparty Swap Tx",
+		"Redeem Tx",
+		"Refund Tx",
+		"Note",
+		"Tags",
+	})
+	if err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		side := "buy"
+		if rec.Sell {
+			side = "sell"
+		}
+		status := rec.Status.String()
+		if rec.Refunded {
+			status = "Refunded"
+		} else if rec.Abandoned {
+			status = "Abandoned"
+		}
+		err = csvWriter.Write([]string{
+			time.UnixMilli(int64(rec.Stamp)).UTC().Format(time.RFC3339),
+			rec.Host,
+			rec.OrderID.String(),
+			rec.MatchID.String(),
+			unbip(rec.BaseID) + "_" + unbip(rec.QuoteID),
+			side,
+			rec.Side.String(),
+			status,
+			amt(rec.BaseID, rec.Qty),
+			strconv.FormatFloat(calc.ConventionalRate(rec.Rate, *units(rec.BaseID), *units(rec.QuoteID)), 'f', -1, 64),
+			amt(rec.QuoteID, rec.QuoteQty),
+			amt(rec.SentID, rec.Sent),
+			unbip(rec.SentID),
+			amt(rec.ReceivedID, rec.Received),
+			unbip(rec.ReceivedID),
+			amt(rec.SwapFeeID, rec.SwapFees),
+			unbip(rec.SwapFeeID),
+			amt(rec.RedeemFeeID, rec.RedeemFees),
+			unbip(rec.RedeemFeeID),
+			txIDFromCoin(rec.Swap),
+			txIDFromCoin(rec.CounterSwap),
+			txIDFromCoin(rec.Redeem),
+			txIDFromCoin(rec.Refund),
+			rec.Note,
+			strings.Join(rec.Tags, " "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeUniversalMatchRecords(csvWriter *csv.Writer, recs []*MatchRecord, units func(uint32) *dex.UnitInfo) error {
+	amt := func(assetID uint32, v uint64) string {
+		return units(assetID).ConventionalString(v)
+	}
+	err := csvWriter.Write([]string{
+		"Date",
+		"Sent Amount",
+		"Sent Currency",
+		"Received Amount",
+		"Received Currency",
+		"Fee Amount",
+		"Fee Currency",
+		"Net Worth Amount",
+		"Net Worth Currency",
+		"Label",
+		"Description",
+		"TxHash",
+	})
+	if err != nil {
+		return err
+	}
+	currency := func(assetID uint32) string {
+		return strings.ToUpper(dex.TokenSymbol(unbip(assetID)))
+	}
+	for _, rec := range recs {
+		date := time.UnixMilli(int64(rec.Stamp)).UTC().Format("2006-01-02 15:04:05 UTC")
+		desc := fmt.Sprintf("DEX match %s at %s", rec.MatchID, rec.Host)
+		costRow := func(assetID uint32, fees uint64, coin *Coin, what string) error {
+			if fees == 0 {
+				return nil
+			}
+			return csvWriter.Write([]string{
+				date, amt(assetID, fees), currency(assetID), "", "", "", "", "", "",
+				"cost", desc + " " + what + " fees", txIDFromCoin(coin),
+			})
+		}
+		swapFeeCoin := rec.Swap
+		if rec.Refunded {
+			swapFeeCoin = rec.Refund
+		}
+		if rec.Redeem == nil {
+			// Not a completed trade. Only the fees are recorded.
+			if err := costRow(rec.SwapFeeID, rec.SwapFees, swapFeeCoin, "swap"); err != nil {
+				return err
+			}
+			continue
+		}
+		// The swap fees go on the trade row if they're paid in the sent asset.
+		// Otherwise, they're a separate cost, as are the redeem fees.
+		var feeAmt, feeCurrency string
+		if rec.SwapFeeID == rec.SentID && rec.SwapFees > 0 {
+			feeAmt, feeCurrency = amt(rec.SwapFeeID, rec.SwapFees), currency(rec.SwapFeeID)
+		} else if err := costRow(rec.SwapFeeID, rec.SwapFees, rec.Swap, "swap"); err != nil {
+			return err
+		}
+		err = csvWriter.Write([]string{
+			date,
+			amt(rec.SentID, rec.Sent),
+			currency(rec.SentID),
+			amt(rec.ReceivedID, rec.Received),
+			currency(rec.ReceivedID),
+			feeAmt,
+			feeCurrency,
+			"",
+			"",
+			"",
+			desc,
+			txIDFromCoin(rec.Redeem),
+		})
+		if err != nil {
+			return err
+		}
+		if err := costRow(rec.RedeemFeeID, rec.RedeemFees, rec.Redeem, "redeem"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
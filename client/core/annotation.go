@@ -0,0 +1,104 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/order"
+)
+
+const (
+	// maxNoteLength is the maximum length of an order or match note, in
+	// characters.
+	maxNoteLength = 1000
+	// maxTags is the maximum number of tags on an order or match.
+	maxTags = 20
+	// maxTagLength is the maximum length of a tag, in characters.
+	maxTagLength = 32
+)
+
+// newAnnotation validates the note and tags, and returns the annotation to
+// store. The note is trimmed. Tags are trimmed, must not contain spaces or
+// commas, and are deduplicated ignoring case. A nil *Annotation is returned
+// if there is no note or tag.
+func newAnnotation(note string, tags []string) (*db.Annotation, error) {
+	note = strings.TrimSpace(note)
+	if n := utf8.RuneCountInString(note); n > maxNoteLength {
+		return nil, fmt.Errorf("note is %d characters long, max is %d", n, maxNoteLength)
+	}
+	a := &db.Annotation{Note: note}
+	have := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || have[strings.ToLower(tag)] {
+			continue
+		}
+		if strings.ContainsFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) {
+			return nil, fmt.Errorf("tag %q contains a space or comma", tag)
+		}
+		if n := utf8.RuneCountInString(tag); n > maxTagLength {
+			return nil, fmt.Errorf("tag %q is %d characters long, max is %d", tag, n, maxTagLength)
+		}
+		have[strings.ToLower(tag)] = true
+		a.Tags = append(a.Tags, tag)
+	}
+	if len(a.Tags) > maxTags {
+		return nil, fmt.Errorf("%d tags, max is %d", len(a.Tags), maxTags)
+	}
+	if a.IsEmpty() {
+		return nil, nil
+	}
+	return a, nil
+}
+
+// AnnotateOrder sets the user's note and tags for an order, or for one of the
+// order's matches if form.MatchID is set, replacing any existing note and
+// tags. An empty note and tags remove the annotation. The annotation is
+// returned with the order or match, and is searchable with OrderFilter.Text.
+func (c *Core) AnnotateOrder(form *AnnotationForm) error {
+	oid, err := order.IDFromBytes(form.OrderID)
+	if err != nil {
+		return err
+	}
+	annotation, err := newAnnotation(form.Note, form.Tags)
+	if err != nil {
+		return newError(orderParamsErr, "invalid annotation: %v", err)
+	}
+
+	tracker, _ := c.findActiveOrder(oid)
+
+	if len(form.MatchID) == 0 {
+		if err := c.db.SetOrderAnnotation(oid, annotation); err != nil {
+			return fmt.Errorf("error storing annotation for order %s: %w", oid, err)
+		}
+		if tracker != nil {
+			tracker.mtx.Lock()
+			tracker.metaData.Annotation = annotation
+			tracker.mtx.Unlock()
+		}
+		return nil
+	}
+
+	if len(form.MatchID) != order.MatchIDSize {
+		return fmt.Errorf("invalid match ID length %d", len(form.MatchID))
+	}
+	var mid order.MatchID
+	copy(mid[:], form.MatchID)
+	if err := c.db.SetMatchAnnotation(oid, mid, annotation); err != nil {
+		return fmt.Errorf("error storing annotation for match %s: %w", mid, err)
+	}
+	if tracker != nil {
+		tracker.mtx.Lock()
+		if mt := tracker.matches[mid]; mt != nil {
+			mt.MetaData.Annotation = annotation
+		}
+		tracker.mtx.Unlock()
+	}
+	return nil
+}
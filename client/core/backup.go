@@ -0,0 +1,284 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/encode"
+	"github.com/decred/dcrd/crypto/blake256"
+)
+
+const (
+	// backupFilePrefix and backupFileExt make up the names of the encrypted
+	// backup files, with a UTC timestamp in between. Backups created in the
+	// same millisecond get a counter suffix after the timestamp.
+	backupFilePrefix = "bisonw-backup-"
+	backupFileExt    = ".bwbak"
+	backupTimeFormat = "20060102-150405.000"
+	// restoredDir is the folder, next to the database, that RestoreBackup
+	// writes the restored files to.
+	restoredDir = "restored"
+	// restoredWalletsFile is the file of wallet configurations written by
+	// RestoreBackup.
+	restoredWalletsFile = "wallets.json"
+)
+
+// backupKey derives the encryption key material for encrypted backups from the
+// app seed, so that backups can be restored with nothing but the seed.
+func backupKey(seed []byte) []byte {
+	b := make([]byte, 0, len(seed)+len("backup"))
+	b = append(append(b, seed...), "backup"...)
+	defer encode.ClearBytes(b)
+	k := blake256.Sum256(b)
+	return k[:]
+}
+
+// backupDir is the directory for encrypted backups.
+func (c *Core) backupDir() string {
+	if c.cfg.BackupDir != "" {
+		return c.cfg.BackupDir
+	}
+	return filepath.Join(filepath.Dir(c.cfg.DBPath), "encrypted-backups")
+}
+
+// BackupNow creates an encrypted backup of the database and the wallet
+// configurations in the backup directory, returning the path of the new file.
+// The backup is encrypted with a key derived from the app seed, so Core must
+// be logged in.
+func (c *Core) BackupNow() (string, error) {
+	c.loginMtx.Lock()
+	key := append([]byte(nil), c.backupKey...)
+	c.loginMtx.Unlock()
+	if len(key) == 0 {
+		return "", errors.New("cannot create an encrypted backup before login")
+	}
+	defer encode.ClearBytes(key)
+
+	// Scheduled and manual backups may overlap.
+	c.backupMtx.Lock()
+	defer c.backupMtx.Unlock()
+
+	dir := c.backupDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("unable to create backup directory: %w", err)
+	}
+
+	// Copy the database to a temporary file, since the database file itself
+	// may be written to while it is read.
+	tmpDB := filepath.Join(dir, ".db-copy")
+	if err := c.db.BackupTo(tmpDB, true, true); err != nil {
+		return "", fmt.Errorf("database backup error: %w", err)
+	}
+	dbB, err := os.ReadFile(tmpDB)
+	os.Remove(tmpDB)
+	if err != nil {
+		return "", fmt.Errorf("error reading database copy: %w", err)
+	}
+
+	dbWallets, err := c.db.Wallets()
+	if err != nil {
+		return "", fmt.Errorf("error loading wallets: %w", err)
+	}
+	wallets := make([]*db.BackupWallet, 0, len(dbWallets))
+	for _, w := range dbWallets {
+		wallets = append(wallets, &db.BackupWallet{
+			AssetID:  w.AssetID,
+			Type:     w.Type,
+			Settings: w.Settings,
+		})
+	}
+
+	stamp := time.Now()
+	b, err := db.EncryptBackup(key, &db.Backup{
+		Stamp:   stamp,
+		DB:      dbB,
+		Wallets: wallets,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so that an interrupted write doesn't
+	// leave a truncated backup.
+	path := uniqueBackupPath(dir, stamp)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0600); err != nil {
+		return "", fmt.Errorf("error writing backup: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("error writing backup: %w", err)
+	}
+
+	c.rotateBackups(dir)
+
+	return path, nil
+}
+
+// uniqueBackupPath is the path for a new backup file created at stamp. If a
+// backup with the same timestamp already exists, a counter is appended to the
+// timestamp, which preserves the chronological sorting of the names.
+func uniqueBackupPath(dir string, stamp time.Time) string {
+	name := backupFilePrefix + stamp.UTC().Format(backupTimeFormat)
+	path := filepath.Join(dir, name+backupFileExt)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return path
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s_%03d%s", name, i, backupFileExt))
+	}
+}
+
+// rotateBackups deletes the oldest encrypted backups in excess of the
+// Config.BackupsKept.
+func (c *Core) rotateBackups(dir string) {
+	if c.cfg.BackupsKept <= 0 {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		c.log.Errorf("Error listing backups for rotation: %v", err)
+		return
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && strings.HasPrefix(name, backupFilePrefix) && strings.HasSuffix(name, backupFileExt) {
+			names = append(names, name)
+		}
+	}
+	if len(names) <= c.cfg.BackupsKept {
+		return
+	}
+	// The timestamps in the names sort chronologically.
+	sort.Strings(names)
+	for _, name := range names[:len(names)-c.cfg.BackupsKept] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			c.log.Errorf("Error deleting old backup %s: %v", name, err)
+		} else {
+			c.log.Debugf("Deleted old backup %s", name)
+		}
+	}
+}
+
+// watchBackups creates an encrypted backup every Config.BackupInterval while
+// logged in, until the context is canceled.
+func (c *Core) watchBackups(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.BackupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.loginMtx.Lock()
+			loggedIn := len(c.backupKey) > 0
+			c.loginMtx.Unlock()
+			if !loggedIn {
+				continue
+			}
+			path, err := c.BackupNow()
+			if err != nil {
+				c.log.Errorf("Scheduled backup failed: %v", err)
+				subject, details := c.formatDetails(TopicBackupFailed, err)
+				c.notify(newSecurityNote(TopicBackupFailed, subject, details, db.ErrorLevel))
+				continue
+			}
+			c.log.Infof("Created encrypted backup %s", path)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RestoreBackup decrypts and validates an encrypted backup created by
+// BackupNow or the scheduled backups, using the app seed, which may be a
+// mnemonic or a hex-encoded legacy seed. The database and a JSON file of the
+// wallet configurations are written to a "restored" folder next to the current
+// database, and are not used until the application is stopped and the restored
+// database is moved in place of the current one. Any previously restored files
+// must be removed first.
+func (c *Core) RestoreBackup(backupPath, seedStr string) (*RestoredBackup, error) {
+	seed, _, err := decodeSeedString(seedStr)
+	if err != nil {
+		return nil, err
+	}
+	key := backupKey(seed)
+	encode.ClearBytes(seed)
+	defer encode.ClearBytes(key)
+
+	b, err := os.ReadFile(backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup: %w", err)
+	}
+	backup, err := db.DecryptBackup(key, b)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(filepath.Dir(c.cfg.DBPath), restoredDir)
+	dbPath := filepath.Join(dir, filepath.Base(c.cfg.DBPath))
+	walletsPath := filepath.Join(dir, restoredWalletsFile)
+	for _, path := range []string{dbPath, walletsPath} {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("%s already exists", path)
+		}
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create restore directory: %w", err)
+	}
+	if err := os.WriteFile(dbPath, backup.DB, 0600); err != nil {
+		return nil, fmt.Errorf("error writing restored database: %w", err)
+	}
+
+	// Make sure the database opens and was initialized.
+	if err := c.validateRestoredDB(dbPath); err != nil {
+		os.Remove(dbPath)
+		return nil, err
+	}
+
+	walletsB, err := json.MarshalIndent(backup.Wallets, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding wallets: %w", err)
+	}
+	if err := os.WriteFile(walletsPath, walletsB, 0600); err != nil {
+		return nil, fmt.Errorf("error writing restored wallets: %w", err)
+	}
+
+	c.log.Infof("Restored backup from %s to %s", backup.Stamp.Format(time.DateTime), dbPath)
+
+	return &RestoredBackup{
+		Stamp:       uint64(backup.Stamp.UnixMilli()),
+		DBPath:      dbPath,
+		WalletsPath: walletsPath,
+		Wallets:     len(backup.Wallets),
+	}, nil
+}
+
+// validateRestoredDB opens the database and checks that it has stored
+// credentials.
+func (c *Core) validateRestoredDB(dbPath string) error {
+	restoredDB, err := openDB(c.cfg.DBBackend, dbPath, c.log.SubLogger("RESTORE"), false)
+	if err != nil {
+		return fmt.Errorf("restored database can't be opened: %w", err)
+	}
+	// Run returns after closing the database when the context is canceled.
+	defer func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		restoredDB.Run(ctx)
+	}()
+	if _, err := restoredDB.PrimaryCredentials(); err != nil {
+		return fmt.Errorf("restored database has no credentials: %w", err)
+	}
+	return nil
+}
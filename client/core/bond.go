@@ -571,7 +571,12 @@ func (c *Core) refundExpiredBonds(ctx context.Context, acct *dexAccount, cfg *de
 		} else {
 			subject, details := c.formatDetails(TopicBondRefunded, makeCoinIDToken(bond.CoinID.String(), bond.AssetID), acct.host,
 				makeCoinIDToken(refundCoinStr, bond.AssetID), wallet.amtString(refundVal), wallet.amtString(bond.Amount))
-			c.notify(newBondRefundNote(TopicBondRefunded, subject, details, db.Success))
+			c.notify(withData(newBondRefundNote(TopicBondRefunded, subject, details, db.Success), &db.NoteData{
+				Host:    acct.host,
+				AssetID: &assetID,
+				CoinID:  refundCoinStr,
+				Amount:  refundVal,
+			}))
 		}
 
 		err = c.db.BondRefunded(acct.host, assetID, bond.CoinID)
@@ -852,7 +857,8 @@ func (c *Core) postAndConfirmBond(dc *dexConnection, bond *asset.Bond) {
 	pbr, err := c.postBond(dc, bond) // can be long while server searches
 	if err != nil {
 		subject, details := c.formatDetails(TopicBondPostError, err, err)
-		c.notify(newBondPostNote(TopicBondPostError, subject, details, db.ErrorLevel, dc.acct.host))
+		c.notify(withData(newBondPostNote(TopicBondPostError, subject, details, db.ErrorLevel, dc.acct.host),
+			&db.NoteData{Host: dc.acct.host, AssetID: &assetID, CoinID: coinIDStr, Error: err.Error()}))
 		return
 	}
 
@@ -931,7 +937,8 @@ func (c *Core) monitorBondConfs(dc *dexConnection, bond *asset.Bond, reqConfs ui
 	c.wait(coinID, assetID, trigger, func(err error) {
 		if err != nil {
 			subject, details := c.formatDetails(TopicBondPostErrorConfirm, host, err)
-			c.notify(newBondPostNote(TopicBondPostError, subject, details, db.ErrorLevel, host))
+			c.notify(withData(newBondPostNote(TopicBondPostError, subject, details, db.ErrorLevel, host),
+				&db.NoteData{Host: host, AssetID: &assetID, CoinID: coinIDStr, Error: err.Error()}))
 			return
 		}
 
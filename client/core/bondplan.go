@@ -0,0 +1,284 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+)
+
+// BondRenewal is an upcoming bond posting for a DEX account. Tiers are the
+// bond increments to post, and Time is when they will be posted (unix
+// seconds). Bonds that are posted together share a lock time.
+type BondRenewal struct {
+	Time  int64 `json:"time"`
+	Tiers int64 `json:"tiers"`
+}
+
+// BondFundingOption is a wallet that could fund the bond renewals of a DEX
+// account.
+type BondFundingOption struct {
+	AssetID uint32 `json:"assetID"`
+	Symbol  string `json:"symbol"`
+	// Required is the amount required for the renewals, including estimated
+	// fees.
+	Required uint64 `json:"required"`
+	// Fees is the estimated transaction fees for the renewals.
+	Fees uint64 `json:"fees"`
+	// FiatFees is Fees converted to the fiat currency, if a rate is known.
+	FiatFees float64 `json:"fiatFees,omitempty"`
+	// Available is the amount available to fund the renewals. This is the
+	// wallet's available balance and bond reserves, plus the expected refunds
+	// of expired bonds, less the amount allocated to the renewals of DEX
+	// accounts that renew earlier in the plan.
+	Available  uint64 `json:"available"`
+	Sufficient bool   `json:"sufficient"`
+}
+
+// HostBondPlan is the bond funding plan for a DEX account.
+type HostBondPlan struct {
+	Host        string               `json:"host"`
+	BondAssetID uint32               `json:"bondAssetID"`
+	TargetTier  uint64               `json:"targetTier"`
+	Renewals    []*BondRenewal       `json:"renewals"`
+	Options     []*BondFundingOption `json:"options"`
+	// RecommendedAssetID is the asset of the cheapest sufficient option. It is
+	// nil if no option is sufficient.
+	RecommendedAssetID *uint32 `json:"recommendedAssetID,omitempty"`
+	// Consolidate indicates that the account's bonds are in assets other than
+	// the recommended asset. Renewing with the recommended asset consolidates
+	// the bonds as the others expire.
+	Consolidate bool `json:"consolidate"`
+}
+
+// BondFundingPlan is the proposed funding of upcoming bond renewals across
+// DEX accounts. The hosts are ordered by their first renewal.
+type BondFundingPlan struct {
+	Hosts []*HostBondPlan `json:"hosts"`
+}
+
+// bondRenewals lists the upcoming bond postings for the account. Bonds are
+// renewed when they are within the pending buffer of expiry, and any missing
+// tiers are posted immediately. The authMtx must be held.
+func (c *Core) bondRenewals(dc *dexConnection, bondAssets map[uint32]*BondAsset, bondExpiry, now int64) []*BondRenewal {
+	byLockTime := make(map[uint64]int64)
+	var bonded int64
+	for _, bond := range append(dc.acct.pendingBonds, dc.acct.bonds...) {
+		tiers := int64(1) // an unsupported bond asset is considered strength one
+		if ba := bondAssets[bond.AssetID]; ba != nil {
+			tiers = int64(bond.Amount / ba.Amt)
+		}
+		byLockTime[bond.LockTime] += tiers
+		bonded += tiers
+	}
+	renewals := make([]*BondRenewal, 0, len(byLockTime)+1)
+	if missing := int64(dc.acct.targetTier) - bonded; missing > 0 {
+		renewals = append(renewals, &BondRenewal{Time: now, Tiers: missing})
+	}
+	for lockTime, tiers := range byLockTime {
+		renewTime := int64(lockTime) - bondExpiry - pendingBuffer(c.net)
+		if renewTime < now {
+			renewTime = now
+		}
+		renewals = append(renewals, &BondRenewal{Time: renewTime, Tiers: tiers})
+	}
+	sort.Slice(renewals, func(i, j int) bool {
+		return renewals[i].Time < renewals[j].Time
+	})
+	return renewals
+}
+
+// BondFundingPlan proposes how to fund the upcoming bond renewals of the DEX
+// accounts with a target tier. For each account, every bond asset supported by
+// the server with a connected wallet is considered. The wallets' balances are
+// allocated to the accounts in the order of their first renewal, and the
+// cheapest option by estimated fees that the wallet can fund is recommended.
+// When fiat rates are not available for all options, the account's current
+// bond asset is preferred if it is sufficient. Use ApplyBondFundingPlan to
+// switch the accounts' bond assets to the recommended assets.
+func (c *Core) BondFundingPlan() (*BondFundingPlan, error) {
+	now := time.Now().Unix()
+	fiatRates := c.fiatConversions()
+	// remaining tracks each wallet's funds that are not yet allocated.
+	remaining := make(map[uint32]uint64)
+	wallets := make(map[uint32]*xcWallet)
+	getWallet := func(assetID uint32) *xcWallet {
+		if w, found := wallets[assetID]; found {
+			return w
+		}
+		w, err := c.connectedWallet(assetID)
+		if err != nil {
+			w = nil
+		} else if _, is := w.Wallet.(asset.Bonder); !is {
+			w = nil
+		}
+		wallets[assetID] = w
+		if w != nil {
+			if bal := w.state().Balance; bal != nil && bal.Balance != nil {
+				remaining[assetID] = bal.Available + bal.BondReserves
+			}
+		}
+		return w
+	}
+
+	type hostPlan struct {
+		*HostBondPlan
+		bondAssets map[uint32]*BondAsset
+		bonded     map[uint32]bool // assets of the account's bonds
+	}
+	hostPlans := make([]*hostPlan, 0)
+	for _, dc := range c.dexConnections() {
+		if initialized, _ := dc.acct.status(); !initialized || dc.acct.isDisabled() {
+			continue
+		}
+		bondAssets, bondExpiry := dc.bondAssets()
+		if len(bondAssets) == 0 {
+			continue
+		}
+		dc.acct.authMtx.RLock()
+		if dc.acct.targetTier == 0 {
+			dc.acct.authMtx.RUnlock()
+			continue
+		}
+		hp := &hostPlan{
+			HostBondPlan: &HostBondPlan{
+				Host:        dc.acct.host,
+				BondAssetID: dc.acct.bondAsset,
+				TargetTier:  dc.acct.targetTier,
+				Renewals:    c.bondRenewals(dc, bondAssets, int64(bondExpiry), now),
+			},
+			bondAssets: bondAssets,
+			bonded:     make(map[uint32]bool),
+		}
+		for _, bond := range append(dc.acct.pendingBonds, dc.acct.bonds...) {
+			hp.bonded[bond.AssetID] = true
+		}
+		// Expired bonds will be refunded to their wallets.
+		expired := make([]*db.Bond, len(dc.acct.expiredBonds))
+		copy(expired, dc.acct.expiredBonds)
+		dc.acct.authMtx.RUnlock()
+
+		for _, bond := range expired {
+			if getWallet(bond.AssetID) != nil {
+				remaining[bond.AssetID] += bond.Amount
+			}
+		}
+		hostPlans = append(hostPlans, hp)
+	}
+
+	// Allocate funds to the accounts that renew first.
+	firstRenewal := func(hp *hostPlan) int64 {
+		if len(hp.Renewals) == 0 {
+			return 0
+		}
+		return hp.Renewals[0].Time
+	}
+	sort.SliceStable(hostPlans, func(i, j int) bool {
+		return firstRenewal(hostPlans[i]) < firstRenewal(hostPlans[j])
+	})
+
+	plan := &BondFundingPlan{Hosts: make([]*HostBondPlan, 0, len(hostPlans))}
+	for _, hp := range hostPlans {
+		var tiers int64
+		for _, r := range hp.Renewals {
+			tiers += r.Tiers
+		}
+		var best *BondFundingOption
+		haveAllRates := true
+		for assetID, ba := range hp.bondAssets {
+			w := getWallet(assetID)
+			if w == nil {
+				continue
+			}
+			fees := w.BondsFeeBuffer(c.feeSuggestionAny(assetID)) * uint64(len(hp.Renewals))
+			opt := &BondFundingOption{
+				AssetID:   assetID,
+				Symbol:    unbip(assetID),
+				Required:  ba.Amt*uint64(tiers) + fees,
+				Fees:      fees,
+				Available: remaining[assetID],
+			}
+			opt.Sufficient = opt.Available >= opt.Required
+			if rate := fiatRates[assetID]; rate > 0 {
+				opt.FiatFees = float64(fees) / float64(w.unitInfo().Conventional.ConversionFactor) * rate
+			} else {
+				haveAllRates = false
+			}
+			hp.Options = append(hp.Options, opt)
+		}
+		sort.Slice(hp.Options, func(i, j int) bool {
+			return hp.Options[i].AssetID < hp.Options[j].AssetID
+		})
+		for _, opt := range hp.Options {
+			if !opt.Sufficient {
+				continue
+			}
+			switch {
+			case best == nil:
+				best = opt
+			case !haveAllRates:
+				if opt.AssetID == hp.BondAssetID {
+					best = opt
+				}
+			case opt.FiatFees < best.FiatFees:
+				best = opt
+			}
+		}
+		if best != nil {
+			assetID := best.AssetID
+			hp.RecommendedAssetID = &assetID
+			remaining[assetID] -= best.Required
+			for bondAssetID := range hp.bonded {
+				if bondAssetID != assetID {
+					hp.Consolidate = true
+				}
+			}
+		}
+		plan.Hosts = append(plan.Hosts, hp.HostBondPlan)
+	}
+	return plan, nil
+}
+
+// ApplyBondFundingPlan switches the bond asset of each DEX account in the plan
+// to the plan's recommended asset. Accounts without a recommended asset or
+// that already use it are not changed. The plan is typically one returned by
+// BondFundingPlan and approved by the user.
+func (c *Core) ApplyBondFundingPlan(plan *BondFundingPlan) error {
+	if plan == nil {
+		return errors.New("no plan")
+	}
+	var errs []error
+	for _, hp := range plan.Hosts {
+		if hp.RecommendedAssetID == nil {
+			continue
+		}
+		dc, _, err := c.dex(hp.Host)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hp.Host, err))
+			continue
+		}
+		dc.acct.authMtx.RLock()
+		bondAssetID := dc.acct.bondAsset
+		dc.acct.authMtx.RUnlock()
+		assetID := *hp.RecommendedAssetID
+		if assetID == bondAssetID {
+			continue
+		}
+		if err := c.UpdateBondOptions(&BondOptionsForm{
+			Host:        hp.Host,
+			BondAssetID: &assetID,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hp.Host, err))
+			continue
+		}
+		c.log.Infof("Bond asset for %s switched from %s to %s by bond funding plan",
+			hp.Host, unbip(bondAssetID), unbip(assetID))
+	}
+	return errors.Join(errs...)
+}
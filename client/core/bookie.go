@@ -30,6 +30,10 @@ var (
 	outdatedClientErr = errors.New("outdated client")
 )
 
+// orderFlowWindow is the window of recent trades used for the order-flow
+// statistics sent with epoch match summaries.
+const orderFlowWindow = time.Hour
+
 // BookFeed manages a channel for receiving order book updates. It is imperative
 // that the feeder (BookFeed).Close() when no longer using the feed.
 type BookFeed interface {
@@ -193,6 +197,7 @@ func (b *bookie) logEpochReport(note *msgjson.EpochReportNote) error {
 		Payload: &EpochMatchSummaryPayload{
 			MatchSummaries: matchSummaries,
 			Epoch:          note.Epoch,
+			OrderFlow:      b.OrderFlow(orderFlowWindow),
 		},
 	})
 
@@ -260,39 +265,55 @@ func (b *bookie) closeFeeds() {
 // candles fetches the candle set from the server and activates the candle
 // cache.
 func (b *bookie) candles(durStr string, feedID uint32) error {
+	cache, err := b.syncCandles(durStr)
+	if err != nil {
+		return err
+	}
+	b.feedsMtx.RLock()
+	defer b.feedsMtx.RUnlock()
+	f, ok := b.feeds[feedID]
+	if !ok {
+		// Feed must have been closed in another thread.
+		return nil
+	}
+	dur, _ := time.ParseDuration(durStr)
+	cache.candleMtx.RLock()
+	cdls := cache.CandlesCopy()
+	cache.candleMtx.RUnlock()
+	f.c <- &BookUpdate{
+		Action:   FreshCandlesAction,
+		Host:     b.dc.acct.host,
+		MarketID: marketName(b.base, b.quote),
+		Payload: &CandlesPayload{
+			Dur:          durStr,
+			DurMilliSecs: uint64(dur.Milliseconds()),
+			Candles:      cdls,
+		},
+	}
+	return nil
+}
+
+// candlesCopy returns a copy of the candles for the bin size, oldest first,
+// fetching the candle set from the server if the cache is not yet active.
+func (b *bookie) candlesCopy(durStr string) ([]msgjson.Candle, error) {
+	cache, err := b.syncCandles(durStr)
+	if err != nil {
+		return nil, err
+	}
+	cache.candleMtx.RLock()
+	defer cache.candleMtx.RUnlock()
+	return cache.CandlesCopy(), nil
+}
+
+// syncCandles fetches the candle set from the server and activates the candle
+// cache for the bin size, if it is not already active.
+func (b *bookie) syncCandles(durStr string) (*candleCache, error) {
 	cache := b.candleCaches[durStr]
 	if cache == nil {
-		return fmt.Errorf("no candles for %s-%s %q", unbip(b.base), unbip(b.quote), durStr)
+		return nil, fmt.Errorf("no candles for %s-%s %q", unbip(b.base), unbip(b.quote), durStr)
 	}
-	var err error
-	defer func() {
-		if err != nil {
-			return
-		}
-		b.feedsMtx.RLock()
-		defer b.feedsMtx.RUnlock()
-		f, ok := b.feeds[feedID]
-		if !ok {
-			// Feed must have been closed in another thread.
-			return
-		}
-		dur, _ := time.ParseDuration(durStr)
-		cache.candleMtx.RLock()
-		cdls := cache.CandlesCopy()
-		cache.candleMtx.RUnlock()
-		f.c <- &BookUpdate{
-			Action:   FreshCandlesAction,
-			Host:     b.dc.acct.host,
-			MarketID: marketName(b.base, b.quote),
-			Payload: &CandlesPayload{
-				Dur:          durStr,
-				DurMilliSecs: uint64(dur.Milliseconds()),
-				Candles:      cdls,
-			},
-		}
-	}()
 	if atomic.LoadUint32(&cache.on) == 1 {
-		return nil
+		return cache, nil
 	}
 	// Subscribe to the feed.
 	payload := &msgjson.CandlesRequest{
@@ -302,13 +323,13 @@ func (b *bookie) candles(durStr string, feedID uint32) error {
 		NumCandles: candles.CacheSize,
 	}
 	wireCandles := new(msgjson.WireCandles)
-	err = sendRequest(b.dc.WsConn, msgjson.CandlesRoute, payload, wireCandles, DefaultResponseTimeout)
+	err := sendRequest(b.dc.WsConn, msgjson.CandlesRoute, payload, wireCandles, DefaultResponseTimeout)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	cache.init(wireCandles.Candles())
 	atomic.StoreUint32(&cache.on, 1)
-	return nil
+	return cache, nil
 }
 
 // closeFeed closes the specified feed, and if no more feeds are open, sets a
@@ -475,6 +496,7 @@ func (dc *dexConnection) subscribe(baseID, quoteID uint32) (*msgjson.OrderBook,
 	req, err := msgjson.NewRequest(dc.NextID(), msgjson.OrderBookRoute, &msgjson.OrderBookSubscription{
 		Base:  baseID,
 		Quote: quoteID,
+		Batch: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error encoding 'orderbook' request: %w", err)
@@ -560,6 +582,112 @@ func (c *Core) SyncBook(host string, base, quote uint32) (*orderbook.OrderBook,
 	return dc.syncBook(base, quote)
 }
 
+// OrderFlow computes order-flow statistics for the trades on a market within
+// the window before now. The market's book must be synced with SyncBook. A
+// zero window includes all trades in the book's trades tape.
+func (c *Core) OrderFlow(host string, base, quote uint32, window time.Duration) (*orderbook.OrderFlowStats, error) {
+	dc, _, err := c.dex(host)
+	if err != nil {
+		return nil, err
+	}
+	book := dc.bookie(marketName(base, quote))
+	if book == nil {
+		return nil, fmt.Errorf("no synced book for %s-%s at %s", unbip(base), unbip(quote), dc.acct.host)
+	}
+	return book.OrderFlow(window), nil
+}
+
+// maxIndicatorPeriod is the longest indicator period that can be requested,
+// which is the number of candles that are cached.
+const maxIndicatorPeriod = candles.CacheSize
+
+// Indicators computes the requested indicator series from the candles of a
+// market. If the market's book is not already synced, it is synced for long
+// enough to fetch the candles.
+func (c *Core) Indicators(form *IndicatorsForm) (*IndicatorSeries, error) {
+	checkPeriod := func(name string, period int) error {
+		if period <= 0 || period > maxIndicatorPeriod {
+			return fmt.Errorf("invalid %s period %d", name, period)
+		}
+		return nil
+	}
+	for _, period := range form.SMA {
+		if err := checkPeriod("SMA", period); err != nil {
+			return nil, err
+		}
+	}
+	for _, period := range form.EMA {
+		if err := checkPeriod("EMA", period); err != nil {
+			return nil, err
+		}
+	}
+	if form.Bollinger != nil {
+		if err := checkPeriod("Bollinger", form.Bollinger.Period); err != nil {
+			return nil, err
+		}
+		if form.Bollinger.StdDevs <= 0 {
+			return nil, fmt.Errorf("invalid Bollinger standard deviations %f", form.Bollinger.StdDevs)
+		}
+	}
+	if form.VolumeProfileBins < 0 || form.VolumeProfileBins > maxIndicatorPeriod {
+		return nil, fmt.Errorf("invalid number of volume profile bins %d", form.VolumeProfileBins)
+	}
+	dur, err := time.ParseDuration(form.BinSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bin size %q: %w", form.BinSize, err)
+	}
+
+	dc, _, err := c.dex(form.Host)
+	if err != nil {
+		return nil, err
+	}
+	mktID := marketName(form.Base, form.Quote)
+	book := dc.bookie(mktID)
+	if book == nil {
+		_, feed, err := dc.syncBook(form.Base, form.Quote)
+		if err != nil {
+			return nil, fmt.Errorf("error syncing %s book: %w", mktID, err)
+		}
+		// Closing the feed leaves the book synced until the close timer
+		// expires.
+		feed.Close()
+		if book = dc.bookie(mktID); book == nil {
+			return nil, fmt.Errorf("no synced book for %s at %s", mktID, dc.acct.host)
+		}
+	}
+	cdls, err := book.candlesCopy(form.BinSize)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &IndicatorSeries{
+		Host:         dc.acct.host,
+		MarketID:     mktID,
+		BinSize:      form.BinSize,
+		DurMilliSecs: uint64(dur.Milliseconds()),
+		Candles:      cdls,
+	}
+	if len(form.SMA) > 0 {
+		series.SMA = make(map[int][]candles.SeriesPoint, len(form.SMA))
+		for _, period := range form.SMA {
+			series.SMA[period] = candles.SMA(cdls, period)
+		}
+	}
+	if len(form.EMA) > 0 {
+		series.EMA = make(map[int][]candles.SeriesPoint, len(form.EMA))
+		for _, period := range form.EMA {
+			series.EMA[period] = candles.EMA(cdls, period)
+		}
+	}
+	if form.Bollinger != nil {
+		series.Bollinger = candles.BollingerBands(cdls, form.Bollinger.Period, form.Bollinger.StdDevs)
+	}
+	if form.VolumeProfileBins > 0 {
+		series.VolumeProfile = candles.VolumeProfile(cdls, form.VolumeProfileBins)
+	}
+	return series, nil
+}
+
 // Book fetches the order book. If a subscription doesn't exist, one will be
 // attempted and immediately closed.
 func (c *Core) Book(dex string, base, quote uint32) (*OrderBook, error) {
@@ -631,13 +759,17 @@ func handleBookOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error
 	if err != nil {
 		return fmt.Errorf("book order note unmarshal error: %w", err)
 	}
+	return dc.bookOrder(note)
+}
 
+// bookOrder adds the order to the order book and updates the book feeds.
+func (dc *dexConnection) bookOrder(note *msgjson.BookOrderNote) error {
 	book := dc.bookie(note.MarketID)
 	if book == nil {
 		return fmt.Errorf("no order book found with market id '%v'",
 			note.MarketID)
 	}
-	err = book.Book(note)
+	err := book.Book(note)
 	if err != nil {
 		return err
 	}
@@ -720,7 +852,8 @@ func handleTradeSuspensionMsg(c *Core, dc *dexConnection, msg *msgjson.Message)
 		// This is just a warning about a scheduled suspension.
 		suspendTime := time.UnixMilli(int64(sp.SuspendTime))
 		subject, detail := c.formatDetails(TopicMarketSuspendScheduled, sp.MarketID, dc.acct.host, suspendTime)
-		c.notify(newServerNotifyNote(TopicMarketSuspendScheduled, subject, detail, db.WarningLevel))
+		c.notify(withData(newServerNotifyNote(TopicMarketSuspendScheduled, subject, detail, db.WarningLevel),
+			&db.NoteData{Host: dc.acct.host, MarketID: sp.MarketID}))
 		return nil
 	}
 
@@ -729,7 +862,8 @@ func handleTradeSuspensionMsg(c *Core, dc *dexConnection, msg *msgjson.Message)
 		topic = TopicMarketSuspendedWithPurge
 	}
 	subject, detail := c.formatDetails(topic, sp.MarketID, dc.acct.host)
-	c.notify(newServerNotifyNote(topic, subject, detail, db.WarningLevel))
+	c.notify(withData(newServerNotifyNote(topic, subject, detail, db.WarningLevel),
+		&db.NoteData{Host: dc.acct.host, MarketID: sp.MarketID}))
 
 	if sp.Persist {
 		// No book changes. Just wait for more order notes.
@@ -810,7 +944,8 @@ func handleTradeResumptionMsg(c *Core, dc *dexConnection, msg *msgjson.Message)
 		dc.setMarketStartEpoch(rs.MarketID, rs.StartEpoch, false) // set the start epoch, leaving any final/persist data
 		resTime := time.UnixMilli(int64(rs.ResumeTime))
 		subject, detail := c.formatDetails(TopicMarketResumeScheduled, rs.MarketID, dc.acct.host, resTime)
-		c.notify(newServerNotifyNote(TopicMarketResumeScheduled, subject, detail, db.WarningLevel))
+		c.notify(withData(newServerNotifyNote(TopicMarketResumeScheduled, subject, detail, db.WarningLevel),
+			&db.NoteData{Host: dc.acct.host, MarketID: rs.MarketID}))
 		return nil
 	}
 
@@ -828,7 +963,8 @@ func handleTradeResumptionMsg(c *Core, dc *dexConnection, msg *msgjson.Message)
 	// dc.refreshServerConfig()
 
 	subject, detail := c.formatDetails(TopicMarketResumed, rs.MarketID, dc.acct.host, rs.StartEpoch)
-	c.notify(newServerNotifyNote(TopicMarketResumed, subject, detail, db.Success))
+	c.notify(withData(newServerNotifyNote(TopicMarketResumed, subject, detail, db.Success),
+		&db.NoteData{Host: dc.acct.host, MarketID: rs.MarketID}))
 
 	// Book notes may resume at any time. Seq not set since no book changes.
 
@@ -839,33 +975,84 @@ func (dc *dexConnection) apiVersion() int32 {
 	return atomic.LoadInt32(&dc.apiVer)
 }
 
+// negotiateAPIVersion requests a communications API version for the connection
+// from the server. If the server does not support version negotiation or none
+// of our versions, the bool is false, and the server's APIVersion in the
+// config response should be checked instead.
+func (dc *dexConnection) negotiateAPIVersion() (int32, bool, error) {
+	req := &msgjson.VersionRequest{Versions: make([]uint16, 0, len(supportedAPIVers))}
+	for _, ver := range supportedAPIVers {
+		req.Versions = append(req.Versions, uint16(ver))
+	}
+	res := new(msgjson.VersionResult)
+	err := sendRequest(dc.WsConn, msgjson.VersionRoute, req, res, DefaultResponseTimeout)
+	if err != nil {
+		var mErr *msgjson.Error
+		if errors.As(err, &mErr) && (mErr.Code == msgjson.RPCUnknownRoute || mErr.Code == msgjson.RPCVersionUnsupported) {
+			dc.log.Debugf("Server %v did not negotiate an API version: %v", dc.acct.host, mErr)
+			return -1, false, nil
+		}
+		return -1, false, fmt.Errorf("unable to negotiate API version: %w", err)
+	}
+	for _, ver := range supportedAPIVers {
+		if int32(res.Version) == ver {
+			return ver, true, nil
+		}
+	}
+	return -1, false, fmt.Errorf("server selected unrequested API version %d", res.Version)
+}
+
 // refreshServerConfig fetches and replaces server configuration data. It also
-// initially checks that a server's API version is one of serverAPIVers.
+// negotiates the API version for the connection, or if the server does not
+// support negotiation, checks that the server's API version is one of
+// supportedAPIVers.
 func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
+	apiVer, negotiated, err := dc.negotiateAPIVersion()
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch the updated DEX configuration.
 	cfg := new(msgjson.ConfigResult)
-	err := sendRequest(dc.WsConn, msgjson.ConfigRoute, nil, cfg, DefaultResponseTimeout)
+	err = sendRequest(dc.WsConn, msgjson.ConfigRoute, nil, cfg, DefaultResponseTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch server config: %w", err)
 	}
 
-	apiVer := int32(cfg.APIVersion)
-	dc.log.Infof("Server %v supports API version %v.", dc.acct.host, cfg.APIVersion)
-	atomic.StoreInt32(&dc.apiVer, apiVer)
-
-	// Check that we are able to communicate with this DEX.
-	var supported bool
-	for _, ver := range supportedAPIVers {
-		if apiVer == ver {
-			supported = true
+	// A server reached at an alternate address must be the same DEX.
+	if dc.acct.dexPubKey != nil && len(cfg.DEXPubKey) > 0 {
+		pubKey, err := secp256k1.ParsePubKey(cfg.DEXPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding secp256k1 PublicKey from bytes: %w", err)
+		}
+		if !pubKey.IsEqual(dc.acct.dexPubKey) {
+			return nil, fmt.Errorf("server at %s is not the DEX at %s (public key mismatch)",
+				dc.Stats().Endpoint, dc.acct.host)
 		}
 	}
-	if !supported {
-		err := fmt.Errorf("unsupported server API version %v", apiVer)
-		if apiVer > supportedAPIVers[len(supportedAPIVers)-1] {
-			err = fmt.Errorf("%v: %w", err, outdatedClientErr)
+
+	if negotiated {
+		dc.log.Infof("Negotiated API version %v with server %v.", apiVer, dc.acct.host)
+		atomic.StoreInt32(&dc.apiVer, apiVer)
+	} else {
+		apiVer = int32(cfg.APIVersion)
+		dc.log.Infof("Server %v supports API version %v.", dc.acct.host, cfg.APIVersion)
+		atomic.StoreInt32(&dc.apiVer, apiVer)
+
+		// Check that we are able to communicate with this DEX.
+		var supported bool
+		for _, ver := range supportedAPIVers {
+			if apiVer == ver {
+				supported = true
+			}
+		}
+		if !supported {
+			err := fmt.Errorf("unsupported server API version %v", apiVer)
+			if apiVer > supportedAPIVers[len(supportedAPIVers)-1] {
+				err = fmt.Errorf("%v: %w", err, outdatedClientErr)
+			}
+			return nil, err
 		}
-		return nil, err
 	}
 
 	bTimeout := time.Millisecond * time.Duration(cfg.BroadcastTimeout)
@@ -964,13 +1151,18 @@ func handleUnbookOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) erro
 	if err != nil {
 		return fmt.Errorf("unbook order note unmarshal error: %w", err)
 	}
+	return dc.unbookOrder(note)
+}
 
+// unbookOrder removes the order from the order book and updates the book
+// feeds.
+func (dc *dexConnection) unbookOrder(note *msgjson.UnbookOrderNote) error {
 	book := dc.bookie(note.MarketID)
 	if book == nil {
 		return fmt.Errorf("no order book found with market id %q",
 			note.MarketID)
 	}
-	err = book.Unbook(note)
+	err := book.Unbook(note)
 	if err != nil {
 		return err
 	}
@@ -992,13 +1184,18 @@ func handleUpdateRemainingMsg(_ *Core, dc *dexConnection, msg *msgjson.Message)
 	if err != nil {
 		return fmt.Errorf("book order note unmarshal error: %w", err)
 	}
+	return dc.updateRemaining(note)
+}
 
+// updateRemaining updates the remaining quantity of a booked order and updates
+// the book feeds.
+func (dc *dexConnection) updateRemaining(note *msgjson.UpdateRemainingNote) error {
 	book := dc.bookie(note.MarketID)
 	if book == nil {
 		return fmt.Errorf("no order book found with market id '%v'",
 			note.MarketID)
 	}
-	err = book.UpdateRemaining(note)
+	err := book.UpdateRemaining(note)
 	if err != nil {
 		return err
 	}
@@ -1015,6 +1212,51 @@ func handleUpdateRemainingMsg(_ *Core, dc *dexConnection, msg *msgjson.Message)
 	return nil
 }
 
+// handleBookUpdateBatchMsg is called when a book_update_batch notification is
+// received. The updates are applied in order. Updates that were already applied,
+// e.g. those included in the order book snapshot, are skipped.
+func handleBookUpdateBatchMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error {
+	batch := new(msgjson.BookUpdateBatch)
+	err := msg.Unmarshal(batch)
+	if err != nil {
+		return fmt.Errorf("book update batch unmarshal error: %w", err)
+	}
+
+	book := dc.bookie(batch.MarketID)
+	if book == nil {
+		return fmt.Errorf("no order book found with market id '%v'",
+			batch.MarketID)
+	}
+	for _, u := range batch.Updates {
+		if book.Stale(u.Seq()) {
+			continue
+		}
+		switch {
+		case u.Book != nil:
+			if u.Book.MarketID != batch.MarketID {
+				return fmt.Errorf("book order for market %s in %s batch", u.Book.MarketID, batch.MarketID)
+			}
+			err = dc.bookOrder(u.Book)
+		case u.Unbook != nil:
+			if u.Unbook.MarketID != batch.MarketID {
+				return fmt.Errorf("unbook order for market %s in %s batch", u.Unbook.MarketID, batch.MarketID)
+			}
+			err = dc.unbookOrder(u.Unbook)
+		case u.UpdateRemaining != nil:
+			if u.UpdateRemaining.MarketID != batch.MarketID {
+				return fmt.Errorf("update remaining for market %s in %s batch", u.UpdateRemaining.MarketID, batch.MarketID)
+			}
+			err = dc.updateRemaining(u.UpdateRemaining)
+		default:
+			return fmt.Errorf("empty update in %s batch", batch.MarketID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // handleEpochReportMsg is called when an epoch_report notification is received.
 func handleEpochReportMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	note := new(msgjson.EpochReportNote)
@@ -0,0 +1,126 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+)
+
+// maxConfTarget is the largest explicit confirmation target, in blocks.
+const maxConfTarget = 1008
+
+// confSpeedBlocks are the confirmation targets, in blocks, for the named
+// speeds.
+var confSpeedBlocks = map[string]uint64{
+	db.ConfSpeedFast:    1,
+	db.ConfSpeedNormal:  3,
+	db.ConfSpeedEconomy: 12,
+}
+
+// confTargetBlocks is the number of blocks for the ConfTarget.
+func confTargetBlocks(target *db.ConfTarget) (uint64, error) {
+	if target.Blocks > 0 {
+		if target.Speed != "" {
+			return 0, fmt.Errorf("set a speed or a number of blocks, not both")
+		}
+		if target.Blocks > maxConfTarget {
+			return 0, fmt.Errorf("confirmation target of %d blocks exceeds the maximum of %d", target.Blocks, maxConfTarget)
+		}
+		return target.Blocks, nil
+	}
+	blocks, found := confSpeedBlocks[target.Speed]
+	if !found {
+		return 0, fmt.Errorf("unknown confirmation speed %q", target.Speed)
+	}
+	return blocks, nil
+}
+
+// ConfTargets returns the user's default confirmation targets for sends, keyed
+// by asset ID.
+func (c *Core) ConfTargets() map[uint32]*db.ConfTarget {
+	c.confTargetsMtx.RLock()
+	defer c.confTargetsMtx.RUnlock()
+	targets := make(map[uint32]*db.ConfTarget, len(c.confTargets))
+	for assetID, t := range c.confTargets {
+		tCopy := *t
+		targets[assetID] = &tCopy
+	}
+	return targets
+}
+
+// confTarget is the user's confirmation target for the asset, or nil if there
+// is none.
+func (c *Core) confTarget(assetID uint32) *db.ConfTarget {
+	c.confTargetsMtx.RLock()
+	defer c.confTargetsMtx.RUnlock()
+	return c.confTargets[assetID]
+}
+
+// SetConfTarget sets the default confirmation target that drives the fee rate
+// for sends and withdraws from the asset's wallet. The wallet must be able to
+// estimate fee rates for a confirmation target. The targets are persisted. A
+// nil or zero-valued target restores the default fee rate selection.
+func (c *Core) SetConfTarget(assetID uint32, target *db.ConfTarget) error {
+	if !target.IsZero() {
+		if _, err := confTargetBlocks(target); err != nil {
+			return err
+		}
+		wallet, found := c.wallet(assetID)
+		if !found {
+			return newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+		}
+		if _, is := wallet.Wallet.(asset.TargetFeeRater); !is {
+			return fmt.Errorf("%s wallet does not support confirmation targets", unbip(assetID))
+		}
+	}
+	c.confTargetsMtx.Lock()
+	defer c.confTargetsMtx.Unlock()
+	updated := make(map[uint32]*db.ConfTarget, len(c.confTargets)+1)
+	for id, t := range c.confTargets {
+		updated[id] = t
+	}
+	if target.IsZero() {
+		delete(updated, assetID)
+	} else {
+		tCopy := *target
+		updated[assetID] = &tCopy
+	}
+	if err := c.db.SetConfTargets(updated); err != nil {
+		return fmt.Errorf("error storing confirmation targets: %w", err)
+	}
+	c.confTargets = updated
+	return nil
+}
+
+// sendFeeRate is the fee rate suggestion for a send or withdraw from the
+// wallet. If the user has set a confirmation target for the asset, the
+// wallet's estimate for the target is used. Otherwise, or if the wallet can't
+// provide an estimate, the suggestion is the same as for any other
+// transaction.
+func (c *Core) sendFeeRate(wallet *xcWallet) uint64 {
+	assetID := wallet.AssetID
+	target := c.confTarget(assetID)
+	if target == nil {
+		return c.feeSuggestionAny(assetID)
+	}
+	feeRater, is := wallet.Wallet.(asset.TargetFeeRater)
+	if !is {
+		return c.feeSuggestionAny(assetID)
+	}
+	blocks, err := confTargetBlocks(target)
+	if err != nil {
+		c.log.Errorf("Invalid %s confirmation target: %v", unbip(assetID), err)
+		return c.feeSuggestionAny(assetID)
+	}
+	rate, err := feeRater.TargetFeeRate(blocks)
+	if err != nil || rate == 0 {
+		c.log.Warnf("Failed to get %s fee rate for a %d block confirmation target. Using the default fee rate: %v",
+			unbip(assetID), blocks, err)
+		return c.feeSuggestionAny(assetID)
+	}
+	return rate
+}
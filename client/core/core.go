@@ -31,6 +31,7 @@ import (
 	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/db/bolt"
+	"decred.org/dcrdex/client/db/sqlite"
 	"decred.org/dcrdex/client/mnemonic"
 	"decred.org/dcrdex/client/orderbook"
 	"decred.org/dcrdex/dex"
@@ -77,6 +78,12 @@ const (
 	// connection anomaly count is increased.
 	wsAnomalyDuration = 60 * time.Minute
 
+	// slowServerRTT is the average request round-trip time above which the
+	// client receives a notification that a server is responding slowly. The
+	// notification is not repeated until the average drops below half of
+	// slowServerRTT.
+	slowServerRTT = 5 * time.Second
+
 	// This is a configurable server parameter, but we're assuming servers have
 	// changed it from the default , We're using this for the v1 ConnectResult,
 	// where we don't have the necessary information to calculate our bonded
@@ -102,6 +109,11 @@ var (
 	// When waiting for a wallet to sync, a SyncStatus check will be performed
 	// every syncTickerPeriod. var instead of const for testing purposes.
 	syncTickerPeriod = 3 * time.Second
+	// After an epoch's match_proof, the matches expected for our orders from
+	// rerunning the epoch's matching cycle are awaited for up to
+	// epochMatchesTimeout, checking every epochMatchesPollInterval.
+	epochMatchesTimeout      = DefaultResponseTimeout
+	epochMatchesPollInterval = time.Second / 4
 	// supportedAPIVers are the DEX server API versions this client is capable
 	// of communicating with.
 	//
@@ -203,6 +215,18 @@ type dexConnection struct {
 	anomaliesCount uint32 // atomic
 	lastConnectMtx sync.RWMutex
 	lastConnect    time.Time
+
+	// slowServer is set when the user has been notified of slow responses
+	// from the server.
+	slowServer atomic.Bool
+
+	// altHostsV holds the []string of alternate addresses for the server.
+	altHostsV atomic.Value
+
+	// connectResult is the *msgjson.ConnectResult of the last successful
+	// 'connect' request. It is used to report on the account's active orders
+	// and matches after a recovery.
+	connectResult atomic.Pointer[msgjson.ConnectResult]
 }
 
 // DefaultResponseTimeout is the default timeout for responses after a request is
@@ -223,6 +247,12 @@ func (dc *dexConnection) running(mkt string) bool {
 	return mktCfg.Running()
 }
 
+// altHosts returns the alternate addresses configured for the server.
+func (dc *dexConnection) altHosts() []string {
+	hosts, _ := dc.altHostsV.Load().([]string)
+	return hosts
+}
+
 // status returns the status of the connection to the dex.
 func (dc *dexConnection) status() comms.ConnectionStatus {
 	return comms.ConnectionStatus(atomic.LoadUint32(&dc.connectionStatus))
@@ -479,6 +509,7 @@ func (c *Core) exchangeInfo(dc *dexConnection) *Exchange {
 	if cfg == nil { // no config, assets, or markets data
 		return &Exchange{
 			Host:             dc.acct.host,
+			AltHosts:         dc.altHosts(),
 			AcctID:           acctID,
 			ConnectionStatus: dc.status(),
 			Disabled:         dc.acct.isDisabled(),
@@ -512,6 +543,7 @@ func (c *Core) exchangeInfo(dc *dexConnection) *Exchange {
 
 	return &Exchange{
 		Host:             dc.acct.host,
+		AltHosts:         dc.altHosts(),
 		AcctID:           acctID,
 		Markets:          dc.marketMap(),
 		Assets:           assets,
@@ -1433,6 +1465,9 @@ type Config struct {
 	// DBPath is a filepath to use for the client database. If the database does
 	// not already exist, it will be created.
 	DBPath string
+	// DBBackend is the database backend, DBBackendBolt or DBBackendSQLite.
+	// The default is DBBackendBolt.
+	DBBackend string
 	// Net is the current network.
 	Net dex.Network
 	// Logger is the Core's logger and is also used to create the sub-loggers
@@ -1448,6 +1483,13 @@ type Config struct {
 	TorIsolation bool
 	// Language. A BCP 47 language tag. Default is en-US.
 	Language string
+	// LocalesDir is a directory of JSON notification translation files, named
+	// by language tag, e.g. pt-BR.json. Translations in these files are loaded
+	// at startup, in addition to the built-in translations.
+	LocalesDir string
+	// NoteRetention is how long notifications are kept in the database. Zero
+	// means notifications are kept forever.
+	NoteRetention time.Duration
 
 	// NoAutoWalletLock instructs Core to skip locking the wallet on shutdown or
 	// logout. This can be helpful if the user wants the wallet to remain
@@ -1459,6 +1501,15 @@ type Config struct {
 	// on shutdown. This is useful if the consumer is using the BackupDB method,
 	// or simply creating manual backups of the DB file after shutdown.
 	NoAutoDBBackup bool // zero value is legacy behavior
+	// BackupDir is the directory for the encrypted backups of the database.
+	// The default is an "encrypted-backups" folder next to the database.
+	BackupDir string
+	// BackupInterval is how often an encrypted backup is created while logged
+	// in. Zero disables scheduled backups.
+	BackupInterval time.Duration
+	// BackupsKept is the number of encrypted backups to keep. Older backups
+	// are deleted. Zero keeps all backups.
+	BackupsKept int
 	// UnlockCoinsOnLogin indicates that on wallet connect during login, or on
 	// creation of a new wallet, all coins with the wallet should be unlocked.
 	UnlockCoinsOnLogin bool
@@ -1466,6 +1517,12 @@ type Config struct {
 	// for running core in extension mode, which gives the caller options for
 	// e.g. limiting the ability to configure wallets.
 	ExtensionModeFile string
+	// PreferCBOR requests the binary CBOR message encoding from DEX servers.
+	// Servers that do not support it will continue to use JSON.
+	PreferCBOR bool
+	// QUIC uses QUIC connections with DEX servers that advertise QUIC in their
+	// websocket handshake. See comms.WsCfg.
+	QUIC bool
 
 	TheOneHost string
 }
@@ -1475,6 +1532,9 @@ type locale struct {
 	lang    language.Tag
 	m       map[Topic]*translation
 	printer *message.Printer
+	// fallbacks are the locales of the languages to use, in order, for topics
+	// that have no translation in m. See fallbackChain.
+	fallbacks []*locale
 }
 
 // Core is the core client application. Core manages DEX connections, wallets,
@@ -1501,6 +1561,9 @@ type Core struct {
 	loginMtx  sync.Mutex
 	loggedIn  bool
 	bondXPriv *hdkeychain.ExtendedKey // derived from creds.EncSeed on login
+	backupKey []byte                  // derived from creds.EncSeed on login
+
+	backupMtx sync.Mutex // serializes BackupNow
 
 	seedGenerationTime uint64
 
@@ -1523,6 +1586,20 @@ type Core struct {
 
 	noteMtx   sync.RWMutex
 	noteChans map[uint64]chan Notification
+	// desktopChans receive the notifications for topics that are opted in to
+	// desktop notifications.
+	desktopChans map[uint64]chan Notification
+
+	topicSettingsMtx sync.RWMutex
+	topicSettings    map[Topic]*db.TopicSettings
+
+	noteTemplatesMtx sync.RWMutex
+	noteTemplates    map[Topic]*db.NoteTemplate
+
+	confTargetsMtx sync.RWMutex
+	confTargets    map[uint32]*db.ConfTarget
+
+	noteAgg *noteAggregator
 
 	sentCommitsMtx sync.Mutex
 	sentCommits    map[order.Commitment]chan struct{}
@@ -1542,20 +1619,43 @@ type Core struct {
 	requestedActionMtx sync.RWMutex
 	requestedActions   map[string]*asset.ActionRequiredNote
 
+	// tradingLimitsMtx guards the trading limit watcher state. See
+	// watchTradingLimits.
+	tradingLimitsMtx     sync.Mutex
+	tradingLimitsPending map[string]bool
+	tradingLimits        map[string]*TradingLimitStatus
+	tradingLimitsCheck   chan struct{}
+
 	// previouslyFailedTradeAttempt is used to track last trade user tried to place but
 	// failed to pass all validation rules
 	previouslyFailedTradeAttempt atomic.Pointer[tradeAttempt]
 }
 
+// Database backends for Config.DBBackend.
+const (
+	DBBackendBolt   = "bolt"
+	DBBackendSQLite = "sqlite"
+)
+
+// openDB opens the database at the path with the backend, DBBackendBolt or
+// DBBackendSQLite. An empty backend is DBBackendBolt.
+func openDB(backend, path string, logger dex.Logger, backupOnShutdown bool) (db.DB, error) {
+	switch backend {
+	case "", DBBackendBolt:
+		return bolt.NewDB(path, logger, bolt.Opts{BackupOnShutdown: backupOnShutdown})
+	case DBBackendSQLite:
+		return sqlite.NewDB(path, logger, sqlite.Opts{BackupOnShutdown: backupOnShutdown})
+	default:
+		return nil, fmt.Errorf("unknown database backend %q", backend)
+	}
+}
+
 // New is the constructor for a new Core.
 func New(cfg *Config) (*Core, error) {
 	if cfg.Logger == nil {
 		return nil, fmt.Errorf("Core.Config must specify a Logger")
 	}
-	dbOpts := bolt.Opts{
-		BackupOnShutdown: !cfg.NoAutoDBBackup,
-	}
-	boltDB, err := bolt.NewDB(cfg.DBPath, cfg.Logger.SubLogger("DB"), dbOpts)
+	clientDB, err := openDB(cfg.DBBackend, cfg.DBPath, cfg.Logger.SubLogger("DB"), !cfg.NoAutoDBBackup)
 	if err != nil {
 		return nil, fmt.Errorf("database initialization error: %w", err)
 	}
@@ -1572,13 +1672,19 @@ func New(cfg *Config) (*Core, error) {
 		cfg.Onion = cfg.TorProxy
 	}
 
+	if cfg.LocalesDir != "" {
+		if _, err := loadLocales(cfg.LocalesDir, cfg.Logger); err != nil {
+			return nil, fmt.Errorf("error loading locales from %s: %w", cfg.LocalesDir, err)
+		}
+	}
+
 	parseLanguage := func(langStr string) (language.Tag, error) {
 		acceptLang, err := language.Parse(langStr)
 		if err != nil {
 			return language.Und, fmt.Errorf("unable to parse requested language: %w", err)
 		}
 		var langs []language.Tag
-		for locale := range locales {
+		for _, locale := range localeLangs() {
 			tag, err := language.Parse(locale)
 			if err != nil {
 				return language.Und, fmt.Errorf("bad %v: %w", locale, err)
@@ -1601,7 +1707,7 @@ func New(cfg *Config) (*Core, error) {
 	lang := language.Und
 
 	// Check if the user has set a language with SetLanguage.
-	if langStr, err := boltDB.Language(); err != nil {
+	if langStr, err := clientDB.Language(); err != nil {
 		cfg.Logger.Errorf("Error loading language from database: %v", err)
 	} else if len(langStr) > 0 {
 		if lang, err = parseLanguage(langStr); err != nil {
@@ -1624,23 +1730,41 @@ func New(cfg *Config) (*Core, error) {
 
 	cfg.Logger.Debugf("Using locale printer for %q", lang)
 
-	translations, found := locales[lang.String()]
-	if !found {
-		return nil, fmt.Errorf("no translations for language %s", lang)
+	loc, err := newLocale(lang)
+	if err != nil {
+		return nil, err
 	}
 
 	// Try to get the primary credentials, but ignore no-credentials error here
 	// because the client may not be initialized.
-	creds, err := boltDB.PrimaryCredentials()
+	creds, err := clientDB.PrimaryCredentials()
 	if err != nil && !errors.Is(err, db.ErrNoCredentials) {
 		return nil, err
 	}
 
-	seedGenerationTime, err := boltDB.SeedGenerationTime()
+	seedGenerationTime, err := clientDB.SeedGenerationTime()
 	if err != nil && !errors.Is(err, db.ErrNoSeedGenTime) {
 		return nil, err
 	}
 
+	topicSettings, err := clientDB.TopicSettings()
+	if err != nil {
+		cfg.Logger.Errorf("Error loading notification settings from database: %v", err)
+		topicSettings = make(map[Topic]*db.TopicSettings)
+	}
+
+	noteTemplates, err := clientDB.NoteTemplates()
+	if err != nil {
+		cfg.Logger.Errorf("Error loading notification templates from database: %v", err)
+		noteTemplates = make(map[Topic]*db.NoteTemplate)
+	}
+
+	confTargets, err := clientDB.ConfTargets()
+	if err != nil {
+		cfg.Logger.Errorf("Error loading confirmation targets from database: %v", err)
+		confTargets = make(map[uint32]*db.ConfTarget)
+	}
+
 	var xCfg *ExtensionModeConfig
 	if cfg.ExtensionModeFile != "" {
 		b, err := os.ReadFile(cfg.ExtensionModeFile)
@@ -1658,7 +1782,7 @@ func New(cfg *Config) (*Core, error) {
 		ready:         make(chan struct{}),
 		rotate:        make(chan struct{}, 1),
 		log:           cfg.Logger,
-		db:            boltDB,
+		db:            clientDB,
 		conns:         make(map[string]*dexConnection),
 		wallets:       make(map[uint32]*xcWallet),
 		net:           cfg.Net,
@@ -1673,6 +1797,10 @@ func New(cfg *Config) (*Core, error) {
 		reCrypter:     encrypt.Deserialize,
 		latencyQ:      wait.NewTickerQueue(recheckInterval),
 		noteChans:     make(map[uint64]chan Notification),
+		desktopChans:  make(map[uint64]chan Notification),
+		topicSettings: topicSettings,
+		noteTemplates: noteTemplates,
+		confTargets:   confTargets,
 
 		extensionModeConfig: xCfg,
 		seedGenerationTime:  seedGenerationTime,
@@ -1683,13 +1811,14 @@ func New(cfg *Config) (*Core, error) {
 
 		notes:            make(chan asset.WalletNotification, 128),
 		requestedActions: make(map[string]*asset.ActionRequiredNote),
+
+		tradingLimitsPending: make(map[string]bool),
+		tradingLimits:        make(map[string]*TradingLimitStatus),
+		tradingLimitsCheck:   make(chan struct{}, 1),
 	}
 
-	c.intl.Store(&locale{
-		lang:    lang,
-		m:       translations,
-		printer: message.NewPrinter(lang),
-	})
+	c.intl.Store(loc)
+	c.noteAgg = newNoteAggregator(noteAggregationWindow, c.sendAggregateNote)
 
 	// Populate the initial user data. User won't include any DEX info yet, as
 	// those are retrieved when Run is called and the core connects to the DEXes.
@@ -1773,6 +1902,31 @@ fetchers:
 		}
 	}()
 
+	// Delete notifications that are past the retention period.
+	if c.cfg.NoteRetention > 0 {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.watchNoteRetention(ctx)
+		}()
+	}
+
+	// Create encrypted backups periodically.
+	if c.cfg.BackupInterval > 0 {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.watchBackups(ctx)
+		}()
+	}
+
+	// Keep trading limit usage updated.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.watchTradingLimits(ctx)
+	}()
+
 	// Start bond supervisor.
 	c.wg.Add(1)
 	go func() {
@@ -1877,17 +2031,14 @@ func (c *Core) SetLanguage(lang string) error {
 		return fmt.Errorf("error parsing language %q: %w", lang, err)
 	}
 
-	translations, found := locales[lang]
-	if !found {
-		return fmt.Errorf("no translations for language %s", lang)
+	loc, err := newLocale(tag)
+	if err != nil {
+		return err
 	}
 	if err := c.db.SetLanguage(lang); err != nil {
 		return fmt.Errorf("error storing language: %w", err)
 	}
-	c.intl.Store(&locale{
-		m:       translations,
-		printer: message.NewPrinter(tag),
-	})
+	c.intl.Store(loc)
 	return nil
 }
 
@@ -1994,6 +2145,19 @@ func (c *Core) Exchange(host string) (*Exchange, error) {
 	return c.exchangeInfo(dc), nil
 }
 
+// ConnectionStats returns round-trip time, reconnect, and message backlog
+// measurements for the connection to the DEX server at host.
+func (c *Core) ConnectionStats(host string) (*comms.ConnectionStats, error) {
+	dc, _, err := c.dex(host)
+	if err != nil {
+		return nil, err
+	}
+	if dc.WsConn == nil {
+		return nil, fmt.Errorf("no connection to %s", dc.acct.host)
+	}
+	return dc.Stats(), nil
+}
+
 // ExchangeMarket returns the market with the given base and quote assets at the
 // given host. It returns an error if no market exists at that host.
 func (c *Core) ExchangeMarket(host string, baseID, quoteID uint32) (*Market, error) {
@@ -3023,7 +3187,8 @@ func (c *Core) loadWallet(dbWallet *db.Wallet) (*xcWallet, error) {
 	if err != nil {
 		if errors.Is(err, asset.ErrWalletTypeDisabled) {
 			subject, details := c.formatDetails(TopicWalletTypeDeprecated, unbip(assetID))
-			c.notify(newWalletConfigNote(TopicWalletTypeDeprecated, subject, details, db.WarningLevel, nil))
+			c.notify(withData(newWalletConfigNote(TopicWalletTypeDeprecated, subject, details, db.WarningLevel, nil),
+				&db.NoteData{AssetID: &assetID, Error: err.Error()}))
 		}
 		return nil, fmt.Errorf("error opening wallet: %w", err)
 	}
@@ -3176,13 +3341,26 @@ func (c *Core) walletCheckAndNotify(w *xcWallet) bool {
 		return false
 	}
 
+	start, height, target := w.syncHeights(ss)
+	now := time.Now()
+
 	w.mtx.Lock()
 	wasSynced := w.syncStatus.Synced
 	w.syncStatus = ss
+	t := w.syncTracker
+	if !ss.Synced && (t == nil || t.done) {
+		t = newSyncTracker(false, now)
+		w.syncTracker = t
+	}
+	var progress *WalletSyncProgress
+	if t != nil && !t.done {
+		t.update(start, height, target, ss.Synced, now)
+		progress = t.progress(w.AssetID, now)
+	}
 	w.mtx.Unlock()
 
 	if atomic.LoadUint32(w.broadcasting) == 1 {
-		c.notify(newWalletSyncNote(w.AssetID, ss))
+		c.notify(newWalletSyncNote(w.AssetID, ss, progress))
 	}
 	if ss.Synced && !wasSynced {
 		c.updateWalletBalance(w)
@@ -3260,6 +3438,10 @@ func (c *Core) RescanWallet(assetID uint32, force bool) error {
 		return err
 	}
 
+	wallet.mtx.Lock()
+	wallet.syncTracker = newSyncTracker(true, time.Now())
+	wallet.mtx.Unlock()
+
 	if c.walletCheckAndNotify(wallet) {
 		return nil // sync done, Rescan may have by synchronous or a no-op
 	}
@@ -3270,6 +3452,28 @@ func (c *Core) RescanWallet(assetID uint32, force bool) error {
 	return nil
 }
 
+// WalletSyncProgress returns the progress of the wallet's current or most
+// recent sync or rescan, in the same terms for every asset.
+func (c *Core) WalletSyncProgress(assetID uint32) (*WalletSyncProgress, error) {
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return nil, newError(missingWalletErr, "no configured wallet found for %s", unbip(assetID))
+	}
+	wallet.mtx.RLock()
+	defer wallet.mtx.RUnlock()
+	if t := wallet.syncTracker; t != nil {
+		return t.progress(assetID, time.Now()), nil
+	}
+	// No sync has been observed since the wallet was loaded.
+	p := &WalletSyncProgress{AssetID: assetID}
+	if ss := wallet.syncStatus; ss != nil {
+		p.Done = ss.Synced
+		p.Progress = ss.BlockProgress()
+		p.StartHeight, p.Height, p.TargetHeight = ss.StartingBlocks, ss.Blocks, ss.TargetHeight
+	}
+	return p, nil
+}
+
 func (c *Core) removeWallet(assetID uint32) {
 	c.walletMtx.Lock()
 	defer c.walletMtx.Unlock()
@@ -4412,6 +4616,13 @@ func (c *Core) IsInitialized() bool {
 	return c.credentials != nil
 }
 
+// LoggedIn checks if the user is logged in.
+func (c *Core) LoggedIn() bool {
+	c.loginMtx.Lock()
+	defer c.loginMtx.Unlock()
+	return c.loggedIn
+}
+
 // InitializeClient sets the initial app-wide password and app seed for the
 // client. The seed argument should be left nil unless restoring from seed.
 func (c *Core) InitializeClient(pw []byte, restorationSeed *string) (string, error) {
@@ -4624,6 +4835,7 @@ func (c *Core) Login(pw []byte) error {
 			if err != nil {
 				return false, fmt.Errorf("GenDeepChild error: %w", err)
 			}
+			c.backupKey = backupKey(seed)
 			c.loggedIn = true
 			return true, nil
 		}
@@ -4777,6 +4989,9 @@ func (c *Core) Notifications(n int) (notes, pokes []*db.Notification, _ error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("error getting notifications: %w", err)
 	}
+	for _, n := range notes {
+		setNoteMetadata(n)
+	}
 	return notes, c.pokes(), nil
 }
 
@@ -4923,6 +5138,8 @@ func (c *Core) Logout() error {
 
 	c.bondXPriv.Zero()
 	c.bondXPriv = nil
+	encode.ClearBytes(c.backupKey)
+	c.backupKey = nil
 
 	c.loggedIn = false
 
@@ -4957,6 +5174,7 @@ func (c *Core) Orders(filter *OrderFilter) ([]*Order, error) {
 		Statuses:          filter.Statuses,
 		CompletedOnly:     filter.CompletedOnly,
 		FresherThanUnixMs: filter.FresherThanUnixMs,
+		Text:              filter.Text,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("UserOrders error: %w", err)
@@ -5201,7 +5419,8 @@ func (c *Core) initializeDEXConnection(dc *dexConnection, crypter encrypt.Crypte
 	err := dc.acct.unlock(crypter)
 	if err != nil {
 		subject, details := c.formatDetails(TopicAccountUnlockError, dc.acct.host, err)
-		c.notify(newFeePaymentNote(TopicAccountUnlockError, subject, details, db.ErrorLevel, dc.acct.host)) // newDEXAuthNote?
+		c.notify(withData(newFeePaymentNote(TopicAccountUnlockError, subject, details, db.ErrorLevel, dc.acct.host), // newDEXAuthNote?
+			&db.NoteData{Host: dc.acct.host, Error: err.Error()}))
 		return
 	}
 
@@ -5225,7 +5444,8 @@ func (c *Core) initializeDEXConnection(dc *dexConnection, crypter encrypt.Crypte
 			err = wallet.Unlock(crypter)
 			if err != nil {
 				subject, details := c.formatDetails(TopicWalletUnlockError, dc.acct.host, err)
-				c.notify(newFeePaymentNote(TopicWalletUnlockError, subject, details, db.ErrorLevel, dc.acct.host))
+				c.notify(withData(newFeePaymentNote(TopicWalletUnlockError, subject, details, db.ErrorLevel, dc.acct.host),
+					&db.NoteData{Host: dc.acct.host, AssetID: &bondAssetID, Error: err.Error()}))
 			}
 		}
 	}
@@ -5404,7 +5624,7 @@ func (c *Core) Send(pw []byte, assetID uint32, value uint64, address string, sub
 	}
 
 	var coin asset.Coin
-	feeSuggestion := c.feeSuggestionAny(assetID)
+	feeSuggestion := c.sendFeeRate(wallet)
 	if !subtract {
 		coin, err = wallet.Wallet.Send(address, value, feeSuggestion)
 	} else {
@@ -5416,13 +5636,15 @@ func (c *Core) Send(pw []byte, assetID uint32, value uint64, address string, sub
 	}
 	if err != nil {
 		subject, details := c.formatDetails(TopicSendError, unbip(assetID), err)
-		c.notify(newSendNote(TopicSendError, subject, details, db.ErrorLevel))
+		c.notify(withData(newSendNote(TopicSendError, subject, details, db.ErrorLevel),
+			&db.NoteData{AssetID: &assetID, Amount: value, Error: err.Error()}))
 		return nil, err
 	}
 
 	sentValue := wallet.Info().UnitInfo.ConventionalString(coin.Value())
 	subject, details := c.formatDetails(TopicSendSuccess, sentValue, unbip(assetID), address, coin)
-	c.notify(newSendNote(TopicSendSuccess, subject, details, db.Success))
+	c.notify(withData(newSendNote(TopicSendSuccess, subject, details, db.Success),
+		&db.NoteData{AssetID: &assetID, CoinID: coin.String(), Amount: coin.Value()}))
 
 	c.updateAssetBalance(assetID)
 
@@ -5537,6 +5759,52 @@ func (c *Core) ApproveTokenFee(assetID uint32, version uint32, approval bool) (u
 	return wallet.ApprovalFee(version, approval)
 }
 
+// TokenAllowances returns the allowances granted to the swap contracts by a
+// token wallet.
+func (c *Core) TokenAllowances(assetID uint32) ([]*asset.TokenAllowance, error) {
+	wallet, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.TokenAllowances()
+}
+
+// SetTokenAllowance sets the allowance granted to a version of a token's swap
+// contract. An allowance of zero revokes the approval.
+func (c *Core) SetTokenAllowance(appPW []byte, assetID, version uint32, allowance uint64) (string, error) {
+	crypter, err := c.encryptionKey(appPW)
+	if err != nil {
+		return "", err
+	}
+
+	wallet, err := c.connectedWallet(assetID)
+	if err != nil {
+		return "", err
+	}
+
+	err = wallet.Unlock(crypter)
+	if err != nil {
+		return "", err
+	}
+
+	err = wallet.checkPeersAndSyncStatus()
+	if err != nil {
+		return "", err
+	}
+
+	onConfirm := func() {
+		go c.notify(newTokenApprovalNote(wallet.state()))
+	}
+
+	txID, err := wallet.SetTokenAllowance(version, allowance, onConfirm)
+	if err != nil {
+		return "", err
+	}
+
+	c.notify(newTokenApprovalNote(wallet.state()))
+	return txID, nil
+}
+
 // EstimateSendTxFee returns an estimate of the tx fee needed to send or
 // withdraw the specified amount.
 func (c *Core) EstimateSendTxFee(address string, assetID uint32, amount uint64, subtract, maxWithdraw bool) (fee uint64, isValidAddress bool, err error) {
@@ -5561,7 +5829,7 @@ func (c *Core) EstimateSendTxFee(address string, assetID uint32, amount uint64,
 		return 0, false, fmt.Errorf("wallet does not support fee estimation")
 	}
 
-	return estimator.EstimateSendTxFee(address, amount, c.feeSuggestionAny(assetID), subtract, maxWithdraw)
+	return estimator.EstimateSendTxFee(address, amount, c.sendFeeRate(wallet), subtract, maxWithdraw)
 }
 
 // SingleLotFees returns the estimated swap, refund, and redeem fees for a single lot
@@ -5709,26 +5977,30 @@ func (c *Core) PreOrder(form *TradeForm) (*OrderEstimate, error) {
 			return nil, fmt.Errorf("Market order quantity buys less than a single lot")
 		}
 
-		var fills []*orderbook.Fill
-		var filled bool
 		if form.Sell {
-			fills, filled = book.BestFill(form.Sell, form.Qty)
+			// A market sell fills against the buy side of the book.
+			fill, err := book.VWAPForQty(form.Qty, false)
+			if err != nil {
+				return nil, fmt.Errorf("Cannot estimate market order: %w", err)
+			}
+			if !fill.Filled {
+				return nil, fmt.Errorf("Market is too thin to estimate market order")
+			}
+			rate = fill.AvgRate
 		} else {
-			fills, filled = book.BestFillMarketBuy(form.Qty, lotSize)
-		}
-
-		if !filled {
-			return nil, fmt.Errorf("Market is too thin to estimate market order")
-		}
+			// The quantity of a market buy is in units of the quote asset.
+			fills, filled := book.BestFillMarketBuy(form.Qty, lotSize)
+			if !filled {
+				return nil, fmt.Errorf("Market is too thin to estimate market order")
+			}
 
-		// Get an average rate.
-		var qtySum, product uint64
-		for _, fill := range fills {
-			product += fill.Quantity * fill.Rate
-			qtySum += fill.Quantity
-		}
-		rate = product / qtySum
-		if !form.Sell {
+			// Get an average rate.
+			var qtySum, product uint64
+			for _, fill := range fills {
+				product += fill.Quantity * fill.Rate
+				qtySum += fill.Quantity
+			}
+			rate = product / qtySum
 			lots = qtySum / lotSize
 		}
 	}
@@ -6875,7 +7147,7 @@ func (c *Core) authDEX(dc *dexConnection) error {
 	acctID := dc.acct.ID()
 	payload := &msgjson.Connect{
 		AccountID:  acctID[:],
-		APIVersion: 0,
+		APIVersion: uint16(dc.apiVersion()),
 		Time:       uint64(time.Now().UnixMilli()),
 	}
 	sigMsg := payload.Serialize()
@@ -6926,6 +7198,7 @@ func (c *Core) authDEX(dc *dexConnection) error {
 	if err != nil {
 		return newError(signatureErr, "DEX signature validation error: %w", err)
 	}
+	dc.connectResult.Store(result)
 
 	// Check active and pending bonds, comparing against result.ActiveBonds. For
 	// pendingBonds, rebroadcast and start waiter to postBond. For
@@ -7074,7 +7347,9 @@ func (c *Core) authDEX(dc *dexConnection) error {
 	// tier. Warn the user of this.
 	if unknownBondStrength > 0 && dc.acct.targetTier == 0 {
 		subject, details := c.formatDetails(TopicUnknownBondTierZero, unbip(uint32(unknownBondAssetID)), dc.acct.host)
-		c.notify(newUnknownBondTierZeroNote(subject, details))
+		bondAssetID := uint32(unknownBondAssetID)
+		c.notify(withData(newUnknownBondTierZeroNote(subject, details),
+			&db.NoteData{Host: dc.acct.host, AssetID: &bondAssetID}))
 		c.log.Warnf("Unknown bonds for asset %s found for dex %s while target tier is zero.",
 			unbip(uint32(unknownBondAssetID)), dc.acct.host)
 	}
@@ -7591,7 +7866,8 @@ func (c *Core) loadDBTrades(dc *dexConnection) error {
 		if err != nil {
 			err = fmt.Errorf("failed to load wallets for trade ID %s: %w", tracker.ID(), err)
 			subject, details := c.formatDetails(TopicOrderLoadFailure, err)
-			c.notify(newOrderNote(TopicOrderLoadFailure, subject, details, db.ErrorLevel, nil))
+			c.notify(withData(newOrderNote(TopicOrderLoadFailure, subject, details, db.ErrorLevel, nil),
+				&db.NoteData{Host: dc.acct.host, OrderID: tracker.ID().Bytes(), Error: err.Error()}))
 			continue
 		}
 
@@ -7924,7 +8200,8 @@ func (c *Core) resumeTrades(crypter encrypt.Crypter) {
 				err := fmt.Errorf("failed to connect and unlock wallets for trade ID %s", tracker.ID())
 				tracker.mtx.RUnlock()
 				subject, details := c.formatDetails(TopicOrderResumeFailure, err)
-				c.notify(newOrderNote(TopicOrderResumeFailure, subject, details, db.ErrorLevel, nil))
+				c.notify(withData(newOrderNote(TopicOrderResumeFailure, subject, details, db.ErrorLevel, nil),
+					&db.NoteData{Host: tracker.dc.acct.host, OrderID: tracker.ID().Bytes(), Error: err.Error()}))
 			}
 		}
 	}
@@ -8193,7 +8470,8 @@ func (c *Core) runMatches(tradeMatches map[order.OrderID]*serverMatches) (assetM
 // indicates the client should be updated to be used with this DEX server.
 func sendOutdatedClientNotification(c *Core, dc *dexConnection) {
 	subject, details := c.formatDetails(TopicUpgradeNeeded, dc.acct.host)
-	c.notify(newUpgradeNote(TopicUpgradeNeeded, subject, details, db.WarningLevel))
+	c.notify(withData(newUpgradeNote(TopicUpgradeNeeded, subject, details, db.WarningLevel),
+		&db.NoteData{Host: dc.acct.host}))
 }
 
 func isOnionHost(addr string) bool {
@@ -8250,9 +8528,9 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 	if err != nil {
 		return nil, newError(addressParseErr, "error parsing address: %v", err)
 	}
-	wsURL, err := url.Parse("wss://" + host + "/ws")
+	ep, err := c.wsEndpoint(host)
 	if err != nil {
-		return nil, newError(addressParseErr, "error parsing ws address from host %s: %w", host, err)
+		return nil, err
 	}
 
 	listen := flag&connectDEXFlagTemporary == 0
@@ -8279,13 +8557,46 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 		// On connect, must set: cfg, epoch, and assets.
 	}
 
+	dc.altHostsV.Store(acctInfo.AltHosts)
+
 	wsCfg := comms.WsCfg{
-		URL:      wsURL.String(),
-		PingWait: 50 * time.Second, // larger than server's pingPeriod (server/comms/server.go)
-		Cert:     acctInfo.Cert,
-		Logger:   c.log.SubLogger(wsURL.String()),
+		URL:            ep.URL,
+		NetDialContext: ep.NetDialContext,
+		PingWait:       50 * time.Second, // larger than server's pingPeriod (server/comms/server.go)
+		Cert:           acctInfo.Cert,
+		Logger:         c.log.SubLogger(ep.URL),
+		PreferCBOR:     c.cfg.PreferCBOR,
+		QUIC:           c.cfg.QUIC,
+		AltEndpoints:   c.altEndpoints(acctInfo.AltHosts),
+	}
+
+	wsCfg.ConnectEventFunc = func(status comms.ConnectionStatus) {
+		c.handleConnectEvent(dc, status)
+	}
+	wsCfg.ReconnectSync = func() {
+		go c.handleReconnect(host)
 	}
 
+	// Create a websocket "connection" to the server. (Don't actually connect.)
+	conn, err := c.wsConstructor(&wsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dc.WsConn = conn
+	dc.connMaster = dex.NewConnectionMaster(conn)
+
+	return dc, nil
+}
+
+// wsEndpoint creates the websocket endpoint for a DEX host. Onion hosts, or
+// all hosts if TorProxy is configured, are dialed through a Tor proxy.
+func (c *Core) wsEndpoint(host string) (*comms.WsEndpoint, error) {
+	wsURL, err := url.Parse("wss://" + host + "/ws")
+	if err != nil {
+		return nil, newError(addressParseErr, "error parsing ws address from host %s: %w", host, err)
+	}
+	ep := &comms.WsEndpoint{URL: wsURL.String()}
 	isOnionHost := isOnionHost(wsURL.Host)
 	if isOnionHost || c.cfg.TorProxy != "" {
 		proxyAddr := c.cfg.TorProxy
@@ -8296,32 +8607,31 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 			proxyAddr = c.cfg.Onion
 
 			wsURL.Scheme = "ws"
-			wsCfg.URL = wsURL.String()
+			ep.URL = wsURL.String()
 		}
 		proxy := &socks.Proxy{
 			Addr:         proxyAddr,
 			TorIsolation: c.cfg.TorIsolation, // need socks.NewPool with isolation???
 		}
-		wsCfg.NetDialContext = proxy.DialContext
-	}
-
-	wsCfg.ConnectEventFunc = func(status comms.ConnectionStatus) {
-		c.handleConnectEvent(dc, status)
-	}
-	wsCfg.ReconnectSync = func() {
-		go c.handleReconnect(host)
+		ep.NetDialContext = proxy.DialContext
 	}
+	return ep, nil
+}
 
-	// Create a websocket "connection" to the server. (Don't actually connect.)
-	conn, err := c.wsConstructor(&wsCfg)
-	if err != nil {
-		return nil, err
+// altEndpoints creates the websocket endpoints for a server's alternate hosts.
+// Hosts that cannot be used, e.g. onion hosts when Tor is not configured, are
+// skipped.
+func (c *Core) altEndpoints(altHosts []string) []*comms.WsEndpoint {
+	eps := make([]*comms.WsEndpoint, 0, len(altHosts))
+	for _, altHost := range altHosts {
+		ep, err := c.wsEndpoint(altHost)
+		if err != nil {
+			c.log.Warnf("Skipping alternate DEX address %s: %v", altHost, err)
+			continue
+		}
+		eps = append(eps, ep)
 	}
-
-	dc.WsConn = conn
-	dc.connMaster = dex.NewConnectionMaster(conn)
-
-	return dc, nil
+	return eps
 }
 
 // startDexConnection attempts to connect the provided dexConnection. dc must be
@@ -8626,6 +8936,28 @@ func (c *Core) handleConnectEvent(dc *dexConnection, status comms.ConnectionStat
 	}
 }
 
+// checkConnectionQuality notifies the user when a connected server's average
+// response time crosses slowServerRTT. A slow server with a stable connection
+// is distinguished from an unstable network, which is reported by
+// handleConnectEvent.
+func (c *Core) checkConnectionQuality(dc *dexConnection) {
+	if dc.IsDown() {
+		return
+	}
+	stats := dc.Stats()
+	if stats.AvgRTT < slowServerRTT/2 {
+		dc.slowServer.Store(false)
+		return
+	}
+	if stats.AvgRTT < slowServerRTT || dc.slowServer.Swap(true) {
+		return
+	}
+	c.log.Warnf("Slow responses from %s: average round trip %v, %d requests timed out, %d messages queued",
+		dc.acct.host, stats.AvgRTT, stats.TimedOut, stats.Backlog)
+	subject, details := c.formatDetails(TopicDEXSlowServer, dc.acct.host, stats.AvgRTT.Round(time.Millisecond))
+	c.notify(newConnEventNote(TopicDEXSlowServer, subject, dc.acct.host, dc.status(), details, db.WarningLevel))
+}
+
 // handleMatchProofMsg is called when a match_proof notification is received.
 func handleMatchProofMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	var note msgjson.MatchProofNote
@@ -8649,10 +8981,19 @@ func handleMatchProofMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error
 	}
 
 	// Validate match_proof commitment checksum for client orders in this epoch.
+	var epochTrades, bookedTrades []*trackedTrade
 	for _, trade := range dc.trackedTrades() {
 		if note.MarketID != trade.mktID {
 			continue
 		}
+		if trade.inEpoch(note.Epoch) {
+			epochTrades = append(epochTrades, trade)
+		} else if lo, ok := trade.Order.(*order.LimitOrder); ok && lo.Force == order.StandingTiF &&
+			trade.status() >= order.OrderStatusBooked {
+			// The order may be a maker in this epoch. If the epoch's match
+			// request arrived first, it may no longer be booked.
+			bookedTrades = append(bookedTrades, trade)
+		}
 
 		// Validation can fail either due to server trying to cheat (by
 		// requesting a preimage before closing the epoch to more orders), or
@@ -8667,9 +9008,207 @@ func handleMatchProofMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error
 		}
 	}
 
+	// Verify the full epoch matching inputs for epochs with our orders, and the
+	// matches of our orders in the epoch or on the book. The book has not been
+	// updated with the epoch's matching results yet, so this is the book that
+	// the epoch's orders were matched against.
+	if (len(epochTrades) > 0 && len(note.Preimages)+len(note.Misses) > 0) ||
+		(len(bookedTrades) > 0 && len(note.Preimages) > 0) {
+		buys, sells, _ := book.Orders()
+		go c.verifyEpochProof(dc, &note, epochTrades, bookedTrades, buys, sells)
+	}
+
 	return nil
 }
 
+// verifyEpochProof retrieves the epoch_proof for a match_proof's epoch, which
+// has all of the epoch's queued orders and revealed preimages. The proof's
+// commitment checksum and seed are recomputed and compared with the
+// match_proof, the deterministic shuffle is rerun, and the commitments and
+// preimages of the epoch trades' orders are checked. The epoch's matching cycle
+// is then rerun against the provided pre-epoch book, and the matches received
+// for the epoch trades and booked trades are compared with the result. The user
+// is notified of any discrepancy.
+func (c *Core) verifyEpochProof(dc *dexConnection, note *msgjson.MatchProofNote, epochTrades, bookedTrades []*trackedTrade,
+	buys, sells []*orderbook.Order) {
+
+	proof := new(msgjson.EpochProof)
+	err := sendRequest(dc.WsConn, msgjson.EpochProofRoute, &msgjson.EpochProofRequest{
+		MarketID: note.MarketID,
+		Epoch:    note.Epoch,
+	}, proof, DefaultResponseTimeout)
+	if err != nil {
+		// Servers that predate the epoch_proof route will not respond.
+		c.log.Debugf("Unable to retrieve epoch %d proof for %s at %s: %v",
+			note.Epoch, note.MarketID, dc.acct.host, err)
+		return
+	}
+
+	trades := append(epochTrades[:len(epochTrades):len(epochTrades)], bookedTrades...)
+	shuffled, err := orderbook.VerifyEpochProof(proof)
+	if err == nil {
+		switch {
+		case proof.MarketID != note.MarketID || proof.Epoch != note.Epoch:
+			err = fmt.Errorf("requested proof for %s epoch %d, got %s epoch %d",
+				note.MarketID, note.Epoch, proof.MarketID, proof.Epoch)
+		case !bytes.Equal(proof.CSum, note.CSum):
+			err = fmt.Errorf("proof csum %s != match_proof csum %s", proof.CSum, note.CSum)
+		case !bytes.Equal(proof.Seed, note.Seed):
+			err = fmt.Errorf("proof seed %s != match_proof seed %s", proof.Seed, note.Seed)
+		}
+	}
+	if err != nil {
+		for _, trade := range trades {
+			c.notifyEpochProofMismatch(dc, trade, note.Epoch, err)
+		}
+		return
+	}
+
+	proofOrders := make(map[order.OrderID]*msgjson.EpochProofOrder, len(proof.Orders))
+	for _, ord := range proof.Orders {
+		var oid order.OrderID
+		copy(oid[:], ord.OrderID)
+		proofOrders[oid] = ord
+	}
+	position := make(map[order.OrderID]int, len(shuffled))
+	for i, oid := range shuffled {
+		position[oid] = i
+	}
+
+	var failed bool
+	for _, trade := range epochTrades {
+		for _, ord := range trade.epochOrders(note.Epoch) {
+			oid, commit := ord.ID(), ord.Commitment()
+			proofOrd := proofOrders[oid]
+			var err error
+			switch {
+			case proofOrd == nil:
+				err = fmt.Errorf("order %s not in the epoch's queue", oid)
+			case !bytes.Equal(proofOrd.Commit, commit[:]):
+				err = fmt.Errorf("order %s commitment %s != our commitment %s", oid, proofOrd.Commit, commit)
+			case len(proofOrd.Preimage) > 0 && !bytes.Equal(proofOrd.Preimage, ord.pimg[:]):
+				err = fmt.Errorf("order %s preimage %s != our preimage", oid, proofOrd.Preimage)
+			}
+			if err != nil {
+				c.notifyEpochProofMismatch(dc, trade, note.Epoch, err)
+				failed = true
+				break
+			}
+			if pos, found := position[oid]; found {
+				c.log.Debugf("Verified epoch %d proof for %s at %s. Order %s matched %d of %d.",
+					note.Epoch, note.MarketID, dc.acct.host, oid, pos+1, len(shuffled))
+			}
+		}
+	}
+
+	if failed {
+		return
+	}
+	mkt := dc.marketConfig(note.MarketID)
+	if mkt == nil {
+		return
+	}
+	matches, err := orderbook.MatchEpoch(proof, shuffled, buys, sells, mkt.LotSize)
+	if err != nil {
+		for _, trade := range trades {
+			c.notifyEpochProofMismatch(dc, trade, note.Epoch, err)
+		}
+		return
+	}
+	c.verifyEpochMatches(dc, note.Epoch, matches, epochTrades, trades)
+}
+
+// verifyEpochMatches waits for the matches of an epoch to be received, and
+// checks that the trades received exactly the matches made by rerunning the
+// epoch's matching cycle. Since taker matches are only made in the epoch that
+// the taker was queued, any other taker match received for an order of the
+// epoch trades is also a discrepancy.
+func (c *Core) verifyEpochMatches(dc *dexConnection, epoch uint64, matches []*orderbook.EpochMatch, epochTrades, trades []*trackedTrade) {
+	expected := make(map[order.MatchID]*orderbook.EpochMatch, len(matches))
+	for _, match := range matches {
+		expected[match.ID()] = match
+	}
+	tradeMatches := make(map[*trackedTrade][]order.MatchID, len(trades))
+	for _, trade := range trades {
+		oid := trade.ID()
+		for mid, match := range expected {
+			// Cancel matches have the trade order as the maker.
+			if match.Taker == oid || match.Maker == oid {
+				tradeMatches[trade] = append(tradeMatches[trade], mid)
+			}
+		}
+	}
+
+	// The match requests for the epoch may arrive before or after the
+	// match_proof.
+	received := make(map[*trackedTrade]map[order.MatchID]*receivedMatch, len(trades))
+	allReceived := func() bool {
+		all := true
+		for _, trade := range trades {
+			received[trade] = trade.receivedMatches()
+			for _, mid := range tradeMatches[trade] {
+				if received[trade][mid] == nil {
+					all = false
+				}
+			}
+		}
+		return all
+	}
+	timeout := time.NewTimer(epochMatchesTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(epochMatchesPollInterval)
+	defer ticker.Stop()
+out:
+	for !allReceived() {
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			break out
+		case <-c.ctx.Done():
+			return
+		}
+	}
+
+	takers := make(map[order.OrderID]bool)
+	for _, trade := range epochTrades {
+		for _, ord := range trade.epochOrders(epoch) {
+			takers[ord.ID()] = true
+		}
+	}
+	for _, trade := range trades {
+		var err error
+		for _, mid := range tradeMatches[trade] {
+			if received[trade][mid] == nil {
+				match := expected[mid]
+				err = fmt.Errorf("expected match %s of taker %s and maker %s for %d at rate %d was not received",
+					mid, match.Taker, match.Maker, match.Quantity, match.Rate)
+				break
+			}
+		}
+		if err == nil {
+			for mid, match := range received[trade] {
+				if match.side == order.Taker && takers[match.oid] && expected[mid] == nil {
+					err = fmt.Errorf("received match %s for order %s was not made by the epoch's matching cycle",
+						mid, match.oid)
+					break
+				}
+			}
+		}
+		if err != nil {
+			c.notifyEpochProofMismatch(dc, trade, epoch, err)
+		}
+	}
+}
+
+// notifyEpochProofMismatch logs and notifies the user of an epoch_proof
+// discrepancy for the trade.
+func (c *Core) notifyEpochProofMismatch(dc *dexConnection, trade *trackedTrade, epoch uint64, err error) {
+	c.log.Errorf("Epoch %d proof verification failed for order %s at %s: %v",
+		epoch, trade.ID(), dc.acct.host, err)
+	subject, details := c.formatDetails(TopicEpochProofMismatch, makeOrderToken(trade.token()), epoch, dc.acct.host, err)
+	c.notify(newOrderNote(TopicEpochProofMismatch, subject, details, db.WarningLevel, trade.coreOrder()))
+}
+
 // handleRevokeOrderMsg is called when a revoke_order message is received.
 func handleRevokeOrderMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	var revocation msgjson.RevokeOrder
@@ -8755,7 +9294,8 @@ func handleNotifyMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 		return fmt.Errorf("notify unmarshal error: %w", err)
 	}
 	subject, details := c.formatDetails(TopicDEXNotification, dc.acct.host, txt)
-	c.notify(newServerNotifyNote(TopicDEXNotification, subject, details, db.WarningLevel))
+	c.notify(withData(newServerNotifyNote(TopicDEXNotification, subject, details, db.WarningLevel),
+		&db.NoteData{Host: dc.acct.host}))
 	return nil
 }
 
@@ -8776,7 +9316,8 @@ func handlePenaltyMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	t := time.UnixMilli(int64(note.Penalty.Time))
 
 	subject, details := c.formatDetails(TopicPenalized, dc.acct.host, note.Penalty.Rule, t, note.Penalty.Details)
-	c.notify(newServerNotifyNote(TopicPenalized, subject, details, db.WarningLevel))
+	c.notify(withData(newServerNotifyNote(TopicPenalized, subject, details, db.WarningLevel),
+		&db.NoteData{Host: dc.acct.host}))
 	return nil
 }
 
@@ -8877,6 +9418,7 @@ var noteHandlers = map[string]routeHandler{
 	msgjson.UnbookOrderRoute:     handleUnbookOrderMsg,
 	msgjson.PriceUpdateRoute:     handlePriceUpdateNote,
 	msgjson.UpdateRemainingRoute: handleUpdateRemainingMsg,
+	msgjson.BookUpdateBatchRoute: handleBookUpdateBatchMsg,
 	msgjson.EpochReportRoute:     handleEpochReportMsg,
 	msgjson.SuspensionRoute:      handleTradeSuspensionMsg,
 	msgjson.ResumptionRoute:      handleTradeResumptionMsg,
@@ -9020,6 +9562,7 @@ func (c *Core) listen(dc *dexConnection) {
 				}
 
 				checkTrades()
+				c.checkConnectionQuality(dc)
 			case <-stopTicks:
 				return
 			case <-c.ctx.Done():
@@ -9352,7 +9895,8 @@ func handleAuditRoute(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 
 	tracker, _ := dc.findOrder(oid)
 	if tracker == nil {
-		return fmt.Errorf("audit request received for unknown order: %s", string(msg.Payload))
+		return fmt.Errorf("audit request %d received for unknown order %v, match %v from %s",
+			msg.ID, oid, audit.MatchID, dc.acct.host)
 	}
 	return tracker.processAuditMsg(msg.ID, audit)
 }
@@ -9500,7 +10044,8 @@ func handleRedemptionRoute(c *Core, dc *dexConnection, msg *msgjson.Message) err
 		return nil
 	}
 
-	return fmt.Errorf("redemption request received for unknown order: %s", string(msg.Payload))
+	return fmt.Errorf("redemption request %d received for unknown order %v, match %v from %s",
+		msg.ID, oid, redemption.MatchID, dc.acct.host)
 }
 
 // peerChange is called by a wallet backend when the peer count changes or
@@ -10253,7 +10798,8 @@ func (c *Core) DeleteArchivedRecords(olderThan *time.Time, matchesFile, ordersFi
 }
 
 // AccelerateOrder will use the Child-Pays-For-Parent technique to accelerate
-// the swap transactions in an order.
+// the swap transactions in an order. For account-based assets, the unmined
+// swap and redemption transactions are instead replaced at the new fee rate.
 func (c *Core) AccelerateOrder(pw []byte, oidB dex.Bytes, newFeeRate uint64) (string, error) {
 	_, err := c.encryptionKey(pw)
 	if err != nil {
@@ -10270,7 +10816,10 @@ func (c *Core) AccelerateOrder(pw []byte, oidB dex.Bytes, newFeeRate uint64) (st
 	}
 
 	if !tracker.wallets.fromWallet.traits.IsAccelerator() {
-		return "", fmt.Errorf("the %s wallet is not an accelerator", tracker.wallets.fromWallet.Symbol)
+		if !tracker.isFeeBumpable() {
+			return "", fmt.Errorf("the %s wallet is not an accelerator", tracker.wallets.fromWallet.Symbol)
+		}
+		return c.bumpOrderFees(tracker, newFeeRate)
 	}
 
 	tracker.mtx.Lock()
@@ -10310,7 +10859,10 @@ func (c *Core) AccelerationEstimate(oidB dex.Bytes, newFeeRate uint64) (uint64,
 	}
 
 	if !tracker.wallets.fromWallet.traits.IsAccelerator() {
-		return 0, fmt.Errorf("the %s wallet is not an accelerator", tracker.wallets.fromWallet.Symbol)
+		if !tracker.isFeeBumpable() {
+			return 0, fmt.Errorf("the %s wallet is not an accelerator", tracker.wallets.fromWallet.Symbol)
+		}
+		return c.orderFeeBumpEstimate(tracker, newFeeRate)
 	}
 
 	tracker.mtx.RLock()
@@ -10343,7 +10895,10 @@ func (c *Core) PreAccelerateOrder(oidB dex.Bytes) (*PreAccelerate, error) {
 	}
 
 	if !tracker.wallets.fromWallet.traits.IsAccelerator() {
-		return nil, fmt.Errorf("the %s wallet is not an accelerator", tracker.wallets.fromWallet.Symbol)
+		if !tracker.isFeeBumpable() {
+			return nil, fmt.Errorf("the %s wallet is not an accelerator", tracker.wallets.fromWallet.Symbol)
+		}
+		return c.preBumpOrderFees(tracker)
 	}
 
 	feeSuggestion := c.feeSuggestionAny(tracker.fromAssetID)
@@ -10374,6 +10929,91 @@ func (c *Core) PreAccelerateOrder(oidB dex.Bytes) (*PreAccelerate, error) {
 	}, nil
 }
 
+// bumpOrderFees replaces the unmined swap and redemption transactions of an
+// order with the same transactions at the new fee rate. The IDs of the
+// replacement transactions are returned.
+func (c *Core) bumpOrderFees(tracker *trackedTrade, newFeeRate uint64) (string, error) {
+	tracker.mtx.RLock()
+	defer tracker.mtx.RUnlock()
+	targets, err := tracker.feeBumpTargets()
+	if err != nil {
+		return "", err
+	}
+	var txIDs []string
+	for _, target := range targets {
+		ids, err := target.wallet.bumpFees(target.coinIDs, newFeeRate)
+		txIDs = append(txIDs, ids...)
+		if err != nil {
+			return strings.Join(txIDs, ", "), fmt.Errorf("error replacing %s transactions: %w", target.wallet.Symbol, err)
+		}
+	}
+	if len(txIDs) == 0 {
+		return "", errors.New("no transactions needed to be replaced at the new fee rate")
+	}
+	c.log.Infof("Replaced transactions for order %s at fee rate %d: %s", tracker.ID(), newFeeRate, strings.Join(txIDs, ", "))
+	return strings.Join(txIDs, ", "), nil
+}
+
+// orderFeeBumpEstimate returns the additional fees that would be paid to
+// replace the unmined swap and redemption transactions of an order at the new
+// fee rate.
+func (c *Core) orderFeeBumpEstimate(tracker *trackedTrade, newFeeRate uint64) (uint64, error) {
+	tracker.mtx.RLock()
+	defer tracker.mtx.RUnlock()
+	targets, err := tracker.feeBumpTargets()
+	if err != nil {
+		return 0, err
+	}
+	var fees uint64
+	for _, target := range targets {
+		f, err := target.wallet.bumpFeesEstimate(target.coinIDs, newFeeRate)
+		if err != nil {
+			return 0, err
+		}
+		fees += f
+	}
+	return fees, nil
+}
+
+// preBumpOrderFees returns information the user can use to decide how much to
+// raise the fee rate of the unmined swap and redemption transactions of an
+// order.
+func (c *Core) preBumpOrderFees(tracker *trackedTrade) (*PreAccelerate, error) {
+	tracker.mtx.RLock()
+	defer tracker.mtx.RUnlock()
+	targets, err := tracker.feeBumpTargets()
+	if err != nil {
+		return nil, err
+	}
+	feeSuggestion := c.feeSuggestionAny(targets[0].wallet.AssetID)
+	var pre *PreAccelerate
+	var lastErr error
+	for _, target := range targets {
+		currentRate, suggestedRange, early, err := target.wallet.preBumpFees(target.coinIDs, feeSuggestion)
+		if err != nil {
+			// Probably nothing unmined for this wallet.
+			lastErr = err
+			continue
+		}
+		if pre == nil {
+			pre = &PreAccelerate{
+				SuggestedRate:  feeSuggestion,
+				SuggestedRange: *suggestedRange,
+			}
+		}
+		if currentRate > pre.SwapRate {
+			pre.SwapRate = currentRate
+		}
+		if early != nil && (pre.EarlyAcceleration == nil || early.TimePast < pre.EarlyAcceleration.TimePast) {
+			pre.EarlyAcceleration = early
+		}
+	}
+	if pre == nil {
+		return nil, lastErr
+	}
+	return pre, nil
+}
+
 // WalletPeers returns a list of peers that a wallet is connected to. It also
 // returns the user added peers that the wallet is not connected to.
 func (c *Core) WalletPeers(assetID uint32) ([]*asset.WalletPeer, error) {
@@ -7,12 +7,15 @@ import (
 	"context"
 	crand "crypto/rand"
 	"crypto/sha256"
+	"encoding/csv"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
@@ -25,7 +28,10 @@ import (
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/db/bolt"
 	dbtest "decred.org/dcrdex/client/db/test"
+	"decred.org/dcrdex/client/intl"
+	"decred.org/dcrdex/client/orderbook"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/calc"
 	"decred.org/dcrdex/dex/encode"
@@ -178,6 +184,7 @@ type TWebsocket struct {
 	handlers       map[string][]func(*msgjson.Message, msgFunc) error
 	submittedBond  *msgjson.PostBond
 	liveBondExpiry uint64
+	stats          comms.ConnectionStats
 }
 
 func newTWebsocket() *TWebsocket {
@@ -334,6 +341,12 @@ func (conn *TWebsocket) RequestWithTimeout(msg *msgjson.Message, f func(*msgjson
 		conn.handlers[msg.Route] = handlers[1:]
 		return handler(msg, f)
 	}
+	if msg.Route == msgjson.VersionRoute {
+		// Respond like a server that predates version negotiation.
+		resp, _ := msgjson.NewResponse(msg.ID, nil, msgjson.NewError(msgjson.RPCUnknownRoute, "unknown route"))
+		f(resp)
+		return nil
+	}
 	return fmt.Errorf("no handler for route %q", msg.Route)
 }
 func (conn *TWebsocket) MessageSource() <-chan *msgjson.Message { return conn.msgs } // use when Core.listen is running
@@ -349,6 +362,15 @@ func (conn *TWebsocket) Connect(context.Context) (*sync.WaitGroup, error) {
 
 func (conn *TWebsocket) UpdateURL(string) {}
 
+func (conn *TWebsocket) SetAltEndpoints([]*comms.WsEndpoint) {}
+
+func (conn *TWebsocket) Stats() *comms.ConnectionStats {
+	conn.mtx.RLock()
+	defer conn.mtx.RUnlock()
+	stats := conn.stats
+	return &stats
+}
+
 type TDB struct {
 	updateWalletErr          error
 	acct                     *db.AccountInfo
@@ -371,6 +393,10 @@ type TDB struct {
 	orderErr                 error
 	linkedFromID             order.OrderID
 	linkedToID               order.OrderID
+	annotatedOrder           order.OrderID
+	annotatedMatch           order.MatchID
+	annotation               *db.Annotation
+	annotationErr            error
 	existValues              map[string]bool
 	accountProofErr          error
 	verifyCreateAccount      bool
@@ -379,6 +405,7 @@ type TDB struct {
 	disableAccountErr        error
 	creds                    *db.PrimaryCredentials
 	setCredsErr              error
+	backupToData             []byte
 	legacyKeyErr             error
 	recryptErr               error
 	deleteInactiveOrdersErr  error
@@ -386,6 +413,17 @@ type TDB struct {
 	deleteInactiveMatchesErr error
 	archivedMatches          int
 	updateAccountInfoErr     error
+	topicSettings            map[db.Topic]*db.TopicSettings
+	topicSettingsErr         error
+	noteTemplates            map[db.Topic]*db.NoteTemplate
+	noteTemplatesErr         error
+	confTargets              map[uint32]*db.ConfTarget
+	confTargetsErr           error
+	noteFilter               *db.NoteFilter
+	notesDeletedBefore       uint64
+	integrityReport          *db.IntegrityReport
+	integrityErr             error
+	compactOnShutdown        bool
 }
 
 func (tdb *TDB) Run(context.Context) {}
@@ -485,6 +523,19 @@ func (tdb *TDB) LinkOrder(oid, linkedID order.OrderID) error {
 	return nil
 }
 
+func (tdb *TDB) SetOrderAnnotation(oid order.OrderID, a *db.Annotation) error {
+	tdb.annotatedOrder = oid
+	tdb.annotation = a
+	return tdb.annotationErr
+}
+
+func (tdb *TDB) SetMatchAnnotation(oid order.OrderID, mid order.MatchID, a *db.Annotation) error {
+	tdb.annotatedOrder = oid
+	tdb.annotatedMatch = mid
+	tdb.annotation = a
+	return tdb.annotationErr
+}
+
 func (tdb *TDB) UpdateMatch(m *db.MetaMatch) error {
 	if tdb.updateMatchChan != nil {
 		tdb.updateMatchChan <- m.Status
@@ -529,11 +580,27 @@ func (tdb *TDB) Wallet([]byte) (*db.Wallet, error) {
 	return tdb.wallet, tdb.walletErr
 }
 
-func (tdb *TDB) SaveNotification(*db.Notification) error            { return nil }
-func (tdb *TDB) BackupTo(dst string, overwrite, compact bool) error { return nil }
-func (tdb *TDB) NotificationsN(int) ([]*db.Notification, error)     { return nil, nil }
-func (tdb *TDB) SavePokes([]*db.Notification) error                 { return nil }
-func (tdb *TDB) LoadPokes() ([]*db.Notification, error)             { return nil, nil }
+func (tdb *TDB) SaveNotification(*db.Notification) error        { return nil }
+func (tdb *TDB) NotificationsN(int) ([]*db.Notification, error) { return nil, nil }
+func (tdb *TDB) SavePokes([]*db.Notification) error             { return nil }
+func (tdb *TDB) LoadPokes() ([]*db.Notification, error)         { return nil, nil }
+
+func (tdb *TDB) BackupTo(dst string, overwrite, compact bool) error {
+	if tdb.backupToData != nil {
+		return os.WriteFile(dst, tdb.backupToData, 0600)
+	}
+	return nil
+}
+
+func (tdb *TDB) SearchNotifications(filter *db.NoteFilter) ([]*db.Notification, error) {
+	tdb.noteFilter = filter
+	return nil, nil
+}
+
+func (tdb *TDB) DeleteNotifications(olderThan uint64) (int, error) {
+	tdb.notesDeletedBefore = olderThan
+	return 0, nil
+}
 
 func (tdb *TDB) SetPrimaryCredentials(creds *db.PrimaryCredentials) error {
 	if tdb.setCredsErr != nil {
@@ -576,6 +643,14 @@ func (tdb *TDB) Recrypt(creds *db.PrimaryCredentials, oldCrypter, newCrypter enc
 	return nil, nil, nil
 }
 
+func (tdb *TDB) CheckIntegrity() (*db.IntegrityReport, error) {
+	return tdb.integrityReport, tdb.integrityErr
+}
+
+func (tdb *TDB) CompactOnShutdown() {
+	tdb.compactOnShutdown = true
+}
+
 func (tdb *TDB) Backup() error {
 	return nil
 }
@@ -589,6 +664,33 @@ func (tdb *TDB) Language() (string, error) {
 	return "en-US", nil
 }
 
+func (tdb *TDB) SetTopicSettings(settings map[db.Topic]*db.TopicSettings) error {
+	tdb.topicSettings = settings
+	return tdb.topicSettingsErr
+}
+
+func (tdb *TDB) TopicSettings() (map[db.Topic]*db.TopicSettings, error) {
+	return tdb.topicSettings, nil
+}
+
+func (tdb *TDB) SetNoteTemplates(templates map[db.Topic]*db.NoteTemplate) error {
+	tdb.noteTemplates = templates
+	return tdb.noteTemplatesErr
+}
+
+func (tdb *TDB) NoteTemplates() (map[db.Topic]*db.NoteTemplate, error) {
+	return tdb.noteTemplates, nil
+}
+
+func (tdb *TDB) SetConfTargets(targets map[uint32]*db.ConfTarget) error {
+	tdb.confTargets = targets
+	return tdb.confTargetsErr
+}
+
+func (tdb *TDB) ConfTargets() (map[uint32]*db.ConfTarget, error) {
+	return tdb.confTargets, nil
+}
+
 type tCoin struct {
 	id []byte
 
@@ -1180,6 +1282,45 @@ func (w *TAccountLocker) ReReserveRefund(v uint64) error {
 	return w.reReserveRefundErr
 }
 
+type TFeeBumper struct {
+	*TXCWallet
+	bumpedCoins   []dex.Bytes
+	bumpedRate    uint64
+	replacements  []string
+	bumpErr       error
+	currentRate   uint64
+	bumpEstimate  uint64
+	earlyBump     *asset.EarlyAcceleration
+	preBumpCoins  []dex.Bytes
+	preBumpErr    error
+	feeSuggestion uint64
+}
+
+func newTFeeBumper(assetID uint32) (*xcWallet, *TFeeBumper) {
+	xcWallet, tWallet := newTWallet(assetID)
+	bumper := &TFeeBumper{TXCWallet: tWallet}
+	xcWallet.Wallet = bumper
+	// TXCWallet is an Accelerator, but account-based wallets are not.
+	xcWallet.traits = asset.DetermineWalletTraits(bumper) &^ asset.WalletTraitAccelerator
+	return xcWallet, bumper
+}
+
+func (w *TFeeBumper) PreBumpFees(coinIDs []dex.Bytes, feeSuggestion uint64) (uint64, *asset.XYRange, *asset.EarlyAcceleration, error) {
+	w.preBumpCoins = coinIDs
+	w.feeSuggestion = feeSuggestion
+	return w.currentRate, &asset.XYRange{}, w.earlyBump, w.preBumpErr
+}
+
+func (w *TFeeBumper) BumpFeesEstimate(coinIDs []dex.Bytes, newFeeRate uint64) (uint64, error) {
+	return w.bumpEstimate, nil
+}
+
+func (w *TFeeBumper) BumpFees(coinIDs []dex.Bytes, newFeeRate uint64) ([]string, error) {
+	w.bumpedCoins = coinIDs
+	w.bumpedRate = newFeeRate
+	return w.replacements, w.bumpErr
+}
+
 type TFeeRater struct {
 	*TXCWallet
 	feeRate uint64
@@ -1193,6 +1334,29 @@ func (w *TFeeRater) FeeRateSwap() (rate uint64, tooLow bool) {
 	return w.FeeRate()
 }
 
+type TTargetFeeRater struct {
+	*TFeeRater
+	targetRates   map[uint64]uint64
+	targetRateErr error
+}
+
+func (w *TTargetFeeRater) TargetFeeRate(confTarget uint64) (uint64, error) {
+	return w.targetRates[confTarget], w.targetRateErr
+}
+
+type TTxHistorian struct {
+	*TXCWallet
+	txs []*asset.WalletTransaction
+}
+
+func (w *TTxHistorian) TxHistory(n int, refID *string, past bool) ([]*asset.WalletTransaction, error) {
+	return w.txs, nil
+}
+
+func (w *TTxHistorian) WalletTransaction(ctx context.Context, txID string) (*asset.WalletTransaction, error) {
+	return nil, asset.CoinNotFoundError
+}
+
 type TLiveReconfigurer struct {
 	*TXCWallet
 	restart     bool
@@ -1342,9 +1506,10 @@ func newTestRig() *testRig {
 				// which may have been previously "disconnected".
 				return conn, nil
 			},
-			newCrypter: func([]byte) encrypt.Crypter { return crypter },
-			reCrypter:  func([]byte, []byte) (encrypt.Crypter, error) { return crypter, crypter.recryptErr },
-			noteChans:  make(map[uint64]chan Notification),
+			newCrypter:   func([]byte) encrypt.Crypter { return crypter },
+			reCrypter:    func([]byte, []byte) (encrypt.Crypter, error) { return crypter, crypter.recryptErr },
+			noteChans:    make(map[uint64]chan Notification),
+			desktopChans: make(map[uint64]chan Notification),
 
 			fiatRateSources:  make(map[string]*commonRateSource),
 			notes:            make(chan asset.WalletNotification, 128),
@@ -1753,6 +1918,55 @@ func TestBookFeed(t *testing.T) {
 		t.Fatalf("expected 1 buy after unbook_order, got %d", len(book.Buys))
 	}
 
+	// A book_update_batch is applied in order, skipping updates that were
+	// already applied.
+	oid4 := ordertest.RandomOrderID()
+	batchNote, _ := msgjson.NewNotification(msgjson.BookUpdateBatchRoute, &msgjson.BookUpdateBatch{
+		MarketID: tDcrBtcMktName,
+		Updates: []*msgjson.BookUpdate{
+			{Unbook: &msgjson.UnbookOrderNote{ // stale
+				Seq:      5,
+				MarketID: tDcrBtcMktName,
+				OrderID:  oid3[:],
+			}},
+			{Book: &msgjson.BookOrderNote{
+				TradeNote: msgjson.TradeNote{
+					Side:     msgjson.BuyOrderNum,
+					Quantity: 10,
+					Rate:     1,
+				},
+				OrderNote: msgjson.OrderNote{
+					Seq:      6,
+					MarketID: tDcrBtcMktName,
+					OrderID:  oid4[:],
+				},
+			}},
+			{UpdateRemaining: &msgjson.UpdateRemainingNote{
+				OrderNote: msgjson.OrderNote{
+					Seq:      7,
+					MarketID: tDcrBtcMktName,
+					OrderID:  oid4[:],
+				},
+				Remaining: 4,
+			}},
+		},
+	})
+	if err = handleBookUpdateBatchMsg(tCore, dc, batchNote); err != nil {
+		t.Fatalf("[handleBookUpdateBatchMsg]: unexpected err: %v", err)
+	}
+	checkAction(feed2, BookOrderAction)
+	checkAction(feed2, UpdateRemainingAction)
+	book, _ = tCore.Book(tDexHost, tUTXOAssetA.ID, tUTXOAssetB.ID)
+	if len(book.Buys) != 2 {
+		t.Fatalf("expected 2 buys after book_update_batch, got %d", len(book.Buys))
+	}
+	if len(book.Sells) != 1 {
+		t.Fatalf("stale unbook applied from book_update_batch")
+	}
+	if book.Buys[1].QtyAtomic != 4 {
+		t.Fatalf("expected remaining quantity of 4 after book_update_batch, got %d", book.Buys[1].QtyAtomic)
+	}
+
 	// Test candles
 	queueCandles := func() {
 		rig.ws.queueResponse(msgjson.CandlesRoute, func(msg *msgjson.Message, f msgFunc) error {
@@ -6437,6 +6651,299 @@ func TestHandleMatchProofMsg(t *testing.T) {
 	}
 }
 
+// epochProofOrder creates the epoch_proof entry for a limit order.
+func epochProofOrder(lo *order.LimitOrder, epochIdx uint64, pimg []byte) *msgjson.EpochProofOrder {
+	oid, commit := lo.ID(), lo.Commitment()
+	side, tif := uint8(msgjson.BuyOrderNum), uint8(msgjson.StandingOrderNum)
+	if lo.Sell {
+		side = msgjson.SellOrderNum
+	}
+	if lo.Force == order.ImmediateTiF {
+		tif = msgjson.ImmediateOrderNum
+	}
+	return &msgjson.EpochProofOrder{
+		EpochOrderNote: msgjson.EpochOrderNote{
+			BookOrderNote: msgjson.BookOrderNote{
+				OrderNote: msgjson.OrderNote{
+					MarketID: tDcrBtcMktName,
+					OrderID:  oid[:],
+				},
+				TradeNote: msgjson.TradeNote{
+					Side:     side,
+					Quantity: lo.Quantity,
+					Rate:     lo.Rate,
+					TiF:      tif,
+					Time:     uint64(lo.Time()),
+				},
+			},
+			OrderType: msgjson.LimitOrderNum,
+			Epoch:     epochIdx,
+			Commit:    commit[:],
+		},
+		Preimage: pimg,
+	}
+}
+
+// epochProofSums computes the commitment checksum and seed of an epoch_proof.
+func epochProofSums(proof *msgjson.EpochProof) (csum, seed dex.Bytes) {
+	var commits [][]byte
+	var revealed []*msgjson.EpochProofOrder
+	for _, ord := range proof.Orders {
+		commits = append(commits, ord.Commit)
+		if len(ord.Preimage) > 0 {
+			revealed = append(revealed, ord)
+		}
+	}
+	sort.Slice(commits, func(i, j int) bool {
+		return bytes.Compare(commits[i], commits[j]) < 0
+	})
+	sort.Slice(revealed, func(i, j int) bool {
+		return bytes.Compare(revealed[i].OrderID, revealed[j].OrderID) < 0
+	})
+	var b []byte
+	for _, commit := range commits {
+		b = append(b, commit...)
+	}
+	csumB := blake256.Sum256(b)
+	b = nil
+	for _, ord := range revealed {
+		b = append(b, ord.Preimage...)
+	}
+	seedB := blake256.Sum256(b)
+	return csumB[:], seedB[:]
+}
+
+func TestVerifyEpochProof(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	dc := rig.dc
+	lo, dbOrder, preImg, _ := makeLimitOrder(dc, true, 3*dcrBtcLotSize, dcrBtcRateStep*10)
+	dcrWallet, _ := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+	walletSet, _, _, _ := tCore.walletSet(dc, tUTXOAssetA.ID, tUTXOAssetB.ID, true)
+	tracker := newTrackedTrade(dbOrder, preImg, dc, rig.core.lockTimeTaker, rig.core.lockTimeMaker,
+		rig.db, rig.queue, walletSet, nil, rig.core.notify, rig.core.formatDetails)
+	oid := lo.ID()
+	epochIdx := tracker.epochIdx()
+
+	defer func(timeout, interval time.Duration) {
+		epochMatchesTimeout, epochMatchesPollInterval = timeout, interval
+	}(epochMatchesTimeout, epochMatchesPollInterval)
+	epochMatchesTimeout, epochMatchesPollInterval = 20*time.Millisecond, time.Millisecond
+
+	// Our order and another order that missed its preimage. The book is empty,
+	// so our immediate order has no matches.
+	other, _, _, _ := makeLimitOrder(dc, false, dcrBtcLotSize, dcrBtcRateStep*5)
+	var csum, seed dex.Bytes
+	newProof := func() *msgjson.EpochProof {
+		return &msgjson.EpochProof{
+			MarketID: tDcrBtcMktName,
+			Epoch:    epochIdx,
+			Orders: []*msgjson.EpochProofOrder{
+				epochProofOrder(lo, epochIdx, preImg[:]),
+				epochProofOrder(other, epochIdx, nil),
+			},
+			CSum: csum,
+			Seed: seed,
+		}
+	}
+	csum, seed = epochProofSums(newProof())
+	otherID := other.ID()
+	note := &msgjson.MatchProofNote{
+		MarketID:  tDcrBtcMktName,
+		Epoch:     epochIdx,
+		Preimages: []dex.Bytes{preImg[:]},
+		Misses:    []dex.Bytes{otherID[:]},
+		CSum:      csum,
+		Seed:      seed,
+	}
+
+	orderNotes, done := orderNoteFeed(tCore)
+	defer done()
+
+	test := func(name string, proof *msgjson.EpochProof, expMismatch bool) {
+		t.Helper()
+		rig.ws.queueResponse(msgjson.EpochProofRoute, func(msg *msgjson.Message, f msgFunc) error {
+			resp, _ := msgjson.NewResponse(msg.ID, proof, nil)
+			f(resp)
+			return nil
+		})
+		tCore.verifyEpochProof(dc, note, []*trackedTrade{tracker}, nil, nil, nil)
+		if mismatch := waitEpochProofMismatch(orderNotes); mismatch != expMismatch {
+			t.Fatalf("%s: expected mismatch = %t, got %t", name, expMismatch, mismatch)
+		}
+	}
+
+	test("ok", newProof(), false)
+
+	proof := newProof()
+	proof.CSum = encode.RandomBytes(32)
+	test("bad csum", proof, true)
+
+	proof = newProof()
+	proof.Orders[0].OrderID = encode.RandomBytes(order.OrderIDSize)
+	test("order missing", proof, true)
+
+	// The server drops the other order from the proof.
+	proof = newProof()
+	proof.Orders = proof.Orders[:1]
+	test("dropped order", proof, true)
+
+	proof = newProof()
+	proof.Seed = encode.RandomBytes(32)
+	test("bad seed", proof, true)
+
+	// Preimage not revealed. The proof must have no seed.
+	proof = newProof()
+	proof.Orders[0].Preimage = nil
+	proof.Seed = nil
+	test("unrevealed", proof, true) // differs from the match_proof seed
+
+	// A match was received for our order, but the empty book has no matches.
+	mid := ordertest.RandomMatchID()
+	tracker.matches[mid] = &matchTracker{
+		MetaMatch: db.MetaMatch{
+			UserMatch: &order.UserMatch{
+				OrderID:  oid,
+				MatchID:  mid,
+				Quantity: lo.Quantity,
+				Rate:     lo.Rate,
+				Side:     order.Taker,
+			},
+			MetaData: &db.MatchMetaData{},
+		},
+	}
+	test("unexpected match", newProof(), true)
+}
+
+// waitEpochProofMismatch checks for an epoch_proof mismatch notification.
+func waitEpochProofMismatch(orderNotes chan *OrderNote) bool {
+	timeout := time.After(time.Millisecond * 100)
+	for {
+		select {
+		case n := <-orderNotes:
+			if n.TopicID == TopicEpochProofMismatch {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+func TestVerifyEpochMatches(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	dc := rig.dc
+	rate := dcrBtcRateStep * 10
+	lo, dbOrder, preImg, _ := makeLimitOrder(dc, true, 3*dcrBtcLotSize, rate)
+	dcrWallet, _ := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+	walletSet, _, _, _ := tCore.walletSet(dc, tUTXOAssetA.ID, tUTXOAssetB.ID, true)
+	newTracker := func() *trackedTrade {
+		return newTrackedTrade(dbOrder, preImg, dc, rig.core.lockTimeTaker, rig.core.lockTimeMaker,
+			rig.db, rig.queue, walletSet, nil, rig.core.notify, rig.core.formatDetails)
+	}
+	oid := lo.ID()
+	epochIdx := newTracker().epochIdx()
+
+	defer func(timeout, interval time.Duration) {
+		epochMatchesTimeout, epochMatchesPollInterval = timeout, interval
+	}(epochMatchesTimeout, epochMatchesPollInterval)
+	epochMatchesTimeout, epochMatchesPollInterval = 20*time.Millisecond, time.Millisecond
+
+	// Our sell and another sell in the epoch. Whichever is matched first gets
+	// the better buy.
+	other, _, otherPimg, _ := makeLimitOrder(dc, true, 3*dcrBtcLotSize, rate)
+	proof := &msgjson.EpochProof{
+		MarketID: tDcrBtcMktName,
+		Epoch:    epochIdx,
+		Orders: []*msgjson.EpochProofOrder{
+			epochProofOrder(lo, epochIdx, preImg[:]),
+			epochProofOrder(other, epochIdx, otherPimg[:]),
+		},
+	}
+	proof.CSum, proof.Seed = epochProofSums(proof)
+	note := &msgjson.MatchProofNote{
+		MarketID:  tDcrBtcMktName,
+		Epoch:     epochIdx,
+		Preimages: []dex.Bytes{preImg[:], otherPimg[:]},
+		CSum:      proof.CSum,
+		Seed:      proof.Seed,
+	}
+	bestBuy := &orderbook.Order{
+		OrderID:  ordertest.RandomOrderID(),
+		Side:     msgjson.BuyOrderNum,
+		Quantity: 3 * dcrBtcLotSize,
+		Rate:     rate + 5*dcrBtcRateStep,
+	}
+	nextBuy := &orderbook.Order{
+		OrderID:  ordertest.RandomOrderID(),
+		Side:     msgjson.BuyOrderNum,
+		Quantity: 3 * dcrBtcLotSize,
+		Rate:     rate,
+	}
+	buys := []*orderbook.Order{bestBuy, nextBuy}
+
+	shuffled, err := orderbook.VerifyEpochProof(proof)
+	if err != nil {
+		t.Fatalf("VerifyEpochProof error: %v", err)
+	}
+	ourMaker, otherMaker := bestBuy, nextBuy
+	if shuffled[0] != oid {
+		ourMaker, otherMaker = nextBuy, bestBuy
+	}
+
+	orderNotes, done := orderNoteFeed(tCore)
+	defer done()
+
+	test := func(name string, maker *orderbook.Order, expMismatch bool) {
+		t.Helper()
+		tracker := newTracker()
+		if maker != nil {
+			match := &orderbook.EpochMatch{
+				Taker:    oid,
+				Maker:    maker.OrderID,
+				Quantity: lo.Quantity,
+				Rate:     maker.Rate,
+			}
+			mid := match.ID()
+			tracker.matches[mid] = &matchTracker{
+				MetaMatch: db.MetaMatch{
+					UserMatch: &order.UserMatch{
+						OrderID:  oid,
+						MatchID:  mid,
+						Quantity: match.Quantity,
+						Rate:     match.Rate,
+						Side:     order.Taker,
+					},
+					MetaData: &db.MatchMetaData{},
+				},
+			}
+		}
+		rig.ws.queueResponse(msgjson.EpochProofRoute, func(msg *msgjson.Message, f msgFunc) error {
+			resp, _ := msgjson.NewResponse(msg.ID, proof, nil)
+			f(resp)
+			return nil
+		})
+		tCore.verifyEpochProof(dc, note, []*trackedTrade{tracker}, nil, buys, nil)
+		if mismatch := waitEpochProofMismatch(orderNotes); mismatch != expMismatch {
+			t.Fatalf("%s: expected mismatch = %t, got %t", name, expMismatch, mismatch)
+		}
+	}
+
+	test("ok", ourMaker, false)
+	// The server matched the orders in a different order than the shuffle.
+	test("reordered matches", otherMaker, true)
+	test("match not received", nil, true)
+}
+
 func Test_marketTrades(t *testing.T) {
 	mktID := "dcr_btc"
 	dc := &dexConnection{
@@ -6488,6 +6995,65 @@ func Test_marketTrades(t *testing.T) {
 	}
 }
 
+func TestCheckConnectionQuality(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	dc := rig.dc
+
+	feed := tCore.NotificationFeed()
+	defer feed.ReturnFeed()
+	slowNotes := func() (n int) {
+		for {
+			select {
+			case note := <-feed.C:
+				if note.Topic() == TopicDEXSlowServer {
+					n++
+				}
+			default:
+				return
+			}
+		}
+	}
+	setRTT := func(rtt time.Duration) {
+		rig.ws.mtx.Lock()
+		rig.ws.stats.AvgRTT = rtt
+		rig.ws.mtx.Unlock()
+	}
+
+	tests := []struct {
+		name      string
+		avgRTT    time.Duration
+		wantNotes int
+	}{
+		{"fast", time.Millisecond * 100, 0},
+		{"slow", slowServerRTT, 1},
+		{"still slow", slowServerRTT * 2, 0},
+		{"recovering", slowServerRTT * 3 / 4, 0},
+		{"slow again", slowServerRTT, 0},
+		{"recovered", slowServerRTT / 4, 0},
+		{"slow after recovery", slowServerRTT, 1},
+	}
+	for _, tt := range tests {
+		setRTT(tt.avgRTT)
+		tCore.checkConnectionQuality(dc)
+		if n := slowNotes(); n != tt.wantNotes {
+			t.Fatalf("%s: expected %d notifications, got %d", tt.name, tt.wantNotes, n)
+		}
+	}
+
+	stats, err := tCore.ConnectionStats(tDexHost)
+	if err != nil {
+		t.Fatalf("ConnectionStats error: %v", err)
+	}
+	if stats.AvgRTT != slowServerRTT {
+		t.Fatalf("wrong average round trip %v", stats.AvgRTT)
+	}
+	if _, err = tCore.ConnectionStats("unknown.host"); err == nil {
+		t.Fatalf("no error for unknown host")
+	}
+}
+
 func TestLogout(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -9511,7 +10077,13 @@ out:
 				continue
 			}
 			progressNotes++
+			if syncNote.Progress == nil || syncNote.Progress.Rescan {
+				t.Fatalf("wrong sync progress in note: %+v", syncNote.Progress)
+			}
 			if syncNote.SyncStatus.Synced {
+				if !syncNote.Progress.Done || syncNote.Progress.Progress != 1 {
+					t.Fatalf("sync progress not done in synced note: %+v", syncNote.Progress)
+				}
 				break out
 			}
 		case <-timeout.C:
@@ -9523,36 +10095,155 @@ out:
 	if progressNotes > 10 {
 		t.Fatalf("expected 10 progress notes at most, got %d", progressNotes)
 	}
-}
 
-func TestParseCert(t *testing.T) {
-	byteCert := []byte{0x0a, 0x0b}
-	cert, err := parseCert("anyhost", []byte{0x0a, 0x0b}, dex.Mainnet)
-	if err != nil {
-		t.Fatalf("byte cert error: %v", err)
-	}
-	if !bytes.Equal(cert, byteCert) {
-		t.Fatalf("byte cert note returned unmodified. expected %x, got %x", byteCert, cert)
-	}
-	byteCert = []byte{0x05, 0x06}
-	certFile, _ := os.CreateTemp("", "dumbcert")
-	defer os.Remove(certFile.Name())
-	certFile.Write(byteCert)
-	certFile.Close()
-	cert, err = parseCert("anyhost", certFile.Name(), dex.Mainnet)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	progress, err := tCore.WalletSyncProgress(tUTXOAssetA.ID)
 	if err != nil {
-		t.Fatalf("file cert error: %v", err)
+		t.Fatalf("WalletSyncProgress error: %v", err)
 	}
-	if !bytes.Equal(cert, byteCert) {
-		t.Fatalf("byte cert note returned unmodified. expected %x, got %x", byteCert, cert)
+	if !progress.Done || progress.Rescan {
+		t.Fatalf("wrong sync progress %+v", progress)
 	}
-	_, err = parseCert("bison.exchange:17232", []byte(nil), dex.Testnet)
-	if err != nil {
-		t.Fatalf("CertStore cert error: %v", err)
+	if _, err := tCore.WalletSyncProgress(12345); err == nil {
+		t.Fatalf("no error for missing wallet")
 	}
 }
 
-func TestPreOrder(t *testing.T) {
+func TestFeeReport(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	from := time.Unix(1_700_000_000, 0)
+	to := from.Add(time.Hour)
+	stamp := func(d time.Duration) uint64 {
+		return uint64(from.Add(d).Unix())
+	}
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	dcrWallet.Wallet = &TTxHistorian{TXCWallet: tDcrWallet, txs: []*asset.WalletTransaction{
+		{Type: asset.Swap, ID: "swap", Fees: 100, Timestamp: stamp(time.Minute)},
+		{Type: asset.Redeem, ID: "redeem", Fees: 50, Timestamp: stamp(time.Minute)},
+		{Type: asset.Swap, ID: "swap2", Fees: 10, Timestamp: stamp(2 * time.Minute)},
+		{Type: asset.Receive, ID: "receive", Fees: 20, Timestamp: stamp(time.Minute)},
+		{Type: asset.Send, ID: "early", Fees: 20, Timestamp: stamp(-time.Minute)},
+		{Type: asset.Send, ID: "late", Fees: 20, Timestamp: stamp(time.Hour)},
+	}}
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+
+	// A token wallet, whose fees are paid in the parent asset. The swap is
+	// also in the parent wallet's history.
+	const tokenID = 60001
+	tokenWallet, tTokenWallet := newTWallet(tokenID)
+	tokenWallet.parent = dcrWallet
+	tokenWallet.Wallet = &TTxHistorian{TXCWallet: tTokenWallet, txs: []*asset.WalletTransaction{
+		{Type: asset.Swap, ID: "swap", Fees: 100, Timestamp: stamp(time.Minute)},
+		{Type: asset.ApproveToken, ID: "approve", Fees: 30, Timestamp: stamp(time.Minute)},
+	}}
+	tCore.wallets[tokenID] = tokenWallet
+
+	// No transaction history.
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+
+	report, err := tCore.FeeReport(from, to)
+	if err != nil {
+		t.Fatalf("FeeReport error: %v", err)
+	}
+	if report.From != uint64(from.UnixMilli()) || report.To != uint64(to.UnixMilli()) {
+		t.Fatalf("wrong report range %d - %d", report.From, report.To)
+	}
+	if len(report.Unavailable) != 1 || report.Unavailable[0] != tUTXOAssetB.ID {
+		t.Fatalf("wrong unavailable assets %v", report.Unavailable)
+	}
+	if len(report.Assets) != 1 {
+		t.Fatalf("expected fees in 1 asset, got %d", len(report.Assets))
+	}
+	fees := report.Assets[0]
+	if fees.AssetID != tUTXOAssetA.ID || fees.Total != 190 || fees.Count != 4 {
+		t.Fatalf("wrong fees %+v", fees)
+	}
+	for typeName, exp := range map[string]TxTypeFees{
+		"swap":         {Total: 110, Count: 2},
+		"redeem":       {Total: 50, Count: 1},
+		"approveToken": {Total: 30, Count: 1},
+	} {
+		if typeFees := fees.ByType[typeName]; typeFees == nil || *typeFees != exp {
+			t.Fatalf("wrong %s fees %+v", typeName, typeFees)
+		}
+	}
+	if len(fees.ByType) != 3 {
+		t.Fatalf("wrong fee types %v", fees.ByType)
+	}
+
+	// Unbounded includes the early and late sends.
+	if report, err = tCore.FeeReport(time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("FeeReport error: %v", err)
+	}
+	if fees = report.Assets[0]; fees.Total != 230 || fees.ByType["send"].Count != 2 {
+		t.Fatalf("wrong unbounded fees %+v", fees)
+	}
+}
+
+func TestSyncTracker(t *testing.T) {
+	start := time.Now()
+	tracker := newSyncTracker(true, start)
+	tracker.update(100, 100, 1100, false, start)
+	p := tracker.progress(42, start)
+	if !p.Rescan || p.Done || p.Progress != 0 || p.ETA != 0 || p.Stalled {
+		t.Fatalf("wrong initial progress %+v", p)
+	}
+
+	// 500 blocks in 50 seconds leaves 500 blocks in 50 seconds.
+	now := start.Add(50 * time.Second)
+	tracker.update(100, 600, 1100, false, now)
+	p = tracker.progress(42, now)
+	if p.Progress != 0.5 || p.ETA != 50 || p.Height != 600 || p.LastProgress != uint64(now.UnixMilli()) {
+		t.Fatalf("wrong progress %+v", p)
+	}
+
+	// No progress for a long time.
+	now = now.Add(syncStallTimeout + time.Second)
+	tracker.update(100, 600, 1100, false, now)
+	if p = tracker.progress(42, now); !p.Stalled {
+		t.Fatalf("rescan not stalled")
+	}
+
+	tracker.update(100, 1100, 1100, true, now)
+	p = tracker.progress(42, now)
+	if !p.Done || p.Progress != 1 || p.ETA != 0 || p.Stalled {
+		t.Fatalf("wrong final progress %+v", p)
+	}
+}
+
+func TestParseCert(t *testing.T) {
+	byteCert := []byte{0x0a, 0x0b}
+	cert, err := parseCert("anyhost", []byte{0x0a, 0x0b}, dex.Mainnet)
+	if err != nil {
+		t.Fatalf("byte cert error: %v", err)
+	}
+	if !bytes.Equal(cert, byteCert) {
+		t.Fatalf("byte cert note returned unmodified. expected %x, got %x", byteCert, cert)
+	}
+	byteCert = []byte{0x05, 0x06}
+	certFile, _ := os.CreateTemp("", "dumbcert")
+	defer os.Remove(certFile.Name())
+	certFile.Write(byteCert)
+	certFile.Close()
+	cert, err = parseCert("anyhost", certFile.Name(), dex.Mainnet)
+	if err != nil {
+		t.Fatalf("file cert error: %v", err)
+	}
+	if !bytes.Equal(cert, byteCert) {
+		t.Fatalf("byte cert note returned unmodified. expected %x, got %x", byteCert, cert)
+	}
+	_, err = parseCert("bison.exchange:17232", []byte(nil), dex.Testnet)
+	if err != nil {
+		t.Fatalf("CertStore cert error: %v", err)
+	}
+}
+
+func TestPreOrder(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
 	tCore := rig.core
@@ -10788,6 +11479,224 @@ func TestRotateBonds(t *testing.T) {
 	}
 }
 
+func TestAnnotateOrder(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	dc := rig.dc
+	tCore := rig.core
+
+	mkt := dc.marketConfig(tDcrBtcMktName)
+	dcrWallet, _ := newTWallet(mkt.Base)
+	tCore.wallets[mkt.Base] = dcrWallet
+	btcWallet, _ := newTWallet(mkt.Quote)
+	tCore.wallets[mkt.Quote] = btcWallet
+	walletSet, _, _, err := tCore.walletSet(dc, mkt.Base, mkt.Quote, true)
+	if err != nil {
+		t.Fatalf("walletSet error: %v", err)
+	}
+	tracker := makeTradeTracker(rig, walletSet, order.StandingTiF, order.OrderStatusBooked)
+	oid := tracker.ID()
+	dc.trades[oid] = tracker
+	mid := ordertest.RandomMatchID()
+	tracker.matches[mid] = &matchTracker{
+		MetaMatch: db.MetaMatch{
+			UserMatch: &order.UserMatch{
+				OrderID:  oid,
+				MatchID:  mid,
+				Side:     order.Maker,
+				Status:   order.NewlyMatched,
+				Quantity: dcrBtcLotSize,
+				Rate:     dcrBtcRateStep,
+				Address:  "counterparty-address",
+			},
+			MetaData: &db.MatchMetaData{},
+		},
+	}
+
+	form := &AnnotationForm{
+		OrderID: oid[:],
+		Note:    " Placed by hand ",
+		Tags:    []string{"manual", " DCA", "", "dca"},
+	}
+	if err := tCore.AnnotateOrder(form); err != nil {
+		t.Fatalf("AnnotateOrder error: %v", err)
+	}
+	if rig.db.annotatedOrder != oid || rig.db.annotation == nil {
+		t.Fatalf("annotation not stored")
+	}
+	corder := tracker.coreOrder()
+	if corder.Note != "Placed by hand" || len(corder.Tags) != 2 || corder.Tags[1] != "DCA" {
+		t.Fatalf("wrong order annotation. note = %q, tags = %v", corder.Note, corder.Tags)
+	}
+
+	// Match annotation.
+	form = &AnnotationForm{OrderID: oid[:], MatchID: mid[:], Tags: []string{"arb"}}
+	if err := tCore.AnnotateOrder(form); err != nil {
+		t.Fatalf("AnnotateOrder error: %v", err)
+	}
+	if rig.db.annotatedMatch != mid {
+		t.Fatalf("match annotation not stored")
+	}
+	corder = tracker.coreOrder()
+	if len(corder.Matches) != 1 || len(corder.Matches[0].Tags) != 1 || corder.Matches[0].Tags[0] != "arb" {
+		t.Fatalf("wrong match annotation")
+	}
+
+	// Removing the order annotation.
+	if err := tCore.AnnotateOrder(&AnnotationForm{OrderID: oid[:]}); err != nil {
+		t.Fatalf("AnnotateOrder error: %v", err)
+	}
+	if rig.db.annotation != nil {
+		t.Fatalf("empty annotation not removed")
+	}
+	if corder = tracker.coreOrder(); corder.Note != "" || len(corder.Tags) != 0 {
+		t.Fatalf("annotation not removed from the tracked order")
+	}
+
+	// Invalid annotations.
+	for _, form := range []*AnnotationForm{
+		{OrderID: oid[:], Tags: []string{"has space"}},
+		{OrderID: oid[:], Tags: []string{"a,b"}},
+		{OrderID: oid[:], Tags: []string{strings.Repeat("t", maxTagLength+1)}},
+		{OrderID: oid[:], Note: strings.Repeat("n", maxNoteLength+1)},
+		{OrderID: oid[:], MatchID: []byte{1}},
+		{OrderID: []byte{1}},
+	} {
+		if err := tCore.AnnotateOrder(form); err == nil {
+			t.Fatalf("no error for invalid form %+v", form)
+		}
+	}
+
+	// DB error.
+	rig.db.annotationErr = tErr
+	if err := tCore.AnnotateOrder(&AnnotationForm{OrderID: oid[:], Note: "note"}); err == nil {
+		t.Fatalf("no error for DB error")
+	}
+}
+
+func TestBondFundingPlan(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	acct := rig.dc.acct
+	acct.isAuthed = true
+
+	btcBondAsset := &msgjson.BondAsset{ID: tUTXOAssetB.ID, Amt: tFee * 2, Confs: 1}
+	rig.dc.cfg.BondAssets["btc"] = btcBondAsset
+	bondAsset := dcrBondAsset
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	rig.core.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	rig.core.wallets[tUTXOAssetB.ID] = btcWallet
+	feeBuffer := tDcrWallet.BondsFeeBuffer(0)
+	setBalance := func(w *xcWallet, avail uint64) {
+		w.setBalance(&WalletBalance{Balance: &db.Balance{Balance: asset.Balance{Available: avail}}})
+	}
+
+	acct.targetTier = 2
+	acct.bondAsset = bondAsset.ID
+	now := uint64(time.Now().Unix())
+	bondExpiry := rig.dc.config().BondExpiry
+	liveLockTime := now + bondExpiry*4
+	acct.bonds = []*db.Bond{{AssetID: bondAsset.ID, Amount: bondAsset.Amt, LockTime: liveLockTime}}
+
+	plan := func() *HostBondPlan {
+		t.Helper()
+		p, err := rig.core.BondFundingPlan()
+		if err != nil {
+			t.Fatalf("BondFundingPlan error: %v", err)
+		}
+		if len(p.Hosts) != 1 {
+			t.Fatalf("expected 1 host plan, got %d", len(p.Hosts))
+		}
+		return p.Hosts[0]
+	}
+	option := func(hp *HostBondPlan, assetID uint32) *BondFundingOption {
+		t.Helper()
+		for _, opt := range hp.Options {
+			if opt.AssetID == assetID {
+				return opt
+			}
+		}
+		t.Fatalf("no option for %s", unbip(assetID))
+		return nil
+	}
+
+	// The missing tier is posted now, and the live bond renewed later. The
+	// current bond asset has enough for both.
+	dcrRequired := 2*bondAsset.Amt + 2*feeBuffer
+	setBalance(dcrWallet, dcrRequired)
+	setBalance(btcWallet, 2*btcBondAsset.Amt+2*feeBuffer)
+	hp := plan()
+	if len(hp.Renewals) != 2 || hp.Renewals[0].Tiers != 1 || hp.Renewals[1].Tiers != 1 {
+		t.Fatalf("wrong renewals %+v", hp.Renewals)
+	}
+	if hp.Renewals[1].Time != int64(liveLockTime-bondExpiry)-pendingBuffer(rig.core.net) {
+		t.Fatalf("wrong renewal time")
+	}
+	if opt := option(hp, bondAsset.ID); opt.Required != dcrRequired || !opt.Sufficient {
+		t.Fatalf("wrong dcr option %+v", opt)
+	}
+	if hp.RecommendedAssetID == nil || *hp.RecommendedAssetID != bondAsset.ID || hp.Consolidate {
+		t.Fatalf("expected dcr to be recommended without consolidation")
+	}
+
+	// An expired bond will be refunded in time.
+	acct.expiredBonds = []*db.Bond{{AssetID: bondAsset.ID, Amount: bondAsset.Amt, LockTime: now}}
+	setBalance(dcrWallet, dcrRequired-bondAsset.Amt)
+	if hp = plan(); !option(hp, bondAsset.ID).Sufficient {
+		t.Fatalf("expired bond refund not counted")
+	}
+	acct.expiredBonds = nil
+
+	// Not enough dcr. Switch to btc and consolidate.
+	if hp = plan(); option(hp, bondAsset.ID).Sufficient {
+		t.Fatalf("dcr should be insufficient")
+	}
+	if hp.RecommendedAssetID == nil || *hp.RecommendedAssetID != btcBondAsset.ID || !hp.Consolidate {
+		t.Fatalf("expected btc to be recommended with consolidation")
+	}
+
+	// Not enough of either.
+	setBalance(btcWallet, 0)
+	if hp = plan(); hp.RecommendedAssetID != nil {
+		t.Fatalf("expected no recommendation")
+	}
+
+	// A second account using the same wallet renews first, so it is allocated
+	// the funds first.
+	setBalance(dcrWallet, dcrRequired-1)
+	dc2, _, _ := testDexConnection(rig.core.ctx, rig.crypter.(*tCrypter))
+	dc2.acct.host = "someotherdex.tld:7232"
+	dc2.acct.isAuthed = true
+	dc2.acct.targetTier = 1
+	dc2.acct.bondAsset = bondAsset.ID
+	dc2.acct.bonds = []*db.Bond{{AssetID: bondAsset.ID, Amount: bondAsset.Amt, LockTime: now + bondExpiry*2}}
+	rig.core.conns[dc2.acct.host] = dc2
+	acct.targetTier = 1
+	p, err := rig.core.BondFundingPlan()
+	if err != nil {
+		t.Fatalf("BondFundingPlan error: %v", err)
+	}
+	if len(p.Hosts) != 2 || p.Hosts[0].Host != dc2.acct.host {
+		t.Fatalf("wrong host plan order")
+	}
+	if !option(p.Hosts[0], bondAsset.ID).Sufficient {
+		t.Fatalf("first renewal should be funded")
+	}
+	if opt := option(p.Hosts[1], bondAsset.ID); opt.Available != dcrRequired-1-bondAsset.Amt-feeBuffer || opt.Sufficient {
+		t.Fatalf("wrong allocation %+v", opt)
+	}
+
+	if err := rig.core.ApplyBondFundingPlan(nil); err == nil {
+		t.Fatalf("no error for nil plan")
+	}
+	// Nothing to change.
+	if err := rig.core.ApplyBondFundingPlan(p); err != nil {
+		t.Fatalf("ApplyBondFundingPlan error: %v", err)
+	}
+}
+
 func TestFindBondKeyIdx(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -11191,6 +12100,126 @@ func TestTradingLimits(t *testing.T) {
 	checkTradingLimits(8, 20)
 }
 
+func TestTradingLimitStatus(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	rig.dc.acct.rep.BondedTier = 10
+	rig.dc.books[tDcrBtcMktName] = newBookie(rig.dc, tUTXOAssetA.ID, tUTXOAssetB.ID, nil, tLogger)
+	feed := tCore.NotificationFeed()
+	defer feed.ReturnFeed()
+
+	addOrder := func(oid order.OrderID, lots uint64) {
+		rig.dc.trades[oid] = &trackedTrade{
+			Order: &order.LimitOrder{
+				Force: order.StandingTiF,
+				P:     order.Prefix{ServerTime: time.Now()},
+				T: order.Trade{
+					Sell:     true,
+					Quantity: dcrBtcLotSize * lots,
+				},
+			},
+			preImg: newPreimage(),
+			mktID:  tDcrBtcMktName,
+			db:     rig.db,
+			dc:     rig.dc,
+			metaData: &db.OrderMetaData{
+				Status: order.OrderStatusEpoch,
+			},
+		}
+	}
+
+	// checkNotes checks the trading limit notifications sent by a check.
+	checkNotes := func(tag string, expUsed uint32, expWarning bool) {
+		t.Helper()
+		tCore.checkTradingLimits(tDexHost)
+		var update, warning *TradingLimitNote
+		for {
+			select {
+			case n := <-feed.C:
+				note, is := n.(*TradingLimitNote)
+				if !is {
+					continue
+				}
+				switch note.Topic() {
+				case TopicTradingLimitUpdate:
+					update = note
+				case TopicTradingLimitApproaching:
+					warning = note
+				}
+				continue
+			default:
+			}
+			break
+		}
+		if update == nil {
+			t.Fatalf("%s: no update notification", tag)
+		}
+		if update.Host != tDexHost || update.Limits.UsedParcels != expUsed {
+			t.Fatalf("%s: wrong update %+v", tag, update.Limits)
+		}
+		if (warning != nil) != expWarning {
+			t.Fatalf("%s: expected warning = %t, got %v", tag, expWarning, warning)
+		}
+	}
+
+	status, err := tCore.TradingLimitStatus(tDexHost)
+	if err != nil {
+		t.Fatalf("TradingLimitStatus error: %v", err)
+	}
+	if status.Tier != 10 || status.UsedParcels != 0 || status.ParcelLimit != 20 ||
+		status.RemainingParcels != 20 || status.RemainingLots[tDcrBtcMktName] != 20 || status.Approaching {
+		t.Fatalf("wrong initial status %+v", status)
+	}
+	checkNotes("initial", 0, false)
+
+	// No notification if nothing changed.
+	tCore.checkTradingLimits(tDexHost)
+	select {
+	case n := <-feed.C:
+		if _, is := n.(*TradingLimitNote); is {
+			t.Fatalf("notification for unchanged limits")
+		}
+	default:
+	}
+
+	// 80% of the limit.
+	addOrder(order.OrderID{0x01}, 16)
+	checkNotes("approaching", 16, true)
+	status, _ = tCore.TradingLimitStatus(tDexHost)
+	if !status.Approaching || status.RemainingParcels != 4 || status.RemainingLots[tDcrBtcMktName] != 4 {
+		t.Fatalf("wrong approaching status %+v", status)
+	}
+
+	// Only warned once.
+	addOrder(order.OrderID{0x02}, 2)
+	checkNotes("still approaching", 18, false)
+
+	// Over the limit after a tier drop.
+	rig.dc.acct.rep.BondedTier = 5
+	checkNotes("tier drop", 18, false)
+	status, _ = tCore.TradingLimitStatus(tDexHost)
+	if status.ParcelLimit != 10 || status.RemainingParcels != 0 {
+		t.Fatalf("wrong status after tier drop %+v", status)
+	}
+
+	// Warned again after dropping below.
+	rig.dc.acct.rep.BondedTier = 10
+	delete(rig.dc.trades, order.OrderID{0x01})
+	delete(rig.dc.trades, order.OrderID{0x02})
+	checkNotes("cleared", 0, false)
+	addOrder(order.OrderID{0x03}, 17)
+	checkNotes("approaching again", 17, true)
+
+	if _, err := tCore.TradingLimitStatus("unknown.dex"); err == nil {
+		t.Fatalf("no error for unknown host")
+	}
+	if statuses := tCore.TradingLimitStatuses(); len(statuses) != 1 || statuses[0].UsedParcels != 17 {
+		t.Fatalf("wrong statuses %+v", statuses)
+	}
+}
+
 func TestTakeAction(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -11278,3 +12307,1581 @@ func TestTakeAction(t *testing.T) {
 	}
 
 }
+
+func TestLoadLocales(t *testing.T) {
+	dir := t.TempDir()
+	writeLocale := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("error writing locale file: %v", err)
+		}
+	}
+	writeLocale("es.json", `{
+		"AccountRegistered": {"subject": "Cuenta registrada", "template": "Ya puede operar en %s"},
+		"FeePaymentInProgress": {"subject": "Pago en curso", "template": "%[2]s requiere %[1]d confirmaciones"},
+		"RegUpdate": {"subject": "Actualización", "template": "Confirmaciones %d"},
+		"OrderLoadFailure": {"subject": "Error", "template": "Error: %v %v"},
+		"WalletConfigurationUpdated": {"subject": "Configuración"},
+		"NotATopic": {"subject": "x", "template": "y"}
+	}`)
+	writeLocale("en-US.json", `{"AccountRegistered": {"subject": "x", "template": "%s"}}`)
+	writeLocale("not a lang.json", `{}`)
+	writeLocale("fr.json", `not json`)
+	writeLocale("readme.txt", `ignored`)
+	defer func() {
+		localesMtx.Lock()
+		delete(locales, "es")
+		localesMtx.Unlock()
+	}()
+
+	langs, err := loadLocales(dir, tLogger)
+	if err != nil {
+		t.Fatalf("loadLocales error: %v", err)
+	}
+	if len(langs) != 1 || langs[0] != "es" {
+		t.Fatalf("wrong languages loaded: %v", langs)
+	}
+	m, found := localeTranslations("es")
+	if !found {
+		t.Fatalf("es translations not found")
+	}
+	// Only the translations with matching format arguments are loaded.
+	if len(m) != 2 {
+		t.Fatalf("expected 2 translations, got %d", len(m))
+	}
+	if m[TopicAccountRegistered] == nil || m[TopicFeePaymentInProgress] == nil {
+		t.Fatalf("valid translations not loaded")
+	}
+	if originLocale[TopicAccountRegistered].subject.T != "Account registered" {
+		t.Fatalf("origin translation replaced")
+	}
+
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	if err := tCore.SetLanguage("es"); err != nil {
+		t.Fatalf("SetLanguage error: %v", err)
+	}
+	subject, details := tCore.formatDetails(TopicFeePaymentInProgress, 3, "dex.org")
+	if subject != "Pago en curso" || details != "dex.org requiere 3 confirmaciones" {
+		t.Fatalf("wrong translation %q: %q", subject, details)
+	}
+
+	// A missing directory is not an error.
+	if langs, err = loadLocales(filepath.Join(dir, "missing"), tLogger); err != nil || len(langs) != 0 {
+		t.Fatalf("unexpected result for missing directory: %v, %v", langs, err)
+	}
+}
+
+func TestCheckFormatArgs(t *testing.T) {
+	tests := []struct {
+		name, origin, translated string
+		wantErr                  bool
+	}{
+		{"same", "%s has %d", "%s tiene %d", false},
+		{"reordered", "%s has %d", "%[2]d en %[1]s", false},
+		{"v for any", "%s has %d", "%v tiene %v", false},
+		{"escaped percent", "%d%% done", "%d %% hecho", false},
+		{"width and precision", "%.2f and %5d", "%.3f y %d", false},
+		{"missing arg", "%s has %d", "%s tiene", true},
+		{"extra arg", "%s", "%s %s", true},
+		{"wrong verb", "%s has %d", "%d tiene %s", true},
+	}
+	for _, tt := range tests {
+		if err := checkFormatArgs(tt.origin, tt.translated); (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wanted error = %t, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	dir := t.TempDir()
+	writeLocale := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("error writing locale file: %v", err)
+		}
+	}
+	writeLocale("pt.json", `{
+		"AccountRegistered": {"subject": "Conta registada", "template": "Pode agora negociar em %s"},
+		"FeePaymentInProgress": {"subject": "Pagamento em curso", "template": "A aguardar %d confirmações em %s"}
+	}`)
+	writeLocale("pt-BR.json", `{
+		"AccountRegistered": {"subject": "Conta Registrada", "template": "Você agora pode trocar em %s"}
+	}`)
+	defer func() {
+		localesMtx.Lock()
+		delete(locales, "pt")
+		delete(locales, "pt-BR")
+		localesMtx.Unlock()
+	}()
+	if _, err := loadLocales(dir, tLogger); err != nil {
+		t.Fatalf("loadLocales error: %v", err)
+	}
+
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	if err := tCore.SetLanguage("pt-BR"); err != nil {
+		t.Fatalf("SetLanguage error: %v", err)
+	}
+	if tCore.Language() != "pt-BR" {
+		t.Fatalf("wrong language %s", tCore.Language())
+	}
+
+	tests := []struct {
+		topic   Topic
+		args    []any
+		subject string
+		details string
+	}{
+		{TopicAccountRegistered, []any{"dex.org"}, "Conta Registrada", "Você agora pode trocar em dex.org"},
+		{TopicFeePaymentInProgress, []any{2, "dex.org"}, "Pagamento em curso", "A aguardar 2 confirmações em dex.org"},
+		{TopicOrderLoadFailure, []any{"oops"}, "Order load failure", "Some orders failed to load from the database: oops"},
+	}
+	for _, tt := range tests {
+		subject, details := tCore.formatDetails(tt.topic, tt.args...)
+		if subject != tt.subject || details != tt.details {
+			t.Fatalf("%s: wrong translation %q: %q", tt.topic, subject, details)
+		}
+	}
+
+	// A language is only set if it or a parent language has translations.
+	if err := tCore.SetLanguage("ja-JP"); err == nil {
+		t.Fatalf("no error for language without translations")
+	}
+	// But a child of a translated language is fine.
+	if err := tCore.SetLanguage("pt-PT"); err != nil {
+		t.Fatalf("SetLanguage error: %v", err)
+	}
+	if subject, _ := tCore.formatDetails(TopicAccountRegistered, "dex.org"); subject != "Conta registada" {
+		t.Fatalf("wrong subject for pt-PT: %q", subject)
+	}
+
+	// Topics translated by a parent language are not missing.
+	n := len(originLocale)
+	expMissing := (n - 2) + (n - 2) + (n - len(deDE)) + (n - len(zhCN))
+	if missing := CheckTopicLangs(); missing != expMissing {
+		t.Fatalf("expected %d missing translations, got %d", expMissing, missing)
+	}
+}
+
+func TestTranslationReports(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "es.json"), []byte(`{
+		"AccountRegistered": {"subject": "Cuenta registrada", "template": "Ya puede operar en %s"}
+	}`), 0644); err != nil {
+		t.Fatalf("error writing locale file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "es-MX.json"), []byte(`{
+		"FeePaymentInProgress": {"subject": "Pago en curso", "template": "Esperando %d confirmaciones en %s"}
+	}`), 0644); err != nil {
+		t.Fatalf("error writing locale file: %v", err)
+	}
+	defer func() {
+		localesMtx.Lock()
+		delete(locales, "es")
+		delete(locales, "es-MX")
+		localesMtx.Unlock()
+	}()
+	if _, err := loadLocales(dir, tLogger); err != nil {
+		t.Fatalf("loadLocales error: %v", err)
+	}
+	// Problems that are rejected by loadLocales can still be in compiled
+	// translations.
+	localesMtx.Lock()
+	locales["es-MX"][TopicDEXConnected] = &translation{template: intl.Translation{T: "%d conectado"}}
+	locales["es-MX"]["NotATopic"] = &translation{subject: intl.Translation{T: "?"}, template: intl.Translation{T: "?"}}
+	localesMtx.Unlock()
+
+	reports := TranslationReports()
+	var langs []string
+	for _, r := range reports {
+		langs = append(langs, r.Lang)
+	}
+	if strings.Join(langs, ",") != "de-DE,es,es-MX,zh-CN" {
+		t.Fatalf("wrong report languages %v", langs)
+	}
+
+	r, err := LangTranslationReport("es-MX")
+	if err != nil {
+		t.Fatalf("LangTranslationReport error: %v", err)
+	}
+	n := len(originLocale)
+	if r.Translated != 2 || len(r.Inherited) != 1 || r.Inherited[0] != TopicAccountRegistered || len(r.Missing) != n-3 {
+		t.Fatalf("wrong counts: translated = %d, inherited = %v, missing = %d", r.Translated, r.Inherited, len(r.Missing))
+	}
+	if len(r.MissingStrings) != 1 || r.MissingStrings[0] != string(TopicDEXConnected)+" subject" {
+		t.Fatalf("wrong missing strings %v", r.MissingStrings)
+	}
+	if len(r.BadFormat) != 1 || r.BadFormat[TopicDEXConnected] == "" {
+		t.Fatalf("wrong bad format %v", r.BadFormat)
+	}
+	if len(r.Unknown) != 1 || r.Unknown[0] != "NotATopic" {
+		t.Fatalf("wrong unknown topics %v", r.Unknown)
+	}
+
+	// The built-in translations are valid.
+	for _, lang := range []string{"de-DE", "zh-CN"} {
+		r, err := LangTranslationReport(lang)
+		if err != nil {
+			t.Fatalf("LangTranslationReport(%s) error: %v", lang, err)
+		}
+		if len(r.MissingStrings) > 0 || len(r.BadFormat) > 0 || len(r.Unknown) > 0 {
+			t.Fatalf("%s: problems with translations: %+v", lang, r)
+		}
+	}
+
+	// Explicit argument indexes are formatted correctly.
+	rig := newTestRig()
+	defer rig.shutdown()
+	if err := rig.core.SetLanguage("zh-CN"); err != nil {
+		t.Fatalf("SetLanguage error: %v", err)
+	}
+	if _, details := rig.core.formatDetails(TopicFeePaymentInProgress, 2, "dex.org"); details != "在 dex.org 交易前等待 2 次确认" {
+		t.Fatalf("wrong zh-CN details %q", details)
+	}
+
+	if _, err := LangTranslationReport("ja"); err == nil {
+		t.Fatalf("no error for language without translations")
+	}
+	if _, err := LangTranslationReport("not a language"); err == nil {
+		t.Fatalf("no error for invalid language")
+	}
+}
+
+func TestTopicSettings(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	feed := tCore.NotificationFeed()
+	defer feed.ReturnFeed()
+
+	const topicA, topicB Topic = "TopicA", "TopicB"
+	sent := func(topic Topic, severity db.Severity) bool {
+		t.Helper()
+		tCore.notify(newSecurityNote(topic, "subject", "details", severity))
+		select {
+		case n := <-feed.C:
+			if n.Topic() != topic {
+				t.Fatalf("wrong topic %s", n.Topic())
+			}
+			return true
+		default:
+			return false
+		}
+	}
+
+	// Defaults.
+	if !sent(topicA, db.Poke) || !sent(topicB, db.Data) {
+		t.Fatalf("notification not sent with default settings")
+	}
+
+	if err := tCore.UpdateTopicSettings(topicA, &db.TopicSettings{Mute: true}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	if err := tCore.UpdateTopicSettings(topicB, &db.TopicSettings{MinSeverity: db.WarningLevel}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	if len(rig.db.topicSettings) != 2 {
+		t.Fatalf("settings not stored")
+	}
+	if sent(topicA, db.WarningLevel) {
+		t.Fatalf("muted notification sent")
+	}
+	// Errors are not muted.
+	if !sent(topicA, db.ErrorLevel) {
+		t.Fatalf("muted error notification not sent")
+	}
+	if sent(topicB, db.Success) {
+		t.Fatalf("notification below minimum severity sent")
+	}
+	if !sent(topicB, db.WarningLevel) {
+		t.Fatalf("notification at minimum severity not sent")
+	}
+
+	// Log only.
+	if err := tCore.UpdateTopicSettings(topicB, &db.TopicSettings{LogOnly: true}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	if sent(topicB, db.ErrorLevel) {
+		t.Fatalf("log-only notification sent")
+	}
+
+	// Zero settings restore the default.
+	if err := tCore.UpdateTopicSettings(topicA, &db.TopicSettings{}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	if !sent(topicA, db.Poke) {
+		t.Fatalf("notification not sent after restoring default settings")
+	}
+	settings := tCore.TopicSettings()
+	if len(settings) != 1 || !settings[topicB].LogOnly {
+		t.Fatalf("wrong settings %+v", settings)
+	}
+
+	// Settings are not changed if they are not stored.
+	rig.db.topicSettingsErr = tErr
+	if err := tCore.UpdateTopicSettings(topicB, nil); err == nil {
+		t.Fatalf("no error for db error")
+	}
+	rig.db.topicSettingsErr = nil
+	if len(tCore.TopicSettings()) != 1 {
+		t.Fatalf("settings changed after db error")
+	}
+
+	// Invalid settings.
+	if err := tCore.UpdateTopicSettings("", &db.TopicSettings{Mute: true}); err == nil {
+		t.Fatalf("no error for empty topic")
+	}
+	if err := tCore.UpdateTopicSettings(topicA, &db.TopicSettings{MinSeverity: db.ErrorLevel + 1}); err == nil {
+		t.Fatalf("no error for invalid severity")
+	}
+}
+
+func TestNoteTemplates(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	const host = "dex.example.com"
+	subject, details := tCore.formatDetails(TopicFeePaymentInProgress, 2, host)
+	if subject != "Fee payment in progress" || details != "Waiting for 2 confirmations before trading at "+host {
+		t.Fatalf("wrong translation %q, %q", subject, details)
+	}
+
+	// Invalid templates.
+	for _, tmpl := range []*db.NoteTemplate{
+		{Template: "%d confirmations"},                 // missing argument
+		{Template: "%s confirmations at %s"},           // wrong verb
+		{Template: "%d confirmations at %s, %s again"}, // extra argument
+	} {
+		if err := tCore.UpdateNoteTemplate(TopicFeePaymentInProgress, tmpl); err == nil {
+			t.Fatalf("no error for invalid template %q", tmpl.Template)
+		}
+	}
+	if err := tCore.UpdateNoteTemplate("NotATopic", &db.NoteTemplate{Subject: "subject"}); err == nil {
+		t.Fatalf("no error for unknown topic")
+	}
+	if len(tCore.NoteTemplates()) != 0 {
+		t.Fatalf("invalid templates stored")
+	}
+
+	// The template replaces the translation.
+	tmpl := &db.NoteTemplate{Subject: "Bond pending", Template: "%[2]s: %[1]v confirmations to go"}
+	if err := tCore.UpdateNoteTemplate(TopicFeePaymentInProgress, tmpl); err != nil {
+		t.Fatalf("UpdateNoteTemplate error: %v", err)
+	}
+	if len(rig.db.noteTemplates) != 1 {
+		t.Fatalf("templates not stored")
+	}
+	subject, details = tCore.formatDetails(TopicFeePaymentInProgress, 2, host)
+	if subject != tmpl.Subject || details != host+": 2 confirmations to go" {
+		t.Fatalf("wrong custom translation %q, %q", subject, details)
+	}
+
+	// A subject alone keeps the translated template.
+	if err := tCore.UpdateNoteTemplate(TopicAccountRegistered, &db.NoteTemplate{Subject: "Ready"}); err != nil {
+		t.Fatalf("UpdateNoteTemplate error: %v", err)
+	}
+	subject, details = tCore.formatDetails(TopicAccountRegistered, host)
+	if subject != "Ready" || details != "You may now trade at "+host {
+		t.Fatalf("wrong custom subject %q, %q", subject, details)
+	}
+	if len(tCore.NoteTemplates()) != 2 {
+		t.Fatalf("expected 2 templates")
+	}
+
+	// Templates are not changed if they are not stored.
+	rig.db.noteTemplatesErr = tErr
+	if err := tCore.UpdateNoteTemplate(TopicAccountRegistered, nil); err == nil {
+		t.Fatalf("no error for db error")
+	}
+	rig.db.noteTemplatesErr = nil
+	if len(tCore.NoteTemplates()) != 2 {
+		t.Fatalf("templates changed after db error")
+	}
+
+	// Zero templates restore the translation.
+	if err := tCore.UpdateNoteTemplate(TopicFeePaymentInProgress, &db.NoteTemplate{}); err != nil {
+		t.Fatalf("UpdateNoteTemplate error: %v", err)
+	}
+	subject, _ = tCore.formatDetails(TopicFeePaymentInProgress, 2, host)
+	if subject != "Fee payment in progress" {
+		t.Fatalf("translation not restored")
+	}
+	if templates := tCore.NoteTemplates(); len(templates) != 1 || templates[TopicAccountRegistered].Subject != "Ready" {
+		t.Fatalf("wrong templates %+v", templates)
+	}
+}
+
+func TestConfTargets(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	wallet, tWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = wallet
+	tWallet.sendCoin = &tCoin{id: encode.RandomBytes(36)}
+	feeRater := &TFeeRater{TXCWallet: tWallet, feeRate: 10}
+	wallet.Wallet = feeRater
+
+	// The wallet must support confirmation targets.
+	if err := tCore.SetConfTarget(tUTXOAssetA.ID, &db.ConfTarget{Speed: db.ConfSpeedFast}); err == nil {
+		t.Fatalf("no error for a wallet without target fee rates")
+	}
+	targetRater := &TTargetFeeRater{
+		TFeeRater:   feeRater,
+		targetRates: map[uint64]uint64{1: 30, 3: 20, 12: 5, 6: 15},
+	}
+	wallet.Wallet = targetRater
+
+	// Invalid targets.
+	for _, target := range []*db.ConfTarget{
+		{Speed: "ludicrous"},
+		{Speed: db.ConfSpeedFast, Blocks: 2},
+		{Blocks: maxConfTarget + 1},
+	} {
+		if err := tCore.SetConfTarget(tUTXOAssetA.ID, target); err == nil {
+			t.Fatalf("no error for invalid target %+v", target)
+		}
+	}
+	if err := tCore.SetConfTarget(12345, &db.ConfTarget{Blocks: 2}); err == nil {
+		t.Fatalf("no error for unknown wallet")
+	}
+	if len(tCore.ConfTargets()) != 0 {
+		t.Fatalf("invalid targets stored")
+	}
+
+	send := func(wantRate uint64) {
+		t.Helper()
+		if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, "addr", false); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		if tWallet.sendFeeSuggestion != wantRate {
+			t.Fatalf("wrong fee rate. wanted %d, got %d", wantRate, tWallet.sendFeeSuggestion)
+		}
+	}
+
+	// No target uses the default rate.
+	send(10)
+
+	for _, tt := range []struct {
+		target   *db.ConfTarget
+		wantRate uint64
+	}{
+		{&db.ConfTarget{Speed: db.ConfSpeedFast}, 30},
+		{&db.ConfTarget{Speed: db.ConfSpeedNormal}, 20},
+		{&db.ConfTarget{Speed: db.ConfSpeedEconomy}, 5},
+		{&db.ConfTarget{Blocks: 6}, 15},
+	} {
+		if err := tCore.SetConfTarget(tUTXOAssetA.ID, tt.target); err != nil {
+			t.Fatalf("SetConfTarget error: %v", err)
+		}
+		send(tt.wantRate)
+	}
+	if rig.db.confTargets[tUTXOAssetA.ID].Blocks != 6 {
+		t.Fatalf("target not stored")
+	}
+
+	// A failed estimate falls back to the default rate.
+	targetRater.targetRateErr = tErr
+	send(10)
+	targetRater.targetRateErr = nil
+
+	// Targets are not changed if they are not stored.
+	rig.db.confTargetsErr = tErr
+	if err := tCore.SetConfTarget(tUTXOAssetA.ID, nil); err == nil {
+		t.Fatalf("no error for db error")
+	}
+	rig.db.confTargetsErr = nil
+	send(15)
+
+	// A zero target restores the default rate.
+	if err := tCore.SetConfTarget(tUTXOAssetA.ID, &db.ConfTarget{}); err != nil {
+		t.Fatalf("SetConfTarget error: %v", err)
+	}
+	if len(tCore.ConfTargets()) != 0 {
+		t.Fatalf("target not removed")
+	}
+	send(10)
+}
+
+func TestSearchNotifications(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	if _, err := tCore.SearchNotifications(nil); err != nil {
+		t.Fatalf("SearchNotifications error: %v", err)
+	}
+	if rig.db.noteFilter.N != maxNoteSearchResults {
+		t.Fatalf("wrong default N %d", rig.db.noteFilter.N)
+	}
+	filter := &db.NoteFilter{N: 10, Text: "order", SinceUnixMs: 100, UntilUnixMs: 200}
+	if _, err := tCore.SearchNotifications(filter); err != nil {
+		t.Fatalf("SearchNotifications error: %v", err)
+	}
+	if rig.db.noteFilter.N != 10 || rig.db.noteFilter.Text != "order" {
+		t.Fatalf("filter not passed to db")
+	}
+	if _, err := tCore.SearchNotifications(&db.NoteFilter{N: maxNoteSearchResults + 1}); err != nil {
+		t.Fatalf("SearchNotifications error: %v", err)
+	}
+	if rig.db.noteFilter.N != maxNoteSearchResults {
+		t.Fatalf("N not limited")
+	}
+	if _, err := tCore.SearchNotifications(&db.NoteFilter{SinceUnixMs: 200, UntilUnixMs: 100}); err == nil {
+		t.Fatalf("no error for invalid time range")
+	}
+
+	// Retention.
+	tCore.pruneNotifications()
+	if rig.db.notesDeletedBefore != 0 {
+		t.Fatalf("notifications deleted without retention period")
+	}
+	tCore.cfg.NoteRetention = time.Hour
+	tCore.pruneNotifications()
+	expCutoff := uint64(time.Now().Add(-time.Hour).UnixMilli())
+	if cutoff := rig.db.notesDeletedBefore; cutoff > expCutoff || cutoff < expCutoff-1000 {
+		t.Fatalf("wrong cutoff %d, expected ~%d", cutoff, expCutoff)
+	}
+}
+
+func TestDesktopNotificationFeed(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	feed := tCore.NotificationFeed()
+	defer feed.ReturnFeed()
+	desktopFeed := tCore.DesktopNotificationFeed()
+
+	const topicA, topicB Topic = "TopicA", "TopicB"
+	sentToDesktop := func(topic Topic, severity db.Severity) bool {
+		t.Helper()
+		tCore.notify(newSecurityNote(topic, "subject", "details", severity))
+		select {
+		case <-feed.C:
+		default:
+		}
+		select {
+		case n := <-desktopFeed.C:
+			if n.Topic() != topic {
+				t.Fatalf("wrong topic %s", n.Topic())
+			}
+			return true
+		default:
+			return false
+		}
+	}
+
+	// Desktop notifications are opt-in.
+	if sentToDesktop(topicA, db.ErrorLevel) {
+		t.Fatalf("desktop notification sent without opting in")
+	}
+
+	if err := tCore.UpdateTopicSettings(topicA, &db.TopicSettings{Desktop: true}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	if !sentToDesktop(topicA, db.Success) {
+		t.Fatalf("desktop notification not sent after opting in")
+	}
+	if sentToDesktop(topicB, db.Success) {
+		t.Fatalf("desktop notification sent for wrong topic")
+	}
+
+	// Other settings still apply.
+	if err := tCore.UpdateTopicSettings(topicA, &db.TopicSettings{Desktop: true, MinSeverity: db.WarningLevel}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	if sentToDesktop(topicA, db.Success) {
+		t.Fatalf("desktop notification below minimum severity sent")
+	}
+	if err := tCore.UpdateTopicSettings(topicA, &db.TopicSettings{Desktop: true, LogOnly: true}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	if sentToDesktop(topicA, db.ErrorLevel) {
+		t.Fatalf("log-only desktop notification sent")
+	}
+
+	// Returned feeds receive nothing.
+	if err := tCore.UpdateTopicSettings(topicA, &db.TopicSettings{Desktop: true}); err != nil {
+		t.Fatalf("UpdateTopicSettings error: %v", err)
+	}
+	desktopFeed.ReturnFeed()
+	if sentToDesktop(topicA, db.ErrorLevel) {
+		t.Fatalf("desktop notification sent to returned feed")
+	}
+}
+
+func TestNotificationMetadata(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	feed := tCore.NotificationFeed()
+	defer feed.ReturnFeed()
+
+	tests := []struct {
+		name    string
+		note    db.Notification
+		expPrio db.Priority
+		expCat  db.Category
+	}{{
+		name:    "security error",
+		note:    db.NewNotification(NoteTypeSecurity, "TopicA", "subject", "details", db.ErrorLevel),
+		expPrio: db.PriorityHigh,
+		expCat:  db.CategorySecurity,
+	}, {
+		name:    "wallet success",
+		note:    db.NewNotification(NoteTypeBalance, "TopicA", "subject", "details", db.Success),
+		expPrio: db.PriorityNormal,
+		expCat:  db.CategoryWallet,
+	}, {
+		name:    "order poke",
+		note:    db.NewNotification(NoteTypeOrder, TopicOrderLoadFailure, "subject", "details", db.Poke),
+		expPrio: db.PriorityLow,
+		expCat:  db.CategoryTrading,
+	}, {
+		name:    "data",
+		note:    db.NewNotification(NoteTypeEpoch, "TopicA", "", "", db.Data),
+		expPrio: db.PriorityNone,
+		expCat:  db.CategoryTrading,
+	}, {
+		name:    "topic override",
+		note:    db.NewNotification(NoteTypeMatch, TopicBuyMatchesMade, "subject", "details", db.Success),
+		expPrio: db.PriorityHigh,
+		expCat:  db.CategoryTrading,
+	}, {
+		name:    "action required",
+		note:    db.NewNotification(NoteTypeActionRequired, "TopicA", "", "", db.Data),
+		expPrio: db.PriorityUrgent,
+		expCat:  db.CategoryWallet,
+	}, {
+		name:    "unknown type",
+		note:    db.NewNotification("sometype", "TopicA", "subject", "details", db.Success),
+		expPrio: db.PriorityNormal,
+		expCat:  db.CategoryApp,
+	}}
+
+	for _, tt := range tests {
+		tCore.notify(&tt.note)
+		var n Notification
+		select {
+		case n = <-feed.C:
+		default:
+			t.Fatalf("%s: no notification received", tt.name)
+		}
+		if n.Priority() != tt.expPrio {
+			t.Fatalf("%s: wrong priority %s, expected %s", tt.name, n.Priority(), tt.expPrio)
+		}
+		if n.Category() != tt.expCat {
+			t.Fatalf("%s: wrong category %q, expected %q", tt.name, n.Category(), tt.expCat)
+		}
+	}
+
+	// Metadata that is already set is not overwritten.
+	n := db.NewNotification(NoteTypeSecurity, "TopicA", "subject", "details", db.Poke)
+	n.Prio, n.Cat = db.PriorityUrgent, db.CategoryApp
+	setNoteMetadata(&n)
+	if n.Prio != db.PriorityUrgent || n.Cat != db.CategoryApp {
+		t.Fatalf("metadata overwritten: %s, %q", n.Prio, n.Cat)
+	}
+}
+
+func TestNotificationData(t *testing.T) {
+	oid := encode.RandomBytes(32)
+	corder := &Order{
+		Host:     tDexHost,
+		BaseID:   tUTXOAssetA.ID,
+		QuoteID:  tUTXOAssetB.ID,
+		MarketID: tDcrBtcMktName,
+		ID:       oid,
+		Qty:      1e8,
+	}
+	note := newOrderNote(TopicOrderBooked, "subject", "details", db.Success, corder)
+	expData := &db.NoteData{
+		Host:     tDexHost,
+		AssetID:  &corder.BaseID,
+		MarketID: tDcrBtcMktName,
+		OrderID:  oid,
+		Amount:   1e8,
+	}
+	if !reflect.DeepEqual(note.Data(), expData) {
+		t.Fatalf("wrong order note data %+v, expected %+v", note.Data(), expData)
+	}
+
+	// The data is included in the JSON for the frontends.
+	b, err := json.Marshal(note)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var noteData struct {
+		Data *db.NoteData `json:"data"`
+	}
+	if err := json.Unmarshal(b, &noteData); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(noteData.Data, expData) {
+		t.Fatalf("wrong JSON note data %+v, expected %+v", noteData.Data, expData)
+	}
+
+	// Setting the data does not change the ID.
+	sendNote := newSendNote(TopicSendError, "subject", "details", db.ErrorLevel)
+	id := sendNote.ID()
+	assetID := tUTXOAssetA.ID
+	withData(sendNote, &db.NoteData{AssetID: &assetID, Error: "error"})
+	if !bytes.Equal(sendNote.ID(), id) || !bytes.Equal(sendNote.Id, id) {
+		t.Fatalf("ID changed with data")
+	}
+
+	// Notes without data have nil Data.
+	if newSecurityNote(TopicSeedNeedsSaving, "subject", "details", db.Success).Data() != nil {
+		t.Fatalf("unexpected data")
+	}
+
+	// Aggregate notes have the data of the repeated notification.
+	if agg := newAggregateNote(note, 2, note.Time()); !reflect.DeepEqual(agg.Data(), expData) {
+		t.Fatalf("wrong aggregate note data %+v, expected %+v", agg.Data(), expData)
+	}
+}
+
+func TestNotificationAggregation(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	const window = 100 * time.Millisecond
+	tCore.noteAgg = newNoteAggregator(window, tCore.sendAggregateNote)
+	feed := tCore.NotificationFeed()
+	defer feed.ReturnFeed()
+
+	const topic Topic = "TopicA"
+	notify := func(details string, severity db.Severity) {
+		tCore.notify(newSecurityNote(topic, "subject", details, severity))
+	}
+	received := func() []Notification {
+		var ns []Notification
+		for {
+			select {
+			case n := <-feed.C:
+				ns = append(ns, n)
+			default:
+				return ns
+			}
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		notify("details", db.WarningLevel)
+		notify("other details", db.WarningLevel)
+		notify("data", db.Data) // data notes are never collapsed
+	}
+	ns := received()
+	if len(ns) != 5 {
+		t.Fatalf("expected 5 notifications, got %d", len(ns))
+	}
+	if raw := tCore.RawNotifications(); len(raw) != 6 {
+		t.Fatalf("expected 6 raw notifications, got %d", len(raw))
+	}
+
+	time.Sleep(window * 2)
+	ns = received()
+	if len(ns) != 2 {
+		t.Fatalf("expected 2 aggregate notifications, got %d", len(ns))
+	}
+	for _, n := range ns {
+		aggNote, ok := n.(*AggregateNote)
+		if !ok {
+			t.Fatalf("wrong notification type %T", n)
+		}
+		if aggNote.Repeats != 2 || aggNote.Topic() != topic || aggNote.RepeatedType != NoteTypeSecurity {
+			t.Fatalf("wrong aggregate note %+v", aggNote)
+		}
+		if !strings.HasSuffix(aggNote.Details(), "(repeated 2 more times)") {
+			t.Fatalf("wrong details %q", aggNote.Details())
+		}
+	}
+
+	// A new window starts with the next notification.
+	notify("details", db.WarningLevel)
+	if ns = received(); len(ns) != 1 {
+		t.Fatalf("expected 1 notification in new window, got %d", len(ns))
+	}
+}
+
+func TestUniqueBackupPath(t *testing.T) {
+	dir := t.TempDir()
+	stamp := time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC)
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path := uniqueBackupPath(dir, stamp)
+		if err := os.WriteFile(path, nil, 0600); err != nil {
+			t.Fatalf("error writing %s: %v", path, err)
+		}
+		paths = append(paths, filepath.Base(path))
+	}
+	// A backup a millisecond later.
+	path := uniqueBackupPath(dir, stamp.Add(time.Millisecond))
+	os.WriteFile(path, nil, 0600)
+	paths = append(paths, filepath.Base(path))
+
+	if paths[0] != backupFilePrefix+"20240102-030405.006"+backupFileExt {
+		t.Fatalf("wrong backup name %s", paths[0])
+	}
+	// The names are unique and sort chronologically, for rotation.
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != len(paths) {
+		t.Fatalf("expected %d backups, found %d", len(paths), len(entries))
+	}
+	for i, e := range entries {
+		if e.Name() != paths[i] {
+			t.Fatalf("backup %d is %s, expected %s", i, e.Name(), paths[i])
+		}
+	}
+}
+
+func TestEncryptedBackups(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	dir := t.TempDir()
+	tCore.cfg.DBPath = filepath.Join(dir, "dexc.db")
+	tCore.cfg.BackupsKept = 2
+
+	// A real database, so that the restored copy can be validated.
+	srcPath := filepath.Join(dir, "src.db")
+	srcDB, err := bolt.NewDB(srcPath, tLogger, bolt.Opts{})
+	if err != nil {
+		t.Fatalf("error creating database: %v", err)
+	}
+	err = srcDB.SetPrimaryCredentials(&db.PrimaryCredentials{
+		EncSeed:        encode.RandomBytes(32),
+		EncInnerKey:    encode.RandomBytes(32),
+		InnerKeyParams: encode.RandomBytes(32),
+		OuterKeyParams: encode.RandomBytes(32),
+	})
+	if err != nil {
+		t.Fatalf("error setting credentials: %v", err)
+	}
+	srcDB.(*bolt.BoltDB).Close()
+	rig.db.backupToData, err = os.ReadFile(srcPath)
+	if err != nil {
+		t.Fatalf("error reading database: %v", err)
+	}
+
+	// Not logged in.
+	if _, err := tCore.BackupNow(); err == nil {
+		t.Fatalf("no error for backup before login")
+	}
+
+	seed := encode.RandomBytes(legacySeedLength)
+	tCore.backupKey = backupKey(seed)
+	path, err := tCore.BackupNow()
+	if err != nil {
+		t.Fatalf("BackupNow error: %v", err)
+	}
+	if filepath.Dir(path) != tCore.backupDir() {
+		t.Fatalf("backup written to %s, expected in %s", path, tCore.backupDir())
+	}
+
+	// Wrong seed.
+	if _, err := tCore.RestoreBackup(path, hex.EncodeToString(encode.RandomBytes(legacySeedLength))); !errors.Is(err, db.ErrBackupIntegrity) {
+		t.Fatalf("expected ErrBackupIntegrity for wrong seed, got %v", err)
+	}
+
+	// Corrupt file.
+	b, _ := os.ReadFile(path)
+	b[len(b)-1] ^= 0x01
+	badPath := filepath.Join(dir, "bad.bwbak")
+	os.WriteFile(badPath, b, 0600)
+	if _, err := tCore.RestoreBackup(badPath, hex.EncodeToString(seed)); !errors.Is(err, db.ErrBackupIntegrity) {
+		t.Fatalf("expected ErrBackupIntegrity for corrupt file, got %v", err)
+	}
+
+	restored, err := tCore.RestoreBackup(path, hex.EncodeToString(seed))
+	if err != nil {
+		t.Fatalf("RestoreBackup error: %v", err)
+	}
+	// Opening the restored database for validation may modify it, so just
+	// check that it's there.
+	if _, err := os.Stat(restored.DBPath); err != nil {
+		t.Fatalf("restored database not written: %v", err)
+	}
+	if restored.Stamp == 0 {
+		t.Fatalf("restored backup has no stamp")
+	}
+	if _, err := os.Stat(restored.WalletsPath); err != nil {
+		t.Fatalf("restored wallets not written: %v", err)
+	}
+
+	// Previously restored files are not overwritten.
+	if _, err := tCore.RestoreBackup(path, hex.EncodeToString(seed)); err == nil {
+		t.Fatalf("no error for overwriting a restored database")
+	}
+
+	// An invalid database is not restored.
+	os.RemoveAll(filepath.Join(dir, restoredDir))
+	rig.db.backupToData = []byte("not a database")
+	os.Remove(path)
+	if path, err = tCore.BackupNow(); err != nil {
+		t.Fatalf("BackupNow error: %v", err)
+	}
+	if _, err := tCore.RestoreBackup(path, hex.EncodeToString(seed)); err == nil {
+		t.Fatalf("no error for invalid database")
+	}
+	if _, err := os.Stat(filepath.Join(dir, restoredDir, "dexc.db")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("invalid restored database not removed")
+	}
+
+	// Rotation.
+	for _, stamp := range []string{"20200101-000000", "20210101-000000", "20220101-000000"} {
+		os.WriteFile(filepath.Join(tCore.backupDir(), backupFilePrefix+stamp+backupFileExt), nil, 0600)
+	}
+	tCore.rotateBackups(tCore.backupDir())
+	entries, _ := os.ReadDir(tCore.backupDir())
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 || names[0] != backupFilePrefix+"20220101-000000"+backupFileExt || names[1] != filepath.Base(path) {
+		t.Fatalf("wrong backups after rotation: %v", names)
+	}
+}
+
+func TestDBMaintenance(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	rig.db.integrityErr = errors.New("test error")
+	if _, err := tCore.DBMaintenance(); err == nil {
+		t.Fatalf("no error for integrity check error")
+	}
+	if rig.db.compactOnShutdown {
+		t.Fatalf("compaction requested after integrity check error")
+	}
+
+	rig.db.integrityErr = nil
+	rig.db.integrityReport = &db.IntegrityReport{
+		Problems: []*db.IntegrityProblem{{Kind: db.ProblemOrphanedMatch}},
+	}
+	report, err := tCore.DBMaintenance()
+	if err != nil {
+		t.Fatalf("DBMaintenance error: %v", err)
+	}
+	if len(report.Problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(report.Problems))
+	}
+	if !rig.db.compactOnShutdown {
+		t.Fatalf("compaction not requested")
+	}
+
+	// MaintainDB with a real database.
+	dbPath := filepath.Join(t.TempDir(), "dexc.db")
+	cfg := &Config{DBPath: dbPath, Logger: tLogger, NoAutoDBBackup: true}
+	if _, err := MaintainDB(cfg); err == nil {
+		t.Fatalf("no error for missing database")
+	}
+	boltDB, err := bolt.NewDB(dbPath, tLogger, bolt.Opts{})
+	if err != nil {
+		t.Fatalf("error creating database: %v", err)
+	}
+	boltDB.(*bolt.BoltDB).Close()
+	if report, err = MaintainDB(cfg); err != nil {
+		t.Fatalf("MaintainDB error: %v", err)
+	}
+	if len(report.Problems) != 0 {
+		t.Fatalf("unexpected problems: %+v", report.Problems)
+	}
+}
+
+func TestMatchRecords(t *testing.T) {
+	const baseID, quoteID = 42, 0
+	coin := func(assetID uint32, s string) *Coin {
+		return &Coin{AssetID: assetID, StringID: s}
+	}
+	ord := &Order{
+		Host:    tDexHost,
+		ID:      encode.RandomBytes(32),
+		BaseID:  baseID,
+		QuoteID: quoteID,
+		Sell:    true,
+		Note:    "manual",
+		Tags:    []string{"a", "b"},
+		FeesPaid: &FeeBreakdown{
+			Swap:       3000,
+			Redemption: 900,
+		},
+		Matches: []*Match{
+			{
+				MatchID:     encode.RandomBytes(32),
+				Status:      order.MatchConfirmed,
+				Side:        order.Maker,
+				Rate:        2e7, // 0.2
+				Qty:         2e8,
+				Stamp:       1700000000000,
+				Swap:        coin(baseID, "aa:0"),
+				CounterSwap: coin(quoteID, "bb:1"),
+				Redeem:      coin(quoteID, "cc:0"),
+			},
+			{
+				MatchID: encode.RandomBytes(32),
+				Status:  order.MakerSwapCast,
+				Side:    order.Taker,
+				Rate:    2e7,
+				Qty:     1e8,
+				Stamp:   1700000001000,
+				Swap:    coin(baseID, "dd:0"),
+				Refund:  coin(baseID, "ee"),
+				Note:    "oops",
+			},
+			{
+				MatchID:  encode.RandomBytes(32),
+				Qty:      5e8,
+				IsCancel: true,
+			},
+		},
+	}
+
+	recs := matchRecords(ord)
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	completed, refunded := recs[0], recs[1]
+	if completed.SentID != baseID || completed.Sent != 2e8 || completed.ReceivedID != quoteID || completed.Received != 4e7 {
+		t.Fatalf("wrong amounts for completed match: %+v", completed)
+	}
+	// Fees are apportioned by quantity, ignoring the cancel match.
+	if completed.SwapFees != 2000 || completed.RedeemFees != 600 || refunded.SwapFees != 1000 {
+		t.Fatalf("wrong fees: %d, %d, %d", completed.SwapFees, completed.RedeemFees, refunded.SwapFees)
+	}
+	if !refunded.Refunded || refunded.Received != 0 {
+		t.Fatalf("refunded match not recorded as refunded: %+v", refunded)
+	}
+
+	// Generic
+	var b bytes.Buffer
+	if err := WriteMatchRecords(&b, recs, AccountingFormatGeneric, false); err != nil {
+		t.Fatalf("WriteMatchRecords (generic) error: %v", err)
+	}
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading generic CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 generic rows, got %d", len(rows))
+	}
+	if rows[1][19] != "aa" || rows[1][20] != "bb" || rows[1][21] != "cc" || rows[2][22] != "ee" {
+		t.Fatalf("wrong tx IDs in generic rows: %v, %v", rows[1], rows[2])
+	}
+	if rows[2][7] != "Refunded" {
+		t.Fatalf("wrong status for refunded match: %s", rows[2][7])
+	}
+	// The match's annotation overrides the order's.
+	if rows[1][23] != "manual" || rows[1][24] != "a b" || rows[2][23] != "oops" || rows[2][24] != "" {
+		t.Fatalf("wrong annotations in generic rows: %v, %v", rows[1], rows[2])
+	}
+
+	// Universal
+	b.Reset()
+	if err := WriteMatchRecords(&b, recs, AccountingFormatUniversal, false); err != nil {
+		t.Fatalf("WriteMatchRecords (universal) error: %v", err)
+	}
+	rows, err = csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading universal CSV: %v", err)
+	}
+	// header, trade, redeem cost, refunded match cost
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 universal rows, got %d", len(rows))
+	}
+	trade := rows[1]
+	if trade[1] != "2.00000000" || trade[2] != "DCR" || trade[3] != "0.40000000" || trade[4] != "BTC" ||
+		trade[5] != "0.00002000" || trade[6] != "DCR" || trade[11] != "cc" {
+		t.Fatalf("wrong trade row: %v", trade)
+	}
+	if rows[2][9] != "cost" || rows[2][1] != "0.00000600" || rows[2][2] != "BTC" {
+		t.Fatalf("wrong redeem cost row: %v", rows[2])
+	}
+	if rows[3][9] != "cost" || rows[3][1] != "0.00001000" || rows[3][11] != "ee" {
+		t.Fatalf("wrong refund cost row: %v", rows[3])
+	}
+
+	if err := WriteMatchRecords(&b, recs, "nope", false); err == nil {
+		t.Fatalf("no error for unknown format")
+	}
+}
+
+func TestRecoverAccount(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	// Recovery requires a new app.
+	if _, err := tCore.RecoverFromSeed(&RecoveryForm{AppPass: tPW, Seed: "abc"}); err == nil {
+		t.Fatalf("no error recovering an initialized app")
+	}
+
+	lo, _, _, _ := makeLimitOrder(rig.dc, true, dcrBtcLotSize, dcrBtcRateStep)
+	knownOID := lo.ID()
+	rig.dc.trades[knownOID] = &trackedTrade{
+		Order:    lo,
+		mktID:    tDcrBtcMktName,
+		db:       rig.db,
+		dc:       rig.dc,
+		metaData: &db.OrderMetaData{},
+	}
+	unknownOID := ordertest.RandomOrderID()
+	unknownMID := encode.RandomBytes(32)
+	rig.dc.connectResult.Store(&msgjson.ConnectResult{
+		ActiveOrderStatuses: []*msgjson.OrderStatus{
+			{ID: knownOID[:], Status: uint16(order.OrderStatusBooked)},
+			{ID: unknownOID[:], Status: uint16(order.OrderStatusBooked)},
+		},
+		ActiveMatches: []*msgjson.Match{
+			{OrderID: knownOID[:], MatchID: encode.RandomBytes(32), Status: uint8(order.MakerSwapCast)},
+			{OrderID: unknownOID[:], MatchID: unknownMID, Status: uint8(order.MakerRedeemed),
+				Side: uint8(order.Taker), Address: "counterpartyAddr"},
+		},
+	})
+
+	// The maker's swap is found by the base asset wallet, which owns the
+	// address it pays to. Our swap is found by the quote asset wallet, as it
+	// pays to the counterparty's address.
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	makerSwap := encode.RandomBytes(36)
+	tDcrWallet.auditInfo = &asset.AuditInfo{
+		Recipient:  "ourAddr",
+		Expiration: time.Now().Add(time.Hour),
+		Coin:       &tCoin{id: makerSwap},
+		Contract:   encode.RandomBytes(50),
+		SecretHash: encode.RandomBytes(32),
+	}
+	btcWallet, tBtcWallet := newTWallet(tUTXOAssetB.ID)
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+	takerSwap := encode.RandomBytes(36)
+	tBtcWallet.auditInfo = &asset.AuditInfo{
+		Recipient:  "counterpartyAddr",
+		Expiration: time.Now().Add(time.Hour),
+		Coin:       &tCoin{id: takerSwap},
+		Contract:   encode.RandomBytes(50),
+		SecretHash: encode.RandomBytes(32),
+	}
+	secret := encode.RandomBytes(32)
+	rig.ws.queueResponse(msgjson.MatchStatusRoute, func(msg *msgjson.Message, f msgFunc) error {
+		resp, _ := msgjson.NewResponse(msg.ID, []*msgjson.MatchStatusResult{{
+			MatchID:       unknownMID,
+			Status:        uint8(order.MakerRedeemed),
+			MakerContract: tDcrWallet.auditInfo.Contract,
+			MakerSwap:     makerSwap,
+			TakerContract: tBtcWallet.auditInfo.Contract,
+			TakerSwap:     takerSwap,
+			MakerRedeem:   encode.RandomBytes(36),
+			Secret:        secret,
+			Active:        true,
+		}}, nil)
+		f(resp)
+		return nil
+	})
+
+	report := new(RecoveryReport)
+	ra := tCore.recoverAccount(tDexHost, tPW, report)
+	if ra.Error != "" || !ra.Found {
+		t.Fatalf("account not recovered: %+v", ra)
+	}
+	if len(ra.Orders) != 2 || !ra.Orders[0].Known || ra.Orders[1].Known {
+		t.Fatalf("wrong recovered orders: %+v", ra.Orders)
+	}
+	if len(ra.Matches) != 2 || !ra.Matches[0].Known || ra.Matches[1].Known ||
+		ra.Matches[1].Status != order.MakerRedeemed {
+		t.Fatalf("wrong recovered matches: %+v", ra.Matches)
+	}
+	rm := ra.Matches[1]
+	if rm.MarketID != tDcrBtcMktName {
+		t.Fatalf("wrong market %q", rm.MarketID)
+	}
+	if rm.CounterSwap == nil || rm.CounterSwap.Coin.AssetID != tUTXOAssetA.ID || !rm.Redeemable {
+		t.Fatalf("counterparty swap not found redeemable: %+v", rm.CounterSwap)
+	}
+	if rm.Swap == nil || rm.Swap.Coin.AssetID != tUTXOAssetB.ID || rm.Refundable {
+		t.Fatalf("our swap not found, or refundable before its lock time: %+v", rm.Swap)
+	}
+	// One action for the unknown order, and a redeem and a later refund for
+	// the unknown match.
+	if len(report.ManualActions) != 3 {
+		t.Fatalf("expected 3 manual actions, got %v", report.ManualActions)
+	}
+
+	// Once the lock time expires, our swap can be refunded. Without the
+	// server's swap data, nothing is found.
+	tBtcWallet.contractExpired = true
+	rig.ws.queueResponse(msgjson.MatchStatusRoute, func(msg *msgjson.Message, f msgFunc) error {
+		resp, _ := msgjson.NewResponse(msg.ID, []*msgjson.MatchStatusResult{{
+			MatchID:       unknownMID,
+			Status:        uint8(order.TakerSwapCast),
+			MakerContract: tDcrWallet.auditInfo.Contract,
+			MakerSwap:     makerSwap,
+			TakerContract: tBtcWallet.auditInfo.Contract,
+			TakerSwap:     takerSwap,
+			Active:        true,
+		}}, nil)
+		f(resp)
+		return nil
+	})
+	ra = tCore.recoverAccount(tDexHost, tPW, new(RecoveryReport))
+	if rm := ra.Matches[1]; !rm.Refundable || rm.Redeemable {
+		t.Fatalf("expected refundable and not redeemable swap: %+v", rm)
+	}
+	report = new(RecoveryReport)
+	ra = tCore.recoverAccount(tDexHost, tPW, report)
+	if rm := ra.Matches[1]; rm.MarketID != "" || rm.Swap != nil || rm.CounterSwap != nil {
+		t.Fatalf("swaps found without server data: %+v", rm)
+	}
+	if len(report.ManualActions) != 2 {
+		t.Fatalf("expected 2 manual actions, got %v", report.ManualActions)
+	}
+}
+
+func TestSwapRecovery(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	dc := rig.dc
+	tCore := rig.core
+
+	mkt := dc.marketConfig(tDcrBtcMktName)
+	dcrWallet, tDcrWallet := newTWallet(mkt.Base)
+	tCore.wallets[mkt.Base] = dcrWallet
+	btcWallet, _ := newTWallet(mkt.Quote)
+	tCore.wallets[mkt.Quote] = btcWallet
+	walletSet, _, _, err := tCore.walletSet(dc, mkt.Base, mkt.Quote, true)
+	if err != nil {
+		t.Fatalf("walletSet error: %v", err)
+	}
+	tracker := makeTradeTracker(rig, walletSet, order.StandingTiF, order.OrderStatusExecuted)
+	dc.trades[tracker.ID()] = tracker
+
+	addMatch := func(side order.MatchSide, status order.MatchStatus) *matchTracker {
+		mid := ordertest.RandomMatchID()
+		match := &matchTracker{
+			MetaMatch: db.MetaMatch{
+				UserMatch: &order.UserMatch{
+					MatchID:  mid,
+					Side:     side,
+					Status:   status,
+					Quantity: dcrBtcLotSize,
+					Rate:     dcrBtcRateStep,
+					Address:  "counterparty-address",
+				},
+				MetaData: &db.MatchMetaData{
+					Proof: db.MatchProof{
+						ContractData: encode.RandomBytes(36),
+						MakerSwap:    encode.RandomBytes(36),
+						TakerSwap:    encode.RandomBytes(36),
+						SecretHash:   encode.RandomBytes(32),
+					},
+				},
+			},
+			counterSwap: &asset.AuditInfo{},
+		}
+		tracker.matches[mid] = match
+		return match
+	}
+	findTroubled := func(match *matchTracker) *TroubledMatch {
+		t.Helper()
+		troubled, err := tCore.TroubledMatches()
+		if err != nil {
+			t.Fatalf("TroubledMatches error: %v", err)
+		}
+		for _, tm := range troubled {
+			if bytes.Equal(tm.MatchID, match.MatchID[:]) {
+				return tm
+			}
+		}
+		return nil
+	}
+	hasOption := func(tm *TroubledMatch, action SwapRecoveryAction) bool {
+		for _, opt := range tm.Options {
+			if opt.Action == action {
+				return true
+			}
+		}
+		return false
+	}
+	recoverSwap := func(match *matchTracker, action SwapRecoveryAction, secret []byte) error {
+		return tCore.RecoverSwap(tPW, &SwapRecoveryForm{
+			OrderID: tracker.ID().Bytes(),
+			MatchID: match.MatchID[:],
+			Action:  action,
+			Secret:  secret,
+		})
+	}
+
+	// A healthy match is not troubled.
+	healthy := addMatch(order.Maker, order.MakerSwapCast)
+	if findTroubled(healthy) != nil {
+		t.Fatalf("healthy match listed as troubled")
+	}
+	if err := recoverSwap(healthy, SwapRecoveryAbandon, nil); err == nil {
+		t.Fatalf("no error abandoning a healthy match")
+	}
+
+	// An expired contract can be refunded.
+	tDcrWallet.contractExpired = true
+	tDcrWallet.refundCoin = encode.RandomBytes(36)
+	tm := findTroubled(healthy)
+	if tm == nil {
+		t.Fatalf("expired match not listed as troubled")
+	}
+	if !hasOption(tm, SwapRecoveryRefund) || !hasOption(tm, SwapRecoveryAbandon) || hasOption(tm, SwapRecoveryRedeem) {
+		t.Fatalf("wrong options for expired maker swap: %+v", tm.Options)
+	}
+	if err := recoverSwap(healthy, SwapRecoveryRedeem, nil); err == nil {
+		t.Fatalf("no error for unavailable action")
+	}
+	if err := recoverSwap(healthy, SwapRecoveryRefund, nil); err != nil {
+		t.Fatalf("refund error: %v", err)
+	}
+	if !bytes.Equal(healthy.MetaData.Proof.RefundCoin, tDcrWallet.refundCoin) {
+		t.Fatalf("refund coin not recorded")
+	}
+	if findTroubled(healthy) != nil {
+		t.Fatalf("refunded match still troubled")
+	}
+	tDcrWallet.contractExpired = false
+
+	// A taker that hasn't found the maker's redemption must provide the
+	// secret to redeem.
+	taker := addMatch(order.Taker, order.TakerSwapCast)
+	taker.swapErr = errors.New("test error")
+	taker.counterSwap = &asset.AuditInfo{}
+	tm = findTroubled(taker)
+	if tm == nil {
+		t.Fatalf("taker match with swap error not troubled")
+	}
+	// swapErr precludes redeeming.
+	if hasOption(tm, SwapRecoveryRedeem) {
+		t.Fatalf("redeem option for match with swap error")
+	}
+	taker.swapErr = nil
+	taker.redeemErrCount = 1
+	tm = findTroubled(taker)
+	if tm == nil || !hasOption(tm, SwapRecoveryRedeem) || !tm.Options[0].NeedsSecret {
+		t.Fatalf("no redeem option needing secret")
+	}
+	if err := recoverSwap(taker, SwapRecoveryRedeem, nil); err == nil {
+		t.Fatalf("no error for missing secret")
+	}
+
+	// Abandon. The match keeps its status.
+	status := taker.Status
+	if err := recoverSwap(taker, SwapRecoveryAbandon, nil); err != nil {
+		t.Fatalf("abandon error: %v", err)
+	}
+	if taker.Status != status || !taker.MetaData.Proof.Abandoned || !errors.Is(taker.refundErr, errMatchAbandoned) {
+		t.Fatalf("match not abandoned")
+	}
+	if taker.MetaData.Proof.IsRevoked() {
+		t.Fatalf("abandoned match revoked")
+	}
+	if db.MatchIsActive(taker.UserMatch, &taker.MetaData.Proof) {
+		t.Fatalf("abandoned match still active")
+	}
+	if findTroubled(taker) != nil {
+		t.Fatalf("abandoned match still troubled")
+	}
+}
+
+func TestAccelerateOrderFeeBumper(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	dc := rig.dc
+
+	// The swap wallet is a FeeBumper, so acceleration replaces the swaps.
+	mkt := dc.marketConfig(tDcrBtcMktName)
+	dcrWallet, tDcrBumper := newTFeeBumper(mkt.Base)
+	tCore.wallets[mkt.Base] = dcrWallet
+	btcWallet, _ := newTWallet(mkt.Quote)
+	tCore.wallets[mkt.Quote] = btcWallet
+	walletSet, _, _, err := tCore.walletSet(dc, mkt.Base, mkt.Quote, true)
+	if err != nil {
+		t.Fatalf("walletSet error: %v", err)
+	}
+	tracker := makeTradeTracker(rig, walletSet, order.StandingTiF, order.OrderStatusExecuted)
+	dc.trades[tracker.ID()] = tracker
+	oid := tracker.ID()
+
+	// No swaps yet.
+	if _, err := tCore.AccelerateOrder(tPW, oid[:], 50); err == nil {
+		t.Fatalf("no error accelerating an order without swaps")
+	}
+
+	makerSwap := encode.RandomBytes(32)
+	takerRedeem := encode.RandomBytes(32)
+	addMatch := func(side order.MatchSide, status order.MatchStatus, proof db.MatchProof) {
+		mid := ordertest.RandomMatchID()
+		tracker.matches[mid] = &matchTracker{
+			MetaMatch: db.MetaMatch{
+				UserMatch: &order.UserMatch{
+					MatchID: mid,
+					Side:    side,
+					Status:  status,
+					Address: "counterparty-address",
+				},
+				MetaData: &db.MatchMetaData{Proof: proof},
+			},
+		}
+	}
+	addMatch(order.Maker, order.MakerSwapCast, db.MatchProof{MakerSwap: makerSwap})
+	// A taker match that has redeemed. The redeem wallet is not a FeeBumper,
+	// so its redemption is not included.
+	addMatch(order.Taker, order.MakerRedeemed, db.MatchProof{TakerSwap: encode.RandomBytes(32), TakerRedeem: takerRedeem})
+	// Refunded and confirmed matches are skipped.
+	addMatch(order.Maker, order.MakerSwapCast, db.MatchProof{MakerSwap: encode.RandomBytes(32), RefundCoin: encode.RandomBytes(32)})
+	addMatch(order.Maker, order.MatchConfirmed, db.MatchProof{MakerSwap: encode.RandomBytes(32)})
+
+	tDcrBumper.replacements = []string{"tx1", "tx2"}
+	txIDs, err := tCore.AccelerateOrder(tPW, oid[:], 50)
+	if err != nil {
+		t.Fatalf("AccelerateOrder error: %v", err)
+	}
+	if txIDs != "tx1, tx2" {
+		t.Fatalf("wrong tx IDs %q", txIDs)
+	}
+	if tDcrBumper.bumpedRate != 50 {
+		t.Fatalf("wrong fee rate %d", tDcrBumper.bumpedRate)
+	}
+	if len(tDcrBumper.bumpedCoins) != 2 {
+		t.Fatalf("expected 2 swap coins, got %d", len(tDcrBumper.bumpedCoins))
+	}
+	var foundMakerSwap bool
+	for _, coinID := range tDcrBumper.bumpedCoins {
+		if bytes.Equal(coinID, takerRedeem) {
+			t.Fatalf("redemption bumped by the swap wallet")
+		}
+		foundMakerSwap = foundMakerSwap || bytes.Equal(coinID, makerSwap)
+	}
+	if !foundMakerSwap {
+		t.Fatalf("maker swap not bumped")
+	}
+
+	// Nothing replaced.
+	tDcrBumper.replacements = nil
+	if _, err := tCore.AccelerateOrder(tPW, oid[:], 50); err == nil {
+		t.Fatalf("no error when nothing was replaced")
+	}
+	// Wallet error.
+	tDcrBumper.bumpErr = errors.New("test error")
+	if _, err := tCore.AccelerateOrder(tPW, oid[:], 50); err == nil {
+		t.Fatalf("no error for wallet error")
+	}
+
+	tDcrBumper.bumpEstimate = 1234
+	fees, err := tCore.AccelerationEstimate(oid[:], 50)
+	if err != nil {
+		t.Fatalf("AccelerationEstimate error: %v", err)
+	}
+	if fees != 1234 {
+		t.Fatalf("wrong estimate %d", fees)
+	}
+
+	tDcrBumper.currentRate = 20
+	tDcrBumper.earlyBump = &asset.EarlyAcceleration{TimePast: 30}
+	pre, err := tCore.PreAccelerateOrder(oid[:])
+	if err != nil {
+		t.Fatalf("PreAccelerateOrder error: %v", err)
+	}
+	if pre.SwapRate != 20 || pre.EarlyAcceleration == nil || pre.EarlyAcceleration.TimePast != 30 {
+		t.Fatalf("wrong PreAccelerate %+v", pre)
+	}
+	tDcrBumper.preBumpErr = errors.New("test error")
+	if _, err := tCore.PreAccelerateOrder(oid[:]); err == nil {
+		t.Fatalf("no error for PreBumpFees error")
+	}
+}
+
+func TestIndicators(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	form := &IndicatorsForm{
+		Host:              tDexHost,
+		Base:              tUTXOAssetA.ID,
+		Quote:             tUTXOAssetB.ID,
+		BinSize:           "1h",
+		SMA:               []int{2},
+		EMA:               []int{2, 3},
+		Bollinger:         &BollingerForm{Period: 2, StdDevs: 2},
+		VolumeProfileBins: 4,
+	}
+
+	// Bad forms.
+	for _, badForm := range []*IndicatorsForm{
+		{Host: tDexHost, BinSize: "1h", SMA: []int{0}},
+		{Host: tDexHost, BinSize: "1h", EMA: []int{maxIndicatorPeriod + 1}},
+		{Host: tDexHost, BinSize: "1h", Bollinger: &BollingerForm{Period: 2}},
+		{Host: tDexHost, BinSize: "1h", VolumeProfileBins: -1},
+		{Host: tDexHost, BinSize: "1 hour"},
+	} {
+		if _, err := tCore.Indicators(badForm); err == nil {
+			t.Fatalf("no error for bad form %+v", badForm)
+		}
+	}
+
+	// The book is synced to fetch the candles.
+	rig.ws.queueResponse(msgjson.OrderBookRoute, func(msg *msgjson.Message, f msgFunc) error {
+		resp, _ := msgjson.NewResponse(msg.ID, &msgjson.OrderBook{
+			Seq:      1,
+			MarketID: tDcrBtcMktName,
+		}, nil)
+		f(resp)
+		return nil
+	})
+	const hour = 3_600_000
+	rig.ws.queueResponse(msgjson.CandlesRoute, func(msg *msgjson.Message, f msgFunc) error {
+		resp, _ := msgjson.NewResponse(msg.ID, &msgjson.WireCandles{
+			StartStamps:  []uint64{hour, 2 * hour, 3 * hour},
+			EndStamps:    []uint64{2 * hour, 3 * hour, 4 * hour},
+			MatchVolumes: []uint64{10, 20, 30},
+			QuoteVolumes: []uint64{10, 20, 30},
+			HighRates:    []uint64{12, 22, 32},
+			LowRates:     []uint64{8, 18, 28},
+			StartRates:   []uint64{9, 19, 29},
+			EndRates:     []uint64{10, 20, 30},
+		}, nil)
+		f(resp)
+		return nil
+	})
+
+	series, err := tCore.Indicators(form)
+	if err != nil {
+		t.Fatalf("Indicators error: %v", err)
+	}
+	if len(series.Candles) != 3 {
+		t.Fatalf("expected 3 candles, got %d", len(series.Candles))
+	}
+	if len(series.SMA[2]) != 2 || series.SMA[2][0].Value != 15 {
+		t.Fatalf("wrong SMA series %+v", series.SMA[2])
+	}
+	if len(series.EMA[2]) != 2 || len(series.EMA[3]) != 1 {
+		t.Fatalf("wrong EMA series %+v", series.EMA)
+	}
+	if len(series.Bollinger) != 2 || series.Bollinger[0].Upper != 25 || series.Bollinger[0].Lower != 5 {
+		t.Fatalf("wrong Bollinger series %+v", series.Bollinger)
+	}
+	if len(series.VolumeProfile) != 4 {
+		t.Fatalf("wrong volume profile %+v", series.VolumeProfile)
+	}
+
+	// The candle cache is now active, so the candles are not requested again.
+	if _, err := tCore.Indicators(form); err != nil {
+		t.Fatalf("Indicators error with synced candles: %v", err)
+	}
+
+	// Unknown bin size.
+	form.BinSize = "5m"
+	if _, err := tCore.Indicators(form); err == nil {
+		t.Fatalf("no error for unknown bin size")
+	}
+}
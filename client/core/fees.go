@@ -0,0 +1,120 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"sort"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+// feeTxTypes are the names of the transaction types in a FeeReport. Receives
+// are not included, since the sender pays the fees.
+var feeTxTypes = map[asset.TransactionType]string{
+	asset.Unknown:             "unknown",
+	asset.Send:                "send",
+	asset.Swap:                "swap",
+	asset.Redeem:              "redeem",
+	asset.Refund:              "refund",
+	asset.Split:               "split",
+	asset.CreateBond:          "createBond",
+	asset.RedeemBond:          "redeemBond",
+	asset.ApproveToken:        "approveToken",
+	asset.Acceleration:        "acceleration",
+	asset.SelfSend:            "selfSend",
+	asset.RevokeTokenApproval: "revokeTokenApproval",
+	asset.TicketPurchase:      "ticketPurchase",
+	asset.TicketVote:          "ticketVote",
+	asset.TicketRevocation:    "ticketRevocation",
+	asset.SwapOrSend:          "swapOrSend",
+	asset.Mix:                 "mix",
+}
+
+// FeeReport reports the on-chain fees paid for the transactions in the wallets'
+// transaction histories between from (inclusive) and to (exclusive), by the
+// asset the fees were paid in and the type of transaction. A zero from or to
+// is unbounded. Transactions that are not mined yet are counted as of now.
+// Wallets that are not connected or don't keep a transaction history are
+// listed as unavailable.
+func (c *Core) FeeReport(from, to time.Time) (*FeeReport, error) {
+	report := &FeeReport{
+		Unavailable: make([]uint32, 0),
+	}
+	if !from.IsZero() {
+		report.From = uint64(from.UnixMilli())
+	}
+	if !to.IsZero() {
+		report.To = uint64(to.UnixMilli())
+	}
+
+	now := time.Now()
+	assetFees := make(map[uint32]*AssetFees)
+	// A transaction may be in the histories of both a token wallet and its
+	// parent, so transactions are only counted once per fee asset.
+	counted := make(map[uint32]map[string]bool)
+	for _, w := range c.xcWallets() {
+		txs, err := w.TxHistory(0, nil, false)
+		if err != nil {
+			c.log.Debugf("No transaction history for %s fee report: %v", unbip(w.AssetID), err)
+			report.Unavailable = append(report.Unavailable, w.AssetID)
+			continue
+		}
+		feeAssetID := w.AssetID
+		if w.parent != nil {
+			feeAssetID = w.parent.AssetID
+		}
+		if counted[feeAssetID] == nil {
+			counted[feeAssetID] = make(map[string]bool)
+		}
+		for _, tx := range txs {
+			if tx.Fees == 0 || tx.Type == asset.Receive || counted[feeAssetID][tx.ID] {
+				continue
+			}
+			stamp := now
+			if tx.Timestamp > 0 {
+				stamp = time.Unix(int64(tx.Timestamp), 0)
+			}
+			if (!from.IsZero() && stamp.Before(from)) || (!to.IsZero() && !stamp.Before(to)) {
+				continue
+			}
+			counted[feeAssetID][tx.ID] = true
+
+			fees := assetFees[feeAssetID]
+			if fees == nil {
+				fees = &AssetFees{
+					AssetID: feeAssetID,
+					Symbol:  unbip(feeAssetID),
+					ByType:  make(map[string]*TxTypeFees),
+				}
+				assetFees[feeAssetID] = fees
+			}
+			fees.Total += tx.Fees
+			fees.Count++
+			typeName, found := feeTxTypes[tx.Type]
+			if !found {
+				typeName = feeTxTypes[asset.Unknown]
+			}
+			typeFees := fees.ByType[typeName]
+			if typeFees == nil {
+				typeFees = new(TxTypeFees)
+				fees.ByType[typeName] = typeFees
+			}
+			typeFees.Total += tx.Fees
+			typeFees.Count++
+		}
+	}
+
+	report.Assets = make([]*AssetFees, 0, len(assetFees))
+	for _, fees := range assetFees {
+		report.Assets = append(report.Assets, fees)
+	}
+	sort.Slice(report.Assets, func(i, j int) bool {
+		return report.Assets[i].AssetID < report.Assets[j].AssetID
+	})
+	sort.Slice(report.Unavailable, func(i, j int) bool {
+		return report.Unavailable[i] < report.Unavailable[j]
+	})
+	return report, nil
+}
@@ -1,9 +1,18 @@
 package core
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"decred.org/dcrdex/client/intl"
+	"decred.org/dcrdex/dex"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
@@ -81,6 +90,10 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Trade limit exceeded"},
 		template: intl.Translation{T: "Order quantity exceeds current trade limit on %s", Notes: "args: [host]"},
 	},
+	TopicEpochProofMismatch: {
+		subject:  intl.Translation{T: "Epoch proof mismatch"},
+		template: intl.Translation{T: "Order %s: the matching inputs of epoch %d from %s could not be verified: %v", Notes: "args: [token, epoch, host, error]"},
+	},
 	TopicOrderLoadFailure: {
 		subject:  intl.Translation{T: "Order load failure"},
 		template: intl.Translation{T: "Some orders failed to load from the database: %v", Notes: "args: [error]"},
@@ -274,6 +287,14 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Internet Connectivity"},
 		template: intl.Translation{T: "Your internet connection to %s is unstable, check your internet connection", Notes: "args: [host]"},
 	},
+	TopicDEXSlowServer: {
+		subject:  intl.Translation{T: "Server responding slowly"},
+		template: intl.Translation{T: "Responses from %s are slow. Average round trip: %v", Notes: "args: [host, duration]"},
+	},
+	TopicNotificationRepeated: {
+		subject:  intl.Translation{T: "Repeated notification"},
+		template: intl.Translation{T: "%s (repeated %d more times)", Notes: "args: [details, count]"},
+	},
 	TopicPenalized: {
 		subject:  intl.Translation{T: "Server has penalized you"},
 		template: intl.Translation{T: "Penalty from DEX at %s\nlast broken rule: %s\ntime: %v\ndetails:\n\"%s\"\n", Notes: "args: [host, rule, time, details]"},
@@ -286,6 +307,14 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Back up your new application seed"},
 		template: intl.Translation{T: "The client has been upgraded to use an application seed. Back up the seed now in the settings view."},
 	},
+	TopicBackupFailed: {
+		subject:  intl.Translation{T: "Encrypted backup failed"},
+		template: intl.Translation{T: "Unable to create an encrypted backup of the database: %v", Notes: "args: [error]"},
+	},
+	TopicTradingLimitApproaching: {
+		subject:  intl.Translation{T: "Approaching trading limit"},
+		template: intl.Translation{T: "%s: %d of %d parcels in use. Orders exceeding the limit will be rejected by the server.", Notes: "args: [host, used parcels, parcel limit]"},
+	},
 	TopicDEXNotification: {
 		subject:  intl.Translation{T: "Message from DEX"},
 		template: intl.Translation{T: "%s: %s", Notes: "args: [host, msg]"},
@@ -600,12 +629,443 @@ var ptBR = map[Topic]*translation{
 	},
 }
 
-// The language string key *must* parse with language.Parse.
-var locales = map[string]map[Topic]*translation{
-	originLang: originLocale,
+// deDE is the German translations.
+var deDE = map[Topic]*translation{
+	TopicAccountRegistered: {
+		subject:  intl.Translation{T: "Konto registriert"},
+		template: intl.Translation{T: "Sie können jetzt auf %s handeln"},
+	},
+	TopicFeePaymentInProgress: {
+		subject:  intl.Translation{T: "Gebührenzahlung läuft"},
+		template: intl.Translation{T: "Warte auf %d Bestätigungen, bevor auf %s gehandelt werden kann"},
+	},
+	TopicWalletConnectionWarning: {
+		subject:  intl.Translation{T: "Warnung zur Wallet-Verbindung"},
+		template: intl.Translation{T: "Unvollständige Registrierung für %s erkannt, aber die Verbindung zur Decred-Wallet ist fehlgeschlagen"},
+	},
+	TopicWalletUnlockError: {
+		subject:  intl.Translation{T: "Fehler beim Entsperren der Wallet"},
+		template: intl.Translation{T: "Mit der Wallet verbunden, um die Registrierung bei %s abzuschließen, aber das Entsperren ist fehlgeschlagen: %v"},
+	},
+	TopicSendError: {
+		subject:  intl.Translation{T: "Fehler beim Senden"},
+		template: intl.Translation{T: "Fehler beim Senden von %s: %v"},
+	},
+	TopicSendSuccess: {
+		subject:  intl.Translation{T: "Erfolgreich gesendet"},
+		template: intl.Translation{T: "Das Senden von %s %s an %s wurde erfolgreich abgeschlossen. Tx-ID = %s"},
+	},
+	TopicAsyncOrderFailure: {
+		subject:  intl.Translation{T: "Fehler bei laufendem Auftrag"},
+		template: intl.Translation{T: "Laufender Auftrag mit ID %v fehlgeschlagen: %v"},
+	},
+	TopicOrderLoadFailure: {
+		subject:  intl.Translation{T: "Fehler beim Laden der Aufträge"},
+		template: intl.Translation{T: "Einige Aufträge konnten nicht aus der Datenbank geladen werden: %v"},
+	},
+	TopicBuyOrderPlaced: {
+		subject:  intl.Translation{T: "Auftrag platziert"},
+		template: intl.Translation{T: "Kaufe %s %s, Kurs = %s (%s)"},
+	},
+	TopicSellOrderPlaced: {
+		subject:  intl.Translation{T: "Auftrag platziert"},
+		template: intl.Translation{T: "Verkaufe %s %s, Kurs = %s (%s)"},
+	},
+	TopicBuyOrderCanceled: {
+		subject:  intl.Translation{T: "Auftrag storniert"},
+		template: intl.Translation{T: "Kaufauftrag für %s-%s bei %s wurde storniert (%s)"},
+	},
+	TopicSellOrderCanceled: {
+		subject:  intl.Translation{T: "Auftrag storniert"},
+		template: intl.Translation{T: "Verkaufsauftrag für %s-%s bei %s wurde storniert (%s)"},
+	},
+	TopicBuyMatchesMade: {
+		subject:  intl.Translation{T: "Matches erstellt"},
+		template: intl.Translation{T: "Kaufauftrag für %s-%s zu %.1f%% ausgeführt (%s)"},
+	},
+	TopicSellMatchesMade: {
+		subject:  intl.Translation{T: "Matches erstellt"},
+		template: intl.Translation{T: "Verkaufsauftrag für %s-%s zu %.1f%% ausgeführt (%s)"},
+	},
+	TopicSwapsInitiated: {
+		subject:  intl.Translation{T: "Swaps eingeleitet"},
+		template: intl.Translation{T: "Swaps im Wert von %s %s für Auftrag %s gesendet"},
+	},
+	TopicMatchComplete: {
+		subject:  intl.Translation{T: "Match abgeschlossen"},
+		template: intl.Translation{T: "%s %s für Auftrag %s eingelöst"},
+	},
+	TopicRefundFailure: {
+		subject:  intl.Translation{T: "Rückerstattung fehlgeschlagen"},
+		template: intl.Translation{T: "%s %s für Auftrag %s mit einigen Fehlern zurückerstattet"},
+	},
+	TopicMatchesRefunded: {
+		subject:  intl.Translation{T: "Matches zurückerstattet"},
+		template: intl.Translation{T: "%s %s für Auftrag %s zurückerstattet"},
+	},
+	TopicOrderRevoked: {
+		subject:  intl.Translation{T: "Auftrag widerrufen"},
+		template: intl.Translation{T: "Auftrag %s im Markt %s bei %s wurde vom Server widerrufen"},
+	},
+	TopicDEXConnected: {
+		subject:  intl.Translation{T: "Server verbunden"},
+		template: intl.Translation{T: "%s"},
+	},
+	TopicDEXDisconnected: {
+		subject:  intl.Translation{T: "Server getrennt"},
+		template: intl.Translation{T: "%s"},
+	},
+	TopicDEXSlowServer: {
+		subject:  intl.Translation{T: "Server antwortet langsam"},
+		template: intl.Translation{T: "Antworten von %s sind langsam. Durchschnittliche Umlaufzeit: %v"},
+	},
+	TopicMarketSuspended: {
+		subject:  intl.Translation{T: "Markt ausgesetzt"},
+		template: intl.Translation{T: "Der Handel im Markt %s bei %s ist jetzt ausgesetzt."},
+	},
+	TopicMarketResumed: {
+		subject:  intl.Translation{T: "Markt fortgesetzt"},
+		template: intl.Translation{T: "Der Markt %s bei %s hat den Handel in Epoche %d wieder aufgenommen"},
+	},
+	TopicUpgradeNeeded: {
+		subject:  intl.Translation{T: "Aktualisierung erforderlich"},
+		template: intl.Translation{T: "Möglicherweise müssen Sie Ihren Client aktualisieren, um auf %s zu handeln"},
+	},
+	TopicSeedNeedsSaving: {
+		subject:  intl.Translation{T: "Vergessen Sie nicht, Ihren Anwendungs-Seed zu sichern"},
+		template: intl.Translation{T: "Ein neuer Anwendungs-Seed wurde erstellt. Erstellen Sie jetzt eine Sicherung in den Einstellungen."},
+	},
+	TopicBondConfirmed: {
+		subject:  intl.Translation{T: "Bond bestätigt"},
+		template: intl.Translation{T: "Neue Stufe = %d (Ziel = %d)."},
+	},
+	TopicBondExpired: {
+		subject:  intl.Translation{T: "Bond abgelaufen"},
+		template: intl.Translation{T: "Neue Stufe = %d (Ziel = %d)."},
+	},
+	TopicRedemptionConfirmed: {
+		subject:  intl.Translation{T: "Einlösung bestätigt"},
+		template: intl.Translation{T: "Ihre Einlösung für Match %s in Auftrag %s wurde bestätigt"},
+	},
+}
+
+// zhCN is the Simplified Chinese translations.
+var zhCN = map[Topic]*translation{
+	TopicAccountRegistered: {
+		subject:  intl.Translation{T: "账户已注册"},
+		template: intl.Translation{T: "您现在可以在 %s 交易"},
+	},
+	TopicFeePaymentInProgress: {
+		subject:  intl.Translation{T: "费用支付中"},
+		template: intl.Translation{T: "在 %[2]s 交易前等待 %[1]d 次确认"},
+	},
+	TopicWalletConnectionWarning: {
+		subject:  intl.Translation{T: "钱包连接警告"},
+		template: intl.Translation{T: "检测到 %s 的注册不完整，但无法连接到 Decred 钱包"},
+	},
+	TopicWalletUnlockError: {
+		subject:  intl.Translation{T: "钱包解锁错误"},
+		template: intl.Translation{T: "已连接到钱包以完成在 %s 的注册，但解锁失败：%v"},
+	},
+	TopicSendError: {
+		subject:  intl.Translation{T: "发送错误"},
+		template: intl.Translation{T: "发送 %s 时出错：%v"},
+	},
+	TopicSendSuccess: {
+		subject:  intl.Translation{T: "发送成功"},
+		template: intl.Translation{T: "已成功将 %s %s 发送至 %s。交易 ID = %s"},
+	},
+	TopicAsyncOrderFailure: {
+		subject:  intl.Translation{T: "进行中订单错误"},
+		template: intl.Translation{T: "ID 为 %v 的进行中订单失败：%v"},
+	},
+	TopicOrderLoadFailure: {
+		subject:  intl.Translation{T: "订单加载失败"},
+		template: intl.Translation{T: "部分订单无法从数据库加载：%v"},
+	},
+	TopicBuyOrderPlaced: {
+		subject:  intl.Translation{T: "订单已下达"},
+		template: intl.Translation{T: "买入 %s %s，价格 = %s（%s）"},
+	},
+	TopicSellOrderPlaced: {
+		subject:  intl.Translation{T: "订单已下达"},
+		template: intl.Translation{T: "卖出 %s %s，价格 = %s（%s）"},
+	},
+	TopicBuyOrderCanceled: {
+		subject:  intl.Translation{T: "订单已取消"},
+		template: intl.Translation{T: "%[3]s 上的 %[1]s-%[2]s 买单已取消（%[4]s）"},
+	},
+	TopicSellOrderCanceled: {
+		subject:  intl.Translation{T: "订单已取消"},
+		template: intl.Translation{T: "%[3]s 上的 %[1]s-%[2]s 卖单已取消（%[4]s）"},
+	},
+	TopicBuyMatchesMade: {
+		subject:  intl.Translation{T: "已撮合"},
+		template: intl.Translation{T: "%s-%s 买单已成交 %.1f%%（%s）"},
+	},
+	TopicSellMatchesMade: {
+		subject:  intl.Translation{T: "已撮合"},
+		template: intl.Translation{T: "%s-%s 卖单已成交 %.1f%%（%s）"},
+	},
+	TopicSwapsInitiated: {
+		subject:  intl.Translation{T: "交换已发起"},
+		template: intl.Translation{T: "已为订单 %[3]s 发送价值 %[1]s %[2]s 的交换"},
+	},
+	TopicMatchComplete: {
+		subject:  intl.Translation{T: "撮合完成"},
+		template: intl.Translation{T: "已为订单 %[3]s 赎回 %[1]s %[2]s"},
+	},
+	TopicRefundFailure: {
+		subject:  intl.Translation{T: "退款失败"},
+		template: intl.Translation{T: "已为订单 %[3]s 退还 %[1]s %[2]s，但出现了一些错误"},
+	},
+	TopicMatchesRefunded: {
+		subject:  intl.Translation{T: "撮合已退款"},
+		template: intl.Translation{T: "已为订单 %[3]s 退还 %[1]s %[2]s"},
+	},
+	TopicOrderRevoked: {
+		subject:  intl.Translation{T: "订单已撤销"},
+		template: intl.Translation{T: "%[3]s 上市场 %[2]s 中的订单 %[1]s 已被服务器撤销"},
+	},
+	TopicDEXConnected: {
+		subject:  intl.Translation{T: "服务器已连接"},
+		template: intl.Translation{T: "%s"},
+	},
+	TopicDEXDisconnected: {
+		subject:  intl.Translation{T: "服务器已断开"},
+		template: intl.Translation{T: "%s"},
+	},
+	TopicDEXSlowServer: {
+		subject:  intl.Translation{T: "服务器响应缓慢"},
+		template: intl.Translation{T: "%s 的响应缓慢。平均往返时间：%v"},
+	},
+	TopicMarketSuspended: {
+		subject:  intl.Translation{T: "市场已暂停"},
+		template: intl.Translation{T: "%[2]s 上的市场 %[1]s 现已暂停交易。"},
+	},
+	TopicMarketResumed: {
+		subject:  intl.Translation{T: "市场已恢复"},
+		template: intl.Translation{T: "%[2]s 上的市场 %[1]s 已在第 %[3]d 个周期恢复交易"},
+	},
+	TopicUpgradeNeeded: {
+		subject:  intl.Translation{T: "需要升级"},
+		template: intl.Translation{T: "您可能需要更新客户端才能在 %s 交易"},
+	},
+	TopicSeedNeedsSaving: {
+		subject:  intl.Translation{T: "别忘了备份您的应用程序种子"},
+		template: intl.Translation{T: "已创建新的应用程序种子。请立即在设置页面中备份。"},
+	},
+	TopicBondConfirmed: {
+		subject:  intl.Translation{T: "保证金已确认"},
+		template: intl.Translation{T: "新等级 = %d（目标 = %d）。"},
+	},
+	TopicBondExpired: {
+		subject:  intl.Translation{T: "保证金已过期"},
+		template: intl.Translation{T: "新等级 = %d（目标 = %d）。"},
+	},
+	TopicRedemptionConfirmed: {
+		subject:  intl.Translation{T: "赎回已确认"},
+		template: intl.Translation{T: "您在订单 %[2]s 中撮合 %[1]s 的赎回已确认"},
+	},
+}
+
+// The language string key *must* parse with language.Parse. Translations loaded
+// from locale files with loadLocales are added at runtime, so locales must
+// only be accessed with localesMtx locked.
+var (
+	localesMtx sync.RWMutex
+	locales    = map[string]map[Topic]*translation{
+		originLang: originLocale,
+		"de-DE":    deDE,
+		"zh-CN":    zhCN,
+	}
+)
+
+// localeTranslations returns the translations for the language.
+func localeTranslations(lang string) (map[Topic]*translation, bool) {
+	localesMtx.RLock()
+	defer localesMtx.RUnlock()
+	m, found := locales[lang]
+	return m, found
+}
+
+// localeLangs returns the languages with translations, sorted.
+func localeLangs() []string {
+	localesMtx.RLock()
+	defer localesMtx.RUnlock()
+	langs := make([]string, 0, len(locales))
+	for lang := range locales {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// localeFileTranslation is a translation in a locale file.
+type localeFileTranslation struct {
+	Subject  string `json:"subject"`
+	Template string `json:"template"`
+}
+
+// loadLocales loads notification translations from the JSON locale files in
+// dir, so that translations can be added and tested without rebuilding. Each
+// file is named for its language, e.g. pt-BR.json, and maps topics to a subject
+// and template. Translations from a file replace any built-in translations for
+// the same language and topic. Invalid files and translations are logged and
+// skipped. A missing dir is not an error. The languages loaded are returned.
+func loadLocales(dir string, log dex.Logger) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var loaded []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		lang := strings.TrimSuffix(entry.Name(), ".json")
+		langTag, err := language.Parse(lang)
+		if err != nil {
+			log.Errorf("Skipping locale file %s: invalid language: %v", entry.Name(), err)
+			continue
+		}
+		lang = langTag.String()
+		if lang == originLang {
+			log.Errorf("Skipping locale file %s: %s translations cannot be replaced", entry.Name(), originLang)
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.Errorf("Error reading locale file %s: %v", entry.Name(), err)
+			continue
+		}
+		fileTranslations, errs := parseLocaleFile(b)
+		for _, err := range errs {
+			log.Errorf("Locale file %s: %v", entry.Name(), err)
+		}
+		if len(fileTranslations) == 0 {
+			continue
+		}
+		for topic, t := range fileTranslations {
+			if err := message.SetString(langTag, string(topic), t.template.T); err != nil {
+				return nil, fmt.Errorf("SetString(%s): %v", lang, err)
+			}
+		}
+		localesMtx.Lock()
+		m := make(map[Topic]*translation, len(locales[lang])+len(fileTranslations))
+		for topic, t := range locales[lang] {
+			m[topic] = t
+		}
+		for topic, t := range fileTranslations {
+			m[topic] = t
+		}
+		locales[lang] = m
+		localesMtx.Unlock()
+		log.Infof("Loaded %d notification translations for %s from %s", len(fileTranslations), lang, entry.Name())
+		loaded = append(loaded, lang)
+	}
+	return loaded, nil
+}
+
+// parseLocaleFile parses the translations in a locale file. Translations for
+// unknown topics and translations with format arguments that do not match the
+// originLocale template are omitted, and an error is returned for each.
+func parseLocaleFile(b []byte) (map[Topic]*translation, []error) {
+	var fileTranslations map[Topic]*localeFileTranslation
+	if err := json.Unmarshal(b, &fileTranslations); err != nil {
+		return nil, []error{fmt.Errorf("error decoding translations: %w", err)}
+	}
+	var errs []error
+	m := make(map[Topic]*translation, len(fileTranslations))
+	for topic, t := range fileTranslations {
+		originTrans, found := originLocale[topic]
+		if !found {
+			errs = append(errs, fmt.Errorf("unknown topic %q", topic))
+			continue
+		}
+		if t == nil || t.Subject == "" || t.Template == "" {
+			errs = append(errs, fmt.Errorf("topic %q: subject and template are required", topic))
+			continue
+		}
+		if err := checkFormatArgs(originTrans.template.T, t.Template); err != nil {
+			errs = append(errs, fmt.Errorf("topic %q: %w", topic, err))
+			continue
+		}
+		m[topic] = &translation{
+			subject:  intl.Translation{T: t.Subject},
+			template: intl.Translation{T: t.Template},
+		}
+	}
+	return m, errs
+}
+
+// checkFormatArgs checks that a translated template uses the same format
+// arguments as the origin template. The verbs must match, except that %v may
+// be used for any argument.
+func checkFormatArgs(origin, translated string) error {
+	originArgs, translatedArgs := formatArgs(origin), formatArgs(translated)
+	if len(originArgs) != len(translatedArgs) {
+		return fmt.Errorf("expected %d format arguments, found %d", len(originArgs), len(translatedArgs))
+	}
+	for argNum, verb := range originArgs {
+		v, found := translatedArgs[argNum]
+		if !found {
+			return fmt.Errorf("format argument %d not used", argNum)
+		}
+		if v != verb && v != 'v' && verb != 'v' {
+			return fmt.Errorf("wrong verb for format argument %d. expected %%%c, found %%%c", argNum, verb, v)
+		}
+	}
+	return nil
+}
+
+// formatArgs parses the format verbs in a template, returning the verb for
+// each argument number, starting at 1. Explicit argument indexes, e.g. %[2]s,
+// are supported.
+func formatArgs(s string) map[int]byte {
+	args := make(map[int]byte)
+	argNum := 1
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(s) {
+			c := s[i]
+			if c == '[' {
+				end := strings.IndexByte(s[i:], ']')
+				if end < 0 {
+					return args
+				}
+				if n, err := strconv.Atoi(s[i+1 : i+end]); err == nil {
+					argNum = n
+				}
+				i += end + 1
+				continue
+			}
+			if !strings.ContainsRune("+-# 0123456789.*", rune(c)) {
+				break
+			}
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		if s[i] == '%' {
+			continue
+		}
+		args[argNum] = s[i]
+		argNum++
+	}
+	return args
 }
 
 func init() {
+	localesMtx.RLock()
+	defer localesMtx.RUnlock()
 	for lang, translations := range locales {
 		langtag, err := language.Parse(lang)
 		if err != nil {
@@ -625,6 +1085,8 @@ func init() {
 func RegisterTranslations() {
 	const callerID = "notifications"
 
+	localesMtx.RLock()
+	defer localesMtx.RUnlock()
 	for lang, m := range locales {
 		r := intl.NewRegistrar(callerID, lang, len(m)*2)
 		for topic, t := range m {
@@ -634,14 +1096,189 @@ func RegisterTranslations() {
 	}
 }
 
-// CheckTopicLangs is used to report missing notification translations.
-func CheckTopicLangs() (missingTranslations int) {
-	for topic := range originLocale {
-		for _, m := range locales {
-			if _, found := m[topic]; !found {
-				missingTranslations += len(m)
+// TranslationReport describes the completeness of the notification
+// translations for a language, for translators.
+type TranslationReport struct {
+	Lang string `json:"lang"`
+	// Translated is the number of topics with a translation for the language.
+	Translated int `json:"translated"`
+	// Inherited are the topics without a translation for the language that
+	// use the translation for a parent language other than originLang.
+	Inherited []Topic `json:"inherited,omitempty"`
+	// Missing are the topics without a translation for the language or any of
+	// its parent languages other than originLang. They are shown in
+	// originLang.
+	Missing []Topic `json:"missing,omitempty"`
+	// MissingStrings are the strings that are empty in the language's
+	// translations, e.g. "OrderPlaced subject".
+	MissingStrings []string `json:"missingStrings,omitempty"`
+	// BadFormat describes the problem with each translated template that does
+	// not use the same format arguments as the originLang template.
+	BadFormat map[Topic]string `json:"badFormat,omitempty"`
+	// Unknown are the translated topics that have no originLang translation.
+	Unknown []Topic `json:"unknown,omitempty"`
+}
+
+// TranslationReports reports the completeness of the notification
+// translations for every language other than originLang, sorted by language.
+func TranslationReports() []*TranslationReport {
+	localesMtx.RLock()
+	defer localesMtx.RUnlock()
+	reports := make([]*TranslationReport, 0, len(locales))
+	for lang := range locales {
+		if lang == originLang {
+			continue
+		}
+		reports = append(reports, translationReport(lang))
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Lang < reports[j].Lang })
+	return reports
+}
+
+// LangTranslationReport reports the completeness of the notification
+// translations for the language.
+func LangTranslationReport(lang string) (*TranslationReport, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid language %q: %w", lang, err)
+	}
+	localesMtx.RLock()
+	defer localesMtx.RUnlock()
+	if _, found := locales[tag.String()]; !found {
+		return nil, fmt.Errorf("no translations for language %s", tag)
+	}
+	return translationReport(tag.String()), nil
+}
+
+// translationReport generates the TranslationReport for the language, which
+// must be in locales. localesMtx must be locked.
+func translationReport(lang string) *TranslationReport {
+	r := &TranslationReport{
+		Lang:      lang,
+		BadFormat: make(map[Topic]string),
+	}
+	m := locales[lang]
+	chain := fallbackChain(language.Make(lang)) // lang checked in init and loadLocales
+	for topic, originTrans := range originLocale {
+		t, found := m[topic]
+		if !found {
+			inherited := false
+			for _, l := range chain[1:] {
+				if l == originLang {
+					break
+				}
+				if _, inherited = locales[l][topic]; inherited {
+					break
+				}
+			}
+			if inherited {
+				r.Inherited = append(r.Inherited, topic)
+			} else {
+				r.Missing = append(r.Missing, topic)
 			}
+			continue
+		}
+		r.Translated++
+		if t.subject.T == "" {
+			r.MissingStrings = append(r.MissingStrings, string(topic)+" subject")
 		}
+		if t.template.T == "" {
+			r.MissingStrings = append(r.MissingStrings, string(topic)+" template")
+		} else if err := checkFormatArgs(originTrans.template.T, t.template.T); err != nil {
+			r.BadFormat[topic] = err.Error()
+		}
+	}
+	for topic := range m {
+		if _, found := originLocale[topic]; !found {
+			r.Unknown = append(r.Unknown, topic)
+		}
+	}
+	sortTopics := func(topics []Topic) {
+		sort.Slice(topics, func(i, j int) bool { return topics[i] < topics[j] })
+	}
+	sortTopics(r.Inherited)
+	sortTopics(r.Missing)
+	sortTopics(r.Unknown)
+	sort.Strings(r.MissingStrings)
+	return r
+}
+
+// CheckTopicLangs is used to report missing notification translations. A
+// translation is missing if neither the language nor any of its fallback
+// languages, other than originLang, has a translation for the topic. See
+// TranslationReports for details.
+func CheckTopicLangs() (missingTranslations int) {
+	for _, r := range TranslationReports() {
+		missingTranslations += len(r.Missing)
 	}
 	return
 }
+
+// fallbackChain is the list of languages to search for a translation, in
+// order. It is the language, then its parent languages, ending with
+// originLang, e.g. pt-BR -> pt -> en-US. Languages without translations are
+// included, and must be skipped by the caller.
+func fallbackChain(lang language.Tag) []string {
+	var chain []string
+	for tag := lang; !tag.IsRoot(); tag = tag.Parent() {
+		if l := tag.String(); len(chain) == 0 || chain[len(chain)-1] != l {
+			chain = append(chain, l)
+		}
+		if tag.String() == originLang {
+			return chain
+		}
+	}
+	return append(chain, originLang)
+}
+
+// newLocale creates the locale for the language. Topics without a translation
+// for the language fall back to the translations for its parent languages and
+// finally to originLang. It is an error if neither the language nor a parent
+// language has translations.
+func newLocale(lang language.Tag) (*locale, error) {
+	localesMtx.RLock()
+	defer localesMtx.RUnlock()
+	var l *locale
+	for _, fb := range fallbackChain(lang) {
+		m, found := locales[fb]
+		if !found {
+			continue
+		}
+		if l == nil {
+			if fb == originLang && lang.String() != originLang {
+				break
+			}
+			l = &locale{
+				lang:    lang,
+				m:       m,
+				printer: message.NewPrinter(language.MustParse(fb)),
+			}
+			continue
+		}
+		l.fallbacks = append(l.fallbacks, &locale{
+			lang:    language.MustParse(fb),
+			m:       m,
+			printer: message.NewPrinter(language.MustParse(fb)),
+		})
+	}
+	if l == nil {
+		return nil, fmt.Errorf("no translations for language %s", lang)
+	}
+	return l, nil
+}
+
+// translation returns the translation for the topic and the printer for its
+// language, checking the locale's fallbacks if the locale's language has no
+// translation for the topic. If no translation is found, a nil translation is
+// returned.
+func (l *locale) translation(topic Topic) (*translation, *message.Printer) {
+	if t, found := l.m[topic]; found {
+		return t, l.printer
+	}
+	for _, fb := range l.fallbacks {
+		if t, found := fb.m[topic]; found {
+			return t, fb.printer
+		}
+	}
+	return nil, nil
+}
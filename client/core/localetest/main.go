@@ -7,5 +7,17 @@ import (
 )
 
 func main() {
+	for _, r := range core.TranslationReports() {
+		fmt.Printf("%s: %d translated, %d inherited, %d missing\n", r.Lang, r.Translated, len(r.Inherited), len(r.Missing))
+		for _, s := range r.MissingStrings {
+			fmt.Printf("  empty: %s\n", s)
+		}
+		for topic, problem := range r.BadFormat {
+			fmt.Printf("  bad format: %s: %s\n", topic, problem)
+		}
+		for _, topic := range r.Unknown {
+			fmt.Printf("  unknown topic: %s\n", topic)
+		}
+	}
 	fmt.Printf("Missing %d translations \n", core.CheckTopicLangs())
 }
@@ -0,0 +1,76 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+)
+
+// DBMaintenance checks the consistency of the database, and requests
+// compaction of the database file when Core is shut down, since the file can't
+// be compacted while it is in use. Problems are reported but not repaired.
+func (c *Core) DBMaintenance() (*db.IntegrityReport, error) {
+	report, err := c.db.CheckIntegrity()
+	if err != nil {
+		return nil, fmt.Errorf("integrity check error: %w", err)
+	}
+	logIntegrityReport(c.log, report)
+	c.db.CompactOnShutdown()
+	return report, nil
+}
+
+// MaintainDB opens the database specified by the Config, checks its
+// consistency, and compacts it. This is for maintenance while the application
+// is not running, and must not be used with a database that is in use by Core.
+// Unless Config.NoAutoDBBackup is set, a copy of the database is written to the
+// backups folder before it is compacted.
+func MaintainDB(cfg *Config) (*db.IntegrityReport, error) {
+	if cfg.Logger == nil {
+		return nil, fmt.Errorf("Core.Config must specify a Logger")
+	}
+	if _, err := os.Stat(cfg.DBPath); err != nil {
+		return nil, fmt.Errorf("database not found: %w", err)
+	}
+	clientDB, err := openDB(cfg.DBBackend, cfg.DBPath, cfg.Logger.SubLogger("DB"), !cfg.NoAutoDBBackup)
+	if err != nil {
+		return nil, fmt.Errorf("database initialization error: %w", err)
+	}
+	report, err := clientDB.CheckIntegrity()
+	if err == nil {
+		logIntegrityReport(cfg.Logger, report)
+		clientDB.CompactOnShutdown()
+	}
+	// Run backs up, compacts and closes the database once the context is
+	// canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	clientDB.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("integrity check error: %w", err)
+	}
+	return report, nil
+}
+
+// logIntegrityReport logs the result of a database integrity check.
+func logIntegrityReport(log dex.Logger, report *db.IntegrityReport) {
+	log.Infof("Checked %d orders and %d matches. Database size is %d bytes, with %d bytes unused.",
+		report.Orders, report.Matches, report.Size, report.FreeBytes)
+	if len(report.Problems) == 0 {
+		log.Infof("No database problems found")
+		return
+	}
+	for _, p := range report.Problems {
+		if p.ID != "" {
+			log.Warnf("Database problem (%s) with %s: %s", p.Kind, p.ID, p.Details)
+		} else {
+			log.Warnf("Database problem (%s): %s", p.Kind, p.Details)
+		}
+	}
+	log.Warnf("%d database problems found", len(report.Problems))
+}
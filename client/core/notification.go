@@ -4,8 +4,12 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/comms"
@@ -43,6 +47,8 @@ const (
 	NoteTypeWalletNote     = "walletnote"
 	NoteTypeReputation     = "reputation"
 	NoteTypeActionRequired = "actionrequired"
+	NoteTypeAggregate      = "aggregate"
+	NoteTypeTradingLimit   = "tradinglimit"
 )
 
 var noteChanCounter uint64
@@ -79,17 +85,142 @@ func (c *Core) Broadcast(n Notification) {
 	c.notify(n)
 }
 
+// withData sets the structured data of the notification.
+func withData[N Notification](n N, data *db.NoteData) N {
+	n.DBNote().NoteData = data
+	return n
+}
+
+// noteTypeCategories is the Category of the notifications of each type.
+// Notifications of other types are CategoryApp.
+var noteTypeCategories = map[string]db.Category{
+	NoteTypeFeePayment:     db.CategoryAccount,
+	NoteTypeBondPost:       db.CategoryAccount,
+	NoteTypeBondRefund:     db.CategoryAccount,
+	NoteTypeUnknownBond:    db.CategoryAccount,
+	NoteTypeDEXAuth:        db.CategoryAccount,
+	NoteTypeReputation:     db.CategoryAccount,
+	NoteTypeSend:           db.CategoryWallet,
+	NoteTypeBalance:        db.CategoryWallet,
+	NoteTypeWalletConfig:   db.CategoryWallet,
+	NoteTypeWalletState:    db.CategoryWallet,
+	NoteTypeWalletSync:     db.CategoryWallet,
+	NoteTypeCreateWallet:   db.CategoryWallet,
+	NoteTypeWalletNote:     db.CategoryWallet,
+	NoteTypeActionRequired: db.CategoryWallet,
+	NoteTypeOrder:          db.CategoryTrading,
+	NoteTypeMatch:          db.CategoryTrading,
+	NoteTypeEpoch:          db.CategoryTrading,
+	NoteTypeSpots:          db.CategoryTrading,
+	NoteTypeTradingLimit:   db.CategoryTrading,
+	NoteTypeConnEvent:      db.CategoryServer,
+	NoteTypeServerNotify:   db.CategoryServer,
+	NoteTypeUpgrade:        db.CategoryServer,
+	NoteTypeSecurity:       db.CategorySecurity,
+	NoteTypeBot:            db.CategoryMarketMaking,
+}
+
+// topicPriorities are the Priority of the topics that do not have the default
+// Priority for their Severity.
+var topicPriorities = map[Topic]db.Priority{
+	TopicBuyMatchesMade:  db.PriorityHigh,
+	TopicSellMatchesMade: db.PriorityHigh,
+	TopicSeedNeedsSaving: db.PriorityUrgent,
+	TopicUpgradedToSeed:  db.PriorityUrgent,
+	TopicUpgradeNeeded:   db.PriorityUrgent,
+	TopicPenalized:       db.PriorityUrgent,
+}
+
+// notePriority is the Priority of the notification. Notifications that
+// require user action are PriorityUrgent. Otherwise, unless the topic is in
+// topicPriorities, the Priority is based on the Severity.
+func notePriority(n *db.Notification) db.Priority {
+	if p, found := topicPriorities[n.TopicID]; found {
+		return p
+	}
+	if n.NoteType == NoteTypeActionRequired {
+		return db.PriorityUrgent
+	}
+	switch n.Severeness {
+	case db.Poke:
+		return db.PriorityLow
+	case db.Success:
+		return db.PriorityNormal
+	case db.WarningLevel, db.ErrorLevel:
+		return db.PriorityHigh
+	}
+	return db.PriorityNone
+}
+
+// setNoteMetadata sets the Priority and Category of the notification, unless
+// they are already set.
+func setNoteMetadata(n *db.Notification) {
+	if n.Cat == "" {
+		n.Cat = noteTypeCategories[n.NoteType]
+		if n.Cat == "" {
+			n.Cat = db.CategoryApp
+		}
+	}
+	if n.Prio == db.PriorityNone {
+		n.Prio = notePriority(n)
+	}
+}
+
 // notify sends a notification to all subscribers. If the notification is of
-// sufficient severity, it is stored in the database.
+// sufficient severity, it is stored in the database. The user's TopicSettings
+// for the notification's Topic may suppress the notification, which is then
+// only logged, or prevent it from being sent to subscribers. Notifications
+// identical to one sent recently are collapsed into an AggregateNote.
 func (c *Core) notify(n Notification) {
-	if n.Severity() >= db.Success {
+	setNoteMetadata(n.DBNote())
+
+	// Trading limit usage may change with orders, matches, and tier.
+	switch nt := n.(type) {
+	case *OrderNote:
+		if nt.Order != nil {
+			c.scheduleTradingLimitCheck(nt.Order.Host)
+		}
+	case *MatchNote:
+		c.scheduleTradingLimitCheck(nt.Host)
+	case *ReputationNote:
+		c.scheduleTradingLimitCheck(nt.Host)
+	case *BondPostNote:
+		c.scheduleTradingLimitCheck(nt.Dex)
+	case *DEXAuthNote:
+		c.scheduleTradingLimitCheck(nt.Host)
+	}
+
+	c.topicSettingsMtx.RLock()
+	settings := c.topicSettings[n.Topic()]
+	c.topicSettingsMtx.RUnlock()
+	if settings.Suppresses(n.Severity()) {
+		c.logNote(n)
+		return
+	}
+
+	if c.noteAgg != nil && !c.noteAgg.pass(n) {
+		c.log.Tracef("Collapsed repeated notification: %v", n)
+		return
+	}
+
+	c.deliverNote(n, settings)
+}
+
+// deliverNote stores, logs, and sends the notification to all subscribers.
+func (c *Core) deliverNote(n Notification, settings *db.TopicSettings) {
+	if n.Severity() >= db.Poke {
 		c.db.SaveNotification(n.DBNote())
-	} else if n.Severity() == db.Poke {
+	}
+	if n.Severity() == db.Poke {
 		c.pokesCache.add(n.DBNote())
 	}
 
 	c.logNote(n)
 
+	if settings != nil && settings.LogOnly {
+		return
+	}
+
 	c.noteMtx.RLock()
 	for _, ch := range c.noteChans {
 		select {
@@ -98,9 +229,307 @@ func (c *Core) notify(n Notification) {
 			c.log.Errorf("blocking notification channel")
 		}
 	}
+	if settings != nil && settings.Desktop {
+		for _, ch := range c.desktopChans {
+			select {
+			case ch <- n:
+			default:
+				c.log.Errorf("blocking desktop notification channel")
+			}
+		}
+	}
 	c.noteMtx.RUnlock()
 }
 
+// TopicSettings returns the user's notification settings for each Topic that
+// has non-default settings.
+func (c *Core) TopicSettings() map[Topic]*db.TopicSettings {
+	c.topicSettingsMtx.RLock()
+	defer c.topicSettingsMtx.RUnlock()
+	settings := make(map[Topic]*db.TopicSettings, len(c.topicSettings))
+	for topic, s := range c.topicSettings {
+		sCopy := *s
+		settings[topic] = &sCopy
+	}
+	return settings
+}
+
+// UpdateTopicSettings sets the user's notification settings for the Topic. The
+// settings are persisted. Nil or zero-valued settings restore the default
+// behavior for the Topic.
+func (c *Core) UpdateTopicSettings(topic Topic, settings *db.TopicSettings) error {
+	if topic == "" {
+		return errors.New("no topic specified")
+	}
+	if settings != nil && settings.MinSeverity > db.ErrorLevel {
+		return fmt.Errorf("invalid minimum severity %d", settings.MinSeverity)
+	}
+	c.topicSettingsMtx.Lock()
+	defer c.topicSettingsMtx.Unlock()
+	updated := make(map[Topic]*db.TopicSettings, len(c.topicSettings)+1)
+	for t, s := range c.topicSettings {
+		updated[t] = s
+	}
+	if settings.IsZero() {
+		delete(updated, topic)
+	} else {
+		sCopy := *settings
+		updated[topic] = &sCopy
+	}
+	if err := c.db.SetTopicSettings(updated); err != nil {
+		return fmt.Errorf("error storing notification settings: %w", err)
+	}
+	c.topicSettings = updated
+	return nil
+}
+
+// NoteTemplates returns the user's notification templates for each Topic that
+// has one.
+func (c *Core) NoteTemplates() map[Topic]*db.NoteTemplate {
+	c.noteTemplatesMtx.RLock()
+	defer c.noteTemplatesMtx.RUnlock()
+	templates := make(map[Topic]*db.NoteTemplate, len(c.noteTemplates))
+	for topic, t := range c.noteTemplates {
+		tCopy := *t
+		templates[topic] = &tCopy
+	}
+	return templates
+}
+
+// UpdateNoteTemplate sets the user's subject and template for notifications
+// with the Topic, which are used instead of the translations for every
+// language. The template must use the same format arguments as the en-US
+// template for the Topic. The templates are persisted. A nil or zero-valued
+// template restores the translations for the Topic.
+func (c *Core) UpdateNoteTemplate(topic Topic, tmpl *db.NoteTemplate) error {
+	originTrans, found := originLocale[topic]
+	if !found {
+		return fmt.Errorf("unknown topic %q", topic)
+	}
+	if tmpl != nil && tmpl.Template != "" {
+		if err := checkFormatArgs(originTrans.template.T, tmpl.Template); err != nil {
+			return fmt.Errorf("invalid template for topic %q: %w", topic, err)
+		}
+	}
+	c.noteTemplatesMtx.Lock()
+	defer c.noteTemplatesMtx.Unlock()
+	updated := make(map[Topic]*db.NoteTemplate, len(c.noteTemplates)+1)
+	for t, tmpl := range c.noteTemplates {
+		updated[t] = tmpl
+	}
+	if tmpl.IsZero() {
+		delete(updated, topic)
+	} else {
+		tCopy := *tmpl
+		updated[topic] = &tCopy
+	}
+	if err := c.db.SetNoteTemplates(updated); err != nil {
+		return fmt.Errorf("error storing notification templates: %w", err)
+	}
+	c.noteTemplates = updated
+	return nil
+}
+
+// RawNotifications returns the most recent notifications that are displayed to
+// the user, including those collapsed into an AggregateNote, oldest first.
+func (c *Core) RawNotifications() []*db.Notification {
+	if c.noteAgg == nil {
+		return nil
+	}
+	return c.noteAgg.raw.pokes()
+}
+
+const (
+	// noteAggregationWindow is how long identical notifications are collapsed
+	// after the first is sent.
+	noteAggregationWindow = 5 * time.Minute
+	// rawNotesCapacity is the number of notifications retained for
+	// RawNotifications.
+	rawNotesCapacity = 1000
+	// maxNoteSearchResults is the maximum number of notifications returned by
+	// SearchNotifications.
+	maxNoteSearchResults = 1000
+	// notePruneInterval is how often notifications older than the
+	// Config.NoteRetention are deleted.
+	notePruneInterval = 24 * time.Hour
+)
+
+// SearchNotifications finds the stored notifications that pass the filter,
+// newest first. All notifications with a severity of at least Poke are stored,
+// until they are older than the Config.NoteRetention. No more than 1000
+// notifications are returned.
+func (c *Core) SearchNotifications(filter *db.NoteFilter) ([]*db.Notification, error) {
+	if filter == nil {
+		filter = new(db.NoteFilter)
+	}
+	f := *filter
+	if f.N <= 0 || f.N > maxNoteSearchResults {
+		f.N = maxNoteSearchResults
+	}
+	if f.UntilUnixMs > 0 && f.UntilUnixMs < f.SinceUnixMs {
+		return nil, fmt.Errorf("invalid time range %d to %d", f.SinceUnixMs, f.UntilUnixMs)
+	}
+	notes, err := c.db.SearchNotifications(&f)
+	if err != nil {
+		return nil, fmt.Errorf("error searching notifications: %w", err)
+	}
+	for _, n := range notes {
+		setNoteMetadata(n)
+	}
+	return notes, nil
+}
+
+// pruneNotifications deletes the stored notifications that are older than the
+// Config.NoteRetention.
+func (c *Core) pruneNotifications() {
+	if c.cfg.NoteRetention <= 0 {
+		return
+	}
+	olderThan := time.Now().Add(-c.cfg.NoteRetention)
+	n, err := c.db.DeleteNotifications(uint64(olderThan.UnixMilli()))
+	if err != nil {
+		c.log.Errorf("Error deleting old notifications: %v", err)
+		return
+	}
+	if n > 0 {
+		c.log.Infof("Deleted %d notifications from before %s", n, olderThan.Format(time.DateTime))
+	}
+}
+
+// watchNoteRetention deletes old notifications periodically until the context
+// is canceled.
+func (c *Core) watchNoteRetention(ctx context.Context) {
+	c.pruneNotifications()
+	ticker := time.NewTicker(notePruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.pruneNotifications()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// noteAggregate is a notification and the count of identical notifications
+// that followed it within the aggregation window.
+type noteAggregate struct {
+	note    Notification
+	start   time.Time
+	repeats int
+	last    uint64
+}
+
+// noteAggregator collapses identical notifications, such as a warning that is
+// repeated on every reconnect attempt. The first notification is sent, and any
+// identical notifications within the window are counted and reported in a
+// single AggregateNote at the end of the window. Only notifications meant for
+// display are aggregated. Data notifications are always sent.
+type noteAggregator struct {
+	window time.Duration
+	send   func(*AggregateNote)
+	raw    *pokesCache
+
+	mtx  sync.Mutex
+	aggs map[string]*noteAggregate
+}
+
+func newNoteAggregator(window time.Duration, send func(*AggregateNote)) *noteAggregator {
+	return &noteAggregator{
+		window: window,
+		send:   send,
+		raw:    newPokesCache(rawNotesCapacity),
+		aggs:   make(map[string]*noteAggregate),
+	}
+}
+
+// pass records the notification and checks if it should be sent. A
+// notification should not be sent if it is identical to one sent within the
+// window.
+func (a *noteAggregator) pass(n Notification) bool {
+	if n.Severity() < db.Poke {
+		return true
+	}
+	a.raw.add(n.DBNote())
+	key := n.Type() + "|" + string(n.Topic()) + "|" + n.Subject() + "|" + n.Details()
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	now := time.Now()
+	if agg, found := a.aggs[key]; found && now.Sub(agg.start) < a.window {
+		agg.repeats++
+		agg.last = n.Time()
+		if agg.repeats == 1 {
+			time.AfterFunc(a.window-now.Sub(agg.start), func() { a.flush(key, agg) })
+		}
+		return false
+	}
+	// Prune any expired aggregates that have nothing to report.
+	for k, agg := range a.aggs {
+		if agg.repeats == 0 && now.Sub(agg.start) >= a.window {
+			delete(a.aggs, k)
+		}
+	}
+	a.aggs[key] = &noteAggregate{note: n, start: now}
+	return true
+}
+
+// flush sends an AggregateNote for the repeated notifications, ending the
+// window.
+func (a *noteAggregator) flush(key string, agg *noteAggregate) {
+	a.mtx.Lock()
+	if a.aggs[key] == agg {
+		delete(a.aggs, key)
+	}
+	repeats, last := agg.repeats, agg.last
+	a.mtx.Unlock()
+	a.send(newAggregateNote(agg.note, repeats, last))
+}
+
+// AggregateNote reports identical notifications that were collapsed into the
+// first. The Topic and Subject are those of the repeated notification.
+type AggregateNote struct {
+	db.Notification
+	// RepeatedType is the type of the repeated notification.
+	RepeatedType string `json:"repeatedType"`
+	// Repeats is the number of identical notifications after the first.
+	Repeats int `json:"repeats"`
+	// First is the timestamp of the first notification, and Last is the
+	// timestamp of the last repeat.
+	First uint64 `json:"first"`
+	Last  uint64 `json:"last"`
+}
+
+const TopicNotificationRepeated Topic = "NotificationRepeated"
+
+func newAggregateNote(n Notification, repeats int, last uint64) *AggregateNote {
+	note := &AggregateNote{
+		// Details are set in sendAggregateNote.
+		Notification: db.NewNotification(NoteTypeAggregate, n.Topic(), n.Subject(), n.Details(), n.Severity()),
+		RepeatedType: n.Type(),
+		Repeats:      repeats,
+		First:        n.Time(),
+		Last:         last,
+	}
+	note.Prio, note.Cat, note.NoteData = n.Priority(), n.Category(), n.Data()
+	return note
+}
+
+// sendAggregateNote translates the details of the AggregateNote and delivers
+// it, bypassing aggregation.
+func (c *Core) sendAggregateNote(n *AggregateNote) {
+	_, n.DetailText = c.formatDetails(TopicNotificationRepeated, n.DetailText, n.Repeats)
+	n.Stamp()
+	c.topicSettingsMtx.RLock()
+	settings := c.topicSettings[n.Topic()]
+	c.topicSettingsMtx.RUnlock()
+	if settings.Suppresses(n.Severity()) {
+		return
+	}
+	c.deliverNote(n, settings)
+}
+
 // NoteFeed contains a receiving channel for notifications.
 type NoteFeed struct {
 	C      <-chan Notification
@@ -140,6 +569,28 @@ func (c *Core) returnFeed(channelID uint64) {
 	c.noteMtx.Unlock()
 }
 
+// DesktopNotificationFeed returns a new receiving channel for the
+// notifications that should be shown as native OS desktop notifications, i.e.
+// those with a Topic that the user has opted in to with the Desktop field of
+// the TopicSettings. Notifications that are muted, filtered by severity, or
+// LogOnly are not sent. The channel has capacity 1024, and should be monitored
+// for the lifetime of the Core.
+func (c *Core) DesktopNotificationFeed() *NoteFeed {
+	ch := make(chan Notification, 1024)
+	cid := atomic.AddUint64(&noteChanCounter, 1)
+	c.noteMtx.Lock()
+	c.desktopChans[cid] = ch
+	c.noteMtx.Unlock()
+	return &NoteFeed{
+		C: ch,
+		closer: func() {
+			c.noteMtx.Lock()
+			delete(c.desktopChans, cid)
+			c.noteMtx.Unlock()
+		},
+	}
+}
+
 // AckNotes sets the acknowledgement field for the notifications.
 func (c *Core) AckNotes(ids []dex.Bytes) {
 	for _, id := range ids {
@@ -151,17 +602,32 @@ func (c *Core) AckNotes(ids []dex.Bytes) {
 }
 
 func (c *Core) formatDetails(topic Topic, args ...any) (translatedSubject, details string) {
-	locale := c.locale()
-	trans, found := locale.m[topic]
-	if !found {
+	trans, printer := c.locale().translation(topic)
+	sprintf := fmt.Sprintf
+	if trans == nil {
 		c.log.Errorf("No translation found for topic %q", topic)
 		originTrans, found := originLocale[topic]
 		if !found {
 			return string(topic), "translation error"
 		}
-		return originTrans.subject.T, fmt.Sprintf(originTrans.template.T, args...)
+		translatedSubject, details = originTrans.subject.T, fmt.Sprintf(originTrans.template.T, args...)
+	} else {
+		sprintf = func(format string, args ...any) string { return printer.Sprintf(format, args...) }
+		translatedSubject, details = trans.subject.T, printer.Sprintf(string(topic), args...)
+	}
+	// The user's templates replace the translations.
+	c.noteTemplatesMtx.RLock()
+	tmpl := c.noteTemplates[topic]
+	c.noteTemplatesMtx.RUnlock()
+	if tmpl != nil {
+		if tmpl.Subject != "" {
+			translatedSubject = tmpl.Subject
+		}
+		if tmpl.Template != "" {
+			details = sprintf(tmpl.Template, args...)
+		}
 	}
-	return trans.subject.T, locale.printer.Sprintf(string(topic), args...)
+	return translatedSubject, details
 }
 
 func makeCoinIDToken(txHash string, assetID uint32) string {
@@ -190,6 +656,13 @@ type Notification interface {
 	Details() string
 	// Severity is the notification severity.
 	Severity() db.Severity
+	// Priority is how urgently the notification should be brought to the
+	// user's attention. Notification sinks should use the Priority, rather
+	// than the Severity, to decide how to alert the user.
+	Priority() db.Priority
+	// Category is the area of the application that the notification
+	// concerns, for routing by notification sinks.
+	Category() db.Category
 	// Time is the notification timestamp. The timestamp is set in
 	// db.NewNotification. Time is a UNIX timestamp, in milliseconds.
 	Time() uint64
@@ -204,6 +677,10 @@ type Notification interface {
 	Stamp()
 	// DBNote returns the underlying *db.Notification.
 	DBNote() *db.Notification
+	// Data is structured data about the subject of the notification, e.g.
+	// the host, asset, order and match, for programmatic consumers. Data may
+	// be nil.
+	Data() *db.NoteData
 	// String generates a compact human-readable representation of the
 	// Notification that is suitable for logging.
 	String() string
@@ -221,6 +698,7 @@ type SecurityNote struct {
 const (
 	TopicSeedNeedsSaving Topic = "SeedNeedsSaving"
 	TopicUpgradedToSeed  Topic = "UpgradedToSeed"
+	TopicBackupFailed    Topic = "BackupFailed"
 )
 
 func newSecurityNote(topic Topic, subject, details string, severity db.Severity) *SecurityNote {
@@ -257,16 +735,17 @@ type FeePaymentNote struct {
 
 func newFeePaymentNote(topic Topic, subject, details string, severity db.Severity, dexAddr string) *FeePaymentNote {
 	host, _ := addrHost(dexAddr)
-	return &FeePaymentNote{
+	return withData(&FeePaymentNote{
 		Notification: db.NewNotification(NoteTypeFeePayment, topic, subject, details, severity),
 		Dex:          host,
-	}
+	}, &db.NoteData{Host: host})
 }
 
 func newFeePaymentNoteWithConfirmations(topic Topic, subject, details string, severity db.Severity, asset, currConfs uint32, dexAddr string) *FeePaymentNote {
 	feePmtNt := newFeePaymentNote(topic, subject, details, severity, dexAddr)
 	feePmtNt.Asset = &asset
 	feePmtNt.Confirmations = &currConfs
+	feePmtNt.NoteData.AssetID = &asset
 	return feePmtNt
 }
 
@@ -298,10 +777,10 @@ type BondPostNote struct {
 
 func newBondPostNote(topic Topic, subject, details string, severity db.Severity, dexAddr string) *BondPostNote {
 	host, _ := addrHost(dexAddr)
-	return &BondPostNote{
+	return withData(&BondPostNote{
 		Notification: db.NewNotification(NoteTypeBondPost, topic, subject, details, severity),
 		Dex:          host,
-	}
+	}, &db.NoteData{Host: host})
 }
 
 func newBondPostNoteWithConfirmations(
@@ -321,6 +800,8 @@ func newBondPostNoteWithConfirmations(
 	bondPmtNt.CoinID = &coinID
 	bondPmtNt.Confirmations = &currConfs
 	bondPmtNt.Auth = auth
+	bondPmtNt.NoteData.AssetID = &asset
+	bondPmtNt.NoteData.CoinID = coinID
 	return bondPmtNt
 }
 
@@ -404,13 +885,25 @@ const (
 	TopicAsyncOrderFailure    Topic = "AsyncOrderFailure"
 	TopicAsyncOrderSubmitted  Topic = "AsyncOrderSubmitted"
 	TopicOrderQuantityTooHigh Topic = "OrderQuantityTooHigh"
+	TopicEpochProofMismatch   Topic = "EpochProofMismatch"
 )
 
 func newOrderNote(topic Topic, subject, details string, severity db.Severity, corder *Order) *OrderNote {
-	return &OrderNote{
+	note := &OrderNote{
 		Notification: db.NewNotification(NoteTypeOrder, topic, subject, details, severity),
 		Order:        corder,
 	}
+	if corder != nil {
+		baseID := corder.BaseID
+		note.NoteData = &db.NoteData{
+			Host:     corder.Host,
+			AssetID:  &baseID,
+			MarketID: corder.MarketID,
+			OrderID:  corder.ID,
+			Amount:   corder.Qty,
+		}
+	}
+	return note
 }
 
 func newOrderNoteWithTempID(topic Topic, subject, details string, severity db.Severity, corder *Order, tempID uint64) *OrderNote {
@@ -446,7 +939,7 @@ func newMatchNote(topic Topic, subject, details string, severity db.Severity, t
 		// match note, it should be non-negative.
 		counterConfs = 0
 	}
-	return &MatchNote{
+	note := &MatchNote{
 		Notification: db.NewNotification(NoteTypeMatch, topic, subject, details, severity),
 		OrderID:      t.ID().Bytes(),
 		Match: matchFromMetaMatchWithConfs(t.Order, &match.MetaMatch, swapConfs,
@@ -455,6 +948,15 @@ func newMatchNote(topic Topic, subject, details string, severity db.Severity, t
 		Host:     t.dc.acct.host,
 		MarketID: marketName(t.Base(), t.Quote()),
 	}
+	baseID := t.Base()
+	return withData(note, &db.NoteData{
+		Host:     note.Host,
+		AssetID:  &baseID,
+		MarketID: note.MarketID,
+		OrderID:  note.OrderID,
+		MatchID:  note.Match.MatchID,
+		Amount:   note.Match.Qty,
+	})
 }
 
 // String supplements db.Notification's Stringer with the Order's ID, if the
@@ -478,12 +980,12 @@ type EpochNotification struct {
 const TopicEpoch Topic = "Epoch"
 
 func newEpochNotification(host, mktID string, epochIdx uint64) *EpochNotification {
-	return &EpochNotification{
+	return withData(&EpochNotification{
 		Host:         host,
 		MarketID:     mktID,
 		Notification: db.NewNotification(NoteTypeEpoch, TopicEpoch, "", "", db.Data),
 		Epoch:        epochIdx,
-	}
+	}, &db.NoteData{Host: host, MarketID: mktID})
 }
 
 // String supplements db.Notification's Stringer with the Epoch index.
@@ -502,14 +1004,15 @@ const (
 	TopicDEXConnected    Topic = "DEXConnected"
 	TopicDEXDisconnected Topic = "DEXDisconnected"
 	TopicDexConnectivity Topic = "DEXConnectivity"
+	TopicDEXSlowServer   Topic = "DEXSlowServer"
 )
 
 func newConnEventNote(topic Topic, subject, host string, status comms.ConnectionStatus, details string, severity db.Severity) *ConnEventNote {
-	return &ConnEventNote{
+	return withData(&ConnEventNote{
 		Notification:     db.NewNotification(NoteTypeConnEvent, topic, subject, details, severity),
 		Host:             host,
 		ConnectionStatus: status,
-	}
+	}, &db.NoteData{Host: host})
 }
 
 // FiatRatesNote is an update of fiat rate data for assets.
@@ -537,11 +1040,11 @@ type BalanceNote struct {
 const TopicBalanceUpdated Topic = "BalanceUpdated"
 
 func newBalanceNote(assetID uint32, bal *WalletBalance) *BalanceNote {
-	return &BalanceNote{
+	return withData(&BalanceNote{
 		Notification: db.NewNotification(NoteTypeBalance, TopicBalanceUpdated, "", "", db.Data),
 		AssetID:      assetID,
 		Balance:      bal, // Once created, balance is never modified by Core.
-	}
+	}, &db.NoteData{AssetID: &assetID})
 }
 
 // SpotPriceNote is a notification of an update to the market's spot price.
@@ -554,11 +1057,11 @@ type SpotPriceNote struct {
 const TopicSpotsUpdate Topic = "SpotsUpdate"
 
 func newSpotPriceNote(host string, spots map[string]*msgjson.Spot) *SpotPriceNote {
-	return &SpotPriceNote{
+	return withData(&SpotPriceNote{
 		Notification: db.NewNotification(NoteTypeSpots, TopicSpotsUpdate, "", "", db.Data),
 		Host:         host,
 		Spots:        spots,
-	}
+	}, &db.NoteData{Host: host})
 }
 
 // DEXAuthNote is a notification regarding individual DEX authentication status.
@@ -579,11 +1082,11 @@ const (
 )
 
 func newDEXAuthNote(topic Topic, subject, host string, authenticated bool, details string, severity db.Severity) *DEXAuthNote {
-	return &DEXAuthNote{
+	return withData(&DEXAuthNote{
 		Notification:  db.NewNotification(NoteTypeDEXAuth, topic, subject, details, severity),
 		Host:          host,
 		Authenticated: authenticated,
-	}
+	}, &db.NoteData{Host: host})
 }
 
 // WalletConfigNote is a notification regarding a change in wallet
@@ -603,10 +1106,19 @@ const (
 )
 
 func newWalletConfigNote(topic Topic, subject, details string, severity db.Severity, walletState *WalletState) *WalletConfigNote {
-	return &WalletConfigNote{
+	return withData(&WalletConfigNote{
 		Notification: db.NewNotification(NoteTypeWalletConfig, topic, subject, details, severity),
 		Wallet:       walletState,
+	}, walletStateData(walletState))
+}
+
+// walletStateData is the structured data for a notification about the wallet.
+func walletStateData(walletState *WalletState) *db.NoteData {
+	if walletState == nil {
+		return nil
 	}
+	assetID := walletState.AssetID
+	return &db.NoteData{AssetID: &assetID}
 }
 
 // WalletStateNote is a notification regarding a change in wallet state,
@@ -618,17 +1130,17 @@ const TopicWalletState Topic = "WalletState"
 const TopicTokenApproval Topic = "TokenApproval"
 
 func newTokenApprovalNote(walletState *WalletState) *WalletStateNote {
-	return &WalletStateNote{
+	return withData(&WalletStateNote{
 		Notification: db.NewNotification(NoteTypeWalletState, TopicTokenApproval, "", "", db.Data),
 		Wallet:       walletState,
-	}
+	}, walletStateData(walletState))
 }
 
 func newWalletStateNote(walletState *WalletState) *WalletStateNote {
-	return &WalletStateNote{
+	return withData(&WalletStateNote{
 		Notification: db.NewNotification(NoteTypeWalletState, TopicWalletState, "", "", db.Data),
 		Wallet:       walletState,
-	}
+	}, walletStateData(walletState))
 }
 
 // WalletSyncNote is a notification of the wallet sync status.
@@ -637,17 +1149,22 @@ type WalletSyncNote struct {
 	AssetID      uint32            `json:"assetID"`
 	SyncStatus   *asset.SyncStatus `json:"syncStatus"`
 	SyncProgress float32           `json:"syncProgress"`
+	// Progress is the progress of the sync or rescan in the same terms for
+	// every asset, with an estimate of the time remaining. Progress is nil
+	// once the wallet is synced, except in the note that reports completion.
+	Progress *WalletSyncProgress `json:"progress,omitempty"`
 }
 
 const TopicWalletSync = "WalletSync"
 
-func newWalletSyncNote(assetID uint32, ss *asset.SyncStatus) *WalletSyncNote {
-	return &WalletSyncNote{
+func newWalletSyncNote(assetID uint32, ss *asset.SyncStatus, progress *WalletSyncProgress) *WalletSyncNote {
+	return withData(&WalletSyncNote{
 		Notification: db.NewNotification(NoteTypeWalletSync, TopicWalletState, "", "", db.Data),
 		AssetID:      assetID,
 		SyncStatus:   ss,
 		SyncProgress: ss.BlockProgress(),
-	}
+		Progress:     progress,
+	}, &db.NoteData{AssetID: &assetID})
 }
 
 // ServerNotifyNote is a notification containing a server-originating message.
@@ -699,10 +1216,10 @@ const (
 )
 
 func newWalletCreationNote(topic Topic, subject, details string, severity db.Severity, assetID uint32) *WalletCreationNote {
-	return &WalletCreationNote{
+	return withData(&WalletCreationNote{
 		Notification: db.NewNotification(NoteTypeCreateWallet, topic, subject, details, severity),
 		AssetID:      assetID,
-	}
+	}, &db.NoteData{AssetID: &assetID})
 }
 
 // LoginNote is a notification with the recent login status.
@@ -742,11 +1259,32 @@ type ReputationNote struct {
 const TopicReputationUpdate = "ReputationUpdate"
 
 func newReputationNote(host string, rep account.Reputation) *ReputationNote {
-	return &ReputationNote{
+	return withData(&ReputationNote{
 		Notification: db.NewNotification(NoteTypeReputation, TopicReputationUpdate, "", "", db.Data),
 		Host:         host,
 		Reputation:   rep,
-	}
+	}, &db.NoteData{Host: host})
+}
+
+// TradingLimitNote is a notification of a change in the user's trading limit
+// usage on a server.
+type TradingLimitNote struct {
+	db.Notification
+	Host   string              `json:"host"`
+	Limits *TradingLimitStatus `json:"limits,omitempty"`
+}
+
+const (
+	TopicTradingLimitUpdate      Topic = "TradingLimitUpdate"
+	TopicTradingLimitApproaching Topic = "TradingLimitApproaching"
+)
+
+func newTradingLimitNote(topic Topic, subject, details string, severity db.Severity, status *TradingLimitStatus) *TradingLimitNote {
+	return withData(&TradingLimitNote{
+		Notification: db.NewNotification(NoteTypeTradingLimit, topic, subject, details, severity),
+		Host:         status.Host,
+		Limits:       status,
+	}, &db.NoteData{Host: status.Host})
 }
 
 const TopicUnknownBondTierZero = "UnknownBondTierZero"
@@ -794,9 +1332,13 @@ func newRejectedRedemptionNote(assetID uint32, oid order.OrderID, coinID []byte)
 	}
 	uniqueID := dex.Bytes(coinID).String()
 	actionNote := newActionRequiredNote(ActionIDRedeemRejected, uniqueID, data)
-	coreNote := &ActionRequiredNote{
+	coreNote := withData(&ActionRequiredNote{
 		Notification: db.NewNotification(NoteTypeActionRequired, TopicRedeemRejected, "", "", db.Data),
 		Payload:      actionNote,
-	}
+	}, &db.NoteData{
+		AssetID: &data.AssetID,
+		OrderID: data.OrderID,
+		CoinID:  data.CoinFmt,
+	})
 	return actionNote, coreNote
 }
@@ -0,0 +1,438 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+)
+
+// RecoveryForm is the input to RecoverFromSeed.
+type RecoveryForm struct {
+	AppPass []byte
+	Seed    string
+	// Assets are the assets to restore native wallets for. If empty, every
+	// asset with a native wallet is restored.
+	Assets []uint32
+	// Hosts are the DEX hosts to discover accounts on. If empty, the known
+	// hosts for the network are used.
+	Hosts []string
+}
+
+// RecoveredWallet is the result of restoring a native wallet.
+type RecoveredWallet struct {
+	AssetID uint32 `json:"assetID"`
+	Symbol  string `json:"symbol"`
+	Type    string `json:"type"`
+	Created bool   `json:"created"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RecoveredOrder is an active order reported by the server.
+type RecoveredOrder struct {
+	ID     dex.Bytes         `json:"id"`
+	Status order.OrderStatus `json:"status"`
+	// Known is true if the order is in the database and is being tracked.
+	Known bool `json:"known"`
+}
+
+// RecoveredMatch is an active match reported by the server.
+type RecoveredMatch struct {
+	MatchID dex.Bytes         `json:"matchID"`
+	OrderID dex.Bytes         `json:"orderID"`
+	Status  order.MatchStatus `json:"status"`
+	Side    order.MatchSide   `json:"side"`
+	Qty     uint64            `json:"qty"`
+	Rate    uint64            `json:"rate"`
+	// Known is true if the match's order is in the database and the match is
+	// being negotiated.
+	Known bool `json:"known"`
+	// The fields below are only set for unknown matches. MarketID is set if
+	// the server reported the match's swap data. Swap and CounterSwap are our
+	// and the counterparty's swaps, if they were found on-chain by the
+	// restored wallets. Secret is set if the maker has revealed it.
+	MarketID    string         `json:"marketID,omitempty"`
+	Swap        *RecoveredSwap `json:"swap,omitempty"`
+	CounterSwap *RecoveredSwap `json:"counterSwap,omitempty"`
+	Secret      dex.Bytes      `json:"secret,omitempty"`
+	// Refundable is true if our swap is unspent and its lock time has
+	// expired.
+	Refundable bool `json:"refundable"`
+	// Redeemable is true if the counterparty's swap was found, the secret is
+	// known, and the server has not seen our redeem.
+	Redeemable bool `json:"redeemable"`
+}
+
+// RecoveredSwap is a swap contract of an unknown match found on-chain.
+type RecoveredSwap struct {
+	Coin       *Coin     `json:"coin"`
+	Contract   dex.Bytes `json:"contract"`
+	SecretHash dex.Bytes `json:"secretHash"`
+	// LockTime is when the contract can be refunded (ms UNIX).
+	LockTime uint64 `json:"lockTime"`
+	// Spent is only checked for our swap.
+	Spent bool `json:"spent,omitempty"`
+}
+
+// RecoveredAccount is the result of discovering a DEX account.
+type RecoveredAccount struct {
+	Host          string            `json:"host"`
+	Found         bool              `json:"found"`
+	EffectiveTier int64             `json:"effectiveTier"`
+	LiveStrength  int64             `json:"liveStrength"`
+	Orders        []*RecoveredOrder `json:"orders"`
+	Matches       []*RecoveredMatch `json:"matches"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// RecoveryReport is the result of RecoverFromSeed.
+type RecoveryReport struct {
+	Wallets  []*RecoveredWallet  `json:"wallets"`
+	Accounts []*RecoveredAccount `json:"accounts"`
+	// ManualActions describes the things that could not be recovered
+	// automatically and need the user's attention.
+	ManualActions []string `json:"manualActions"`
+}
+
+func (r *RecoveryReport) needsAction(format string, args ...any) {
+	r.ManualActions = append(r.ManualActions, fmt.Sprintf(format, args...))
+}
+
+// RecoverFromSeed rebuilds a fresh app from its seed. The app is initialized
+// with the seed and logged in, native wallets are re-derived and start
+// rescanning, and accounts are discovered on the DEX hosts. The server's
+// reports of active orders and matches for the discovered accounts are
+// reconciled with the restored database. Unknown orders are canceled, as they
+// are after any login. The swaps of matches that are not in the database are
+// looked up on-chain with the server's swap data, and the ones that can be
+// refunded or redeemed are listed. The report lists what was recovered and
+// what needs manual action.
+func (c *Core) RecoverFromSeed(form *RecoveryForm) (*RecoveryReport, error) {
+	if c.IsInitialized() {
+		return nil, errors.New("the app is already initialized. recovery requires a new app")
+	}
+	if form.Seed == "" {
+		return nil, errors.New("no seed provided")
+	}
+	if _, err := c.InitializeClient(form.AppPass, &form.Seed); err != nil {
+		return nil, fmt.Errorf("error initializing with seed: %w", err)
+	}
+	if err := c.Login(form.AppPass); err != nil {
+		return nil, fmt.Errorf("login error: %w", err)
+	}
+
+	report := &RecoveryReport{
+		Wallets:       make([]*RecoveredWallet, 0),
+		Accounts:      make([]*RecoveredAccount, 0),
+		ManualActions: make([]string, 0),
+	}
+
+	// Wallets
+	assetIDs := form.Assets
+	if len(assetIDs) == 0 {
+		for assetID := range asset.Assets() {
+			assetIDs = append(assetIDs, assetID)
+		}
+		sort.Slice(assetIDs, func(i, j int) bool { return assetIDs[i] < assetIDs[j] })
+	}
+	for _, assetID := range assetIDs {
+		if rw := c.recoverWallet(assetID, form.AppPass, len(form.Assets) > 0); rw != nil {
+			report.Wallets = append(report.Wallets, rw)
+			if rw.Error != "" {
+				report.needsAction("Create the %s wallet manually: %s", unbip(assetID), rw.Error)
+			}
+		}
+	}
+
+	// Accounts
+	hosts := form.Hosts
+	if len(hosts) == 0 {
+		for host := range CertStore[c.net] {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+	}
+	for _, host := range hosts {
+		report.Accounts = append(report.Accounts, c.recoverAccount(host, form.AppPass, report))
+	}
+
+	return report, nil
+}
+
+// recoverWallet creates the native wallet for the asset. If required is false,
+// a nil *RecoveredWallet is returned for assets with no native wallet.
+func (c *Core) recoverWallet(assetID uint32, appPW []byte, required bool) *RecoveredWallet {
+	rw := &RecoveredWallet{
+		AssetID: assetID,
+		Symbol:  unbip(assetID),
+	}
+	ra := asset.Asset(assetID)
+	if ra == nil {
+		if !required {
+			return nil
+		}
+		rw.Error = "unknown asset"
+		return rw
+	}
+	var def *asset.WalletDefinition
+	for _, d := range ra.Info.AvailableWallets {
+		if d.Seeded {
+			def = d
+			break
+		}
+	}
+	if def == nil {
+		if !required {
+			return nil
+		}
+		rw.Error = "no native wallet"
+		return rw
+	}
+	rw.Type = def.Type
+	if _, exists := c.wallet(assetID); exists {
+		rw.Error = "wallet already exists"
+		return rw
+	}
+	err := c.CreateWallet(appPW, nil, &WalletForm{
+		AssetID: assetID,
+		Config:  make(map[string]string),
+		Type:    def.Type,
+	})
+	if err != nil {
+		rw.Error = err.Error()
+		return rw
+	}
+	rw.Created = true
+	return rw
+}
+
+// recoverAccount discovers the account on the host and reconciles the server's
+// active orders and matches with the database. The swaps of unknown matches
+// are looked up with scanUnknownMatches.
+func (c *Core) recoverAccount(host string, appPW []byte, report *RecoveryReport) *RecoveredAccount {
+	ra := &RecoveredAccount{
+		Host:    host,
+		Orders:  make([]*RecoveredOrder, 0),
+		Matches: make([]*RecoveredMatch, 0),
+	}
+	xc, found, err := c.DiscoverAccount(host, appPW, nil)
+	if err != nil {
+		ra.Error = err.Error()
+		report.needsAction("Discover the account on %s when it is reachable: %v", host, err)
+		return ra
+	}
+	ra.Found = found
+	if !found {
+		return ra
+	}
+	ra.EffectiveTier = xc.Auth.EffectiveTier
+	ra.LiveStrength = xc.Auth.LiveStrength
+	if xc.Auth.LiveStrength > 0 && xc.Auth.TargetTier == 0 {
+		report.needsAction("Set a target tier for %s to keep the account's %d bonded tiers.",
+			host, xc.Auth.LiveStrength)
+	}
+
+	dc, _, err := c.dex(host)
+	if err != nil {
+		ra.Error = err.Error()
+		return ra
+	}
+	result := dc.connectResult.Load()
+	if result == nil {
+		return ra
+	}
+	for _, ordStatus := range result.ActiveOrderStatuses {
+		var oid order.OrderID
+		copy(oid[:], ordStatus.ID)
+		tracker, _ := dc.findOrder(oid)
+		ro := &RecoveredOrder{
+			ID:     ordStatus.ID,
+			Status: order.OrderStatus(ordStatus.Status),
+			Known:  tracker != nil,
+		}
+		ra.Orders = append(ra.Orders, ro)
+		if !ro.Known {
+			report.needsAction("Order %s on %s was not in the restored database. A cancel order was sent for it.",
+				oid, host)
+		}
+	}
+	var unknown []*unknownMatch
+	for _, m := range result.ActiveMatches {
+		var oid order.OrderID
+		copy(oid[:], m.OrderID)
+		tracker, _ := dc.findOrder(oid)
+		rm := &RecoveredMatch{
+			MatchID: m.MatchID,
+			OrderID: m.OrderID,
+			Status:  order.MatchStatus(m.Status),
+			Side:    order.MatchSide(m.Side),
+			Qty:     m.Quantity,
+			Rate:    m.Rate,
+			Known:   tracker != nil,
+		}
+		ra.Matches = append(ra.Matches, rm)
+		if !rm.Known {
+			unknown = append(unknown, &unknownMatch{rm, m})
+		}
+	}
+	if len(unknown) > 0 {
+		c.scanUnknownMatches(dc, unknown)
+	}
+	for _, um := range unknown {
+		reportUnknownMatch(report, host, um.rm)
+	}
+	return ra
+}
+
+// unknownMatch is an active match reported by the server whose order is not
+// in the database.
+type unknownMatch struct {
+	rm  *RecoveredMatch
+	msg *msgjson.Match
+}
+
+// scanUnknownMatches requests the server's swap data for the matches, and
+// looks for the swaps on-chain with the wallets of the match's market. The
+// server's connect response does not say which market a match is on, so the
+// match statuses are requested market by market.
+func (c *Core) scanUnknownMatches(dc *dexConnection, matches []*unknownMatch) {
+	cfg := dc.config()
+	if cfg == nil {
+		return
+	}
+	for _, mkt := range cfg.Markets {
+		byID := make(map[order.MatchID]*unknownMatch, len(matches))
+		reqs := make([]*msgjson.MatchRequest, 0, len(matches))
+		for _, um := range matches {
+			if um.rm.MarketID != "" {
+				continue // found on another market
+			}
+			var mid order.MatchID
+			copy(mid[:], um.rm.MatchID)
+			byID[mid] = um
+			reqs = append(reqs, &msgjson.MatchRequest{
+				Base:    mkt.Base,
+				Quote:   mkt.Quote,
+				MatchID: um.rm.MatchID,
+			})
+		}
+		if len(reqs) == 0 {
+			return
+		}
+		var results []*msgjson.MatchStatusResult
+		err := sendRequest(dc.WsConn, msgjson.MatchStatusRoute, reqs, &results, DefaultResponseTimeout)
+		if err != nil {
+			c.log.Errorf("match_status request error for %s market %s: %v", dc.acct.host, mkt.Name, err)
+			continue
+		}
+		for _, res := range results {
+			var mid order.MatchID
+			copy(mid[:], res.MatchID)
+			um := byID[mid]
+			if um == nil || um.rm.MarketID != "" {
+				continue
+			}
+			um.rm.MarketID = mkt.Name
+			c.scanUnknownMatch(um, mkt.Base, mkt.Quote, res)
+		}
+	}
+}
+
+// scanUnknownMatch looks for the match's swaps on-chain. Our swap is on the
+// wallet that finds a contract paying to the counterparty's address from the
+// match, and the counterparty's swap is on the wallet that owns the address
+// its contract pays to.
+func (c *Core) scanUnknownMatch(um *unknownMatch, base, quote uint32, res *msgjson.MatchStatusResult) {
+	rm := um.rm
+	coinID, contract, txData, redeem := res.MakerSwap, res.MakerContract, res.MakerTxData, res.MakerRedeem
+	counterCoinID, counterContract, counterTxData := res.TakerSwap, res.TakerContract, res.TakerTxData
+	if rm.Side == order.Taker {
+		coinID, contract, txData, redeem = res.TakerSwap, res.TakerContract, res.TakerTxData, res.TakerRedeem
+		counterCoinID, counterContract, counterTxData = res.MakerSwap, res.MakerContract, res.MakerTxData
+	}
+	rm.Secret = res.Secret
+	matchTime := time.UnixMilli(int64(um.msg.ServerTime))
+
+	ctx, cancel := context.WithTimeout(c.ctx, 10*time.Second)
+	defer cancel()
+	for _, assetID := range []uint32{base, quote} {
+		w, found := c.wallet(assetID)
+		if !found || !w.connected() {
+			continue
+		}
+		if rm.Swap == nil && len(coinID) > 0 {
+			auditInfo, err := w.AuditContract(coinID, contract, txData, false)
+			if err == nil && auditInfo.Recipient == um.msg.Address {
+				rm.Swap = recoveredSwap(assetID, coinID, auditInfo)
+				_, spent, err := w.SwapConfirmations(ctx, coinID, contract, matchTime)
+				if err != nil {
+					c.log.Errorf("Error checking %s swap %s of match %s: %v",
+						unbip(assetID), rm.Swap.Coin.StringID, rm.MatchID, err)
+					continue
+				}
+				rm.Swap.Spent = spent
+				expired, err := w.LockTimeExpired(ctx, auditInfo.Expiration)
+				if err != nil {
+					c.log.Errorf("Error checking lock time of %s swap %s of match %s: %v",
+						unbip(assetID), rm.Swap.Coin.StringID, rm.MatchID, err)
+					continue
+				}
+				rm.Refundable = !spent && expired
+				continue
+			}
+		}
+		if rm.CounterSwap == nil && len(counterCoinID) > 0 {
+			auditInfo, err := w.AuditContract(counterCoinID, counterContract, counterTxData, false)
+			if err != nil {
+				continue
+			}
+			if owns, err := w.OwnsDepositAddress(auditInfo.Recipient); err == nil && owns {
+				rm.CounterSwap = recoveredSwap(assetID, counterCoinID, auditInfo)
+				rm.Redeemable = len(rm.Secret) > 0 && len(redeem) == 0
+			}
+		}
+	}
+}
+
+func recoveredSwap(assetID uint32, coinID dex.Bytes, auditInfo *asset.AuditInfo) *RecoveredSwap {
+	return &RecoveredSwap{
+		Coin:       NewCoin(assetID, coinID),
+		Contract:   auditInfo.Contract,
+		SecretHash: auditInfo.SecretHash,
+		LockTime:   uint64(auditInfo.Expiration.UnixMilli()),
+	}
+}
+
+// reportUnknownMatch adds the manual actions for a match that was not in the
+// restored database.
+func reportUnknownMatch(report *RecoveryReport, host string, rm *RecoveredMatch) {
+	if rm.Redeemable {
+		swap := rm.CounterSwap
+		report.needsAction("Redeem the counterparty's %s swap %s of match %s on %s with secret %s and contract %s.",
+			swap.Coin.Symbol, swap.Coin.StringID, rm.MatchID, host, rm.Secret, swap.Contract)
+	}
+	if swap := rm.Swap; swap != nil && !swap.Spent {
+		if rm.Refundable {
+			report.needsAction("Refund our %s swap %s of match %s on %s with contract %s. Its lock time has expired.",
+				swap.Coin.Symbol, swap.Coin.StringID, rm.MatchID, host, swap.Contract)
+		} else {
+			report.needsAction("Refund our %s swap %s of match %s on %s with contract %s after %s, "+
+				"unless the counterparty redeems it first.", swap.Coin.Symbol, swap.Coin.StringID, rm.MatchID, host,
+				swap.Contract, time.UnixMilli(int64(swap.LockTime)).UTC().Format(time.RFC3339))
+		}
+	}
+	if rm.Swap == nil && rm.CounterSwap == nil {
+		report.needsAction("Match %s of order %s on %s is active in status %s, but its swaps were not found "+
+			"on-chain. Any swap that was broadcast for it must be redeemed or refunded manually once the "+
+			"wallets have synced.", rm.MatchID, rm.OrderID, host, rm.Status)
+	}
+}
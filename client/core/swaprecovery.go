@@ -0,0 +1,396 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+)
+
+// SwapRecoveryAction is a manual action that can be taken to resolve a match
+// that is not progressing on its own.
+type SwapRecoveryAction string
+
+const (
+	// SwapRecoveryRefund refunds our swap contract. It is available once the
+	// contract's lock time has expired and neither party has redeemed.
+	SwapRecoveryRefund SwapRecoveryAction = "refund"
+	// SwapRecoveryRedeem redeems the counterparty's swap contract. As maker,
+	// the secret is known. As taker, the secret must be known from the maker's
+	// redemption, or be provided.
+	SwapRecoveryRedeem SwapRecoveryAction = "redeem"
+	// SwapRecoveryAbandon stops all further action on the match. Any funds
+	// locked in our swap contract must then be recovered outside of the app.
+	SwapRecoveryAbandon SwapRecoveryAction = "abandon"
+)
+
+// errMatchAbandoned is set as the swap and refund error of an abandoned match
+// so that no further swap, redeem, or refund is attempted.
+var errMatchAbandoned = errors.New("match abandoned")
+
+// SwapRecoveryOption is an action available for a TroubledMatch.
+type SwapRecoveryOption struct {
+	Action SwapRecoveryAction `json:"action"`
+	// Description explains the consequences of the action, so that the user
+	// can confirm it.
+	Description string `json:"description"`
+	// NeedsSecret is true for a taker redeem when the maker's redemption has
+	// not been found, so the secret must be provided.
+	NeedsSecret bool `json:"needsSecret,omitempty"`
+}
+
+// TroubledMatch is a match that is not progressing on its own and may need
+// manual action.
+type TroubledMatch struct {
+	Host      string            `json:"host"`
+	MarketID  string            `json:"marketID"`
+	OrderID   dex.Bytes         `json:"orderID"`
+	MatchID   dex.Bytes         `json:"matchID"`
+	Status    order.MatchStatus `json:"status"`
+	Side      order.MatchSide   `json:"side"`
+	Qty       uint64            `json:"qty"`
+	Rate      uint64            `json:"rate"`
+	FromAsset uint32            `json:"fromAsset"`
+	ToAsset   uint32            `json:"toAsset"`
+	Revoked   bool              `json:"revoked"`
+	// LockTime is when our swap contract can be refunded (ms UNIX). Zero if
+	// we have not broadcast a swap or the lock time is unknown.
+	LockTime uint64 `json:"lockTime,omitempty"`
+	// Problems describes why the match is considered troubled.
+	Problems []string              `json:"problems"`
+	Options  []*SwapRecoveryOption `json:"options"`
+}
+
+// SwapRecoveryForm is the input to RecoverSwap.
+type SwapRecoveryForm struct {
+	OrderID dex.Bytes          `json:"orderID"`
+	MatchID dex.Bytes          `json:"matchID"`
+	Action  SwapRecoveryAction `json:"action"`
+	// Secret is the swap secret for a taker redeem when the maker's
+	// redemption has not been found.
+	Secret dex.Bytes `json:"secret,omitempty"`
+}
+
+// TroubledMatches lists the active matches that are not progressing on their
+// own, with the actions available to resolve them. A match is troubled if a
+// swap or redeem has failed, a refund has failed, the match is revoked with
+// our funds still in a swap contract, the swap contract has expired, or the
+// server or market is no longer reachable.
+func (c *Core) TroubledMatches() ([]*TroubledMatch, error) {
+	troubled := make([]*TroubledMatch, 0)
+	for _, dc := range c.dexConnections() {
+		for _, t := range dc.trackedTrades() {
+			troubled = append(troubled, c.troubledTradeMatches(t)...)
+		}
+	}
+	sort.Slice(troubled, func(i, j int) bool {
+		return troubled[i].LockTime < troubled[j].LockTime
+	})
+	return troubled, nil
+}
+
+// troubledTradeMatches finds the troubled matches for the trade. The lock
+// times of our swap contracts are checked after the trade's mutex is released,
+// since the wallet may take a while to respond.
+func (c *Core) troubledTradeMatches(t *trackedTrade) []*TroubledMatch {
+	t.mtx.RLock()
+	var checks []*troubledMatchCheck
+	for _, match := range t.matches {
+		if match.Address == "" || !t.matchIsActive(match) {
+			continue // cancel match or done
+		}
+		checks = append(checks, c.checkTroubledMatch(t, match))
+	}
+	t.mtx.RUnlock()
+
+	var troubled []*TroubledMatch
+	for _, chk := range checks {
+		ctx, cancel := context.WithTimeout(c.ctx, 10*time.Second)
+		tm := c.troubledMatch(ctx, t, chk)
+		cancel()
+		if tm != nil {
+			troubled = append(troubled, tm)
+		}
+	}
+	return troubled
+}
+
+// troubledMatchCheck is the state of a match needed to decide if it is
+// troubled, collected with the trackedTrade mutex held.
+type troubledMatchCheck struct {
+	tm *TroubledMatch
+	// contract is our swap contract, if our funds may still be in it.
+	contract    []byte
+	refundable  bool
+	canRedeem   bool
+	needsSecret bool
+}
+
+// checkTroubledMatch collects the match's problems that are known without
+// querying the wallets.
+//
+// This method accesses match fields and MUST be called with the trackedTrade
+// mutex lock held for reads.
+func (c *Core) checkTroubledMatch(t *trackedTrade, match *matchTracker) *troubledMatchCheck {
+	proof := &match.MetaData.Proof
+	tm := &TroubledMatch{
+		Host:      t.dc.acct.host,
+		MarketID:  t.mktID,
+		OrderID:   t.ID().Bytes(),
+		MatchID:   match.MatchID[:],
+		Status:    match.Status,
+		Side:      match.Side,
+		Qty:       match.Quantity,
+		Rate:      match.Rate,
+		FromAsset: t.wallets.fromWallet.AssetID,
+		ToAsset:   t.wallets.toWallet.AssetID,
+		Revoked:   proof.IsRevoked(),
+		Problems:  make([]string, 0),
+		Options:   make([]*SwapRecoveryOption, 0),
+	}
+
+	if match.swapErr != nil {
+		tm.Problems = append(tm.Problems, fmt.Sprintf("swap failed: %v", match.swapErr))
+	}
+	if match.refundErr != nil {
+		tm.Problems = append(tm.Problems, fmt.Sprintf("refund failed: %v", match.refundErr))
+	}
+	if match.redeemErrCount > 0 {
+		tm.Problems = append(tm.Problems, fmt.Sprintf("%d failed redeem attempts", match.redeemErrCount))
+	}
+	if match.redemptionRejected {
+		tm.Problems = append(tm.Problems, "redeem transaction rejected")
+	}
+	if t.isSelfGoverned() {
+		tm.Problems = append(tm.Problems, "the server or market is not reachable")
+	}
+
+	chk := &troubledMatchCheck{
+		tm:         tm,
+		refundable: refundableStatus(match),
+	}
+	chk.canRedeem, chk.needsSecret = redeemableStatus(match)
+	if len(proof.ContractData) > 0 && len(proof.RefundCoin) == 0 && match.Status < order.MakerRedeemed {
+		chk.contract = proof.ContractData
+		if tm.Revoked {
+			tm.Problems = append(tm.Problems, "match revoked with funds in our swap contract")
+		}
+	}
+	return chk
+}
+
+// troubledMatch checks the lock time of our swap contract and lists the
+// recovery options for the match. A nil *TroubledMatch is returned if there
+// are no problems. The trackedTrade mutex should not be held.
+func (c *Core) troubledMatch(ctx context.Context, t *trackedTrade, chk *troubledMatchCheck) *TroubledMatch {
+	tm := chk.tm
+	haveSwap := len(chk.contract) > 0
+	if haveSwap {
+		expired, lockTime, err := t.wallets.fromWallet.ContractLockTimeExpired(ctx, chk.contract)
+		if err != nil {
+			c.log.Debugf("Error checking lock time of match %s: %v", tm.MatchID, err)
+		} else {
+			tm.LockTime = uint64(lockTime.UnixMilli())
+			if expired {
+				tm.Problems = append(tm.Problems, "swap contract lock time expired")
+				if chk.refundable {
+					tm.Options = append(tm.Options, &SwapRecoveryOption{
+						Action: SwapRecoveryRefund,
+						Description: fmt.Sprintf("Refund %s from our swap contract. The trade will not complete.",
+							unbip(tm.FromAsset)),
+					})
+				}
+			}
+		}
+	}
+
+	if len(tm.Problems) == 0 {
+		return nil
+	}
+
+	if chk.canRedeem {
+		tm.Options = append(tm.Options, &SwapRecoveryOption{
+			Action:      SwapRecoveryRedeem,
+			Description: fmt.Sprintf("Redeem %s from the counterparty's swap contract.", unbip(tm.ToAsset)),
+			NeedsSecret: chk.needsSecret,
+		})
+	}
+
+	abandonDesc := "Stop all action on the match. It will no longer be tracked."
+	if haveSwap {
+		abandonDesc += fmt.Sprintf(" The %s in our swap contract must be refunded outside of the app.",
+			unbip(tm.FromAsset))
+	}
+	tm.Options = append(tm.Options, &SwapRecoveryOption{
+		Action:      SwapRecoveryAbandon,
+		Description: abandonDesc,
+	})
+	return tm
+}
+
+// refundableStatus checks if the match is in a status from which our swap can
+// be refunded. See refundMatches.
+func refundableStatus(match *matchTracker) bool {
+	proof := &match.MetaData.Proof
+	switch {
+	case match.Side == order.Maker && match.Status == order.MakerSwapCast,
+		match.Side == order.Maker && match.Status == order.TakerSwapCast && len(proof.MakerRedeem) == 0,
+		match.Side == order.Taker && match.Status == order.TakerSwapCast:
+		return true
+	}
+	return false
+}
+
+// redeemableStatus checks if the counterparty's swap can be redeemed. If
+// needsSecret is true, we are the taker and the secret is not yet known.
+func redeemableStatus(match *matchTracker) (canRedeem, needsSecret bool) {
+	proof := &match.MetaData.Proof
+	if match.counterSwap == nil || match.swapErr != nil || len(proof.RefundCoin) > 0 {
+		return false, false
+	}
+	if match.Side == order.Maker {
+		return match.Status == order.TakerSwapCast && len(proof.MakerRedeem) == 0, false
+	}
+	switch match.Status {
+	case order.TakerSwapCast:
+		return true, true
+	case order.MakerRedeemed:
+		return len(proof.TakerRedeem) == 0, false
+	}
+	return false, false
+}
+
+// RecoverSwap executes an action to resolve a troubled match. The action must
+// be one of the options listed for the match by TroubledMatches. The app
+// password is required as confirmation.
+func (c *Core) RecoverSwap(pw []byte, form *SwapRecoveryForm) error {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return fmt.Errorf("RecoverSwap password error: %w", err)
+	}
+	defer crypter.Close()
+
+	oid, err := order.IDFromBytes(form.OrderID)
+	if err != nil {
+		return err
+	}
+	if len(form.MatchID) != order.MatchIDSize {
+		return fmt.Errorf("invalid match ID length %d", len(form.MatchID))
+	}
+	var mid order.MatchID
+	copy(mid[:], form.MatchID)
+
+	t, err := c.findActiveOrder(oid)
+	if err != nil {
+		return err
+	}
+
+	// Unlock the wallet before locking the trade. Trades are not resumed,
+	// since that would lock the trade too.
+	var w *xcWallet
+	switch form.Action {
+	case SwapRecoveryRefund:
+		w = t.wallets.fromWallet
+	case SwapRecoveryRedeem:
+		w = t.wallets.toWallet
+	}
+	if w != nil {
+		if err := c.connectAndUnlockResumeTrades(crypter, w, false); err != nil {
+			return fmt.Errorf("error unlocking %s wallet: %w", unbip(w.AssetID), err)
+		}
+	}
+
+	// Don't act concurrently with a tick. The lock time of our swap contract
+	// is checked before locking the trade, since the wallet may take a while
+	// to respond.
+	t.tickLock.Lock()
+	defer t.tickLock.Unlock()
+	t.mtx.RLock()
+	match, found := t.matches[mid]
+	var chk *troubledMatchCheck
+	if found && t.matchIsActive(match) {
+		chk = c.checkTroubledMatch(t, match)
+	}
+	t.mtx.RUnlock()
+	if !found {
+		return fmt.Errorf("match %s not found for order %s", mid, oid)
+	}
+	if chk == nil {
+		return fmt.Errorf("match %s is not active", mid)
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, 10*time.Second)
+	tm := c.troubledMatch(ctx, t, chk)
+	cancel()
+	if tm == nil {
+		return fmt.Errorf("match %s does not need recovery", mid)
+	}
+	var opt *SwapRecoveryOption
+	for _, o := range tm.Options {
+		if o.Action == form.Action {
+			opt = o
+			break
+		}
+	}
+	if opt == nil {
+		return fmt.Errorf("action %q is not available for match %s", form.Action, mid)
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	switch form.Action {
+	case SwapRecoveryRefund:
+		refunded, err := c.refundMatches(t, []*matchTracker{match})
+		if err != nil {
+			return fmt.Errorf("refund error: %w", err)
+		}
+		if len(match.MetaData.Proof.RefundCoin) == 0 {
+			return fmt.Errorf("match %s was not refunded", mid)
+		}
+		c.log.Infof("Manually refunded %d %s for match %s", refunded, unbip(tm.FromAsset), mid)
+	case SwapRecoveryRedeem:
+		if opt.NeedsSecret {
+			if len(form.Secret) == 0 {
+				return errors.New("the swap secret is required to redeem")
+			}
+			proof := &match.MetaData.Proof
+			if !t.wallets.toWallet.ValidateSecret(form.Secret, proof.SecretHash) {
+				return fmt.Errorf("secret does not hash to the swap's secret hash %x", proof.SecretHash)
+			}
+			proof.Secret = form.Secret
+		}
+		// The redeem is attempted even if a previous attempt failed.
+		match.suspectRedeem = true
+		if err := c.redeemMatches(t, []*matchTracker{match}); err != nil {
+			return fmt.Errorf("redeem error: %w", err)
+		}
+		c.log.Infof("Manually redeemed match %s", mid)
+	case SwapRecoveryAbandon:
+		if match.cancelRedemptionSearch != nil {
+			match.cancelRedemptionSearch()
+		}
+		match.swapErr = errMatchAbandoned
+		match.refundErr = errMatchAbandoned
+		// The match keeps its status. The Abandoned flag makes it inactive, so
+		// that it is not loaded from the database again.
+		match.MetaData.Proof.Abandoned = true
+		if err := t.db.UpdateMatch(&match.MetaMatch); err != nil {
+			return fmt.Errorf("error storing abandoned match: %w", err)
+		}
+		c.log.Warnf("Match %s of order %s abandoned by the user", mid, oid)
+	default:
+		return fmt.Errorf("unknown action %q", form.Action)
+	}
+
+	t.notify(newOrderNote(TopicOrderStatusUpdate, "", "", db.Data, t.coreOrderInternal()))
+	return nil
+}
@@ -621,6 +621,69 @@ func (t *trackedTrade) verifyCSum(vsum dex.Bytes, epochIdx uint64) error {
 	return nil // includes not in epoch
 }
 
+// inEpoch checks whether the trade order or its linked cancel order was queued
+// in the epoch.
+func (t *trackedTrade) inEpoch(epochIdx uint64) bool {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	return epochIdx == t.epochIdx() || (t.cancel != nil && epochIdx == t.cancelEpochIdx())
+}
+
+// epochOrder is an order queued in an epoch, with its preimage.
+type epochOrder struct {
+	order.Order
+	pimg order.Preimage
+}
+
+// epochOrders returns the trade order and linked cancel order that were queued
+// in the epoch.
+func (t *trackedTrade) epochOrders(epochIdx uint64) []*epochOrder {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	var ords []*epochOrder
+	if epochIdx == t.epochIdx() {
+		ords = append(ords, &epochOrder{t.Order, t.preImg})
+	}
+	if t.cancel != nil && epochIdx == t.cancelEpochIdx() {
+		t.csumMtx.RLock()
+		pimg := t.cancelPreimg
+		t.csumMtx.RUnlock()
+		ords = append(ords, &epochOrder{&t.cancel.CancelOrder, pimg})
+	}
+	return ords
+}
+
+// receivedMatch is a match received from the server for the order with ID oid.
+type receivedMatch struct {
+	oid  order.OrderID
+	side order.MatchSide
+}
+
+// receivedMatches returns the matches received for the trade order and its
+// linked cancel order, keyed by match ID.
+func (t *trackedTrade) receivedMatches() map[order.MatchID]*receivedMatch {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	matches := make(map[order.MatchID]*receivedMatch, len(t.matches)+2)
+	for mid, match := range t.matches {
+		matches[mid] = &receivedMatch{match.OrderID, match.Side}
+	}
+	if t.cancel == nil {
+		return matches
+	}
+	for _, msgMatch := range []*msgjson.Match{t.cancel.matches.maker, t.cancel.matches.taker} {
+		if msgMatch == nil {
+			continue
+		}
+		var oid order.OrderID
+		copy(oid[:], msgMatch.OrderID)
+		var mid order.MatchID
+		copy(mid[:], msgMatch.MatchID)
+		matches[mid] = &receivedMatch{oid, order.MatchSide(msgMatch.Side)}
+	}
+	return matches
+}
+
 // rate returns the order's rate, or zero if a market or cancel order.
 func (t *trackedTrade) rate() uint64 {
 	if ord, ok := t.Order.(*order.LimitOrder); ok {
@@ -3811,6 +3874,69 @@ func (t *trackedTrade) orderAccelerationParameters() (swapCoins, accelerationCoi
 	return swapCoins, accelerationCoins, dex.Bytes(t.metaData.ChangeCoin), requiredForRemainingSwaps, nil
 }
 
+// isFeeBumpable checks whether either wallet of the trade can replace its
+// transactions at a higher fee rate.
+func (t *trackedTrade) isFeeBumpable() bool {
+	return t.wallets.fromWallet.traits.IsFeeBumper() || t.wallets.toWallet.traits.IsFeeBumper()
+}
+
+// feeBumpTarget is a wallet's transactions that can be replaced at a higher
+// fee rate.
+type feeBumpTarget struct {
+	wallet  *xcWallet
+	coinIDs []dex.Bytes
+}
+
+// feeBumpTargets returns the swap transactions of the order for the swap
+// wallet and the redemption transactions for the redeem wallet, if the wallets
+// are FeeBumpers. The transactions may already be mined, which the wallets
+// account for.
+//
+// This method accesses match fields and MUST be called with the trackedTrade
+// mutex lock held for reads.
+func (t *trackedTrade) feeBumpTargets() ([]*feeBumpTarget, error) {
+	var swapCoins, redeemCoins []dex.Bytes
+	for _, match := range t.matches {
+		if match.Status == order.MatchConfirmed || match.Address == "" {
+			continue
+		}
+		proof := &match.MetaData.Proof
+		var swapCoinID, redeemCoinID order.CoinID
+		if match.Side == order.Maker {
+			swapCoinID, redeemCoinID = proof.MakerSwap, proof.MakerRedeem
+		} else {
+			swapCoinID, redeemCoinID = proof.TakerSwap, proof.TakerRedeem
+		}
+		if len(swapCoinID) > 0 && len(proof.RefundCoin) == 0 {
+			swapCoins = append(swapCoins, dex.Bytes(swapCoinID))
+		}
+		if len(redeemCoinID) > 0 {
+			redeemCoins = append(redeemCoins, dex.Bytes(redeemCoinID))
+		}
+	}
+	// A single fee rate is used for all targets, so both wallets must pay
+	// fees on the same chain.
+	feeAssetID := func(w *xcWallet) uint32 {
+		if w.parent != nil {
+			return w.parent.AssetID
+		}
+		return w.AssetID
+	}
+	var targets []*feeBumpTarget
+	fromWallet, toWallet := t.wallets.fromWallet, t.wallets.toWallet
+	if fromWallet.traits.IsFeeBumper() && len(swapCoins) > 0 {
+		targets = append(targets, &feeBumpTarget{wallet: fromWallet, coinIDs: swapCoins})
+	}
+	if toWallet.traits.IsFeeBumper() && len(redeemCoins) > 0 &&
+		(len(targets) == 0 || feeAssetID(fromWallet) == feeAssetID(toWallet)) {
+		targets = append(targets, &feeBumpTarget{wallet: toWallet, coinIDs: redeemCoins})
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("order has no swap or redemption transactions that can be accelerated")
+	}
+	return targets, nil
+}
+
 func (t *trackedTrade) likelyTaker(midGap uint64) bool {
 	if t.Type() == order.MarketOrderType {
 		return true
@@ -0,0 +1,158 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+
+	"decred.org/dcrdex/client/db"
+)
+
+// tradingLimitWarnRatio is the fraction of the parcel limit in use at which
+// the user is warned that they are approaching their trading limit.
+const tradingLimitWarnRatio = 0.8
+
+// TradingLimitStatus is the user's trading limit usage on a server.
+type TradingLimitStatus struct {
+	Host string `json:"host"`
+	// Tier is the effective tier, which the parcel limit is based on.
+	Tier int64 `json:"tier"`
+	// UsedParcels is the number of parcels in the user's active orders and
+	// settling matches.
+	UsedParcels uint32 `json:"usedParcels"`
+	// ParcelLimit is the number of parcels the user can trade at once.
+	ParcelLimit uint32 `json:"parcelLimit"`
+	// RemainingParcels is ParcelLimit less UsedParcels.
+	RemainingParcels uint32 `json:"remainingParcels"`
+	// RemainingLots is the approximate number of lots that can still be
+	// ordered on each market, keyed by market name. Orders that would
+	// exceed the limit are rejected by the server.
+	RemainingLots map[string]uint64 `json:"remainingLots"`
+	// Approaching is true if the user's usage is near or over the limit.
+	Approaching bool `json:"approaching"`
+}
+
+// TradingLimitStatus returns the user's trading limit usage on the server.
+// The same status is sent in TradingLimitNote notifications whenever it
+// changes.
+func (c *Core) TradingLimitStatus(host string) (*TradingLimitStatus, error) {
+	dc, _, err := c.dex(host)
+	if err != nil {
+		return nil, err
+	}
+	usedParcels, parcelLimit, err := c.TradingLimits(host)
+	if err != nil {
+		return nil, err
+	}
+	dc.acct.authMtx.RLock()
+	tier := dc.acct.rep.EffectiveTier()
+	dc.acct.authMtx.RUnlock()
+
+	status := &TradingLimitStatus{
+		Host:          dc.acct.host,
+		Tier:          tier,
+		UsedParcels:   usedParcels,
+		ParcelLimit:   parcelLimit,
+		RemainingLots: make(map[string]uint64),
+		Approaching:   usedParcels > 0 && float64(usedParcels) >= tradingLimitWarnRatio*float64(parcelLimit),
+	}
+	if parcelLimit > usedParcels {
+		status.RemainingParcels = parcelLimit - usedParcels
+	}
+	if cfg := dc.config(); cfg != nil {
+		for _, mkt := range cfg.Markets {
+			status.RemainingLots[mkt.Name] = uint64(status.RemainingParcels) * uint64(mkt.ParcelSize)
+		}
+	}
+	return status, nil
+}
+
+// TradingLimitStatuses returns the user's trading limit usage on every server
+// with an account.
+func (c *Core) TradingLimitStatuses() []*TradingLimitStatus {
+	statuses := make([]*TradingLimitStatus, 0)
+	for _, dc := range c.dexConnections() {
+		if dc.acct.isViewOnly() {
+			continue
+		}
+		status, err := c.TradingLimitStatus(dc.acct.host)
+		if err != nil {
+			c.log.Errorf("Error getting trading limits for %s: %v", dc.acct.host, err)
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// scheduleTradingLimitCheck signals watchTradingLimits to check the trading
+// limit usage on the server. Checks are done asynchronously because the
+// notifications that trigger them may be sent with trade mutexes held.
+func (c *Core) scheduleTradingLimitCheck(host string) {
+	if host == "" {
+		return
+	}
+	c.tradingLimitsMtx.Lock()
+	if c.tradingLimitsPending == nil {
+		c.tradingLimitsPending = make(map[string]bool)
+	}
+	c.tradingLimitsPending[host] = true
+	c.tradingLimitsMtx.Unlock()
+	select {
+	case c.tradingLimitsCheck <- struct{}{}:
+	default:
+	}
+}
+
+// watchTradingLimits checks the trading limit usage on servers as scheduled
+// by scheduleTradingLimitCheck, and sends a TradingLimitNote when it changes.
+// A warning is sent when the usage first reaches tradingLimitWarnRatio of the
+// limit.
+func (c *Core) watchTradingLimits(ctx context.Context) {
+	for {
+		select {
+		case <-c.tradingLimitsCheck:
+		case <-ctx.Done():
+			return
+		}
+		c.tradingLimitsMtx.Lock()
+		hosts := c.tradingLimitsPending
+		c.tradingLimitsPending = make(map[string]bool, len(hosts))
+		c.tradingLimitsMtx.Unlock()
+
+		for host := range hosts {
+			c.checkTradingLimits(host)
+		}
+	}
+}
+
+// checkTradingLimits sends notifications if the trading limit usage on the
+// server has changed since the last check.
+func (c *Core) checkTradingLimits(host string) {
+	status, err := c.TradingLimitStatus(host)
+	c.tradingLimitsMtx.Lock()
+	if c.tradingLimits == nil {
+		c.tradingLimits = make(map[string]*TradingLimitStatus)
+	}
+	prev := c.tradingLimits[host]
+	if err != nil {
+		// Probably removed.
+		delete(c.tradingLimits, host)
+		c.tradingLimitsMtx.Unlock()
+		c.log.Debugf("Not checking trading limits for %s: %v", host, err)
+		return
+	}
+	c.tradingLimits[host] = status
+	c.tradingLimitsMtx.Unlock()
+
+	if prev != nil && prev.UsedParcels == status.UsedParcels &&
+		prev.ParcelLimit == status.ParcelLimit && prev.Tier == status.Tier {
+		return
+	}
+	c.notify(newTradingLimitNote(TopicTradingLimitUpdate, "", "", db.Data, status))
+	if status.Approaching && (prev == nil || !prev.Approaching) {
+		subject, details := c.formatDetails(TopicTradingLimitApproaching, status.Host, status.UsedParcels, status.ParcelLimit)
+		c.notify(newTradingLimitNote(TopicTradingLimitApproaching, subject, details, db.WarningLevel, status))
+	}
+}
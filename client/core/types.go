@@ -140,6 +140,64 @@ type WalletState struct {
 	FeeState     *FeeState                       `json:"feeState"`
 }
 
+// WalletSyncProgress is the progress of a wallet sync or rescan, reported in
+// the same terms for every asset.
+type WalletSyncProgress struct {
+	AssetID uint32 `json:"assetID"`
+	// Rescan is true for a rescan requested with RescanWallet, and false for
+	// the wallet syncing with the network.
+	Rescan bool `json:"rescan"`
+	Done   bool `json:"done"`
+	// Progress is the fraction complete, from 0 to 1.
+	Progress     float32 `json:"progress"`
+	StartHeight  uint64  `json:"startHeight"`
+	Height       uint64  `json:"height"`
+	TargetHeight uint64  `json:"targetHeight"`
+	// Started is when the sync or rescan started, and LastProgress is when the
+	// height last increased, in unix milliseconds.
+	Started      uint64 `json:"started"`
+	LastProgress uint64 `json:"lastProgress"`
+	// Stalled is true if the height has not increased for a long time, which
+	// may mean the sync or rescan is stuck.
+	Stalled bool `json:"stalled"`
+	// ETA is the estimated number of seconds remaining, based on the rate of
+	// progress so far, or zero if there isn't enough progress to estimate.
+	ETA uint64 `json:"eta"`
+}
+
+// FeeReport is the on-chain transaction fees paid by the wallets in a time
+// range, by the asset the fees were paid in.
+type FeeReport struct {
+	// From and To are the time range, in unix milliseconds. Zero is
+	// unbounded.
+	From   uint64       `json:"from"`
+	To     uint64       `json:"to"`
+	Assets []*AssetFees `json:"assets"`
+	// Unavailable are the assets whose wallets could not provide their
+	// transaction history, because they are not connected or do not support
+	// it. Their fees are not in the report.
+	Unavailable []uint32 `json:"unavailable"`
+}
+
+// AssetFees is the fees paid in an asset. Fees for token transactions are
+// paid in the parent asset.
+type AssetFees struct {
+	AssetID uint32 `json:"assetID"`
+	Symbol  string `json:"symbol"`
+	// Total is the sum of the fees, in the asset's atomic units.
+	Total uint64 `json:"total"`
+	// Count is the number of transactions.
+	Count int `json:"count"`
+	// ByType is the fees by transaction type, e.g. swap, redeem, send.
+	ByType map[string]*TxTypeFees `json:"byType"`
+}
+
+// TxTypeFees is the fees paid for a type of transaction.
+type TxTypeFees struct {
+	Total uint64 `json:"total"`
+	Count int    `json:"count"`
+}
+
 // FeeState is information about the current network transaction fees and
 // estimates of standard operations.
 type FeeState struct {
@@ -208,6 +266,16 @@ type SupportedAsset struct {
 	WalletCreationPending bool `json:"walletCreationPending"`
 }
 
+// AnnotationForm is used to set the user's note and tags for an order, or for
+// one of its matches if MatchID is set. An empty Note and Tags remove the
+// annotation.
+type AnnotationForm struct {
+	OrderID dex.Bytes `json:"orderID"`
+	MatchID dex.Bytes `json:"matchID,omitempty"`
+	Note    string    `json:"note"`
+	Tags    []string  `json:"tags"`
+}
+
 // BondOptionsForm is used from the settings page to change the auto-bond
 // maintenance setting for a DEX.
 type BondOptionsForm struct {
@@ -247,6 +315,7 @@ type Match struct {
 	Status        order.MatchStatus `json:"status"`
 	Active        bool              `json:"active"`
 	Revoked       bool              `json:"revoked"`
+	Abandoned     bool              `json:"abandoned,omitempty"`
 	Rate          uint64            `json:"rate"`
 	Qty           uint64            `json:"qty"`
 	Side          order.MatchSide   `json:"side"`
@@ -258,6 +327,9 @@ type Match struct {
 	Refund        *Coin             `json:"refund,omitempty"`
 	Stamp         uint64            `json:"stamp"` // Server's time stamp - we have no local time recorded
 	IsCancel      bool              `json:"isCancel"`
+	// Note and Tags are the user's annotation of the match.
+	Note string   `json:"note,omitempty"`
+	Tags []string `json:"tags,omitempty"`
 }
 
 // Coin encodes both the coin ID and the asset-dependent string representation
@@ -368,6 +440,7 @@ func matchFromMetaMatchWithConfs(ord order.Order, metaMatch *db.MetaMatch, swapC
 		Status:        userMatch.Status,
 		Active:        db.MatchIsActive(userMatch, proof),
 		Revoked:       proof.IsRevoked(),
+		Abandoned:     proof.Abandoned,
 		Rate:          userMatch.Rate,
 		Qty:           userMatch.Quantity,
 		Side:          userMatch.Side,
@@ -380,6 +453,9 @@ func matchFromMetaMatchWithConfs(ord order.Order, metaMatch *db.MetaMatch, swapC
 		CounterRedeem: counterRedeem,
 		Refund:        refund,
 	}
+	if a := metaMatch.MetaData.Annotation; a != nil {
+		match.Note, match.Tags = a.Note, a.Tags
+	}
 
 	return match
 }
@@ -424,6 +500,9 @@ type Order struct {
 	TimeInForce       order.TimeInForce `json:"tif"`           // limit only
 	TargetOrderID     dex.Bytes         `json:"targetOrderID"` // cancel only
 	ReadyToTick       bool              `json:"readyToTick"`
+	// Note and Tags are the user's annotation of the order.
+	Note string   `json:"note,omitempty"`
+	Tags []string `json:"tags,omitempty"`
 }
 
 // InFlightOrder is an Order that is not stamped yet, but has a temporary ID
@@ -531,6 +610,9 @@ func coreOrderFromTrade(ord order.Order, metaData *db.OrderMetaData) *Order {
 		FundingCoins:      fundingCoins,
 		AccelerationCoins: accelerationCoins,
 	}
+	if a := metaData.Annotation; a != nil {
+		corder.Note, corder.Tags = a.Note, a.Tags
+	}
 
 	return corder
 }
@@ -703,6 +785,7 @@ type ExchangeAuth struct {
 // Exchange represents a single DEX with any number of markets.
 type Exchange struct {
 	Host             string                 `json:"host"`
+	AltHosts         []string               `json:"altHosts,omitempty"`
 	AcctID           string                 `json:"acctID"`
 	Markets          map[string]*Market     `json:"markets"`
 	Assets           map[uint32]*dex.Asset  `json:"assets"`
@@ -793,9 +876,51 @@ type CandlesPayload struct {
 	Candles      []msgjson.Candle `json:"candles"`
 }
 
+// IndicatorsForm is the information necessary to compute indicator series
+// for a market's candles.
+type IndicatorsForm struct {
+	Host    string `json:"host"`
+	Base    uint32 `json:"base"`
+	Quote   uint32 `json:"quote"`
+	BinSize string `json:"binSize"`
+	// SMA and EMA are the periods, in candles, of the simple and exponential
+	// moving averages to compute.
+	SMA []int `json:"sma"`
+	EMA []int `json:"ema"`
+	// Bollinger, if set, is the Bollinger band configuration.
+	Bollinger *BollingerForm `json:"bollinger,omitempty"`
+	// VolumeProfileBins is the number of rate bins for the volume profile. No
+	// volume profile is computed if zero.
+	VolumeProfileBins int `json:"volumeProfileBins"`
+}
+
+// BollingerForm configures a Bollinger band series.
+type BollingerForm struct {
+	Period  int     `json:"period"`
+	StdDevs float64 `json:"stdDevs"`
+}
+
+// IndicatorSeries is a market's candles along with the derived series
+// requested with an IndicatorsForm. Series values are in the units of the
+// candle rates. The SMA and EMA series are keyed by period.
+type IndicatorSeries struct {
+	Host          string                        `json:"host"`
+	MarketID      string                        `json:"marketID"`
+	BinSize       string                        `json:"binSize"`
+	DurMilliSecs  uint64                        `json:"ms"`
+	Candles       []msgjson.Candle              `json:"candles"`
+	SMA           map[int][]candles.SeriesPoint `json:"sma,omitempty"`
+	EMA           map[int][]candles.SeriesPoint `json:"ema,omitempty"`
+	Bollinger     []candles.BollingerPoint      `json:"bollinger,omitempty"`
+	VolumeProfile []candles.VolumeBin           `json:"volumeProfile,omitempty"`
+}
+
 type EpochMatchSummaryPayload struct {
 	MatchSummaries []*orderbook.MatchSummary `json:"matchSummaries"`
 	Epoch          uint64                    `json:"epoch"`
+	// OrderFlow is the order-flow statistics for the trades within
+	// orderFlowWindow, including this epoch's matches.
+	OrderFlow *orderbook.OrderFlowStats `json:"orderFlow"`
 }
 
 type ResolvedEpoch struct {
@@ -1128,6 +1253,7 @@ type OrderFilter struct {
 	Assets            []uint32            `json:"assets"`
 	Statuses          []order.OrderStatus `json:"statuses"`
 	CompletedOnly     bool                `json:"completedOnly"`
+	Text              string              `json:"text"`
 	Market            *struct {
 		Base  uint32 `json:"baseID"`
 		Quote uint32 `json:"quoteID"`
@@ -1180,3 +1306,16 @@ type PreAccelerate struct {
 	SuggestedRange    asset.XYRange            `json:"suggestedRange"`
 	EarlyAcceleration *asset.EarlyAcceleration `json:"earlyAcceleration,omitempty"`
 }
+
+// RestoredBackup describes the files written by Core.RestoreBackup.
+type RestoredBackup struct {
+	// Stamp is when the backup was created, in unix milliseconds.
+	Stamp uint64 `json:"stamp"`
+	// DBPath is the restored database, which should replace the current
+	// database while the application is stopped.
+	DBPath string `json:"dbPath"`
+	// WalletsPath is a JSON file of the wallet configurations.
+	WalletsPath string `json:"walletsPath"`
+	// Wallets is the number of wallet configurations restored.
+	Wallets int `json:"wallets"`
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -65,7 +66,9 @@ type xcWallet struct {
 	monitored  uint32 // startWalletSyncMonitor goroutines monitoring sync status
 	hookedUp   bool
 	syncStatus *asset.SyncStatus
-	disabled   bool
+	// syncTracker follows the current or most recent sync or rescan.
+	syncTracker *syncTracker
+	disabled    bool
 
 	// When wallets are being reconfigured and especially when the wallet type
 	// or host is being changed, we want to suppress "walletstate" notes to
@@ -462,6 +465,85 @@ func (w *xcWallet) Disconnect() {
 	w.mtx.Unlock()
 }
 
+// syncHeights are the start, current and target heights of a sync or rescan.
+// These come from the wallet's RescanProgress if it is an
+// asset.RescanProgresser with a rescan running, otherwise from the SyncStatus.
+func (w *xcWallet) syncHeights(ss *asset.SyncStatus) (start, height, target uint64) {
+	if rp, is := w.Wallet.(asset.RescanProgresser); is {
+		if p := rp.RescanProgress(); p != nil {
+			return p.StartHeight, p.Height, p.TargetHeight
+		}
+	}
+	return ss.StartingBlocks, ss.Blocks, ss.TargetHeight
+}
+
+// syncStallTimeout is how long a sync or rescan can go without progress before
+// it is reported as stalled.
+const syncStallTimeout = 5 * time.Minute
+
+// syncTracker follows a wallet sync or rescan to report its progress and
+// estimate the time remaining.
+type syncTracker struct {
+	rescan                            bool
+	started                           time.Time
+	done                              bool
+	startHeight, height, targetHeight uint64
+	lastProgress                      time.Time
+	// firstHeight and firstStamp are the first observed height and when it
+	// was observed, for the rate of progress.
+	firstHeight uint64
+	firstStamp  time.Time
+}
+
+func newSyncTracker(rescan bool, now time.Time) *syncTracker {
+	return &syncTracker{
+		rescan:       rescan,
+		started:      now,
+		lastProgress: now,
+	}
+}
+
+// update records the latest heights.
+func (t *syncTracker) update(start, height, target uint64, synced bool, now time.Time) {
+	if t.firstStamp.IsZero() {
+		t.firstHeight, t.firstStamp = height, now
+	} else if height > t.height {
+		t.lastProgress = now
+	}
+	t.startHeight, t.height, t.targetHeight = start, height, target
+	t.done = synced
+}
+
+// progress generates a WalletSyncProgress.
+func (t *syncTracker) progress(assetID uint32, now time.Time) *WalletSyncProgress {
+	p := &WalletSyncProgress{
+		AssetID:      assetID,
+		Rescan:       t.rescan,
+		Done:         t.done,
+		StartHeight:  t.startHeight,
+		Height:       t.height,
+		TargetHeight: t.targetHeight,
+		Started:      uint64(t.started.UnixMilli()),
+		LastProgress: uint64(t.lastProgress.UnixMilli()),
+	}
+	if t.done {
+		p.Progress = 1
+		return p
+	}
+	if t.targetHeight > t.startHeight && t.height > t.startHeight {
+		p.Progress = float32(t.height-t.startHeight) / float32(t.targetHeight-t.startHeight)
+		if p.Progress > 1 {
+			p.Progress = 1
+		}
+	}
+	p.Stalled = now.Sub(t.lastProgress) > syncStallTimeout
+	if elapsed := now.Sub(t.firstStamp).Seconds(); t.height > t.firstHeight && t.targetHeight > t.height && elapsed > 0 {
+		rate := float64(t.height-t.firstHeight) / elapsed // blocks per second
+		p.ETA = uint64(math.Ceil(float64(t.targetHeight-t.height) / rate))
+	}
+	return p
+}
+
 // rescan will initiate a rescan of the wallet if the asset.Wallet
 // implementation is a Rescanner.
 func (w *xcWallet) rescan(ctx context.Context, bday /* unix time seconds*/ uint64) error {
@@ -535,6 +617,52 @@ func (w *xcWallet) preAccelerate(swapCoins, accelerationCoins []dex.Bytes, chang
 	return accelerator.PreAccelerate(swapCoins, accelerationCoins, changeCoin, requiredForRemainingSwaps, feeSuggestion)
 }
 
+// feeBumper returns the wallet as an asset.FeeBumper if it is connected and
+// enabled.
+func (w *xcWallet) feeBumper() (asset.FeeBumper, error) {
+	if w.isDisabled() { // cannot replace transactions with disabled wallet.
+		return nil, fmt.Errorf(walletDisabledErrStr, strings.ToUpper(unbip(w.AssetID)))
+	}
+	if !w.connected() {
+		return nil, errWalletNotConnected
+	}
+	bumper, ok := w.Wallet.(asset.FeeBumper)
+	if !ok {
+		return nil, errors.New("wallet does not support fee bumping")
+	}
+	return bumper, nil
+}
+
+// bumpFees replaces unmined transactions at a higher fee rate if the wallet
+// is a FeeBumper.
+func (w *xcWallet) bumpFees(coinIDs []dex.Bytes, newFeeRate uint64) ([]string, error) {
+	bumper, err := w.feeBumper()
+	if err != nil {
+		return nil, err
+	}
+	return bumper.BumpFees(coinIDs, newFeeRate)
+}
+
+// bumpFeesEstimate estimates the cost to replace unmined transactions at a
+// higher fee rate if the wallet is a FeeBumper.
+func (w *xcWallet) bumpFeesEstimate(coinIDs []dex.Bytes, newFeeRate uint64) (uint64, error) {
+	bumper, err := w.feeBumper()
+	if err != nil {
+		return 0, err
+	}
+	return bumper.BumpFeesEstimate(coinIDs, newFeeRate)
+}
+
+// preBumpFees gives the user information about replacing unmined
+// transactions if the wallet is a FeeBumper.
+func (w *xcWallet) preBumpFees(coinIDs []dex.Bytes, feeSuggestion uint64) (uint64, *asset.XYRange, *asset.EarlyAcceleration, error) {
+	bumper, err := w.feeBumper()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return bumper.PreBumpFees(coinIDs, feeSuggestion)
+}
+
 // swapConfirmations calls (asset.Wallet).SwapConfirmations with a timeout
 // Context. If the coin cannot be located, an asset.CoinNotFoundError is
 // returned. If the coin is located, but recognized as spent, no error is
@@ -660,6 +788,26 @@ func (w *xcWallet) ApprovalFee(assetVersion uint32, approval bool) (uint64, erro
 	return approver.ApprovalFee(assetVersion, approval)
 }
 
+// TokenAllowances returns the allowances granted to each version of the
+// token's swap contract if the wallet is a TokenAllowanceManager.
+func (w *xcWallet) TokenAllowances() ([]*asset.TokenAllowance, error) {
+	am, ok := w.Wallet.(asset.TokenAllowanceManager)
+	if !ok {
+		return nil, fmt.Errorf("%s wallet is not a TokenAllowanceManager", unbip(w.AssetID))
+	}
+	return am.TokenAllowances()
+}
+
+// SetTokenAllowance sends an approval transaction setting the allowance if the
+// wallet is a TokenAllowanceManager.
+func (w *xcWallet) SetTokenAllowance(assetVersion uint32, allowance uint64, onConfirm func()) (string, error) {
+	am, ok := w.Wallet.(asset.TokenAllowanceManager)
+	if !ok {
+		return "", fmt.Errorf("%s wallet is not a TokenAllowanceManager", unbip(w.AssetID))
+	}
+	return am.SetTokenAllowance(assetVersion, allowance, onConfirm)
+}
+
 // ApprovalStatus returns the approval status of each version of the asset if
 // the wallet is a TokenApprover.
 func (w *xcWallet) ApprovalStatus() map[uint32]asset.ApprovalStatus {
@@ -0,0 +1,401 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package ctlsock is a local control interface for bisonw over a unix socket.
+// It is intended for headless installs managed by a service manager such as
+// systemd, and is independent of the RPC server. Access is controlled by the
+// socket file's permissions, which only allow the user running bisonw.
+//
+// Each request is a line of JSON, e.g. {"cmd":"unlock","args":["apppass"]},
+// and is answered with a line of JSON with either a result or an error. A
+// connection can make any number of requests. The commands are:
+//
+//	status: The app, exchange, and wallet status. See Status.
+//	lock: Log out, locking the wallets. Fails if there are active orders.
+//	unlock: Log in with the app password, unlocking the wallets.
+//	shutdown: Log out and stop bisonw. Fails if there are active orders,
+//	  unless the argument is "force".
+//	loglevel: Set the log level, e.g. "debug" or "CORE=trace,MM=debug".
+//	backup: Create an encrypted backup. The result is the backup's path.
+package ctlsock
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+)
+
+// Commands
+const (
+	CmdStatus   = "status"
+	CmdLock     = "lock"
+	CmdUnlock   = "unlock"
+	CmdShutdown = "shutdown"
+	CmdLogLevel = "loglevel"
+	CmdBackup   = "backup"
+)
+
+const (
+	// maxRequestSize is the longest request line that is read.
+	maxRequestSize = 1 << 16
+	// requestTimeout is how long a connection may be idle before it is
+	// closed.
+	requestTimeout = 5 * time.Minute
+)
+
+// clientCore is the Core methods used by the control server.
+type clientCore interface {
+	Network() dex.Network
+	IsInitialized() bool
+	LoggedIn() bool
+	Active() bool
+	Exchanges() map[string]*core.Exchange
+	Wallets() []*core.WalletState
+	Login(pw []byte) error
+	Logout() error
+	BackupNow() (string, error)
+}
+
+var _ clientCore = (*core.Core)(nil)
+
+// Request is a control request.
+type Request struct {
+	Cmd  string   `json:"cmd"`
+	Args []string `json:"args,omitempty"`
+}
+
+// Response is the response to a Request. Error is empty if the request was
+// successful.
+type Response struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// ExchangeStatus is the connection status of a DEX host.
+type ExchangeStatus struct {
+	Host       string `json:"host"`
+	Connection string `json:"connection"`
+	Disabled   bool   `json:"disabled"`
+}
+
+// WalletStatus is the status of a wallet.
+type WalletStatus struct {
+	Symbol       string  `json:"symbol"`
+	Running      bool    `json:"running"`
+	Unlocked     bool    `json:"unlocked"`
+	Synced       bool    `json:"synced"`
+	SyncProgress float32 `json:"syncProgress"`
+	Disabled     bool    `json:"disabled"`
+}
+
+// Status is the result of the status command.
+type Status struct {
+	Version      string            `json:"version"`
+	Net          string            `json:"net"`
+	Initialized  bool              `json:"initialized"`
+	LoggedIn     bool              `json:"loggedIn"`
+	ActiveOrders bool              `json:"activeOrders"`
+	Exchanges    []*ExchangeStatus `json:"exchanges"`
+	Wallets      []*WalletStatus   `json:"wallets"`
+}
+
+// Config is the configuration for a Server.
+type Config struct {
+	// Path is the path of the socket file.
+	Path string
+	Core clientCore
+	// Version is the app version reported by the status command.
+	Version string
+	// Shutdown stops the app.
+	Shutdown func()
+	// SetLogLevel sets the app's log level. See dex.LoggerMaker.UpdateLevels.
+	SetLogLevel func(debugLevel string) error
+	Logger      dex.Logger
+}
+
+// Server is the control server. Server satisfies dex.Connector.
+type Server struct {
+	cfg *Config
+	log dex.Logger
+}
+
+var _ dex.Connector = (*Server)(nil)
+
+// New is the constructor for a Server.
+func New(cfg *Config) (*Server, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("no control socket path")
+	}
+	if cfg.Core == nil || cfg.Shutdown == nil || cfg.SetLogLevel == nil {
+		return nil, errors.New("incomplete control server configuration")
+	}
+	return &Server{cfg: cfg, log: cfg.Logger}, nil
+}
+
+// Connect starts listening on the socket. The socket file is removed when the
+// context is canceled.
+func (s *Server) Connect(ctx context.Context) (*sync.WaitGroup, error) {
+	// A socket file left by an unclean shutdown would prevent listening.
+	if fi, err := os.Lstat(s.cfg.Path); err == nil {
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", s.cfg.Path)
+		}
+		if err := os.Remove(s.cfg.Path); err != nil {
+			return nil, fmt.Errorf("error removing old control socket: %w", err)
+		}
+	}
+	ln, err := listen(s.cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on control socket: %w", err)
+	}
+	s.log.Infof("Control socket listening on %s", s.cfg.Path)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		ln.Close()
+		os.Remove(s.cfg.Path)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if ctx.Err() == nil {
+					s.log.Errorf("Control socket accept error: %v", err)
+				}
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.serve(ctx, conn)
+			}()
+		}
+	}()
+	return &wg, nil
+}
+
+// listen listens on a unix socket at path that only the current user may
+// connect to. The socket is created in a new directory with 0700 permissions,
+// so nobody else can connect before the socket's own permissions are
+// restricted, and is then moved to path.
+func listen(path string) (*net.UnixListener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".ctlsock")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmpPath := filepath.Join(dir, "s")
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmpPath, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// The socket file is moved, so the listener can't remove it.
+	ln.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("error setting permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// serve handles the requests on a connection until it is closed, it is idle
+// for too long, or the context is canceled.
+func (s *Server) serve(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 1024), maxRequestSize)
+	enc := json.NewEncoder(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(requestTimeout))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil && ctx.Err() == nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+				s.log.Debugf("Control socket read error: %v", err)
+			}
+			return
+		}
+		var req Request
+		var resp *Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = &Response{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else {
+			resp = s.handle(&req)
+		}
+		if err := enc.Encode(resp); err != nil {
+			s.log.Debugf("Control socket write error: %v", err)
+			return
+		}
+	}
+}
+
+// handle handles a request.
+func (s *Server) handle(req *Request) *Response {
+	if req.Cmd != CmdUnlock { // don't log the password
+		s.log.Debugf("Control socket request %q %v", req.Cmd, req.Args)
+	}
+	res, err := s.run(req)
+	if err != nil {
+		return &Response{Error: err.Error()}
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return &Response{Error: fmt.Sprintf("error encoding result: %v", err)}
+	}
+	return &Response{Result: b}
+}
+
+func (s *Server) run(req *Request) (any, error) {
+	nArgs := func(min, max int) error {
+		if n := len(req.Args); n < min || n > max {
+			return fmt.Errorf("%s: wrong number of arguments %d", req.Cmd, n)
+		}
+		return nil
+	}
+	c := s.cfg.Core
+	switch req.Cmd {
+	case CmdStatus:
+		if err := nArgs(0, 0); err != nil {
+			return nil, err
+		}
+		return s.status(), nil
+	case CmdLock:
+		if err := nArgs(0, 0); err != nil {
+			return nil, err
+		}
+		if err := c.Logout(); err != nil {
+			return nil, err
+		}
+		return "locked", nil
+	case CmdUnlock:
+		if err := nArgs(1, 1); err != nil {
+			return nil, err
+		}
+		pw := []byte(req.Args[0])
+		defer encode.ClearBytes(pw)
+		if err := c.Login(pw); err != nil {
+			return nil, err
+		}
+		return "unlocked", nil
+	case CmdShutdown:
+		if err := nArgs(0, 1); err != nil {
+			return nil, err
+		}
+		force := len(req.Args) == 1 && req.Args[0] == "force"
+		if len(req.Args) == 1 && !force {
+			return nil, fmt.Errorf("unknown shutdown argument %q", req.Args[0])
+		}
+		if err := c.Logout(); err != nil {
+			if !force || !errors.Is(err, core.ActiveOrdersLogoutErr) {
+				return nil, err
+			}
+			s.log.Warnf("Shutting down with active orders by control socket request")
+		}
+		s.log.Infof("Shutting down by control socket request")
+		s.cfg.Shutdown()
+		return "shutting down", nil
+	case CmdLogLevel:
+		if err := nArgs(1, 1); err != nil {
+			return nil, err
+		}
+		if err := s.cfg.SetLogLevel(req.Args[0]); err != nil {
+			return nil, err
+		}
+		s.log.Infof("Log level set to %q by control socket request", req.Args[0])
+		return "log level set", nil
+	case CmdBackup:
+		if err := nArgs(0, 0); err != nil {
+			return nil, err
+		}
+		return c.BackupNow()
+	}
+	return nil, fmt.Errorf("unknown command %q", req.Cmd)
+}
+
+func (s *Server) status() *Status {
+	c := s.cfg.Core
+	st := &Status{
+		Version:      s.cfg.Version,
+		Net:          c.Network().String(),
+		Initialized:  c.IsInitialized(),
+		LoggedIn:     c.LoggedIn(),
+		ActiveOrders: c.Active(),
+		Exchanges:    make([]*ExchangeStatus, 0),
+		Wallets:      make([]*WalletStatus, 0),
+	}
+	for _, xc := range c.Exchanges() {
+		st.Exchanges = append(st.Exchanges, &ExchangeStatus{
+			Host:       xc.Host,
+			Connection: xc.ConnectionStatus.String(),
+			Disabled:   xc.Disabled,
+		})
+	}
+	for _, w := range c.Wallets() {
+		st.Wallets = append(st.Wallets, &WalletStatus{
+			Symbol:       w.Symbol,
+			Running:      w.Running,
+			Unlocked:     w.Open,
+			Synced:       w.Synced,
+			SyncProgress: w.SyncProgress,
+			Disabled:     w.Disabled,
+		})
+	}
+	sort.Slice(st.Exchanges, func(i, j int) bool {
+		return st.Exchanges[i].Host < st.Exchanges[j].Host
+	})
+	return st
+}
+
+// Send sends a request to the control socket at path, and returns the result.
+func Send(ctx context.Context, path, cmd string, args ...string) (json.RawMessage, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to control socket: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if err := json.NewEncoder(conn).Encode(&Request{Cmd: cmd, Args: args}); err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Result, nil
+}
@@ -0,0 +1,221 @@
+package ctlsock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+)
+
+type tCore struct {
+	loggedIn  bool
+	active    bool
+	pw        string
+	logoutErr error
+	backupErr error
+}
+
+func (c *tCore) Network() dex.Network { return dex.Simnet }
+func (c *tCore) IsInitialized() bool  { return true }
+func (c *tCore) LoggedIn() bool       { return c.loggedIn }
+func (c *tCore) Active() bool         { return c.active }
+func (c *tCore) Exchanges() map[string]*core.Exchange {
+	return map[string]*core.Exchange{
+		"b.example.com": {Host: "b.example.com", ConnectionStatus: comms.Disconnected},
+		"a.example.com": {Host: "a.example.com", ConnectionStatus: comms.Connected},
+	}
+}
+func (c *tCore) Wallets() []*core.WalletState {
+	return []*core.WalletState{{Symbol: "dcr", Running: true, Open: c.loggedIn, Synced: true, SyncProgress: 1}}
+}
+func (c *tCore) Login(pw []byte) error {
+	if string(pw) != c.pw {
+		return errors.New("wrong password")
+	}
+	c.loggedIn = true
+	return nil
+}
+func (c *tCore) Logout() error {
+	if c.logoutErr != nil {
+		return c.logoutErr
+	}
+	c.loggedIn = false
+	return nil
+}
+func (c *tCore) BackupNow() (string, error) {
+	return "/backups/bisonw.bak", c.backupErr
+}
+
+func TestServer(t *testing.T) {
+	// Unix socket paths are limited to ~100 characters, which the test's
+	// temporary directory may exceed.
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatalf("MkdirTemp error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "s.sock")
+
+	tc := &tCore{pw: "abc"}
+	var shutdown bool
+	var logLevel string
+	srv, err := New(&Config{
+		Path:     path,
+		Core:     tc,
+		Version:  "1.0.0",
+		Shutdown: func() { shutdown = true },
+		SetLogLevel: func(lvl string) error {
+			if lvl == "loud" {
+				return errors.New("invalid level")
+			}
+			logLevel = lvl
+			return nil
+		},
+		Logger: dex.StdOutLogger("T", dex.LevelOff),
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	// A stale socket file is replaced.
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := srv.Connect(ctx); err == nil {
+		t.Fatalf("no error for existing non-socket file")
+	}
+	os.Remove(path)
+
+	cm := dex.NewConnectionMaster(srv)
+	if err := cm.ConnectOnce(ctx); err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	defer func() {
+		cancel()
+		cm.Wait()
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("socket file not removed")
+		}
+	}()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat error: %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Fatalf("wrong socket permissions %o", perm)
+	}
+	// The private directory the socket was created in is removed.
+	if entries, err := os.ReadDir(dir); err != nil {
+		t.Fatalf("ReadDir error: %v", err)
+	} else if len(entries) != 1 {
+		t.Fatalf("expected only the socket file in %s, found %d entries", dir, len(entries))
+	}
+
+	send := func(cmd string, args ...string) (json.RawMessage, error) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+		defer cancel()
+		return Send(ctx, path, cmd, args...)
+	}
+	ensureResult := func(cmd string, want string, args ...string) {
+		t.Helper()
+		res, err := send(cmd, args...)
+		if err != nil {
+			t.Fatalf("%s error: %v", cmd, err)
+		}
+		var s string
+		if err := json.Unmarshal(res, &s); err != nil {
+			t.Fatalf("%s: error decoding result %s: %v", cmd, res, err)
+		}
+		if s != want {
+			t.Fatalf("%s: wanted result %q, got %q", cmd, want, s)
+		}
+	}
+	ensureErr := func(cmd string, errStr string, args ...string) {
+		t.Helper()
+		_, err := send(cmd, args...)
+		if err == nil || !strings.Contains(err.Error(), errStr) {
+			t.Fatalf("%s: wanted error containing %q, got %v", cmd, errStr, err)
+		}
+	}
+	status := func() *Status {
+		t.Helper()
+		res, err := send(CmdStatus)
+		if err != nil {
+			t.Fatalf("status error: %v", err)
+		}
+		st := new(Status)
+		if err := json.Unmarshal(res, st); err != nil {
+			t.Fatalf("error decoding status: %v", err)
+		}
+		return st
+	}
+
+	st := status()
+	if st.Version != "1.0.0" || st.Net != "simnet" || !st.Initialized || st.LoggedIn {
+		t.Fatalf("wrong status: %+v", st)
+	}
+	if len(st.Exchanges) != 2 || st.Exchanges[0].Host != "a.example.com" ||
+		st.Exchanges[0].Connection != comms.Connected.String() {
+		t.Fatalf("wrong exchanges: %+v", st.Exchanges)
+	}
+	if len(st.Wallets) != 1 || st.Wallets[0].Symbol != "dcr" || st.Wallets[0].Unlocked {
+		t.Fatalf("wrong wallets: %+v", st.Wallets)
+	}
+
+	// Unlock and lock.
+	ensureErr(CmdUnlock, "wrong password", "abd")
+	ensureErr(CmdUnlock, "wrong number of arguments")
+	ensureResult(CmdUnlock, "unlocked", "abc")
+	if st := status(); !st.LoggedIn || !st.Wallets[0].Unlocked {
+		t.Fatalf("not unlocked: %+v", st)
+	}
+	ensureResult(CmdLock, "locked")
+	if tc.loggedIn {
+		t.Fatalf("not locked")
+	}
+
+	// Log level.
+	ensureResult(CmdLogLevel, "log level set", "CORE=debug")
+	if logLevel != "CORE=debug" {
+		t.Fatalf("log level not set")
+	}
+	ensureErr(CmdLogLevel, "invalid level", "loud")
+
+	// Backup.
+	ensureResult(CmdBackup, "/backups/bisonw.bak")
+	tc.backupErr = errors.New("backup failed")
+	ensureErr(CmdBackup, "backup failed")
+
+	// Unknown commands and invalid requests.
+	ensureErr("nonsense", "unknown command")
+	ensureErr(CmdShutdown, "unknown shutdown argument", "now")
+
+	// Shutdown with active orders requires force.
+	tc.logoutErr = core.ActiveOrdersLogoutErr
+	ensureErr(CmdShutdown, core.ActiveOrdersLogoutErr.Error())
+	if shutdown {
+		t.Fatalf("shut down with active orders")
+	}
+	// Other logout errors prevent shutdown even if forced.
+	tc.logoutErr = errors.New("logout failed")
+	ensureErr(CmdShutdown, "logout failed", "force")
+	if shutdown {
+		t.Fatalf("shut down after logout error")
+	}
+	tc.logoutErr = core.ActiveOrdersLogoutErr
+	ensureResult(CmdShutdown, "shutting down", "force")
+	if !shutdown {
+		t.Fatalf("not shut down")
+	}
+}
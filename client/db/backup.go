@@ -0,0 +1,162 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+)
+
+// ErrBackupIntegrity is returned from DecryptBackup if the backup file is not
+// an encrypted backup, cannot be decrypted with the key, or is corrupt.
+const ErrBackupIntegrity = dex.ErrorKind("backup integrity check failed")
+
+// backupMagic prefixes every encrypted backup file.
+var backupMagic = []byte("BWBACKUP")
+
+// BackupVersion is the latest encrypted backup file format version.
+const BackupVersion = 0
+
+// Backup is the content of an encrypted backup file.
+type Backup struct {
+	// Stamp is when the backup was created.
+	Stamp time.Time
+	// DB is a copy of the database file.
+	DB []byte
+	// Wallets are the wallet configurations at the time of the backup. They
+	// are also in DB, but are kept separately so that they can be recovered
+	// with any backend.
+	Wallets []*BackupWallet
+}
+
+// BackupWallet is the configuration of a wallet in a Backup.
+type BackupWallet struct {
+	AssetID  uint32            `json:"assetID"`
+	Type     string            `json:"type"`
+	Settings map[string]string `json:"settings"`
+}
+
+// EncryptBackup encrypts the Backup with a key derived from the provided key
+// material. The file layout is
+//
+//	magic | version | crypter params length (uint16) | crypter params | ciphertext
+//
+// and the plaintext is
+//
+//	header length (uint32) | header | database file
+//
+// where the header is a versioned blob of the timestamp, the SHA-256 hash of
+// the database file, and the JSON-encoded wallet configurations.
+func EncryptBackup(key []byte, b *Backup) ([]byte, error) {
+	walletsB, err := json.Marshal(b.Wallets)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding wallets: %w", err)
+	}
+	dbHash := sha256.Sum256(b.DB)
+	header := encode.BuildyBytes{BackupVersion}.
+		AddData(uint64Bytes(uint64(b.Stamp.UnixMilli()))).
+		AddData(dbHash[:]).
+		AddData(walletsB)
+
+	plaintext := make([]byte, 0, 4+len(header)+len(b.DB))
+	plaintext = append(plaintext, uint32Bytes(uint32(len(header)))...)
+	plaintext = append(plaintext, header...)
+	plaintext = append(plaintext, b.DB...)
+
+	crypter := encrypt.NewCrypter(key)
+	defer crypter.Close()
+	ciphertext, err := crypter.Encrypt(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("encryption error: %w", err)
+	}
+	params := crypter.Serialize()
+	if len(params) > math.MaxUint16 {
+		return nil, fmt.Errorf("crypter parameters too long")
+	}
+
+	out := make([]byte, 0, len(backupMagic)+3+len(params)+len(ciphertext))
+	out = append(out, backupMagic...)
+	out = append(out, BackupVersion)
+	out = append(out, uint16Bytes(uint16(len(params)))...)
+	out = append(out, params...)
+	return append(out, ciphertext...), nil
+}
+
+// DecryptBackup decrypts a file created with EncryptBackup, verifying the
+// integrity of the contents. The error wraps ErrBackupIntegrity if the file is
+// not a valid backup for the key.
+func DecryptBackup(key, b []byte) (*Backup, error) {
+	if !bytes.HasPrefix(b, backupMagic) {
+		return nil, fmt.Errorf("%w: not a backup file", ErrBackupIntegrity)
+	}
+	b = b[len(backupMagic):]
+	if len(b) < 3 {
+		return nil, fmt.Errorf("%w: truncated file", ErrBackupIntegrity)
+	}
+	if ver := b[0]; ver != BackupVersion {
+		return nil, fmt.Errorf("unknown backup version %d", ver)
+	}
+	paramsLen := int(intCoder.Uint16(b[1:3]))
+	b = b[3:]
+	if len(b) < paramsLen {
+		return nil, fmt.Errorf("%w: truncated file", ErrBackupIntegrity)
+	}
+	crypter, err := encrypt.Deserialize(key, b[:paramsLen])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad encryption parameters: %v", ErrBackupIntegrity, err)
+	}
+	defer crypter.Close()
+	// Decryption authenticates the ciphertext, so a wrong key and a modified
+	// file are indistinguishable.
+	plaintext, err := crypter.Decrypt(b[paramsLen:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong seed or corrupt file", ErrBackupIntegrity)
+	}
+
+	if len(plaintext) < 4 {
+		return nil, fmt.Errorf("%w: truncated contents", ErrBackupIntegrity)
+	}
+	headerLen := int(intCoder.Uint32(plaintext[:4]))
+	plaintext = plaintext[4:]
+	if len(plaintext) < headerLen {
+		return nil, fmt.Errorf("%w: truncated header", ErrBackupIntegrity)
+	}
+	ver, pushes, err := encode.DecodeBlob(plaintext[:headerLen], 3)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error decoding header: %v", ErrBackupIntegrity, err)
+	}
+	if ver != BackupVersion {
+		return nil, fmt.Errorf("unknown backup header version %d", ver)
+	}
+	if len(pushes) != 3 || len(pushes[0]) != 8 || len(pushes[1]) != sha256.Size {
+		return nil, fmt.Errorf("%w: malformed header", ErrBackupIntegrity)
+	}
+	dbB := plaintext[headerLen:]
+	if dbHash := sha256.Sum256(dbB); !bytes.Equal(dbHash[:], pushes[1]) {
+		return nil, fmt.Errorf("%w: database checksum mismatch", ErrBackupIntegrity)
+	}
+	var wallets []*BackupWallet
+	if len(pushes[2]) > 0 {
+		if err := json.Unmarshal(pushes[2], &wallets); err != nil {
+			return nil, fmt.Errorf("%w: error decoding wallets: %v", ErrBackupIntegrity, err)
+		}
+	}
+	if len(dbB) == 0 {
+		return nil, errors.New("backup has no database")
+	}
+	return &Backup{
+		Stamp:   time.UnixMilli(int64(intCoder.Uint64(pushes[0]))),
+		DB:      dbB,
+		Wallets: wallets,
+	}, nil
+}
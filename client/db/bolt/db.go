@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"decred.org/dcrdex/client/db"
@@ -124,6 +125,11 @@ var (
 	walletDisabledKey     = []byte("walletDisabled")
 	programKey            = []byte("program")
 	langKey               = []byte("lang")
+	topicSettingsKey      = []byte("topicSettings")
+	noteTemplatesKey      = []byte("noteTemplates")
+	confTargetsKey        = []byte("confTargets")
+	searchKey             = []byte("search")
+	annotationKey         = []byte("annotation")
 
 	// values
 	byteTrue   = encode.ByteTrue
@@ -149,6 +155,8 @@ type BoltDB struct {
 	*bbolt.DB
 	opts Opts
 	log  dex.Logger
+	// forceCompact is set by CompactOnShutdown.
+	forceCompact atomic.Bool
 }
 
 // Check that BoltDB satisfies the db.DB interface.
@@ -247,22 +255,14 @@ func (db *BoltDB) Run(ctx context.Context) {
 	// terms of bytes AND relative to total DB size.
 	const byteThresh = 1 << 18 // 256 KiB free
 	const pctThresh = 0.05     // 5% free
-	var dbSize int64
-	_ = db.View(func(tx *bbolt.Tx) error {
-		dbSize = tx.Size() // db size including free bytes
-		return nil
-	})
-	dbStats := db.Stats()
-	// FreeAlloc is (FreePageN + PendingPageN) * db.Info().PageSize
-	// FreelistInuse seems to be page header overhead (much smaller). Add them.
-	// https://github.com/etcd-io/bbolt/blob/020684ea1eb7b5574a8007c8d69605b1de8d9ec4/tx.go#L308-L309
-	freeBytes := int64(dbStats.FreeAlloc + dbStats.FreelistInuse)
+	dbSize, freeBytes := db.sizes()
 	pctFree := float64(freeBytes) / float64(dbSize)
 	db.log.Debugf("Total DB size %d bytes, %d bytes unused (%.2f%%)",
 		dbSize, freeBytes, 100*pctFree)
 	// Only compact if free space is at least the byte threshold AND that fee
-	// space accounts for a significant percent of the file.
-	if freeBytes < byteThresh || pctFree < pctThresh {
+	// space accounts for a significant percent of the file, unless compaction
+	// was requested with CompactOnShutdown.
+	if !db.forceCompact.Load() && (freeBytes < byteThresh || pctFree < pctThresh) {
 		db.Close()
 		return
 	}
@@ -297,6 +297,124 @@ func (db *BoltDB) Run(ctx context.Context) {
 	}
 }
 
+// sizes returns the size of the database, including unused space, and the
+// unused space in bytes.
+func (db *BoltDB) sizes() (dbSize, freeBytes int64) {
+	_ = db.View(func(tx *bbolt.Tx) error {
+		dbSize = tx.Size() // db size including free bytes
+		return nil
+	})
+	dbStats := db.Stats()
+	// FreeAlloc is (FreePageN + PendingPageN) * db.Info().PageSize
+	// FreelistInuse seems to be page header overhead (much smaller). Add them.
+	// https://github.com/etcd-io/bbolt/blob/020684ea1eb7b5574a8007c8d69605b1de8d9ec4/tx.go#L308-L309
+	freeBytes = int64(dbStats.FreeAlloc + dbStats.FreelistInuse)
+	return dbSize, freeBytes
+}
+
+// CompactOnShutdown requests that the database file be compacted when Run
+// returns, regardless of the amount of unused space.
+func (db *BoltDB) CompactOnShutdown() {
+	db.forceCompact.Store(true)
+}
+
+// CheckIntegrity checks the consistency of the database. In addition to the
+// bbolt page checks, every order and match is decoded, matches are checked for
+// a stored order, and active orders and matches are checked for wallets.
+func (db *BoltDB) CheckIntegrity() (*dexdb.IntegrityReport, error) {
+	report := new(dexdb.IntegrityReport)
+	report.Size, report.FreeBytes = db.sizes()
+	return report, db.View(func(tx *bbolt.Tx) error {
+		for err := range tx.Check() {
+			report.AddProblem(dexdb.ProblemCorruptFile, nil, err.Error())
+		}
+
+		wallets := make(map[uint32]bool)
+		if wb := tx.Bucket(walletsBucket); wb != nil {
+			err := wb.ForEach(func(wid, _ []byte) error {
+				if len(wid) == 4 {
+					wallets[intCoder.Uint32(wid)] = true
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		checkWallets := func(id []byte, base, quote uint32) {
+			for _, assetID := range []uint32{base, quote} {
+				if !wallets[assetID] {
+					report.AddProblem(dexdb.ProblemMissingWallet, id,
+						fmt.Sprintf("no wallet for %s", dex.BipIDSymbol(assetID)))
+				}
+			}
+		}
+
+		orders := make(map[order.OrderID]bool)
+		for _, bktName := range [][]byte{activeOrdersBucket, archivedOrdersBucket} {
+			master := tx.Bucket(bktName)
+			if master == nil {
+				return fmt.Errorf("failed to open %s bucket", string(bktName))
+			}
+			active := bEqual(bktName, activeOrdersBucket)
+			err := master.ForEach(func(oid, _ []byte) error {
+				report.Orders++
+				oBkt := master.Bucket(oid)
+				if oBkt == nil {
+					report.AddProblem(dexdb.ProblemCorruptRecord, oid, "order is not a bucket")
+					return nil
+				}
+				mOrd, err := decodeOrderBucket(oid, oBkt)
+				if err != nil {
+					report.AddProblem(dexdb.ProblemCorruptRecord, oid, err.Error())
+					return nil
+				}
+				orders[mOrd.Order.ID()] = true
+				if active {
+					checkWallets(oid, mOrd.Order.Base(), mOrd.Order.Quote())
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, bktName := range [][]byte{activeMatchesBucket, archivedMatchesBucket} {
+			master := tx.Bucket(bktName)
+			if master == nil {
+				return fmt.Errorf("failed to open %s bucket", string(bktName))
+			}
+			active := bEqual(bktName, activeMatchesBucket)
+			err := master.ForEach(func(k, _ []byte) error {
+				report.Matches++
+				mBkt := master.Bucket(k)
+				if mBkt == nil {
+					report.AddProblem(dexdb.ProblemCorruptRecord, k, "match is not a bucket")
+					return nil
+				}
+				m, err := loadMatchBucket(mBkt, false)
+				if err != nil {
+					report.AddProblem(dexdb.ProblemCorruptRecord, k, err.Error())
+					return nil
+				}
+				if !orders[m.OrderID] {
+					report.AddProblem(dexdb.ProblemOrphanedMatch, m.MatchID[:],
+						fmt.Sprintf("order %s not found", m.OrderID))
+				}
+				if active {
+					checkWallets(m.MatchID[:], m.MetaData.Base, m.MetaData.Quote)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // Recrypt re-encrypts the wallet passwords and account private keys. As a
 // convenience, the provided *PrimaryCredentials are stored under the same
 // transaction.
@@ -1150,6 +1268,22 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 		})
 	}
 
+	if text := dexdb.SearchTerm(orderFilter.Text); text != "" {
+		textB := []byte(text)
+		matchOrders, err := db.searchMatchOrders(textB)
+		if err != nil {
+			return nil, fmt.Errorf("error searching matches: %w", err)
+		}
+		filters = append(filters, func(oidB []byte, oBkt *bbolt.Bucket) bool {
+			if bytes.Contains(oBkt.Get(searchKey), textB) {
+				return true
+			}
+			var oid order.OrderID
+			copy(oid[:], oidB)
+			return matchOrders[oid]
+		})
+	}
+
 	if !orderFilter.Offset.IsZero() {
 		offsetOID := orderFilter.Offset
 		var stampB []byte
@@ -1274,6 +1408,11 @@ func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error)
 		fundingFeesPaid = intCoder.Uint64(fundingFeesB)
 	}
 
+	annotation, err := dexdb.DecodeAnnotation(oBkt.Get(annotationKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode annotation: %w", err)
+	}
+
 	return &dexdb.MetaOrder{
 		MetaData: &dexdb.OrderMetaData{
 			Proof:              *proof,
@@ -1295,6 +1434,7 @@ func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error)
 			RefundReserves:     refundReserves,
 			AccelerationCoins:  accelerationCoinIDs,
 			FundingFeesPaid:    fundingFeesPaid,
+			Annotation:         annotation,
 		},
 		Order: ord,
 	}, nil
@@ -1370,7 +1510,7 @@ func updateOrderMetaData(bkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
 		}
 	}
 
-	return newBucketPutter(bkt).
+	err := newBucketPutter(bkt).
 		put(statusKey, uint16Bytes(uint16(md.Status))).
 		put(updateTimeKey, uint64Bytes(timeNow())).
 		put(proofKey, md.Proof.Encode()).
@@ -1384,6 +1524,58 @@ func updateOrderMetaData(bkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
 		put(accelerationsKey, accelerationsB).
 		put(fundingFeesKey, uint64Bytes(md.FundingFeesPaid)).
 		err()
+	if err != nil {
+		return err
+	}
+
+	return putOrderSearchText(bkt, md)
+}
+
+// putOrderSearchText stores the order's search text, which includes some of
+// the metadata and the stored annotation, for OrderFilter.Text searches.
+func putOrderSearchText(oBkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
+	ord, err := order.DecodeOrder(getCopy(oBkt, orderKey))
+	if err != nil {
+		return fmt.Errorf("error decoding order: %w", err)
+	}
+	annotation, err := dexdb.DecodeAnnotation(oBkt.Get(annotationKey))
+	if err != nil {
+		return fmt.Errorf("error decoding annotation: %w", err)
+	}
+	searchMD := *md
+	searchMD.Annotation = annotation
+	return oBkt.Put(searchKey, []byte(dexdb.OrderSearchText(ord, &searchMD)))
+}
+
+// putAnnotation stores the annotation, or deletes the stored annotation if it
+// is empty.
+func putAnnotation(bkt *bbolt.Bucket, a *dexdb.Annotation) error {
+	if a.IsEmpty() {
+		return bkt.Delete(annotationKey)
+	}
+	return bkt.Put(annotationKey, a.Encode())
+}
+
+// SetOrderAnnotation sets the user's note and tags for the order. A nil or
+// empty annotation removes any existing annotation.
+func (db *BoltDB) SetOrderAnnotation(oid order.OrderID, a *dexdb.Annotation) error {
+	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
+		oBkt := ob.Bucket(oid[:])
+		if oBkt == nil {
+			oBkt = archivedOB.Bucket(oid[:])
+		}
+		if oBkt == nil {
+			return fmt.Errorf("SetOrderAnnotation - order %s not found", oid)
+		}
+		if err := putAnnotation(oBkt, a); err != nil {
+			return err
+		}
+		mord, err := decodeOrderBucket(oid[:], oBkt)
+		if err != nil {
+			return err
+		}
+		return putOrderSearchText(oBkt, mord.MetaData)
+	})
 }
 
 // UpdateOrderStatus sets the order status for an order.
@@ -1506,6 +1698,14 @@ func (db *BoltDB) UpdateMatch(m *dexdb.MetaMatch) error {
 			return err
 		}
 
+		// The search text includes the stored annotation.
+		annotation, err := dexdb.DecodeAnnotation(mBkt.Get(annotationKey))
+		if err != nil {
+			return fmt.Errorf("error decoding annotation: %w", err)
+		}
+		searchMD := *md
+		searchMD.Annotation = annotation
+
 		return newBucketPutter(mBkt).
 			put(baseKey, uint32Bytes(md.Base)).
 			put(quoteKey, uint32Bytes(md.Quote)).
@@ -1517,10 +1717,37 @@ func (db *BoltDB) UpdateMatch(m *dexdb.MetaMatch) error {
 			put(matchIDKey, match.MatchID[:]).
 			put(matchKey, order.EncodeMatch(match)).
 			put(stampKey, uint64Bytes(md.Stamp)).
+			put(searchKey, []byte(dexdb.MatchSearchText(&dexdb.MetaMatch{
+				UserMatch: match,
+				MetaData:  &searchMD,
+			}))).
 			err()
 	})
 }
 
+// SetMatchAnnotation sets the user's note and tags for the order's match. A
+// nil or empty annotation removes any existing annotation.
+func (db *BoltDB) SetMatchAnnotation(oid order.OrderID, mid order.MatchID, a *dexdb.Annotation) error {
+	return db.matchesUpdate(func(mb, archivedMB *bbolt.Bucket) error {
+		metaID := dexdb.MatchOrderUniqueID(mid, oid)
+		mBkt := mb.Bucket(metaID)
+		if mBkt == nil {
+			mBkt = archivedMB.Bucket(metaID)
+		}
+		if mBkt == nil {
+			return fmt.Errorf("SetMatchAnnotation - match %s for order %s not found", mid, oid)
+		}
+		if err := putAnnotation(mBkt, a); err != nil {
+			return err
+		}
+		m, err := loadMatchBucket(mBkt, false)
+		if err != nil {
+			return err
+		}
+		return mBkt.Put(searchKey, []byte(dexdb.MatchSearchText(m)))
+	})
+}
+
 // ActiveMatches retrieves the matches that are in an active state, which is
 // any match that is still active.
 func (db *BoltDB) ActiveMatches() ([]*dexdb.MetaMatch, error) {
@@ -1575,6 +1802,32 @@ func (db *BoltDB) MatchesForOrder(oid order.OrderID, excludeCancels bool) ([]*de
 	}, excludeCancels, true) // include archived matches
 }
 
+// searchMatchOrders returns the IDs of the orders with a match with search
+// text containing the lower-case text.
+func (db *BoltDB) searchMatchOrders(text []byte) (map[order.OrderID]bool, error) {
+	oids := make(map[order.OrderID]bool)
+	return oids, db.matchesView(func(mb, archivedMB *bbolt.Bucket) error {
+		for _, master := range []*bbolt.Bucket{mb, archivedMB} {
+			err := master.ForEach(func(k, _ []byte) error {
+				mBkt := master.Bucket(k)
+				if mBkt == nil {
+					return fmt.Errorf("match %x bucket is not a bucket", k)
+				}
+				if bytes.Contains(mBkt.Get(searchKey), text) {
+					var oid order.OrderID
+					copy(oid[:], mBkt.Get(orderIDKey))
+					oids[oid] = true
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // completedOrders returns a set of fully filled or partially filled orders. Order is partially
 // filled if it has at least 1 non-cancel match with non-zero quantity.
 func (db *BoltDB) completedOrders() (map[order.OrderID]struct{}, error) {
@@ -1702,13 +1955,18 @@ func loadMatchBucket(mBkt *bbolt.Bucket, excludeCancels bool) (*dexdb.MetaMatch,
 	if excludeCancels && (len(proof.Auth.InitSig) == 0 && match.Status == order.MatchComplete) {
 		return nil, nil
 	}
+	annotation, err := dexdb.DecodeAnnotation(mBkt.Get(annotationKey))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding annotation: %w", err)
+	}
 	return &dexdb.MetaMatch{
 		MetaData: &dexdb.MatchMetaData{
-			Proof: *proof,
-			DEX:   string(getCopy(mBkt, dexKey)),
-			Base:  intCoder.Uint32(mBkt.Get(baseKey)),
-			Quote: intCoder.Uint32(mBkt.Get(quoteKey)),
-			Stamp: intCoder.Uint64(mBkt.Get(stampKey)),
+			Proof:      *proof,
+			DEX:        string(getCopy(mBkt, dexKey)),
+			Base:       intCoder.Uint32(mBkt.Get(baseKey)),
+			Quote:      intCoder.Uint32(mBkt.Get(quoteKey)),
+			Stamp:      intCoder.Uint64(mBkt.Get(stampKey)),
+			Annotation: annotation,
 		},
 		UserMatch: match,
 	}, nil
@@ -1878,7 +2136,7 @@ func (db *BoltDB) walletsUpdate(f bucketFunc) error {
 
 // SaveNotification saves the notification.
 func (db *BoltDB) SaveNotification(note *dexdb.Notification) error {
-	if note.Severeness < dexdb.Success {
+	if note.Severeness < dexdb.Poke {
 		return fmt.Errorf("storage of notification with severity %s is forbidden", note.Severeness)
 	}
 	return db.notesUpdate(func(master *bbolt.Bucket) error {
@@ -1911,11 +2169,14 @@ func (db *BoltDB) AckNotification(id []byte) error {
 	})
 }
 
-// NotificationsN reads out the N most recent notifications.
+// NotificationsN reads out the N most recent notifications with a severity of
+// at least Success. Pokes are stored for searching, but are not included.
 func (db *BoltDB) NotificationsN(n int) ([]*dexdb.Notification, error) {
 	notes := make([]*dexdb.Notification, 0, n)
 	return notes, db.notesUpdate(func(master *bbolt.Bucket) error {
-		trios := newestBuckets([]*bbolt.Bucket{master}, n, stampKey, nil)
+		trios := newestBuckets([]*bbolt.Bucket{master}, n, stampKey, func(_ []byte, noteBkt *bbolt.Bucket) bool {
+			return noteSeverity(noteBkt) >= dexdb.Success
+		})
 		for _, trio := range trios {
 			note, err := dexdb.DecodeNotification(getCopy(trio.b, noteKey))
 			if err != nil {
@@ -1940,6 +2201,96 @@ func (db *BoltDB) NotificationsN(n int) ([]*dexdb.Notification, error) {
 	})
 }
 
+// SearchNotifications reads out the notifications that pass the filter, newest
+// first.
+func (db *BoltDB) SearchNotifications(filter *dexdb.NoteFilter) ([]*dexdb.Notification, error) {
+	topics := make(map[dexdb.Topic]bool, len(filter.Topics))
+	for _, topic := range filter.Topics {
+		topics[topic] = true
+	}
+	text := strings.ToLower(filter.Text)
+	// Notes decoded by the filter are kept so they are not decoded again.
+	decoded := make(map[string]*dexdb.Notification)
+	var notes []*dexdb.Notification
+	return notes, db.notesView(func(master *bbolt.Bucket) error {
+		trios := newestBuckets([]*bbolt.Bucket{master}, filter.N, stampKey, func(k []byte, noteBkt *bbolt.Bucket) bool {
+			stamp := intCoder.Uint64(noteBkt.Get(stampKey))
+			if stamp < filter.SinceUnixMs || (filter.UntilUnixMs > 0 && stamp > filter.UntilUnixMs) {
+				return false
+			}
+			sev := noteSeverity(noteBkt)
+			if sev < filter.MinSeverity || (filter.MaxSeverity > 0 && sev > filter.MaxSeverity) {
+				return false
+			}
+			if len(topics) == 0 && text == "" {
+				return true
+			}
+			note, err := dexdb.DecodeNotification(noteBkt.Get(noteKey))
+			if err != nil {
+				return false
+			}
+			if len(topics) > 0 && !topics[note.TopicID] {
+				return false
+			}
+			if text != "" && !strings.Contains(strings.ToLower(note.SubjectText), text) &&
+				!strings.Contains(strings.ToLower(note.DetailText), text) {
+				return false
+			}
+			decoded[string(k)] = note
+			return true
+		})
+		notes = make([]*dexdb.Notification, 0, len(trios))
+		for _, trio := range trios {
+			note := decoded[string(trio.k)]
+			if note == nil {
+				var err error
+				if note, err = dexdb.DecodeNotification(getCopy(trio.b, noteKey)); err != nil {
+					return err
+				}
+			}
+			note.Ack = bEqual(trio.b.Get(ackKey), byteTrue)
+			note.Id = note.ID()
+			if !bytes.Equal(note.Id, trio.k) {
+				continue // stored before the note ID included the TopicID
+			}
+			notes = append(notes, note)
+		}
+		return nil
+	})
+}
+
+// DeleteNotifications deletes the notifications older than the unix
+// millisecond timestamp, returning the number deleted.
+func (db *BoltDB) DeleteNotifications(olderThanUnixMs uint64) (n int, _ error) {
+	return n, db.notesUpdate(func(master *bbolt.Bucket) error {
+		var keys [][]byte
+		if err := master.ForEach(func(k, _ []byte) error {
+			noteBkt := master.Bucket(k)
+			if noteBkt != nil && intCoder.Uint64(noteBkt.Get(stampKey)) < olderThanUnixMs {
+				keys = append(keys, bytes.Clone(k))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := master.DeleteBucket(k); err != nil {
+				return err
+			}
+		}
+		n = len(keys)
+		return nil
+	})
+}
+
+// noteSeverity is the severity stored in the notification's bucket.
+func noteSeverity(noteBkt *bbolt.Bucket) dexdb.Severity {
+	if b := noteBkt.Get(severityKey); len(b) == 1 {
+		return dexdb.Severity(b[0])
+	}
+	return dexdb.Ignorable
+}
+
 // notesView is a convenience function to read from the notifications bucket.
 func (db *BoltDB) notesView(f bucketFunc) error {
 	return db.withBucket(notesBucket, db.View, f)
@@ -2542,6 +2893,108 @@ func (db *BoltDB) Language() (lang string, _ error) {
 	})
 }
 
+// SetTopicSettings stores the notification settings, replacing any previously
+// stored settings.
+func (db *BoltDB) SetTopicSettings(settings map[dexdb.Topic]*dexdb.TopicSettings) error {
+	// Just save it as JSON.
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return fmt.Errorf("app bucket not found")
+		}
+		return bkt.Put(topicSettingsKey, b)
+	})
+}
+
+// TopicSettings retrieves the notification settings stored with
+// SetTopicSettings. If no settings have been stored, an empty map is returned
+// without an error.
+func (db *BoltDB) TopicSettings() (map[dexdb.Topic]*dexdb.TopicSettings, error) {
+	settings := make(map[dexdb.Topic]*dexdb.TopicSettings)
+	return settings, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return nil
+		}
+		b := bkt.Get(topicSettingsKey)
+		if len(b) == 0 {
+			return nil
+		}
+		return json.Unmarshal(b, &settings)
+	})
+}
+
+// SetNoteTemplates stores the notification templates, replacing any previously
+// stored templates.
+func (db *BoltDB) SetNoteTemplates(templates map[dexdb.Topic]*dexdb.NoteTemplate) error {
+	b, err := json.Marshal(templates)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return fmt.Errorf("app bucket not found")
+		}
+		return bkt.Put(noteTemplatesKey, b)
+	})
+}
+
+// NoteTemplates retrieves the notification templates stored with
+// SetNoteTemplates. If no templates have been stored, an empty map is returned
+// without an error.
+func (db *BoltDB) NoteTemplates() (map[dexdb.Topic]*dexdb.NoteTemplate, error) {
+	templates := make(map[dexdb.Topic]*dexdb.NoteTemplate)
+	return templates, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return nil
+		}
+		b := bkt.Get(noteTemplatesKey)
+		if len(b) == 0 {
+			return nil
+		}
+		return json.Unmarshal(b, &templates)
+	})
+}
+
+// SetConfTargets stores the confirmation targets, replacing any previously
+// stored targets.
+func (db *BoltDB) SetConfTargets(targets map[uint32]*dexdb.ConfTarget) error {
+	b, err := json.Marshal(targets)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return fmt.Errorf("app bucket not found")
+		}
+		return bkt.Put(confTargetsKey, b)
+	})
+}
+
+// ConfTargets retrieves the confirmation targets stored with SetConfTargets.
+// If no targets have been stored, an empty map is returned without an error.
+func (db *BoltDB) ConfTargets() (map[uint32]*dexdb.ConfTarget, error) {
+	targets := make(map[uint32]*dexdb.ConfTarget)
+	return targets, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return nil
+		}
+		b := bkt.Get(confTargetsKey)
+		if len(b) == 0 {
+			return nil
+		}
+		return json.Unmarshal(b, &targets)
+	})
+}
+
 // timeNow is the current unix timestamp in milliseconds.
 func timeNow() uint64 {
 	return uint64(time.Now().UnixMilli())
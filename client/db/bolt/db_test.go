@@ -3,11 +3,14 @@ package bolt
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -788,6 +791,305 @@ func TestOrderFilters(t *testing.T) {
 	}
 }
 
+func TestOrderSearch(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	host1, host2 := "somehost.co", "anotherhost.org"
+	makeOrder := func(host, addr string, coin order.CoinID) *db.MetaOrder {
+		lo, _ := ordertest.RandomLimitOrder()
+		lo.BaseAsset, lo.QuoteAsset = 42, 0
+		lo.Address = addr
+		lo.Coins = []order.CoinID{coin}
+		mord := &db.MetaOrder{
+			MetaData: &db.OrderMetaData{
+				Status: order.OrderStatusBooked,
+				Host:   host,
+				Proof:  db.OrderProof{DEXSig: randBytes(73)},
+			},
+			Order: lo,
+		}
+		if err := boltdb.UpdateOrder(mord); err != nil {
+			t.Fatalf("error inserting order: %v", err)
+		}
+		return mord
+	}
+
+	orders := []*db.MetaOrder{
+		makeOrder(host1, "DsAddressOne", randBytes(36)),
+		makeOrder(host1, "DsAddressTwo", randBytes(36)),
+		makeOrder(host2, "DsAddressThree", randBytes(36)),
+	}
+
+	// The change coin is indexed when the metadata is updated.
+	changeCoin := randBytes(36)
+	orders[0].MetaData.ChangeCoin = changeCoin
+	if err := boltdb.UpdateOrderMetaData(orders[0].Order.ID(), orders[0].MetaData); err != nil {
+		t.Fatalf("UpdateOrderMetaData error: %v", err)
+	}
+
+	// A match for the third order, which is found by its coins and the
+	// counterparty address.
+	swapCoin := randBytes(36)
+	err := boltdb.UpdateMatch(&db.MetaMatch{
+		UserMatch: &order.UserMatch{
+			OrderID: orders[2].Order.ID(),
+			MatchID: ordertest.RandomMatchID(),
+			Address: "CounterpartyAddr",
+			Status:  order.MakerSwapCast,
+		},
+		MetaData: &db.MatchMetaData{
+			DEX:   host2,
+			Base:  42,
+			Quote: 0,
+			Proof: db.MatchProof{MakerSwap: swapCoin},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+
+	// UTXO transaction IDs are shown byte-reversed.
+	reversedTxID := make([]byte, 32)
+	for i := range reversedTxID {
+		reversedTxID[i] = swapCoin[31-i]
+	}
+
+	tests := []struct {
+		name     string
+		filter   *db.OrderFilter
+		expected []int
+	}{{
+		name:     "order ID prefix",
+		filter:   &db.OrderFilter{Text: strings.ToUpper(orders[1].Order.ID().String()[:12])},
+		expected: []int{1},
+	}, {
+		name:     "address fragment",
+		filter:   &db.OrderFilter{Text: " addressT "},
+		expected: []int{1, 2},
+	}, {
+		name:     "address fragment and host",
+		filter:   &db.OrderFilter{Text: "addressT", Hosts: []string{host2}},
+		expected: []int{2},
+	}, {
+		name:     "funding coin",
+		filter:   &db.OrderFilter{Text: hex.EncodeToString(orders[0].Order.Trade().Coins[0])},
+		expected: []int{0},
+	}, {
+		name:     "change coin",
+		filter:   &db.OrderFilter{Text: hex.EncodeToString(changeCoin)},
+		expected: []int{0},
+	}, {
+		name:     "counterparty address",
+		filter:   &db.OrderFilter{Text: "counterparty"},
+		expected: []int{2},
+	}, {
+		name:     "reversed match tx ID",
+		filter:   &db.OrderFilter{Text: hex.EncodeToString(reversedTxID)},
+		expected: []int{2},
+	}, {
+		name:   "no match",
+		filter: &db.OrderFilter{Text: "nothing"},
+	}}
+
+	for _, test := range tests {
+		test.filter.N = len(orders)
+		ords, err := boltdb.Orders(test.filter)
+		if err != nil {
+			t.Fatalf("%s: Orders error: %v", test.name, err)
+		}
+		found := make(map[order.OrderID]bool, len(ords))
+		for _, ord := range ords {
+			found[ord.Order.ID()] = true
+		}
+		if len(ords) != len(test.expected) {
+			t.Fatalf("%s: wrong number of orders. wanted %d, got %d", test.name, len(test.expected), len(ords))
+		}
+		for _, j := range test.expected {
+			if !found[orders[j].Order.ID()] {
+				t.Fatalf("%s: order %d not found", test.name, j)
+			}
+		}
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	lo, _ := ordertest.RandomLimitOrder()
+	mord := &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status: order.OrderStatusBooked,
+			Host:   "somehost.co",
+			Proof:  db.OrderProof{DEXSig: randBytes(73)},
+		},
+		Order: lo,
+	}
+	if err := boltdb.UpdateOrder(mord); err != nil {
+		t.Fatalf("UpdateOrder error: %v", err)
+	}
+	oid := lo.ID()
+	m := &db.MetaMatch{
+		UserMatch: &order.UserMatch{
+			OrderID: oid,
+			MatchID: ordertest.RandomMatchID(),
+			Address: "CounterpartyAddr",
+			Status:  order.MakerSwapCast,
+		},
+		MetaData: &db.MatchMetaData{
+			DEX:   "somehost.co",
+			Base:  42,
+			Quote: 0,
+		},
+	}
+	if err := boltdb.UpdateMatch(m); err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+
+	checkSearch := func(text string, expFound bool) {
+		t.Helper()
+		ords, err := boltdb.Orders(&db.OrderFilter{N: 10, Text: text})
+		if err != nil {
+			t.Fatalf("Orders error: %v", err)
+		}
+		if found := len(ords) == 1; found != expFound {
+			t.Fatalf("search for %q: expected found = %t, got %d orders", text, expFound, len(ords))
+		}
+	}
+
+	orderAnnotation := &db.Annotation{Note: "Placed by hand", Tags: []string{"manual", "dca"}}
+	if err := boltdb.SetOrderAnnotation(oid, orderAnnotation); err != nil {
+		t.Fatalf("SetOrderAnnotation error: %v", err)
+	}
+	if err := boltdb.SetMatchAnnotation(oid, m.MatchID, &db.Annotation{Tags: []string{"arb"}}); err != nil {
+		t.Fatalf("SetMatchAnnotation error: %v", err)
+	}
+	if err := boltdb.SetOrderAnnotation(ordertest.RandomOrderID(), orderAnnotation); err == nil {
+		t.Fatalf("no error for unknown order")
+	}
+	if err := boltdb.SetMatchAnnotation(oid, ordertest.RandomMatchID(), orderAnnotation); err == nil {
+		t.Fatalf("no error for unknown match")
+	}
+
+	// The annotations are kept, and still indexed, when the order and match
+	// are updated, even when the order is archived.
+	mord.MetaData.Status = order.OrderStatusExecuted
+	if err := boltdb.UpdateOrderMetaData(oid, mord.MetaData); err != nil {
+		t.Fatalf("UpdateOrderMetaData error: %v", err)
+	}
+	m.Status = order.MatchConfirmed
+	if err := boltdb.UpdateMatch(m); err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+	checkSearch("BY HAND", true)
+	checkSearch("dca", true)
+	checkSearch("arb", true)
+
+	mord2, err := boltdb.Order(oid)
+	if err != nil {
+		t.Fatalf("Order error: %v", err)
+	}
+	if !reflect.DeepEqual(mord2.MetaData.Annotation, orderAnnotation) {
+		t.Fatalf("wrong order annotation %+v", mord2.MetaData.Annotation)
+	}
+	matches, err := boltdb.MatchesForOrder(oid, false)
+	if err != nil {
+		t.Fatalf("MatchesForOrder error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].MetaData.Annotation == nil || matches[0].MetaData.Annotation.Tags[0] != "arb" {
+		t.Fatalf("wrong match annotation")
+	}
+
+	// Removing the annotations.
+	if err := boltdb.SetOrderAnnotation(oid, &db.Annotation{}); err != nil {
+		t.Fatalf("SetOrderAnnotation error: %v", err)
+	}
+	if err := boltdb.SetMatchAnnotation(oid, m.MatchID, nil); err != nil {
+		t.Fatalf("SetMatchAnnotation error: %v", err)
+	}
+	checkSearch("manual", false)
+	checkSearch("arb", false)
+	if mord2, err = boltdb.Order(oid); err != nil {
+		t.Fatalf("Order error: %v", err)
+	}
+	if mord2.MetaData.Annotation != nil {
+		t.Fatalf("annotation not removed")
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	w := dbtest.RandomWallet()
+	w.AssetID = 42
+	if err := boltdb.UpdateWallet(w); err != nil {
+		t.Fatalf("UpdateWallet error: %v", err)
+	}
+
+	// An active order for a market with no wallet for the quote asset.
+	lo, _ := ordertest.RandomLimitOrder()
+	lo.BaseAsset, lo.QuoteAsset = 42, 0
+	err := boltdb.UpdateOrder(&db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status: order.OrderStatusBooked,
+			Host:   "somehost.co",
+			Proof:  db.OrderProof{DEXSig: randBytes(73)},
+		},
+		Order: lo,
+	})
+	if err != nil {
+		t.Fatalf("error inserting order: %v", err)
+	}
+
+	report, err := boltdb.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity error: %v", err)
+	}
+	if report.Orders != 1 || report.Matches != 0 || report.Size == 0 {
+		t.Fatalf("wrong report %+v", report)
+	}
+	oid := lo.ID()
+	if len(report.Problems) != 1 || report.Problems[0].Kind != db.ProblemMissingWallet ||
+		report.Problems[0].ID != oid.String() {
+		t.Fatalf("expected a missing wallet problem for the order, got %+v", report.Problems)
+	}
+
+	// A match for an order that isn't stored.
+	matchID := ordertest.RandomMatchID()
+	err = boltdb.UpdateMatch(&db.MetaMatch{
+		UserMatch: &order.UserMatch{
+			OrderID: ordertest.RandomOrderID(),
+			MatchID: matchID,
+			Address: "CounterpartyAddr",
+			Status:  order.MatchConfirmed,
+		},
+		MetaData: &db.MatchMetaData{
+			DEX:   "somehost.co",
+			Base:  42,
+			Quote: 0,
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+	report, err = boltdb.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity error: %v", err)
+	}
+	if report.Matches != 1 {
+		t.Fatalf("expected 1 match, got %d", report.Matches)
+	}
+	var orphaned bool
+	for _, p := range report.Problems {
+		orphaned = orphaned || (p.Kind == db.ProblemOrphanedMatch && p.ID == matchID.String())
+	}
+	if !orphaned || len(report.Problems) != 2 {
+		t.Fatalf("expected an orphaned match problem, got %+v", report.Problems)
+	}
+}
+
 func TestOrderChange(t *testing.T) {
 	boltdb, shutdown := newTestDB(t)
 	defer shutdown()
@@ -1048,6 +1350,108 @@ func TestNotifications(t *testing.T) {
 	}
 }
 
+func TestSearchNotifications(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	newNote := func(topic db.Topic, details string, sev db.Severity, stamp uint64) *db.Notification {
+		note := &db.Notification{
+			NoteType:    "test",
+			TopicID:     topic,
+			SubjectText: string(topic),
+			DetailText:  details,
+			Severeness:  sev,
+			TimeStamp:   stamp,
+		}
+		note.Id = note.ID()
+		if err := boltdb.SaveNotification(note); err != nil {
+			t.Fatalf("SaveNotification error: %v", err)
+		}
+		return note
+	}
+	poke := newNote("TopicA", "first poke", db.Poke, 100)
+	success := newNote("TopicA", "Order ABC matched", db.Success, 200)
+	warning := newNote("TopicB", "Order abc revoked", db.WarningLevel, 300)
+	errNote := newNote("TopicC", "Something broke", db.ErrorLevel, 400)
+
+	if err := boltdb.SaveNotification(&db.Notification{Severeness: db.Data}); err == nil {
+		t.Fatalf("no error for saving data notification")
+	}
+
+	// Pokes are not returned by NotificationsN.
+	notes, err := boltdb.NotificationsN(10)
+	if err != nil {
+		t.Fatalf("NotificationsN error: %v", err)
+	}
+	if len(notes) != 3 {
+		t.Fatalf("expected 3 notifications, got %d", len(notes))
+	}
+
+	tests := []struct {
+		name   string
+		filter *db.NoteFilter
+		exp    []*db.Notification
+	}{{
+		name:   "all",
+		filter: &db.NoteFilter{},
+		exp:    []*db.Notification{errNote, warning, success, poke},
+	}, {
+		name:   "N",
+		filter: &db.NoteFilter{N: 2},
+		exp:    []*db.Notification{errNote, warning},
+	}, {
+		name:   "topics",
+		filter: &db.NoteFilter{Topics: []db.Topic{"TopicA", "TopicC"}},
+		exp:    []*db.Notification{errNote, success, poke},
+	}, {
+		name:   "severity",
+		filter: &db.NoteFilter{MinSeverity: db.Success, MaxSeverity: db.WarningLevel},
+		exp:    []*db.Notification{warning, success},
+	}, {
+		name:   "time",
+		filter: &db.NoteFilter{SinceUnixMs: 200, UntilUnixMs: 300},
+		exp:    []*db.Notification{warning, success},
+	}, {
+		name:   "text",
+		filter: &db.NoteFilter{Text: "aBc"},
+		exp:    []*db.Notification{warning, success},
+	}, {
+		name:   "combined",
+		filter: &db.NoteFilter{Text: "order", Topics: []db.Topic{"TopicA"}, SinceUnixMs: 150},
+		exp:    []*db.Notification{success},
+	}, {
+		name:   "none",
+		filter: &db.NoteFilter{Text: "nothing"},
+	}}
+	for _, tt := range tests {
+		notes, err := boltdb.SearchNotifications(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: SearchNotifications error: %v", tt.name, err)
+		}
+		if len(notes) != len(tt.exp) {
+			t.Fatalf("%s: expected %d notifications, got %d", tt.name, len(tt.exp), len(notes))
+		}
+		for i, note := range notes {
+			dbtest.MustCompareNotifications(t, note, tt.exp[i])
+		}
+	}
+
+	n, err := boltdb.DeleteNotifications(300)
+	if err != nil {
+		t.Fatalf("DeleteNotifications error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 notifications deleted, got %d", n)
+	}
+	notes, err = boltdb.SearchNotifications(&db.NoteFilter{})
+	if err != nil {
+		t.Fatalf("SearchNotifications error: %v", err)
+	}
+	if len(notes) != 2 || notes[0].Time() != 400 || notes[1].Time() != 300 {
+		t.Fatalf("wrong notifications after delete")
+	}
+}
+
 type tCrypter struct {
 	enc []byte
 }
@@ -1578,6 +1982,105 @@ func TestOrderSide(t *testing.T) {
 	}
 }
 
+func TestTopicSettings(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	settings, err := boltdb.TopicSettings()
+	if err != nil {
+		t.Fatalf("TopicSettings error: %v", err)
+	}
+	if len(settings) != 0 {
+		t.Fatalf("expected no settings, got %d", len(settings))
+	}
+
+	settings = map[db.Topic]*db.TopicSettings{
+		"EpochNote":   {Mute: true},
+		"OrderPlaced": {MinSeverity: db.WarningLevel, LogOnly: true},
+	}
+	if err := boltdb.SetTopicSettings(settings); err != nil {
+		t.Fatalf("SetTopicSettings error: %v", err)
+	}
+	reSettings, err := boltdb.TopicSettings()
+	if err != nil {
+		t.Fatalf("TopicSettings error: %v", err)
+	}
+	if len(reSettings) != len(settings) {
+		t.Fatalf("expected %d settings, got %d", len(settings), len(reSettings))
+	}
+	for topic, s := range settings {
+		if *reSettings[topic] != *s {
+			t.Fatalf("%s: wrong settings %+v != %+v", topic, reSettings[topic], s)
+		}
+	}
+}
+
+func TestNoteTemplates(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	templates, err := boltdb.NoteTemplates()
+	if err != nil {
+		t.Fatalf("NoteTemplates error: %v", err)
+	}
+	if len(templates) != 0 {
+		t.Fatalf("expected no templates, got %d", len(templates))
+	}
+
+	templates = map[db.Topic]*db.NoteTemplate{
+		"OrderPlaced":       {Subject: "New order", Template: "Order placed: %s %s %s"},
+		"AsyncOrderFailure": {Subject: "Order failed"},
+	}
+	if err := boltdb.SetNoteTemplates(templates); err != nil {
+		t.Fatalf("SetNoteTemplates error: %v", err)
+	}
+	reTemplates, err := boltdb.NoteTemplates()
+	if err != nil {
+		t.Fatalf("NoteTemplates error: %v", err)
+	}
+	if len(reTemplates) != len(templates) {
+		t.Fatalf("expected %d templates, got %d", len(templates), len(reTemplates))
+	}
+	for topic, tmpl := range templates {
+		if *reTemplates[topic] != *tmpl {
+			t.Fatalf("%s: wrong template %+v != %+v", topic, reTemplates[topic], tmpl)
+		}
+	}
+}
+
+func TestConfTargets(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	targets, err := boltdb.ConfTargets()
+	if err != nil {
+		t.Fatalf("ConfTargets error: %v", err)
+	}
+	if len(targets) != 0 {
+		t.Fatalf("expected no targets, got %d", len(targets))
+	}
+
+	targets = map[uint32]*db.ConfTarget{
+		0:  {Speed: db.ConfSpeedEconomy},
+		42: {Blocks: 6},
+	}
+	if err := boltdb.SetConfTargets(targets); err != nil {
+		t.Fatalf("SetConfTargets error: %v", err)
+	}
+	reTargets, err := boltdb.ConfTargets()
+	if err != nil {
+		t.Fatalf("ConfTargets error: %v", err)
+	}
+	if len(reTargets) != len(targets) {
+		t.Fatalf("expected %d targets, got %d", len(targets), len(reTargets))
+	}
+	for assetID, target := range targets {
+		if *reTargets[assetID] != *target {
+			t.Fatalf("%d: wrong target %+v != %+v", assetID, reTargets[assetID], target)
+		}
+	}
+}
+
 func TestPokes(t *testing.T) {
 	boltdb, shutdown := newTestDB(t)
 	defer shutdown()
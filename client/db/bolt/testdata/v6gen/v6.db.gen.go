@@ -0,0 +1,108 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// This file should compiled from the commit the file was introduced, otherwise
+// it may not compile due to API changes, or may not create the database with
+// the correct old version.  This file should not be updated for API changes.
+
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/db/bolt"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/dex/order/test"
+)
+
+const dbname = "v6.db"
+
+func main() {
+	err := setup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "setup: %v\n", err)
+		os.Exit(1)
+	}
+	err = compress()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "compress: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func setup() error {
+	db, err := bolt.NewDB(dbname, dex.StdOutLogger("db_TEST", dex.LevelOff))
+	if err != nil {
+		return err
+	}
+	bdb, ok := db.(*bolt.BoltDB)
+	if !ok {
+		return fmt.Errorf("database is not a BoltDB")
+	}
+	defer bdb.DB.Close()
+	return mockData(db)
+}
+
+func mockData(tdb db.DB) error {
+	// One active and one archived order, each with a match.
+	for _, status := range []order.OrderStatus{order.OrderStatusBooked, order.OrderStatusExecuted} {
+		lo, _ := test.RandomLimitOrder()
+		lo.BaseAsset, lo.QuoteAsset = 42, 0
+		err := tdb.UpdateOrder(&db.MetaOrder{
+			MetaData: &db.OrderMetaData{
+				Status:     status,
+				Host:       "somedex.ltd",
+				Proof:      db.OrderProof{DEXSig: []byte{1}},
+				ChangeCoin: []byte{2, 3},
+			},
+			Order: lo,
+		})
+		if err != nil {
+			return err
+		}
+		match := &db.MetaMatch{
+			UserMatch: &order.UserMatch{
+				OrderID: lo.ID(),
+				MatchID: test.RandomMatchID(),
+				Address: test.RandomAddress(),
+				Status:  order.MakerSwapCast,
+			},
+			MetaData: &db.MatchMetaData{
+				DEX:   "somedex.ltd",
+				Base:  42,
+				Quote: 0,
+				Proof: db.MatchProof{MakerSwap: []byte{4, 5}},
+			},
+		}
+		if err := tdb.UpdateMatch(match); err != nil {
+			return err
+		}
+		fmt.Printf("order %s (%s), match %s\n", lo.ID(), status, match.MatchID)
+	}
+	return nil
+}
+
+func compress() error {
+	db, err := os.Open(dbname)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dbname)
+	defer db.Close()
+	dbgz, err := os.Create(dbname + ".gz")
+	if err != nil {
+		return err
+	}
+	defer dbgz.Close()
+	gz := gzip.NewWriter(dbgz)
+	_, err = io.Copy(gz, db)
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -42,6 +42,8 @@ var upgrades = [...]upgradefunc{
 	v5Upgrade,
 	// v5 => v6 splits matches into separate active and archived buckets.
 	v6Upgrade,
+	// v6 => v7 adds the search text to orders and matches.
+	v7Upgrade,
 }
 
 // DBVersion is the latest version of the database that is understood. Databases
@@ -366,6 +368,68 @@ func v6Upgrade(dbtx *bbolt.Tx) error {
 	})
 }
 
+// v7Upgrade stores the search text of every order and match, for
+// OrderFilter.Text searches. Orders and matches saved after the upgrade have
+// the search text set on every update.
+func v7Upgrade(dbtx *bbolt.Tx) error {
+	const oldVersion = 6
+
+	if err := ensureVersion(dbtx, oldVersion); err != nil {
+		return err
+	}
+
+	var nOrders, nMatches int
+
+	defer func() {
+		upgradeLog.Infof("Indexed %d orders and %d matches for search", nOrders, nMatches)
+	}()
+
+	for _, bktName := range [][]byte{activeOrdersBucket, archivedOrdersBucket} {
+		master := dbtx.Bucket(bktName)
+		if master == nil {
+			return fmt.Errorf("failed to open %s bucket", string(bktName))
+		}
+		err := master.ForEach(func(oid, _ []byte) error {
+			oBkt := master.Bucket(oid)
+			if oBkt == nil {
+				return fmt.Errorf("order %x bucket is not a bucket", oid)
+			}
+			mord, err := decodeOrderBucket(oid, oBkt)
+			if err != nil {
+				return err
+			}
+			nOrders++
+			return oBkt.Put(searchKey, []byte(dexdb.OrderSearchText(mord.Order, mord.MetaData)))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, bktName := range [][]byte{activeMatchesBucket, archivedMatchesBucket} {
+		master := dbtx.Bucket(bktName)
+		if master == nil {
+			return fmt.Errorf("failed to open %s bucket", string(bktName))
+		}
+		err := master.ForEach(func(k, _ []byte) error {
+			mBkt := master.Bucket(k)
+			if mBkt == nil {
+				return fmt.Errorf("match %x bucket is not a bucket", k)
+			}
+			m, err := loadMatchBucket(mBkt, false)
+			if err != nil {
+				return fmt.Errorf("error loading match %x: %w", k, err)
+			}
+			nMatches++
+			return mBkt.Put(searchKey, []byte(dexdb.MatchSearchText(m)))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func ensureVersion(tx *bbolt.Tx, ver uint32) error {
 	dbVersion, err := getVersionTx(tx)
 	if err != nil {
@@ -35,6 +35,7 @@ var dbUpgradeTests = [...]struct {
 	{"upgradeFromV3", v4Upgrade, verifyV4Upgrade, "v3.db.gz", 4},
 	{"upgradeFromV4", v5Upgrade, verifyV5Upgrade, "v4.db.gz", 5},
 	{"upgradeFromV5", v6Upgrade, verifyV6Upgrade, "v5.db.gz", 6},
+	{"upgradeFromV6", v7Upgrade, verifyV7Upgrade, "v6.db.gz", 7},
 }
 
 func TestUpgrades(t *testing.T) {
@@ -307,6 +308,61 @@ func verifyV6Upgrade(t *testing.T, db *bbolt.DB) {
 	}
 }
 
+func verifyV7Upgrade(t *testing.T, db *bbolt.DB) {
+	t.Helper()
+	// Orders and matches created by v6gen. The first order is active.
+	verifyOrders := []string{
+		"40b7af6638c69cf361cf1938a6a45ac402f657fba7b60483f3568185c5bcde98",
+		"6be0afef58db8d005a56d3ed86ac580c9be03313cc198b05977e8b97b3892e81",
+	}
+	verifyMatches := []string{
+		"68831b30a9d32afb5c66c0026c8f76106358c5e6fe43f7fffbcee74152055937",
+		"26cfb1377ef6e2def9a4f4344f48520ba8ccdd274401fb2bb421fb9b9de8c032",
+	}
+	err := db.View(func(dbtx *bbolt.Tx) error {
+		if err := checkVersion(dbtx, 7); err != nil {
+			return err
+		}
+		for i, bktName := range [][]byte{activeOrdersBucket, archivedOrdersBucket} {
+			oidB, _ := hex.DecodeString(verifyOrders[i])
+			oBkt := dbtx.Bucket(bktName).Bucket(oidB)
+			if oBkt == nil {
+				return fmt.Errorf("order %s not found", verifyOrders[i])
+			}
+			search := string(oBkt.Get(searchKey))
+			// The order ID and the change coin.
+			if !strings.Contains(search, verifyOrders[i]) || !strings.Contains(search, "0203") {
+				return fmt.Errorf("wrong search text for order %s: %q", verifyOrders[i], search)
+			}
+		}
+		matchSearch := make(map[string]string)
+		for _, bktName := range [][]byte{activeMatchesBucket, archivedMatchesBucket} {
+			master := dbtx.Bucket(bktName)
+			if err := master.ForEach(func(k, _ []byte) error {
+				mBkt := master.Bucket(k)
+				matchSearch[hex.EncodeToString(mBkt.Get(matchIDKey))] = string(mBkt.Get(searchKey))
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		for _, mid := range verifyMatches {
+			search, found := matchSearch[mid]
+			if !found {
+				return fmt.Errorf("match %s not found", mid)
+			}
+			// The match ID and the maker swap coin.
+			if !strings.Contains(search, mid) || !strings.Contains(search, "0405") {
+				return fmt.Errorf("wrong search text for match %s: %q", mid, search)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func checkVersion(dbtx *bbolt.Tx, expectedVersion uint32) error {
 	bkt := dbtx.Bucket(appBucket)
 	if bkt == nil {
@@ -0,0 +1,53 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package db
+
+import "encoding/hex"
+
+// IntegrityProblemKind is the type of problem found by an integrity check.
+type IntegrityProblemKind string
+
+const (
+	// ProblemCorruptFile is a structural problem reported by the database
+	// engine itself.
+	ProblemCorruptFile IntegrityProblemKind = "corruptFile"
+	// ProblemCorruptRecord is an order or match that can't be decoded.
+	ProblemCorruptRecord IntegrityProblemKind = "corruptRecord"
+	// ProblemOrphanedMatch is a match for an order that is not in the
+	// database.
+	ProblemOrphanedMatch IntegrityProblemKind = "orphanedMatch"
+	// ProblemMissingWallet is an active order or match for an asset that has
+	// no wallet in the database.
+	ProblemMissingWallet IntegrityProblemKind = "missingWallet"
+)
+
+// IntegrityProblem is a problem found by an integrity check.
+type IntegrityProblem struct {
+	Kind IntegrityProblemKind `json:"kind"`
+	// ID is the hex-encoded ID of the order or match with the problem, if
+	// applicable.
+	ID      string `json:"id,omitempty"`
+	Details string `json:"details"`
+}
+
+// IntegrityReport is the result of a database integrity check.
+type IntegrityReport struct {
+	// Orders and Matches are the number of records checked.
+	Orders  int `json:"orders"`
+	Matches int `json:"matches"`
+	// Size is the size of the database in bytes, and FreeBytes is the part of
+	// it that is unused and would be reclaimed by compaction.
+	Size      int64               `json:"size"`
+	FreeBytes int64               `json:"freeBytes"`
+	Problems  []*IntegrityProblem `json:"problems"`
+}
+
+// AddProblem adds a problem to the report.
+func (r *IntegrityReport) AddProblem(kind IntegrityProblemKind, id []byte, details string) {
+	r.Problems = append(r.Problems, &IntegrityProblem{
+		Kind:    kind,
+		ID:      hex.EncodeToString(id),
+		Details: details,
+	})
+}
@@ -87,6 +87,10 @@ type DB interface {
 	// LinkOrder sets the LinkedOrder field of the specified order's
 	// OrderMetaData.
 	LinkOrder(oid, linkedID order.OrderID) error
+	// SetOrderAnnotation sets the user's note and tags for the order, which
+	// are loaded as the OrderMetaData's Annotation. A nil or empty annotation
+	// removes any existing annotation.
+	SetOrderAnnotation(oid order.OrderID, a *Annotation) error
 	// UpdateMatch updates the match information in the database. Any existing
 	// entry for the match will be overwritten without indication.
 	UpdateMatch(m *MetaMatch) error
@@ -99,6 +103,10 @@ type DB interface {
 	DEXOrdersWithActiveMatches(dex string) ([]order.OrderID, error)
 	// MatchesForOrder gets the matches for the order ID.
 	MatchesForOrder(oid order.OrderID, excludeCancels bool) ([]*MetaMatch, error)
+	// SetMatchAnnotation sets the user's note and tags for the order's match,
+	// which are loaded as the MatchMetaData's Annotation. A nil or empty
+	// annotation removes any existing annotation.
+	SetMatchAnnotation(oid order.OrderID, mid order.MatchID, a *Annotation) error
 	// Update wallets adds a wallet to the database, or updates the wallet
 	// credentials if the wallet already exists. A wallet is specified by the
 	// pair (asset ID, account name).
@@ -118,10 +126,25 @@ type DB interface {
 	// BackupTo makes a backup of the database at the specified location,
 	// optionally overwriting any existing file and compacting the database.
 	BackupTo(dst string, overwrite, compact bool) error
-	// SaveNotification saves the notification.
+	// CheckIntegrity checks the consistency of the database, reporting
+	// corrupt records, orphaned matches, and active orders and matches for
+	// assets with no wallet.
+	CheckIntegrity() (*IntegrityReport, error)
+	// CompactOnShutdown requests that the database file be compacted when
+	// Run returns, regardless of the amount of unused space.
+	CompactOnShutdown()
+	// SaveNotification saves the notification. Notifications with a severity
+	// less than Poke are not saved.
 	SaveNotification(*Notification) error
-	// NotificationsN reads out the N most recent notifications.
+	// NotificationsN reads out the N most recent notifications with a
+	// severity of at least Success.
 	NotificationsN(int) ([]*Notification, error)
+	// SearchNotifications reads out the notifications that pass the filter,
+	// newest first.
+	SearchNotifications(*NoteFilter) ([]*Notification, error)
+	// DeleteNotifications deletes the notifications older than the unix
+	// millisecond timestamp, returning the number deleted.
+	DeleteNotifications(olderThanUnixMs uint64) (int, error)
 	// AckNotification sets the acknowledgement for a notification.
 	AckNotification(id []byte) error
 	// SavePokes saves a slice of notifications, overwriting any previously
@@ -155,4 +178,21 @@ type DB interface {
 	SetLanguage(lang string) error
 	// Language gets the language stored with SetLanguage.
 	Language() (string, error)
+	// SetTopicSettings stores the user's notification settings, replacing
+	// any previously stored settings.
+	SetTopicSettings(map[Topic]*TopicSettings) error
+	// TopicSettings gets the notification settings stored with
+	// SetTopicSettings.
+	TopicSettings() (map[Topic]*TopicSettings, error)
+	// SetNoteTemplates stores the user's notification templates, replacing
+	// any previously stored templates.
+	SetNoteTemplates(map[Topic]*NoteTemplate) error
+	// NoteTemplates gets the notification templates stored with
+	// SetNoteTemplates.
+	NoteTemplates() (map[Topic]*NoteTemplate, error)
+	// SetConfTargets stores the user's default confirmation targets for
+	// sends, keyed by asset ID, replacing any previously stored targets.
+	SetConfTargets(map[uint32]*ConfTarget) error
+	// ConfTargets gets the confirmation targets stored with SetConfTargets.
+	ConfTargets() (map[uint32]*ConfTarget, error)
 }
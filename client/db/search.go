@@ -0,0 +1,96 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package db
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"decred.org/dcrdex/dex/order"
+)
+
+// coinHashSize is the size of the transaction hash at the start of most coin
+// IDs.
+const coinHashSize = 32
+
+// OrderSearchText is the text indexed for the order and searched with
+// OrderFilter.Text: the order ID, the redemption address, the funding, change
+// and acceleration coin IDs, and the user's note and tags. Terms are lower case
+// and separated by spaces.
+func OrderSearchText(ord order.Order, md *OrderMetaData) string {
+	oid := ord.ID()
+	terms := []string{oid.String()}
+	if trade := ord.Trade(); trade != nil {
+		if trade.Address != "" {
+			terms = append(terms, strings.ToLower(trade.Address))
+		}
+		for _, coinID := range trade.Coins {
+			terms = appendCoinTerms(terms, coinID)
+		}
+	}
+	terms = appendCoinTerms(terms, md.ChangeCoin)
+	for _, coinID := range md.AccelerationCoins {
+		terms = appendCoinTerms(terms, coinID)
+	}
+	terms = appendAnnotationTerms(terms, md.Annotation)
+	return strings.Join(terms, " ")
+}
+
+// MatchSearchText is the text indexed for the match and searched with
+// OrderFilter.Text, which returns the match's order: the match ID, the
+// counterparty's address, the swap, redeem and refund coin IDs, and the user's
+// note and tags. Terms are lower case and separated by spaces.
+func MatchSearchText(m *MetaMatch) string {
+	terms := []string{m.MatchID.String()}
+	if m.Address != "" {
+		terms = append(terms, strings.ToLower(m.Address))
+	}
+	proof := &m.MetaData.Proof
+	for _, coinID := range []order.CoinID{proof.MakerSwap, proof.MakerRedeem,
+		proof.TakerSwap, proof.TakerRedeem, proof.RefundCoin} {
+		terms = appendCoinTerms(terms, coinID)
+	}
+	terms = appendAnnotationTerms(terms, m.MetaData.Annotation)
+	return strings.Join(terms, " ")
+}
+
+// appendAnnotationTerms appends the annotation's note and tags.
+func appendAnnotationTerms(terms []string, a *Annotation) []string {
+	if a == nil {
+		return terms
+	}
+	if note := strings.TrimSpace(a.Note); note != "" {
+		terms = append(terms, strings.ToLower(note))
+	}
+	for _, tag := range a.Tags {
+		terms = append(terms, strings.ToLower(tag))
+	}
+	return terms
+}
+
+// appendCoinTerms appends the coin ID as hex. Coin IDs are asset-specific and
+// can't be decoded here, but most start with a transaction hash, which UTXO
+// assets display byte-reversed, so the reversed hash is appended too. This
+// makes transaction IDs searchable as shown by the wallets and block
+// explorers.
+func appendCoinTerms(terms []string, coinID order.CoinID) []string {
+	if len(coinID) == 0 {
+		return terms
+	}
+	terms = append(terms, hex.EncodeToString(coinID))
+	if len(coinID) >= coinHashSize {
+		rev := make([]byte, coinHashSize)
+		for i := range rev {
+			rev[i] = coinID[coinHashSize-1-i]
+		}
+		terms = append(terms, hex.EncodeToString(rev))
+	}
+	return terms
+}
+
+// SearchTerm normalizes the OrderFilter.Text for comparison with the search
+// text.
+func SearchTerm(text string) string {
+	return strings.ToLower(strings.TrimSpace(text))
+}
@@ -0,0 +1,1041 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package sqlite is a SQLite database backend for Bison Wallet, satisfying the
+// db.DB interface defined at decred.org/dcrdex/client/db. The SQLite driver,
+// github.com/mattn/go-sqlite3, requires cgo and is only included in builds with
+// the sqlite build tag.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+)
+
+// driverName is the name that the SQLite driver registers with database/sql.
+const driverName = "sqlite3"
+
+// ErrNoDriver is returned from NewDB if the application was built without the
+// SQLite driver.
+const ErrNoDriver = dex.ErrorKind("built without SQLite support. rebuild with -tags sqlite")
+
+const backupDir = "backup"
+
+// Short names for some commonly used imported functions.
+var (
+	intCoder    = encode.IntCoder
+	uint64Bytes = encode.Uint64Bytes
+)
+
+// Opts is a set of options for the DB.
+type Opts struct {
+	BackupOnShutdown bool // default is true
+}
+
+var defaultOpts = Opts{
+	BackupOnShutdown: true,
+}
+
+// SQLiteDB is a SQLite-based database backend for Bison Wallet. SQLiteDB
+// satisfies the db.DB interface defined at decred.org/dcrdex/client/db.
+type SQLiteDB struct {
+	*sql.DB
+	path string
+	opts Opts
+	log  dex.Logger
+	// forceCompact is set by CompactOnShutdown.
+	forceCompact atomic.Bool
+}
+
+// Check that SQLiteDB satisfies the db.DB interface.
+var _ dexdb.DB = (*SQLiteDB)(nil)
+
+// NewDB is a constructor for a *SQLiteDB. The database file is created if it
+// does not exist.
+func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
+	return newDB(dbPath, logger, opts...)
+}
+
+// Supported is true if the application was built with the SQLite driver.
+func Supported() bool {
+	return slices.Contains(sql.Drivers(), driverName)
+}
+
+func newDB(dbPath string, logger dex.Logger, opts ...Opts) (*SQLiteDB, error) {
+	if !Supported() {
+		return nil, ErrNoDriver
+	}
+
+	_, err := os.Stat(dbPath)
+	isNew := os.IsNotExist(err)
+
+	// WAL mode allows reads concurrent with a write, and the busy timeout
+	// makes concurrent writers wait for the lock instead of failing. Write
+	// transactions take the lock immediately so that they are not aborted
+	// when upgrading from a read lock.
+	dsn := (&url.URL{
+		Scheme: "file",
+		Opaque: filepath.ToSlash(dbPath),
+		RawQuery: url.Values{
+			"_busy_timeout": []string{"5000"},
+			"_journal_mode": []string{"WAL"},
+			"_foreign_keys": []string{"1"},
+			"_txlock":       []string{"immediate"},
+		}.Encode(),
+	}).String()
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	sdb := &SQLiteDB{
+		DB:   db,
+		path: dbPath,
+		opts: defaultOpts,
+		log:  logger,
+	}
+	if len(opts) > 0 {
+		sdb.opts = opts[0]
+	}
+
+	var ver int
+	if err := db.QueryRow("PRAGMA user_version;").Scan(&ver); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error reading database version: %w", err)
+	}
+	if ver > DBVersion {
+		db.Close()
+		return nil, fmt.Errorf("unknown database version %d, latest is %d", ver, DBVersion)
+	}
+
+	// A new database has version 0 and gets the latest tables.
+	if ver > 0 && ver < DBVersion {
+		if err := sdb.upgradeDB(ver); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	if _, err := db.Exec(createTablesStmt); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating tables: %w", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d;", DBVersion)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error setting database version: %w", err)
+	}
+
+	if isNew {
+		sdb.log.Infof("Created and started database (version = %d, file = %s)", DBVersion, dbPath)
+	} else {
+		sdb.log.Infof("Started database (version = %d, file = %s)", DBVersion, dbPath)
+	}
+
+	return sdb, nil
+}
+
+// Path is the path of the database file.
+func (db *SQLiteDB) Path() string {
+	return db.path
+}
+
+// Run waits for context cancellation and closes the database.
+func (db *SQLiteDB) Run(ctx context.Context) {
+	<-ctx.Done() // wait for shutdown to backup and compact
+	defer db.Close()
+
+	// Create a backup in the backups folder.
+	if db.opts.BackupOnShutdown {
+		db.log.Infof("Backing up database...")
+		if err := db.Backup(); err != nil {
+			db.log.Errorf("Unable to backup database: %v", err)
+		}
+	}
+
+	// Only compact the DB file if there is excessive free space, in terms of
+	// bytes AND relative to total DB size.
+	const byteThresh = 1 << 18 // 256 KiB free
+	const pctThresh = 0.05     // 5% free
+	dbSize, freeBytes, err := db.sizes()
+	if err != nil {
+		db.log.Errorf("Unable to get database size: %v", err)
+		return
+	}
+	if dbSize == 0 {
+		return
+	}
+	pctFree := float64(freeBytes) / float64(dbSize)
+	db.log.Debugf("Total DB size %d bytes, %d bytes unused (%.2f%%)",
+		dbSize, freeBytes, 100*pctFree)
+	if !db.forceCompact.Load() && (freeBytes < byteThresh || pctFree < pctThresh) {
+		return
+	}
+
+	db.log.Infof("Compacting database to reclaim at least %d bytes...", freeBytes)
+	if _, err := db.Exec("VACUUM;"); err != nil {
+		db.log.Errorf("Unable to compact database: %v", err)
+		return
+	}
+	db.log.Infof("Compacted database from %d bytes", dbSize)
+}
+
+// sizes returns the size of the database, including unused pages, and the
+// unused space in bytes.
+func (db *SQLiteDB) sizes() (dbSize, freeBytes int64, err error) {
+	var pageSize, pageCount, freePages int64
+	if err := db.QueryRow("PRAGMA page_size;").Scan(&pageSize); err != nil {
+		return 0, 0, fmt.Errorf("unable to get page size: %w", err)
+	}
+	if err := db.QueryRow("PRAGMA page_count;").Scan(&pageCount); err != nil {
+		return 0, 0, fmt.Errorf("unable to get page count: %w", err)
+	}
+	if err := db.QueryRow("PRAGMA freelist_count;").Scan(&freePages); err != nil {
+		return 0, 0, fmt.Errorf("unable to get free page count: %w", err)
+	}
+	return pageCount * pageSize, freePages * pageSize, nil
+}
+
+// CompactOnShutdown requests that the database file be compacted when Run
+// returns, regardless of the amount of unused space.
+func (db *SQLiteDB) CompactOnShutdown() {
+	db.forceCompact.Store(true)
+}
+
+// update runs the function in a transaction, which is committed if the function
+// does not return an error.
+func (db *SQLiteDB) update(f func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// querier is satisfied by *sql.DB and *sql.Tx.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// requireRow returns an error if the statement did not affect any rows.
+func requireRow(res sql.Result, err error, notFound error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// Recrypt re-encrypts the wallet passwords and account private keys. As a
+// convenience, the provided *PrimaryCredentials are stored under the same
+// transaction.
+func (db *SQLiteDB) Recrypt(creds *dexdb.PrimaryCredentials, oldCrypter, newCrypter encrypt.Crypter) (walletUpdates map[uint32][]byte, acctUpdates map[string][]byte, err error) {
+	if err := validateCreds(creds); err != nil {
+		return nil, nil, err
+	}
+
+	walletUpdates = make(map[uint32][]byte)
+	acctUpdates = make(map[string][]byte)
+
+	return walletUpdates, acctUpdates, db.update(func(tx *sql.Tx) error {
+		wallets, err := loadWallets(tx, "")
+		if err != nil {
+			return fmt.Errorf("wallets update error: %w", err)
+		}
+		for _, w := range wallets {
+			if len(w.EncryptedPW) == 0 {
+				continue
+			}
+			pw, err := oldCrypter.Decrypt(w.EncryptedPW)
+			if err != nil {
+				return fmt.Errorf("Decrypt error: %w", err)
+			}
+			w.EncryptedPW, err = newCrypter.Encrypt(pw)
+			if err != nil {
+				return fmt.Errorf("Encrypt error: %w", err)
+			}
+			if _, err := tx.Exec("UPDATE wallets SET wallet = ? WHERE id = ?;", w.Encode(), w.ID()); err != nil {
+				return fmt.Errorf("wallets update error: %w", err)
+			}
+			walletUpdates[w.AssetID] = w.EncryptedPW
+		}
+
+		accts, err := db.loadAccounts(tx, "")
+		if err != nil {
+			return fmt.Errorf("accounts update error: %w", err)
+		}
+		for _, acctInfo := range accts {
+			if len(acctInfo.LegacyEncKey) != 0 {
+				privB, err := oldCrypter.Decrypt(acctInfo.LegacyEncKey)
+				if err != nil {
+					return err
+				}
+				acctInfo.LegacyEncKey, err = newCrypter.Encrypt(privB)
+				if err != nil {
+					return err
+				}
+				acctUpdates[acctInfo.Host] = acctInfo.LegacyEncKey
+			} else if len(acctInfo.EncKeyV2) > 0 {
+				privB, err := oldCrypter.Decrypt(acctInfo.EncKeyV2)
+				if err != nil {
+					return err
+				}
+				acctInfo.EncKeyV2, err = newCrypter.Encrypt(privB)
+				if err != nil {
+					return err
+				}
+				acctUpdates[acctInfo.Host] = acctInfo.EncKeyV2
+			}
+			if _, err := tx.Exec("UPDATE accounts SET info = ? WHERE host = ?;", acctInfo.Encode(), acctInfo.Host); err != nil {
+				return fmt.Errorf("accounts update error: %w", err)
+			}
+		}
+
+		// Store the new credentials.
+		return setCreds(tx, creds)
+	})
+}
+
+// SetPrimaryCredentials validates and stores the PrimaryCredentials.
+func (db *SQLiteDB) SetPrimaryCredentials(creds *dexdb.PrimaryCredentials) error {
+	if err := validateCreds(creds); err != nil {
+		return err
+	}
+	return setCreds(db, creds)
+}
+
+// validateCreds checks that the PrimaryCredentials fields are properly
+// populated.
+func validateCreds(creds *dexdb.PrimaryCredentials) error {
+	if len(creds.EncSeed) == 0 {
+		return errors.New("EncSeed not set")
+	}
+	if len(creds.EncInnerKey) == 0 {
+		return errors.New("EncInnerKey not set")
+	}
+	if len(creds.InnerKeyParams) == 0 {
+		return errors.New("InnerKeyParams not set")
+	}
+	if len(creds.OuterKeyParams) == 0 {
+		return errors.New("OuterKeyParams not set")
+	}
+	return nil
+}
+
+// setCreds stores the *PrimaryCredentials.
+func setCreds(q querier, creds *dexdb.PrimaryCredentials) error {
+	_, err := q.Exec(`INSERT OR REPLACE INTO credentials (id, enc_seed, enc_inner_key,
+		inner_key_params, outer_key_params, birthday, version) VALUES (0, ?, ?, ?, ?, ?, ?);`,
+		creds.EncSeed, creds.EncInnerKey, creds.InnerKeyParams, creds.OuterKeyParams,
+		creds.Birthday.Unix(), creds.Version)
+	return err
+}
+
+// PrimaryCredentials retrieves the *PrimaryCredentials, if they are stored. It
+// is an error if none have been stored.
+func (db *SQLiteDB) PrimaryCredentials() (*dexdb.PrimaryCredentials, error) {
+	var creds dexdb.PrimaryCredentials
+	var bday int64
+	err := db.QueryRow(`SELECT enc_seed, enc_inner_key, inner_key_params, outer_key_params,
+		birthday, version FROM credentials WHERE id = 0;`).Scan(&creds.EncSeed, &creds.EncInnerKey,
+		&creds.InnerKeyParams, &creds.OuterKeyParams, &bday, &creds.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dexdb.ErrNoCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bday > 0 {
+		creds.Birthday = time.Unix(bday, 0)
+	}
+	return &creds, nil
+}
+
+// setMeta stores the value in the meta table.
+func setMeta(q querier, key string, value []byte) error {
+	_, err := q.Exec("INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?);", key, value)
+	return err
+}
+
+// meta retrieves the value from the meta table. If the key is not found, a nil
+// value is returned without an error.
+func meta(q querier, key string) (value []byte, err error) {
+	err = q.QueryRow("SELECT value FROM meta WHERE key = ?;", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// setMetaJSON stores the JSON-encoded value in the meta table.
+func setMetaJSON(q querier, key string, thing any) error {
+	b, err := json.Marshal(thing)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return setMeta(q, key, b)
+}
+
+// metaJSON decodes the JSON-encoded value from the meta table into thing. If
+// the key is not found, thing is not modified.
+func metaJSON(q querier, key string, thing any) error {
+	b, err := meta(q, key)
+	if err != nil || len(b) == 0 {
+		return err
+	}
+	return json.Unmarshal(b, thing)
+}
+
+// SetSeedGenerationTime stores the time the app seed was generated.
+func (db *SQLiteDB) SetSeedGenerationTime(time uint64) error {
+	return setMeta(db, seedGenTimeKey, uint64Bytes(time))
+}
+
+// SeedGenerationTime returns the time the app seed was generated, if it was
+// stored. It returns dexdb.ErrNoSeedGenTime if it was not stored.
+func (db *SQLiteDB) SeedGenerationTime() (uint64, error) {
+	b, err := meta(db, seedGenTimeKey)
+	if err != nil {
+		return 0, err
+	}
+	if b == nil {
+		return 0, dexdb.ErrNoSeedGenTime
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("seed generation time length %v, expected 8", len(b))
+	}
+	return intCoder.Uint64(b), nil
+}
+
+// ListAccounts returns a list of DEX URLs. The DB is designed to have a single
+// account per DEX, so the account itself is identified by the DEX URL.
+func (db *SQLiteDB) ListAccounts() ([]string, error) {
+	rows, err := db.Query("SELECT host FROM accounts WHERE active = 1 ORDER BY host;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hosts []string
+	for rows.Next() {
+		var host string
+		if err := rows.Scan(&host); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, rows.Err()
+}
+
+// loadAccounts loads the accounts and their bonds. If host is not empty, only
+// the account for that host is loaded.
+func (db *SQLiteDB) loadAccounts(q querier, host string) ([]*dexdb.AccountInfo, error) {
+	query, args := "SELECT host, info, active FROM accounts ORDER BY host;", []any{}
+	if host != "" {
+		query, args = "SELECT host, info, active FROM accounts WHERE host = ?;", []any{host}
+	}
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var accts []*dexdb.AccountInfo
+	acctMap := make(map[string]*dexdb.AccountInfo)
+	for rows.Next() {
+		var host string
+		var acctB []byte
+		var active bool
+		if err := rows.Scan(&host, &acctB, &active); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if len(acctB) == 0 {
+			rows.Close()
+			return nil, fmt.Errorf("empty account found for %s", host)
+		}
+		acctInfo, err := dexdb.DecodeAccountInfo(acctB)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		acctInfo.Disabled = !active
+		accts = append(accts, acctInfo)
+		acctMap[host] = acctInfo
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(accts) == 0 {
+		return nil, nil
+	}
+
+	query, args = "SELECT host, bond, confirmed, refunded FROM bonds ORDER BY host, uid;", []any{}
+	if host != "" {
+		query, args = "SELECT host, bond, confirmed, refunded FROM bonds WHERE host = ? ORDER BY uid;", []any{host}
+	}
+	rows, err = q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var host string
+		var bondB []byte
+		var confirmed, refunded bool
+		if err := rows.Scan(&host, &bondB, &confirmed, &refunded); err != nil {
+			return nil, err
+		}
+		bond, err := dexdb.DecodeBond(bondB)
+		if err != nil {
+			db.log.Errorf("Invalid bond data encoding: %v", err)
+			continue
+		}
+		bond.Confirmed, bond.Refunded = confirmed, refunded
+		if acctInfo := acctMap[host]; acctInfo != nil {
+			acctInfo.Bonds = append(acctInfo.Bonds, bond)
+		}
+	}
+	return accts, rows.Err()
+}
+
+// Accounts returns a list of DEX Accounts. The DB is designed to have a single
+// account per DEX, so the account itself is identified by the DEX host.
+func (db *SQLiteDB) Accounts() ([]*dexdb.AccountInfo, error) {
+	return db.loadAccounts(db, "")
+}
+
+// Account gets the AccountInfo associated with the specified DEX address.
+func (db *SQLiteDB) Account(host string) (*dexdb.AccountInfo, error) {
+	accts, err := db.loadAccounts(db, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(accts) == 0 {
+		return nil, dexdb.ErrAcctNotFound
+	}
+	return accts[0], nil
+}
+
+// CreateAccount saves the AccountInfo. If an account already exists for this
+// DEX, it will return an error.
+func (db *SQLiteDB) CreateAccount(ai *dexdb.AccountInfo) error {
+	if ai.Host == "" {
+		return fmt.Errorf("empty host not allowed")
+	}
+	if ai.DEXPubKey == nil {
+		return fmt.Errorf("nil DEXPubKey not allowed")
+	}
+	return db.update(func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM accounts WHERE host = ?);", ai.Host).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("account already exists for %s", ai.Host)
+		}
+		if _, err := tx.Exec("INSERT INTO accounts (host, info, active) VALUES (?, ?, 1);", ai.Host, ai.Encode()); err != nil {
+			return fmt.Errorf("error inserting account: %w", err)
+		}
+		for _, bond := range ai.Bonds {
+			if err := storeBond(tx, ai.Host, bond); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NextBondKeyIndex returns the next bond key index and increments the stored
+// value so that subsequent calls will always return a higher index.
+func (db *SQLiteDB) NextBondKeyIndex(assetID uint32) (uint32, error) {
+	var bondIndex uint32
+	return bondIndex, db.update(func(tx *sql.Tx) error {
+		err := tx.QueryRow("SELECT next_index FROM bond_indexes WHERE asset_id = ?;", assetID).Scan(&bondIndex)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return setBondKeyIndex(tx, assetID, bondIndex+1)
+	})
+}
+
+// setBondKeyIndex sets the next bond key index for the asset.
+func setBondKeyIndex(q querier, assetID, idx uint32) error {
+	_, err := q.Exec("INSERT OR REPLACE INTO bond_indexes (asset_id, next_index) VALUES (?, ?);", assetID, idx)
+	return err
+}
+
+// UpdateAccountInfo updates the account info for an existing account with
+// the same Host as the parameter. If no account exists with this host,
+// an error is returned.
+func (db *SQLiteDB) UpdateAccountInfo(ai *dexdb.AccountInfo) error {
+	return db.update(func(tx *sql.Tx) error {
+		res, err := tx.Exec("UPDATE accounts SET info = ? WHERE host = ?;", ai.Encode(), ai.Host)
+		if err := requireRow(res, err, fmt.Errorf("account not found for %s", ai.Host)); err != nil {
+			return err
+		}
+		for _, bond := range ai.Bonds {
+			if err := storeBond(tx, ai.Host, bond); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ToggleAccountStatus enables or disables the account associated with the given
+// host.
+func (db *SQLiteDB) ToggleAccountStatus(host string, disable bool) error {
+	return db.update(func(tx *sql.Tx) error {
+		var active bool
+		err := tx.QueryRow("SELECT active FROM accounts WHERE host = ?;", host).Scan(&active)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("account not found for %s", host)
+		}
+		if err != nil {
+			return err
+		}
+		if active != disable {
+			msg := "account is already enabled"
+			if disable {
+				msg = "account is already disabled"
+			}
+			return errors.New(msg)
+		}
+		_, err = tx.Exec("UPDATE accounts SET active = ? WHERE host = ?;", !disable, host)
+		return err
+	})
+}
+
+// storeBond inserts or updates the bond.
+func storeBond(q querier, host string, bond *dexdb.Bond) error {
+	_, err := q.Exec(`INSERT OR REPLACE INTO bonds (host, uid, asset_id, bond, confirmed, refunded, lock_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`, host, bond.UniqueID(), bond.AssetID, bond.Encode(),
+		bond.Confirmed, bond.Refunded, int64(bond.LockTime))
+	if err != nil {
+		return fmt.Errorf("error storing bond: %w", err)
+	}
+	return nil
+}
+
+// AddBond saves a new Bond or updates an existing bond for an existing DEX
+// account.
+func (db *SQLiteDB) AddBond(host string, bond *dexdb.Bond) error {
+	return db.update(func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM accounts WHERE host = ?);", host).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("account not found for %s", host)
+		}
+		return storeBond(tx, host, bond)
+	})
+}
+
+// ConfirmBond marks a DEX account bond as confirmed by the DEX.
+func (db *SQLiteDB) ConfirmBond(host string, assetID uint32, bondCoinID []byte) error {
+	bondUID := dexdb.BondUID(assetID, bondCoinID)
+	res, err := db.Exec("UPDATE bonds SET confirmed = 1 WHERE host = ? AND uid = ?;", host, bondUID)
+	return requireRow(res, err, fmt.Errorf("bond does not exist: %x", bondUID))
+}
+
+// BondRefunded marks a DEX account bond as refunded by the client wallet.
+func (db *SQLiteDB) BondRefunded(host string, assetID uint32, bondCoinID []byte) error {
+	bondUID := dexdb.BondUID(assetID, bondCoinID)
+	res, err := db.Exec("UPDATE bonds SET refunded = 1 WHERE host = ? AND uid = ?;", host, bondUID)
+	return requireRow(res, err, fmt.Errorf("bond does not exist: %x", bondUID))
+}
+
+// UpdateWallet adds a wallet to the database, or updates the wallet if it
+// already exists.
+func (db *SQLiteDB) UpdateWallet(wallet *dexdb.Wallet) error {
+	if wallet.Balance == nil {
+		return fmt.Errorf("cannot UpdateWallet with nil Balance field")
+	}
+	_, err := db.Exec(`INSERT INTO wallets (id, asset_id, wallet, balance) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET wallet = excluded.wallet, balance = excluded.balance;`,
+		wallet.ID(), wallet.AssetID, wallet.Encode(), wallet.Balance.Encode())
+	return err
+}
+
+// SetWalletPassword set the encrypted password field for the wallet.
+func (db *SQLiteDB) SetWalletPassword(wid []byte, newEncPW []byte) error {
+	return db.update(func(tx *sql.Tx) error {
+		var b []byte
+		err := tx.QueryRow("SELECT wallet FROM wallets WHERE id = ?;", wid).Scan(&b)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("wallet with ID is %x not known", wid)
+		}
+		if err != nil {
+			return err
+		}
+		wallet, err := dexdb.DecodeWallet(b)
+		if err != nil {
+			return err
+		}
+		wallet.EncryptedPW = make([]byte, len(newEncPW))
+		copy(wallet.EncryptedPW, newEncPW)
+		_, err = tx.Exec("UPDATE wallets SET wallet = ? WHERE id = ?;", wallet.Encode(), wid)
+		return err
+	})
+}
+
+// UpdateBalance updates a wallet's balance.
+func (db *SQLiteDB) UpdateBalance(wid []byte, bal *dexdb.Balance) error {
+	res, err := db.Exec("UPDATE wallets SET balance = ? WHERE id = ?;", bal.Encode(), wid)
+	return requireRow(res, err, fmt.Errorf("wallet %x not found", wid))
+}
+
+// UpdateWalletStatus updates a wallet's status.
+func (db *SQLiteDB) UpdateWalletStatus(wid []byte, disable bool) error {
+	res, err := db.Exec("UPDATE wallets SET disabled = ? WHERE id = ?;", disable, wid)
+	return requireRow(res, err, fmt.Errorf("wallet %x not found", wid))
+}
+
+// loadWallets loads the wallets. If wid is not empty, only that wallet is
+// loaded.
+func loadWallets(q querier, wid string) ([]*dexdb.Wallet, error) {
+	query, args := "SELECT wallet, balance, disabled FROM wallets ORDER BY id;", []any{}
+	if wid != "" {
+		query, args = "SELECT wallet, balance, disabled FROM wallets WHERE id = ?;", []any{[]byte(wid)}
+	}
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var wallets []*dexdb.Wallet
+	for rows.Next() {
+		var walletB, balB []byte
+		var disabled bool
+		if err := rows.Scan(&walletB, &balB, &disabled); err != nil {
+			return nil, err
+		}
+		w, err := dexdb.DecodeWallet(walletB)
+		if err != nil {
+			return nil, fmt.Errorf("DecodeWallet error: %w", err)
+		}
+		if len(balB) > 0 {
+			if w.Balance, err = dexdb.DecodeBalance(balB); err != nil {
+				return nil, fmt.Errorf("DecodeBalance error: %w", err)
+			}
+		}
+		w.Disabled = disabled
+		wallets = append(wallets, w)
+	}
+	return wallets, rows.Err()
+}
+
+// Wallets loads all wallets from the database.
+func (db *SQLiteDB) Wallets() ([]*dexdb.Wallet, error) {
+	return loadWallets(db, "")
+}
+
+// Wallet loads a single wallet from the database.
+func (db *SQLiteDB) Wallet(wid []byte) (*dexdb.Wallet, error) {
+	wallets, err := loadWallets(db, string(wid))
+	if err != nil {
+		return nil, err
+	}
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("wallet %x not found", wid)
+	}
+	return wallets[0], nil
+}
+
+// SaveNotification saves the notification.
+func (db *SQLiteDB) SaveNotification(note *dexdb.Notification) error {
+	if note.Severeness < dexdb.Poke {
+		return fmt.Errorf("storage of notification with severity %s is forbidden", note.Severeness)
+	}
+	_, err := db.Exec(`INSERT INTO notes (id, type, topic, subject, details, severity, stamp, note)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO UPDATE SET type = excluded.type,
+		topic = excluded.topic, subject = excluded.subject, details = excluded.details,
+		severity = excluded.severity, stamp = excluded.stamp, note = excluded.note;`,
+		[]byte(note.ID()), note.NoteType, string(note.TopicID), note.SubjectText, note.DetailText,
+		note.Severeness, int64(note.TimeStamp), note.Encode())
+	return err
+}
+
+// AckNotification sets the acknowledgement for a notification.
+func (db *SQLiteDB) AckNotification(id []byte) error {
+	res, err := db.Exec("UPDATE notes SET ack = 1 WHERE id = ?;", id)
+	return requireRow(res, err, errors.New("notification not found"))
+}
+
+// scanNotes decodes the notes from rows of (note, ack), stopping after n notes
+// that pass the filter. n = 0 applies no limit.
+func scanNotes(rows *sql.Rows, n int, filter func(*dexdb.Notification) bool) ([]*dexdb.Notification, error) {
+	defer rows.Close()
+	var notes []*dexdb.Notification
+	for rows.Next() && (n == 0 || len(notes) < n) {
+		var noteB []byte
+		var ack bool
+		if err := rows.Scan(&noteB, &ack); err != nil {
+			return nil, err
+		}
+		note, err := dexdb.DecodeNotification(noteB)
+		if err != nil {
+			return nil, err
+		}
+		if filter != nil && !filter(note) {
+			continue
+		}
+		note.Ack = ack
+		note.Id = note.ID()
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// NotificationsN reads out the N most recent notifications with a severity of
+// at least Success. Pokes are stored for searching, but are not included.
+func (db *SQLiteDB) NotificationsN(n int) ([]*dexdb.Notification, error) {
+	rows, err := db.Query(`SELECT note, ack FROM notes WHERE severity >= ?
+		ORDER BY stamp DESC, id DESC LIMIT ?;`, dexdb.Success, sqlLimit(n))
+	if err != nil {
+		return nil, err
+	}
+	return scanNotes(rows, 0, nil)
+}
+
+// SearchNotifications reads out the notifications that pass the filter, newest
+// first.
+func (db *SQLiteDB) SearchNotifications(filter *dexdb.NoteFilter) ([]*dexdb.Notification, error) {
+	var where []string
+	var args []any
+	if filter.SinceUnixMs > 0 {
+		where, args = append(where, "stamp >= ?"), append(args, int64(filter.SinceUnixMs))
+	}
+	if filter.UntilUnixMs > 0 {
+		where, args = append(where, "stamp <= ?"), append(args, int64(filter.UntilUnixMs))
+	}
+	if filter.MinSeverity > 0 {
+		where, args = append(where, "severity >= ?"), append(args, filter.MinSeverity)
+	}
+	if filter.MaxSeverity > 0 {
+		where, args = append(where, "severity <= ?"), append(args, filter.MaxSeverity)
+	}
+	if len(filter.Topics) > 0 {
+		where = append(where, "topic IN ("+placeholders(len(filter.Topics))+")")
+		for _, topic := range filter.Topics {
+			args = append(args, string(topic))
+		}
+	}
+	query := "SELECT note, ack FROM notes"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY stamp DESC, id DESC"
+
+	// The text filter is applied to the decoded notes, so that case folding
+	// matches the bolt backend for all languages.
+	text := strings.ToLower(filter.Text)
+	if text == "" {
+		query += " LIMIT ?"
+		args = append(args, sqlLimit(filter.N))
+	}
+	rows, err := db.Query(query+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return scanNotes(rows, 0, nil)
+	}
+	return scanNotes(rows, filter.N, func(note *dexdb.Notification) bool {
+		return strings.Contains(strings.ToLower(note.SubjectText), text) ||
+			strings.Contains(strings.ToLower(note.DetailText), text)
+	})
+}
+
+// DeleteNotifications deletes the notifications older than the unix
+// millisecond timestamp, returning the number deleted.
+func (db *SQLiteDB) DeleteNotifications(olderThanUnixMs uint64) (int, error) {
+	res, err := db.Exec("DELETE FROM notes WHERE stamp < ?;", int64(olderThanUnixMs))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// SavePokes saves a slice of notifications, overwriting any previously saved
+// slice.
+func (db *SQLiteDB) SavePokes(pokes []*dexdb.Notification) error {
+	return setMetaJSON(db, pokesKey, pokes)
+}
+
+// LoadPokes loads the slice of notifications last saved with SavePokes. The
+// loaded pokes are deleted from the database.
+func (db *SQLiteDB) LoadPokes() (pokes []*dexdb.Notification, _ error) {
+	return pokes, db.update(func(tx *sql.Tx) error {
+		if err := metaJSON(tx, pokesKey, &pokes); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DELETE FROM meta WHERE key = ?;", pokesKey)
+		return err
+	})
+}
+
+// SaveDisabledRateSources updates disabled fiat rate sources.
+func (db *SQLiteDB) SaveDisabledRateSources(disabledSources []string) error {
+	return setMeta(db, disabledRateSourceKey, []byte(strings.Join(disabledSources, ",")))
+}
+
+// DisabledRateSources retrieves a map of disabled fiat rate sources.
+func (db *SQLiteDB) DisabledRateSources() (disabledSources []string, err error) {
+	b, err := meta(db, disabledRateSourceKey)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+	disabled := strings.Split(string(b), ",")
+	disabledSources = make([]string, 0, len(disabled))
+	for _, token := range disabled {
+		if token != "" {
+			disabledSources = append(disabledSources, token)
+		}
+	}
+	return disabledSources, nil
+}
+
+// SetLanguage stores the language.
+func (db *SQLiteDB) SetLanguage(lang string) error {
+	return setMeta(db, langKey, []byte(lang))
+}
+
+// Language retrieves the language stored with SetLanguage. If no language
+// has been stored, an empty string is returned without an error.
+func (db *SQLiteDB) Language() (string, error) {
+	b, err := meta(db, langKey)
+	return string(b), err
+}
+
+// SetTopicSettings stores the notification settings, replacing any previously
+// stored settings.
+func (db *SQLiteDB) SetTopicSettings(settings map[dexdb.Topic]*dexdb.TopicSettings) error {
+	return setMetaJSON(db, topicSettingsKey, settings)
+}
+
+// TopicSettings retrieves the notification settings stored with
+// SetTopicSettings. If no settings have been stored, an empty map is returned
+// without an error.
+func (db *SQLiteDB) TopicSettings() (map[dexdb.Topic]*dexdb.TopicSettings, error) {
+	settings := make(map[dexdb.Topic]*dexdb.TopicSettings)
+	return settings, metaJSON(db, topicSettingsKey, &settings)
+}
+
+// SetNoteTemplates stores the notification templates, replacing any previously
+// stored templates.
+func (db *SQLiteDB) SetNoteTemplates(templates map[dexdb.Topic]*dexdb.NoteTemplate) error {
+	return setMetaJSON(db, noteTemplatesKey, templates)
+}
+
+// NoteTemplates retrieves the notification templates stored with
+// SetNoteTemplates. If no templates have been stored, an empty map is returned
+// without an error.
+func (db *SQLiteDB) NoteTemplates() (map[dexdb.Topic]*dexdb.NoteTemplate, error) {
+	templates := make(map[dexdb.Topic]*dexdb.NoteTemplate)
+	return templates, metaJSON(db, noteTemplatesKey, &templates)
+}
+
+// SetConfTargets stores the confirmation targets, replacing any previously
+// stored targets.
+func (db *SQLiteDB) SetConfTargets(targets map[uint32]*dexdb.ConfTarget) error {
+	return setMetaJSON(db, confTargetsKey, targets)
+}
+
+// ConfTargets retrieves the confirmation targets stored with SetConfTargets.
+// If no targets have been stored, an empty map is returned without an error.
+func (db *SQLiteDB) ConfTargets() (map[uint32]*dexdb.ConfTarget, error) {
+	targets := make(map[uint32]*dexdb.ConfTarget)
+	return targets, metaJSON(db, confTargetsKey, &targets)
+}
+
+// BackupTo makes a copy of the database to the specified file, optionally
+// overwriting the destination. SQLite backups are always compacted.
+func (db *SQLiteDB) BackupTo(dst string, overwrite, _ bool) error {
+	// If relative path, use current db path.
+	if !filepath.IsAbs(dst) {
+		dst = filepath.Join(filepath.Dir(db.path), dst)
+	}
+	dst = filepath.Clean(dst)
+	if dst == filepath.Clean(db.path) {
+		return errors.New("destination is the active DB")
+	}
+
+	// Make the parent folder if it does not exists.
+	dir := filepath.Dir(dst)
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("unable to create backup directory: %w", err)
+		}
+	}
+
+	// VACUUM INTO will not write to an existing file.
+	if _, err := os.Stat(dst); err == nil {
+		if !overwrite {
+			return fmt.Errorf("backup file %s already exists", dst)
+		}
+		if err := os.Remove(dst); err != nil {
+			return fmt.Errorf("unable to remove existing backup: %w", err)
+		}
+	}
+	_, err := db.Exec("VACUUM INTO ?;", dst)
+	return err
+}
+
+// Backup makes a copy of the database in the "backup" folder, overwriting any
+// existing backup.
+func (db *SQLiteDB) Backup() error {
+	dir, file := filepath.Split(db.path)
+	return db.BackupTo(filepath.Join(dir, backupDir, file), true, false)
+}
+
+// placeholders is a comma-separated list of n query parameter placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// sqlLimit converts a zero "no limit" count into SQLite's -1.
+func sqlLimit(n int) int {
+	if n <= 0 {
+		return -1
+	}
+	return n
+}
+
+// timeNow is the current unix timestamp in milliseconds.
+func timeNow() uint64 {
+	return uint64(time.Now().UnixMilli())
+}
@@ -0,0 +1,575 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"encoding/hex"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/db/bolt"
+	dbtest "decred.org/dcrdex/client/db/test"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+)
+
+var (
+	tLogger = dex.StdOutLogger("db_TEST", dex.LevelTrace)
+)
+
+func newTestDB(t *testing.T) (*SQLiteDB, func()) {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "db.sqlite")
+	sdb, err := newDB(dbPath, tLogger)
+	if err != nil {
+		t.Fatalf("error creating dB: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sdb.Run(ctx)
+	}()
+	shutdown := func() {
+		cancel()
+		wg.Wait()
+	}
+	return sdb, shutdown
+}
+
+func randBytes(l int) []byte {
+	b := make([]byte, l)
+	rand.Read(b)
+	return b
+}
+
+func randOrderForMarket(base, quote uint32) order.Order {
+	switch rand.Intn(3) {
+	case 0:
+		o, _ := ordertest.RandomCancelOrder()
+		o.BaseAsset = base
+		o.QuoteAsset = quote
+		return o
+	case 1:
+		o, _ := ordertest.RandomMarketOrder()
+		o.BaseAsset = base
+		o.QuoteAsset = quote
+		return o
+	default:
+		o, _ := ordertest.RandomLimitOrder()
+		o.BaseAsset = base
+		o.QuoteAsset = quote
+		return o
+	}
+}
+
+func randomMetaOrder(host string, status order.OrderStatus) *db.MetaOrder {
+	return &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status:             status,
+			Host:               host,
+			Proof:              db.OrderProof{DEXSig: randBytes(73)},
+			SwapFeesPaid:       rand.Uint64(),
+			RedemptionFeesPaid: rand.Uint64(),
+			MaxFeeRate:         rand.Uint64(),
+		},
+		Order: randOrderForMarket(rand.Uint32(), rand.Uint32()),
+	}
+}
+
+func TestBackup(t *testing.T) {
+	sdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	if err := sdb.Backup(); err != nil {
+		t.Fatalf("unable to backup database: %v", err)
+	}
+	path := filepath.Join(filepath.Dir(sdb.Path()), backupDir, filepath.Base(sdb.Path()))
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		t.Fatalf("backup file does not exist: %v", err)
+	}
+	// Overwrite the backup.
+	if err := sdb.Backup(); err != nil {
+		t.Fatalf("unable to overwrite backup: %v", err)
+	}
+	// Don't overwrite.
+	if err := sdb.BackupTo(path, false, false); err == nil {
+		t.Fatalf("no error for existing backup without overwrite")
+	}
+}
+
+func TestPrimaryCredentials(t *testing.T) {
+	sdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	if _, err := sdb.PrimaryCredentials(); err != db.ErrNoCredentials {
+		t.Fatalf("wrong error for missing credentials: %v", err)
+	}
+	creds := dbtest.RandomPrimaryCredentials()
+	if err := sdb.SetPrimaryCredentials(creds); err != nil {
+		t.Fatalf("SetPrimaryCredentials error: %v", err)
+	}
+	reCreds, err := sdb.PrimaryCredentials()
+	if err != nil {
+		t.Fatalf("PrimaryCredentials error: %v", err)
+	}
+	if string(reCreds.EncSeed) != string(creds.EncSeed) || reCreds.Version != creds.Version ||
+		reCreds.Birthday.Unix() != creds.Birthday.Unix() {
+		t.Fatalf("wrong credentials")
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	sdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	acct := dbtest.RandomAccountInfo()
+	if err := sdb.CreateAccount(acct); err != nil {
+		t.Fatalf("CreateAccount error: %v", err)
+	}
+	if err := sdb.CreateAccount(acct); err == nil {
+		t.Fatalf("no error for duplicate account")
+	}
+	reAcct, err := sdb.Account(acct.Host)
+	if err != nil {
+		t.Fatalf("Account error: %v", err)
+	}
+	dbtest.MustCompareAccountInfo(t, acct, reAcct)
+
+	if err := sdb.ToggleAccountStatus(acct.Host, true); err != nil {
+		t.Fatalf("ToggleAccountStatus error: %v", err)
+	}
+	if err := sdb.ToggleAccountStatus(acct.Host, true); err == nil {
+		t.Fatalf("no error for disabling a disabled account")
+	}
+	hosts, err := sdb.ListAccounts()
+	if err != nil {
+		t.Fatalf("ListAccounts error: %v", err)
+	}
+	if len(hosts) != 0 {
+		t.Fatalf("disabled account listed")
+	}
+
+	for i := uint32(0); i < 3; i++ {
+		idx, err := sdb.NextBondKeyIndex(42)
+		if err != nil {
+			t.Fatalf("NextBondKeyIndex error: %v", err)
+		}
+		if idx != i {
+			t.Fatalf("wrong bond key index %d, wanted %d", idx, i)
+		}
+	}
+}
+
+func TestWallets(t *testing.T) {
+	sdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	w := dbtest.RandomWallet()
+	if err := sdb.UpdateWallet(w); err != nil {
+		t.Fatalf("UpdateWallet error: %v", err)
+	}
+	reW, err := sdb.Wallet(w.ID())
+	if err != nil {
+		t.Fatalf("Wallet error: %v", err)
+	}
+	dbtest.MustCompareWallets(t, reW, w)
+
+	if err := sdb.UpdateWalletStatus(w.ID(), true); err != nil {
+		t.Fatalf("UpdateWalletStatus error: %v", err)
+	}
+	wallets, err := sdb.Wallets()
+	if err != nil {
+		t.Fatalf("Wallets error: %v", err)
+	}
+	if len(wallets) != 1 || !wallets[0].Disabled {
+		t.Fatalf("wallet not disabled")
+	}
+}
+
+func TestOrdersAndMatches(t *testing.T) {
+	sdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	acct := dbtest.RandomAccountInfo()
+	if err := sdb.CreateAccount(acct); err != nil {
+		t.Fatalf("CreateAccount error: %v", err)
+	}
+
+	active := randomMetaOrder(acct.Host, order.OrderStatusBooked)
+	inactive := randomMetaOrder(acct.Host, order.OrderStatusExecuted)
+	for _, mo := range []*db.MetaOrder{active, inactive} {
+		if err := sdb.UpdateOrder(mo); err != nil {
+			t.Fatalf("UpdateOrder error: %v", err)
+		}
+		reOrd, err := sdb.Order(mo.Order.ID())
+		if err != nil {
+			t.Fatalf("Order error: %v", err)
+		}
+		ordertest.MustCompareOrders(t, reOrd.Order, mo.Order)
+		if reOrd.MetaData.SwapFeesPaid != mo.MetaData.SwapFeesPaid ||
+			reOrd.MetaData.MaxFeeRate != mo.MetaData.MaxFeeRate {
+			t.Fatalf("wrong order metadata")
+		}
+	}
+
+	activeOrders, err := sdb.ActiveOrders()
+	if err != nil {
+		t.Fatalf("ActiveOrders error: %v", err)
+	}
+	if len(activeOrders) != 1 || activeOrders[0].Order.ID() != active.Order.ID() {
+		t.Fatalf("wrong active orders")
+	}
+
+	// Inactive orders can't be reactivated.
+	if err := sdb.UpdateOrderStatus(inactive.Order.ID(), order.OrderStatusBooked); err == nil {
+		t.Fatalf("no error for reactivating an inactive order")
+	}
+	if err := sdb.UpdateOrderStatus(active.Order.ID(), order.OrderStatusCanceled); err != nil {
+		t.Fatalf("UpdateOrderStatus error: %v", err)
+	}
+
+	accountOrders, err := sdb.AccountOrders(acct.Host, 0, 0)
+	if err != nil {
+		t.Fatalf("AccountOrders error: %v", err)
+	}
+	if len(accountOrders) != 2 {
+		t.Fatalf("expected 2 account orders, got %d", len(accountOrders))
+	}
+
+	m := &db.MetaMatch{
+		MetaData: &db.MatchMetaData{
+			Proof: *dbtest.RandomMatchProof(0.5),
+			DEX:   acct.Host,
+			Base:  active.Order.Base(),
+			Quote: active.Order.Quote() + 1,
+			Stamp: rand.Uint64() >> 1,
+		},
+		UserMatch: ordertest.RandomUserMatch(),
+	}
+	m.OrderID = active.Order.ID()
+	m.Status = order.MakerSwapCast
+	if err := sdb.UpdateMatch(m); err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+	activeMatches, err := sdb.ActiveMatches()
+	if err != nil {
+		t.Fatalf("ActiveMatches error: %v", err)
+	}
+	if len(activeMatches) != 1 {
+		t.Fatalf("expected 1 active match, got %d", len(activeMatches))
+	}
+	ordertest.MustCompareUserMatch(t, activeMatches[0].UserMatch, m.UserMatch)
+	dbtest.MustCompareMatchMetaData(t, activeMatches[0].MetaData, m.MetaData)
+
+	oids, err := sdb.DEXOrdersWithActiveMatches(acct.Host)
+	if err != nil {
+		t.Fatalf("DEXOrdersWithActiveMatches error: %v", err)
+	}
+	if len(oids) != 1 || oids[0] != m.OrderID {
+		t.Fatalf("wrong orders with active matches")
+	}
+
+	// The order with an active match is kept.
+	n, err := sdb.DeleteInactiveOrders(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("DeleteInactiveOrders error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 deleted order, got %d", n)
+	}
+}
+
+func TestOrderSearch(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "db.sqlite")
+	sdb, err := newDB(dbPath, tLogger)
+	if err != nil {
+		t.Fatalf("error creating dB: %v", err)
+	}
+
+	lo, _ := ordertest.RandomLimitOrder()
+	lo.Address = "DsSearchAddr_1"
+	mo := &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status: order.OrderStatusBooked,
+			Host:   "somehost.co",
+			Proof:  db.OrderProof{DEXSig: randBytes(73)},
+		},
+		Order: lo,
+	}
+	if err := sdb.UpdateOrder(mo); err != nil {
+		t.Fatalf("UpdateOrder error: %v", err)
+	}
+	m := &db.MetaMatch{
+		MetaData: &db.MatchMetaData{
+			DEX:   "somehost.co",
+			Base:  42,
+			Quote: 0,
+			Proof: db.MatchProof{TakerSwap: randBytes(36)},
+		},
+		UserMatch: ordertest.RandomUserMatch(),
+	}
+	m.OrderID = lo.ID()
+	m.Address = "CounterpartyAddr"
+	if err := sdb.UpdateMatch(m); err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+
+	checkSearch := func(text string, expFound bool) {
+		t.Helper()
+		ords, err := sdb.Orders(&db.OrderFilter{N: 10, Text: text})
+		if err != nil {
+			t.Fatalf("Orders error: %v", err)
+		}
+		if found := len(ords) == 1; found != expFound {
+			t.Fatalf("search for %q: expected found = %t, got %d orders", text, expFound, len(ords))
+		}
+	}
+	checkSearch(lo.ID().String()[:10], true)
+	checkSearch("searchaddr_", true)
+	checkSearch("searchaddr%", false) // wildcards are escaped
+	checkSearch("COUNTERPARTY", true)
+	checkSearch(hex.EncodeToString(m.MetaData.Proof.TakerSwap), true)
+	checkSearch("nothing", false)
+
+	// Annotations are indexed, and kept when the order and match are updated.
+	oid := lo.ID()
+	err = sdb.SetOrderAnnotation(oid, &db.Annotation{Note: "Placed by hand", Tags: []string{"manual"}})
+	if err != nil {
+		t.Fatalf("SetOrderAnnotation error: %v", err)
+	}
+	if err = sdb.SetMatchAnnotation(oid, m.MatchID, &db.Annotation{Tags: []string{"arb"}}); err != nil {
+		t.Fatalf("SetMatchAnnotation error: %v", err)
+	}
+	if err = sdb.UpdateOrderMetaData(oid, mo.MetaData); err != nil {
+		t.Fatalf("UpdateOrderMetaData error: %v", err)
+	}
+	if err = sdb.UpdateMatch(m); err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+	checkSearch("by hand", true)
+	checkSearch("ARB", true)
+	mo2, err := sdb.Order(oid)
+	if err != nil {
+		t.Fatalf("Order error: %v", err)
+	}
+	if a := mo2.MetaData.Annotation; a == nil || a.Note != "Placed by hand" || len(a.Tags) != 1 {
+		t.Fatalf("wrong order annotation %+v", a)
+	}
+	matches, err := sdb.MatchesForOrder(oid, false)
+	if err != nil {
+		t.Fatalf("MatchesForOrder error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].MetaData.Annotation == nil || matches[0].MetaData.Annotation.Tags[0] != "arb" {
+		t.Fatalf("wrong match annotation")
+	}
+	if err = sdb.SetMatchAnnotation(oid, m.MatchID, nil); err != nil {
+		t.Fatalf("SetMatchAnnotation error: %v", err)
+	}
+	checkSearch("arb", false)
+
+	// Downgrade the schema to version 1 and check that the upgrades index
+	// the existing orders and matches.
+	for _, stmt := range []string{
+		"ALTER TABLE orders DROP COLUMN search;",
+		"ALTER TABLE matches DROP COLUMN search;",
+		"ALTER TABLE orders DROP COLUMN annotation;",
+		"ALTER TABLE matches DROP COLUMN annotation;",
+		"PRAGMA user_version = 1;",
+	} {
+		if _, err := sdb.Exec(stmt); err != nil {
+			t.Fatalf("error downgrading: %v", err)
+		}
+	}
+	sdb.Close()
+	if sdb, err = newDB(dbPath, tLogger); err != nil {
+		t.Fatalf("error upgrading: %v", err)
+	}
+	defer sdb.Close()
+	checkSearch("searchaddr_", true)
+	checkSearch("counterparty", true)
+}
+
+func TestNotifications(t *testing.T) {
+	sdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	const n = 20
+	for i := 0; i < n; i++ {
+		note := dbtest.RandomNotification(uint64(i + 1))
+		note.Severeness = db.Success
+		if err := sdb.SaveNotification(note); err != nil {
+			t.Fatalf("SaveNotification error: %v", err)
+		}
+	}
+	notes, err := sdb.NotificationsN(n / 2)
+	if err != nil {
+		t.Fatalf("NotificationsN error: %v", err)
+	}
+	if len(notes) != n/2 {
+		t.Fatalf("expected %d notes, got %d", n/2, len(notes))
+	}
+	for i := 1; i < len(notes); i++ {
+		if notes[i].TimeStamp > notes[i-1].TimeStamp {
+			t.Fatalf("notes out of order")
+		}
+	}
+	if err := sdb.AckNotification(notes[0].ID()); err != nil {
+		t.Fatalf("AckNotification error: %v", err)
+	}
+	notes, err = sdb.SearchNotifications(&db.NoteFilter{N: 1})
+	if err != nil {
+		t.Fatalf("SearchNotifications error: %v", err)
+	}
+	if len(notes) != 1 || !notes[0].Ack {
+		t.Fatalf("newest note not acknowledged")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	src, err := bolt.NewDB(filepath.Join(dir, "dexc.db"), tLogger, bolt.Opts{})
+	if err != nil {
+		t.Fatalf("error creating bolt DB: %v", err)
+	}
+	defer src.(*bolt.BoltDB).Close()
+
+	creds := dbtest.RandomPrimaryCredentials()
+	acct := dbtest.RandomAccountInfo()
+	w := dbtest.RandomWallet()
+	mo := randomMetaOrder(acct.Host, order.OrderStatusExecuted)
+	note := dbtest.RandomNotification(1e12)
+	note.Severeness = db.Success
+	for _, err := range []error{
+		src.SetPrimaryCredentials(creds),
+		src.CreateAccount(acct),
+		src.UpdateWallet(w),
+		src.UpdateOrder(mo),
+		src.SaveNotification(note),
+		src.SetLanguage("pt-BR"),
+	} {
+		if err != nil {
+			t.Fatalf("error populating bolt DB: %v", err)
+		}
+	}
+
+	dstPath := filepath.Join(dir, "dexc.sqlite")
+	if err := Migrate(src, dstPath, tLogger); err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if err := Migrate(src, dstPath, tLogger); err == nil {
+		t.Fatalf("no error for migrating to an existing file")
+	}
+
+	dst, err := newDB(dstPath, tLogger)
+	if err != nil {
+		t.Fatalf("error opening migrated DB: %v", err)
+	}
+	defer dst.Close()
+
+	reAcct, err := dst.Account(acct.Host)
+	if err != nil {
+		t.Fatalf("Account error: %v", err)
+	}
+	dbtest.MustCompareAccountInfo(t, acct, reAcct)
+	reW, err := dst.Wallet(w.ID())
+	if err != nil {
+		t.Fatalf("Wallet error: %v", err)
+	}
+	dbtest.MustCompareWallets(t, reW, w)
+	reOrd, err := dst.Order(mo.Order.ID())
+	if err != nil {
+		t.Fatalf("Order error: %v", err)
+	}
+	ordertest.MustCompareOrders(t, reOrd.Order, mo.Order)
+	notes, err := dst.NotificationsN(10)
+	if err != nil {
+		t.Fatalf("NotificationsN error: %v", err)
+	}
+	if len(notes) != 1 {
+		t.Fatalf("expected 1 note, got %d", len(notes))
+	}
+	dbtest.MustCompareNotifications(t, notes[0], note)
+	if lang, _ := dst.Language(); lang != "pt-BR" {
+		t.Fatalf("wrong language %q", lang)
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	sdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	w := dbtest.RandomWallet()
+	w.AssetID = 42
+	if err := sdb.UpdateWallet(w); err != nil {
+		t.Fatalf("UpdateWallet error: %v", err)
+	}
+
+	// An active order for a market with no wallet for the quote asset.
+	lo, _ := ordertest.RandomLimitOrder()
+	lo.BaseAsset, lo.QuoteAsset = 42, 0
+	err := sdb.UpdateOrder(&db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status: order.OrderStatusBooked,
+			Host:   "somehost.co",
+			Proof:  db.OrderProof{DEXSig: randBytes(73)},
+		},
+		Order: lo,
+	})
+	if err != nil {
+		t.Fatalf("error inserting order: %v", err)
+	}
+
+	report, err := sdb.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity error: %v", err)
+	}
+	if report.Orders != 1 || report.Matches != 0 || report.Size == 0 {
+		t.Fatalf("wrong report %+v", report)
+	}
+	oid := lo.ID()
+	if len(report.Problems) != 1 || report.Problems[0].Kind != db.ProblemMissingWallet ||
+		report.Problems[0].ID != oid.String() {
+		t.Fatalf("expected a missing wallet problem for the order, got %+v", report.Problems)
+	}
+
+	// A match for an order that isn't stored.
+	matchID := ordertest.RandomMatchID()
+	err = sdb.UpdateMatch(&db.MetaMatch{
+		UserMatch: &order.UserMatch{
+			OrderID: ordertest.RandomOrderID(),
+			MatchID: matchID,
+			Address: "CounterpartyAddr",
+			Status:  order.MatchConfirmed,
+		},
+		MetaData: &db.MatchMetaData{
+			DEX:   "somehost.co",
+			Base:  42,
+			Quote: 0,
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+	report, err = sdb.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity error: %v", err)
+	}
+	if report.Matches != 1 {
+		t.Fatalf("expected 1 match, got %d", report.Matches)
+	}
+	var orphaned bool
+	for _, p := range report.Problems {
+		orphaned = orphaned || (p.Kind == db.ProblemOrphanedMatch && p.ID == matchID.String())
+	}
+	if !orphaned || len(report.Problems) != 2 {
+		t.Fatalf("expected an orphaned match problem, got %+v", report.Problems)
+	}
+}
@@ -0,0 +1,8 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+//go:build sqlite
+
+package sqlite
+
+import _ "github.com/mattn/go-sqlite3" // registers the "sqlite3" database/sql driver
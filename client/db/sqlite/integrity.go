@@ -0,0 +1,147 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"fmt"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+)
+
+// CheckIntegrity checks the consistency of the database. In addition to
+// SQLite's integrity_check, every order and match is decoded, matches are
+// checked for a stored order, and active orders and matches are checked for
+// wallets.
+func (db *SQLiteDB) CheckIntegrity() (*dexdb.IntegrityReport, error) {
+	report := new(dexdb.IntegrityReport)
+	var err error
+	if report.Size, report.FreeBytes, err = db.sizes(); err != nil {
+		return nil, err
+	}
+
+	msgs, err := queryStrings(db, "PRAGMA integrity_check;")
+	if err != nil {
+		return nil, fmt.Errorf("integrity_check error: %w", err)
+	}
+	for _, msg := range msgs {
+		if msg != "ok" {
+			report.AddProblem(dexdb.ProblemCorruptFile, nil, msg)
+		}
+	}
+
+	// Decode every order and match.
+	err = forEachRow(db, "SELECT oid, ord, proof FROM orders;", func(scan func(...any) error) error {
+		var oid, orderB, proofB []byte
+		if err := scan(&oid, &orderB, &proofB); err != nil {
+			return err
+		}
+		report.Orders++
+		if _, err := order.DecodeOrder(orderB); err != nil {
+			report.AddProblem(dexdb.ProblemCorruptRecord, oid, fmt.Sprintf("error decoding order: %v", err))
+		} else if _, err := dexdb.DecodeOrderProof(proofB); err != nil {
+			report.AddProblem(dexdb.ProblemCorruptRecord, oid, fmt.Sprintf("error decoding order proof: %v", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = forEachRow(db, "SELECT match_id, match, proof FROM matches;", func(scan func(...any) error) error {
+		var matchID, matchB, proofB []byte
+		if err := scan(&matchID, &matchB, &proofB); err != nil {
+			return err
+		}
+		report.Matches++
+		if _, _, err := order.DecodeMatch(matchB); err != nil {
+			report.AddProblem(dexdb.ProblemCorruptRecord, matchID, fmt.Sprintf("error decoding match: %v", err))
+		} else if _, _, err := dexdb.DecodeMatchProof(proofB); err != nil {
+			report.AddProblem(dexdb.ProblemCorruptRecord, matchID, fmt.Sprintf("error decoding match proof: %v", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = forEachRow(db, "SELECT match_id, oid FROM matches WHERE oid NOT IN (SELECT oid FROM orders);",
+		func(scan func(...any) error) error {
+			var matchID, oid []byte
+			if err := scan(&matchID, &oid); err != nil {
+				return err
+			}
+			report.AddProblem(dexdb.ProblemOrphanedMatch, matchID, fmt.Sprintf("order %x not found", oid))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := make(map[uint32]bool)
+	err = forEachRow(db, "SELECT asset_id FROM wallets;", func(scan func(...any) error) error {
+		var assetID uint32
+		if err := scan(&assetID); err != nil {
+			return err
+		}
+		wallets[assetID] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range []string{
+		"SELECT oid, base, quote FROM orders WHERE active = 1;",
+		"SELECT match_id, base, quote FROM matches WHERE active = 1;",
+	} {
+		err = forEachRow(db, stmt, func(scan func(...any) error) error {
+			var id []byte
+			var base, quote uint32
+			if err := scan(&id, &base, &quote); err != nil {
+				return err
+			}
+			for _, assetID := range []uint32{base, quote} {
+				if !wallets[assetID] {
+					report.AddProblem(dexdb.ProblemMissingWallet, id,
+						fmt.Sprintf("no wallet for %s", dex.BipIDSymbol(assetID)))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// forEachRow runs the query and calls f with the Scan function of each row.
+func forEachRow(db *SQLiteDB, query string, f func(scan func(...any) error) error) error {
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := f(rows.Scan); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// queryStrings runs the query and returns the single string column of every
+// row.
+func queryStrings(db *SQLiteDB, query string) ([]string, error) {
+	var strs []string
+	return strs, forEachRow(db, query, func(scan func(...any) error) error {
+		var s string
+		if err := scan(&s); err != nil {
+			return err
+		}
+		strs = append(strs, s)
+		return nil
+	})
+}
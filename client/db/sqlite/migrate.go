@@ -0,0 +1,237 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+)
+
+// Migrate copies the contents of the src database into a new SQLite database at
+// dstPath, which must not already exist. The new file is removed if the
+// migration fails. src is only read, with two exceptions: the pokes are re-saved
+// after loading, and the next bond key index of each known asset is
+// incremented, which just skips one index.
+func Migrate(src dexdb.DB, dstPath string, logger dex.Logger) error {
+	if _, err := os.Stat(dstPath); err == nil {
+		return fmt.Errorf("destination %s already exists", dstPath)
+	}
+	dst, err := newDB(dstPath, logger, Opts{})
+	if err != nil {
+		return err
+	}
+	if err := migrate(src, dst); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return err
+	}
+	return dst.Close()
+}
+
+func migrate(src dexdb.DB, dst *SQLiteDB) error {
+	creds, err := src.PrimaryCredentials()
+	switch {
+	case err == nil:
+		if err := dst.SetPrimaryCredentials(creds); err != nil {
+			return fmt.Errorf("error storing credentials: %w", err)
+		}
+	case !errors.Is(err, dexdb.ErrNoCredentials):
+		return fmt.Errorf("error loading credentials: %w", err)
+	}
+
+	seedGenTime, err := src.SeedGenerationTime()
+	switch {
+	case err == nil:
+		if err := dst.SetSeedGenerationTime(seedGenTime); err != nil {
+			return fmt.Errorf("error storing seed generation time: %w", err)
+		}
+	case !errors.Is(err, dexdb.ErrNoSeedGenTime):
+		return fmt.Errorf("error loading seed generation time: %w", err)
+	}
+
+	if err := migrateSettings(src, dst); err != nil {
+		return err
+	}
+
+	// Assets for which the bond key index must be carried over.
+	bondAssets := make(map[uint32]bool)
+
+	wallets, err := src.Wallets()
+	if err != nil {
+		return fmt.Errorf("error loading wallets: %w", err)
+	}
+	for _, w := range wallets {
+		if w.Balance == nil {
+			w.Balance = &dexdb.Balance{}
+		}
+		if err := dst.UpdateWallet(w); err != nil {
+			return fmt.Errorf("error storing %d wallet: %w", w.AssetID, err)
+		}
+		if w.Disabled {
+			if err := dst.UpdateWalletStatus(w.ID(), true); err != nil {
+				return fmt.Errorf("error disabling %d wallet: %w", w.AssetID, err)
+			}
+		}
+		bondAssets[w.AssetID] = true
+	}
+
+	accts, err := src.Accounts()
+	if err != nil {
+		return fmt.Errorf("error loading accounts: %w", err)
+	}
+	var nOrders, nMatches int
+	for _, acct := range accts {
+		if err := dst.CreateAccount(acct); err != nil {
+			return fmt.Errorf("error storing %s account: %w", acct.Host, err)
+		}
+		if acct.Disabled {
+			if err := dst.ToggleAccountStatus(acct.Host, true); err != nil {
+				return fmt.Errorf("error disabling %s account: %w", acct.Host, err)
+			}
+		}
+		for _, bond := range acct.Bonds {
+			bondAssets[bond.AssetID] = true
+		}
+
+		ords, err := src.AccountOrders(acct.Host, 0, 0)
+		if err != nil {
+			return fmt.Errorf("error loading %s orders: %w", acct.Host, err)
+		}
+		for _, mo := range ords {
+			n, err := migrateOrder(src, dst, mo)
+			if err != nil {
+				return err
+			}
+			nOrders++
+			nMatches += n
+		}
+	}
+
+	for assetID := range bondAssets {
+		idx, err := src.NextBondKeyIndex(assetID)
+		if err != nil {
+			return fmt.Errorf("error loading %d bond key index: %w", assetID, err)
+		}
+		if err := setBondKeyIndex(dst, assetID, idx); err != nil {
+			return fmt.Errorf("error storing %d bond key index: %w", assetID, err)
+		}
+	}
+
+	notes, err := src.SearchNotifications(&dexdb.NoteFilter{})
+	if err != nil {
+		return fmt.Errorf("error loading notifications: %w", err)
+	}
+	for _, note := range notes {
+		if err := dst.SaveNotification(note); err != nil {
+			return fmt.Errorf("error storing notification: %w", err)
+		}
+		if note.Ack {
+			if err := dst.AckNotification(note.ID()); err != nil {
+				return fmt.Errorf("error acknowledging notification: %w", err)
+			}
+		}
+	}
+
+	pokes, err := src.LoadPokes()
+	if err != nil {
+		return fmt.Errorf("error loading pokes: %w", err)
+	}
+	if len(pokes) > 0 {
+		if err := dst.SavePokes(pokes); err != nil {
+			return fmt.Errorf("error storing pokes: %w", err)
+		}
+		// LoadPokes deletes them, so put them back.
+		if err := src.SavePokes(pokes); err != nil {
+			return fmt.Errorf("error restoring pokes: %w", err)
+		}
+	}
+
+	dst.log.Infof("Migrated %d wallets, %d accounts, %d orders, %d matches and %d notifications",
+		len(wallets), len(accts), nOrders, nMatches, len(notes))
+
+	return nil
+}
+
+// migrateSettings copies the application settings.
+func migrateSettings(src dexdb.DB, dst *SQLiteDB) error {
+	lang, err := src.Language()
+	if err != nil {
+		return fmt.Errorf("error loading language: %w", err)
+	}
+	if lang != "" {
+		if err := dst.SetLanguage(lang); err != nil {
+			return fmt.Errorf("error storing language: %w", err)
+		}
+	}
+
+	rateSources, err := src.DisabledRateSources()
+	if err != nil {
+		return fmt.Errorf("error loading disabled rate sources: %w", err)
+	}
+	if err := dst.SaveDisabledRateSources(rateSources); err != nil {
+		return fmt.Errorf("error storing disabled rate sources: %w", err)
+	}
+
+	topicSettings, err := src.TopicSettings()
+	if err != nil {
+		return fmt.Errorf("error loading notification settings: %w", err)
+	}
+	if err := dst.SetTopicSettings(topicSettings); err != nil {
+		return fmt.Errorf("error storing notification settings: %w", err)
+	}
+
+	templates, err := src.NoteTemplates()
+	if err != nil {
+		return fmt.Errorf("error loading notification templates: %w", err)
+	}
+	if err := dst.SetNoteTemplates(templates); err != nil {
+		return fmt.Errorf("error storing notification templates: %w", err)
+	}
+
+	confTargets, err := src.ConfTargets()
+	if err != nil {
+		return fmt.Errorf("error loading confirmation targets: %w", err)
+	}
+	if err := dst.SetConfTargets(confTargets); err != nil {
+		return fmt.Errorf("error storing confirmation targets: %w", err)
+	}
+	return nil
+}
+
+// migrateOrder copies the order and its matches, returning the number of
+// matches. The order's update time is not available through the DB interface,
+// so the order's server time is used instead.
+func migrateOrder(src dexdb.DB, dst *SQLiteDB, mo *dexdb.MetaOrder) (int, error) {
+	oid := mo.Order.ID()
+	if err := dst.UpdateOrder(mo); err != nil {
+		return 0, fmt.Errorf("error storing order %s: %w", oid, err)
+	}
+	if _, err := dst.Exec("UPDATE orders SET update_time = ? WHERE oid = ?;", mo.Order.Time(), oid[:]); err != nil {
+		return 0, fmt.Errorf("error setting order %s time: %w", oid, err)
+	}
+	if mo.MetaData.Annotation != nil {
+		if err := dst.SetOrderAnnotation(oid, mo.MetaData.Annotation); err != nil {
+			return 0, fmt.Errorf("error storing order %s annotation: %w", oid, err)
+		}
+	}
+	matches, err := src.MatchesForOrder(oid, false)
+	if err != nil {
+		return 0, fmt.Errorf("error loading order %s matches: %w", oid, err)
+	}
+	for _, m := range matches {
+		if err := dst.UpdateMatch(m); err != nil {
+			return 0, fmt.Errorf("error storing match %s: %w", m.MatchID, err)
+		}
+		if m.MetaData.Annotation != nil {
+			if err := dst.SetMatchAnnotation(oid, m.MatchID, m.MetaData.Annotation); err != nil {
+				return 0, fmt.Errorf("error storing match %s annotation: %w", m.MatchID, err)
+			}
+		}
+	}
+	return len(matches), nil
+}
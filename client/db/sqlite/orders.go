@@ -0,0 +1,732 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/config"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/order"
+)
+
+// orderColumns are the columns decoded by scanOrder, in order.
+const orderColumns = `oid, host, status, ord, proof, change_coin, linked, swap_fees,
+	redemption_fees, funding_fees, epoch_dur, from_version, to_version, from_swap_conf,
+	to_swap_conf, max_fee_rate, redeem_max_fee_rate, options, redemption_reserves,
+	refund_reserves, accelerations, annotation`
+
+// matchColumns are the columns decoded by scanMatch, in order.
+const matchColumns = "match, proof, host, base, quote, stamp, annotation"
+
+// UpdateOrder saves the order information in the database. Any existing order
+// info for the same order ID will be overwritten without indication.
+func (db *SQLiteDB) UpdateOrder(m *dexdb.MetaOrder) error {
+	ord, md := m.Order, m.MetaData
+	if md.Status == order.OrderStatusUnknown {
+		return fmt.Errorf("cannot set order %s status to unknown", ord.ID())
+	}
+	if md.Host == "" {
+		return fmt.Errorf("empty DEX not allowed")
+	}
+	if len(md.Proof.DEXSig) == 0 {
+		return fmt.Errorf("cannot save order without DEX signature")
+	}
+	var sell bool
+	if trade := ord.Trade(); trade != nil {
+		sell = trade.Sell
+	}
+	oid := ord.ID()
+	return db.update(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO orders (oid, host, base, quote, type, sell, status, active,
+			update_time, ord, proof, epoch_dur, from_version, to_version, from_swap_conf,
+			to_swap_conf, max_fee_rate, redeem_max_fee_rate)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (oid) DO UPDATE SET host = excluded.host, base = excluded.base,
+			quote = excluded.quote, type = excluded.type, sell = excluded.sell,
+			ord = excluded.ord, epoch_dur = excluded.epoch_dur,
+			from_version = excluded.from_version, to_version = excluded.to_version,
+			from_swap_conf = excluded.from_swap_conf, to_swap_conf = excluded.to_swap_conf,
+			max_fee_rate = excluded.max_fee_rate, redeem_max_fee_rate = excluded.redeem_max_fee_rate;`,
+			oid[:], md.Host, ord.Base(), ord.Quote(), ord.Type(), sell, md.Status,
+			md.Status.IsActive(), int64(timeNow()), order.EncodeOrder(ord), md.Proof.Encode(),
+			int64(md.EpochDur), md.FromVersion, md.ToVersion, md.FromSwapConf, md.ToSwapConf,
+			int64(md.MaxFeeRate), int64(md.RedeemMaxFeeRate))
+		if err != nil {
+			return fmt.Errorf("error storing order %s: %w", oid, err)
+		}
+		return updateOrderMetaData(tx, oid, md)
+	})
+}
+
+// checkOrderStatus checks that the order exists, and that the status is not
+// unknown and does not move an inactive order back to active.
+func checkOrderStatus(q querier, oid order.OrderID, status order.OrderStatus) error {
+	if status == order.OrderStatusUnknown {
+		return fmt.Errorf("cannot set order %s status to unknown", oid)
+	}
+	var active bool
+	err := q.QueryRow("SELECT active FROM orders WHERE oid = ?;", oid[:]).Scan(&active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("order %s not found", oid)
+	}
+	if err != nil {
+		return err
+	}
+	if !active && status.IsActive() {
+		return fmt.Errorf("active order %s not found", oid)
+	}
+	return nil
+}
+
+// updateOrderMetaData updates the order's metadata, not including the Host.
+func updateOrderMetaData(q querier, oid order.OrderID, md *dexdb.OrderMetaData) error {
+	var linkedB []byte
+	if !md.LinkedOrder.IsZero() {
+		linkedB = md.LinkedOrder[:]
+	}
+
+	var accelerationsB encode.BuildyBytes
+	if len(md.AccelerationCoins) > 0 {
+		accelerationsB = encode.BuildyBytes{0}
+		for _, acceleration := range md.AccelerationCoins {
+			accelerationsB = accelerationsB.AddData(acceleration)
+		}
+	}
+
+	// The search text includes some of the metadata and the stored
+	// annotation.
+	var orderB, annotationB []byte
+	err := q.QueryRow("SELECT ord, annotation FROM orders WHERE oid = ?;", oid[:]).Scan(&orderB, &annotationB)
+	if err != nil {
+		return err
+	}
+	ord, err := order.DecodeOrder(orderB)
+	if err != nil {
+		return fmt.Errorf("error decoding order %s: %w", oid, err)
+	}
+	searchMD := *md
+	if searchMD.Annotation, err = dexdb.DecodeAnnotation(annotationB); err != nil {
+		return fmt.Errorf("error decoding order %s annotation: %w", oid, err)
+	}
+
+	_, err = q.Exec(`UPDATE orders SET status = ?, active = ?, update_time = ?, proof = ?,
+		change_coin = ?, linked = ?, swap_fees = ?, redemption_fees = ?, options = ?,
+		redemption_reserves = ?, refund_reserves = ?, accelerations = ?, funding_fees = ?,
+		search = ? WHERE oid = ?;`, md.Status, md.Status.IsActive(), int64(timeNow()),
+		md.Proof.Encode(), []byte(md.ChangeCoin), linkedB, int64(md.SwapFeesPaid),
+		int64(md.RedemptionFeesPaid), config.Data(md.Options), int64(md.RedemptionReserves),
+		int64(md.RefundReserves), []byte(accelerationsB), int64(md.FundingFeesPaid),
+		dexdb.OrderSearchText(ord, &searchMD), oid[:])
+	return err
+}
+
+// UpdateOrderMetaData updates the order metadata, not including the Host.
+func (db *SQLiteDB) UpdateOrderMetaData(oid order.OrderID, md *dexdb.OrderMetaData) error {
+	return db.update(func(tx *sql.Tx) error {
+		if err := checkOrderStatus(tx, oid, md.Status); err != nil {
+			return fmt.Errorf("UpdateOrderMetaData: %w", err)
+		}
+		return updateOrderMetaData(tx, oid, md)
+	})
+}
+
+// UpdateOrderStatus sets the order status for an order.
+func (db *SQLiteDB) UpdateOrderStatus(oid order.OrderID, status order.OrderStatus) error {
+	return db.update(func(tx *sql.Tx) error {
+		if err := checkOrderStatus(tx, oid, status); err != nil {
+			return fmt.Errorf("UpdateOrderStatus: %w", err)
+		}
+		_, err := tx.Exec("UPDATE orders SET status = ?, active = ? WHERE oid = ?;",
+			status, status.IsActive(), oid[:])
+		return err
+	})
+}
+
+// LinkOrder sets the linked order.
+func (db *SQLiteDB) LinkOrder(oid, linkedID order.OrderID) error {
+	linkedB := linkedID[:]
+	if linkedID.IsZero() {
+		linkedB = nil
+	}
+	res, err := db.Exec("UPDATE orders SET linked = ? WHERE oid = ?;", linkedB, oid[:])
+	return requireRow(res, err, fmt.Errorf("LinkOrder - order %s not found", oid))
+}
+
+// encodeAnnotation encodes the annotation for the annotation columns. An empty
+// annotation is stored as NULL.
+func encodeAnnotation(a *dexdb.Annotation) []byte {
+	if a.IsEmpty() {
+		return nil
+	}
+	return a.Encode()
+}
+
+// SetOrderAnnotation sets the user's note and tags for the order. A nil or
+// empty annotation removes any existing annotation.
+func (db *SQLiteDB) SetOrderAnnotation(oid order.OrderID, a *dexdb.Annotation) error {
+	return db.update(func(tx *sql.Tx) error {
+		res, err := tx.Exec("UPDATE orders SET annotation = ? WHERE oid = ?;", encodeAnnotation(a), oid[:])
+		if err := requireRow(res, err, fmt.Errorf("SetOrderAnnotation - order %s not found", oid)); err != nil {
+			return err
+		}
+		mord, err := scanOrder(tx.QueryRow("SELECT "+orderColumns+" FROM orders WHERE oid = ?;", oid[:]))
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE orders SET search = ? WHERE oid = ?;",
+			dexdb.OrderSearchText(mord.Order, mord.MetaData), oid[:])
+		return err
+	})
+}
+
+// scanOrder decodes a row of orderColumns into a *MetaOrder.
+func scanOrder(row scanner) (*dexdb.MetaOrder, error) {
+	var oidB, orderB, proofB, changeB, linkedB, optionsB, accelerationsB, annotationB []byte
+	var host string
+	var status uint16
+	var fromVersion, toVersion, fromSwapConf, toSwapConf uint32
+	var swapFees, redemptionFees, fundingFees, epochDur, maxFeeRate, redeemMaxFeeRate,
+		redemptionReserves, refundReserves int64
+	err := row.Scan(&oidB, &host, &status, &orderB, &proofB, &changeB, &linkedB, &swapFees,
+		&redemptionFees, &fundingFees, &epochDur, &fromVersion, &toVersion, &fromSwapConf,
+		&toSwapConf, &maxFeeRate, &redeemMaxFeeRate, &optionsB, &redemptionReserves,
+		&refundReserves, &accelerationsB, &annotationB)
+	if err != nil {
+		return nil, err
+	}
+	ord, err := order.DecodeOrder(orderB)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding order %x: %w", oidB, err)
+	}
+	proof, err := dexdb.DecodeOrderProof(proofB)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding order proof for %x: %w", oidB, err)
+	}
+	options, err := config.Parse(optionsB)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode order options")
+	}
+
+	annotation, err := dexdb.DecodeAnnotation(annotationB)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode order annotation: %w", err)
+	}
+
+	var linkedID order.OrderID
+	copy(linkedID[:], linkedB)
+
+	var accelerationCoinIDs []order.CoinID
+	if len(accelerationsB) > 0 {
+		_, coinIDs, err := encode.DecodeBlob(accelerationsB)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode accelerations")
+		}
+		for _, coinID := range coinIDs {
+			accelerationCoinIDs = append(accelerationCoinIDs, order.CoinID(coinID))
+		}
+	}
+
+	if len(changeB) == 0 {
+		changeB = nil
+	}
+
+	return &dexdb.MetaOrder{
+		MetaData: &dexdb.OrderMetaData{
+			Proof:              *proof,
+			Status:             order.OrderStatus(status),
+			Host:               host,
+			ChangeCoin:         changeB,
+			LinkedOrder:        linkedID,
+			SwapFeesPaid:       uint64(swapFees),
+			EpochDur:           uint64(epochDur),
+			MaxFeeRate:         uint64(maxFeeRate),
+			RedeemMaxFeeRate:   uint64(redeemMaxFeeRate),
+			RedemptionFeesPaid: uint64(redemptionFees),
+			FromSwapConf:       fromSwapConf,
+			ToSwapConf:         toSwapConf,
+			FromVersion:        fromVersion,
+			ToVersion:          toVersion,
+			Options:            options,
+			RedemptionReserves: uint64(redemptionReserves),
+			RefundReserves:     uint64(refundReserves),
+			AccelerationCoins:  accelerationCoinIDs,
+			FundingFeesPaid:    uint64(fundingFees),
+			Annotation:         annotation,
+		},
+		Order: ord,
+	}, nil
+}
+
+// queryOrders decodes the orders returned by the query. The where clause and
+// the trailing clauses (ordering, limit) are optional.
+func (db *SQLiteDB) queryOrders(where, trailing string, args ...any) ([]*dexdb.MetaOrder, error) {
+	query := "SELECT " + orderColumns + " FROM orders"
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := db.Query(query+" "+trailing+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var orders []*dexdb.MetaOrder
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+// newestFirst sorts orders newest first, breaking ties by descending order ID
+// in the same way as the bolt backend.
+const newestFirst = "ORDER BY update_time DESC, oid DESC"
+
+// ActiveOrders retrieves all orders which appear to be in an active state,
+// which is either in the epoch queue or in the order book.
+func (db *SQLiteDB) ActiveOrders() ([]*dexdb.MetaOrder, error) {
+	return db.queryOrders("active = 1", "")
+}
+
+// AccountOrders retrieves all orders associated with the specified DEX. n = 0
+// applies no limit on number of orders returned. since = 0 is equivalent to
+// disabling the time filter, since no orders were created before 1970.
+func (db *SQLiteDB) AccountOrders(dex string, n int, since uint64) ([]*dexdb.MetaOrder, error) {
+	return db.queryOrders("host = ? AND update_time >= ?", newestFirst+" LIMIT ?",
+		dex, int64(since), sqlLimit(n))
+}
+
+// MarketOrders retrieves all orders for the specified DEX and market. n = 0
+// applies no limit on number of orders returned. since = 0 is equivalent to
+// disabling the time filter, since no orders were created before 1970.
+func (db *SQLiteDB) MarketOrders(dex string, base, quote uint32, n int, since uint64) ([]*dexdb.MetaOrder, error) {
+	return db.queryOrders("host = ? AND base = ? AND quote = ? AND update_time >= ?",
+		newestFirst+" LIMIT ?", dex, base, quote, int64(since), sqlLimit(n))
+}
+
+// ActiveDEXOrders retrieves all orders for the specified DEX.
+func (db *SQLiteDB) ActiveDEXOrders(dex string) ([]*dexdb.MetaOrder, error) {
+	return db.queryOrders("host = ? AND active = 1", "", dex)
+}
+
+// Order fetches a MetaOrder by order ID.
+func (db *SQLiteDB) Order(oid order.OrderID) (*dexdb.MetaOrder, error) {
+	ords, err := db.queryOrders("oid = ?", "", oid[:])
+	if err != nil {
+		return nil, err
+	}
+	if len(ords) == 0 {
+		return nil, fmt.Errorf("order %s not found", oid)
+	}
+	return ords[0], nil
+}
+
+// Orders fetches a slice of orders, sorted by descending time, and filtered
+// with the provided OrderFilter. Orders does not return cancel orders.
+func (db *SQLiteDB) Orders(orderFilter *dexdb.OrderFilter) ([]*dexdb.MetaOrder, error) {
+	// Default filter is just to exclude cancel orders.
+	where := []string{"type != ?"}
+	args := []any{order.CancelOrderType}
+
+	if len(orderFilter.Hosts) > 0 {
+		where = append(where, "host IN ("+placeholders(len(orderFilter.Hosts))+")")
+		for _, host := range orderFilter.Hosts {
+			args = append(args, host)
+		}
+	}
+
+	if n := len(orderFilter.Assets); n > 0 {
+		where = append(where, "(base IN ("+placeholders(n)+") OR quote IN ("+placeholders(n)+"))")
+		for i := 0; i < 2; i++ {
+			for _, assetID := range orderFilter.Assets {
+				args = append(args, assetID)
+			}
+		}
+	}
+
+	if len(orderFilter.Statuses) > 0 {
+		where = append(where, "status IN ("+placeholders(len(orderFilter.Statuses))+")")
+		for _, status := range orderFilter.Statuses {
+			args = append(args, status)
+		}
+	}
+
+	// An order is completed if it has at least one inactive, non-cancel match
+	// with a non-zero quantity.
+	if orderFilter.CompletedOnly {
+		where = append(where, "oid IN (SELECT oid FROM matches WHERE active = 0 AND cancel = 0 AND qty > 0)")
+	}
+
+	if orderFilter.FresherThanUnixMs > 0 {
+		where = append(where, "update_time >= ?")
+		args = append(args, int64(orderFilter.FresherThanUnixMs))
+	}
+
+	if orderFilter.Market != nil {
+		where = append(where, "base = ? AND quote = ?")
+		args = append(args, orderFilter.Market.Base, orderFilter.Market.Quote)
+	}
+
+	if text := dexdb.SearchTerm(orderFilter.Text); text != "" {
+		where = append(where, `(search LIKE ? ESCAPE '\' OR
+			oid IN (SELECT oid FROM matches WHERE search LIKE ? ESCAPE '\'))`)
+		pattern := "%" + likeEscaper.Replace(text) + "%"
+		args = append(args, pattern, pattern)
+	}
+
+	if !orderFilter.Offset.IsZero() {
+		offsetOID := orderFilter.Offset
+		var stamp int64
+		err := db.QueryRow("SELECT update_time FROM orders WHERE oid = ?;", offsetOID[:]).Scan(&stamp)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("order %s not found", offsetOID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, "(update_time < ? OR (update_time = ? AND oid > ?))")
+		args = append(args, stamp, stamp, offsetOID[:])
+	}
+
+	args = append(args, sqlLimit(orderFilter.N))
+	return db.queryOrders(strings.Join(where, " AND "), newestFirst+" LIMIT ?", args...)
+}
+
+// likeEscaper escapes the LIKE wildcards of search text.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// UpdateMatch updates the match information in the database. Any existing
+// entry for the same match ID will be overwritten without indication.
+func (db *SQLiteDB) UpdateMatch(m *dexdb.MetaMatch) error {
+	match, md := m.UserMatch, m.MetaData
+	if md.Quote == md.Base {
+		return fmt.Errorf("quote and base asset cannot be the same")
+	}
+	if md.DEX == "" {
+		return fmt.Errorf("empty DEX not allowed")
+	}
+	active := dexdb.MatchIsActive(m.UserMatch, &m.MetaData.Proof)
+	// A cancel match for a maker (trade) order has an empty address.
+	cancel := match.Address == ""
+	id := m.MatchOrderUniqueID()
+	return db.update(func(tx *sql.Tx) error {
+		// The search text includes the stored annotation.
+		var annotationB []byte
+		err := tx.QueryRow("SELECT annotation FROM matches WHERE id = ?;", id).Scan(&annotationB)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		searchMD := *md
+		if searchMD.Annotation, err = dexdb.DecodeAnnotation(annotationB); err != nil {
+			return fmt.Errorf("error decoding match %s annotation: %w", match.MatchID, err)
+		}
+		_, err = tx.Exec(`INSERT INTO matches (id, oid, match_id, host, base, quote, status,
+			active, cancel, qty, rate, update_time, stamp, match, proof, search)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET oid = excluded.oid, match_id = excluded.match_id,
+			host = excluded.host, base = excluded.base, quote = excluded.quote,
+			status = excluded.status, active = excluded.active, cancel = excluded.cancel,
+			qty = excluded.qty, rate = excluded.rate, update_time = excluded.update_time,
+			stamp = excluded.stamp, match = excluded.match, proof = excluded.proof,
+			search = excluded.search;`,
+			id, match.OrderID[:], match.MatchID[:], md.DEX, md.Base, md.Quote, match.Status,
+			active, cancel, int64(match.Quantity), int64(match.Rate), int64(timeNow()),
+			int64(md.Stamp), order.EncodeMatch(match), md.Proof.Encode(),
+			dexdb.MatchSearchText(&dexdb.MetaMatch{UserMatch: match, MetaData: &searchMD}))
+		return err
+	})
+}
+
+// SetMatchAnnotation sets the user's note and tags for the order's match. A
+// nil or empty annotation removes any existing annotation.
+func (db *SQLiteDB) SetMatchAnnotation(oid order.OrderID, mid order.MatchID, a *dexdb.Annotation) error {
+	id := dexdb.MatchOrderUniqueID(mid, oid)
+	return db.update(func(tx *sql.Tx) error {
+		res, err := tx.Exec("UPDATE matches SET annotation = ? WHERE id = ?;", encodeAnnotation(a), id)
+		notFound := fmt.Errorf("SetMatchAnnotation - match %s for order %s not found", mid, oid)
+		if err := requireRow(res, err, notFound); err != nil {
+			return err
+		}
+		m, err := scanMatch(tx.QueryRow("SELECT "+matchColumns+" FROM matches WHERE id = ?;", id), false)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE matches SET search = ? WHERE id = ?;", dexdb.MatchSearchText(m), id)
+		return err
+	})
+}
+
+// scanMatch decodes a row of matchColumns into a *MetaMatch. If excludeCancels
+// is true and the match is a cancel order match, a nil match is returned
+// without an error.
+func scanMatch(row scanner, excludeCancels bool) (*dexdb.MetaMatch, error) {
+	var matchB, proofB, annotationB []byte
+	var host string
+	var base, quote uint32
+	var stamp int64
+	if err := row.Scan(&matchB, &proofB, &host, &base, &quote, &stamp, &annotationB); err != nil {
+		return nil, err
+	}
+	match, matchVer, err := order.DecodeMatch(matchB)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding match: %w", err)
+	}
+	if matchVer == 0 && match.Status == order.MatchComplete {
+		// When v0 matches were written, there was no MatchConfirmed, so we will
+		// "upgrade" this match on the fly to agree with MatchIsActive.
+		match.Status = order.MatchConfirmed
+	}
+	// A cancel match for a maker (trade) order has an empty address.
+	if excludeCancels && match.Address == "" {
+		return nil, nil
+	}
+	if len(proofB) == 0 {
+		return nil, fmt.Errorf("empty proof")
+	}
+	proof, _, err := dexdb.DecodeMatchProof(proofB)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding proof: %w", err)
+	}
+	// A cancel match for a taker (the cancel) order is complete with no
+	// InitSig. Unfortunately, the trade Address was historically set.
+	if excludeCancels && (len(proof.Auth.InitSig) == 0 && match.Status == order.MatchComplete) {
+		return nil, nil
+	}
+	annotation, err := dexdb.DecodeAnnotation(annotationB)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding match annotation: %w", err)
+	}
+	return &dexdb.MetaMatch{
+		MetaData: &dexdb.MatchMetaData{
+			Proof:      *proof,
+			DEX:        host,
+			Base:       base,
+			Quote:      quote,
+			Stamp:      uint64(stamp),
+			Annotation: annotation,
+		},
+		UserMatch: match,
+	}, nil
+}
+
+// queryMatches decodes the matches that satisfy the where clause.
+func (db *SQLiteDB) queryMatches(where string, excludeCancels bool, args ...any) ([]*dexdb.MetaMatch, error) {
+	rows, err := db.Query("SELECT "+matchColumns+" FROM matches WHERE "+where+" ORDER BY id;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var matches []*dexdb.MetaMatch
+	for rows.Next() {
+		m, err := scanMatch(rows, excludeCancels)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			matches = append(matches, m)
+		}
+	}
+	return matches, rows.Err()
+}
+
+// ActiveMatches retrieves the matches that are in an active state, which is
+// any match that is still active.
+func (db *SQLiteDB) ActiveMatches() ([]*dexdb.MetaMatch, error) {
+	// Don't bother with cancel matches that are never active.
+	return db.queryMatches("active = 1", true)
+}
+
+// DEXOrdersWithActiveMatches retrieves order IDs for any order that has active
+// matches, regardless of whether the order itself is in an active state.
+func (db *SQLiteDB) DEXOrdersWithActiveMatches(dex string) ([]order.OrderID, error) {
+	rows, err := db.Query("SELECT DISTINCT oid FROM matches WHERE active = 1 AND host = ?;", dex)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []order.OrderID
+	for rows.Next() {
+		var oidB []byte
+		if err := rows.Scan(&oidB); err != nil {
+			return nil, err
+		}
+		var oid order.OrderID
+		copy(oid[:], oidB)
+		ids = append(ids, oid)
+	}
+	return ids, rows.Err()
+}
+
+// MatchesForOrder retrieves the matches for the specified order ID.
+func (db *SQLiteDB) MatchesForOrder(oid order.OrderID, excludeCancels bool) ([]*dexdb.MetaMatch, error) {
+	return db.queryMatches("oid = ?", excludeCancels, oid[:])
+}
+
+// deletionKeys returns the keys from the query, which should select a single
+// BLOB column.
+func (db *SQLiteDB) deletionKeys(query string, args ...any) ([][]byte, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys [][]byte
+	for rows.Next() {
+		var k []byte
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// olderThanMs is the deletion cutoff in unix milliseconds. A nil or zero time
+// is treated as now.
+func olderThanMs(olderThan *time.Time) int64 {
+	if olderThan != nil && !olderThan.IsZero() {
+		return olderThan.UnixMilli()
+	}
+	return int64(timeNow())
+}
+
+// DeleteInactiveOrders deletes orders that are no longer needed for normal
+// operations. Optionally accepts a time to delete orders with a later time
+// stamp. Accepts an optional function to perform on deleted orders.
+func (db *SQLiteDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time,
+	perOrderFn func(ords *dexdb.MetaOrder) error) (int, error) {
+	const batchSize = 1000
+
+	// Some inactive orders may still be needed for active matches.
+	keys, err := db.deletionKeys(`SELECT oid FROM orders WHERE active = 0 AND update_time <= ?
+		AND oid NOT IN (SELECT oid FROM matches WHERE active = 1) ORDER BY oid;`, olderThanMs(olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("unable to get inactive order keys: %v", err)
+	}
+
+	nDeletedOrders := 0
+	start := time.Now()
+	// Delete orders in batches to prevent any single db transaction from
+	// becoming too large.
+	for i := 0; i < len(keys); i += batchSize {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		end := min(i+batchSize, len(keys))
+		nDeletedBatch := 0
+		err := db.update(func(tx *sql.Tx) error {
+			for _, key := range keys[i:end] {
+				o, err := scanOrder(tx.QueryRow("SELECT "+orderColumns+" FROM orders WHERE oid = ?;", key))
+				if errors.Is(err, sql.ErrNoRows) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to decode order: %v", err)
+				}
+				if _, err := tx.Exec("DELETE FROM orders WHERE oid = ?;", key); err != nil {
+					return fmt.Errorf("failed to delete order: %v", err)
+				}
+				if perOrderFn != nil {
+					if err := perOrderFn(o); err != nil {
+						return fmt.Errorf("problem performing batch function: %v", err)
+					}
+				}
+				nDeletedBatch++
+			}
+			nDeletedOrders += nDeletedBatch
+			return nil
+		})
+		if err != nil {
+			if perOrderFn != nil && nDeletedBatch != 0 {
+				db.log.Warnf("%d orders reported as deleted have been rolled back due to error.", nDeletedBatch)
+			}
+			return 0, fmt.Errorf("unable to delete orders: %v", err)
+		}
+	}
+
+	db.log.Infof("Deleted %d archived orders from the database in %v",
+		nDeletedOrders, time.Since(start))
+
+	return nDeletedOrders, nil
+}
+
+// DeleteInactiveMatches deletes matches that are no longer needed for normal
+// operations. Optionally accepts a time to delete matches with a later time
+// stamp. Accepts an optional function to perform on deleted matches.
+func (db *SQLiteDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Time,
+	perMatchFn func(mtch *dexdb.MetaMatch, isSell bool) error) (int, error) {
+	const batchSize = 1000
+
+	// Some inactive matches still have active orders. Keep those just in case
+	// they are needed again.
+	keys, err := db.deletionKeys(`SELECT id FROM matches WHERE active = 0 AND stamp <= ?
+		AND oid NOT IN (SELECT oid FROM orders WHERE active = 1) ORDER BY id;`, olderThanMs(olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("unable to get inactive match keys: %v", err)
+	}
+
+	nDeletedMatches := 0
+	start := time.Now()
+	// Delete matches in batches to prevent any single db transaction from
+	// becoming too large.
+	for i := 0; i < len(keys); i += batchSize {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		end := min(i+batchSize, len(keys))
+		nDeletedBatch := 0
+		err := db.update(func(tx *sql.Tx) error {
+			for _, key := range keys[i:end] {
+				m, err := scanMatch(tx.QueryRow("SELECT "+matchColumns+" FROM matches WHERE id = ?;", key), false)
+				if errors.Is(err, sql.ErrNoRows) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to load match: %v", err)
+				}
+				if _, err := tx.Exec("DELETE FROM matches WHERE id = ?;", key); err != nil {
+					return fmt.Errorf("failed to delete match: %v", err)
+				}
+				if perMatchFn != nil {
+					var isSell bool
+					err := tx.QueryRow("SELECT sell FROM orders WHERE oid = ?;", m.OrderID[:]).Scan(&isSell)
+					if errors.Is(err, sql.ErrNoRows) {
+						return fmt.Errorf("problem getting order side for order %v: order not found", m.OrderID)
+					}
+					if err != nil {
+						return fmt.Errorf("problem getting order side for order %v: %v", m.OrderID, err)
+					}
+					if err := perMatchFn(m, isSell); err != nil {
+						return fmt.Errorf("problem performing batch function: %v", err)
+					}
+				}
+				nDeletedBatch++
+			}
+			nDeletedMatches += nDeletedBatch
+			return nil
+		})
+		if err != nil {
+			if perMatchFn != nil && nDeletedBatch != 0 {
+				db.log.Warnf("%d matches reported as deleted have been rolled back due to error.", nDeletedBatch)
+			}
+			return 0, fmt.Errorf("unable to delete matches: %v", err)
+		}
+	}
+
+	db.log.Infof("Deleted %d archived matches from the database in %v",
+		nDeletedMatches, time.Since(start))
+
+	return nDeletedMatches, nil
+}
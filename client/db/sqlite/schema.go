@@ -0,0 +1,143 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+// DBVersion is the latest version of the database schema, stored as the SQLite
+// user_version.
+const DBVersion = len(upgrades) + 1
+
+// Unsigned 64-bit values are stored in the signed INTEGER columns as their
+// two's complement bit pattern, so values above math.MaxInt64 are stored as
+// negative numbers. Byte slices that bolt stores with a fixed encoding (orders,
+// matches, proofs, wallets, etc.) are stored with the same encoding, and the
+// fields that are used for filtering and sorting have their own columns.
+const createTablesStmt = `
+CREATE TABLE IF NOT EXISTS meta (
+	key TEXT PRIMARY KEY,
+	value BLOB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS credentials (
+	id INTEGER PRIMARY KEY CHECK (id = 0),
+	enc_seed BLOB NOT NULL,
+	enc_inner_key BLOB NOT NULL,
+	inner_key_params BLOB NOT NULL,
+	outer_key_params BLOB NOT NULL,
+	birthday INTEGER NOT NULL,
+	version INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS accounts (
+	host TEXT PRIMARY KEY,
+	info BLOB NOT NULL,
+	active INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS bonds (
+	host TEXT NOT NULL REFERENCES accounts (host) ON DELETE CASCADE,
+	uid BLOB NOT NULL,
+	asset_id INTEGER NOT NULL,
+	bond BLOB NOT NULL,
+	confirmed INTEGER NOT NULL,
+	refunded INTEGER NOT NULL,
+	lock_time INTEGER NOT NULL,
+	PRIMARY KEY (host, uid)
+);
+
+CREATE TABLE IF NOT EXISTS bond_indexes (
+	asset_id INTEGER PRIMARY KEY,
+	next_index INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+	oid BLOB PRIMARY KEY,
+	host TEXT NOT NULL,
+	base INTEGER NOT NULL,
+	quote INTEGER NOT NULL,
+	type INTEGER NOT NULL,
+	sell INTEGER NOT NULL,
+	status INTEGER NOT NULL,
+	active INTEGER NOT NULL,
+	update_time INTEGER NOT NULL,
+	ord BLOB NOT NULL,
+	proof BLOB NOT NULL,
+	change_coin BLOB,
+	linked BLOB,
+	swap_fees INTEGER NOT NULL DEFAULT 0,
+	redemption_fees INTEGER NOT NULL DEFAULT 0,
+	funding_fees INTEGER NOT NULL DEFAULT 0,
+	epoch_dur INTEGER NOT NULL DEFAULT 0,
+	from_version INTEGER NOT NULL DEFAULT 0,
+	to_version INTEGER NOT NULL DEFAULT 0,
+	from_swap_conf INTEGER NOT NULL DEFAULT 0,
+	to_swap_conf INTEGER NOT NULL DEFAULT 0,
+	max_fee_rate INTEGER NOT NULL DEFAULT 0,
+	redeem_max_fee_rate INTEGER NOT NULL DEFAULT 0,
+	options BLOB,
+	redemption_reserves INTEGER NOT NULL DEFAULT 0,
+	refund_reserves INTEGER NOT NULL DEFAULT 0,
+	accelerations BLOB,
+	search TEXT NOT NULL DEFAULT '',
+	annotation BLOB
+);
+CREATE INDEX IF NOT EXISTS orders_active_idx ON orders (active);
+CREATE INDEX IF NOT EXISTS orders_update_time_idx ON orders (update_time);
+CREATE INDEX IF NOT EXISTS orders_market_idx ON orders (host, base, quote);
+
+-- cancel is true for matches of cancel orders, which have no Address.
+CREATE TABLE IF NOT EXISTS matches (
+	id BLOB PRIMARY KEY,
+	oid BLOB NOT NULL,
+	match_id BLOB NOT NULL,
+	host TEXT NOT NULL,
+	base INTEGER NOT NULL,
+	quote INTEGER NOT NULL,
+	status INTEGER NOT NULL,
+	active INTEGER NOT NULL,
+	cancel INTEGER NOT NULL,
+	qty INTEGER NOT NULL,
+	rate INTEGER NOT NULL,
+	update_time INTEGER NOT NULL,
+	stamp INTEGER NOT NULL,
+	match BLOB NOT NULL,
+	proof BLOB NOT NULL,
+	search TEXT NOT NULL DEFAULT '',
+	annotation BLOB
+);
+CREATE INDEX IF NOT EXISTS matches_oid_idx ON matches (oid);
+CREATE INDEX IF NOT EXISTS matches_active_idx ON matches (active, host);
+
+CREATE TABLE IF NOT EXISTS wallets (
+	id BLOB PRIMARY KEY,
+	asset_id INTEGER NOT NULL,
+	wallet BLOB NOT NULL,
+	balance BLOB NOT NULL,
+	disabled INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS notes (
+	id BLOB PRIMARY KEY,
+	type TEXT NOT NULL,
+	topic TEXT NOT NULL,
+	subject TEXT NOT NULL,
+	details TEXT NOT NULL,
+	severity INTEGER NOT NULL,
+	stamp INTEGER NOT NULL,
+	ack INTEGER NOT NULL DEFAULT 0,
+	note BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS notes_stamp_idx ON notes (stamp);
+CREATE INDEX IF NOT EXISTS notes_topic_idx ON notes (topic);
+`
+
+// Keys of the meta table.
+const (
+	seedGenTimeKey        = "seedGenTime"
+	disabledRateSourceKey = "disabledRateSources"
+	langKey               = "lang"
+	topicSettingsKey      = "topicSettings"
+	noteTemplatesKey      = "noteTemplates"
+	confTargetsKey        = "confTargets"
+	pokesKey              = "pokes"
+)
@@ -0,0 +1,117 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	dexdb "decred.org/dcrdex/client/db"
+)
+
+// upgrades are the database schema upgrades. upgrades[i] upgrades version i+1
+// to version i+2. Version 1 is the first version, so it has no upgrade.
+var upgrades = [...]func(tx *sql.Tx) error{
+	// v1 => v2 adds the search text of orders and matches.
+	v2Upgrade,
+	// v2 => v3 adds the annotations of orders and matches.
+	v3Upgrade,
+}
+
+// upgradeDB upgrades the database from version ver to DBVersion, with one
+// transaction per version.
+func (db *SQLiteDB) upgradeDB(ver int) error {
+	for ; ver < DBVersion; ver++ {
+		db.log.Infof("Upgrading database to version %d...", ver+1)
+		upgrade := upgrades[ver-1]
+		err := db.update(func(tx *sql.Tx) error {
+			if err := upgrade(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d;", ver+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error upgrading database to version %d: %w", ver+1, err)
+		}
+	}
+	return nil
+}
+
+// v2Upgrade adds the search columns and stores the search text of every order
+// and match.
+func v2Upgrade(tx *sql.Tx) error {
+	for _, table := range []string{"orders", "matches"} {
+		if _, err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN search TEXT NOT NULL DEFAULT '';"); err != nil {
+			return fmt.Errorf("error adding %s search column: %w", table, err)
+		}
+	}
+
+	// The annotation columns are added by v3Upgrade.
+	v2OrderColumns := strings.Replace(orderColumns, "annotation", "NULL", 1)
+	v2MatchColumns := strings.Replace(matchColumns, "annotation", "NULL", 1)
+
+	// Read everything before writing, since the transaction's connection
+	// can't be used while the rows are open.
+	search := make(map[string]string)
+	rows, err := tx.Query("SELECT " + v2OrderColumns + " FROM orders;")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		mord, err := scanOrder(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		oid := mord.Order.ID()
+		search[string(oid[:])] = dexdb.OrderSearchText(mord.Order, mord.MetaData)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for oidB, text := range search {
+		if _, err := tx.Exec("UPDATE orders SET search = ? WHERE oid = ?;", text, []byte(oidB)); err != nil {
+			return err
+		}
+	}
+
+	search = make(map[string]string)
+	rows, err = tx.Query("SELECT " + v2MatchColumns + " FROM matches;")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		m, err := scanMatch(rows, false)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		// The id column is the MatchOrderUniqueID.
+		search[string(m.MatchOrderUniqueID())] = dexdb.MatchSearchText(m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for id, text := range search {
+		if _, err := tx.Exec("UPDATE matches SET search = ? WHERE id = ?;", text, []byte(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// v3Upgrade adds the annotation columns. No existing order or match has an
+// annotation, so the search text is unchanged.
+func v3Upgrade(tx *sql.Tx) error {
+	for _, table := range []string{"orders", "matches"} {
+		if _, err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN annotation BLOB;"); err != nil {
+			return fmt.Errorf("error adding %s annotation column: %w", table, err)
+		}
+	}
+	return nil
+}
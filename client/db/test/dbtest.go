@@ -6,6 +6,7 @@ package dbtest
 import (
 	"bytes"
 	"math/rand"
+	"reflect"
 	"strings"
 	"time"
 
@@ -50,6 +51,7 @@ func RandomAccountInfo() *db.AccountInfo {
 		LegacyFeeAssetID: uint32(rand.Intn(64)),
 		LegacyFeeCoin:    randBytes(32),
 		Cert:             randBytes(100),
+		AltHosts:         []string{ordertest.RandomAddress(), ordertest.RandomAddress()},
 	}
 }
 
@@ -161,7 +163,7 @@ func RandomMatchProof(sparsity float64) *db.MatchProof {
 }
 
 func RandomNotification(maxTime uint64) *db.Notification {
-	return &db.Notification{
+	note := &db.Notification{
 		NoteType:    ordertest.RandomAddress(),
 		SubjectText: ordertest.RandomAddress(),
 		DetailText:  ordertest.RandomAddress(),
@@ -169,6 +171,17 @@ func RandomNotification(maxTime uint64) *db.Notification {
 		Severeness: db.Severity(rand.Intn(3)) + db.Success,
 		TimeStamp:  uint64(rand.Int63n(int64(maxTime))),
 	}
+	// Half of the notifications have structured data.
+	if rand.Intn(2) == 0 {
+		assetID := rand.Uint32()
+		note.NoteData = &db.NoteData{
+			Host:    ordertest.RandomAddress(),
+			AssetID: &assetID,
+			OrderID: randBytes(32),
+			Amount:  rand.Uint64(),
+		}
+	}
+	return note
 }
 
 type testKiller interface {
@@ -256,6 +269,9 @@ func MustCompareMatchProof(t testKiller, m1, m2 *db.MatchProof) {
 	if !bytes.Equal(m1.TakerRedeem, m2.TakerRedeem) {
 		t.Fatalf("TakerRedeem mismatch. %x != %x", m1.TakerRedeem, m2.TakerRedeem)
 	}
+	if m1.Abandoned != m2.Abandoned {
+		t.Fatalf("Abandoned mismatch. %t != %t", m1.Abandoned, m2.Abandoned)
+	}
 	MustCompareMatchAuth(t, &m1.Auth, &m2.Auth)
 }
 
@@ -279,6 +295,9 @@ func MustCompareAccountInfo(t testKiller, a1, a2 *db.AccountInfo) {
 	if !bytes.Equal(a1.LegacyFeeCoin, a2.LegacyFeeCoin) {
 		t.Fatalf("EncKey mismatch. %x != %x", a1.LegacyFeeCoin, a2.LegacyFeeCoin)
 	}
+	if strings.Join(a1.AltHosts, " ") != strings.Join(a2.AltHosts, " ") {
+		t.Fatalf("AltHosts mismatch. %v != %v", a1.AltHosts, a2.AltHosts)
+	}
 }
 
 // MustCompareOrderProof ensures the two OrderProof are identical, calling the
@@ -363,4 +382,7 @@ func MustCompareNotifications(t testKiller, n1, n2 *db.Notification) {
 	if n1.ID().String() != n2.ID().String() {
 		t.Fatalf("ID mismatch. %s != %s", n1.ID(), n2.ID())
 	}
+	if !reflect.DeepEqual(n1.NoteData, n2.NoteData) {
+		t.Fatalf("NoteData mismatch. %+v != %+v", n1.NoteData, n2.NoteData)
+	}
 }
@@ -35,7 +35,11 @@ func TestMatchProof(t *testing.T) {
 	proofs := make([]*db.MatchProof, 0, spins)
 	// Generate proofs with an average of 20% sparsity. Empty fields should not
 	// affect accurate encoding/decoding.
-	nTimes(spins, func(int) { proofs = append(proofs, RandomMatchProof(0.4)) })
+	nTimes(spins, func(i int) {
+		proof := RandomMatchProof(0.4)
+		proof.Abandoned = i%2 == 0
+		proofs = append(proofs, proof)
+	})
 	tStart := time.Now()
 	nTimes(spins, func(i int) {
 		proof := proofs[i]
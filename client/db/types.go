@@ -5,6 +5,7 @@ package db
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
@@ -32,8 +33,9 @@ const (
 	// UI updates or other high-level state changes.
 	Data
 	// Poke notifications are not persistent across sessions. These should be
-	// displayed if the user has a live notification feed. They are not stored in
-	// the database.
+	// displayed if the user has a live notification feed. They are stored in
+	// the database for DB.SearchNotifications, but are not recalled by
+	// DB.NotificationsN.
 	Poke
 	// Success and higher are stored and can be recalled using DB.NotificationsN.
 	Success
@@ -66,6 +68,56 @@ func (s Severity) String() string {
 	return "unknown severity"
 }
 
+// Priority is how urgently a Notification should be brought to the user's
+// attention. Notification sinks use the Priority to decide e.g. whether to
+// play a sound or badge an icon. Unlike Severity, Priority does not affect how
+// the Notification is stored.
+type Priority uint8
+
+const (
+	// PriorityNone notifications are not meant for display.
+	PriorityNone Priority = iota
+	// PriorityLow notifications may be displayed without alerting the user.
+	PriorityLow
+	// PriorityNormal notifications should be indicated, e.g. with a badge.
+	PriorityNormal
+	// PriorityHigh notifications should alert the user, e.g. with a sound.
+	PriorityHigh
+	// PriorityUrgent notifications require action from the user.
+	PriorityUrgent
+)
+
+// String satisfies fmt.Stringer for Priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityNone:
+		return "none"
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityUrgent:
+		return "urgent"
+	}
+	return "unknown priority"
+}
+
+// Category is the area of the application that a Notification concerns,
+// which notification sinks can use for routing.
+type Category string
+
+const (
+	CategoryTrading      Category = "trading"
+	CategoryWallet       Category = "wallet"
+	CategoryAccount      Category = "account"
+	CategoryServer       Category = "server"
+	CategoryMarketMaking Category = "marketmaking"
+	CategorySecurity     Category = "security"
+	CategoryApp          Category = "app"
+)
+
 // PrimaryCredentials should be created during app initialization. Both the seed
 // and the inner key (and technically the other two fields) should be generated
 // with a cryptographically-secure prng.
@@ -218,6 +270,9 @@ type AccountInfo struct {
 	Host      string
 	Cert      []byte
 	DEXPubKey *secp256k1.PublicKey
+	// AltHosts are alternate addresses for the same DEX server, used for
+	// failover when Host is unreachable.
+	AltHosts []string
 
 	// EncKeyV2 is an encrypted private key generated deterministically from the
 	// app seed.
@@ -245,7 +300,7 @@ type AccountInfo struct {
 // DB upgrade at some point. But how to deal with old accounts needing to store
 // this data forever?
 func (ai *AccountInfo) Encode() []byte {
-	return versionedBytes(4).
+	return versionedBytes(5).
 		AddData([]byte(ai.Host)).
 		AddData(ai.Cert).
 		AddData(ai.DEXPubKey.SerializeCompressed()).
@@ -256,7 +311,8 @@ func (ai *AccountInfo) Encode() []byte {
 		AddData(encode.Uint32Bytes(ai.BondAsset)).
 		AddData(encode.Uint32Bytes(ai.LegacyFeeAssetID)).
 		AddData(ai.LegacyFeeCoin).
-		AddData(encode.Uint16Bytes(ai.PenaltyComps))
+		AddData(encode.Uint16Bytes(ai.PenaltyComps)).
+		AddData(encodeStrings(ai.AltHosts))
 }
 
 // ViewOnly is true if account keys are not saved.
@@ -290,6 +346,8 @@ func DecodeAccountInfo(b []byte) (*AccountInfo, error) {
 		return decodeAccountInfo_v3(pushes)
 	case 4:
 		return decodeAccountInfo_v4(pushes)
+	case 5:
+		return decodeAccountInfo_v5(pushes)
 	}
 	return nil, fmt.Errorf("unknown AccountInfo version %d", ver)
 }
@@ -355,12 +413,23 @@ func decodeAccountInfo_v3(pushes [][]byte) (*AccountInfo, error) {
 
 func decodeAccountInfo_v4(pushes [][]byte) (*AccountInfo, error) {
 	if len(pushes) != 11 {
-		return nil, fmt.Errorf("decodeAccountInfo: expected 11 data pushes, got %d", len(pushes))
+		return nil, fmt.Errorf("decodeAccountInfo_v4: expected 11 data pushes, got %d", len(pushes))
+	}
+	return decodeAccountInfo_v5(append(pushes, nil)) // no AltHosts
+}
+
+func decodeAccountInfo_v5(pushes [][]byte) (*AccountInfo, error) {
+	if len(pushes) != 12 {
+		return nil, fmt.Errorf("decodeAccountInfo: expected 12 data pushes, got %d", len(pushes))
 	}
 	hostB, certB, dexPkB := pushes[0], pushes[1], pushes[2]                // dex identity
 	v2Key, legacyKeyB := pushes[3], pushes[4]                              // account identity
 	targetTierB, maxBondedB, bondAssetB := pushes[5], pushes[6], pushes[7] // bond options
 	regAssetB, coinB, penaltyComps := pushes[8], pushes[9], pushes[10]     // legacy reg fee data
+	altHosts, err := decodeStrings(pushes[11])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding alternate hosts: %w", err)
+	}
 	pk, err := secp256k1.ParsePubKey(dexPkB)
 	if err != nil {
 		return nil, err
@@ -369,6 +438,7 @@ func decodeAccountInfo_v4(pushes [][]byte) (*AccountInfo, error) {
 		Host:         string(hostB),
 		Cert:         certB,
 		DEXPubKey:    pk,
+		AltHosts:     altHosts,
 		EncKeyV2:     v2Key,
 		LegacyEncKey: legacyKeyB,
 		// Bonds decoded by DecodeBond from separate pushes.
@@ -479,6 +549,10 @@ type OrderMetaData struct {
 	// AccelerationCoins keeps track of all the change coins generated from doing
 	// accelerations on this order.
 	AccelerationCoins []order.CoinID
+	// Annotation is the user's note and tags for the order. It is set with
+	// SetOrderAnnotation, and is not changed by UpdateOrder or
+	// UpdateOrderMetaData. nil if the order has not been annotated.
+	Annotation *Annotation
 }
 
 // MetaMatch is a match and its metadata.
@@ -491,13 +565,19 @@ type MetaMatch struct {
 
 // MatchOrderUniqueID is a unique ID for the match-order pair.
 func (m *MetaMatch) MatchOrderUniqueID() []byte {
-	return hashKey(append(m.MatchID[:], m.OrderID[:]...))
+	return MatchOrderUniqueID(m.MatchID, m.OrderID)
+}
+
+// MatchOrderUniqueID is the MetaMatch.MatchOrderUniqueID of the order's match.
+func MatchOrderUniqueID(mid order.MatchID, oid order.OrderID) []byte {
+	return hashKey(append(mid[:], oid[:]...))
 }
 
 // MatchIsActive returns false (i.e. the match is inactive) if any: (1) status
 // is MatchConfirmed OR InitSig unset, signaling a cancel order match, which is
-// never active, (2) the match is refunded, or (3) it is revoked and this side
-// of the match requires no further action like refund or auto-redeem.
+// never active, (2) the match is refunded or abandoned, or (3) it is revoked
+// and this side of the match requires no further action like refund or
+// auto-redeem.
 func MatchIsActive(match *order.UserMatch, proof *MatchProof) bool {
 	// MatchComplete only means inactive if: (a) cancel order match or (b) the
 	// redeem request was accepted for trade orders. A cancel order match starts
@@ -513,8 +593,8 @@ func MatchIsActive(match *order.UserMatch, proof *MatchProof) bool {
 		return false
 	}
 
-	// Refunded matches are inactive regardless of status.
-	if len(proof.RefundCoin) > 0 {
+	// Refunded and abandoned matches are inactive regardless of status.
+	if len(proof.RefundCoin) > 0 || proof.Abandoned {
 		return false
 	}
 
@@ -584,6 +664,10 @@ type MatchMetaData struct {
 	// Stamp is the match time (ms UNIX), according to the server's 'match'
 	// request timestamp.
 	Stamp uint64
+	// Annotation is the user's note and tags for the match. It is set with
+	// SetMatchAnnotation, and is not changed by UpdateMatch. nil if the match
+	// has not been annotated.
+	Annotation *Annotation
 	// TODO: ReceiveTime uint64 -- local time stamp for match age and time display
 }
 
@@ -624,6 +708,9 @@ type MatchProof struct {
 	// RedemptionFeeConfirmed indicate the fees for this match have been
 	// confirmed and the value added to the trade.
 	RedemptionFeeConfirmed bool
+	// Abandoned indicates the user gave up on recovering the match's swap.
+	// The match is inactive, but keeps the status it had.
+	Abandoned bool
 }
 
 func boolByte(b bool) []byte {
@@ -635,8 +722,8 @@ func boolByte(b bool) []byte {
 
 // MatchProofVer is the current serialization version of a MatchProof.
 const (
-	MatchProofVer    = 3
-	matchProofPushes = 24
+	MatchProofVer    = 4
+	matchProofPushes = 25
 )
 
 // Encode encodes the MatchProof to a versioned blob.
@@ -666,7 +753,8 @@ func (p *MatchProof) Encode() []byte {
 		AddData(boolByte(p.SelfRevoked)).
 		AddData(p.CounterTxData).
 		AddData(boolByte(p.SwapFeeConfirmed)).
-		AddData(boolByte(p.RedemptionFeeConfirmed))
+		AddData(boolByte(p.RedemptionFeeConfirmed)).
+		AddData(boolByte(p.Abandoned))
 }
 
 // DecodeMatchProof decodes the versioned blob to a *MatchProof.
@@ -676,7 +764,10 @@ func DecodeMatchProof(b []byte) (*MatchProof, uint8, error) {
 		return nil, 0, err
 	}
 	switch ver {
-	case 3: // MatchProofVer
+	case 4: // MatchProofVer
+		proof, err := decodeMatchProof_v4(pushes)
+		return proof, ver, err
+	case 3:
 		proof, err := decodeMatchProof_v3(pushes)
 		return proof, ver, err
 	case 2:
@@ -710,6 +801,12 @@ func decodeMatchProof_v2(pushes [][]byte) (*MatchProof, error) {
 }
 
 func decodeMatchProof_v3(pushes [][]byte) (*MatchProof, error) {
+	// Add the MatchProof Abandoned byte.
+	pushes = append(pushes, encode.ByteFalse)
+	return decodeMatchProof_v4(pushes)
+}
+
+func decodeMatchProof_v4(pushes [][]byte) (*MatchProof, error) {
 	if len(pushes) != matchProofPushes {
 		return nil, fmt.Errorf("DecodeMatchProof: expected %d pushes, got %d",
 			matchProofPushes, len(pushes))
@@ -741,6 +838,7 @@ func decodeMatchProof_v3(pushes [][]byte) (*MatchProof, error) {
 		SelfRevoked:            bytes.Equal(pushes[20], encode.ByteTrue),
 		SwapFeeConfirmed:       bytes.Equal(pushes[21], encode.ByteTrue),
 		RedemptionFeeConfirmed: bytes.Equal(pushes[22], encode.ByteTrue),
+		Abandoned:              bytes.Equal(pushes[24], encode.ByteTrue),
 	}, nil
 }
 
@@ -749,6 +847,56 @@ func (p *MatchProof) IsRevoked() bool {
 	return p.ServerRevoked || p.SelfRevoked
 }
 
+// Annotation is a free-text note and a set of tags that the user attaches to
+// an order or match, e.g. to record the strategy that placed an order.
+type Annotation struct {
+	Note string   `json:"note,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// IsEmpty is true if the Annotation has no note and no tags.
+func (a *Annotation) IsEmpty() bool {
+	return a == nil || (a.Note == "" && len(a.Tags) == 0)
+}
+
+// Encode encodes the Annotation to a versioned blob. The first push is the
+// note, followed by one push per tag.
+func (a *Annotation) Encode() []byte {
+	b := versionedBytes(0).AddData([]byte(a.Note))
+	for _, tag := range a.Tags {
+		b = b.AddData([]byte(tag))
+	}
+	return b
+}
+
+// DecodeAnnotation decodes the versioned blob to an *Annotation. A nil
+// *Annotation is returned for an empty blob.
+func DecodeAnnotation(b []byte) (*Annotation, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	ver, pushes, err := encode.DecodeBlob(b)
+	if err != nil {
+		return nil, err
+	}
+	switch ver {
+	case 0:
+		return decodeAnnotation_v0(pushes)
+	}
+	return nil, fmt.Errorf("unknown Annotation version %d", ver)
+}
+
+func decodeAnnotation_v0(pushes [][]byte) (*Annotation, error) {
+	if len(pushes) == 0 {
+		return nil, fmt.Errorf("decodeAnnotation: expected at least 1 push, got 0")
+	}
+	a := &Annotation{Note: string(pushes[0])}
+	for _, tag := range pushes[1:] {
+		a.Tags = append(a.Tags, string(tag))
+	}
+	return a, nil
+}
+
 // OrderProof is information related to order authentication and matching.
 type OrderProof struct {
 	DEXSig   []byte
@@ -935,6 +1083,38 @@ func versionedBytes(v byte) encode.BuildyBytes {
 	return encode.BuildyBytes{v}
 }
 
+// encodeStrings encodes the strings as a version 0 blob. A nil or empty slice
+// is encoded as nil.
+func encodeStrings(strs []string) []byte {
+	if len(strs) == 0 {
+		return nil
+	}
+	b := versionedBytes(0)
+	for _, s := range strs {
+		b = b.AddData([]byte(s))
+	}
+	return b
+}
+
+// decodeStrings decodes a blob created with encodeStrings.
+func decodeStrings(b []byte) ([]string, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	ver, pushes, err := encode.DecodeBlob(b)
+	if err != nil {
+		return nil, err
+	}
+	if ver != 0 {
+		return nil, fmt.Errorf("unknown strings version %d", ver)
+	}
+	strs := make([]string, 0, len(pushes))
+	for _, p := range pushes {
+		strs = append(strs, string(p))
+	}
+	return strs, nil
+}
+
 var uint64Bytes = encode.Uint64Bytes
 var uint32Bytes = encode.Uint32Bytes
 var uint16Bytes = encode.Uint16Bytes
@@ -1042,6 +1222,69 @@ func RestoreAccountBackup(path string) (*AccountBackup, error) {
 // Topic is a language-independent unique ID for a Notification.
 type Topic string
 
+// TopicSettings are the user's settings for notifications with a particular
+// Topic.
+type TopicSettings struct {
+	// Mute suppresses notifications with the Topic, except for those with
+	// ErrorLevel severity, which are never suppressed. Muted notifications
+	// are logged, but are not stored or sent to notification subscribers.
+	Mute bool `json:"mute,omitempty"`
+	// MinSeverity suppresses notifications with the Topic that have a lower
+	// Severity, in the same way as Mute.
+	MinSeverity Severity `json:"minSeverity,omitempty"`
+	// LogOnly prevents notifications with the Topic from being sent to
+	// notification subscribers, so they are not displayed as they arrive.
+	// They are still logged and stored.
+	LogOnly bool `json:"logOnly,omitempty"`
+	// Desktop opts in to native OS desktop notifications for the Topic, which
+	// are shown even when the browser window is in the background. Desktop
+	// notifications are not shown for LogOnly topics.
+	Desktop bool `json:"desktop,omitempty"`
+}
+
+// IsZero checks if the TopicSettings are the defaults, which have no effect.
+func (s *TopicSettings) IsZero() bool {
+	return s == nil || (!s.Mute && s.MinSeverity == 0 && !s.LogOnly && !s.Desktop)
+}
+
+// Suppresses checks if the settings suppress a notification with the
+// specified Severity. Errors are not suppressed.
+func (s *TopicSettings) Suppresses(severity Severity) bool {
+	return s != nil && severity < ErrorLevel && (s.Mute || severity < s.MinSeverity)
+}
+
+// NoteTemplate is a user-defined subject and template for notifications with a
+// particular Topic, overriding the translations for every language. An empty
+// Subject or Template leaves the translation in place.
+type NoteTemplate struct {
+	Subject  string `json:"subject,omitempty"`
+	Template string `json:"template,omitempty"`
+}
+
+// IsZero checks if the NoteTemplate overrides nothing.
+func (t *NoteTemplate) IsZero() bool {
+	return t == nil || (t.Subject == "" && t.Template == "")
+}
+
+// Confirmation target speeds.
+const (
+	ConfSpeedFast    = "fast"
+	ConfSpeedNormal  = "normal"
+	ConfSpeedEconomy = "economy"
+)
+
+// ConfTarget is a user's default confirmation target for sends from a wallet.
+// Either a Speed or an explicit number of Blocks is set.
+type ConfTarget struct {
+	Speed  string `json:"speed,omitempty"`
+	Blocks uint64 `json:"blocks,omitempty"`
+}
+
+// IsZero checks if the ConfTarget sets no target.
+func (t *ConfTarget) IsZero() bool {
+	return t == nil || (t.Speed == "" && t.Blocks == 0)
+}
+
 // Notification is information for the user that is typically meant for display,
 // and is persisted for recall across sessions.
 type Notification struct {
@@ -1053,6 +1296,27 @@ type Notification struct {
 	TimeStamp   uint64    `json:"stamp"`
 	Ack         bool      `json:"acked"`
 	Id          dex.Bytes `json:"id"`
+	// Prio and Cat are metadata for notification sinks. They are set by core,
+	// and are not stored.
+	Prio Priority `json:"priority,omitempty"`
+	Cat  Category `json:"category,omitempty"`
+	// NoteData is structured data about the subject of the notification.
+	NoteData *NoteData `json:"data,omitempty"`
+}
+
+// NoteData is structured data about the subject of a Notification, so that
+// programmatic consumers do not need to parse the translated text. Fields that
+// do not apply to the notification are empty.
+type NoteData struct {
+	Host     string    `json:"host,omitempty"`
+	AssetID  *uint32   `json:"assetID,omitempty"`
+	MarketID string    `json:"marketID,omitempty"`
+	OrderID  dex.Bytes `json:"orderID,omitempty"`
+	MatchID  dex.Bytes `json:"matchID,omitempty"`
+	CoinID   string    `json:"coinID,omitempty"`
+	// Amount is in the atomic units of AssetID.
+	Amount uint64 `json:"amount,omitempty"`
+	Error  string `json:"error,omitempty"`
 }
 
 // NewNotification is a constructor for a Notification.
@@ -1068,9 +1332,11 @@ func NewNotification(noteType string, topic Topic, subject, details string, seve
 	return note
 }
 
-// ID is a unique ID based on a hash of the notification data.
+// ID is a unique ID based on a hash of the notification data. The ID does not
+// depend on the NoteData, so that the NoteData can be set after the
+// notification is constructed.
 func (n *Notification) ID() dex.Bytes {
-	return noteKey(n.Encode())
+	return noteKey(n.encode(1))
 }
 
 // Type is the notification type.
@@ -1098,11 +1364,28 @@ func (n *Notification) Severity() Severity {
 	return n.Severeness
 }
 
+// Priority is how urgently the notification should be brought to the user's
+// attention.
+func (n *Notification) Priority() Priority {
+	return n.Prio
+}
+
+// Category is the area of the application that the notification concerns.
+func (n *Notification) Category() Category {
+	return n.Cat
+}
+
 // Time is the notification timestamp. The timestamp is set in NewNotification.
 func (n *Notification) Time() uint64 {
 	return n.TimeStamp
 }
 
+// Data is the structured data about the subject of the notification. Data
+// may be nil.
+func (n *Notification) Data() *NoteData {
+	return n.NoteData
+}
+
 // Acked is true if the user has seen the notification. Acknowledgement is
 // recorded with DB.AckNotification.
 func (n *Notification) Acked() bool {
@@ -1153,6 +1436,8 @@ func DecodeNotification(b []byte) (*Notification, error) {
 		return nil, err
 	}
 	switch ver {
+	case 2:
+		return decodeNotification_v2(pushes)
 	case 1:
 		return decodeNotification_v1(pushes)
 	case 0:
@@ -1183,9 +1468,34 @@ func decodeNotification_v1(pushes [][]byte) (*Notification, error) {
 	}, nil
 }
 
-// Encode encodes the Notification to a versioned blob.
+func decodeNotification_v2(pushes [][]byte) (*Notification, error) {
+	if len(pushes) != 7 {
+		return nil, fmt.Errorf("decodeNotification_v2: expected 7 pushes, got %d", len(pushes))
+	}
+	n, err := decodeNotification_v1(pushes[:6])
+	if err != nil {
+		return nil, err
+	}
+	n.NoteData = new(NoteData)
+	if err := json.Unmarshal(pushes[6], n.NoteData); err != nil {
+		return nil, fmt.Errorf("decodeNotification_v2: error decoding note data: %w", err)
+	}
+	return n, nil
+}
+
+// Encode encodes the Notification to a versioned blob. Notifications without
+// NoteData are encoded as version 1.
 func (n *Notification) Encode() []byte {
-	return versionedBytes(1).
+	if n.NoteData == nil {
+		return n.encode(1)
+	}
+	data, _ := json.Marshal(n.NoteData) // no unmarshalable types
+	return n.encode(2).AddData(data)
+}
+
+// encode encodes all fields but the NoteData.
+func (n *Notification) encode(ver byte) encode.BuildyBytes {
+	return versionedBytes(ver).
 		AddData([]byte(n.NoteType)).
 		AddData([]byte(n.SubjectText)).
 		AddData([]byte(n.DetailText)).
@@ -1224,6 +1534,33 @@ type OrderFilter struct {
 	// FresherThanUnixMs is a unix millisecond timestamp used to filter out orders that are
 	// older than its value.
 	FresherThanUnixMs uint64
+	// Text limits results to orders with an order ID, redemption address,
+	// coin ID, note or tag, or a match with a match ID, counterparty address,
+	// coin ID, note or tag, that contains the text, ignoring case. See
+	// OrderSearchText and MatchSearchText.
+	Text string
+}
+
+// NoteFilter is used to limit the results returned by a query to
+// (DB).SearchNotifications.
+type NoteFilter struct {
+	// N is the maximum number of notifications to return. Zero means no limit.
+	N int `json:"n"`
+	// Topics is a list of acceptable topics. A zero-length Topics means all
+	// topics are accepted.
+	Topics []Topic `json:"topics"`
+	// MinSeverity and MaxSeverity limit the range of acceptable severities. A
+	// zero MaxSeverity means there is no upper limit.
+	MinSeverity Severity `json:"minSeverity"`
+	MaxSeverity Severity `json:"maxSeverity"`
+	// SinceUnixMs and UntilUnixMs are unix millisecond timestamps limiting the
+	// range of acceptable notification times, inclusive. A zero UntilUnixMs
+	// means there is no upper limit.
+	SinceUnixMs uint64 `json:"since"`
+	UntilUnixMs uint64 `json:"until"`
+	// Text limits results to notifications with a subject or details that
+	// contain the text, ignoring case.
+	Text string `json:"text"`
 }
 
 // noteKeySize must be <= 32.
@@ -0,0 +1,184 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/dex/calc"
+)
+
+// AssetAllocation is the breakdown of the recommended allocation of an asset
+// on the DEX or the CEX.
+type AssetAllocation struct {
+	// Lots is the amount needed to fund the lots of the bot's placements, or,
+	// on the CEX, to fund the counter-trades of the lots.
+	Lots uint64 `json:"lots"`
+	// BookingFees are the max swap fees for the lots, plus the refund and
+	// redeem fees that are reserved when orders are booked.
+	BookingFees uint64 `json:"bookingFees"`
+	// RedeemFees are the max redeem fees for the counter-asset of the lots,
+	// if they are paid in this asset and are not part of the BookingFees.
+	RedeemFees uint64 `json:"redeemFees"`
+	// FundingFees are the max fees for funding the placements on a side of
+	// the market.
+	FundingFees uint64 `json:"fundingFees"`
+	// RefundBuffer is the amount that would be lost to fees if all swaps
+	// were refunded, for assets that take the refund fees from the refunded
+	// funds rather than reserving them.
+	RefundBuffer uint64 `json:"refundBuffer"`
+	// Total is the sum of the above.
+	Total uint64 `json:"total"`
+}
+
+// AllocationAdvice is the recommended allocation of funds for a bot.
+type AllocationAdvice struct {
+	// Rate is the message-rate used to value the quote asset lots.
+	Rate uint64 `json:"rate"`
+	// BuyLots and SellLots are the lots that the bot places on either side of
+	// the market.
+	BuyLots  uint64                      `json:"buyLots"`
+	SellLots uint64                      `json:"sellLots"`
+	DEX      map[uint32]*AssetAllocation `json:"dex"`
+	CEX      map[uint32]*AssetAllocation `json:"cex"`
+	// Alloc is the total allocation, that can be used to start the bot.
+	Alloc *BotBalanceAllocation `json:"alloc"`
+}
+
+// botLots returns the lots that a bot with the config places on either side of
+// the market. Simple arbitrage bots place a lot on each side of the market for
+// each active arb.
+func botLots(cfg *BotConfig) (buyLots, sellLots uint64) {
+	switch {
+	case cfg.BasicMMConfig != nil:
+		for _, p := range cfg.BasicMMConfig.BuyPlacements {
+			buyLots += p.Lots
+		}
+		for _, p := range cfg.BasicMMConfig.SellPlacements {
+			sellLots += p.Lots
+		}
+	case cfg.ArbMarketMakerConfig != nil:
+		for _, p := range cfg.ArbMarketMakerConfig.BuyPlacements {
+			buyLots += p.Lots
+		}
+		for _, p := range cfg.ArbMarketMakerConfig.SellPlacements {
+			sellLots += p.Lots
+		}
+	case cfg.SimpleArbConfig != nil:
+		buyLots = uint64(cfg.SimpleArbConfig.MaxActiveArbs)
+		sellLots = buyLots
+	}
+	return
+}
+
+// recommendedAllocation calculates the allocation that is required to fund all
+// of the bot's placements at the rate, including the fees.
+func (u *unifiedExchangeAdaptor) recommendedAllocation(cfg *BotConfig, rate uint64, buyFees, sellFees *OrderFees) *AllocationAdvice {
+	buyLots, sellLots := botLots(cfg)
+	adv := &AllocationAdvice{
+		Rate:     rate,
+		BuyLots:  buyLots,
+		SellLots: sellLots,
+		DEX:      make(map[uint32]*AssetAllocation),
+		CEX:      make(map[uint32]*AssetAllocation),
+		Alloc: &BotBalanceAllocation{
+			DEX: make(map[uint32]uint64),
+			CEX: make(map[uint32]uint64),
+		},
+	}
+	get := func(allocs map[uint32]*AssetAllocation, assetID uint32) *AssetAllocation {
+		a, found := allocs[assetID]
+		if !found {
+			a = new(AssetAllocation)
+			allocs[assetID] = a
+		}
+		return a
+	}
+
+	// Every DEX asset is allocated, even if nothing is needed, so that the
+	// bot can be started with the allocation.
+	for _, assetID := range []uint32{u.baseID, u.quoteID, u.baseFeeID, u.quoteFeeID} {
+		get(adv.DEX, assetID)
+	}
+
+	sameChain := u.baseFeeID == u.quoteFeeID
+	if sellLots > 0 {
+		get(adv.DEX, u.baseID).Lots += sellLots * u.lotSize
+		get(adv.DEX, u.baseFeeID).BookingFees += sellLots * sellFees.BookingFeesPerLot
+		get(adv.DEX, u.baseFeeID).FundingFees += sellFees.Funding
+		if !sameChain && u.quoteTraits.IsAccountLocker() {
+			get(adv.DEX, u.quoteFeeID).RedeemFees += sellLots * sellFees.Max.Redeem
+		}
+		if !u.baseTraits.IsAccountLocker() {
+			get(adv.DEX, u.baseFeeID).RefundBuffer += sellLots * sellFees.Max.Refund
+		}
+	}
+	if buyLots > 0 {
+		get(adv.DEX, u.quoteID).Lots += calc.BaseToQuote(rate, buyLots*u.lotSize)
+		get(adv.DEX, u.quoteFeeID).BookingFees += buyLots * buyFees.BookingFeesPerLot
+		get(adv.DEX, u.quoteFeeID).FundingFees += buyFees.Funding
+		if !sameChain && u.baseTraits.IsAccountLocker() {
+			get(adv.DEX, u.baseFeeID).RedeemFees += buyLots * buyFees.Max.Redeem
+		}
+		if !u.quoteTraits.IsAccountLocker() {
+			get(adv.DEX, u.quoteFeeID).RefundBuffer += buyLots * buyFees.Max.Refund
+		}
+	}
+
+	// DEX buys are countered by CEX sells, and DEX sells by CEX buys.
+	if cfg.requiresCEX() {
+		get(adv.CEX, u.baseID).Lots += buyLots * u.lotSize
+		get(adv.CEX, u.quoteID).Lots += calc.BaseToQuote(rate, sellLots*u.lotSize)
+	}
+
+	sum := func(allocs map[uint32]*AssetAllocation, totals map[uint32]uint64) {
+		for assetID, a := range allocs {
+			a.Total = a.Lots + a.BookingFees + a.RedeemFees + a.FundingFees + a.RefundBuffer
+			totals[assetID] = a.Total
+		}
+	}
+	sum(adv.DEX, adv.Alloc.DEX)
+	sum(adv.CEX, adv.Alloc.CEX)
+	return adv
+}
+
+// RecommendedAllocation calculates the balances that a bot with the config
+// requires to fund all of its placements, including the reserves for swap,
+// redeem and refund fees. The quote asset lots are valued at the oracle rate,
+// or the DEX book's mid-gap if there is no oracle rate.
+func (m *MarketMaker) RecommendedAllocation(cfg *BotConfig) (*AllocationAdvice, error) {
+	if err := validateBotConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	u, err := newUnifiedExchangeAdaptor(&exchangeAdaptorCfg{
+		botID:  "alloc-" + dexMarketID(cfg.Host, cfg.BaseID, cfg.QuoteID),
+		mwh:    &MarketWithHost{cfg.Host, cfg.BaseID, cfg.QuoteID},
+		core:   m.core,
+		log:    m.botSubLogger(cfg),
+		botCfg: cfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buyFees, sellFees, err := u.updateFeeRates()
+	if err != nil {
+		return nil, fmt.Errorf("error getting fees: %w", err)
+	}
+
+	rate := u.msgRate(m.oracle.getMarketPrice(cfg.BaseID, cfg.QuoteID))
+	if rate == 0 {
+		book, feed, err := m.core.SyncBook(cfg.Host, cfg.BaseID, cfg.QuoteID)
+		if err != nil {
+			return nil, fmt.Errorf("error syncing book: %w", err)
+		}
+		defer feed.Close()
+		if rate, err = book.MidGap(); err != nil {
+			return nil, fmt.Errorf("no oracle rate and no mid-gap rate: %w", err)
+		}
+	}
+
+	return u.recommendedAllocation(cfg, rate, buyFees, sellFees), nil
+}
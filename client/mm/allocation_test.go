@@ -0,0 +1,104 @@
+package mm
+
+import (
+	"reflect"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+func TestRecommendedAllocation(t *testing.T) {
+	const lotSize uint64 = 1e8
+	const rate uint64 = 1e6
+	buyFees := &OrderFees{
+		LotFeeRange:       &LotFeeRange{Max: &LotFees{Swap: 100, Redeem: 70, Refund: 80}},
+		Funding:           50,
+		BookingFeesPerLot: 100,
+	}
+	sellFees := &OrderFees{
+		LotFeeRange:       &LotFeeRange{Max: &LotFees{Swap: 200, Redeem: 90, Refund: 110}},
+		Funding:           60,
+		BookingFeesPerLot: 200,
+	}
+	basicCfg := func(buyLots, sellLots uint64) *BotConfig {
+		return &BotConfig{BasicMMConfig: &BasicMarketMakingConfig{
+			BuyPlacements:  []*OrderPlacement{{Lots: buyLots}},
+			SellPlacements: []*OrderPlacement{{Lots: 1}, {Lots: sellLots - 1}},
+		}}
+	}
+	const accountLocker = asset.WalletTraitAccountLocker
+
+	tests := []struct {
+		name                    string
+		baseID, quoteID         uint32
+		baseFeeID, quoteFeeID   uint32
+		baseTraits, quoteTraits asset.WalletTrait
+		cfg                     *BotConfig
+		wantDEX, wantCEX        map[uint32]uint64
+		wantRedeem              map[uint32]uint64
+	}{{
+		name:   "utxo assets",
+		baseID: 42, quoteID: 0, baseFeeID: 42, quoteFeeID: 0,
+		cfg: basicCfg(2, 3),
+		wantDEX: map[uint32]uint64{
+			// lots + booking + funding + refund buffer
+			42: 3e8 + 600 + 60 + 330,
+			0:  2e6 + 200 + 50 + 160,
+		},
+		wantCEX: map[uint32]uint64{},
+	}, {
+		name:   "same chain account assets with cex",
+		baseID: 60, quoteID: 60001, baseFeeID: 60, quoteFeeID: 60,
+		baseTraits: accountLocker, quoteTraits: accountLocker,
+		cfg: &BotConfig{ArbMarketMakerConfig: &ArbMarketMakerConfig{
+			BuyPlacements:  []*ArbMarketMakingPlacement{{Lots: 1}},
+			SellPlacements: []*ArbMarketMakingPlacement{{Lots: 2}},
+		}},
+		wantDEX: map[uint32]uint64{
+			60:    2e8 + 400 + 60 + 100 + 50,
+			60001: 1e6,
+		},
+		wantCEX: map[uint32]uint64{
+			60:    1e8,
+			60001: 2e6,
+		},
+	}, {
+		name:   "token quote redeem fees",
+		baseID: 42, quoteID: 60001, baseFeeID: 42, quoteFeeID: 60,
+		quoteTraits: accountLocker,
+		cfg:         basicCfg(1, 2),
+		wantDEX: map[uint32]uint64{
+			42:    2e8 + 400 + 60 + 220,
+			60001: 1e6,
+			60:    100 + 50 + 2*90,
+		},
+		wantCEX:    map[uint32]uint64{},
+		wantRedeem: map[uint32]uint64{60: 180},
+	}}
+
+	for _, tt := range tests {
+		u := &unifiedExchangeAdaptor{
+			market: &market{
+				lotSize:    lotSize,
+				baseID:     tt.baseID,
+				quoteID:    tt.quoteID,
+				baseFeeID:  tt.baseFeeID,
+				quoteFeeID: tt.quoteFeeID,
+			},
+			baseTraits:  tt.baseTraits,
+			quoteTraits: tt.quoteTraits,
+		}
+		adv := u.recommendedAllocation(tt.cfg, rate, buyFees, sellFees)
+		if !reflect.DeepEqual(adv.Alloc.DEX, tt.wantDEX) {
+			t.Fatalf("%s: wanted dex allocation %v, got %v", tt.name, tt.wantDEX, adv.Alloc.DEX)
+		}
+		if !reflect.DeepEqual(adv.Alloc.CEX, tt.wantCEX) {
+			t.Fatalf("%s: wanted cex allocation %v, got %v", tt.name, tt.wantCEX, adv.Alloc.CEX)
+		}
+		for assetID, redeem := range tt.wantRedeem {
+			if adv.DEX[assetID].RedeemFees != redeem {
+				t.Fatalf("%s: wanted %d redeem fees for asset %d, got %d", tt.name, redeem, assetID, adv.DEX[assetID].RedeemFees)
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// BenchmarkReport compares the performance of a market making run with a
+// buy-and-hold benchmark. The benchmark holds the run's starting inventory,
+// adjusted by any inventory modifications made during the run, for the same
+// period. All USD values are at the fiat rates at the end of the run, unless
+// noted otherwise.
+type BenchmarkReport struct {
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+	Running   bool  `json:"running"`
+	// Hold is the benchmark's inventory.
+	Hold    map[uint32]*Amount `json:"hold"`
+	HoldUSD float64            `json:"holdUSD"`
+	// HoldStartUSD is the value of the benchmark's inventory at the fiat rates
+	// at the start of the run. It is zero if the starting rates are not known,
+	// in which case HoldReturn and BotReturn are also zero.
+	HoldStartUSD float64 `json:"holdStartUSD"`
+	// HoldReturn is the benchmark's return over the period due to the change
+	// in fiat rates.
+	HoldReturn float64 `json:"holdReturn"`
+	// Bot is the bot's inventory at the end of the run.
+	Bot    map[uint32]*Amount `json:"bot"`
+	BotUSD float64            `json:"botUSD"`
+	// BotReturn is the bot's return over the period.
+	BotReturn float64 `json:"botReturn"`
+	// ExcessUSD is the value of the bot's inventory less the value of the
+	// benchmark's inventory, i.e. what was gained by running the bot instead
+	// of doing nothing. Fees and CEX costs have been paid.
+	ExcessUSD float64 `json:"excessUSD"`
+	// ExcessRatio is ExcessUSD as a ratio of HoldUSD.
+	ExcessRatio float64 `json:"excessRatio"`
+	// AnnualizedExcessRatio is ExcessRatio scaled to a year, a measure of
+	// how efficiently the bot uses its capital.
+	AnnualizedExcessRatio float64 `json:"annualizedExcessRatio"`
+	// Fees are the on-chain transaction fees paid for swaps, redeems,
+	// refunds, and deposits to the CEX.
+	Fees    map[uint32]*Amount `json:"fees"`
+	FeesUSD float64            `json:"feesUSD"`
+	// CEXCosts are the amounts lost to CEX deposit and withdrawal fees. CEX
+	// trading fees are deducted from the CEX trades, and are reflected in the
+	// bot's inventory, but not here.
+	CEXCosts    map[uint32]*Amount `json:"cexCosts"`
+	CEXCostsUSD float64            `json:"cexCostsUSD"`
+	// GrossExcessUSD is ExcessUSD before Fees and CEX costs.
+	GrossExcessUSD float64 `json:"grossExcessUSD"`
+	// BeatsHold is true if the bot's inventory is worth more than the
+	// benchmark's.
+	BeatsHold bool `json:"beatsHold"`
+}
+
+// newBenchmarkReport generates a BenchmarkReport from a run's overview and
+// events.
+func newBenchmarkReport(mkt *MarketWithHost, startTime, endTime int64, overview *MarketMakingRunOverview, events []*MarketMakingEvent) *BenchmarkReport {
+	finalRates := make(map[uint32]float64)
+	mods := make(map[uint32]int64)
+	finalBals := make(map[uint32]uint64)
+	if fs := overview.FinalState; fs != nil {
+		finalRates = fs.FiatRates
+		mods = fs.InventoryMods
+		for assetID, bal := range fs.Balances {
+			finalBals[assetID] = bal.Available + bal.Pending + bal.Locked + bal.Reserved
+		}
+	}
+
+	r := &BenchmarkReport{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Running:   overview.EndTime == nil,
+		Hold:      make(map[uint32]*Amount),
+		Bot:       make(map[uint32]*Amount),
+		Fees:      make(map[uint32]*Amount),
+		CEXCosts:  make(map[uint32]*Amount),
+	}
+
+	holdBals := make(map[uint32]int64, len(overview.InitialBalances))
+	for assetID, bal := range overview.InitialBalances {
+		holdBals[assetID] = int64(bal)
+	}
+	for assetID, mod := range mods {
+		holdBals[assetID] += mod
+	}
+	startRatesKnown := len(overview.InitialFiatRates) > 0
+	for assetID, bal := range holdBals {
+		if bal <= 0 {
+			continue
+		}
+		amt := NewAmount(assetID, bal, finalRates[assetID])
+		r.Hold[assetID] = amt
+		r.HoldUSD += amt.USD
+		if startRatesKnown {
+			r.HoldStartUSD += NewAmount(assetID, bal, overview.InitialFiatRates[assetID]).USD
+		}
+	}
+	for assetID, bal := range finalBals {
+		if bal == 0 {
+			continue
+		}
+		amt := NewAmount(assetID, int64(bal), finalRates[assetID])
+		r.Bot[assetID] = amt
+		r.BotUSD += amt.USD
+	}
+
+	fees := make(map[uint32]int64)
+	cexCosts := make(map[uint32]int64)
+	for _, e := range events {
+		switch {
+		case e.DEXOrderEvent != nil:
+			_, fromFeeAsset, _, toFeeAsset := orderAssets(mkt.BaseID, mkt.QuoteID, e.DEXOrderEvent.Sell)
+			for _, tx := range e.DEXOrderEvent.Transactions {
+				if tx.Type == asset.Redeem {
+					fees[toFeeAsset] += int64(tx.Fees)
+				} else {
+					fees[fromFeeAsset] += int64(tx.Fees)
+				}
+			}
+		case e.DepositEvent != nil:
+			d := e.DepositEvent
+			if d.Transaction == nil {
+				continue
+			}
+			feeAsset := d.AssetID
+			if token := asset.TokenInfo(d.AssetID); token != nil {
+				feeAsset = token.ParentID
+			}
+			fees[feeAsset] += int64(d.Transaction.Fees)
+			if d.CEXCredit > 0 && d.CEXCredit < d.Transaction.Amount {
+				cexCosts[d.AssetID] += int64(d.Transaction.Amount - d.CEXCredit)
+			}
+		case e.WithdrawalEvent != nil:
+			w := e.WithdrawalEvent
+			if w.Transaction != nil && w.Transaction.Amount < w.CEXDebit {
+				cexCosts[w.AssetID] += int64(w.CEXDebit - w.Transaction.Amount)
+			}
+		}
+	}
+	for assetID, fee := range fees {
+		if fee == 0 {
+			continue
+		}
+		amt := NewAmount(assetID, fee, finalRates[assetID])
+		r.Fees[assetID] = amt
+		r.FeesUSD += amt.USD
+	}
+	for assetID, cost := range cexCosts {
+		amt := NewAmount(assetID, cost, finalRates[assetID])
+		r.CEXCosts[assetID] = amt
+		r.CEXCostsUSD += amt.USD
+	}
+
+	r.ExcessUSD = r.BotUSD - r.HoldUSD
+	r.GrossExcessUSD = r.ExcessUSD + r.FeesUSD + r.CEXCostsUSD
+	r.BeatsHold = r.ExcessUSD > 0
+	if r.HoldUSD > 0 {
+		r.ExcessRatio = r.ExcessUSD / r.HoldUSD
+		if dur := endTime - startTime; dur > 0 {
+			r.AnnualizedExcessRatio = r.ExcessRatio * secondsPerYear / float64(dur)
+		}
+	}
+	if r.HoldStartUSD > 0 {
+		r.HoldReturn = (r.HoldUSD - r.HoldStartUSD) / r.HoldStartUSD
+		r.BotReturn = (r.BotUSD - r.HoldStartUSD) / r.HoldStartUSD
+	}
+	return r
+}
+
+// BenchmarkReport compares the performance of a market making run with a
+// buy-and-hold benchmark of the same starting inventory over the same period.
+// The report for a running bot covers the run so far.
+func (m *MarketMaker) BenchmarkReport(startTime int64, mkt *MarketWithHost) (*BenchmarkReport, error) {
+	overview, err := m.eventLogDB.runOverview(startTime, mkt)
+	if err != nil {
+		return nil, fmt.Errorf("error getting run overview: %w", err)
+	}
+	events, err := m.eventLogDB.runEvents(startTime, mkt, 0, nil, false, noFilters)
+	if err != nil {
+		return nil, fmt.Errorf("error getting run events: %w", err)
+	}
+	endTime := time.Now().Unix()
+	if overview.EndTime != nil {
+		endTime = *overview.EndTime
+	}
+	return newBenchmarkReport(mkt, startTime, endTime, overview, events), nil
+}
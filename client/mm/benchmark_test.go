@@ -0,0 +1,98 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"math"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+func TestBenchmarkReport(t *testing.T) {
+	const startTime, endTime = 1700000000, 1700000000 + 86400
+	mkt := &MarketWithHost{Host: "dex.com", BaseID: 42, QuoteID: 0}
+	endT := int64(endTime)
+	overview := &MarketMakingRunOverview{
+		EndTime:          &endT,
+		InitialBalances:  map[uint32]uint64{42: 10e8, 0: 1e8},
+		InitialFiatRates: map[uint32]float64{42: 20, 0: 50000},
+		FinalState: &BalanceState{
+			FiatRates: map[uint32]float64{42: 25, 0: 60000},
+			Balances: map[uint32]*BotBalance{
+				42: {Available: 19e8, Pending: 1e8},
+				0:  {Available: 0.999e8},
+			},
+			InventoryMods: map[uint32]int64{42: 2e8},
+		},
+	}
+	events := []*MarketMakingEvent{{
+		DEXOrderEvent: &DEXOrderEvent{
+			Sell: true,
+			Transactions: []*asset.WalletTransaction{
+				{Type: asset.Swap, Fees: 1000},
+				{Type: asset.Redeem, Fees: 500},
+			},
+		},
+	}, {
+		DepositEvent: &DepositEvent{
+			AssetID:     0,
+			Transaction: &asset.WalletTransaction{Amount: 1e7, Fees: 200},
+			CEXCredit:   0.99e7,
+		},
+	}, {
+		WithdrawalEvent: &WithdrawalEvent{
+			AssetID:     42,
+			Transaction: &asset.WalletTransaction{Amount: 0.99e8},
+			CEXDebit:    1e8,
+		},
+	}, {
+		UpdateConfig: &BotConfig{},
+	}}
+
+	r := newBenchmarkReport(mkt, startTime, endTime, overview, events)
+
+	near := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-6 {
+			t.Fatalf("wrong %s: wanted %f, got %f", name, want, got)
+		}
+	}
+	if r.Running {
+		t.Fatalf("ended run reported as running")
+	}
+	if r.Hold[42].Atoms != 12e8 || r.Hold[0].Atoms != 1e8 {
+		t.Fatalf("wrong hold inventory: %+v", r.Hold)
+	}
+	near("hold start", r.HoldStartUSD, 12*20+50000)
+	near("hold", r.HoldUSD, 12*25+60000)
+	near("bot", r.BotUSD, 20*25+0.999*60000)
+	near("hold return", r.HoldReturn, (60300.-50240)/50240)
+	near("bot return", r.BotReturn, (60440.-50240)/50240)
+	near("excess", r.ExcessUSD, 140)
+	near("excess ratio", r.ExcessRatio, 140./60300)
+	near("annualized excess ratio", r.AnnualizedExcessRatio, 140./60300*365)
+	if !r.BeatsHold {
+		t.Fatalf("bot did not beat hold")
+	}
+
+	if r.Fees[42].Atoms != 1000 || r.Fees[0].Atoms != 700 {
+		t.Fatalf("wrong fees: %+v", r.Fees)
+	}
+	near("fees", r.FeesUSD, 1000e-8*25+700e-8*60000)
+	if r.CEXCosts[42].Atoms != 1e6 || r.CEXCosts[0].Atoms != 1e5 {
+		t.Fatalf("wrong cex costs: %+v", r.CEXCosts)
+	}
+	near("cex costs", r.CEXCostsUSD, 0.01*25+0.001*60000)
+	near("gross excess", r.GrossExcessUSD, r.ExcessUSD+r.FeesUSD+r.CEXCostsUSD)
+
+	// Without the starting rates, the returns are unknown.
+	overview.InitialFiatRates = nil
+	overview.EndTime = nil
+	r = newBenchmarkReport(mkt, startTime, endTime, overview, events)
+	if !r.Running || r.HoldStartUSD != 0 || r.HoldReturn != 0 || r.BotReturn != 0 {
+		t.Fatalf("wrong report without starting rates: %+v", r)
+	}
+	near("excess without starting rates", r.ExcessUSD, 140)
+}
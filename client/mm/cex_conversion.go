@@ -0,0 +1,340 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"decred.org/dcrdex/client/mm/libxc"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+)
+
+// cexConversionLeg is a trade on a CEX market that is one step of a
+// conversion of one asset to another.
+type cexConversionLeg struct {
+	baseID  uint32
+	quoteID uint32
+	// sell is true if the leg sells the base asset for the quote asset.
+	sell bool
+}
+
+func (l *cexConversionLeg) fromID() uint32 {
+	if l.sell {
+		return l.baseID
+	}
+	return l.quoteID
+}
+
+func (l *cexConversionLeg) toID() uint32 {
+	if l.sell {
+		return l.quoteID
+	}
+	return l.baseID
+}
+
+// cexConversionRoutes finds the routes for converting fromID to toID on a CEX
+// with the markets. A route is either a direct trade, or two trades through
+// an intermediate asset.
+func cexConversionRoutes(mkts map[string]*libxc.Market, fromID, toID uint32) [][]*cexConversionLeg {
+	type pair [2]uint32
+	listed := make(map[pair]bool, len(mkts))
+	for _, mkt := range mkts {
+		listed[pair{mkt.BaseID, mkt.QuoteID}] = true
+	}
+	leg := func(from, to uint32) *cexConversionLeg {
+		switch {
+		case listed[pair{from, to}]:
+			return &cexConversionLeg{baseID: from, quoteID: to, sell: true}
+		case listed[pair{to, from}]:
+			return &cexConversionLeg{baseID: to, quoteID: from}
+		}
+		return nil
+	}
+
+	routes := make([][]*cexConversionLeg, 0)
+	if direct := leg(fromID, toID); direct != nil {
+		routes = append(routes, []*cexConversionLeg{direct})
+	}
+	intermediates := make(map[uint32]bool)
+	for p := range listed {
+		for _, assetID := range p {
+			if assetID != fromID && assetID != toID {
+				intermediates[assetID] = true
+			}
+		}
+	}
+	ids := make([]uint32, 0, len(intermediates))
+	for assetID := range intermediates {
+		ids = append(ids, assetID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, assetID := range ids {
+		first, second := leg(fromID, assetID), leg(assetID, toID)
+		if first != nil && second != nil {
+			routes = append(routes, []*cexConversionLeg{first, second})
+		}
+	}
+	return routes
+}
+
+// cexMarketData is the CEX market data needed to price a conversion.
+type cexMarketData interface {
+	VWAP(baseID, quoteID uint32, sell bool, qty uint64) (vwap, extrema uint64, filled bool, err error)
+	MidGap(baseID, quoteID uint32) uint64
+}
+
+// cexConversionQuote is the estimated result of a conversion along a route.
+type cexConversionQuote struct {
+	route []*cexConversionLeg
+	// out is the estimated amount of the asset converted to.
+	out uint64
+	// slippage is the fraction of the value that is lost to the depth of the
+	// books, relative to converting at the mid-gap rates.
+	slippage float64
+}
+
+// quoteCEXConversion estimates the result of converting qty along the route
+// by walking the books of the route's markets.
+func quoteCEXConversion(cex cexMarketData, route []*cexConversionLeg, qty uint64) (*cexConversionQuote, error) {
+	amt, midAmt := qty, float64(qty)
+	for _, leg := range route {
+		midGap := cex.MidGap(leg.baseID, leg.quoteID)
+		if midGap == 0 {
+			return nil, fmt.Errorf("no mid-gap rate for market %d-%d", leg.baseID, leg.quoteID)
+		}
+		midRate := float64(midGap) / calc.RateEncodingFactor
+		if leg.sell {
+			avg, _, filled, err := cex.VWAP(leg.baseID, leg.quoteID, false, amt)
+			if err != nil {
+				return nil, err
+			}
+			if !filled {
+				return nil, fmt.Errorf("not enough depth to sell %d on market %d-%d", amt, leg.baseID, leg.quoteID)
+			}
+			amt = calc.BaseToQuote(avg, amt)
+			midAmt *= midRate
+			continue
+		}
+		// The base quantity that the quote buys is estimated at the mid-gap
+		// rate and then refined with the average rate for that quantity.
+		baseQty := calc.QuoteToBase(midGap, amt)
+		avg, _, filled, err := cex.VWAP(leg.baseID, leg.quoteID, true, baseQty)
+		if err != nil {
+			return nil, err
+		}
+		if !filled {
+			return nil, fmt.Errorf("not enough depth to buy %d on market %d-%d", baseQty, leg.baseID, leg.quoteID)
+		}
+		amt = calc.QuoteToBase(avg, amt)
+		midAmt /= midRate
+	}
+	if amt == 0 || midAmt == 0 {
+		return nil, errors.New("conversion amount too small")
+	}
+	return &cexConversionQuote{
+		route:    route,
+		out:      amt,
+		slippage: math.Max(0, 1-float64(amt)/midAmt),
+	}, nil
+}
+
+// cexConversionLegOrder is the rate and quantity of the order for a leg that
+// trades amt of the leg's from asset. The rate is tolerance worse than the
+// mid-gap rate, so the order does not fill deeper into the book than that.
+func cexConversionLegOrder(leg *cexConversionLeg, amt, midGap uint64, tolerance float64) (rate, qty uint64) {
+	if leg.sell {
+		return uint64(math.Round(float64(midGap) * (1 - tolerance))), amt
+	}
+	rate = uint64(math.Round(float64(midGap) * (1 + tolerance)))
+	return rate, calc.QuoteToBase(rate, amt)
+}
+
+// cexConversion is a conversion of CEX inventory that is in progress.
+type cexConversion struct {
+	route []*cexConversionLeg
+	// leg is the index of the route's current leg.
+	leg int
+	// tolerance is the slippage tolerance of each leg.
+	tolerance float64
+}
+
+// CEXConversion describes a conversion of CEX inventory started by
+// ConvertCEXInventory.
+type CEXConversion struct {
+	FromID uint32 `json:"fromID"`
+	ToID   uint32 `json:"toID"`
+	Qty    uint64 `json:"qty"`
+	// Path is the assets along the route, from FromID to ToID.
+	Path []uint32 `json:"path"`
+	// EstimatedOut is the estimated amount of the ToID asset.
+	EstimatedOut uint64 `json:"estimatedOut"`
+	// Slippage is the estimated fraction of the value lost to the depth of the
+	// books, relative to converting at the mid-gap rates.
+	Slippage float64 `json:"slippage"`
+}
+
+// convertCEXInventory converts qty of the bot's CEX balance of fromID to
+// toID. The routes through a direct market and through any intermediate asset
+// are priced from the CEX's books, and the route with the best result is
+// used, as long as its slippage does not exceed maxSlippage. The trades of
+// multi-leg routes are placed in sequence as the previous trade completes,
+// and each trade's rate is limited so that the total slippage stays within
+// maxSlippage. Books of markets that were not yet subscribed to may need time
+// to sync, in which case the conversion can be retried.
+func (u *unifiedExchangeAdaptor) convertCEXInventory(fromID, toID uint32, qty uint64, maxSlippage float64) (*CEXConversion, error) {
+	if u.CEX == nil {
+		return nil, errors.New("bot does not use a CEX")
+	}
+	if fromID == toID {
+		return nil, errors.New("cannot convert an asset to itself")
+	}
+	if qty == 0 {
+		return nil, errors.New("zero quantity")
+	}
+	if maxSlippage <= 0 || maxSlippage >= 1 {
+		return nil, fmt.Errorf("max slippage must be 0 < s < 1, but got %v", maxSlippage)
+	}
+	if bal := u.CEXBalance(fromID); bal.Available < qty {
+		return nil, fmt.Errorf("insufficient %s balance. %d available, %d requested",
+			dex.BipIDSymbol(fromID), bal.Available, qty)
+	}
+
+	mkts, err := u.CEX.Markets(u.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting CEX markets: %w", err)
+	}
+	routes := cexConversionRoutes(mkts, fromID, toID)
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no route from %s to %s on the CEX", dex.BipIDSymbol(fromID), dex.BipIDSymbol(toID))
+	}
+
+	var best *cexConversionQuote
+	quoteErrs := make([]string, 0)
+	for _, route := range routes {
+		for _, leg := range route {
+			if err := u.CEX.SubscribeMarket(u.ctx, leg.baseID, leg.quoteID); err != nil {
+				u.log.Errorf("Error subscribing to CEX market %d-%d: %v", leg.baseID, leg.quoteID, err)
+			}
+		}
+		q, err := quoteCEXConversion(u.CEX, route, qty)
+		if err != nil {
+			quoteErrs = append(quoteErrs, err.Error())
+			continue
+		}
+		if q.slippage > maxSlippage {
+			quoteErrs = append(quoteErrs, fmt.Sprintf("slippage of %.4f through %s exceeds the limit", q.slippage, cexConversionPath(route)))
+			continue
+		}
+		if best == nil || q.out > best.out {
+			best = q
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no acceptable route from %s to %s: %s",
+			dex.BipIDSymbol(fromID), dex.BipIDSymbol(toID), strings.Join(quoteErrs, "; "))
+	}
+
+	conv := &cexConversion{
+		route:     best.route,
+		tolerance: maxSlippage / float64(len(best.route)),
+	}
+	u.log.Infof("Converting %d %s to %s on the CEX through %s. Estimated result %d, slippage %.4f",
+		qty, dex.BipIDSymbol(fromID), dex.BipIDSymbol(toID), cexConversionPath(best.route), best.out, best.slippage)
+	if err := u.placeCEXConversionLeg(conv, qty); err != nil {
+		return nil, err
+	}
+	path := make([]uint32, 0, len(best.route)+1)
+	path = append(path, fromID)
+	for _, leg := range best.route {
+		path = append(path, leg.toID())
+	}
+	return &CEXConversion{
+		FromID:       fromID,
+		ToID:         toID,
+		Qty:          qty,
+		Path:         path,
+		EstimatedOut: best.out,
+		Slippage:     best.slippage,
+	}, nil
+}
+
+// placeCEXConversionLeg places the trade for the conversion's current leg.
+// If the trade is not completed immediately, the conversion continues when
+// the trade update is received.
+func (u *unifiedExchangeAdaptor) placeCEXConversionLeg(conv *cexConversion, amt uint64) error {
+	for {
+		leg := conv.route[conv.leg]
+		midGap := u.CEX.MidGap(leg.baseID, leg.quoteID)
+		if midGap == 0 {
+			return fmt.Errorf("no mid-gap rate for market %d-%d", leg.baseID, leg.quoteID)
+		}
+		rate, qty := cexConversionLegOrder(leg, amt, midGap, conv.tolerance)
+
+		// Hold the lock while trading so that the trade's update can't be
+		// handled before the conversion is recorded.
+		u.cexConversionsMtx.Lock()
+		trade, err := u.CEXTrade(u.ctx, leg.baseID, leg.quoteID, leg.sell, rate, qty)
+		if err != nil {
+			u.cexConversionsMtx.Unlock()
+			return fmt.Errorf("error placing conversion trade on market %d-%d: %w", leg.baseID, leg.quoteID, err)
+		}
+		if !trade.Complete {
+			u.cexConversions[trade.ID] = conv
+			u.cexConversionsMtx.Unlock()
+			return nil
+		}
+		u.cexConversionsMtx.Unlock()
+
+		var ok bool
+		if amt, ok = conv.advance(trade); !ok {
+			return nil
+		}
+	}
+}
+
+// advance moves the conversion to its next leg after the current leg's trade
+// completes, returning the amount for the next leg. If there are no more legs,
+// or nothing was received, false is returned.
+func (conv *cexConversion) advance(trade *libxc.Trade) (uint64, bool) {
+	received := trade.BaseFilled
+	if trade.Sell {
+		received = trade.QuoteFilled
+	}
+	conv.leg++
+	return received, received > 0 && conv.leg < len(conv.route)
+}
+
+// continueCEXConversion places the next trade of the conversion that the
+// completed trade is a part of, if any.
+func (u *unifiedExchangeAdaptor) continueCEXConversion(trade *libxc.Trade) {
+	u.cexConversionsMtx.Lock()
+	conv, found := u.cexConversions[trade.ID]
+	delete(u.cexConversions, trade.ID)
+	u.cexConversionsMtx.Unlock()
+	if !found {
+		return
+	}
+	amt, ok := conv.advance(trade)
+	if !ok {
+		return
+	}
+	if err := u.placeCEXConversionLeg(conv, amt); err != nil {
+		u.log.Errorf("CEX conversion stopped at %d %s: %v", amt, dex.BipIDSymbol(conv.route[conv.leg].fromID()), err)
+	}
+}
+
+// cexConversionPath is a string representation of a route, e.g. dcr->btc->usdt.
+func cexConversionPath(route []*cexConversionLeg) string {
+	symbols := make([]string, 0, len(route)+1)
+	symbols = append(symbols, dex.BipIDSymbol(route[0].fromID()))
+	for _, leg := range route {
+		symbols = append(symbols, dex.BipIDSymbol(leg.toID()))
+	}
+	return strings.Join(symbols, "->")
+}
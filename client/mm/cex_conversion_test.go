@@ -0,0 +1,125 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"math"
+	"testing"
+
+	"decred.org/dcrdex/client/mm/libxc"
+	"decred.org/dcrdex/dex/calc"
+)
+
+func TestCEXConversionRoutes(t *testing.T) {
+	const dcr, btc, eth, usdt uint32 = 42, 0, 60, 60002
+	mkts := map[string]*libxc.Market{
+		"dcr_btc":  {BaseID: dcr, QuoteID: btc},
+		"btc_usdt": {BaseID: btc, QuoteID: usdt},
+		"eth_btc":  {BaseID: eth, QuoteID: btc},
+		"eth_usdt": {BaseID: eth, QuoteID: usdt},
+	}
+
+	// No direct market, one route through BTC.
+	routes := cexConversionRoutes(mkts, dcr, usdt)
+	if len(routes) != 1 || cexConversionPath(routes[0]) != "dcr->btc->usdt.eth" {
+		t.Fatalf("wrong DCR to USDT routes %+v", routes)
+	}
+	if !routes[0][0].sell || !routes[0][1].sell {
+		t.Fatalf("DCR to USDT legs should both be sells")
+	}
+
+	// Converting back buys.
+	routes = cexConversionRoutes(mkts, usdt, dcr)
+	if len(routes) != 1 || routes[0][0].sell || routes[0][1].sell {
+		t.Fatalf("wrong USDT to DCR routes %+v", routes)
+	}
+
+	// A direct market and a route through ETH.
+	routes = cexConversionRoutes(mkts, btc, usdt)
+	if len(routes) != 2 || len(routes[0]) != 1 || cexConversionPath(routes[1]) != "btc->eth->usdt.eth" {
+		t.Fatalf("wrong BTC to USDT routes %+v", routes)
+	}
+
+	if routes = cexConversionRoutes(mkts, dcr, 966); len(routes) != 0 {
+		t.Fatalf("expected no routes, got %+v", routes)
+	}
+}
+
+type tMarketBook struct {
+	midGap uint64
+	// bids and asks are the VWAP results for any quantity.
+	bidAvg, askAvg uint64
+}
+
+type tCEXMarketData map[[2]uint32]*tMarketBook
+
+func (m tCEXMarketData) VWAP(baseID, quoteID uint32, sell bool, qty uint64) (vwap, extrema uint64, filled bool, err error) {
+	b := m[[2]uint32{baseID, quoteID}]
+	if b == nil {
+		return 0, 0, false, nil
+	}
+	if sell {
+		return b.askAvg, b.askAvg, true, nil
+	}
+	return b.bidAvg, b.bidAvg, true, nil
+}
+
+func (m tCEXMarketData) MidGap(baseID, quoteID uint32) uint64 {
+	if b := m[[2]uint32{baseID, quoteID}]; b != nil {
+		return b.midGap
+	}
+	return 0
+}
+
+func TestQuoteCEXConversion(t *testing.T) {
+	const dcr, btc, usdt uint32 = 42, 0, 60002
+	rate := func(r float64) uint64 { return uint64(math.Round(r * calc.RateEncodingFactor)) }
+	books := tCEXMarketData{
+		{dcr, btc}:  {midGap: rate(0.0003), bidAvg: rate(0.000297), askAvg: rate(0.000303)},
+		{btc, usdt}: {midGap: rate(60), bidAvg: rate(59.4), askAvg: rate(60.6)},
+	}
+	sellRoute := []*cexConversionLeg{
+		{baseID: dcr, quoteID: btc, sell: true},
+		{baseID: btc, quoteID: usdt, sell: true},
+	}
+	q, err := quoteCEXConversion(books, sellRoute, 1e8)
+	if err != nil {
+		t.Fatalf("quote error: %v", err)
+	}
+	// 1 DCR -> 0.000297 BTC -> 0.0176418 USDT at a 1.99% slippage.
+	if q.out != calc.BaseToQuote(rate(59.4), calc.BaseToQuote(rate(0.000297), 1e8)) {
+		t.Fatalf("wrong sell output %d", q.out)
+	}
+	if math.Abs(q.slippage-0.0199) > 1e-4 {
+		t.Fatalf("wrong sell slippage %f", q.slippage)
+	}
+
+	buyRoute := []*cexConversionLeg{
+		{baseID: btc, quoteID: usdt},
+		{baseID: dcr, quoteID: btc},
+	}
+	q, err = quoteCEXConversion(books, buyRoute, 1e8)
+	if err != nil {
+		t.Fatalf("quote error: %v", err)
+	}
+	if math.Abs(q.slippage-(1-1/(1.01*1.01))) > 1e-4 {
+		t.Fatalf("wrong buy slippage %f", q.slippage)
+	}
+
+	// Missing books can't be quoted.
+	delete(books, [2]uint32{btc, usdt})
+	if _, err := quoteCEXConversion(books, sellRoute, 1e8); err == nil {
+		t.Fatalf("no error for missing book")
+	}
+
+	// Leg orders are limited to the tolerance.
+	r, qty := cexConversionLegOrder(sellRoute[0], 1e8, rate(0.0003), 0.01)
+	if r != rate(0.000297) || qty != 1e8 {
+		t.Fatalf("wrong sell leg order %d @ %d", qty, r)
+	}
+	r, qty = cexConversionLegOrder(buyRoute[1], 1e6, rate(0.0003), 0.01)
+	if r != rate(0.000303) || qty != calc.QuoteToBase(r, 1e6) {
+		t.Fatalf("wrong buy leg order %d @ %d", qty, r)
+	}
+}
@@ -29,6 +29,10 @@ type CEXConfig struct {
 	APIKey string `json:"apiKey"`
 	// APISecret is the API secret for the CEX.
 	APISecret string `json:"apiSecret"`
+	// Endpoints are the names of the CEX's regional API endpoints to use, in
+	// order of preference. The next endpoint is used if the one in use is
+	// restricted from our location. If empty, all known endpoints are used.
+	Endpoints []string `json:"endpoints,omitempty"`
 }
 
 // AutoRebalanceConfig configures deposits and withdrawals by setting minimum
@@ -113,12 +117,47 @@ type BotConfig struct {
 		AutoRebalance *AutoRebalanceConfig  `json:"autoRebalance"`
 	} `json:"rpcConfig"`
 
+	// Jitter randomizes the bot's placements, so that its quotes are less
+	// predictable to competitors.
+	Jitter *PlacementJitter `json:"jitter,omitempty"`
+
+	// InventoryGroup is the name of a group of bots that share their DEX
+	// inventory. The DEX inventory of the assets that the running bots in a
+	// group have in common is periodically redistributed from the bots that
+	// have more than they need to fund their placements to the bots that
+	// have less, e.g. DCR bought by a DCR/BTC bot can be sold by a DCR/USDC
+	// bot.
+	InventoryGroup string `json:"inventoryGroup,omitempty"`
+
 	// Only one of the following configs should be set
 	BasicMMConfig        *BasicMarketMakingConfig `json:"basicMarketMakingConfig,omitempty"`
 	SimpleArbConfig      *SimpleArbConfig         `json:"simpleArbConfig,omitempty"`
 	ArbMarketMakerConfig *ArbMarketMakerConfig    `json:"arbMarketMakingConfig,omitempty"`
 }
 
+// PlacementJitter configures the randomization of a bot's placements.
+type PlacementJitter struct {
+	// RateBand is the max ratio of the rate that a placement's rate is moved
+	// away from the mid-gap, i.e. buys are placed at a random lower rate, and
+	// sells at a random higher rate. The band should be narrower than the
+	// drift tolerance, or orders will be replaced more often.
+	// 0 <= x <= 0.01.
+	RateBand float64 `json:"rateBand"`
+	// MaxDelay is the max ratio of the epoch duration that the submission of
+	// orders is delayed by a random amount of time. 0 <= x <= 0.5.
+	MaxDelay float64 `json:"maxDelay"`
+}
+
+func (j *PlacementJitter) validate() error {
+	if j.RateBand < 0 || j.RateBand > 0.01 {
+		return fmt.Errorf("jitter rate band %f out of bounds", j.RateBand)
+	}
+	if j.MaxDelay < 0 || j.MaxDelay > 0.5 {
+		return fmt.Errorf("jitter max delay %f out of bounds", j.MaxDelay)
+	}
+	return nil
+}
+
 func (c *BotConfig) requiresPriceOracle() bool {
 	return c.BasicMMConfig != nil
 }
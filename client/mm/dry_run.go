@@ -0,0 +1,137 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/client/mm/libxc"
+)
+
+// DryRunReport is the result of a dry run of a bot configuration.
+type DryRunReport struct {
+	BuyPlacements  []*TradePlacement `json:"buyPlacements"`
+	SellPlacements []*TradePlacement `json:"sellPlacements"`
+	FeeGap         *FeeGapStats      `json:"feeGap"`
+}
+
+// validateBotConfig validates the parts of the bot configuration that can be
+// checked without market data.
+func validateBotConfig(cfg *BotConfig) error {
+	if cfg.Jitter != nil {
+		if err := cfg.Jitter.validate(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case cfg.BasicMMConfig != nil:
+		if err := cfg.BasicMMConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid market making config: %w", err)
+		}
+	case cfg.SimpleArbConfig != nil:
+		if err := cfg.SimpleArbConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid arbitrage config: %w", err)
+		}
+	case cfg.ArbMarketMakerConfig == nil:
+		return fmt.Errorf("no bot config")
+	}
+	if cfg.requiresCEX() && cfg.CEXName == "" {
+		return fmt.Errorf("no CEX specified")
+	}
+	return nil
+}
+
+// DryRunBot validates a candidate bot configuration and calculates the
+// placements that the bot would make with the current market data, without
+// placing any orders. The placements are the bot's desired placements, before
+// they are limited by the bot's balances. Simple arbitrage bots don't have
+// standing placements, so only the fee gap is reported for them.
+func (m *MarketMaker) DryRunBot(cfg *BotConfig) (*DryRunReport, error) {
+	if err := validateBotConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	mwh := &MarketWithHost{cfg.Host, cfg.BaseID, cfg.QuoteID}
+	botID := "dryrun-" + dexMarketID(cfg.Host, cfg.BaseID, cfg.QuoteID)
+
+	var botCEX libxc.CEX
+	if cfg.CEXName != "" {
+		var cexCfg *CEXConfig
+		for _, c := range m.defaultConfig().CexConfigs {
+			if c.Name == cfg.CEXName {
+				cexCfg = c
+			}
+		}
+		if cexCfg == nil {
+			return nil, fmt.Errorf("no CEX config found for %s", cfg.CEXName)
+		}
+		cex, err := m.loadAndConnectCEX(m.ctx, cexCfg)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", cexCfg.Name, err)
+		}
+		botCEX = cex
+		if md, is := cex.CEX.(*libxc.MarketData); is {
+			client := md.Client(botID)
+			defer client.Close()
+			botCEX = client
+		}
+		if err := botCEX.SubscribeMarket(m.ctx, cfg.BaseID, cfg.QuoteID); err != nil {
+			return nil, fmt.Errorf("error subscribing to %s market: %w", cexCfg.Name, err)
+		}
+	}
+
+	if cfg.requiresPriceOracle() {
+		if err := m.oracle.startAutoSyncingMarket(cfg.BaseID, cfg.QuoteID); err != nil {
+			return nil, fmt.Errorf("error syncing oracle: %w", err)
+		}
+		defer m.oracle.stopAutoSyncingMarket(cfg.BaseID, cfg.QuoteID)
+	}
+
+	b, err := m.newBot(cfg, &exchangeAdaptorCfg{
+		botID:           botID,
+		mwh:             mwh,
+		baseDexBalances: make(map[uint32]uint64),
+		baseCexBalances: make(map[uint32]uint64),
+		core:            m.core,
+		cex:             botCEX,
+		log:             m.botSubLogger(cfg),
+		botCfg:          cfg,
+		eventLogDB:      m.eventLogDB,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := new(DryRunReport)
+	switch b := b.(type) {
+	case *basicMarketMaker:
+		b.calculator = &basicMMCalculatorImpl{
+			market: b.market,
+			oracle: b.oracle,
+			core:   b.core,
+			cfg:    b.cfg(),
+			log:    b.log,
+		}
+		report.BuyPlacements, report.SellPlacements, err = b.ordersToPlace()
+		if err != nil {
+			return nil, fmt.Errorf("error calculating placements: %w", err)
+		}
+		if feeGapI := b.runStats.feeGapStats.Load(); feeGapI != nil {
+			report.FeeGap = feeGapI.(*FeeGapStats)
+		}
+	case *arbMarketMaker:
+		report.BuyPlacements, report.SellPlacements, err = b.ordersToPlace()
+		if err != nil {
+			return nil, fmt.Errorf("error calculating placements: %w", err)
+		}
+		if report.FeeGap, err = feeGap(b.core, b.CEX, b.baseID, b.quoteID, b.lotSize); err != nil {
+			return nil, fmt.Errorf("error calculating fee gap: %w", err)
+		}
+	case *simpleArbMarketMaker:
+		if report.FeeGap, err = feeGap(b.core, b.CEX, b.baseID, b.quoteID, b.lotSize); err != nil {
+			return nil, fmt.Errorf("error calculating fee gap: %w", err)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,51 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import "testing"
+
+func TestValidateBotConfig(t *testing.T) {
+	basicCfg := func() *BasicMarketMakingConfig {
+		return &BasicMarketMakingConfig{
+			GapStrategy:   GapStrategyPercent,
+			BuyPlacements: []*OrderPlacement{{Lots: 1, GapFactor: 0.01}},
+		}
+	}
+	tests := []struct {
+		name    string
+		cfg     *BotConfig
+		wantErr bool
+	}{{
+		name: "basic ok",
+		cfg:  &BotConfig{BasicMMConfig: basicCfg()},
+	}, {
+		name: "basic invalid",
+		cfg: &BotConfig{BasicMMConfig: &BasicMarketMakingConfig{
+			GapStrategy:   GapStrategyPercent,
+			BuyPlacements: []*OrderPlacement{{Lots: 1, GapFactor: 0.5}},
+		}},
+		wantErr: true,
+	}, {
+		name:    "bad jitter",
+		cfg:     &BotConfig{BasicMMConfig: basicCfg(), Jitter: &PlacementJitter{MaxDelay: 1}},
+		wantErr: true,
+	}, {
+		name:    "arb mm without cex",
+		cfg:     &BotConfig{ArbMarketMakerConfig: &ArbMarketMakerConfig{}},
+		wantErr: true,
+	}, {
+		name: "arb mm ok",
+		cfg:  &BotConfig{ArbMarketMakerConfig: &ArbMarketMakerConfig{}, CEXName: "Binance"},
+	}, {
+		name:    "no bot config",
+		cfg:     &BotConfig{},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		err := validateBotConfig(tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wantErr = %t, err = %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
@@ -92,8 +92,11 @@ type MarketMakingRunOverview struct {
 	EndTime         *int64            `json:"endTime,omitempty"`
 	Cfgs            []*CfgUpdate      `json:"cfgs"`
 	InitialBalances map[uint32]uint64 `json:"initialBalances"`
-	ProfitLoss      *ProfitLoss       `json:"profitLoss"`
-	FinalState      *BalanceState     `json:"finalState"`
+	// InitialFiatRates are the fiat rates at the start of the run. They are
+	// not available for runs started by older versions.
+	InitialFiatRates map[uint32]float64 `json:"initialFiatRates,omitempty"`
+	ProfitLoss       *ProfitLoss        `json:"profitLoss"`
+	FinalState       *BalanceState      `json:"finalState"`
 }
 
 // eventLogDB is the interface for the event log database.
@@ -159,11 +162,12 @@ var (
 	eventsBucket  = []byte("events")
 	cfgsBucket    = []byte("cfgs")
 
-	startTimeKey   = []byte("startTime")
-	endTimeKey     = []byte("endTime")
-	initialBalsKey = []byte("ib")
-	finalStateKey  = []byte("fs")
-	noPendingKey   = []byte("np")
+	startTimeKey    = []byte("startTime")
+	endTimeKey      = []byte("endTime")
+	initialBalsKey  = []byte("ib")
+	initialRatesKey = []byte("ir")
+	finalStateKey   = []byte("fs")
+	noPendingKey    = []byte("np")
 )
 
 const balanceStateDBVersion uint32 = 1
@@ -464,6 +468,12 @@ func (db *boltEventLogDB) storeNewRun(startTime int64, mkt *MarketWithHost, cfg
 		}
 		runBucket.Put(initialBalsKey, versionedBytes(0).AddData(initialBalsB))
 
+		initialRatesB, err := json.Marshal(initialState.FiatRates)
+		if err != nil {
+			return err
+		}
+		runBucket.Put(initialRatesKey, versionedBytes(0).AddData(initialRatesB))
+
 		fsB, err := json.Marshal(initialState)
 		if err != nil {
 			return err
@@ -577,6 +587,23 @@ func (db *boltEventLogDB) runOverview(startTime int64, mkt *MarketWithHost) (*Ma
 			return err
 		}
 
+		var initialRates map[uint32]float64
+		if initialRatesB := runBucket.Get(initialRatesKey); initialRatesB != nil {
+			ver, pushes, err := encode.DecodeBlob(initialRatesB)
+			if err != nil {
+				return err
+			}
+			if ver != 0 {
+				return fmt.Errorf("unknown initial rates version %d", ver)
+			}
+			if len(pushes) != 1 {
+				return fmt.Errorf("expected 1 push for initial rates, got %d", len(pushes))
+			}
+			if err := json.Unmarshal(pushes[0], &initialRates); err != nil {
+				return err
+			}
+		}
+
 		finalStateB := runBucket.Get(finalStateKey)
 		if finalStateB == nil {
 			return fmt.Errorf("no final state found")
@@ -605,11 +632,12 @@ func (db *boltEventLogDB) runOverview(startTime int64, mkt *MarketWithHost) (*Ma
 		}
 
 		overview = &MarketMakingRunOverview{
-			EndTime:         endTime,
-			Cfgs:            cfgs,
-			InitialBalances: initialBals,
-			ProfitLoss:      newProfitLoss(initialBals, finalBals, finalState.InventoryMods, finalState.FiatRates),
-			FinalState:      finalState,
+			EndTime:          endTime,
+			Cfgs:             cfgs,
+			InitialBalances:  initialBals,
+			InitialFiatRates: initialRates,
+			ProfitLoss:       newProfitLoss(initialBals, finalBals, finalState.InventoryMods, finalState.FiatRates),
+			FinalState:       finalState,
 		}
 
 		return nil
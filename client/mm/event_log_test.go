@@ -321,6 +321,10 @@ func TestEventLogDB(t *testing.T) {
 	if !reflect.DeepEqual(overview.InitialBalances, initialBals) {
 		t.Fatalf("expected initial balances %v, got %v", initialBals, overview.InitialBalances)
 	}
+	initialRates := map[uint32]float64{42: 20, 60: 2500}
+	if !reflect.DeepEqual(overview.InitialFiatRates, initialRates) {
+		t.Fatalf("expected initial fiat rates %v, got %v", initialRates, overview.InitialFiatRates)
+	}
 	expPL := newProfitLoss(initialBals, finalBals, nil, fiatRates)
 	if overview.ProfitLoss.Profit != expPL.Profit {
 		t.Fatalf("expected profit loss %v, got %v", expPL, overview.ProfitLoss)
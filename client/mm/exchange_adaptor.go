@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"sort"
 	"strconv"
 	"strings"
@@ -266,6 +267,9 @@ type pendingDEXOrder struct {
 	// which orders to place/cancel.
 	placementIndex   uint64
 	counterTradeRate uint64
+	// filled is the filled quantity of the order when it was last updated.
+	// txsMtx must be locked.
+	filled uint64
 }
 
 func (p *pendingDEXOrder) cexBalanceEffects() *BalanceEffects {
@@ -304,6 +308,7 @@ type market struct {
 	name        string
 	rateStep    uint64
 	lotSize     uint64
+	epochLen    uint64
 	baseID      uint32
 	baseTicker  string
 	bui         dex.UnitInfo
@@ -348,6 +353,7 @@ func parseMarket(host string, mkt *core.Market) (*market, error) {
 		name:        mkt.Name,
 		rateStep:    mkt.RateStep,
 		lotSize:     mkt.LotSize,
+		epochLen:    mkt.EpochLen,
 		baseID:      mkt.BaseID,
 		baseTicker:  bui.Conventional.Unit,
 		bui:         bui,
@@ -422,6 +428,9 @@ type unifiedExchangeAdaptor struct {
 	botLooper dex.Connector
 	botLoop   *dex.ConnectionMaster
 	paused    atomic.Bool
+	// windingDown is set when the bot is winding down, and no more orders
+	// or transfers are made.
+	windingDown atomic.Bool
 
 	autoRebalanceCfg *AutoRebalanceConfig
 
@@ -470,8 +479,19 @@ type unifiedExchangeAdaptor struct {
 
 	epochReport atomic.Value // *EpochReport
 
+	quoteQualityMtx sync.Mutex
+	// epochFills are the filled quantities by placement index since the last
+	// epoch report. [0] is buys and [1] is sells.
+	epochFills   [2]map[uint64]uint64
+	quoteQuality *QuoteQualityStats
+
 	cexProblemsMtx sync.RWMutex
 	cexProblems    *CEXProblems
+
+	// cexConversions are the conversions of CEX inventory that are waiting
+	// for a trade to complete, keyed by the trade ID.
+	cexConversionsMtx sync.Mutex
+	cexConversions    map[string]*cexConversion
 }
 
 var _ botCoreAdaptor = (*unifiedExchangeAdaptor)(nil)
@@ -675,10 +695,10 @@ func (u *unifiedExchangeAdaptor) SufficientBalanceForCEXTrade(baseID, quoteID ui
 	var fromAssetID uint32
 	var fromAssetQty uint64
 	if sell {
-		fromAssetID = u.baseID
+		fromAssetID = baseID
 		fromAssetQty = qty
 	} else {
-		fromAssetID = u.quoteID
+		fromAssetID = quoteID
 		fromAssetQty = calc.BaseToQuote(rate, qty)
 	}
 
@@ -1035,6 +1055,13 @@ type TradePlacement struct {
 	UsedDEX          map[uint32]uint64 `json:"usedDex"`
 	UsedCEX          uint64            `json:"usedCex"`
 	Error            *BotProblems      `json:"error"`
+	// ScaledFromLots is the configured number of lots for the placement, if
+	// Lots was scaled down because the opposing side of the book is too thin
+	// to exit the position.
+	ScaledFromLots uint64 `json:"scaledFromLots,omitempty"`
+	// Unprofitable is true if the placement was skipped because its rate
+	// doesn't clear the break-even fees plus the bot's profit margin.
+	Unprofitable bool `json:"unprofitable,omitempty"`
 }
 
 // setError sets the error field of the TradePlacement and updates the fields
@@ -1092,6 +1119,8 @@ func newOrderReport(placements []*TradePlacement) *OrderReport {
 			Rate:             p.Rate,
 			Lots:             p.Lots,
 			CounterTradeRate: p.CounterTradeRate,
+			ScaledFromLots:   p.ScaledFromLots,
+			Unprofitable:     p.Unprofitable,
 			RequiredDEX:      make(map[uint32]uint64),
 			UsedDEX:          make(map[uint32]uint64),
 		}
@@ -1140,7 +1169,7 @@ func (u *unifiedExchangeAdaptor) multiTrade(
 	currEpoch uint64,
 ) (_ map[order.OrderID]*dexOrderInfo, or *OrderReport) {
 	or = newOrderReport(placements)
-	if len(placements) == 0 {
+	if len(placements) == 0 || u.windingDown.Load() {
 		return nil, or
 	}
 
@@ -1255,6 +1284,17 @@ func (u *unifiedExchangeAdaptor) multiTrade(
 
 	orderInfos := make([]*dexOrderInfo, 0, len(or.Placements))
 
+	// Jitter the rates of the placements that need new orders before their
+	// funding is calculated, so the report has the rates that are placed. The
+	// standing orders were checked against the requested rates above.
+	if jitter := u.botCfg().Jitter; jitter != nil && jitter.RateBand > 0 {
+		for _, placement := range or.Placements {
+			if placement.requiredLots() > 0 {
+				placement.Rate = u.jitteredRate(placement.Rate, sell, jitter.RateBand)
+			}
+		}
+	}
+
 	// Calculate required balances for each placement and the total required.
 	placementRequired := false
 	for _, placement := range or.Placements {
@@ -1358,8 +1398,58 @@ func (u *unifiedExchangeAdaptor) multiTrade(
 	return nil, or
 }
 
+// jitteredRate moves the rate away from the mid-gap by a random amount up to
+// band, in multiples of the rate step.
+func (u *unifiedExchangeAdaptor) jitteredRate(rate uint64, sell bool, band float64) uint64 {
+	steps := uint64(float64(rate) * band / float64(u.rateStep))
+	if steps == 0 {
+		return rate
+	}
+	adj := uint64(rand.Int63n(int64(steps)+1)) * u.rateStep
+	if sell {
+		return rate + adj
+	}
+	if adj >= rate {
+		return rate
+	}
+	return rate - adj
+}
+
+// waitJitterDelay waits a random delay if the bot is configured for timing
+// jitter. Bots call this once per epoch, before canceling and placing orders
+// on both sides of the book, so that the bot is never off the book while
+// waiting. The returned bool is false if the bot is stopped while waiting.
+func (u *unifiedExchangeAdaptor) waitJitterDelay() bool {
+	jitter := u.botCfg().Jitter
+	if jitter == nil {
+		return true
+	}
+	delay := u.jitterDelay(jitter.MaxDelay)
+	if delay == 0 {
+		return true
+	}
+	select {
+	case <-time.After(delay):
+		return true
+	case <-u.ctx.Done():
+		return false
+	}
+}
+
+// jitterDelay is a random delay up to maxDelay of the epoch duration.
+func (u *unifiedExchangeAdaptor) jitterDelay(maxDelay float64) time.Duration {
+	maxMs := int64(float64(u.epochLen) * maxDelay)
+	if maxMs <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(maxMs+1)) * time.Millisecond
+}
+
 // DEXTrade places a single order on the DEX order book.
 func (u *unifiedExchangeAdaptor) DEXTrade(rate, qty uint64, sell bool) (*core.Order, error) {
+	if u.windingDown.Load() {
+		return nil, errors.New("bot is winding down")
+	}
 	enough, err := u.SufficientBalanceForDEXTrade(rate, qty, sell)
 	if err != nil {
 		return nil, err
@@ -2114,6 +2204,10 @@ func (u *unifiedExchangeAdaptor) handleCEXTradeUpdate(trade *libxc.Trade) {
 		return
 	}
 
+	// A conversion of CEX inventory continues with its next trade once this
+	// trade's balance effects are applied.
+	defer func() { go u.continueCEXConversion(trade) }()
+
 	u.balancesMtx.Lock()
 	defer u.balancesMtx.Unlock()
 
@@ -2707,6 +2801,11 @@ func (u *unifiedExchangeAdaptor) handleDEXOrderUpdate(o *core.Order) {
 
 	pendingOrder.txsMtx.Lock()
 	pendingOrder.updateState(o, u.clientCore.WalletTransaction, u.baseTraits, u.quoteTraits)
+	var newlyFilled uint64
+	if o.Filled > pendingOrder.filled {
+		newlyFilled = o.Filled - pendingOrder.filled
+		pendingOrder.filled = o.Filled
+	}
 	dexEffects := pendingOrder.currentState().dexBalanceEffects
 	var havePending bool
 	for _, v := range dexEffects.Pending {
@@ -2717,6 +2816,10 @@ func (u *unifiedExchangeAdaptor) handleDEXOrderUpdate(o *core.Order) {
 	}
 	pendingOrder.txsMtx.Unlock()
 
+	if newlyFilled > 0 {
+		u.recordFill(o.Sell, pendingOrder.placementIndex, newlyFilled)
+	}
+
 	orderUpdates := u.orderUpdates.Load()
 	if orderUpdates != nil {
 		orderUpdates.(chan *core.Order) <- o
@@ -3021,6 +3124,9 @@ func (u *unifiedExchangeAdaptor) optimizeTransfers(dist *distribution, dexSellLo
 
 // transfer attempts to perform the transers specified in the distribution.
 func (u *unifiedExchangeAdaptor) transfer(dist *distribution, currEpoch uint64) (actionTaken bool, err error) {
+	if u.windingDown.Load() {
+		return false, nil
+	}
 	baseInv, quoteInv := dist.baseInv, dist.quoteInv
 	if baseInv.toDeposit+baseInv.toWithdraw+quoteInv.toDeposit+quoteInv.toWithdraw == 0 {
 		return false, nil
@@ -3400,6 +3506,7 @@ type RunStats struct {
 	CompletedMatches   uint32                 `json:"completedMatches"`
 	TradedUSD          float64                `json:"tradedUSD"`
 	FeeGap             *FeeGapStats           `json:"feeGap"`
+	QuoteQuality       *QuoteQualityStats     `json:"quoteQuality"`
 }
 
 // Amount contains the conversions and formatted strings associated with an
@@ -3529,6 +3636,7 @@ func (u *unifiedExchangeAdaptor) stats() *RunStats {
 		CompletedMatches:   u.runStats.completedMatches.Load(),
 		TradedUSD:          tradedUSD,
 		FeeGap:             feeGap,
+		QuoteQuality:       u.quoteQualityStats(),
 	}
 }
 
@@ -3618,6 +3726,7 @@ func (u *unifiedExchangeAdaptor) latestEpoch() *EpochReport {
 }
 
 func (u *unifiedExchangeAdaptor) updateEpochReport(report *EpochReport) {
+	report.Fills = u.epochFillStats(report)
 	u.epochReport.Store(report)
 	u.clientCore.Broadcast(newEpochReportNote(u.host, u.baseID, u.quoteID, report))
 }
@@ -3833,6 +3942,7 @@ func newUnifiedExchangeAdaptor(cfg *exchangeAdaptorCfg) (*unifiedExchangeAdaptor
 		mwh:                cfg.mwh,
 		inventoryMods:      make(map[uint32]int64),
 		cexProblems:        newCEXProblems(),
+		cexConversions:     make(map[string]*cexConversion),
 	}
 
 	adaptor.fiatRates.Store(map[uint32]float64{})
@@ -4961,6 +4961,104 @@ func TestCalcProfitLoss(t *testing.T) {
 	}
 }
 
+func TestPlacementJitter(t *testing.T) {
+	u := &unifiedExchangeAdaptor{market: &market{rateStep: 1e3, epochLen: 20000}}
+	const rate uint64 = 5e6
+	const band = 0.01 // 50 rate steps
+	var moved bool
+	for i := 0; i < 100; i++ {
+		r := u.jitteredRate(rate, true, band)
+		if r < rate || r > rate+5e4 || r%u.rateStep != 0 {
+			t.Fatalf("sell rate %d out of jitter band", r)
+		}
+		moved = moved || r != rate
+		r = u.jitteredRate(rate, false, band)
+		if r > rate || r < rate-5e4 || r%u.rateStep != 0 {
+			t.Fatalf("buy rate %d out of jitter band", r)
+		}
+		if d := u.jitterDelay(0.5); d < 0 || d > 10*time.Second {
+			t.Fatalf("delay %s out of range", d)
+		}
+	}
+	if !moved {
+		t.Fatalf("jitter never moved the rate")
+	}
+	// A band narrower than the rate step does nothing.
+	if r := u.jitteredRate(rate, true, 1e-4); r != rate {
+		t.Fatalf("rate jittered within a single rate step")
+	}
+	if d := u.jitterDelay(0); d != 0 {
+		t.Fatalf("delay with no max delay")
+	}
+
+	// multiTrade funds and reports the jittered rates that are placed.
+	a := mustParseAdaptorFromMarket(&core.Market{
+		RateStep:   1e3,
+		AtomToConv: 1,
+		LotSize:    1e8,
+		BaseID:     42,
+		QuoteID:    0,
+	})
+	a.botCfgV.Store(&BotConfig{Jitter: &PlacementJitter{RateBand: band}})
+	fees := &OrderFees{LotFeeRange: &LotFeeRange{Max: &LotFees{}, Estimated: &LotFees{}}}
+	a.buyFees, a.sellFees = fees, fees
+	a.baseDexBalances[0] = 1e8
+	a.fiatRates.Store(map[uint32]float64{42: 1, 0: 1})
+	placements := []*TradePlacement{{Rate: rate, Lots: 1}}
+	_, or := a.multiTrade(placements, false, 0.01, 100)
+	tc := a.clientCore.(*tCore)
+	if len(tc.multiTradesPlaced) != 1 {
+		t.Fatalf("expected 1 order, got %d", len(tc.multiTradesPlaced))
+	}
+	placed := tc.multiTradesPlaced[0].Placements[0]
+	if placed.Rate > rate || placed.Rate < rate-5e4 {
+		t.Fatalf("placed rate %d out of jitter band", placed.Rate)
+	}
+	if or.Placements[0].Rate != placed.Rate {
+		t.Fatalf("reported rate %d, placed rate %d", or.Placements[0].Rate, placed.Rate)
+	}
+	if used := or.Placements[0].UsedDEX[0]; used != calc.BaseToQuote(placed.Rate, 1e8) {
+		t.Fatalf("placement funded at the wrong rate: used %d", used)
+	}
+	if placements[0].Rate != rate {
+		t.Fatalf("caller's placement modified")
+	}
+}
+
+func TestWindDownSettled(t *testing.T) {
+	u := &unifiedExchangeAdaptor{
+		pendingDEXOrders:   make(map[order.OrderID]*pendingDEXOrder),
+		pendingCEXOrders:   make(map[string]*pendingCEXOrder),
+		pendingDeposits:    make(map[string]*pendingDeposit),
+		pendingWithdrawals: make(map[string]*pendingWithdrawal),
+	}
+	if !u.settled() {
+		t.Fatalf("adaptor with no pending orders or transfers not settled")
+	}
+	u.pendingDeposits["abc"] = &pendingDeposit{}
+	if u.settled() {
+		t.Fatalf("adaptor with a pending deposit settled")
+	}
+	delete(u.pendingDeposits, "abc")
+	u.pendingDEXOrders[order.OrderID{0x01}] = &pendingDEXOrder{}
+	if u.settled() {
+		t.Fatalf("adaptor with a pending DEX order settled")
+	}
+
+	// No orders are placed while winding down.
+	u.windingDown.Store(true)
+	if _, err := u.DEXTrade(1e8, 1e8, true); err == nil {
+		t.Fatalf("no error placing a trade while winding down")
+	}
+	placed, or := u.multiTrade([]*TradePlacement{{Lots: 1, Rate: 1e8}}, true, 0.01, 100)
+	if len(placed) != 0 || or == nil || len(or.Placements) != 1 {
+		t.Fatalf("unexpected multiTrade result while winding down")
+	}
+	if acted, err := u.transfer(&distribution{}, 100); acted || err != nil {
+		t.Fatalf("transfer made while winding down")
+	}
+}
+
 func TestRefreshPendingEvents(t *testing.T) {
 	tCore := newTCore()
 	tCEX := newTCEX()
@@ -0,0 +1,253 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"sort"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+const (
+	// inventoryShareInterval is how often the DEX inventory of the bots in
+	// an inventory group is redistributed.
+	inventoryShareInterval = time.Minute
+	// minInventoryShareRatio is the min ratio of a bot's deficit of an asset
+	// to its demand for the asset before inventory is moved to the bot from
+	// the other bots in its group.
+	minInventoryShareRatio = 0.05
+)
+
+// InventoryGroupStatus is the combined state of the running bots in an
+// inventory group.
+type InventoryGroupStatus struct {
+	Markets []*MarketWithHost `json:"markets"`
+	// DEX is the bots' combined DEX balances.
+	DEX map[uint32]*BotBalance `json:"dex"`
+	// Profit is the bots' combined profit in USD.
+	Profit float64 `json:"profit"`
+}
+
+// inventoryDemand returns the DEX balances that the bot requires to fund all
+// of its placements. The quote asset is valued at the fiat exchange rate. nil
+// is returned if the demand cannot be calculated.
+func (u *unifiedExchangeAdaptor) inventoryDemand() map[uint32]uint64 {
+	buyFees, sellFees, err := u.orderFees()
+	if err != nil {
+		u.log.Errorf("Error getting fees for inventory demand: %v", err)
+		return nil
+	}
+	fiatRates := u.fiatRates.Load().(map[uint32]float64)
+	baseRate, quoteRate := fiatRates[u.baseID], fiatRates[u.quoteID]
+	if baseRate == 0 || quoteRate == 0 {
+		return nil
+	}
+	rate := u.msgRate(baseRate / quoteRate)
+	return u.recommendedAllocation(u.botCfg(), rate, buyFees, sellFees).Alloc.DEX
+}
+
+// shareInventory moves DEX inventory of an asset into or out of the bot's
+// allocation, and returns the applied diff. Inventory that is moved out of the
+// bot's allocation is limited to the bot's available balance.
+func (u *unifiedExchangeAdaptor) shareInventory(assetID uint32, diff int64) int64 {
+	mods := u.applyInventoryDiffs(&BotInventoryDiffs{DEX: map[uint32]int64{assetID: diff}})
+	u.updateInventoryEvent(mods)
+	return mods[assetID]
+}
+
+// inventoryShares calculates the diffs that move an asset from the bots that
+// have more than they demand to the bots that have less. If the bots' surplus
+// cannot cover all of the deficits, the surplus is split in proportion to the
+// deficits. Deficits below minInventoryShareRatio of the demand are ignored.
+// The diffs sum to zero.
+func inventoryShares(avail, demand []uint64) []int64 {
+	surplus := func(i int) uint64 {
+		if avail[i] > demand[i] {
+			return avail[i] - demand[i]
+		}
+		return 0
+	}
+	deficit := func(i int) uint64 {
+		if avail[i] >= demand[i] {
+			return 0
+		}
+		d := demand[i] - avail[i]
+		if float64(d) < float64(demand[i])*minInventoryShareRatio {
+			return 0
+		}
+		return d
+	}
+
+	diffs := make([]int64, len(avail))
+	var totalSurplus, totalDeficit uint64
+	for i := range avail {
+		totalSurplus += surplus(i)
+		totalDeficit += deficit(i)
+	}
+	if totalSurplus == 0 || totalDeficit == 0 {
+		return diffs
+	}
+	moved := totalDeficit
+	if totalSurplus < moved {
+		moved = totalSurplus
+	}
+
+	var given, received uint64
+	for i := range avail {
+		if s := surplus(i); s > 0 {
+			give := uint64(float64(s) / float64(totalSurplus) * float64(moved))
+			diffs[i] = -int64(give)
+			given += give
+		}
+	}
+	lastReceiver := -1
+	for i := range avail {
+		if d := deficit(i); d > 0 {
+			receive := uint64(float64(d) / float64(totalDeficit) * float64(given))
+			diffs[i] = int64(receive)
+			received += receive
+			lastReceiver = i
+		}
+	}
+	// Rounding leftovers go to the last receiver.
+	if lastReceiver >= 0 {
+		diffs[lastReceiver] += int64(given - received)
+	}
+	return diffs
+}
+
+// inventoryGroups returns the running bots in each inventory group, sorted by
+// market for a deterministic order.
+func inventoryGroups(runningBots map[MarketWithHost]*runningBot) map[string][]*runningBot {
+	groups := make(map[string][]*runningBot)
+	for _, rb := range runningBots {
+		if group := rb.botCfg().InventoryGroup; group != "" {
+			groups[group] = append(groups[group], rb)
+		}
+	}
+	for _, bots := range groups {
+		sort.Slice(bots, func(i, j int) bool {
+			return dexMarketID(bots[i].botCfg().Host, bots[i].botCfg().BaseID, bots[i].botCfg().QuoteID) <
+				dexMarketID(bots[j].botCfg().Host, bots[j].botCfg().BaseID, bots[j].botCfg().QuoteID)
+		})
+	}
+	return groups
+}
+
+// shareGroupInventory redistributes the DEX inventory of the assets that are
+// shared by the bots in an inventory group, so that inventory acquired on one
+// market can be used by the bots on the other markets. Bots that are winding
+// down are not included.
+func (m *MarketMaker) shareGroupInventory(group string, groupBots []*runningBot) {
+	bots := make([]*runningBot, 0, len(groupBots))
+	demands := make([]map[uint32]uint64, 0, len(groupBots))
+	assetBots := make(map[uint32]int)
+	for _, rb := range groupBots {
+		if rb.windingDown.Load() {
+			continue
+		}
+		demand := rb.inventoryDemand()
+		if demand == nil {
+			continue
+		}
+		bots = append(bots, rb)
+		demands = append(demands, demand)
+		for assetID := range rb.assets() {
+			assetBots[assetID]++
+		}
+	}
+
+	for assetID, n := range assetBots {
+		if n < 2 {
+			continue
+		}
+		var sharing []*runningBot
+		var avail, demand []uint64
+		for i, rb := range bots {
+			if _, found := rb.assets()[assetID]; !found {
+				continue
+			}
+			sharing = append(sharing, rb)
+			avail = append(avail, rb.DEXBalance(assetID).Available)
+			demand = append(demand, demands[i][assetID])
+		}
+
+		diffs := inventoryShares(avail, demand)
+		// Inventory is moved out of the allocations first, in case the
+		// available balances changed, so that no more is moved in than was
+		// moved out.
+		var moved int64
+		for i, rb := range sharing {
+			if diffs[i] < 0 {
+				moved -= rb.shareInventory(assetID, diffs[i])
+			}
+		}
+		if moved == 0 {
+			continue
+		}
+		for i, rb := range sharing {
+			if diffs[i] <= 0 || moved == 0 {
+				continue
+			}
+			diff := diffs[i]
+			if diff > moved {
+				diff = moved
+			}
+			moved -= rb.shareInventory(assetID, diff)
+		}
+		m.log.Debugf("Shared %s inventory between the bots in inventory group %q", dex.BipIDSymbol(assetID), group)
+	}
+}
+
+// runInventorySharing periodically redistributes the DEX inventory of the
+// bots in each inventory group.
+func (m *MarketMaker) runInventorySharing() {
+	ticker := time.NewTicker(inventoryShareInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for group, bots := range inventoryGroups(m.runningBotsLookup()) {
+				m.shareGroupInventory(group, bots)
+			}
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// inventoryGroupsStatus returns the combined state of the running bots in
+// each inventory group.
+func inventoryGroupsStatus(runningBots map[MarketWithHost]*runningBot) map[string]*InventoryGroupStatus {
+	groups := inventoryGroups(runningBots)
+	if len(groups) == 0 {
+		return nil
+	}
+	statuses := make(map[string]*InventoryGroupStatus, len(groups))
+	for group, bots := range groups {
+		s := &InventoryGroupStatus{DEX: make(map[uint32]*BotBalance)}
+		for _, rb := range bots {
+			cfg := rb.botCfg()
+			s.Markets = append(s.Markets, &MarketWithHost{cfg.Host, cfg.BaseID, cfg.QuoteID})
+			for assetID := range rb.assets() {
+				bal := rb.DEXBalance(assetID)
+				total, found := s.DEX[assetID]
+				if !found {
+					total = new(BotBalance)
+					s.DEX[assetID] = total
+				}
+				total.Available += bal.Available
+				total.Locked += bal.Locked
+				total.Pending += bal.Pending
+				total.Reserved += bal.Reserved
+			}
+			if stats := rb.stats(); stats != nil && stats.ProfitLoss != nil {
+				s.Profit += stats.ProfitLoss.Profit
+			}
+		}
+		statuses[group] = s
+	}
+	return statuses
+}
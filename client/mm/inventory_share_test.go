@@ -0,0 +1,64 @@
+package mm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInventoryShares(t *testing.T) {
+	tests := []struct {
+		name   string
+		avail  []uint64
+		demand []uint64
+		want   []int64
+	}{{
+		name:   "no deficit",
+		avail:  []uint64{100, 200},
+		demand: []uint64{100, 150},
+		want:   []int64{0, 0},
+	}, {
+		name:   "no surplus",
+		avail:  []uint64{50, 100},
+		demand: []uint64{100, 150},
+		want:   []int64{0, 0},
+	}, {
+		name:   "surplus covers deficit",
+		avail:  []uint64{300, 0},
+		demand: []uint64{100, 100},
+		want:   []int64{-100, 100},
+	}, {
+		name:   "surplus split in proportion to deficits",
+		avail:  []uint64{0, 160, 0},
+		demand: []uint64{100, 100, 200},
+		want:   []int64{20, -60, 40},
+	}, {
+		name:   "surplus taken in proportion to surpluses",
+		avail:  []uint64{300, 200, 0},
+		demand: []uint64{100, 100, 150},
+		want:   []int64{-100, -50, 150},
+	}, {
+		name:   "small deficit ignored",
+		avail:  []uint64{300, 98},
+		demand: []uint64{100, 100},
+		want:   []int64{0, 0},
+	}, {
+		name:   "rounding",
+		avail:  []uint64{10, 0, 0, 0},
+		demand: []uint64{0, 10, 10, 10},
+		want:   []int64{-10, 3, 3, 4},
+	}}
+
+	for _, tt := range tests {
+		diffs := inventoryShares(tt.avail, tt.demand)
+		if !reflect.DeepEqual(diffs, tt.want) {
+			t.Fatalf("%s: wanted diffs %v, got %v", tt.name, tt.want, diffs)
+		}
+		var sum int64
+		for _, d := range diffs {
+			sum += d
+		}
+		if sum != 0 {
+			t.Fatalf("%s: diffs sum to %d", tt.name, sum)
+		}
+	}
+}
@@ -52,6 +52,77 @@ const (
 	fakeBinanceWsURL = "ws://localhost:37346"
 )
 
+// binanceEndpoint is a set of Binance API URLs.
+type binanceEndpoint struct {
+	name        string
+	marketsURL  string
+	accountsURL string
+	wsURL       string
+}
+
+// binanceEndpoints are the mainnet API endpoints for Binance, in the order
+// they are used when none are configured. The first is the primary endpoint.
+// The others are served from different regions and can remain available when
+// the primary endpoint is restricted.
+var binanceEndpoints = []*binanceEndpoint{
+	{"global", httpURL, httpURL, websocketURL},
+	{"gcp", "https://api-gcp.binance.com", "https://api-gcp.binance.com", websocketURL},
+	{"api1", "https://api1.binance.com", "https://api1.binance.com", "wss://stream.binance.com:443"},
+	{"api2", "https://api2.binance.com", "https://api2.binance.com", "wss://stream.binance.com:443"},
+	{"api3", "https://api3.binance.com", "https://api3.binance.com", "wss://stream.binance.com:443"},
+	{"api4", "https://api4.binance.com", "https://api4.binance.com", "wss://stream.binance.com:443"},
+}
+
+// binanceUSEndpoints are the mainnet API endpoints for Binance.US.
+var binanceUSEndpoints = []*binanceEndpoint{
+	{"us", usHttpURL, usHttpURL, usWebsocketURL},
+}
+
+// BinanceEndpoints lists the names of the mainnet API endpoints that can be
+// configured for Binance or Binance.US with CEXConfig.Endpoints.
+func BinanceEndpoints(binanceUS bool) []string {
+	endpoints := binanceEndpoints
+	if binanceUS {
+		endpoints = binanceUSEndpoints
+	}
+	names := make([]string, 0, len(endpoints))
+	for _, ep := range endpoints {
+		names = append(names, ep.name)
+	}
+	return names
+}
+
+// selectBinanceEndpoints returns the endpoints with the configured names, in
+// the configured order. All known endpoints are returned if none are
+// configured.
+func selectBinanceEndpoints(names []string, binanceUS bool) ([]*binanceEndpoint, error) {
+	known := binanceEndpoints
+	if binanceUS {
+		known = binanceUSEndpoints
+	}
+	if len(names) == 0 {
+		return known, nil
+	}
+	endpoints := make([]*binanceEndpoint, 0, len(names))
+	have := make(map[string]bool, len(names))
+out:
+	for _, name := range names {
+		if have[name] {
+			continue
+		}
+		for _, ep := range known {
+			if ep.name == name {
+				have[name] = true
+				endpoints = append(endpoints, ep)
+				continue out
+			}
+		}
+		return nil, fmt.Errorf("unknown endpoint %q. known endpoints: %s", name,
+			strings.Join(BinanceEndpoints(binanceUS), ", "))
+	}
+	return endpoints, nil
+}
+
 // binanceOrderBook manages an orderbook for a single market. It keeps
 // the orderbook synced and allows querying of vwap.
 type binanceOrderBook struct {
@@ -454,10 +525,12 @@ type withdrawInfo struct {
 }
 
 type binance struct {
-	log                dex.Logger
-	marketsURL         string
-	accountsURL        string
-	wsURL              string
+	log dex.Logger
+	// endpoints are the API endpoints in order of preference. endpointIdx is
+	// the index of the endpoint in use. It is advanced when the endpoint in use
+	// responds that the service is unavailable from our location.
+	endpoints          []*binanceEndpoint
+	endpointIdx        atomic.Uint32
 	apiKey             string
 	secretKey          string
 	knownAssets        map[uint32]bool
@@ -506,19 +579,18 @@ var _ CEX = (*binance)(nil)
 // TODO: Investigate stablecoin auto-conversion.
 // https://developers.binance.com/docs/wallet/endpoints/switch-busd-stable-coins-convertion
 
-func newBinance(cfg *CEXConfig, binanceUS bool) *binance {
-	var marketsURL, accountsURL, wsURL string
+func newBinance(cfg *CEXConfig, binanceUS bool) (*binance, error) {
+	var endpoints []*binanceEndpoint
 
 	switch cfg.Net {
 	case dex.Testnet:
-		marketsURL, accountsURL, wsURL = testnetHttpURL, fakeBinanceURL, testnetWebsocketURL
+		endpoints = []*binanceEndpoint{{"testnet", testnetHttpURL, fakeBinanceURL, testnetWebsocketURL}}
 	case dex.Simnet:
-		marketsURL, accountsURL, wsURL = fakeBinanceURL, fakeBinanceURL, fakeBinanceWsURL
+		endpoints = []*binanceEndpoint{{"simnet", fakeBinanceURL, fakeBinanceURL, fakeBinanceWsURL}}
 	default: //mainnet
-		if binanceUS {
-			marketsURL, accountsURL, wsURL = usHttpURL, usHttpURL, usWebsocketURL
-		} else {
-			marketsURL, accountsURL, wsURL = httpURL, httpURL, websocketURL
+		var err error
+		if endpoints, err = selectBinanceEndpoints(cfg.Endpoints, binanceUS); err != nil {
+			return nil, err
 		}
 	}
 
@@ -532,9 +604,7 @@ func newBinance(cfg *CEXConfig, binanceUS bool) *binance {
 		log:                cfg.Logger,
 		broadcast:          cfg.Notify,
 		isUS:               binanceUS,
-		marketsURL:         marketsURL,
-		accountsURL:        accountsURL,
-		wsURL:              wsURL,
+		endpoints:          endpoints,
 		apiKey:             cfg.APIKey,
 		secretKey:          cfg.SecretKey,
 		knownAssets:        knownAssets,
@@ -551,7 +621,37 @@ func newBinance(cfg *CEXConfig, binanceUS bool) *binance {
 	bnc.markets.Store(make(map[string]*bntypes.Market))
 	bnc.listenKey.Store("")
 
-	return bnc
+	return bnc, nil
+}
+
+// endpoint returns the API endpoint in use.
+func (bnc *binance) endpoint() *binanceEndpoint {
+	return bnc.endpoints[bnc.endpointIdx.Load()]
+}
+
+// failover switches from the endpoint at index idx to the next endpoint. If
+// another endpoint is already in use, the switch was already made by another
+// request. The websocket streams are reconnected to the new endpoint.
+func (bnc *binance) failover(idx uint32) {
+	next := (idx + 1) % uint32(len(bnc.endpoints))
+	if !bnc.endpointIdx.CompareAndSwap(idx, next) {
+		return
+	}
+	bnc.log.Warnf("Binance endpoint %s is restricted from this location. Switching to endpoint %s.",
+		bnc.endpoints[idx].name, bnc.endpoints[next].name)
+
+	go func() {
+		bnc.marketStreamMtx.RLock()
+		if bnc.marketStream != nil {
+			bnc.marketStream.UpdateURL(bnc.streamURL())
+		}
+		bnc.marketStreamMtx.RUnlock()
+		// The user data stream is reconnected with the new endpoint.
+		select {
+		case bnc.reconnectChan <- struct{}{}:
+		case <-time.After(time.Minute):
+		}
+	}()
 }
 
 // setBalances queries binance for the user's balances and stores them in the
@@ -1025,7 +1125,7 @@ func (bnc *binance) ConfirmDeposit(ctx context.Context, deposit *DepositData) (b
 	var resp []*bntypes.PendingDeposit
 	// We'll add info for the fake server.
 	var query url.Values
-	if bnc.accountsURL == fakeBinanceURL {
+	if bnc.endpoint().accountsURL == fakeBinanceURL {
 		bncAsset, err := bncAssetCfg(deposit.AssetID)
 		if err != nil {
 			bnc.log.Errorf("Error getting asset cfg for %d: %v", deposit.AssetID, err)
@@ -1273,14 +1373,10 @@ func (bnc *binance) postAPI(ctx context.Context, endpoint string, query, form ur
 	return bnc.request(ctx, http.MethodPost, endpoint, query, form, key, sign, thing)
 }
 
+// request performs a request with the endpoint in use. If the endpoint responds
+// with HTTP 451, the service is unavailable from our location, and the request
+// is retried with the next configured endpoint until all have been tried.
 func (bnc *binance) request(ctx context.Context, method, endpoint string, query, form url.Values, key, sign bool, thing interface{}) error {
-	var fullURL string
-	if strings.Contains(endpoint, "sapi") {
-		fullURL = bnc.accountsURL + endpoint
-	} else {
-		fullURL = bnc.marketsURL + endpoint
-	}
-
 	if query == nil {
 		query = make(url.Values)
 	}
@@ -1290,10 +1386,8 @@ func (bnc *binance) request(ctx context.Context, method, endpoint string, query,
 	queryString := query.Encode()
 	bodyString := form.Encode()
 	header := make(http.Header, 2)
-	body := bytes.NewBuffer(nil)
 	if bodyString != "" {
 		header.Set("Content-Type", "application/x-www-form-urlencoded")
-		body = bytes.NewBufferString(bodyString)
 	}
 	if key || sign {
 		header.Set("X-MBX-APIKEY", bnc.apiKey)
@@ -1313,26 +1407,44 @@ func (bnc *binance) request(ctx context.Context, method, endpoint string, query,
 			queryString = fmt.Sprintf("%s&%s", queryString, v.Encode())
 		}
 	}
-	if queryString != "" {
-		fullURL = fmt.Sprintf("%s?%s", fullURL, queryString)
-	}
 
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
-	if err != nil {
-		return fmt.Errorf("NewRequestWithContext error: %w", err)
-	}
+	for tries := 1; ; tries++ {
+		idx := bnc.endpointIdx.Load()
+		ep := bnc.endpoints[idx]
+		var fullURL string
+		if strings.Contains(endpoint, "sapi") {
+			fullURL = ep.accountsURL + endpoint
+		} else {
+			fullURL = ep.marketsURL + endpoint
+		}
+		if queryString != "" {
+			fullURL = fmt.Sprintf("%s?%s", fullURL, queryString)
+		}
 
-	req.Header = header
+		req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewBufferString(bodyString))
+		if err != nil {
+			return fmt.Errorf("NewRequestWithContext error: %w", err)
+		}
 
-	var errPayload struct {
-		Code int    `json:"code"`
-		Msg  string `json:"msg"`
-	}
-	if err := dexnet.Do(req, thing, dexnet.WithSizeLimit(1<<24), dexnet.WithErrorParsing(&errPayload)); err != nil {
+		req.Header = header.Clone()
+
+		var errPayload struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		var status int
+		err = dexnet.Do(req, thing, dexnet.WithSizeLimit(1<<24), dexnet.WithErrorParsing(&errPayload),
+			dexnet.WithStatusFunc(func(code int) { status = code }))
+		if err == nil {
+			return nil
+		}
+		if status == http.StatusUnavailableForLegalReasons && tries < len(bnc.endpoints) {
+			bnc.failover(idx)
+			continue
+		}
 		bnc.log.Errorf("request error from endpoint %s %q with query = %q, body = %q", method, endpoint, queryString, bodyString)
 		return fmt.Errorf("%w, bn code = %d, msg = %q", err, errPayload.Code, errPayload.Msg)
 	}
-	return nil
 }
 
 func (bnc *binance) handleOutboundAccountPosition(update *bntypes.StreamUpdate) {
@@ -1493,7 +1605,7 @@ func (bnc *binance) getUserDataStream(ctx context.Context) (err error) {
 		}
 
 		conn, err := comms.NewWsConn(&comms.WsCfg{
-			URL:          bnc.wsURL + "/ws/" + listenKey,
+			URL:          bnc.endpoint().wsURL + "/ws/" + listenKey,
 			PingWait:     time.Minute * 4,
 			EchoPingData: true,
 			ReconnectSync: func() {
@@ -1732,7 +1844,7 @@ func (bnc *binance) streams() []string {
 }
 
 func (bnc *binance) streamURL() string {
-	return fmt.Sprintf("%s/stream?streams=%s", bnc.wsURL, strings.Join(bnc.streams(), "/"))
+	return fmt.Sprintf("%s/stream?streams=%s", bnc.endpoint().wsURL, strings.Join(bnc.streams(), "/"))
 }
 
 // checkSubs will query binance for current market subscriptions and compare
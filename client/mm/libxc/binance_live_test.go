@@ -48,7 +48,11 @@ func tNewBinance(t *testing.T, net dex.Network) *binance {
 		},
 	}
 	const binanceUS = true
-	return newBinance(cfg, binanceUS)
+	bnc, err := newBinance(cfg, binanceUS)
+	if err != nil {
+		t.Fatalf("error creating binance: %v", err)
+	}
+	return bnc
 }
 
 type spoofDriver struct {
@@ -4,7 +4,12 @@
 package libxc
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"decred.org/dcrdex/dex"
 )
 
 func TestSubscribeTradeUpdates(t *testing.T) {
@@ -47,3 +52,87 @@ func TestBinanceToDexSymbol(t *testing.T) {
 		}
 	}
 }
+
+func TestBinanceEndpointFailover(t *testing.T) {
+	var restrictedHits, openHits int
+	restricted := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		restrictedHits++
+		w.WriteHeader(http.StatusUnavailableForLegalReasons)
+		w.Write([]byte(`{"code":0,"msg":"Service unavailable from a restricted location"}`))
+	}))
+	defer restricted.Close()
+	open := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		openHits++
+		if r.Header.Get("X-MBX-APIKEY") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"serverTime":1}`))
+	}))
+	defer open.Close()
+
+	newEndpoint := func(name, url string) *binanceEndpoint {
+		return &binanceEndpoint{name: name, marketsURL: url, accountsURL: url, wsURL: "ws://localhost"}
+	}
+	bnc := &binance{
+		log:           dex.StdOutLogger("T", dex.LevelOff),
+		apiKey:        "key",
+		reconnectChan: make(chan struct{}),
+		endpoints: []*binanceEndpoint{
+			newEndpoint("restricted", restricted.URL),
+			newEndpoint("open", open.URL),
+		},
+	}
+
+	var resp struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := bnc.getAPI(context.Background(), "/api/v3/time", nil, true, false, &resp); err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if resp.ServerTime != 1 || restrictedHits != 1 || openHits != 1 {
+		t.Fatalf("wrong result. time = %d, restricted hits = %d, open hits = %d", resp.ServerTime, restrictedHits, openHits)
+	}
+	if bnc.endpoint().name != "open" {
+		t.Fatalf("wrong endpoint in use: %s", bnc.endpoint().name)
+	}
+
+	// The new endpoint is used for subsequent requests.
+	if err := bnc.getAPI(context.Background(), "/api/v3/time", nil, true, false, &resp); err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if restrictedHits != 1 || openHits != 2 {
+		t.Fatalf("restricted endpoint used after failover")
+	}
+
+	// When all endpoints are restricted, the error is returned.
+	bnc.endpoints[1] = newEndpoint("restricted2", restricted.URL)
+	if err := bnc.getAPI(context.Background(), "/api/v3/time", nil, true, false, &resp); err == nil {
+		t.Fatalf("no error when all endpoints are restricted")
+	}
+	if restrictedHits != 3 {
+		t.Fatalf("expected each endpoint to be tried once, got %d restricted hits", restrictedHits-1)
+	}
+}
+
+func TestSelectBinanceEndpoints(t *testing.T) {
+	endpoints, err := selectBinanceEndpoints(nil, false)
+	if err != nil {
+		t.Fatalf("error selecting default endpoints: %v", err)
+	}
+	if len(endpoints) != len(binanceEndpoints) || endpoints[0].marketsURL != httpURL {
+		t.Fatalf("wrong default endpoints")
+	}
+
+	endpoints, err = selectBinanceEndpoints([]string{"api2", "global", "api2"}, false)
+	if err != nil {
+		t.Fatalf("error selecting endpoints: %v", err)
+	}
+	if len(endpoints) != 2 || endpoints[0].name != "api2" || endpoints[1].name != "global" {
+		t.Fatalf("wrong endpoints selected")
+	}
+
+	if _, err = selectBinanceEndpoints([]string{"api2"}, true); err == nil {
+		t.Fatalf("no error for a Binance endpoint with Binance.US")
+	}
+}
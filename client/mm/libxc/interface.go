@@ -152,16 +152,25 @@ type CEXConfig struct {
 	SecretKey string
 	Logger    dex.Logger
 	Notify    func(interface{})
+	// Endpoints are the names of the mainnet API endpoints to use, in order of
+	// preference. The next endpoint is used if the one in use is restricted
+	// from our location. If empty, all known endpoints are used.
+	Endpoints []string
 }
 
 // NewCEX creates a new CEX.
 func NewCEX(cexName string, cfg *CEXConfig) (CEX, error) {
+	var binanceUS bool
 	switch cexName {
 	case Binance:
-		return newBinance(cfg, false), nil
 	case BinanceUS:
-		return newBinance(cfg, true), nil
+		binanceUS = true
 	default:
 		return nil, fmt.Errorf("unrecognized CEX: %v", cexName)
 	}
+	bnc, err := newBinance(cfg, binanceUS)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring %s: %w", cexName, err)
+	}
+	return bnc, nil
 }
@@ -0,0 +1,201 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package libxc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+// marketDataTTL is how long the market list and the 24-hour market data are
+// cached.
+const marketDataTTL = time.Minute * 10
+
+// MarketData is a market-data layer that is shared by all of the consumers of
+// a CEX. Each consumer, e.g. a bot, uses the CEX through its own
+// MarketDataClient. Order book subscriptions are multiplexed, so that the CEX
+// holds a single subscription to a market's book for all of the consumers, and
+// the market list and 24-hour market data are cached, so that the CEX's API is
+// queried once per cache period regardless of the number of consumers.
+// Concurrent requests for uncached data wait for a single request to the CEX.
+type MarketData struct {
+	CEX
+	log dex.Logger
+
+	subsMtx sync.Mutex
+	// subs maps the market ID to the IDs of the subscribed clients.
+	subs map[string]map[string]bool
+
+	marketsMtx   sync.Mutex
+	markets      map[string]*Market
+	marketsStamp time.Time
+
+	matchedMtx   sync.Mutex
+	matched      []*MarketMatch
+	matchedStamp time.Time
+}
+
+// NewMarketData is the constructor for a MarketData.
+func NewMarketData(cex CEX, log dex.Logger) *MarketData {
+	return &MarketData{
+		CEX:  cex,
+		log:  log,
+		subs: make(map[string]map[string]bool),
+	}
+}
+
+// Markets returns the cached 24-hour market data, refreshing it from the CEX
+// if it is stale.
+func (md *MarketData) Markets(ctx context.Context) (map[string]*Market, error) {
+	md.marketsMtx.Lock()
+	defer md.marketsMtx.Unlock()
+	if md.markets != nil && time.Since(md.marketsStamp) < marketDataTTL {
+		return md.markets, nil
+	}
+	mkts, err := md.CEX.Markets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	md.markets, md.marketsStamp = mkts, time.Now()
+	return mkts, nil
+}
+
+// MatchedMarkets returns the cached list of markets, refreshing it from the
+// CEX if it is stale.
+func (md *MarketData) MatchedMarkets(ctx context.Context) ([]*MarketMatch, error) {
+	md.matchedMtx.Lock()
+	defer md.matchedMtx.Unlock()
+	if md.matched != nil && time.Since(md.matchedStamp) < marketDataTTL {
+		return md.matched, nil
+	}
+	matched, err := md.CEX.MatchedMarkets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	md.matched, md.matchedStamp = matched, time.Now()
+	return matched, nil
+}
+
+// Client creates a MarketDataClient for a consumer. The ID must be unique
+// among the MarketData's consumers.
+func (md *MarketData) Client(id string) *MarketDataClient {
+	return &MarketDataClient{
+		MarketData: md,
+		id:         id,
+	}
+}
+
+// Subscribers returns the IDs of the clients subscribed to the market.
+func (md *MarketData) Subscribers(baseID, quoteID uint32) []string {
+	mktID, err := dex.MarketName(baseID, quoteID)
+	if err != nil {
+		return nil
+	}
+	md.subsMtx.Lock()
+	defer md.subsMtx.Unlock()
+	ids := make([]string, 0, len(md.subs[mktID]))
+	for id := range md.subs[mktID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// subscribe subscribes the client to the market. Only the first subscriber
+// subscribes with the CEX.
+func (md *MarketData) subscribe(ctx context.Context, clientID string, baseID, quoteID uint32) error {
+	mktID, err := dex.MarketName(baseID, quoteID)
+	if err != nil {
+		return fmt.Errorf("error getting market name: %w", err)
+	}
+	md.subsMtx.Lock()
+	defer md.subsMtx.Unlock()
+	clients := md.subs[mktID]
+	if clients[clientID] {
+		return nil
+	}
+	if len(clients) == 0 {
+		if err := md.CEX.SubscribeMarket(ctx, baseID, quoteID); err != nil {
+			return err
+		}
+		clients = make(map[string]bool)
+		md.subs[mktID] = clients
+	}
+	clients[clientID] = true
+	return nil
+}
+
+// unsubscribe unsubscribes the client from the market. The last subscriber
+// unsubscribes with the CEX.
+func (md *MarketData) unsubscribe(clientID string, baseID, quoteID uint32) error {
+	mktID, err := dex.MarketName(baseID, quoteID)
+	if err != nil {
+		return fmt.Errorf("error getting market name: %w", err)
+	}
+	md.subsMtx.Lock()
+	defer md.subsMtx.Unlock()
+	clients := md.subs[mktID]
+	if !clients[clientID] {
+		return fmt.Errorf("%s is not subscribed to %s", clientID, mktID)
+	}
+	delete(clients, clientID)
+	if len(clients) > 0 {
+		return nil
+	}
+	delete(md.subs, mktID)
+	return md.CEX.UnsubscribeMarket(baseID, quoteID)
+}
+
+// MarketDataClient is a consumer's view of a CEX with a shared MarketData.
+type MarketDataClient struct {
+	*MarketData
+	id string
+
+	mtx  sync.Mutex
+	mkts map[[2]uint32]bool
+}
+
+var _ CEX = (*MarketDataClient)(nil)
+
+// SubscribeMarket subscribes to order book updates on a market. The CEX is
+// only subscribed to the market if no other client is subscribed. Subscribing
+// to a market that the client is already subscribed to does nothing.
+func (c *MarketDataClient) SubscribeMarket(ctx context.Context, baseID, quoteID uint32) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.subscribe(ctx, c.id, baseID, quoteID); err != nil {
+		return err
+	}
+	if c.mkts == nil {
+		c.mkts = make(map[[2]uint32]bool)
+	}
+	c.mkts[[2]uint32{baseID, quoteID}] = true
+	return nil
+}
+
+// UnsubscribeMarket unsubscribes from order book updates on a market. The CEX
+// is only unsubscribed from the market if no other client is subscribed.
+func (c *MarketDataClient) UnsubscribeMarket(baseID, quoteID uint32) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.mkts, [2]uint32{baseID, quoteID})
+	return c.unsubscribe(c.id, baseID, quoteID)
+}
+
+// Close unsubscribes the client from all of its markets.
+func (c *MarketDataClient) Close() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for mkt := range c.mkts {
+		if err := c.unsubscribe(c.id, mkt[0], mkt[1]); err != nil {
+			c.log.Errorf("Error unsubscribing %s from market %d-%d: %v", c.id, mkt[0], mkt[1], err)
+		}
+	}
+	c.mkts = nil
+}
@@ -0,0 +1,124 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package libxc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"decred.org/dcrdex/dex"
+)
+
+type tMarketDataCEX struct {
+	CEX
+	subs          map[[2]uint32]int
+	subErr        error
+	marketsCalls  int
+	marketsErr    error
+	matchedCalls  int
+	unsubscribes  int
+	marketsResult map[string]*Market
+}
+
+func (c *tMarketDataCEX) SubscribeMarket(_ context.Context, baseID, quoteID uint32) error {
+	if c.subErr != nil {
+		return c.subErr
+	}
+	c.subs[[2]uint32{baseID, quoteID}]++
+	return nil
+}
+
+func (c *tMarketDataCEX) UnsubscribeMarket(baseID, quoteID uint32) error {
+	c.subs[[2]uint32{baseID, quoteID}]--
+	c.unsubscribes++
+	return nil
+}
+
+func (c *tMarketDataCEX) Markets(context.Context) (map[string]*Market, error) {
+	c.marketsCalls++
+	return c.marketsResult, c.marketsErr
+}
+
+func (c *tMarketDataCEX) MatchedMarkets(context.Context) ([]*MarketMatch, error) {
+	c.matchedCalls++
+	return []*MarketMatch{{BaseID: 42, QuoteID: 0}}, nil
+}
+
+func TestMarketData(t *testing.T) {
+	cex := &tMarketDataCEX{
+		subs:          make(map[[2]uint32]int),
+		marketsResult: map[string]*Market{"dcr_btc": {BaseID: 42, QuoteID: 0}},
+	}
+	md := NewMarketData(cex, dex.StdOutLogger("T", dex.LevelOff))
+	ctx := context.Background()
+	dcrBTC := [2]uint32{42, 0}
+	ethBTC := [2]uint32{60, 0}
+
+	bot1, bot2 := md.Client("bot1"), md.Client("bot2")
+
+	// A failed subscription is not recorded.
+	cex.subErr = errors.New("test error")
+	if err := bot1.SubscribeMarket(ctx, 42, 0); err == nil {
+		t.Fatalf("no error for CEX subscription error")
+	}
+	cex.subErr = nil
+	if subs := md.Subscribers(42, 0); len(subs) != 0 {
+		t.Fatalf("failed subscription recorded: %v", subs)
+	}
+
+	// The CEX is only subscribed once per market.
+	for _, c := range []*MarketDataClient{bot1, bot2, bot1} {
+		if err := c.SubscribeMarket(ctx, 42, 0); err != nil {
+			t.Fatalf("SubscribeMarket error: %v", err)
+		}
+	}
+	if err := bot2.SubscribeMarket(ctx, 60, 0); err != nil {
+		t.Fatalf("SubscribeMarket error: %v", err)
+	}
+	if cex.subs[dcrBTC] != 1 || cex.subs[ethBTC] != 1 {
+		t.Fatalf("wrong CEX subscriptions %v", cex.subs)
+	}
+	if subs := md.Subscribers(42, 0); !reflect.DeepEqual(subs, []string{"bot1", "bot2"}) {
+		t.Fatalf("wrong subscribers %v", subs)
+	}
+
+	// The CEX stays subscribed until the last client unsubscribes.
+	if err := bot1.UnsubscribeMarket(42, 0); err != nil {
+		t.Fatalf("UnsubscribeMarket error: %v", err)
+	}
+	if err := bot1.UnsubscribeMarket(42, 0); err == nil {
+		t.Fatalf("no error for unsubscribing twice")
+	}
+	if cex.unsubscribes != 0 {
+		t.Fatalf("CEX unsubscribed with a subscriber remaining")
+	}
+	bot2.Close()
+	if cex.subs[dcrBTC] != 0 || cex.subs[ethBTC] != 0 || cex.unsubscribes != 2 {
+		t.Fatalf("CEX not unsubscribed on close: %v", cex.subs)
+	}
+
+	// Market data is cached, and errors are not.
+	cex.marketsErr = errors.New("test error")
+	if _, err := bot1.Markets(ctx); err == nil {
+		t.Fatalf("no error for CEX markets error")
+	}
+	cex.marketsErr = nil
+	for _, c := range []*MarketDataClient{bot1, bot2} {
+		mkts, err := c.Markets(ctx)
+		if err != nil {
+			t.Fatalf("Markets error: %v", err)
+		}
+		if len(mkts) != 1 {
+			t.Fatalf("wrong markets %v", mkts)
+		}
+		if _, err := c.MatchedMarkets(ctx); err != nil {
+			t.Fatalf("MatchedMarkets error: %v", err)
+		}
+	}
+	if cex.marketsCalls != 2 || cex.matchedCalls != 1 {
+		t.Fatalf("market data not cached. %d markets calls, %d matched markets calls", cex.marketsCalls, cex.matchedCalls)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"decred.org/dcrdex/client/asset"
@@ -112,12 +114,17 @@ type bot interface {
 	timeStart() int64
 	botCfg() *BotConfig
 	Book() (buys, sells []*core.MiniOrder, _ error)
+	convertCEXInventory(fromID, toID uint32, qty uint64, maxSlippage float64) (*CEXConversion, error)
+	windDown()
+	inventoryDemand() map[uint32]uint64
+	shareInventory(assetID uint32, diff int64) int64
 }
 
 type runningBot struct {
 	bot
-	cm     *dex.ConnectionMaster
-	cexCfg *CEXConfig
+	cm          *dex.ConnectionMaster
+	cexCfg      *CEXConfig
+	windingDown atomic.Bool
 }
 
 func (rb *runningBot) assets() map[uint32]interface{} {
@@ -202,6 +209,9 @@ func (m *MarketMaker) runningBotsLookup() map[MarketWithHost]*runningBot {
 type Status struct {
 	Bots  []*BotStatus          `json:"bots"`
 	CEXes map[string]*CEXStatus `json:"cexes"`
+	// InventoryGroups is the combined state of the running bots in each
+	// inventory group.
+	InventoryGroups map[string]*InventoryGroupStatus `json:"inventoryGroups,omitempty"`
 }
 
 // CEXStatus is state information about a cex.
@@ -278,6 +288,8 @@ type EpochReport struct {
 	SellsReport *OrderReport `json:"sellsReport"`
 	// EpochNum is the number of the epoch.
 	EpochNum uint64 `json:"epochNum"`
+	// Fills are the bot's fills and quote quality during the epoch.
+	Fills *EpochFillStats `json:"fills"`
 }
 
 func (er *EpochReport) setPreOrderProblems(err error) {
@@ -348,6 +360,8 @@ type BotStatus struct {
 	RunStats    *RunStats    `json:"runStats"`
 	LatestEpoch *EpochReport `json:"latestEpoch"`
 	CEXProblems *CEXProblems `json:"cexProblems"`
+	// WindingDown is true if the bot is settling its matches before it stops.
+	WindingDown bool `json:"windingDown"`
 }
 
 // Status generates a Status for the MarketMaker. This returns the status of
@@ -365,10 +379,12 @@ func (m *MarketMaker) Status() *Status {
 		var stats *RunStats
 		var epochReport *EpochReport
 		var cexProblems *CEXProblems
+		var windingDown bool
 		if rb != nil {
 			stats = rb.stats()
 			epochReport = rb.latestEpoch()
 			cexProblems = rb.latestCEXProblems()
+			windingDown = rb.windingDown.Load()
 		}
 		status.Bots = append(status.Bots, &BotStatus{
 			Config:      botCfg,
@@ -376,6 +392,7 @@ func (m *MarketMaker) Status() *Status {
 			RunStats:    stats,
 			LatestEpoch: epochReport,
 			CEXProblems: cexProblems,
+			WindingDown: windingDown,
 		})
 	}
 	for _, cex := range m.cexList() {
@@ -390,6 +407,7 @@ func (m *MarketMaker) Status() *Status {
 		}
 		status.CEXes[cex.Name] = s
 	}
+	status.InventoryGroups = inventoryGroupsStatus(runningBots)
 	return status
 }
 
@@ -409,8 +427,10 @@ func (m *MarketMaker) RunningBotsStatus() *Status {
 			RunStats:    rb.stats(),
 			LatestEpoch: rb.latestEpoch(),
 			CEXProblems: rb.latestCEXProblems(),
+			WindingDown: rb.windingDown.Load(),
 		})
 	}
+	status.InventoryGroups = inventoryGroupsStatus(runningBots)
 	return status
 }
 
@@ -553,13 +573,13 @@ func (m *MarketMaker) loadCEX(ctx context.Context, cfg *CEXConfig) (*centralized
 	defer m.cexMtx.Unlock()
 	var success bool
 	if cex := m.cexes[cfg.Name]; cex != nil {
-		if cex.APIKey == cfg.APIKey && cex.APISecret == cfg.APISecret {
+		if cex.APIKey == cfg.APIKey && cex.APISecret == cfg.APISecret && slices.Equal(cex.Endpoints, cfg.Endpoints) {
 			return cex, nil
 		}
 		if m.cexInUse(cfg.Name) {
-			return nil, fmt.Errorf("CEX %s already in use with different API key", cfg.Name)
+			return nil, fmt.Errorf("CEX %s already in use with different API key or endpoints", cfg.Name)
 		}
-		// New credentials. Delete the old cex.
+		// New credentials or endpoints. Delete the old cex.
 		defer func() {
 			if success {
 				cex.mtx.Lock()
@@ -573,6 +593,7 @@ func (m *MarketMaker) loadCEX(ctx context.Context, cfg *CEXConfig) (*centralized
 	cex, err := libxc.NewCEX(cfg.Name, &libxc.CEXConfig{
 		APIKey:    cfg.APIKey,
 		SecretKey: cfg.APISecret,
+		Endpoints: cfg.Endpoints,
 		Logger:    logger,
 		Net:       m.core.Network(),
 		Notify: func(n interface{}) {
@@ -583,10 +604,10 @@ func (m *MarketMaker) loadCEX(ctx context.Context, cfg *CEXConfig) (*centralized
 		return nil, fmt.Errorf("failed to create CEX: %v", err)
 	}
 	c := &centralizedExchange{
-		CEX:       cex,
+		CEX:       libxc.NewMarketData(cex, logger),
 		CEXConfig: cfg,
 	}
-	c.mkts, err = cex.Markets(ctx)
+	c.mkts, err = c.Markets(ctx)
 	if err != nil {
 		m.log.Errorf("Failed to get markets for %s: %v", cfg.Name, err)
 		c.mkts = make(map[string]*libxc.Market)
@@ -663,6 +684,12 @@ func (m *MarketMaker) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 
 	var wg sync.WaitGroup
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.runInventorySharing()
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -832,6 +859,10 @@ func (m *MarketMaker) StartBot(startCfg *StartConfig, alternateConfigPath *strin
 
 func (m *MarketMaker) startBot(startCfg *StartConfig, botCfg *BotConfig, cexCfg *CEXConfig, appPW []byte) (err error) {
 	mwh := &startCfg.MarketWithHost
+	if err := validateBotConfig(botCfg); err != nil {
+		return err
+	}
+
 	if err := m.balancesSufficient(startCfg.Alloc, mwh, cexCfg); err != nil {
 		return err
 	}
@@ -863,14 +894,32 @@ func (m *MarketMaker) startBot(startCfg *StartConfig, botCfg *BotConfig, cexCfg
 		}()
 	}
 
+	botID := dexMarketID(botCfg.Host, botCfg.BaseID, botCfg.QuoteID)
+	var botCEX libxc.CEX
+	closeCEX := func() {}
+	if cex != nil {
+		botCEX = cex
+		// Bots share the CEX's market data. The bot's client releases the
+		// bot's market subscriptions when the bot stops.
+		if md, is := cex.CEX.(*libxc.MarketData); is {
+			client := md.Client(botID)
+			botCEX, closeCEX = client, client.Close
+		}
+	}
+	defer func() {
+		if !startedBot {
+			closeCEX()
+		}
+	}()
+
 	adaptorCfg := &exchangeAdaptorCfg{
-		botID:               dexMarketID(botCfg.Host, botCfg.BaseID, botCfg.QuoteID),
+		botID:               botID,
 		mwh:                 mwh,
 		baseDexBalances:     startCfg.Alloc.DEX,
 		baseCexBalances:     startCfg.Alloc.CEX,
 		autoRebalanceConfig: startCfg.AutoRebalance,
 		core:                m.core,
-		cex:                 cex,
+		cex:                 botCEX,
 		log:                 m.botSubLogger(botCfg),
 		botCfg:              botCfg,
 		eventLogDB:          m.eventLogDB,
@@ -888,6 +937,7 @@ func (m *MarketMaker) startBot(startCfg *StartConfig, botCfg *BotConfig, cexCfg
 
 	go func() {
 		cm.Wait()
+		closeCEX()
 		m.runningBotsMtx.Lock()
 		if bot, found := m.runningBots[*mwh]; found {
 			if bot.botCfg().requiresPriceOracle() {
@@ -914,6 +964,28 @@ func (m *MarketMaker) startBot(startCfg *StartConfig, botCfg *BotConfig, cexCfg
 	return nil
 }
 
+// WindDownBot stops a running bot gracefully. The bot stops placing orders and
+// cancels its booked orders, but keeps running until the swaps of its matches
+// and its pending trades and transfers are complete. The bot's final balances
+// are then reported, and the bot is stopped. StopBot can be used to stop a
+// bot that is winding down immediately.
+func (m *MarketMaker) WindDownBot(mkt *MarketWithHost) error {
+	runningBots := m.runningBotsLookup()
+	rb, found := runningBots[*mkt]
+	if !found {
+		return fmt.Errorf("no bot running on market: %s", mkt)
+	}
+	if !rb.windingDown.CompareAndSwap(false, true) {
+		return fmt.Errorf("bot on market %s is already winding down", mkt)
+	}
+	go func() {
+		rb.windDown()
+		rb.cm.Disconnect()
+		m.core.Broadcast(newRunStatsNote(mkt.Host, mkt.BaseID, mkt.QuoteID, nil))
+	}()
+	return nil
+}
+
 // StopBot stops a running bot.
 func (m *MarketMaker) StopBot(mkt *MarketWithHost) error {
 	runningBots := m.runningBotsLookup()
@@ -1065,6 +1137,12 @@ func validRunningBotCfgUpdate(oldCfg, newCfg *BotConfig) error {
 		return fmt.Errorf("cannot change bot type for running bot")
 	}
 
+	if newCfg.Jitter != nil {
+		if err := newCfg.Jitter.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1094,6 +1172,34 @@ func (m *MarketMaker) UpdateRunningBotInventory(mkt *MarketWithHost, balanceDiff
 	return nil
 }
 
+// ConvertCEXInventory converts qty of a running bot's CEX inventory of fromID
+// to toID. If there is no direct market for the conversion on the CEX, or the
+// direct market's books are too thin, the conversion is routed through an
+// intermediate asset, e.g. DCR -> BTC -> USDT. Routes with an estimated
+// slippage greater than maxSlippage, a fraction of the converted value, are
+// not used.
+func (m *MarketMaker) ConvertCEXInventory(mkt *MarketWithHost, fromID, toID uint32, qty uint64, maxSlippage float64) (*CEXConversion, error) {
+	m.runningBotsMtx.RLock()
+	rb := m.runningBots[*mkt]
+	m.runningBotsMtx.RUnlock()
+	if rb == nil {
+		return nil, fmt.Errorf("no bot running on market: %s", mkt)
+	}
+	return rb.convertCEXInventory(fromID, toID, qty, maxSlippage)
+}
+
+// QuoteQuality returns the fill and quote quality statistics of the bot
+// running on the market, aggregated over the bot's run.
+func (m *MarketMaker) QuoteQuality(mkt *MarketWithHost) (*QuoteQualityStats, error) {
+	m.runningBotsMtx.RLock()
+	rb := m.runningBots[*mkt]
+	m.runningBotsMtx.RUnlock()
+	if rb == nil {
+		return nil, fmt.Errorf("no bot running on market: %s", mkt)
+	}
+	return rb.stats().QuoteQuality, nil
+}
+
 // UpdateRunningBotCfg updates the configuration and balance allocation for a
 // running bot. If saveUpdate is true, the update configuration will be saved
 // to the default config file.
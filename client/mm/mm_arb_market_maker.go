@@ -353,6 +353,10 @@ func (a *arbMarketMaker) rebalance(epoch uint64, book *orderbook.OrderBook) {
 		return
 	}
 
+	if !a.waitJitterDelay() {
+		return
+	}
+
 	var buysReport, sellsReport *OrderReport
 	buyOrders, sellOrders, determinePlacementsErr := a.ordersToPlace()
 	if determinePlacementsErr != nil {
@@ -78,8 +78,24 @@ type BasicMarketMakingConfig struct {
 	// before they are replaced (units: ratio of price). Default: 0.1%.
 	// 0 <= x <= 0.01.
 	DriftTolerance float64 `json:"driftTolerance"`
+
+	// ExitLiquidityRatio limits the lots placed on each side of the book to
+	// this ratio of the opposing side's depth within exitDepthRange of the
+	// basis price. Placements are scaled down, lowest priority first, when
+	// the opposing side is too thin. 0 disables the limit.
+	ExitLiquidityRatio float64 `json:"exitLiquidityRatio,omitempty"`
+
+	// MinProfitMargin is the margin, as a ratio of the basis price, that
+	// placement rates must clear beyond the break-even half-gap. Placements
+	// that don't clear the break-even half-gap plus the margin are skipped,
+	// regardless of the gap strategy. 0 <= x <= 0.1.
+	MinProfitMargin float64 `json:"minProfitMargin,omitempty"`
 }
 
+// exitDepthRange is the range around the basis price that the opposing side's
+// depth is measured within for the ExitLiquidityRatio.
+const exitDepthRange = 0.02
+
 func needBreakEvenHalfSpread(strat GapStrategy) bool {
 	return strat == GapStrategyAbsolutePlus || strat == GapStrategyPercentPlus || strat == GapStrategyMultiplier
 }
@@ -89,6 +105,14 @@ func (c *BasicMarketMakingConfig) Validate() error {
 		return fmt.Errorf("drift tolerance %f out of bounds", c.DriftTolerance)
 	}
 
+	if c.ExitLiquidityRatio < 0 {
+		return fmt.Errorf("negative exit liquidity ratio %f", c.ExitLiquidityRatio)
+	}
+
+	if c.MinProfitMargin < 0 || c.MinProfitMargin > 0.1 {
+		return fmt.Errorf("min profit margin %f out of bounds", c.MinProfitMargin)
+	}
+
 	if c.GapStrategy != GapStrategyMultiplier &&
 		c.GapStrategy != GapStrategyPercent &&
 		c.GapStrategy != GapStrategyPercentPlus &&
@@ -365,6 +389,29 @@ func (m *basicMarketMaker) orderPrice(truePrice, bestBuy, bestSell, feeAdj uint6
 	return truePrice - adj
 }
 
+// clearsProfitGap checks whether the rate is at least minGap from the basis
+// price, on the profitable side.
+func clearsProfitGap(rate, basisPrice, minGap uint64, sell bool) bool {
+	if sell {
+		return rate >= basisPrice+minGap
+	}
+	return basisPrice >= minGap && rate <= basisPrice-minGap
+}
+
+// exitLimitedLots limits a placement's lots to the lots remaining of the
+// opposing side's depth, and deducts the placement's lots from the remaining
+// lots. A negative remaining means there is no limit.
+func exitLimitedLots(lots uint64, remaining *int64) uint64 {
+	if *remaining < 0 {
+		return lots
+	}
+	if lots > uint64(*remaining) {
+		lots = uint64(*remaining)
+	}
+	*remaining -= int64(lots)
+	return lots
+}
+
 func (m *basicMarketMaker) ordersToPlace() (buyOrders, sellOrders []*TradePlacement, err error) {
 	m.log.Tracef("mm bot (basic) is starting to calculate placements")
 	defer func() {
@@ -440,8 +487,43 @@ func (m *basicMarketMaker) ordersToPlace() (buyOrders, sellOrders []*TradePlacem
 	if needBreakEvenHalfSpread(m.cfg().GapStrategy) {
 		feeAdj = feeGap.FeeGap / 2
 	}
+	// minProfitGap is the smallest distance from the basis price at which a
+	// placement covers the fees plus the configured margin.
+	minProfitGap := feeGap.FeeGap/2 + uint64(math.Round(m.cfg().MinProfitMargin*float64(basisPrice)))
+
+	// exitLots is the number of lots that the opposing side's depth supports
+	// for a side, or -1 if there is no limit.
+	exitLots := func(sell bool) (int64, error) {
+		ratio := m.cfg().ExitLiquidityRatio
+		if ratio == 0 {
+			return -1, nil
+		}
+		// The opposing side of a sell is the buy side, and its depth is
+		// counted down to exitDepthRange below the basis price.
+		limitRate := uint64(float64(basisPrice) * (1 + exitDepthRange))
+		if sell {
+			limitRate = uint64(float64(basisPrice) * (1 - exitDepthRange))
+		}
+		depth, err := book.CumulativeDepth(limitRate, !sell)
+		if err != nil {
+			return 0, fmt.Errorf("error getting %s book depth: %w", sellStr(!sell), err)
+		}
+		return int64(float64(depth) * ratio / float64(m.lotSize)), nil
+	}
+	buyExitLots, err := exitLots(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	sellExitLots, err := exitLots(true)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	orders := func(orderPlacements []*OrderPlacement, sell bool) []*TradePlacement {
+		remainingExitLots := buyExitLots
+		if sell {
+			remainingExitLots = sellExitLots
+		}
 		placements := make([]*TradePlacement, 0, len(orderPlacements))
 		for _, p := range orderPlacements {
 			// when assessing how far the price has gone since MM bot started (current price vs first
@@ -488,9 +570,23 @@ func (m *basicMarketMaker) ordersToPlace() (buyOrders, sellOrders []*TradePlacem
 			if rate == 0 {
 				lots = 0 // just a no-op placement I guess
 			}
+			var unprofitable bool
+			if rate != 0 && !clearsProfitGap(rate, basisPrice, minProfitGap, sell) {
+				m.log.Debugf("Skipping %s placement at %s, which is within the minimum profitable gap %s of the basis price %s",
+					sellStr(sell), m.fmtRate(rate), m.fmtRate(minProfitGap), m.fmtRate(basisPrice))
+				lots, unprofitable = 0, true
+			}
+			var scaledFromLots uint64
+			if limited := exitLimitedLots(lots, &remainingExitLots); limited < lots {
+				m.log.Debugf("Scaling %s placement at %s down from %d to %d lots for thin %s book depth",
+					sellStr(sell), m.fmtRate(rate), lots, limited, sellStr(!sell))
+				scaledFromLots, lots = lots, limited
+			}
 			placements = append(placements, &TradePlacement{
-				Rate: rate,
-				Lots: lots,
+				Rate:           rate,
+				Lots:           lots,
+				ScaledFromLots: scaledFromLots,
+				Unprofitable:   unprofitable,
 			})
 		}
 		return placements
@@ -514,6 +610,10 @@ func (m *basicMarketMaker) rebalance(newEpoch uint64) {
 		return
 	}
 
+	if !m.waitJitterDelay() {
+		return
+	}
+
 	// simple work-around for not competing with my own (bot's) orders in Bison book,
 	// every 2nd epoch (happens every 60s) we simply revoke our orders so that we can
 	// re-book these with correct price (presumably on that very same epoch).
@@ -176,6 +176,73 @@ func TestBreakEvenHalfSpread(t *testing.T) {
 	}
 }
 
+func TestClearsProfitGap(t *testing.T) {
+	const basisPrice, minGap uint64 = 5e6, 1e5
+	tests := []struct {
+		rate uint64
+		sell bool
+		exp  bool
+	}{
+		{rate: 5.1e6, sell: true, exp: true},
+		{rate: 5.2e6, sell: true, exp: true},
+		{rate: 5.099e6, sell: true, exp: false},
+		{rate: 4.9e6, sell: false, exp: true},
+		{rate: 4.8e6, sell: false, exp: true},
+		{rate: 4.901e6, sell: false, exp: false},
+	}
+	for _, tt := range tests {
+		if clearsProfitGap(tt.rate, basisPrice, minGap, tt.sell) != tt.exp {
+			t.Fatalf("%s at %d: expected %t", sellStr(tt.sell), tt.rate, tt.exp)
+		}
+	}
+	if clearsProfitGap(1, 1e4, 2e4, false) {
+		t.Fatalf("buy cleared a gap wider than the basis price")
+	}
+}
+
+func TestExitLimitedLots(t *testing.T) {
+	tests := []struct {
+		name      string
+		lots      []uint64
+		exitLots  int64
+		expLots   []uint64
+		remaining int64
+	}{{
+		name:      "no limit",
+		lots:      []uint64{5, 3},
+		exitLots:  -1,
+		expLots:   []uint64{5, 3},
+		remaining: -1,
+	}, {
+		name:      "deep book",
+		lots:      []uint64{5, 3},
+		exitLots:  10,
+		expLots:   []uint64{5, 3},
+		remaining: 2,
+	}, {
+		name:     "thin book",
+		lots:     []uint64{5, 3},
+		exitLots: 1,
+		expLots:  []uint64{1, 0},
+	}, {
+		name:     "lower priority scaled",
+		lots:     []uint64{2, 3, 4},
+		exitLots: 4,
+		expLots:  []uint64{2, 2, 0},
+	}}
+	for _, tt := range tests {
+		remaining := tt.exitLots
+		for i, lots := range tt.lots {
+			if limited := exitLimitedLots(lots, &remaining); limited != tt.expLots[i] {
+				t.Fatalf("%s: placement %d: expected %d lots, got %d", tt.name, i, tt.expLots[i], limited)
+			}
+		}
+		if remaining != tt.remaining {
+			t.Fatalf("%s: expected %d remaining, got %d", tt.name, tt.remaining, remaining)
+		}
+	}
+}
+
 func TestBasicMMRebalance(t *testing.T) {
 	const basisPrice uint64 = 5e6
 	const halfSpread uint64 = 2e5
@@ -671,6 +671,16 @@ func (t *tExchangeAdaptor) withPause(func() error) error    { return nil }
 func (t *tExchangeAdaptor) botCfg() *BotConfig              { return t.cfg }
 func (t *tExchangeAdaptor) latestEpoch() *EpochReport       { return &EpochReport{} }
 func (t *tExchangeAdaptor) latestCEXProblems() *CEXProblems { return nil }
+func (t *tExchangeAdaptor) windDown() {}
+func (t *tExchangeAdaptor) inventoryDemand() map[uint32]uint64 {
+	return nil
+}
+func (t *tExchangeAdaptor) shareInventory(assetID uint32, diff int64) int64 {
+	return diff
+}
+func (t *tExchangeAdaptor) convertCEXInventory(fromID, toID uint32, qty uint64, maxSlippage float64) (*CEXConversion, error) {
+	return nil, nil
+}
 
 func TestAvailableBalances(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -15,6 +15,15 @@ const (
 	NoteTypeCEXProblems     = "cexproblems"
 )
 
+// marketNoteData is the structured data for a notification about a bot's
+// market.
+func marketNoteData(host string, baseID, quoteID uint32) *db.NoteData {
+	return &db.NoteData{
+		Host:     host,
+		MarketID: MarketWithHost{Host: host, BaseID: baseID, QuoteID: quoteID}.ID(),
+	}
+}
+
 type runStatsNote struct {
 	db.Notification
 
@@ -26,13 +35,15 @@ type runStatsNote struct {
 }
 
 func newRunStatsNote(host string, baseID, quoteID uint32, stats *RunStats) *runStatsNote {
-	return &runStatsNote{
+	note := &runStatsNote{
 		Notification: db.NewNotification(NoteTypeRunStats, "", "", "", db.Data),
 		Host:         host,
 		BaseID:       baseID,
 		QuoteID:      quoteID,
 		Stats:        stats,
 	}
+	note.NoteData = marketNoteData(host, baseID, quoteID)
+	return note
 }
 
 type runEventNote struct {
@@ -46,7 +57,7 @@ type runEventNote struct {
 }
 
 func newRunEventNote(host string, baseID, quoteID uint32, startTime int64, event *MarketMakingEvent) *runEventNote {
-	return &runEventNote{
+	note := &runEventNote{
 		Notification: db.NewNotification(NoteTypeRunEvent, "", "", "", db.Data),
 		Host:         host,
 		BaseID:       baseID,
@@ -54,6 +65,8 @@ func newRunEventNote(host string, baseID, quoteID uint32, startTime int64, event
 		StartTime:    startTime,
 		Event:        event,
 	}
+	note.NoteData = marketNoteData(host, baseID, quoteID)
+	return note
 }
 
 type cexNotification struct {
@@ -83,13 +96,15 @@ type botProblemsNotification struct {
 }
 
 func newEpochReportNote(host string, baseID, quoteID uint32, report *EpochReport) *botProblemsNotification {
-	return &botProblemsNotification{
+	note := &botProblemsNotification{
 		Notification: db.NewNotification(NoteTypeEpochReport, "", "", "", db.Data),
 		Host:         host,
 		BaseID:       baseID,
 		QuoteID:      quoteID,
 		Report:       report,
 	}
+	note.NoteData = marketNoteData(host, baseID, quoteID)
+	return note
 }
 
 type cexProblemsNotification struct {
@@ -101,11 +116,13 @@ type cexProblemsNotification struct {
 }
 
 func newCexProblemsNote(host string, baseID, quoteID uint32, problems *CEXProblems) *cexProblemsNotification {
-	return &cexProblemsNotification{
+	note := &cexProblemsNotification{
 		Notification: db.NewNotification(NoteTypeCEXProblems, "", "", "", db.Data),
 		Host:         host,
 		BaseID:       baseID,
 		QuoteID:      quoteID,
 		Problems:     problems,
 	}
+	note.NoteData = marketNoteData(host, baseID, quoteID)
+	return note
 }
@@ -0,0 +1,194 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"decred.org/dcrdex/client/orderbook"
+)
+
+// EpochFillStats are the bot's fills and the quality of its quotes during an
+// epoch.
+type EpochFillStats struct {
+	// BuyFilledLots and SellFilledLots are the lots filled for each placement
+	// since the previous epoch's report.
+	BuyFilledLots  []uint64 `json:"buyFilledLots"`
+	SellFilledLots []uint64 `json:"sellFilledLots"`
+	// QuotedBuyLots and QuotedSellLots are the lots that the bot had standing
+	// or ordered on each side of the book.
+	QuotedBuyLots  uint64 `json:"quotedBuyLots"`
+	QuotedSellLots uint64 `json:"quotedSellLots"`
+	// BuyAtBest and SellAtBest are true if the bot's best booked order on the
+	// side was at the top of the book.
+	BuyAtBest  bool `json:"buyAtBest"`
+	SellAtBest bool `json:"sellAtBest"`
+}
+
+// QuoteQualityStats aggregates a bot's EpochFillStats over its run.
+type QuoteQualityStats struct {
+	Epochs         uint64   `json:"epochs"`
+	BuyFilledLots  []uint64 `json:"buyFilledLots"`
+	SellFilledLots []uint64 `json:"sellFilledLots"`
+	// QuotedBuyLots and QuotedSellLots are the sums of the lots quoted in
+	// each epoch.
+	QuotedBuyLots  uint64 `json:"quotedBuyLots"`
+	QuotedSellLots uint64 `json:"quotedSellLots"`
+	// BuyTimeAtBest and SellTimeAtBest are the ratios of epochs in which the
+	// bot's quote was at the top of the book.
+	BuyTimeAtBest  float64 `json:"buyTimeAtBest"`
+	SellTimeAtBest float64 `json:"sellTimeAtBest"`
+	// BuyQuoteToFill and SellQuoteToFill are the ratios of the lots quoted
+	// to the lots filled. They are zero if nothing was filled.
+	BuyQuoteToFill  float64 `json:"buyQuoteToFill"`
+	SellQuoteToFill float64 `json:"sellQuoteToFill"`
+
+	epochsAtBestBuy  uint64
+	epochsAtBestSell uint64
+}
+
+func (q *QuoteQualityStats) copy() *QuoteQualityStats {
+	c := *q
+	c.BuyFilledLots = append([]uint64(nil), q.BuyFilledLots...)
+	c.SellFilledLots = append([]uint64(nil), q.SellFilledLots...)
+	return &c
+}
+
+// add adds an epoch's stats to the aggregate.
+func (q *QuoteQualityStats) add(s *EpochFillStats) {
+	addLots := func(agg, lots []uint64) []uint64 {
+		for len(agg) < len(lots) {
+			agg = append(agg, 0)
+		}
+		for i, n := range lots {
+			agg[i] += n
+		}
+		return agg
+	}
+	sum := func(lots []uint64) (n uint64) {
+		for _, l := range lots {
+			n += l
+		}
+		return
+	}
+	ratio := func(a, b uint64) float64 {
+		if b == 0 {
+			return 0
+		}
+		return float64(a) / float64(b)
+	}
+
+	q.Epochs++
+	q.BuyFilledLots = addLots(q.BuyFilledLots, s.BuyFilledLots)
+	q.SellFilledLots = addLots(q.SellFilledLots, s.SellFilledLots)
+	q.QuotedBuyLots += s.QuotedBuyLots
+	q.QuotedSellLots += s.QuotedSellLots
+	if s.BuyAtBest {
+		q.epochsAtBestBuy++
+	}
+	if s.SellAtBest {
+		q.epochsAtBestSell++
+	}
+	q.BuyTimeAtBest = ratio(q.epochsAtBestBuy, q.Epochs)
+	q.SellTimeAtBest = ratio(q.epochsAtBestSell, q.Epochs)
+	q.BuyQuoteToFill = ratio(q.QuotedBuyLots, sum(q.BuyFilledLots))
+	q.SellQuoteToFill = ratio(q.QuotedSellLots, sum(q.SellFilledLots))
+}
+
+// recordFill records the filled quantity of an order for the epoch's
+// EpochFillStats.
+func (u *unifiedExchangeAdaptor) recordFill(sell bool, placementIndex, qty uint64) {
+	u.quoteQualityMtx.Lock()
+	defer u.quoteQualityMtx.Unlock()
+	side := 0
+	if sell {
+		side = 1
+	}
+	if u.epochFills[side] == nil {
+		u.epochFills[side] = make(map[uint64]uint64)
+	}
+	u.epochFills[side][placementIndex] += qty
+}
+
+// bestQuoter is satisfied by orderbook.OrderBook.
+type bestQuoter interface {
+	BestNOrders(n int, sell bool) ([]*orderbook.Order, bool, error)
+}
+
+// atBest checks whether the bot's best booked order on the side of the book is
+// at the top of the book.
+func (u *unifiedExchangeAdaptor) atBest(book bestQuoter, sell bool) bool {
+	best, _, err := book.BestNOrders(1, sell)
+	if err != nil || len(best) == 0 {
+		return false
+	}
+	for _, orders := range u.groupedBookedOrders(sell) {
+		for _, o := range orders {
+			rate := o.currentState().order.Rate
+			if (sell && rate <= best[0].Rate) || (!sell && rate >= best[0].Rate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// epochFillStats collects the fills since the previous epoch's report and the
+// quotes in the report, and adds them to the aggregate QuoteQualityStats.
+func (u *unifiedExchangeAdaptor) epochFillStats(report *EpochReport) *EpochFillStats {
+	s := new(EpochFillStats)
+	quoted := func(or *OrderReport) (lots uint64, nPlacements int) {
+		if or == nil {
+			return 0, 0
+		}
+		for _, p := range or.Placements {
+			lots += p.StandingLots + p.OrderedLots
+		}
+		return lots, len(or.Placements)
+	}
+	var nBuys, nSells int
+	s.QuotedBuyLots, nBuys = quoted(report.BuysReport)
+	s.QuotedSellLots, nSells = quoted(report.SellsReport)
+
+	if book, feed, err := u.clientCore.SyncBook(u.host, u.baseID, u.quoteID); err != nil {
+		u.log.Errorf("Error getting book for quote stats: %v", err)
+	} else if book != nil {
+		s.BuyAtBest = u.atBest(book, false)
+		s.SellAtBest = u.atBest(book, true)
+		if feed != nil {
+			feed.Close()
+		}
+	}
+
+	filledLots := func(fills map[uint64]uint64, n int) []uint64 {
+		for pi := range fills {
+			if int(pi) >= n {
+				n = int(pi) + 1
+			}
+		}
+		lots := make([]uint64, n)
+		for pi, qty := range fills {
+			lots[pi] = qty / u.lotSize
+		}
+		return lots
+	}
+
+	u.quoteQualityMtx.Lock()
+	defer u.quoteQualityMtx.Unlock()
+	s.BuyFilledLots = filledLots(u.epochFills[0], nBuys)
+	s.SellFilledLots = filledLots(u.epochFills[1], nSells)
+	u.epochFills = [2]map[uint64]uint64{}
+	if u.quoteQuality == nil {
+		u.quoteQuality = new(QuoteQualityStats)
+	}
+	u.quoteQuality.add(s)
+	return s
+}
+
+func (u *unifiedExchangeAdaptor) quoteQualityStats() *QuoteQualityStats {
+	u.quoteQualityMtx.Lock()
+	defer u.quoteQualityMtx.Unlock()
+	if u.quoteQuality == nil {
+		return new(QuoteQualityStats)
+	}
+	return u.quoteQuality.copy()
+}
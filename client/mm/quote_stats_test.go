@@ -0,0 +1,57 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuoteQualityStats(t *testing.T) {
+	u := &unifiedExchangeAdaptor{market: &market{lotSize: 1e8}, clientCore: newTCore()}
+
+	u.recordFill(false, 0, 2e8)
+	u.recordFill(true, 2, 1e8)
+	u.recordFill(false, 0, 1e8)
+	s := u.epochFillStats(&EpochReport{
+		BuysReport: &OrderReport{Placements: []*TradePlacement{
+			{StandingLots: 2, OrderedLots: 1},
+			{StandingLots: 3},
+		}},
+		SellsReport: &OrderReport{Placements: []*TradePlacement{
+			{OrderedLots: 2},
+		}},
+	})
+	if !reflect.DeepEqual(s.BuyFilledLots, []uint64{3, 0}) {
+		t.Fatalf("wrong buy filled lots %v", s.BuyFilledLots)
+	}
+	// A fill for a placement that is no longer reported is still counted.
+	if !reflect.DeepEqual(s.SellFilledLots, []uint64{0, 0, 1}) {
+		t.Fatalf("wrong sell filled lots %v", s.SellFilledLots)
+	}
+	if s.QuotedBuyLots != 6 || s.QuotedSellLots != 2 {
+		t.Fatalf("wrong quoted lots %d, %d", s.QuotedBuyLots, s.QuotedSellLots)
+	}
+
+	// The fills are reset for the next epoch.
+	s = u.epochFillStats(&EpochReport{
+		BuysReport: &OrderReport{Placements: []*TradePlacement{{StandingLots: 6}}},
+	})
+	if !reflect.DeepEqual(s.BuyFilledLots, []uint64{0}) || len(s.SellFilledLots) != 0 {
+		t.Fatalf("fills not reset: %v, %v", s.BuyFilledLots, s.SellFilledLots)
+	}
+
+	q := u.quoteQualityStats()
+	if q.Epochs != 2 || q.QuotedBuyLots != 12 || q.QuotedSellLots != 2 {
+		t.Fatalf("wrong aggregate %+v", q)
+	}
+	if q.BuyQuoteToFill != 4 || q.SellQuoteToFill != 2 {
+		t.Fatalf("wrong quote-to-fill ratios %f, %f", q.BuyQuoteToFill, q.SellQuoteToFill)
+	}
+
+	q.add(&EpochFillStats{BuyAtBest: true})
+	if q.BuyTimeAtBest != 1./3 || q.SellTimeAtBest != 0 {
+		t.Fatalf("wrong time at best %f, %f", q.BuyTimeAtBest, q.SellTimeAtBest)
+	}
+}
@@ -0,0 +1,54 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+// windDownCheckInterval is how often a winding down bot checks whether its
+// orders and transfers are settled.
+const windDownCheckInterval = time.Second * 10
+
+// settled is true if the bot has no pending orders, trades or transfers.
+func (u *unifiedExchangeAdaptor) settled() bool {
+	u.balancesMtx.RLock()
+	defer u.balancesMtx.RUnlock()
+	return len(u.pendingDEXOrders) == 0 && len(u.pendingCEXOrders) == 0 &&
+		len(u.pendingDeposits) == 0 && len(u.pendingWithdrawals) == 0
+}
+
+// windDown stops the bot from placing orders and making transfers, cancels its
+// booked orders, and waits until the swaps of its matches, its CEX trades and
+// its transfers are complete. Counter-trades for matches made before the
+// orders are canceled are still placed on the CEX. The bot's final balances
+// are logged and reported. windDown returns early if the bot is stopped.
+func (u *unifiedExchangeAdaptor) windDown() {
+	u.windingDown.Store(true)
+	u.log.Infof("Winding down. No new orders will be placed.")
+	u.cancelAllOrders(u.ctx)
+
+	ticker := time.NewTicker(windDownCheckInterval)
+	defer ticker.Stop()
+	for !u.settled() {
+		select {
+		case <-ticker.C:
+			// Orders that were booked after the cancellations, e.g. epoch
+			// orders, are canceled now.
+			u.tryCancelOrders(u.ctx, nil, false)
+		case <-u.ctx.Done():
+			return
+		}
+	}
+
+	if pl := u.stats().ProfitLoss; pl != nil {
+		for assetID, amt := range pl.Final {
+			u.log.Infof("Final %s balance: %s", dex.BipIDSymbol(assetID), amt.Fmt)
+		}
+		u.log.Infof("Wound down with a profit of %.2f USD", pl.Profit)
+	}
+	u.sendStatsUpdate()
+}
@@ -6,6 +6,7 @@ package orderbook
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"sort"
 	"sync"
 
@@ -27,9 +28,19 @@ type Fill struct {
 	Quantity uint64
 }
 
+// DepthLevel is the total quantity of the orders at a price level.
+type DepthLevel struct {
+	Rate      uint64
+	Quantity  uint64
+	NumOrders int
+}
+
 // bookSide represents a side of the order book.
 type bookSide struct {
-	bins      map[uint64][]*Order
+	bins map[uint64][]*Order
+	// binQty is the total quantity of the orders in each bin, maintained as
+	// orders are added, removed, and updated.
+	binQty    map[uint64]uint64
 	rateIndex *rateIndex
 	orderPref orderPreference
 	mtx       sync.RWMutex
@@ -39,6 +50,7 @@ type bookSide struct {
 func newBookSide(pref orderPreference) *bookSide {
 	return &bookSide{
 		bins:      make(map[uint64][]*Order),
+		binQty:    make(map[uint64]uint64),
 		rateIndex: newRateIndex(),
 		orderPref: pref,
 	}
@@ -48,6 +60,7 @@ func newBookSide(pref orderPreference) *bookSide {
 func (d *bookSide) reset() {
 	d.mtx.Lock()
 	d.bins = make(map[uint64][]*Order)
+	d.binQty = make(map[uint64]uint64)
 	d.rateIndex = newRateIndex()
 	d.mtx.Unlock()
 }
@@ -77,6 +90,7 @@ func (d *bookSide) Add(order *Order) {
 	copy(bin[i+1:], bin[i:])
 	bin[i] = order
 	d.bins[order.Rate] = bin
+	d.binQty[order.Rate] += order.Quantity
 
 	// Update the sort order if a new order group is created.
 	if !exists {
@@ -97,6 +111,7 @@ func (d *bookSide) Remove(oid order.OrderID, rateBin uint64) error {
 
 	for i := range bin {
 		if oid == bin[i].OrderID {
+			d.binQty[rateBin] -= bin[i].Quantity
 			// Remove the entry and preserve the sort order.
 			if i < len(bin)-1 {
 				copy(bin[i:], bin[i+1:])
@@ -107,6 +122,7 @@ func (d *bookSide) Remove(oid order.OrderID, rateBin uint64) error {
 			// Delete the bin if there are no orders left in it.
 			if len(bin) == 0 {
 				delete(d.bins, rateBin)
+				delete(d.binQty, rateBin)
 				return d.rateIndex.Remove(rateBin)
 			}
 
@@ -130,6 +146,7 @@ func (d *bookSide) UpdateRemaining(oid order.OrderID, rateBin, remaining uint64)
 			newOrder := *ord // deep copy
 			newOrder.Quantity = remaining
 			bin[i] = &newOrder
+			d.binQty[rateBin] = d.binQty[rateBin] - ord.Quantity + remaining
 			return
 		}
 	}
@@ -224,6 +241,92 @@ func (d *bookSide) bestFill(quantity uint64, convert bool, lotSize uint64) ([]*F
 	return best, remainingQty == 0
 }
 
+// Depth returns up to n price levels, best first, with the rates aggregated to
+// multiples of tick. Sell rates are rounded up and buy rates are rounded down,
+// so that a level's rate is never better than the rates of its orders. A tick
+// of zero or one does not aggregate.
+func (d *bookSide) Depth(n int, tick uint64) []*DepthLevel {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	levels := make([]*DepthLevel, 0, n)
+	calcIdx := d.idxCalculator()
+	for ir := range d.rateIndex.Rates {
+		rate := d.rateIndex.Rates[calcIdx(ir)]
+		levelRate := rate
+		if tick > 1 {
+			levelRate = rate - rate%tick
+			if d.orderPref == ascending && levelRate != rate {
+				levelRate += tick
+			}
+		}
+		if len(levels) > 0 && levels[len(levels)-1].Rate == levelRate {
+			lvl := levels[len(levels)-1]
+			lvl.Quantity += d.binQty[rate]
+			lvl.NumOrders += len(d.bins[rate])
+			continue
+		}
+		if len(levels) == n {
+			break
+		}
+		levels = append(levels, &DepthLevel{
+			Rate:      levelRate,
+			Quantity:  d.binQty[rate],
+			NumOrders: len(d.bins[rate]),
+		})
+	}
+	return levels
+}
+
+// CumulativeDepth is the total quantity of the orders with rates at least as
+// good as the specified rate, i.e. at or below the rate for sells, and at or
+// above the rate for buys.
+func (d *bookSide) CumulativeDepth(rate uint64) uint64 {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	rates := d.rateIndex.Rates
+	var start, end int
+	if d.orderPref == ascending {
+		end = sort.Search(len(rates), func(i int) bool { return rates[i] > rate })
+	} else {
+		start = sort.Search(len(rates), func(i int) bool { return rates[i] >= rate })
+		end = len(rates)
+	}
+	var qty uint64
+	for _, r := range rates[start:end] {
+		qty += d.binQty[r]
+	}
+	return qty
+}
+
+// vwap walks the book side until qty is filled, returning the volume weighted
+// average rate and the worst rate of the fill, and the quantity filled, which
+// is less than qty if the book side is too thin.
+func (d *bookSide) vwap(qty uint64) (avg, worst, filledQty uint64) {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	// The sum of rate * quantity can overflow a uint64.
+	weightedSum, product := new(big.Int), new(big.Int)
+	calcIdx := d.idxCalculator()
+	for ir := range d.rateIndex.Rates {
+		if filledQty == qty {
+			break
+		}
+		rate := d.rateIndex.Rates[calcIdx(ir)]
+		binQty := min(d.binQty[rate], qty-filledQty)
+		product.SetUint64(rate)
+		weightedSum.Add(weightedSum, product.Mul(product, new(big.Int).SetUint64(binQty)))
+		filledQty += binQty
+		worst = rate
+	}
+	if filledQty == 0 {
+		return 0, 0, 0
+	}
+	return weightedSum.Div(weightedSum, new(big.Int).SetUint64(filledQty)).Uint64(), worst, filledQty
+}
+
 func (d *bookSide) idxCalculator() func(i int) int {
 	if d.orderPref == ascending {
 		return func(i int) int { return i }
@@ -11,8 +11,15 @@ import (
 // makeBookSideDepth creates a new book side depth from the provided
 // group and sort order.
 func makeBookSide(groups map[uint64][]*Order, rateIndex *rateIndex, orderPref orderPreference) *bookSide {
+	binQty := make(map[uint64]uint64, len(groups))
+	for rate, bin := range groups {
+		for _, ord := range bin {
+			binQty[rate] += ord.Quantity
+		}
+	}
 	return &bookSide{
 		bins:      groups,
+		binQty:    binQty,
 		rateIndex: rateIndex,
 		orderPref: orderPref,
 	}
@@ -838,3 +845,59 @@ func TestBookSideBestFill(t *testing.T) {
 		}
 	}
 }
+
+func TestBookSideDepth(t *testing.T) {
+	sells := newBookSide(ascending)
+	buys := newBookSide(descending)
+	for i, o := range []struct {
+		qty, rate uint64
+	}{{5, 100}, {3, 100}, {2, 105}, {7, 110}, {1, 121}} {
+		sells.Add(makeOrder([32]byte{byte(i)}, msgjson.SellOrderNum, o.qty, o.rate, 10))
+		buys.Add(makeOrder([32]byte{byte(i)}, msgjson.BuyOrderNum, o.qty, o.rate, 10))
+	}
+
+	checkLevels := func(tag string, levels []*DepthLevel, exp []DepthLevel) {
+		t.Helper()
+		if len(levels) != len(exp) {
+			t.Fatalf("%s: expected %d levels, got %d", tag, len(exp), len(levels))
+		}
+		for i, lvl := range levels {
+			if *lvl != exp[i] {
+				t.Fatalf("%s: level %d: expected %+v, got %+v", tag, i, exp[i], *lvl)
+			}
+		}
+	}
+
+	checkLevels("sells", sells.Depth(10, 0), []DepthLevel{{100, 8, 2}, {105, 2, 1}, {110, 7, 1}, {121, 1, 1}})
+	checkLevels("sells n", sells.Depth(2, 1), []DepthLevel{{100, 8, 2}, {105, 2, 1}})
+	checkLevels("sells tick", sells.Depth(10, 10), []DepthLevel{{100, 8, 2}, {110, 9, 2}, {130, 1, 1}})
+	checkLevels("buys", buys.Depth(2, 0), []DepthLevel{{121, 1, 1}, {110, 7, 1}})
+	checkLevels("buys tick", buys.Depth(10, 10), []DepthLevel{{120, 1, 1}, {110, 7, 1}, {100, 10, 3}})
+
+	// Levels are updated with the book.
+	sells.UpdateRemaining([32]byte{0}, 100, 1)
+	if err := sells.Remove([32]byte{3}, 110); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+	checkLevels("updated sells", sells.Depth(10, 10), []DepthLevel{{100, 4, 2}, {110, 2, 1}, {130, 1, 1}})
+
+	for _, tt := range []struct {
+		side *bookSide
+		rate uint64
+		exp  uint64
+	}{
+		{sells, 99, 0},
+		{sells, 100, 4},
+		{sells, 120, 6},
+		{sells, 200, 7},
+		{buys, 200, 0},
+		{buys, 121, 1},
+		{buys, 105, 10},
+		{buys, 1, 18},
+	} {
+		if qty := tt.side.CumulativeDepth(tt.rate); qty != tt.exp {
+			t.Fatalf("wrong cumulative depth at %d for ascending = %t: expected %d, got %d",
+				tt.rate, tt.side.orderPref == ascending, tt.exp, qty)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package orderbook
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/matcher/mt19937"
+	"github.com/decred/dcrd/crypto/blake256"
+)
+
+// VerifyEpochProof checks that the epoch_proof's commitment checksum and
+// shuffling seed are consistent with its orders, and that each revealed
+// preimage matches its order's commitment. The matching order of the revealed
+// orders is recomputed with the same deterministic shuffle used by the server's
+// matching engine, and their IDs are returned in that order.
+func VerifyEpochProof(proof *msgjson.EpochProof) ([]order.OrderID, error) {
+	type revealedOrder struct {
+		id   order.OrderID
+		pimg order.Preimage
+	}
+	commits := make([]order.Commitment, 0, len(proof.Orders))
+	revealed := make([]*revealedOrder, 0, len(proof.Orders))
+	seen := make(map[order.OrderID]bool, len(proof.Orders))
+	for _, ord := range proof.Orders {
+		if len(ord.OrderID) != order.OrderIDSize {
+			return nil, fmt.Errorf("expected order id length of %d, got %d",
+				order.OrderIDSize, len(ord.OrderID))
+		}
+		var oid order.OrderID
+		copy(oid[:], ord.OrderID)
+		if seen[oid] {
+			return nil, fmt.Errorf("order %s repeated in epoch %d proof", oid, proof.Epoch)
+		}
+		seen[oid] = true
+		if len(ord.Commit) != order.CommitmentSize {
+			return nil, fmt.Errorf("expected commitment length of %d, got %d for order %s",
+				order.CommitmentSize, len(ord.Commit), oid)
+		}
+		var commit order.Commitment
+		copy(commit[:], ord.Commit)
+		commits = append(commits, commit)
+
+		if len(ord.Preimage) == 0 {
+			continue // missed preimage
+		}
+		if len(ord.Preimage) != order.PreimageSize {
+			return nil, fmt.Errorf("expected preimage length of %d, got %d for order %s",
+				order.PreimageSize, len(ord.Preimage), oid)
+		}
+		var pimg order.Preimage
+		copy(pimg[:], ord.Preimage)
+		if pimg.Commit() != commit {
+			return nil, fmt.Errorf("preimage %x does not match commitment %s for order %s",
+				pimg, commit, oid)
+		}
+		revealed = append(revealed, &revealedOrder{oid, pimg})
+	}
+
+	// The commitment checksum covers all epoch orders, including misses.
+	sort.Slice(commits, func(i, j int) bool {
+		return bytes.Compare(commits[i][:], commits[j][:]) < 0
+	})
+	comH := blake256.New()
+	for i := range commits {
+		comH.Write(commits[i][:])
+	}
+	if csum := comH.Sum(nil); !bytes.Equal(csum, proof.CSum) {
+		return nil, fmt.Errorf("epoch %d proof csum mismatch: expected %x, computed %x",
+			proof.Epoch, proof.CSum, csum)
+	}
+
+	// There is no seed if no preimages were revealed.
+	if len(revealed) == 0 {
+		if len(proof.Seed) != 0 {
+			return nil, fmt.Errorf("epoch %d proof has a seed but no preimages", proof.Epoch)
+		}
+		return nil, nil
+	}
+
+	// The seed is the hash of the preimages, sorted by order ID.
+	sort.Slice(revealed, func(i, j int) bool {
+		return bytes.Compare(revealed[i].id[:], revealed[j].id[:]) < 0
+	})
+	piH := blake256.New()
+	for _, ord := range revealed {
+		piH.Write(ord.pimg[:])
+	}
+	seed := piH.Sum(nil)
+	if !bytes.Equal(seed, proof.Seed) {
+		return nil, fmt.Errorf("epoch %d proof seed mismatch: expected %x, computed %x",
+			proof.Epoch, proof.Seed, seed)
+	}
+
+	// Fisher-Yates shuffle with MT19937 seeded with the seed, as done by the
+	// server's matcher.
+	mtSrc := mt19937.NewSource()
+	mtSrc.SeedBytes(seed)
+	prng := rand.New(mtSrc)
+	for i := range revealed {
+		j := prng.Intn(len(revealed)-i) + i
+		revealed[i], revealed[j] = revealed[j], revealed[i]
+	}
+
+	shuffled := make([]order.OrderID, 0, len(revealed))
+	for _, ord := range revealed {
+		shuffled = append(shuffled, ord.id)
+	}
+	return shuffled, nil
+}
+
+// EpochMatch is a match made by the matching cycle of an epoch. For a cancel
+// order, Taker is the cancel order, and the Quantity and Rate are the remaining
+// quantity and rate of the canceled Maker order.
+type EpochMatch struct {
+	Taker    order.OrderID
+	Maker    order.OrderID
+	Quantity uint64
+	Rate     uint64
+}
+
+// ID computes the match ID, as done by the server for the matches it sends.
+func (m *EpochMatch) ID() order.MatchID {
+	b := make([]byte, 0, 2*order.OrderIDSize+8+8)
+	b = append(b, m.Taker[:]...)
+	b = append(b, m.Maker[:]...)
+	b = binary.BigEndian.AppendUint64(b, m.Quantity)
+	b = binary.BigEndian.AppendUint64(b, m.Rate)
+	return blake256.Sum256(b)
+}
+
+// matchingOrder is a standing limit order in the book used by MatchEpoch.
+type matchingOrder struct {
+	id        order.OrderID
+	rate      uint64
+	time      uint64
+	remaining uint64
+}
+
+// matchingSide is one side of the book used by MatchEpoch, with the best order
+// first. Orders are sorted by rate, then time, then order ID, as done by the
+// server's book.
+type matchingSide struct {
+	sell   bool
+	orders []*matchingOrder
+}
+
+func newMatchingSide(ords []*Order, sell bool) *matchingSide {
+	side := &matchingSide{
+		sell:   sell,
+		orders: make([]*matchingOrder, 0, len(ords)),
+	}
+	for _, ord := range ords {
+		side.orders = append(side.orders, &matchingOrder{
+			id:        ord.OrderID,
+			rate:      ord.Rate,
+			time:      ord.Time,
+			remaining: ord.Quantity,
+		})
+	}
+	sort.Slice(side.orders, func(i, j int) bool {
+		return side.better(side.orders[i], side.orders[j])
+	})
+	return side
+}
+
+// better is true if order a has priority over order b.
+func (s *matchingSide) better(a, b *matchingOrder) bool {
+	if a.rate != b.rate {
+		if s.sell {
+			return a.rate < b.rate
+		}
+		return a.rate > b.rate
+	}
+	if a.time != b.time {
+		return a.time < b.time
+	}
+	return bytes.Compare(a.id[:], b.id[:]) < 0
+}
+
+func (s *matchingSide) best() *matchingOrder {
+	if len(s.orders) == 0 {
+		return nil
+	}
+	return s.orders[0]
+}
+
+func (s *matchingSide) insert(ord *matchingOrder) {
+	i := sort.Search(len(s.orders), func(i int) bool {
+		return s.better(ord, s.orders[i])
+	})
+	s.orders = append(s.orders, nil)
+	copy(s.orders[i+1:], s.orders[i:])
+	s.orders[i] = ord
+}
+
+func (s *matchingSide) remove(oid order.OrderID) *matchingOrder {
+	for i, ord := range s.orders {
+		if ord.id == oid {
+			s.orders = append(s.orders[:i], s.orders[i+1:]...)
+			return ord
+		}
+	}
+	return nil
+}
+
+// MatchEpoch reruns the server's matching cycle for an epoch. The proof's
+// revealed orders are matched in their shuffled order, as returned by
+// VerifyEpochProof, against the book's standing buy and sell orders from before
+// the epoch was matched. The matches are returned in the order they were made.
+func MatchEpoch(proof *msgjson.EpochProof, shuffled []order.OrderID, buys, sells []*Order, lotSize uint64) ([]*EpochMatch, error) {
+	if lotSize == 0 {
+		return nil, fmt.Errorf("zero lot size")
+	}
+	proofOrders := make(map[order.OrderID]*msgjson.EpochProofOrder, len(proof.Orders))
+	for _, ord := range proof.Orders {
+		var oid order.OrderID
+		copy(oid[:], ord.OrderID)
+		proofOrders[oid] = ord
+	}
+	bookBuys, bookSells := newMatchingSide(buys, false), newMatchingSide(sells, true)
+
+	var matches []*EpochMatch
+	// matchLimit matches the taker against the best opposing orders until it is
+	// filled or the rate no longer crosses, returning its unfilled quantity. A
+	// zero rate is a sell at any rate.
+	matchLimit := func(taker order.OrderID, sell bool, rate, qty uint64) uint64 {
+		makers, crosses := bookSells, func(makerRate uint64) bool { return makerRate <= rate }
+		if sell {
+			makers, crosses = bookBuys, func(makerRate uint64) bool { return rate <= makerRate }
+		}
+		for qty > 0 {
+			best := makers.best()
+			if best == nil || !crosses(best.rate) {
+				break
+			}
+			amt := best.remaining
+			if qty < amt {
+				amt = qty
+			} else {
+				makers.remove(best.id)
+			}
+			best.remaining -= amt
+			qty -= amt
+			matches = append(matches, &EpochMatch{
+				Taker:    taker,
+				Maker:    best.id,
+				Quantity: amt,
+				Rate:     best.rate,
+			})
+		}
+		return qty
+	}
+	// matchMarketBuy matches a market buy, with its quantity in units of the
+	// quote asset, against the best sell orders while the remaining quantity
+	// buys at least one lot.
+	matchMarketBuy := func(taker order.OrderID, qty uint64) {
+		for qty > 0 {
+			best := bookSells.best()
+			if best == nil {
+				return
+			}
+			qtyBase := calc.QuoteToBase(best.rate, qty)
+			if qtyBase < lotSize {
+				return
+			}
+			amt := best.remaining
+			if qtyBase < amt {
+				amt = qtyBase - qtyBase%lotSize
+			} else {
+				bookSells.remove(best.id)
+			}
+			best.remaining -= amt
+			qty -= calc.BaseToQuote(best.rate, amt)
+			matches = append(matches, &EpochMatch{
+				Taker:    taker,
+				Maker:    best.id,
+				Quantity: amt,
+				Rate:     best.rate,
+			})
+		}
+	}
+
+	for _, oid := range shuffled {
+		ord := proofOrders[oid]
+		if ord == nil {
+			return nil, fmt.Errorf("shuffled order %s not in epoch %d proof", oid, proof.Epoch)
+		}
+		var sell bool
+		switch ord.Side {
+		case msgjson.BuyOrderNum:
+		case msgjson.SellOrderNum:
+			sell = true
+		default:
+			if ord.OrderType != msgjson.CancelOrderNum {
+				return nil, fmt.Errorf("unknown side %d for order %s", ord.Side, oid)
+			}
+		}
+
+		switch ord.OrderType {
+		case msgjson.CancelOrderNum:
+			if len(ord.TargetID) != order.OrderIDSize {
+				return nil, fmt.Errorf("expected target id length of %d, got %d for cancel order %s",
+					order.OrderIDSize, len(ord.TargetID), oid)
+			}
+			var targetID order.OrderID
+			copy(targetID[:], ord.TargetID)
+			target := bookBuys.remove(targetID)
+			if target == nil {
+				target = bookSells.remove(targetID)
+			}
+			if target == nil {
+				continue // target not booked, or down queue
+			}
+			matches = append(matches, &EpochMatch{
+				Taker:    oid,
+				Maker:    targetID,
+				Quantity: target.remaining,
+				Rate:     target.rate,
+			})
+
+		case msgjson.LimitOrderNum:
+			if ord.Quantity%lotSize != 0 {
+				continue // fails
+			}
+			remaining := matchLimit(oid, sell, ord.Rate, ord.Quantity)
+			if remaining > 0 && ord.TiF == msgjson.StandingOrderNum {
+				side := bookBuys
+				if sell {
+					side = bookSells
+				}
+				side.insert(&matchingOrder{
+					id:        oid,
+					rate:      ord.Rate,
+					time:      ord.Time,
+					remaining: remaining,
+				})
+			}
+
+		case msgjson.MarketOrderNum:
+			if !sell {
+				matchMarketBuy(oid, ord.Quantity)
+				continue
+			}
+			if ord.Quantity%lotSize != 0 {
+				continue // fails
+			}
+			// A market sell is an immediate limit sell at any rate.
+			matchLimit(oid, true, 0, ord.Quantity)
+
+		default:
+			return nil, fmt.Errorf("unknown order type %d for order %s", ord.OrderType, oid)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,338 @@
+package orderbook
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/book"
+	"decred.org/dcrdex/server/matcher"
+)
+
+func TestVerifyEpochProof(t *testing.T) {
+	const epochIdx = 1234
+	makeProof := func(nOrders, nMisses int) (*msgjson.EpochProof, []*matcher.OrderRevealed) {
+		proof := &msgjson.EpochProof{MarketID: "dcr_btc", Epoch: epochIdx}
+		revealed := make([]*matcher.OrderRevealed, 0, nOrders)
+		commits := make([]order.Commitment, 0, nOrders+nMisses)
+		for i := 0; i < nOrders+nMisses; i++ {
+			var pimg order.Preimage
+			copy(pimg[:], encode.RandomBytes(order.PreimageSize))
+			commit := pimg.Commit()
+			lo := &order.LimitOrder{
+				P: order.Prefix{
+					OrderType:  order.LimitOrderType,
+					ClientTime: time.UnixMilli(int64(i)),
+					ServerTime: time.UnixMilli(int64(i)),
+					Commit:     commit,
+				},
+				T:    order.Trade{Quantity: uint64(i + 1)},
+				Rate: 1e8,
+			}
+			oid := lo.ID()
+			ord := &msgjson.EpochProofOrder{
+				EpochOrderNote: *makeEpochOrderNote("dcr_btc", oid, msgjson.BuyOrderNum, 1e8, uint64(i+1), commit, epochIdx),
+			}
+			commits = append(commits, commit)
+			if i < nOrders {
+				ord.Preimage = pimg[:]
+				revealed = append(revealed, &matcher.OrderRevealed{Order: lo, Preimage: pimg})
+			}
+			proof.Orders = append(proof.Orders, ord)
+		}
+		// The seed is computed from the preimages sorted by order ID.
+		sorted := make([]*matcher.OrderRevealed, len(revealed))
+		copy(sorted, revealed)
+		sort.Slice(sorted, func(i, j int) bool {
+			ii, ij := sorted[i].Order.ID(), sorted[j].Order.ID()
+			return bytes.Compare(ii[:], ij[:]) < 0
+		})
+		pimgs := make([]order.Preimage, 0, nOrders)
+		for _, or := range sorted {
+			pimgs = append(pimgs, or.Preimage)
+		}
+		matcher.ShuffleQueue(revealed)
+		seed, csum := makeMatchProof(pimgs, commits)
+		proof.CSum = csum
+		if nOrders > 0 {
+			proof.Seed = seed
+		}
+		return proof, revealed
+	}
+
+	checkShuffle := func(proof *msgjson.EpochProof, revealed []*matcher.OrderRevealed) {
+		t.Helper()
+		shuffled, err := VerifyEpochProof(proof)
+		if err != nil {
+			t.Fatalf("VerifyEpochProof error: %v", err)
+		}
+		if len(shuffled) != len(revealed) {
+			t.Fatalf("expected %d shuffled orders, got %d", len(revealed), len(shuffled))
+		}
+		for i, oid := range shuffled {
+			if oid != revealed[i].Order.ID() {
+				t.Fatalf("shuffled order %d is %s, matcher has %s", i, oid, revealed[i].Order.ID())
+			}
+		}
+	}
+
+	// Only misses.
+	proof, revealed := makeProof(0, 2)
+	checkShuffle(proof, revealed)
+
+	proof, revealed = makeProof(12, 3)
+	checkShuffle(proof, revealed)
+
+	// Wrong csum.
+	proof.CSum = encode.RandomBytes(32)
+	if _, err := VerifyEpochProof(proof); err == nil {
+		t.Fatalf("no error for bad csum")
+	}
+
+	// Wrong seed.
+	proof, _ = makeProof(5, 0)
+	proof.Seed = encode.RandomBytes(32)
+	if _, err := VerifyEpochProof(proof); err == nil {
+		t.Fatalf("no error for bad seed")
+	}
+
+	// Preimage not matching the commitment.
+	proof, _ = makeProof(5, 0)
+	proof.Orders[2].Preimage = encode.RandomBytes(order.PreimageSize)
+	if _, err := VerifyEpochProof(proof); err == nil {
+		t.Fatalf("no error for bad preimage")
+	}
+
+	// Repeated order.
+	proof, _ = makeProof(5, 0)
+	proof.Orders = append(proof.Orders, proof.Orders[0])
+	if _, err := VerifyEpochProof(proof); err == nil {
+		t.Fatalf("no error for repeated order")
+	}
+}
+
+// epochProofOrder creates the epoch_proof entry for an order, as the server's
+// book router does.
+func epochProofOrder(ord order.Order, pimg *order.Preimage) *msgjson.EpochProofOrder {
+	oid, commit := ord.ID(), ord.Commitment()
+	note := &msgjson.EpochProofOrder{
+		EpochOrderNote: msgjson.EpochOrderNote{
+			BookOrderNote: msgjson.BookOrderNote{
+				OrderNote: msgjson.OrderNote{OrderID: oid[:]},
+				TradeNote: msgjson.TradeNote{Time: uint64(ord.Time())},
+			},
+			Commit: commit[:],
+		},
+	}
+	if pimg != nil {
+		note.Preimage = pimg[:]
+	}
+	if trade := ord.Trade(); trade != nil {
+		note.Side = msgjson.BuyOrderNum
+		if trade.Sell {
+			note.Side = msgjson.SellOrderNum
+		}
+		note.Quantity = trade.Remaining()
+	}
+	switch o := ord.(type) {
+	case *order.LimitOrder:
+		note.OrderType = msgjson.LimitOrderNum
+		note.Rate = o.Rate
+		note.TiF = msgjson.StandingOrderNum
+		if o.Force == order.ImmediateTiF {
+			note.TiF = msgjson.ImmediateOrderNum
+		}
+	case *order.MarketOrder:
+		note.OrderType = msgjson.MarketOrderNum
+	case *order.CancelOrder:
+		note.OrderType = msgjson.CancelOrderNum
+		note.TargetID = o.TargetOrderID[:]
+	}
+	return note
+}
+
+func TestMatchEpoch(t *testing.T) {
+	const lotSize = 1e6
+	const rateStep = 1e5
+	const midRate = 1e8
+	rnd := rand.New(rand.NewSource(1))
+	var acctID account.AccountID
+	// All orders share a few server times so that ties are broken by order ID.
+	prefix := func(orderType order.OrderType, pimg order.Preimage) order.Prefix {
+		copy(acctID[:], encode.RandomBytes(len(acctID)))
+		stamp := time.UnixMilli(int64(rnd.Intn(4)))
+		return order.Prefix{
+			AccountID:  acctID,
+			OrderType:  orderType,
+			ClientTime: stamp,
+			ServerTime: stamp,
+			Commit:     pimg.Commit(),
+		}
+	}
+	newPreimage := func() (pimg order.Preimage) {
+		copy(pimg[:], encode.RandomBytes(order.PreimageSize))
+		return
+	}
+	newLimit := func(sell bool, rate, qty uint64, force order.TimeInForce) (*order.LimitOrder, order.Preimage) {
+		pimg := newPreimage()
+		return &order.LimitOrder{
+			P:     prefix(order.LimitOrderType, pimg),
+			T:     order.Trade{Sell: sell, Quantity: qty},
+			Rate:  rate,
+			Force: force,
+		}, pimg
+	}
+	randLots := func(n int) uint64 {
+		return uint64(rnd.Intn(n)+1) * lotSize
+	}
+
+	for i := 0; i < 50; i++ {
+		srvBook := book.New(lotSize, 0)
+		var buys, sells []*Order
+		var booked []*order.LimitOrder
+		for j := 0; j < 20; j++ {
+			sell := j%2 == 0
+			rate := midRate - uint64(rnd.Intn(10)+1)*rateStep
+			if sell {
+				rate = midRate + uint64(rnd.Intn(10))*rateStep
+			}
+			lo, _ := newLimit(sell, rate, randLots(10), order.StandingTiF)
+			if rnd.Intn(3) == 0 {
+				lo.FillAmt = lo.Quantity - lotSize
+			}
+			srvBook.Insert(lo)
+			booked = append(booked, lo)
+			bookOrd := &Order{
+				OrderID:  lo.ID(),
+				Side:     msgjson.BuyOrderNum,
+				Quantity: lo.Remaining(),
+				Rate:     lo.Rate,
+				Time:     uint64(lo.Time()),
+			}
+			if sell {
+				bookOrd.Side = msgjson.SellOrderNum
+				sells = append(sells, bookOrd)
+			} else {
+				buys = append(buys, bookOrd)
+			}
+		}
+
+		proof := &msgjson.EpochProof{Epoch: uint64(i)}
+		var queue []*matcher.OrderRevealed
+		var epochLimits []*order.LimitOrder
+		for j := 0; j < 20; j++ {
+			var ord order.Order
+			var pimg order.Preimage
+			switch k := rnd.Intn(10); {
+			case k < 5:
+				force := order.StandingTiF
+				if k == 0 {
+					force = order.ImmediateTiF
+				}
+				rate := midRate + uint64(rnd.Intn(21))*rateStep - 10*rateStep
+				qty := randLots(15)
+				if rnd.Intn(20) == 0 {
+					qty++ // bad lot size
+				}
+				var lo *order.LimitOrder
+				lo, pimg = newLimit(rnd.Intn(2) == 0, rate, qty, force)
+				epochLimits = append(epochLimits, lo)
+				ord = lo
+			case k < 7:
+				pimg = newPreimage()
+				mo := &order.MarketOrder{
+					P: prefix(order.MarketOrderType, pimg),
+					T: order.Trade{Sell: k == 5, Quantity: randLots(15)},
+				}
+				if !mo.Sell {
+					mo.Quantity = calc.BaseToQuote(midRate, mo.Quantity) + uint64(rnd.Intn(lotSize))
+				}
+				ord = mo
+			default:
+				// Cancel a booked order or an order earlier in the queue,
+				// which may not be booked when the cancel is matched.
+				target := booked[rnd.Intn(len(booked))]
+				if len(epochLimits) > 0 && k == 9 {
+					target = epochLimits[rnd.Intn(len(epochLimits))]
+				}
+				pimg = newPreimage()
+				ord = &order.CancelOrder{
+					P:             prefix(order.CancelOrderType, pimg),
+					TargetOrderID: target.ID(),
+				}
+			}
+			if rnd.Intn(10) == 0 { // missed preimage
+				proof.Orders = append(proof.Orders, epochProofOrder(ord, nil))
+				continue
+			}
+			proof.Orders = append(proof.Orders, epochProofOrder(ord, &pimg))
+			queue = append(queue, &matcher.OrderRevealed{Order: ord, Preimage: pimg})
+		}
+
+		commits := make([]order.Commitment, 0, len(proof.Orders))
+		for _, ord := range proof.Orders {
+			var commit order.Commitment
+			copy(commit[:], ord.Commit)
+			commits = append(commits, commit)
+		}
+		sorted := make([]*matcher.OrderRevealed, len(queue))
+		copy(sorted, queue)
+		sort.Slice(sorted, func(i, j int) bool {
+			ii, ij := sorted[i].Order.ID(), sorted[j].Order.ID()
+			return bytes.Compare(ii[:], ij[:]) < 0
+		})
+		pimgs := make([]order.Preimage, 0, len(sorted))
+		for _, or := range sorted {
+			pimgs = append(pimgs, or.Preimage)
+		}
+		proof.Seed, proof.CSum = makeMatchProof(pimgs, commits)
+
+		_, matchSets, _, _, _, _, _, _, _, _, _ := matcher.New().Match(srvBook, queue)
+		var expMatches []*EpochMatch
+		var expIDs []order.MatchID
+		for _, set := range matchSets {
+			for k, maker := range set.Makers {
+				expMatches = append(expMatches, &EpochMatch{
+					Taker:    set.Taker.ID(),
+					Maker:    maker.ID(),
+					Quantity: set.Amounts[k],
+					Rate:     set.Rates[k],
+				})
+				match := &order.Match{
+					Taker:    set.Taker,
+					Maker:    maker,
+					Quantity: set.Amounts[k],
+					Rate:     set.Rates[k],
+				}
+				expIDs = append(expIDs, match.ID())
+			}
+		}
+
+		shuffled, err := VerifyEpochProof(proof)
+		if err != nil {
+			t.Fatalf("VerifyEpochProof error: %v", err)
+		}
+		matches, err := MatchEpoch(proof, shuffled, buys, sells, lotSize)
+		if err != nil {
+			t.Fatalf("MatchEpoch error: %v", err)
+		}
+		if len(matches) != len(expMatches) {
+			t.Fatalf("epoch %d: expected %d matches, got %d", i, len(expMatches), len(matches))
+		}
+		for j, m := range matches {
+			if *m != *expMatches[j] {
+				t.Fatalf("epoch %d match %d: expected %+v, got %+v", i, j, expMatches[j], m)
+			}
+			if m.ID() != expIDs[j] {
+				t.Fatalf("epoch %d match %d: wrong match ID", i, j)
+			}
+		}
+	}
+}
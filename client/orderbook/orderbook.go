@@ -7,8 +7,10 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"math/big"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
@@ -59,6 +61,15 @@ type rateSell struct {
 	sell bool
 }
 
+const (
+	// tradeTapeCapacity is the maximum number of recent matches retained for
+	// order-flow statistics.
+	tradeTapeCapacity = 1000
+	// recentMatchesLength is the maximum number of matches returned by
+	// RecentMatches.
+	recentMatchesLength = 100
+)
+
 // MatchSummary summarizes one or more consecutive matches at a given rate and
 // buy/sell direction. Consecutive matches of the same rate and direction are
 // binned by the server.
@@ -155,6 +166,18 @@ func (ob *OrderBook) setSeq(seq uint64) {
 	}
 }
 
+// Stale checks if a sequenced update has already been applied, such as an
+// update that was included in the snapshot. Updates received before the book
+// is synced are cached and are never stale.
+func (ob *OrderBook) Stale(seq uint64) bool {
+	if !ob.isSynced() {
+		return false
+	}
+	ob.seqMtx.Lock()
+	defer ob.seqMtx.Unlock()
+	return seq <= ob.seq
+}
+
 // cacheOrderNote caches an order note.
 func (ob *OrderBook) cacheOrderNote(route string, entry any) error {
 	note := new(cachedOrderNote)
@@ -586,33 +609,53 @@ func (ob *OrderBook) OrderIsBooked(oid order.OrderID, sell bool) bool {
 	return findOrder(epochOrders)
 }
 
+// VWAPFill describes the fill of a quantity of base asset against one side of
+// the order book.
+type VWAPFill struct {
+	// AvgRate is the volume weighted average rate of the fill.
+	AvgRate uint64
+	// WorstRate is the rate of the last order used in the fill.
+	WorstRate uint64
+	// Quantity is the quantity filled. If Filled is false, this is the depth
+	// of the entire book side.
+	Quantity uint64
+	// Filled is true if the book side had enough depth for the requested
+	// quantity.
+	Filled bool
+}
+
+// VWAPForQty walks the sell or buy side of the book to fill the specified
+// quantity of base asset. Note that the sell side of the book fills a buy
+// order, and vice versa. If the book side does not have enough depth, the
+// returned VWAPFill describes a fill of the entire side, with Filled false.
+func (ob *OrderBook) VWAPForQty(qty uint64, sell bool) (*VWAPFill, error) {
+	if !ob.isSynced() {
+		return nil, fmt.Errorf("order book is unsynced")
+	}
+	side := ob.buys
+	if sell {
+		side = ob.sells
+	}
+	avg, worst, filledQty := side.vwap(qty)
+	return &VWAPFill{
+		AvgRate:   avg,
+		WorstRate: worst,
+		Quantity:  filledQty,
+		Filled:    qty > 0 && filledQty == qty,
+	}, nil
+}
+
 // VWAP calculates the volume weighted average price for the specified number
 // of lots.
 func (ob *OrderBook) VWAP(lots, lotSize uint64, sell bool) (avg, extrema uint64, filled bool, err error) {
-	orders, _, err := ob.BestNOrders(int(lots), sell)
+	fill, err := ob.VWAPForQty(lots*lotSize, sell)
 	if err != nil {
 		return 0, 0, false, err
 	}
-
-	remainingLots := lots
-	var weightedSum uint64
-	for _, order := range orders {
-		extrema = order.Rate
-		lotsInOrder := order.Quantity / lotSize
-		if lotsInOrder >= remainingLots {
-			weightedSum += remainingLots * extrema
-			filled = true
-			break
-		}
-		remainingLots -= lotsInOrder
-		weightedSum += lotsInOrder * extrema
-	}
-
-	if !filled {
+	if !fill.Filled {
 		return 0, 0, false, nil
 	}
-
-	return weightedSum / lots, extrema, true, nil
+	return fill.AvgRate, fill.WorstRate, true, nil
 }
 
 // Orders is the full order book, as slices of sorted buys and sells, and
@@ -761,14 +804,41 @@ func (ob *OrderBook) BestFill(sell bool, qty uint64) ([]*Fill, bool) {
 	return ob.sells.BestFill(qty)
 }
 
+// Depth returns up to n price levels for the side of the book, best first,
+// with the rates aggregated to multiples of tick. The aggregated sell rates
+// are rounded up, and the aggregated buy rates are rounded down. A tick of
+// zero does not aggregate. The levels are maintained as book updates are
+// received, so Depth does not scan the orders.
+func (ob *OrderBook) Depth(n int, tick uint64, sell bool) ([]*DepthLevel, error) {
+	if !ob.isSynced() {
+		return nil, fmt.Errorf("order book is unsynced")
+	}
+	if sell {
+		return ob.sells.Depth(n, tick), nil
+	}
+	return ob.buys.Depth(n, tick), nil
+}
+
+// CumulativeDepth is the total quantity of orders on the side of the book with
+// rates at least as good as the specified rate.
+func (ob *OrderBook) CumulativeDepth(rate uint64, sell bool) (uint64, error) {
+	if !ob.isSynced() {
+		return 0, fmt.Errorf("order book is unsynced")
+	}
+	if sell {
+		return ob.sells.CumulativeDepth(rate), nil
+	}
+	return ob.buys.CumulativeDepth(rate), nil
+}
+
 // BestFillMarketBuy is the best (rate, quantity) fill for a market buy order.
 // The qty given will be in units of quote asset.
 func (ob *OrderBook) BestFillMarketBuy(qty, lotSize uint64) ([]*Fill, bool) {
 	return ob.sells.bestFill(qty, true, lotSize)
 }
 
-// AddRecentMatches adds the recent matches. If the recent matches cache length
-// grows bigger than 100, it will slice out the ones first added.
+// AddRecentMatches adds the recent matches to the trades tape. If the tape
+// grows longer than tradeTapeCapacity, it will slice out the ones first added.
 func (ob *OrderBook) AddRecentMatches(matches [][2]int64, ts uint64) []*MatchSummary {
 	if matches == nil {
 		return nil
@@ -796,11 +866,10 @@ func (ob *OrderBook) AddRecentMatches(matches [][2]int64, ts uint64) []*MatchSum
 	ob.matchSummaryMtx.Lock()
 	defer ob.matchSummaryMtx.Unlock()
 	ob.matchesSummary = append(newMatches, ob.matchesSummary...) // nolint:makezero
-	const maxLength = 100
-	// if ob.matchesSummary length is greater than max length, we slice the array
-	// to maxLength, removing values first added.
-	if len(ob.matchesSummary) > maxLength {
-		ob.matchesSummary = ob.matchesSummary[:maxLength]
+	// if ob.matchesSummary length is greater than the capacity, we slice the
+	// array, removing values first added.
+	if len(ob.matchesSummary) > tradeTapeCapacity {
+		ob.matchesSummary = ob.matchesSummary[:tradeTapeCapacity]
 	}
 	return newMatches
 }
@@ -809,5 +878,82 @@ func (ob *OrderBook) AddRecentMatches(matches [][2]int64, ts uint64) []*MatchSum
 func (ob *OrderBook) RecentMatches() []*MatchSummary {
 	ob.matchSummaryMtx.Lock()
 	defer ob.matchSummaryMtx.Unlock()
+	if len(ob.matchesSummary) > recentMatchesLength {
+		return ob.matchesSummary[:recentMatchesLength]
+	}
 	return ob.matchesSummary
 }
+
+// OrderFlowStats are statistics of the trades in the trades tape. Trades are
+// classified by the taker's side, so a buy is a trade in which the taker
+// bought the base asset (MatchSummary.Sell is false). Volumes and sizes are
+// in units of the base asset.
+type OrderFlowStats struct {
+	Trades     int    `json:"trades"`
+	BuyTrades  int    `json:"buyTrades"`
+	SellTrades int    `json:"sellTrades"`
+	BuyVolume  uint64 `json:"buyVolume"`
+	SellVolume uint64 `json:"sellVolume"`
+	// BuySellRatio is BuyVolume / SellVolume. It is zero if there were no
+	// sells.
+	BuySellRatio float64 `json:"buySellRatio"`
+	// Imbalance is (BuyVolume - SellVolume) / (BuyVolume + SellVolume),
+	// ranging from -1 when all volume was sold to 1 when all volume was
+	// bought.
+	Imbalance    float64 `json:"imbalance"`
+	AvgTradeSize uint64  `json:"avgTradeSize"`
+	// VWAP is the volume-weighted average rate of the trades.
+	VWAP uint64 `json:"vwap"`
+	// Start and End are the stamps of the oldest and newest trades, in
+	// milliseconds.
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+// OrderFlow computes order-flow statistics for the trades in the tape that
+// occurred within the window before now. A zero window includes the entire
+// tape, which holds up to tradeTapeCapacity trades.
+func (ob *OrderBook) OrderFlow(window time.Duration) *OrderFlowStats {
+	var since uint64
+	if window > 0 {
+		since = uint64(time.Now().Add(-window).UnixMilli())
+	}
+
+	ob.matchSummaryMtx.Lock()
+	defer ob.matchSummaryMtx.Unlock()
+
+	stats := new(OrderFlowStats)
+	quoteVol := new(big.Int)
+	for _, m := range ob.matchesSummary { // newest first
+		if m.Stamp < since {
+			break
+		}
+		if stats.Trades == 0 {
+			stats.End = m.Stamp
+		}
+		stats.Start = m.Stamp
+		stats.Trades++
+		if m.Sell {
+			stats.SellTrades++
+			stats.SellVolume += m.Qty
+		} else {
+			stats.BuyTrades++
+			stats.BuyVolume += m.Qty
+		}
+		quoteVol.Add(quoteVol, new(big.Int).Mul(new(big.Int).SetUint64(m.Rate), new(big.Int).SetUint64(m.Qty)))
+	}
+	if stats.Trades == 0 {
+		return stats
+	}
+
+	vol := stats.BuyVolume + stats.SellVolume
+	stats.AvgTradeSize = vol / uint64(stats.Trades)
+	if stats.SellVolume > 0 {
+		stats.BuySellRatio = float64(stats.BuyVolume) / float64(stats.SellVolume)
+	}
+	if vol > 0 {
+		stats.Imbalance = (float64(stats.BuyVolume) - float64(stats.SellVolume)) / float64(vol)
+		stats.VWAP = quoteVol.Div(quoteVol, new(big.Int).SetUint64(vol)).Uint64()
+	}
+	return stats
+}
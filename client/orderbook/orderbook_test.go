@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"testing"
+	"time"
 
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
@@ -1051,6 +1052,46 @@ func TestVWAP(t *testing.T) {
 	}
 }
 
+func TestVWAPForQty(t *testing.T) {
+	orders := []*Order{
+		makeOrder([32]byte{'a'}, msgjson.BuyOrderNum, 10, 200, 2),
+		makeOrder([32]byte{'b'}, msgjson.BuyOrderNum, 20, 180, 2),
+		makeOrder([32]byte{'c'}, msgjson.SellOrderNum, 10, 220, 2),
+		makeOrder([32]byte{'d'}, msgjson.SellOrderNum, 5, 220, 3),
+		// Large enough that rate * quantity overflows a uint64.
+		makeOrder([32]byte{'e'}, msgjson.SellOrderNum, 1e12, 1e8, 2),
+	}
+	ob := makeOrderBook(1, "ob", orders, make([]*cachedOrderNote, 0), true)
+
+	tests := []struct {
+		name string
+		sell bool
+		qty  uint64
+		exp  VWAPFill
+	}{
+		{"buys partial order", false, 5, VWAPFill{200, 200, 5, true}},
+		{"buys two orders", false, 15, VWAPFill{(10*200 + 5*180) / 15, 180, 15, true}},
+		{"buys too thin", false, 100, VWAPFill{(10*200 + 20*180) / 30, 180, 30, false}},
+		{"zero", false, 0, VWAPFill{}},
+		{"sells same rate", true, 15, VWAPFill{220, 220, 15, true}},
+		{"sells big", true, 1e12, VWAPFill{99_999_999, 1e8, 1e12, true}}, // (15*220 + (1e12-15)*1e8) / 1e12
+	}
+	for _, tt := range tests {
+		fill, err := ob.VWAPForQty(tt.qty, tt.sell)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if *fill != tt.exp {
+			t.Fatalf("%s: expected %+v, got %+v", tt.name, tt.exp, *fill)
+		}
+	}
+
+	ob.setSynced(false)
+	if _, err := ob.VWAPForQty(1, true); err == nil {
+		t.Fatalf("no error for unsynced book")
+	}
+}
+
 func TestValidateMatchProof(t *testing.T) {
 	mid := "mkt"
 	ob := NewOrderBook(tLogger)
@@ -1247,3 +1288,61 @@ func TestValidateMatchProof(t *testing.T) {
 		t.Fatalf("[ValidateMatchProof (invalid csum)]: unexpected error: %v", err)
 	}
 }
+
+func TestOrderFlow(t *testing.T) {
+	ob := NewOrderBook(tLogger)
+
+	if stats := ob.OrderFlow(0); stats.Trades != 0 {
+		t.Fatalf("expected no trades, got %d", stats.Trades)
+	}
+
+	now := uint64(time.Now().UnixMilli())
+	old := now - uint64(2*time.Hour/time.Millisecond)
+	// Negative quantity means the maker sold, i.e. the taker bought.
+	ob.AddRecentMatches([][2]int64{{100, 10}}, old)
+	ob.AddRecentMatches([][2]int64{{200, -30}, {300, 10}}, now)
+
+	stats := ob.OrderFlow(0)
+	if stats.Trades != 3 || stats.BuyTrades != 1 || stats.SellTrades != 2 {
+		t.Fatalf("wrong trade counts: %+v", stats)
+	}
+	if stats.BuyVolume != 30 || stats.SellVolume != 20 {
+		t.Fatalf("wrong volumes: %+v", stats)
+	}
+	if stats.BuySellRatio != 1.5 || stats.Imbalance != 0.2 {
+		t.Fatalf("wrong ratio %f or imbalance %f", stats.BuySellRatio, stats.Imbalance)
+	}
+	if stats.AvgTradeSize != 16 {
+		t.Fatalf("wrong average trade size %d", stats.AvgTradeSize)
+	}
+	// (100*10 + 200*30 + 300*10) / 50 = 200
+	if stats.VWAP != 200 {
+		t.Fatalf("wrong VWAP %d", stats.VWAP)
+	}
+	if stats.Start != old || stats.End != now {
+		t.Fatalf("wrong start/end %d/%d", stats.Start, stats.End)
+	}
+
+	// The old trade is outside of the window.
+	stats = ob.OrderFlow(time.Hour)
+	if stats.Trades != 2 || stats.SellVolume != 10 || stats.VWAP != 225 {
+		t.Fatalf("wrong windowed stats: %+v", stats)
+	}
+
+	// Another buy.
+	ob.AddRecentMatches([][2]int64{{100, -10}}, now+1)
+	if stats = ob.OrderFlow(time.Hour); stats.BuySellRatio != 4 {
+		t.Fatalf("wrong ratio %f", stats.BuySellRatio)
+	}
+
+	// The tape is capped, but RecentMatches returns fewer.
+	for i := 0; i < tradeTapeCapacity; i++ {
+		ob.AddRecentMatches([][2]int64{{100, 1}}, now)
+	}
+	if n := len(ob.RecentMatches()); n != recentMatchesLength {
+		t.Fatalf("wrong number of recent matches %d", n)
+	}
+	if stats = ob.OrderFlow(0); stats.Trades != tradeTapeCapacity {
+		t.Fatalf("wrong tape length %d", stats.Trades)
+	}
+}
@@ -0,0 +1,282 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+)
+
+// APIScope is a permission granted to an API key.
+type APIScope string
+
+const (
+	// ScopeRead allows the routes that only report on the state of the app,
+	// its wallets and orders. Every API key has read access.
+	ScopeRead APIScope = "read"
+	// ScopeTrade allows placing and canceling orders and managing bots.
+	ScopeTrade APIScope = "trade"
+	// ScopeSend allows sending funds from the wallets.
+	ScopeSend APIScope = "send"
+	// ScopeAdmin allows every route, including API key management.
+	ScopeAdmin APIScope = "admin"
+)
+
+// routeScopes are the scopes required for the routes. Routes that are not
+// listed require ScopeAdmin.
+var routeScopes = map[string]APIScope{
+	exchangesRoute:           ScopeRead,
+	helpRoute:                ScopeRead,
+	myOrdersRoute:            ScopeRead,
+	orderBookRoute:           ScopeRead,
+	getDEXConfRoute:          ScopeRead,
+	bondAssetsRoute:          ScopeRead,
+	bondOptionsRoute:         ScopeRead,
+	versionRoute:             ScopeRead,
+	walletsRoute:             ScopeRead,
+	walletPeersRoute:         ScopeRead,
+	notificationsRoute:       ScopeRead,
+	mmAvailableBalancesRoute: ScopeRead,
+	mmStatusRoute:            ScopeRead,
+	mmQuoteQualityRoute:      ScopeRead,
+	mmBenchmarkRoute:         ScopeRead,
+	mmDryRunRoute:            ScopeTrade,
+	mmAllocationRoute:        ScopeRead,
+	stakeStatusRoute:         ScopeRead,
+	txHistoryRoute:           ScopeRead,
+	walletTxRoute:            ScopeRead,
+	syncProgressRoute:        ScopeRead,
+	feeReportRoute:           ScopeRead,
+	confTargetsRoute:         ScopeRead,
+	tokenAllowancesRoute:     ScopeRead,
+	troubledMatchesRoute:     ScopeRead,
+	bondPlanRoute:            ScopeRead,
+	tradingLimitsRoute:       ScopeRead,
+	cancelRoute:              ScopeTrade,
+	tradeRoute:               ScopeTrade,
+	multiTradeRoute:          ScopeTrade,
+	startBotRoute:            ScopeTrade,
+	stopBotRoute:             ScopeTrade,
+	updateRunningBotCfgRoute: ScopeTrade,
+	updateRunningBotInvRoute: ScopeTrade,
+	convertCEXInventoryRoute: ScopeTrade,
+	recoverSwapRoute:         ScopeTrade,
+	applyBondPlanRoute:       ScopeTrade,
+	annotateOrderRoute:       ScopeTrade,
+	withdrawRoute:            ScopeSend,
+	sendRoute:                ScopeSend,
+	withdrawBchSpvRoute:      ScopeSend,
+	setConfTargetRoute:       ScopeSend,
+	setTokenAllowanceRoute:   ScopeSend,
+}
+
+// parseAPIScopes parses a comma-separated list of scopes.
+func parseAPIScopes(s string) ([]APIScope, error) {
+	var scopes []APIScope
+	for _, str := range strings.Split(s, ",") {
+		scope := APIScope(strings.TrimSpace(strings.ToLower(str)))
+		switch scope {
+		case ScopeRead, ScopeTrade, ScopeSend, ScopeAdmin:
+			scopes = append(scopes, scope)
+		default:
+			return nil, fmt.Errorf("unknown scope %q", str)
+		}
+	}
+	return scopes, nil
+}
+
+// APIKey is an API key that grants access to the routes allowed by its
+// scopes until it expires. Only a hash of the key's secret is stored.
+type APIKey struct {
+	ID     string     `json:"id"`
+	Label  string     `json:"label"`
+	Scopes []APIScope `json:"scopes"`
+	// Created and Expiration are unix milliseconds. An Expiration of 0 means
+	// the key doesn't expire.
+	Created    uint64    `json:"created"`
+	Expiration uint64    `json:"expiration,omitempty"`
+	SecretHash dex.Bytes `json:"secretHash,omitempty"`
+}
+
+// expired is true if the key has expired.
+func (k *APIKey) expired(now time.Time) bool {
+	return k.Expiration != 0 && uint64(now.UnixMilli()) >= k.Expiration
+}
+
+// allows checks whether the key's scopes allow the route.
+func (k *APIKey) allows(route string) bool {
+	return scopesAllow(k.Scopes, route)
+}
+
+// scopesAllow checks whether the scopes allow the route.
+func scopesAllow(scopes []APIScope, route string) bool {
+	required, found := routeScopes[route]
+	if !found {
+		required = ScopeAdmin
+	}
+	for _, scope := range scopes {
+		if scope == ScopeAdmin || scope == required {
+			return true
+		}
+	}
+	return required == ScopeRead
+}
+
+// apiKeySecretSize is the size of an API key's secret, in bytes.
+const apiKeySecretSize = 32
+
+// apiKeyStore keeps the API keys in a JSON file.
+type apiKeyStore struct {
+	path string
+
+	mtx  sync.RWMutex
+	keys map[string]*APIKey
+}
+
+// loadAPIKeys loads the API keys from the file. A missing file is not an
+// error.
+func loadAPIKeys(path string) (*apiKeyStore, error) {
+	store := &apiKeyStore{
+		path: path,
+		keys: make(map[string]*APIKey),
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("error reading API keys file: %w", err)
+	}
+	var keys []*APIKey
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return nil, fmt.Errorf("error decoding API keys file: %w", err)
+	}
+	for _, k := range keys {
+		store.keys[k.ID] = k
+	}
+	return store, nil
+}
+
+// save writes the keys to the file. The mutex must be held.
+func (store *apiKeyStore) save() error {
+	keys := make([]*APIKey, 0, len(store.keys))
+	for _, k := range store.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created < keys[j].Created })
+	b, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(store.path, b, 0600)
+}
+
+// create creates a new API key and returns it along with the key string that
+// the client uses to authenticate. The key string is not stored and can't be
+// recovered.
+func (store *apiKeyStore) create(label string, scopes []APIScope, lifetime time.Duration) (*APIKey, string, error) {
+	id := hex.EncodeToString(encode.RandomBytes(8))
+	secret := encode.RandomBytes(apiKeySecretSize)
+	secretHash := sha256.Sum256(secret)
+	now := time.Now()
+	k := &APIKey{
+		ID:         id,
+		Label:      label,
+		Scopes:     scopes,
+		Created:    uint64(now.UnixMilli()),
+		SecretHash: secretHash[:],
+	}
+	if lifetime > 0 {
+		k.Expiration = uint64(now.Add(lifetime).UnixMilli())
+	}
+
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+	store.keys[id] = k
+	if err := store.save(); err != nil {
+		delete(store.keys, id)
+		return nil, "", fmt.Errorf("error saving API keys: %w", err)
+	}
+	return k.redacted(), id + "." + hex.EncodeToString(secret), nil
+}
+
+// revoke deletes the API key.
+func (store *apiKeyStore) revoke(id string) error {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+	k, found := store.keys[id]
+	if !found {
+		return fmt.Errorf("unknown API key %q", id)
+	}
+	delete(store.keys, id)
+	if err := store.save(); err != nil {
+		store.keys[id] = k
+		return fmt.Errorf("error saving API keys: %w", err)
+	}
+	return nil
+}
+
+// list lists the API keys, oldest first, without their secret hashes.
+func (store *apiKeyStore) list() []*APIKey {
+	store.mtx.RLock()
+	defer store.mtx.RUnlock()
+	keys := make([]*APIKey, 0, len(store.keys))
+	for _, k := range store.keys {
+		keys = append(keys, k.redacted())
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created < keys[j].Created })
+	return keys
+}
+
+// authenticate finds the unexpired API key for the key string.
+func (store *apiKeyStore) authenticate(keyStr string) (*APIKey, error) {
+	id, secretStr, _ := strings.Cut(keyStr, ".")
+	secret, err := hex.DecodeString(secretStr)
+	if err != nil || len(secret) != apiKeySecretSize {
+		return nil, errors.New("malformed API key")
+	}
+	store.mtx.RLock()
+	k, found := store.keys[id]
+	store.mtx.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("unknown API key %q", id)
+	}
+	secretHash := sha256.Sum256(secret)
+	if subtle.ConstantTimeCompare(k.SecretHash, secretHash[:]) != 1 {
+		return nil, fmt.Errorf("wrong secret for API key %q", id)
+	}
+	if k.expired(time.Now()) {
+		return nil, fmt.Errorf("API key %q expired", id)
+	}
+	return k, nil
+}
+
+// redacted is a copy of the key without the secret hash.
+func (k *APIKey) redacted() *APIKey {
+	kCopy := *k
+	kCopy.SecretHash = nil
+	return &kCopy
+}
+
+type apiKeyCtxKey struct{}
+
+// apiKeyFromContext is the API key that authenticated the request, or nil if
+// the request was authenticated with the RPC user and password.
+func apiKeyFromContext(ctx context.Context) *APIKey {
+	k, _ := ctx.Value(apiKeyCtxKey{}).(*APIKey)
+	return k
+}
@@ -0,0 +1,187 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientCert is a TLS client certificate that is allowed to authenticate with
+// the RPC server. Certificates are pinned by fingerprint, so self-signed
+// certificates can be used and no certificate authority is needed.
+type ClientCert struct {
+	// Fingerprint is the hex-encoded SHA-256 hash of the DER-encoded
+	// certificate.
+	Fingerprint string     `json:"fingerprint"`
+	Label       string     `json:"label"`
+	Scopes      []APIScope `json:"scopes"`
+	// Added is unix milliseconds.
+	Added uint64 `json:"added"`
+}
+
+// allows checks whether the certificate's scopes allow the route.
+func (c *ClientCert) allows(route string) bool {
+	return scopesAllow(c.Scopes, route)
+}
+
+// certFingerprint is the hex-encoded SHA-256 hash of the DER-encoded
+// certificate.
+func certFingerprint(cert *x509.Certificate) string {
+	h := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(h[:])
+}
+
+// parseFingerprint parses a hex-encoded SHA-256 certificate fingerprint. The
+// colon-separated upper case format output by e.g.
+// `openssl x509 -noout -fingerprint -sha256` is accepted.
+func parseFingerprint(s string) (string, error) {
+	fp := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), ":", ""))
+	if b, err := hex.DecodeString(fp); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 fingerprint %q", s)
+	}
+	return fp, nil
+}
+
+// clientCertStore keeps the client certificate allowlist in a JSON file. The
+// file can also be edited by hand while the RPC server is not running.
+type clientCertStore struct {
+	path string
+
+	mtx   sync.RWMutex
+	certs map[string]*ClientCert
+}
+
+// loadClientCerts loads the client certificate allowlist from the file. A
+// missing file is not an error.
+func loadClientCerts(path string) (*clientCertStore, error) {
+	store := &clientCertStore{
+		path:  path,
+		certs: make(map[string]*ClientCert),
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("error reading client certificates file: %w", err)
+	}
+	var certs []*ClientCert
+	if err := json.Unmarshal(b, &certs); err != nil {
+		return nil, fmt.Errorf("error decoding client certificates file: %w", err)
+	}
+	for _, c := range certs {
+		fp, err := parseFingerprint(c.Fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate %q: %w", c.Label, err)
+		}
+		c.Fingerprint = fp
+		store.certs[fp] = c
+	}
+	return store, nil
+}
+
+// save writes the allowlist to the file. The mutex must be held.
+func (store *clientCertStore) save() error {
+	certs := make([]*ClientCert, 0, len(store.certs))
+	for _, c := range store.certs {
+		certs = append(certs, c)
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Added < certs[j].Added })
+	b, err := json.MarshalIndent(certs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(store.path, b, 0600)
+}
+
+// add adds the certificate with the fingerprint to the allowlist, replacing
+// the label and scopes if it is already allowed.
+func (store *clientCertStore) add(fingerprint, label string, scopes []APIScope) (*ClientCert, error) {
+	c := &ClientCert{
+		Fingerprint: fingerprint,
+		Label:       label,
+		Scopes:      scopes,
+		Added:       uint64(time.Now().UnixMilli()),
+	}
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+	old := store.certs[fingerprint]
+	store.certs[fingerprint] = c
+	if err := store.save(); err != nil {
+		if old != nil {
+			store.certs[fingerprint] = old
+		} else {
+			delete(store.certs, fingerprint)
+		}
+		return nil, fmt.Errorf("error saving client certificates: %w", err)
+	}
+	return c, nil
+}
+
+// revoke removes the certificate from the allowlist.
+func (store *clientCertStore) revoke(fingerprint string) error {
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+	c, found := store.certs[fingerprint]
+	if !found {
+		return fmt.Errorf("unknown client certificate %s", fingerprint)
+	}
+	delete(store.certs, fingerprint)
+	if err := store.save(); err != nil {
+		store.certs[fingerprint] = c
+		return fmt.Errorf("error saving client certificates: %w", err)
+	}
+	return nil
+}
+
+// list lists the allowed certificates, oldest first.
+func (store *clientCertStore) list() []*ClientCert {
+	store.mtx.RLock()
+	defer store.mtx.RUnlock()
+	certs := make([]*ClientCert, 0, len(store.certs))
+	for _, c := range store.certs {
+		cCopy := *c
+		certs = append(certs, &cCopy)
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Added < certs[j].Added })
+	return certs
+}
+
+// authenticate finds the allowed certificate for the client's certificate.
+// The TLS handshake has already proven that the client has the certificate's
+// private key, but not that the certificate is current.
+func (store *clientCertStore) authenticate(cert *x509.Certificate) (*ClientCert, error) {
+	fp := certFingerprint(cert)
+	store.mtx.RLock()
+	c, found := store.certs[fp]
+	store.mtx.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("client certificate %s is not allowed", fp)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("client certificate %s (%s) is expired or not yet valid", fp, c.Label)
+	}
+	return c, nil
+}
+
+type clientCertCtxKey struct{}
+
+// clientCertFromContext is the client certificate that authenticated the
+// request, or nil if the request was not authenticated with a client
+// certificate.
+func clientCertFromContext(ctx context.Context) *ClientCert {
+	c, _ := ctx.Value(clientCertCtxKey{}).(*ClientCert)
+	return c
+}
@@ -56,6 +56,11 @@ const (
 	updateRunningBotInvRoute   = "updaterunningbotinv"
 	mmAvailableBalancesRoute   = "mmavailablebalances"
 	mmStatusRoute              = "mmstatus"
+	convertCEXInventoryRoute   = "convertcexinventory"
+	mmQuoteQualityRoute        = "mmquotequality"
+	mmBenchmarkRoute           = "mmbenchmark"
+	mmDryRunRoute              = "mmdryrun"
+	mmAllocationRoute          = "mmallocation"
 	multiTradeRoute            = "multitrade"
 	stakeStatusRoute           = "stakestatus"
 	setVSPRoute                = "setvsp"
@@ -64,6 +69,29 @@ const (
 	txHistoryRoute             = "txhistory"
 	walletTxRoute              = "wallettx"
 	withdrawBchSpvRoute        = "withdrawbchspv"
+	translationReportRoute     = "translationreport"
+	backupRoute                = "backup"
+	restoreBackupRoute         = "restorebackup"
+	dbMaintenanceRoute         = "dbmaintenance"
+	syncProgressRoute          = "syncprogress"
+	feeReportRoute             = "feereport"
+	createAPIKeyRoute          = "createapikey"
+	apiKeysRoute               = "apikeys"
+	revokeAPIKeyRoute          = "revokeapikey"
+	addClientCertRoute         = "addclientcert"
+	clientCertsRoute           = "clientcerts"
+	revokeClientCertRoute      = "revokeclientcert"
+	recoverFromSeedRoute       = "recoverfromseed"
+	setConfTargetRoute         = "setconftarget"
+	confTargetsRoute           = "conftargets"
+	tokenAllowancesRoute       = "tokenallowances"
+	setTokenAllowanceRoute     = "settokenallowance"
+	troubledMatchesRoute       = "troubledmatches"
+	recoverSwapRoute           = "recoverswap"
+	bondPlanRoute              = "bondplan"
+	applyBondPlanRoute         = "applybondplan"
+	annotateOrderRoute         = "annotateorder"
+	tradingLimitsRoute         = "tradinglimits"
 )
 
 const (
@@ -72,10 +100,17 @@ const (
 	walletLockedStr   = "%s wallet locked"
 	walletUnlockedStr = "%s wallet unlocked"
 	canceledOrderStr  = "canceled order %s"
+	annotatedOrderStr = "annotated order %s"
 	logoutStr         = "goodbye"
 	walletStatusStr   = "%s wallet has been %s"
 	setVotePrefsStr   = "vote preferences set"
 	setVSPStr         = "vsp set to %s"
+	revokedAPIKeyStr  = "API key %s revoked"
+	revokedCertStr    = "client certificate %s revoked"
+	confTargetSetStr  = "%s confirmation target set"
+	allowanceSetStr   = "%s allowance set in transaction %s"
+	swapRecoveredStr  = "%s action taken for match %s"
+	bondPlanDoneStr   = "bond funding plan applied"
 )
 
 // createResponse creates a msgjson response payload.
@@ -131,6 +166,11 @@ var routes = map[string]func(s *RPCServer, params *RawParams) *msgjson.ResponseP
 	stopBotRoute:               handleStopBot,
 	mmAvailableBalancesRoute:   handleMMAvailableBalances,
 	mmStatusRoute:              handleMMStatus,
+	convertCEXInventoryRoute:   handleConvertCEXInventory,
+	mmQuoteQualityRoute:        handleMMQuoteQuality,
+	mmBenchmarkRoute:           handleMMBenchmark,
+	mmDryRunRoute:              handleMMDryRun,
+	mmAllocationRoute:          handleMMAllocation,
 	updateRunningBotCfgRoute:   handleUpdateRunningBotCfg,
 	updateRunningBotInvRoute:   handleUpdateRunningBotInventory,
 	multiTradeRoute:            handleMultiTrade,
@@ -141,6 +181,29 @@ var routes = map[string]func(s *RPCServer, params *RawParams) *msgjson.ResponseP
 	txHistoryRoute:             handleTxHistory,
 	walletTxRoute:              handleWalletTx,
 	withdrawBchSpvRoute:        handleWithdrawBchSpv,
+	translationReportRoute:     handleTranslationReport,
+	backupRoute:                handleBackup,
+	restoreBackupRoute:         handleRestoreBackup,
+	dbMaintenanceRoute:         handleDBMaintenance,
+	syncProgressRoute:          handleSyncProgress,
+	feeReportRoute:             handleFeeReport,
+	createAPIKeyRoute:          handleCreateAPIKey,
+	apiKeysRoute:               handleAPIKeys,
+	revokeAPIKeyRoute:          handleRevokeAPIKey,
+	addClientCertRoute:         handleAddClientCert,
+	clientCertsRoute:           handleClientCerts,
+	revokeClientCertRoute:      handleRevokeClientCert,
+	recoverFromSeedRoute:       handleRecoverFromSeed,
+	setConfTargetRoute:         handleSetConfTarget,
+	confTargetsRoute:           handleConfTargets,
+	tokenAllowancesRoute:       handleTokenAllowances,
+	setTokenAllowanceRoute:     handleSetTokenAllowance,
+	troubledMatchesRoute:       handleTroubledMatches,
+	recoverSwapRoute:           handleRecoverSwap,
+	bondPlanRoute:              handleBondPlan,
+	applyBondPlanRoute:         handleApplyBondPlan,
+	annotateOrderRoute:         handleAnnotateOrder,
+	tradingLimitsRoute:         handleTradingLimits,
 }
 
 // handleHelp handles requests for help. Returns general help for all commands
@@ -610,6 +673,316 @@ func handleRescanWallet(s *RPCServer, params *RawParams) *msgjson.ResponsePayloa
 	return createResponse(rescanWalletRoute, "started", nil)
 }
 
+// handleSyncProgress handles requests for the progress of a wallet sync or
+// rescan. *msgjson.ResponsePayload.Error is empty if successful.
+func handleSyncProgress(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	assetID, err := parseSyncProgressArgs(params)
+	if err != nil {
+		return usage(syncProgressRoute, err)
+	}
+	progress, err := s.core.WalletSyncProgress(assetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCSyncProgressError, "unable to get sync progress: %v", err)
+		return createResponse(syncProgressRoute, nil, resErr)
+	}
+	return createResponse(syncProgressRoute, progress, nil)
+}
+
+// handleFeeReport handles requests for a report of the on-chain fees paid.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleFeeReport(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	from, to, err := parseFeeReportArgs(params)
+	if err != nil {
+		return usage(feeReportRoute, err)
+	}
+	report, err := s.core.FeeReport(from, to)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCFeeReportError, "unable to create fee report: %v", err)
+		return createResponse(feeReportRoute, nil, resErr)
+	}
+	return createResponse(feeReportRoute, report, nil)
+}
+
+// handleRecoverFromSeed handles requests for recoverfromseed.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRecoverFromSeed(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseRecoverFromSeedArgs(params)
+	if err != nil {
+		return usage(recoverFromSeedRoute, err)
+	}
+	defer form.appPass.Clear()
+	defer form.seed.Clear()
+	report, err := s.core.RecoverFromSeed(&core.RecoveryForm{
+		AppPass: form.appPass,
+		Seed:    string(form.seed),
+		Assets:  form.assets,
+		Hosts:   form.hosts,
+	})
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCRecoveryError, "unable to recover from seed: %v", err)
+		return createResponse(recoverFromSeedRoute, nil, resErr)
+	}
+	return createResponse(recoverFromSeedRoute, report, nil)
+}
+
+// handleSetConfTarget handles requests for setconftarget.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleSetConfTarget(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseSetConfTargetArgs(params)
+	if err != nil {
+		return usage(setConfTargetRoute, err)
+	}
+	if err := s.core.SetConfTarget(form.assetID, form.target); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCConfTargetError, "unable to set confirmation target: %v", err)
+		return createResponse(setConfTargetRoute, nil, resErr)
+	}
+	return createResponse(setConfTargetRoute, fmt.Sprintf(confTargetSetStr, dex.BipIDSymbol(form.assetID)), nil)
+}
+
+// handleConfTargets handles requests for conftargets.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleConfTargets(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
+	return createResponse(confTargetsRoute, s.core.ConfTargets(), nil)
+}
+
+// handleTokenAllowances handles requests for tokenallowances.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleTokenAllowances(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	assetID, err := parseTokenAllowancesArgs(params)
+	if err != nil {
+		return usage(tokenAllowancesRoute, err)
+	}
+	allowances, err := s.core.TokenAllowances(assetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCTokenAllowanceError, "unable to get token allowances: %v", err)
+		return createResponse(tokenAllowancesRoute, nil, resErr)
+	}
+	return createResponse(tokenAllowancesRoute, allowances, nil)
+}
+
+// handleSetTokenAllowance handles requests for settokenallowance.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleSetTokenAllowance(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseSetTokenAllowanceArgs(params)
+	if err != nil {
+		return usage(setTokenAllowanceRoute, err)
+	}
+	defer form.appPass.Clear()
+	txID, err := s.core.SetTokenAllowance(form.appPass, form.assetID, form.version, form.allowance)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCTokenAllowanceError, "unable to set token allowance: %v", err)
+		return createResponse(setTokenAllowanceRoute, nil, resErr)
+	}
+	return createResponse(setTokenAllowanceRoute, fmt.Sprintf(allowanceSetStr, dex.BipIDSymbol(form.assetID), txID), nil)
+}
+
+// handleTroubledMatches handles requests for troubledmatches.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleTroubledMatches(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
+	matches, err := s.core.TroubledMatches()
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCSwapRecoveryError, "unable to list troubled matches: %v", err)
+		return createResponse(troubledMatchesRoute, nil, resErr)
+	}
+	return createResponse(troubledMatchesRoute, matches, nil)
+}
+
+// handleRecoverSwap handles requests for recoverswap.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRecoverSwap(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	appPass, form, err := parseRecoverSwapArgs(params)
+	if err != nil {
+		return usage(recoverSwapRoute, err)
+	}
+	defer appPass.Clear()
+	if err := s.core.RecoverSwap(appPass, form); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCSwapRecoveryError, "unable to recover swap: %v", err)
+		return createResponse(recoverSwapRoute, nil, resErr)
+	}
+	return createResponse(recoverSwapRoute, fmt.Sprintf(swapRecoveredStr, form.Action, form.MatchID), nil)
+}
+
+// handleTradingLimits handles requests for tradinglimits. The trading limit
+// usage is returned for every DEX account, or only the specified host.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleTradingLimits(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	host, err := parseTradingLimitsArgs(params)
+	if err != nil {
+		return usage(tradingLimitsRoute, err)
+	}
+	if host == "" {
+		return createResponse(tradingLimitsRoute, s.core.TradingLimitStatuses(), nil)
+	}
+	status, err := s.core.TradingLimitStatus(host)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCTradingLimitsError, "unable to get trading limits for %s: %v", host, err)
+		return createResponse(tradingLimitsRoute, nil, resErr)
+	}
+	return createResponse(tradingLimitsRoute, []*core.TradingLimitStatus{status}, nil)
+}
+
+// handleBondPlan handles requests for bondplan.
+// *msgjson.ResponsePayload.Error is empty if successful.
+// handleAnnotateOrder handles requests for annotateorder.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleAnnotateOrder(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAnnotateOrderArgs(params)
+	if err != nil {
+		return usage(annotateOrderRoute, err)
+	}
+	if err := s.core.AnnotateOrder(form); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAnnotateOrderError, "unable to annotate order %s: %v", form.OrderID, err)
+		return createResponse(annotateOrderRoute, nil, resErr)
+	}
+	res := fmt.Sprintf(annotatedOrderStr, form.OrderID)
+	return createResponse(annotateOrderRoute, &res, nil)
+}
+
+func handleBondPlan(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
+	plan, err := s.core.BondFundingPlan()
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCBondPlanError, "unable to plan bond funding: %v", err)
+		return createResponse(bondPlanRoute, nil, resErr)
+	}
+	return createResponse(bondPlanRoute, plan, nil)
+}
+
+// handleApplyBondPlan handles requests for applybondplan. A new plan is
+// created and applied, optionally for only one host.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleApplyBondPlan(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	host, err := parseApplyBondPlanArgs(params)
+	if err != nil {
+		return usage(applyBondPlanRoute, err)
+	}
+	plan, err := s.core.BondFundingPlan()
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCBondPlanError, "unable to plan bond funding: %v", err)
+		return createResponse(applyBondPlanRoute, nil, resErr)
+	}
+	if host != "" {
+		hosts := make([]*core.HostBondPlan, 0, 1)
+		for _, hp := range plan.Hosts {
+			if hp.Host == host {
+				hosts = append(hosts, hp)
+			}
+		}
+		if len(hosts) == 0 {
+			resErr := msgjson.NewError(msgjson.RPCBondPlanError, "no bond funding plan for %s", host)
+			return createResponse(applyBondPlanRoute, nil, resErr)
+		}
+		plan.Hosts = hosts
+	}
+	if err := s.core.ApplyBondFundingPlan(plan); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCBondPlanError, "unable to apply bond funding plan: %v", err)
+		return createResponse(applyBondPlanRoute, nil, resErr)
+	}
+	return createResponse(applyBondPlanRoute, bondPlanDoneStr, nil)
+}
+
+// createdAPIKey is the result of createapikey.
+type createdAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// handleCreateAPIKey handles requests for createapikey.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleCreateAPIKey(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseCreateAPIKeyArgs(params)
+	if err != nil {
+		return usage(createAPIKeyRoute, err)
+	}
+	if s.apiKeys == nil {
+		resErr := msgjson.NewError(msgjson.RPCAPIKeyError, "API keys are not enabled")
+		return createResponse(createAPIKeyRoute, nil, resErr)
+	}
+	k, keyStr, err := s.apiKeys.create(form.label, form.scopes, form.lifetime)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAPIKeyError, "unable to create API key: %v", err)
+		return createResponse(createAPIKeyRoute, nil, resErr)
+	}
+	return createResponse(createAPIKeyRoute, &createdAPIKey{APIKey: k, Key: keyStr}, nil)
+}
+
+// handleAPIKeys handles requests for apikeys. *msgjson.ResponsePayload.Error
+// is empty if successful.
+func handleAPIKeys(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
+	if s.apiKeys == nil {
+		resErr := msgjson.NewError(msgjson.RPCAPIKeyError, "API keys are not enabled")
+		return createResponse(apiKeysRoute, nil, resErr)
+	}
+	return createResponse(apiKeysRoute, s.apiKeys.list(), nil)
+}
+
+// handleRevokeAPIKey handles requests for revokeapikey.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRevokeAPIKey(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
+		return usage(revokeAPIKeyRoute, err)
+	}
+	if s.apiKeys == nil {
+		resErr := msgjson.NewError(msgjson.RPCAPIKeyError, "API keys are not enabled")
+		return createResponse(revokeAPIKeyRoute, nil, resErr)
+	}
+	id := params.Args[0]
+	if err := s.apiKeys.revoke(id); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAPIKeyError, "unable to revoke API key: %v", err)
+		return createResponse(revokeAPIKeyRoute, nil, resErr)
+	}
+	return createResponse(revokeAPIKeyRoute, fmt.Sprintf(revokedAPIKeyStr, id), nil)
+}
+
+// handleAddClientCert handles requests for addclientcert.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleAddClientCert(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAddClientCertArgs(params)
+	if err != nil {
+		return usage(addClientCertRoute, err)
+	}
+	if s.clientCerts == nil {
+		resErr := msgjson.NewError(msgjson.RPCClientCertError, "client certificates are not enabled")
+		return createResponse(addClientCertRoute, nil, resErr)
+	}
+	c, err := s.clientCerts.add(form.fingerprint, form.label, form.scopes)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCClientCertError, "unable to add client certificate: %v", err)
+		return createResponse(addClientCertRoute, nil, resErr)
+	}
+	return createResponse(addClientCertRoute, c, nil)
+}
+
+// handleClientCerts handles requests for clientcerts.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleClientCerts(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
+	if s.clientCerts == nil {
+		resErr := msgjson.NewError(msgjson.RPCClientCertError, "client certificates are not enabled")
+		return createResponse(clientCertsRoute, nil, resErr)
+	}
+	return createResponse(clientCertsRoute, s.clientCerts.list(), nil)
+}
+
+// handleRevokeClientCert handles requests for revokeclientcert.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRevokeClientCert(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
+		return usage(revokeClientCertRoute, err)
+	}
+	fp, err := parseFingerprint(params.Args[0])
+	if err != nil {
+		return usage(revokeClientCertRoute, fmt.Errorf("%w: %v", errArgs, err))
+	}
+	if s.clientCerts == nil {
+		resErr := msgjson.NewError(msgjson.RPCClientCertError, "client certificates are not enabled")
+		return createResponse(revokeClientCertRoute, nil, resErr)
+	}
+	if err := s.clientCerts.revoke(fp); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCClientCertError, "unable to revoke client certificate: %v", err)
+		return createResponse(revokeClientCertRoute, nil, resErr)
+	}
+	return createResponse(revokeClientCertRoute, fmt.Sprintf(revokedCertStr, fp), nil)
+}
+
 // handleLogout logs out Bison Wallet. *msgjson.ResponsePayload.Error is empty
 // if successful.
 func handleLogout(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
@@ -855,6 +1228,70 @@ func handleNotifications(s *RPCServer, params *RawParams) *msgjson.ResponsePaylo
 	return createResponse(notificationsRoute, notes, nil)
 }
 
+// handleTranslationReport handles requests for translationreport. Reports the
+// completeness of the notification translations for the language, or for all
+// languages if no language is specified.
+func handleTranslationReport(_ *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	lang, err := parseTranslationReportArgs(params)
+	if err != nil {
+		return usage(translationReportRoute, err)
+	}
+	if lang == "" {
+		return createResponse(translationReportRoute, core.TranslationReports(), nil)
+	}
+	report, err := core.LangTranslationReport(lang)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCTranslationReportError, "unable to report translations: %v", err)
+		return createResponse(translationReportRoute, nil, resErr)
+	}
+	return createResponse(translationReportRoute, []*core.TranslationReport{report}, nil)
+}
+
+// handleBackup handles requests for an encrypted backup of the database.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleBackup(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	if err := checkNArgs(params, []int{0}, []int{0}); err != nil {
+		return usage(backupRoute, err)
+	}
+	path, err := s.core.BackupNow()
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCBackupError, "unable to create backup: %v", err)
+		return createResponse(backupRoute, nil, resErr)
+	}
+	return createResponse(backupRoute, path, nil)
+}
+
+// handleRestoreBackup handles requests to restore an encrypted backup.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRestoreBackup(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	seed, path, err := parseRestoreBackupArgs(params)
+	if err != nil {
+		return usage(restoreBackupRoute, err)
+	}
+	defer seed.Clear()
+	restored, err := s.core.RestoreBackup(path, string(seed))
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCRestoreBackupError, "unable to restore backup: %v", err)
+		return createResponse(restoreBackupRoute, nil, resErr)
+	}
+	return createResponse(restoreBackupRoute, restored, nil)
+}
+
+// handleDBMaintenance handles requests to check the consistency of the
+// database and compact it on shutdown. *msgjson.ResponsePayload.Error is empty
+// if successful.
+func handleDBMaintenance(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	if err := checkNArgs(params, []int{0}, []int{0}); err != nil {
+		return usage(dbMaintenanceRoute, err)
+	}
+	report, err := s.core.DBMaintenance()
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCDBMaintenanceError, "unable to check database: %v", err)
+		return createResponse(dbMaintenanceRoute, nil, resErr)
+	}
+	return createResponse(dbMaintenanceRoute, report, nil)
+}
+
 func handleMMAvailableBalances(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	form, err := parseMMAvailableBalancesArgs(params)
 	if err != nil {
@@ -894,12 +1331,20 @@ func handleStartBot(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 }
 
 func handleStopBot(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
-	mkt, err := parseStopBotArgs(params)
+	form, err := parseStopBotArgs(params)
 	if err != nil {
-		return usage(startBotRoute, err)
+		return usage(stopBotRoute, err)
+	}
+
+	if form.windDown {
+		if err := s.mm.WindDownBot(form.mkt); err != nil {
+			resErr := msgjson.NewError(msgjson.RPCStopMarketMakingError, "unable to wind down market making: %v", err)
+			return createResponse(stopBotRoute, nil, resErr)
+		}
+		return createResponse(stopBotRoute, "winding down bot", nil)
 	}
 
-	err = s.mm.StopBot(mkt)
+	err = s.mm.StopBot(form.mkt)
 	if err != nil {
 		resErr := msgjson.NewError(msgjson.RPCStopMarketMakingError, "unable to stop market making: %v", err)
 		return createResponse(stopBotRoute, nil, resErr)
@@ -969,6 +1414,115 @@ func handleMMStatus(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	return createResponse(mmStatusRoute, status, nil)
 }
 
+// botCfgFromFile reads the config for the form's market from the form's
+// market maker config file.
+func botCfgFromFile(form *mmBotCfgForm) (*mm.BotConfig, error) {
+	data, err := os.ReadFile(form.cfgFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %v", err)
+	}
+
+	cfg := &mm.MarketMakingConfig{}
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal config: %v", err)
+	}
+
+	for _, bot := range cfg.BotConfigs {
+		if bot.Host == form.mkt.Host && bot.BaseID == form.mkt.BaseID && bot.QuoteID == form.mkt.QuoteID {
+			return bot, nil
+		}
+	}
+	return nil, fmt.Errorf("bot config not found for market %s", form.mkt.String())
+}
+
+func handleMMDryRun(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseMMBotCfgArgs(params)
+	if err != nil {
+		return usage(mmDryRunRoute, err)
+	}
+
+	botCfg, err := botCfgFromFile(form)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCMMDryRunError, "%v", err)
+		return createResponse(mmDryRunRoute, nil, resErr)
+	}
+
+	report, err := s.mm.DryRunBot(botCfg)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCMMDryRunError, "dry run failed: %v", err)
+		return createResponse(mmDryRunRoute, nil, resErr)
+	}
+
+	return createResponse(mmDryRunRoute, report, nil)
+}
+
+func handleMMAllocation(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseMMBotCfgArgs(params)
+	if err != nil {
+		return usage(mmAllocationRoute, err)
+	}
+
+	botCfg, err := botCfgFromFile(form)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCMMAllocationError, "%v", err)
+		return createResponse(mmAllocationRoute, nil, resErr)
+	}
+
+	advice, err := s.mm.RecommendedAllocation(botCfg)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCMMAllocationError, "unable to calculate allocation: %v", err)
+		return createResponse(mmAllocationRoute, nil, resErr)
+	}
+
+	return createResponse(mmAllocationRoute, advice, nil)
+}
+
+func handleMMQuoteQuality(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	mkt, err := parseMMQuoteQualityArgs(params)
+	if err != nil {
+		return usage(mmQuoteQualityRoute, err)
+	}
+
+	stats, err := s.mm.QuoteQuality(mkt)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCMMStatusError, "unable to get quote quality: %v", err)
+		return createResponse(mmQuoteQualityRoute, nil, resErr)
+	}
+
+	return createResponse(mmQuoteQualityRoute, stats, nil)
+}
+
+func handleMMBenchmark(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseMMBenchmarkArgs(params)
+	if err != nil {
+		return usage(mmBenchmarkRoute, err)
+	}
+
+	report, err := s.mm.BenchmarkReport(form.startTime, form.mkt)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCMMStatusError, "unable to generate benchmark report: %v", err)
+		return createResponse(mmBenchmarkRoute, nil, resErr)
+	}
+
+	return createResponse(mmBenchmarkRoute, report, nil)
+}
+
+func handleConvertCEXInventory(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseConvertCEXInventoryArgs(params)
+	if err != nil {
+		return usage(convertCEXInventoryRoute, err)
+	}
+
+	conversion, err := s.mm.ConvertCEXInventory(form.mkt, form.fromID, form.toID, form.qty, form.maxSlippage)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCCEXConversionError, "unable to convert CEX inventory: %v", err)
+		return createResponse(convertCEXInventoryRoute, nil, resErr)
+	}
+
+	return createResponse(convertCEXInventoryRoute, conversion, nil)
+}
+
 func handleSetVSP(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	form, err := parseSetVSPArgs(params)
 	if err != nil {
@@ -1482,6 +2036,21 @@ var helpMsgs = map[string]helpMsg{
     orderID (string): The hex ID of the order to cancel`,
 		returns: `Returns:
     string: The message "` + fmt.Sprintf(canceledOrderStr, "[order ID]") + `"`,
+	},
+	annotateOrderRoute: {
+		argsShort: `"orderID" "note" ("tags") ("matchID")`,
+		cmdSummary: `Set a note and tags for an order, or for one of its matches. The note
+  and tags replace any that were set before, and are included in the order
+  and match exports. Empty values clear the annotation.`,
+		argsLong: `Args:
+    orderID (string): The hex ID of the order.
+    note (string): Free text describing the order.
+    tags (string): Optional. A comma-separated list of tags, e.g.
+      "bot,arb". Tags may not contain spaces.
+    matchID (string): Optional. The hex ID of the order's match to annotate
+      instead of the order.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(annotatedOrderStr, "[order ID]") + `"`,
 	},
 	rescanWalletRoute: {
 		argsShort: `assetID (force)`,
@@ -1500,6 +2069,392 @@ needed to complete a swap.`,
       https://github.com/satoshilabs/slips/blob/master/slip-0044.md
     force (bool): Force a wallet rescan even if their are active orders. The
       default is false.`,
+	},
+	syncProgressRoute: {
+		argsShort: `assetID`,
+		cmdSummary: `Show the progress of a wallet's current or most recent sync or rescan,
+  with an estimate of the time remaining.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index. e.g. 42 for DCR.
+      See https://github.com/satoshilabs/slips/blob/master/slip-0044.md`,
+		returns: `Returns:
+  obj: The sync progress.
+  {
+    "assetID" (int): The asset's BIP-44 registered coin index.
+    "rescan" (bool): Whether this is a rescan started with rescanwallet, as
+      opposed to the wallet syncing with the network.
+    "done" (bool): Whether the sync or rescan is complete.
+    "progress" (float): The fraction complete, from 0 to 1.
+    "startHeight" (int): The block height the sync or rescan started from.
+    "height" (int): The current block height of the sync or rescan.
+    "targetHeight" (int): The block height at which it will be complete.
+    "started" (int): When it started, in unix milliseconds.
+    "lastProgress" (int): When the height last increased, in unix
+      milliseconds.
+    "stalled" (bool): Whether there has been no progress for a long time,
+      which may mean the sync or rescan is stuck.
+    "eta" (int): The estimated seconds remaining, or 0 if unknown.
+  }`,
+	},
+	feeReportRoute: {
+		argsShort: `(from) (to)`,
+		cmdSummary: `Report the on-chain transaction fees paid by the wallets, by the asset
+  the fees were paid in and the type of transaction. Fees for token
+  transactions are paid in the parent asset. Fees are from the wallets'
+  transaction histories, so wallets that are not connected or do not keep a
+  history are not included.`,
+		argsLong: `Args:
+    from (int): Optional. Only include transactions mined at or after this
+      time, in unix milliseconds. 0 means no limit.
+    to (int): Optional. Only include transactions mined before this time, in
+      unix milliseconds. 0 means no limit.`,
+		returns: `Returns:
+  obj: The fee report.
+  {
+    "from" (int): The start of the time range, or 0.
+    "to" (int): The end of the time range, or 0.
+    "assets" (array): The fees paid in each asset.
+    [
+      {
+        "assetID" (int): The asset's BIP-44 registered coin index.
+        "symbol" (string): The asset's ticker symbol.
+        "total" (int): The total fees, in the asset's atomic units.
+        "count" (int): The number of transactions.
+        "byType" (obj): The total and count for each type of transaction,
+          e.g. swap, redeem, refund, split, createBond, send.
+      },...
+    ]
+    "unavailable" (array): The IDs of assets whose wallets could not report
+      their transaction history.
+  }`,
+	},
+	recoverFromSeedRoute: {
+		pwArgsShort: `"appPass" "seed"`,
+		argsShort:   `("assetIDs") ("hosts")`,
+		cmdSummary: `Rebuild a new app from its seed. The app is initialized with the seed,
+  native wallets are restored and start rescanning, and accounts are
+  discovered on the DEX hosts. Active orders and matches reported by the
+  servers are reconciled with the restored database, and orders that are not
+  in the database are canceled. The swaps of matches that are not in the
+  database are looked up on-chain with the server's swap data, and the ones
+  that can be refunded or redeemed are listed. Wallet sync progress can be
+  checked with syncprogress.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The new Bison Wallet password.
+    seed (string): The app seed.`,
+		argsLong: `Args:
+    assetIDs (string): Optional. Comma-separated asset IDs of the native
+      wallets to restore. All native wallets are restored if not set.
+    hosts (string): Optional. Comma-separated DEX hosts to discover accounts
+      on. The known hosts for the network are used if not set.`,
+		returns: `Returns:
+  obj: The recovery report.
+  {
+    "wallets" (array): The restored wallets.
+    [
+      {
+        "assetID" (int): The asset's BIP-44 registered coin index.
+        "symbol" (string): The asset's ticker symbol.
+        "type" (string): The wallet type.
+        "created" (bool): Whether the wallet was created.
+        "error" (string): Why the wallet was not created, if it wasn't.
+      },...
+    ]
+    "accounts" (array): The accounts.
+    [
+      {
+        "host" (string): The DEX host.
+        "found" (bool): Whether an account was found.
+        "effectiveTier" (int): The account's tier.
+        "liveStrength" (int): The number of tiers in active bonds.
+        "orders" (array): The active orders reported by the server, with
+          "known" set if the order was in the restored database.
+        "matches" (array): The active matches reported by the server, with
+          "known" set if the match's order was in the restored database. For
+          unknown matches, "swap" and "counterSwap" are the swaps found
+          on-chain, with their coin, contract, secret hash, and lock time,
+          "secret" is set if revealed, and "refundable" and "redeemable" say
+          whether our swap can be refunded or the counterparty's redeemed.
+        "error" (string): Why discovery failed, if it did.
+      },...
+    ]
+    "manualActions" (array): Descriptions of what needs manual action.
+  }`,
+	},
+	setConfTargetRoute: {
+		argsShort: `assetID ("target")`,
+		cmdSummary: `Set the default confirmation target for sends and withdraws from a
+  wallet. The target drives the fee rate of the transactions. Only wallets
+  that can estimate fee rates for a confirmation target support this.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index. e.g. 42 for DCR.
+      See https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+    target (string): Optional. "fast" (1 block), "normal" (3 blocks),
+      "economy" (12 blocks), or an explicit number of blocks. "default" or
+      not set restores the default fee rate selection.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(confTargetSetStr, "[symbol]") + `"`,
+	},
+	confTargetsRoute: {
+		cmdSummary: `List the default confirmation targets for sends.`,
+		returns: `Returns:
+  obj: The confirmation targets, keyed by asset ID.
+  {
+    "[assetID]": {
+      "speed" (string): The speed, if set. fast, normal, or economy.
+      "blocks" (int): The explicit number of blocks, if set.
+    },...
+  }`,
+	},
+	tokenAllowancesRoute: {
+		argsShort: `assetID`,
+		cmdSummary: `List the token's allowances for the swap contracts of each supported
+  contract version.`,
+		argsLong: `Args:
+    assetID (int): The token's asset ID. e.g. 60001 for USDC.eth.`,
+		returns: `Returns:
+  array: The allowances.
+  [
+    {
+      "version" (int): The swap contract version.
+      "spender" (string): The swap contract address.
+      "allowance" (int): The allowance, in the token's atomic units.
+      "unlimited" (bool): Whether the allowance is effectively unlimited.
+      "status" (int): The approval status. 0 = approved, 1 = pending,
+        2 = not approved.
+    },...
+  ]`,
+	},
+	setTokenAllowanceRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `assetID version allowance`,
+		cmdSummary: `Set the token's allowance for a swap contract version. An allowance
+  of zero revokes the approval. A limited allowance caps the total value of
+  the token that can be swapped before the allowance must be set again.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The DEX client password.`,
+		argsLong: `Args:
+    assetID (int): The token's asset ID. e.g. 60001 for USDC.eth.
+    version (int): The swap contract version.
+    allowance (int): The allowance, in the token's atomic units.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(allowanceSetStr, "[symbol]", "[txID]") + `"`,
+	},
+	troubledMatchesRoute: {
+		cmdSummary: `List the active matches that are not progressing on their own, with
+  the actions available to resolve them with recoverswap.`,
+		returns: `Returns:
+  array: The troubled matches.
+  [
+    {
+      "host" (string): The DEX host.
+      "marketID" (string): The market.
+      "orderID" (string): The order ID.
+      "matchID" (string): The match ID.
+      "status" (int): The match status.
+      "side" (int): 0 for maker, 1 for taker.
+      "qty" (int): The match quantity, in atoms of the base asset.
+      "rate" (int): The match rate.
+      "fromAsset" (int): The asset we swap.
+      "toAsset" (int): The asset we redeem.
+      "revoked" (bool): Whether the match is revoked.
+      "lockTime" (int): When our swap can be refunded (ms UNIX), if known.
+      "problems" (array): Why the match needs attention.
+      "options" (array): The available actions.
+      [
+        {
+          "action" (string): refund, redeem, or abandon.
+          "description" (string): The consequences of the action.
+          "needsSecret" (bool): Whether the secret must be provided.
+        },...
+      ]
+    },...
+  ]`,
+	},
+	recoverSwapRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `"orderID" "matchID" "action" ("secret")`,
+		cmdSummary: `Take an action to resolve a troubled match. The action must be one of
+  the options listed for the match by troubledmatches. An abandoned match is
+  no longer tracked, and any funds in our swap contract must be refunded
+  outside of the app.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The DEX client password.`,
+		argsLong: `Args:
+    orderID (string): The hex order ID.
+    matchID (string): The hex match ID.
+    action (string): refund, redeem, or abandon.
+    secret (string): Optional. The hex swap secret, required to redeem as
+      taker if the maker's redemption has not been found.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(swapRecoveredStr, "[action]", "[matchID]") + `"`,
+	},
+	bondPlanRoute: {
+		cmdSummary: `Propose how to fund the upcoming bond renewals of the DEX accounts
+  with a target tier. Every bond asset with a connected wallet is considered,
+  wallet balances are allocated to the accounts that renew first, and the
+  cheapest sufficient asset by estimated fees is recommended. Use
+  applybondplan to switch the accounts to the recommended bond assets.`,
+		returns: `Returns:
+  obj: The bond funding plan.
+  {
+    "hosts" (array): The plans for each DEX account, by first renewal.
+    [
+      {
+        "host" (string): The DEX host.
+        "bondAssetID" (int): The current bond asset.
+        "targetTier" (int): The target tier.
+        "renewals" (array): The upcoming bond postings.
+        [
+          {
+            "time" (int): When the bonds will be posted (unix seconds).
+            "tiers" (int): The bond increments to post.
+          },...
+        ]
+        "options" (array): The wallets that could fund the renewals.
+        [
+          {
+            "assetID" (int): The asset ID.
+            "symbol" (string): The asset symbol.
+            "required" (int): The amount required, including fees.
+            "fees" (int): The estimated transaction fees.
+            "fiatFees" (float): The fees in fiat, if a rate is known.
+            "available" (int): The funds available for the renewals.
+            "sufficient" (bool): Whether the funds are sufficient.
+          },...
+        ]
+        "recommendedAssetID" (int): The recommended bond asset, if any.
+        "consolidate" (bool): Whether the account's bonds are in other
+          assets, and would be consolidated by renewing with the
+          recommended asset.
+      },...
+    ]
+  }`,
+	},
+	applyBondPlanRoute: {
+		argsShort: `("host")`,
+		cmdSummary: `Create a bond funding plan and switch the bond asset of the DEX accounts
+  to the recommended assets. Review the plan with bondplan first.`,
+		argsLong: `Args:
+    host (string): Optional. Only apply the plan for this DEX host.`,
+		returns: `Returns:
+    string: The message "` + bondPlanDoneStr + `"`,
+	},
+	tradingLimitsRoute: {
+		argsShort: `("host")`,
+		cmdSummary: `Show the parcels in use by active orders and settling matches, and the
+  remaining trading limit, for each DEX account. Orders that would exceed the
+  limit are rejected by the server. A "tradinglimit" notification is sent when
+  the usage or limit changes, with a warning when 80% of the limit is in use.`,
+		argsLong: `Args:
+    host (string): Optional. Only show the limits for this DEX host.`,
+		returns: `Returns:
+  array: The trading limits.
+  [
+    {
+      "host" (string): The DEX host.
+      "tier" (int): The effective tier, which the limit is based on.
+      "usedParcels" (int): The parcels in use.
+      "parcelLimit" (int): The parcel limit.
+      "remainingParcels" (int): The parcels that can still be traded.
+      "remainingLots" (obj): The approximate number of lots that can still be
+        ordered on each market, keyed by market name.
+      "approaching" (bool): Whether the usage is near or over the limit.
+    },...
+  ]`,
+	},
+	createAPIKeyRoute: {
+		argsShort: `"label" "scopes" ("lifetime")`,
+		cmdSummary: `Create an API key. Requests authenticated with the key with an
+  "Authorization: Bearer [key]" header are limited to the routes allowed by
+  the key's scopes. The key is only shown once.`,
+		argsLong: `Args:
+    label (string): A label to identify the key.
+    scopes (string): A comma-separated list of scopes. Every key can use the
+      routes that only report the state of the app. The scopes are:
+        read: Only the reporting routes.
+        trade: Placing and canceling orders and running bots.
+        send: Sending funds from the wallets.
+        admin: All routes, including API key management.
+    lifetime (string): Optional. How long until the key expires, e.g. "720h".
+      The key does not expire if not set.`,
+		returns: `Returns:
+  obj: The new key.
+  {
+    "id" (string): The key's ID.
+    "label" (string): The key's label.
+    "scopes" (array): The key's scopes.
+    "created" (int): The creation time in unix milliseconds.
+    "expiration" (int): The expiration time in unix milliseconds, if any.
+    "key" (string): The key to authenticate with.
+  }`,
+	},
+	apiKeysRoute: {
+		cmdSummary: `List the API keys.`,
+		returns: `Returns:
+  array: The API keys.
+  [
+    {
+      "id" (string): The key's ID.
+      "label" (string): The key's label.
+      "scopes" (array): The key's scopes.
+      "created" (int): The creation time in unix milliseconds.
+      "expiration" (int): The expiration time in unix milliseconds, if any.
+    },...
+  ]`,
+	},
+	revokeAPIKeyRoute: {
+		argsShort:  `"id"`,
+		cmdSummary: `Revoke an API key.`,
+		argsLong: `Args:
+    id (string): The key's ID.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(revokedAPIKeyStr, "[id]") + `"`,
+	},
+	addClientCertRoute: {
+		argsShort: `"fingerprint" "label" "scopes"`,
+		cmdSummary: `Allow a TLS client certificate to authenticate with the RPC server.
+  Clients presenting the certificate do not need the RPC username and
+  password, and are limited to the routes allowed by the certificate's
+  scopes. Self-signed certificates can be used, e.g.
+    openssl req -x509 -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+      -days 365 -subj /CN=bot -keyout client.key -out client.cert
+  Adding an allowed certificate again replaces its label and scopes.`,
+		argsLong: `Args:
+    fingerprint (string): The hex-encoded SHA-256 fingerprint of the
+      certificate, e.g. from
+        openssl x509 -noout -fingerprint -sha256 -in client.cert
+    label (string): A label to identify the certificate.
+    scopes (string): A comma-separated list of scopes. See createapikey.`,
+		returns: `Returns:
+  obj: The allowed certificate.
+  {
+    "fingerprint" (string): The certificate's fingerprint.
+    "label" (string): The certificate's label.
+    "scopes" (array): The certificate's scopes.
+    "added" (int): The time the certificate was added in unix milliseconds.
+  }`,
+	},
+	clientCertsRoute: {
+		cmdSummary: `List the TLS client certificates that are allowed to authenticate.`,
+		returns: `Returns:
+  array: The allowed certificates.
+  [
+    {
+      "fingerprint" (string): The certificate's fingerprint.
+      "label" (string): The certificate's label.
+      "scopes" (array): The certificate's scopes.
+      "added" (int): The time the certificate was added in unix milliseconds.
+    },...
+  ]`,
+	},
+	revokeClientCertRoute: {
+		argsShort:  `"fingerprint"`,
+		cmdSummary: `Revoke a TLS client certificate.`,
+		argsLong: `Args:
+    fingerprint (string): The certificate's SHA-256 fingerprint.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(revokedCertStr, "[fingerprint]") + `"`,
 	},
 	withdrawRoute: {
 		pwArgsShort: `"appPass"`,
@@ -1691,11 +2646,15 @@ needed to complete a swap.`,
 	},
 	stopBotRoute: {
 		cmdSummary: `Stop market making.`,
-		argsShort:  `(host) (baseID) (quoteID)`,
+		argsShort:  `(host) (baseID) (quoteID) (windDown)`,
 		argsLong: `Args:
 		host (string): The DEX address.
 		baseID (int): The base asset's BIP-44 registered coin index.
-		quoteID (int): The quote asset's BIP-44 registered coin index.`,
+		quoteID (int): The quote asset's BIP-44 registered coin index.
+		windDown (bool): Optional. If true, the bot stops placing orders and
+		  cancels its booked orders, but only stops once the swaps of its
+		  matches and its pending trades and transfers are complete. Default
+		  is false.`,
 	},
 	mmAvailableBalancesRoute: {
 		cmdSummary: `Get available balances for starting a bot or adding additional balance to a running bot.`,
@@ -1730,6 +2689,127 @@ needed to complete a swap.`,
 		dexInventory (obj): The DEX inventory adjustments i.e. [[60,-1000000],[42,10000000]].
 		cexInventory (obj): The CEX inventory adjustments i.e. [[60,-1000000],[42,10000000]].`,
 	},
+	mmDryRunRoute: {
+		cmdSummary: `Validate a bot config and calculate the placements the bot would make with the
+current market data, without placing any orders. The placements are the bot's desired placements,
+before they are limited by the bot's balances.`,
+		argsShort: `cfgPath host baseID quoteID`,
+		argsLong: `Args:
+		cfgPath (string): The path to the market maker config file.
+		host (string): The DEX address.
+		baseID (int): The base asset's BIP-44 registered coin index.
+		quoteID (int): The quote asset's BIP-44 registered coin index.`,
+		returns: `Returns:
+  obj: The dry run report.
+  {
+    buyPlacements ([obj]): The buy placements, with their rates and lots.
+    sellPlacements ([obj]): The sell placements, with their rates and lots.
+    feeGap (obj): The fee gap statistics.
+  }`,
+	},
+	mmAllocationRoute: {
+		cmdSummary: `Calculate the balances that a bot requires to fund all of its placements, including
+the reserves for swap, redeem and refund fees. The quote asset is valued at the oracle rate, or the
+DEX book's mid-gap rate if there is no oracle rate.`,
+		argsShort: `cfgPath host baseID quoteID`,
+		argsLong: `Args:
+		cfgPath (string): The path to the market maker config file.
+		host (string): The DEX address.
+		baseID (int): The base asset's BIP-44 registered coin index.
+		quoteID (int): The quote asset's BIP-44 registered coin index.`,
+		returns: `Returns:
+  obj: The recommended allocation.
+  {
+    rate (int): The message-rate used to value the quote asset.
+    buyLots (int): The lots the bot places on the buy side.
+    sellLots (int): The lots the bot places on the sell side.
+    dex (obj): The breakdown of the allocation of each DEX asset into lots,
+      bookingFees, redeemFees, fundingFees, refundBuffer and total.
+    cex (obj): The breakdown of the allocation of each CEX asset.
+    alloc (obj): The total allocations, in the format of the rpcConfig alloc.
+  }`,
+	},
+	mmQuoteQualityRoute: {
+		cmdSummary: `Get the fill and quote quality statistics of a running bot, aggregated over the bot's run.`,
+		argsShort:  `host baseID quoteID`,
+		argsLong: `Args:
+		host (string): The DEX address.
+		baseID (int): The base asset's BIP-44 registered coin index.
+		quoteID (int): The quote asset's BIP-44 registered coin index.`,
+		returns: `Returns:
+  obj: The statistics.
+  {
+    epochs (int): The number of epochs reported.
+    buyFilledLots ([int]): The lots filled for each buy placement.
+    sellFilledLots ([int]): The lots filled for each sell placement.
+    quotedBuyLots (int): The sum of the buy lots quoted in each epoch.
+    quotedSellLots (int): The sum of the sell lots quoted in each epoch.
+    buyTimeAtBest (float): The ratio of epochs in which the bot had the best buy.
+    sellTimeAtBest (float): The ratio of epochs in which the bot had the best sell.
+    buyQuoteToFill (float): The ratio of the buy lots quoted to the buy lots filled.
+    sellQuoteToFill (float): The ratio of the sell lots quoted to the sell lots filled.
+  }`,
+	},
+	mmBenchmarkRoute: {
+		cmdSummary: `Compare a market making run with a buy-and-hold benchmark of the same starting
+inventory over the same period. The benchmark's inventory is adjusted by any inventory
+changes made during the run. USD values are at the fiat rates at the end of the run, or
+the latest rates for a running bot.`,
+		argsShort: `host baseID quoteID startTime`,
+		argsLong: `Args:
+		host (string): The DEX address.
+		baseID (int): The base asset's BIP-44 registered coin index.
+		quoteID (int): The quote asset's BIP-44 registered coin index.
+		startTime (int): The start time of the run, in unix seconds. See mmstatus.`,
+		returns: `Returns:
+  obj: The report.
+  {
+    startTime (int): The start time of the run.
+    endTime (int): The end time of the run, or now for a running bot.
+    running (bool): Whether the bot is still running.
+    hold (obj): The benchmark's inventory, by asset ID.
+    holdUSD (float): The value of the benchmark's inventory.
+    holdStartUSD (float): The value of the benchmark's inventory at the start of the run.
+      Zero if the starting fiat rates were not recorded.
+    holdReturn (float): The benchmark's return over the period.
+    bot (obj): The bot's inventory, by asset ID.
+    botUSD (float): The value of the bot's inventory.
+    botReturn (float): The bot's return over the period.
+    excessUSD (float): botUSD less holdUSD. Positive if running the bot beat holding.
+    excessRatio (float): excessUSD as a ratio of holdUSD.
+    annualizedExcessRatio (float): excessRatio scaled to a year.
+    fees (obj): The on-chain fees paid, by asset ID.
+    feesUSD (float): The value of the on-chain fees.
+    cexCosts (obj): The CEX deposit and withdrawal fees, by asset ID.
+    cexCostsUSD (float): The value of the CEX costs.
+    grossExcessUSD (float): excessUSD before fees and CEX costs.
+    beatsHold (bool): Whether running the bot beat holding.
+  }`,
+	},
+	convertCEXInventoryRoute: {
+		cmdSummary: `Convert a running bot's CEX inventory from one asset to another. If there is
+no direct market between the assets, the conversion is routed through an intermediate asset,
+e.g. DCR -> BTC -> USDT. The route with the best output within the slippage limit is used.`,
+		argsShort: `host baseID quoteID fromID toID qty maxSlippage`,
+		argsLong: `Args:
+		host (string): The DEX address.
+		baseID (int): The bot's base asset's BIP-44 registered coin index.
+		quoteID (int): The bot's quote asset's BIP-44 registered coin index.
+		fromID (int): The BIP-44 registered coin index of the asset to convert.
+		toID (int): The BIP-44 registered coin index of the asset to convert to.
+		qty (int): The amount of the fromID asset to convert, in atoms.
+		maxSlippage (float): The maximum slippage against the mid-gap rates, e.g. 0.01 for 1%.`,
+		returns: `Returns:
+  obj: The conversion.
+  {
+    fromID (int): The asset being converted.
+    toID (int): The asset being converted to.
+    qty (int): The amount being converted.
+    path ([int]): The assets along the route.
+    estimatedOut (int): The estimated amount of the toID asset received.
+    slippage (float): The estimated slippage.
+  }`,
+	},
 	stakeStatusRoute: {
 		cmdSummary: `Get stake status. `,
 		argsShort:  `assetID`,
@@ -1837,6 +2917,78 @@ an spv wallet and enables options to view and set the vsp.
 		  assetID (int): The asset's BIP-44 registered coin index.
 		  txID (string): The transaction ID.`,
 	},
+	translationReportRoute: {
+		argsShort:  `("lang")`,
+		cmdSummary: `Report missing and invalid notification translations, for translators.`,
+		argsLong: `Args:
+		  lang (string): Optional. The BCP 47 language tag, e.g. de-DE. If not
+		  specified, all languages are reported.`,
+		returns: `Returns:
+  array: The translation reports.
+  [
+    {
+      "lang" (string): The language.
+      "translated" (int): The number of translated topics.
+      "inherited" (array): Topics using a parent language's translation.
+      "missing" (array): Topics shown in en-US for lack of a translation.
+      "missingStrings" (array): Empty subjects and templates, e.g. "OrderPlaced subject".
+      "badFormat" (object): Topics with template format arguments that do
+        not match the en-US template, and the problem.
+      "unknown" (array): Translated topics that do not exist.
+    },...
+  ]`,
+	},
+	backupRoute: {
+		cmdSummary: `Create an encrypted backup of the database and the wallet
+  configurations now. The backup is encrypted with a key derived from the app
+  seed, and can only be restored with the seed. Must be logged in.`,
+		returns: `Returns:
+    string: The path of the backup file.`,
+	},
+	restoreBackupRoute: {
+		pwArgsShort: `"seed"`,
+		argsShort:   `path`,
+		cmdSummary: `Decrypt and validate an encrypted backup. The database and a wallets.json
+  file of the wallet configurations are written to a "restored" folder next to
+  the current database. To use the restored database, stop Bison Wallet and
+  replace the current database file with it.`,
+		pwArgsLong: `Password Args:
+    seed (string): The app seed, as a mnemonic or hex.`,
+		argsLong: `Args:
+    path (string): The path of the backup file.`,
+		returns: `Returns:
+  obj: The restored files.
+  {
+    "stamp" (int): When the backup was created, in unix milliseconds.
+    "dbPath" (string): The path of the restored database.
+    "walletsPath" (string): The path of the restored wallet configurations.
+    "wallets" (int): The number of wallet configurations.
+  }`,
+	},
+	dbMaintenanceRoute: {
+		cmdSummary: `Check the consistency of the database, reporting corrupt records,
+  matches for orders that are not in the database, and active orders and
+  matches for assets with no wallet. The database file is compacted when Bison
+  Wallet is stopped. To check and compact the database without running Bison
+  Wallet, start it with --maintenance.`,
+		returns: `Returns:
+  obj: The integrity report.
+  {
+    "orders" (int): The number of orders checked.
+    "matches" (int): The number of matches checked.
+    "size" (int): The size of the database in bytes.
+    "freeBytes" (int): The unused bytes that compaction will reclaim.
+    "problems" (array): The problems found.
+    [
+      {
+        "kind" (string): corruptFile, corruptRecord, orphanedMatch or
+          missingWallet.
+        "id" (string): The order or match ID, if applicable.
+        "details" (string): A description of the problem.
+      },...
+    ]
+  }`,
+	},
 	withdrawBchSpvRoute: {
 		pwArgsShort: `"appPass"`,
 		argsShort:   `recipient`,
@@ -8,12 +8,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/websocket"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
@@ -237,6 +239,523 @@ func TestHandleVersion(t *testing.T) {
 	}
 }
 
+func TestHandleTranslationReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      *RawParams
+		wantReports int
+		wantErrCode int
+	}{{
+		name:        "all languages",
+		params:      &RawParams{},
+		wantReports: len(core.TranslationReports()),
+		wantErrCode: -1,
+	}, {
+		name:        "one language",
+		params:      &RawParams{Args: []string{"de-DE"}},
+		wantReports: 1,
+		wantErrCode: -1,
+	}, {
+		name:        "unknown language",
+		params:      &RawParams{Args: []string{"ja"}},
+		wantErrCode: msgjson.RPCTranslationReportError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{Args: []string{"de-DE", "zh-CN"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		r := &RPCServer{core: &TCore{}}
+		payload := handleTranslationReport(r, test.params)
+		var res []*core.TranslationReport
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && len(res) != test.wantReports {
+			t.Fatalf("%s: expected %d reports, got %d", test.name, test.wantReports, len(res))
+		}
+	}
+}
+
+func TestHandleRestoreBackup(t *testing.T) {
+	params := &RawParams{
+		PWArgs: []encode.PassBytes{encode.PassBytes("seed")},
+		Args:   []string{"/path/to/backup.bwbak"},
+	}
+	tests := []struct {
+		name             string
+		params           *RawParams
+		restoreBackupErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:             "core.RestoreBackup error",
+		params:           params,
+		restoreBackupErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCRestoreBackupError,
+	}, {
+		name:        "no seed",
+		params:      &RawParams{Args: params.Args},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "no path",
+		params:      &RawParams{PWArgs: params.PWArgs},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			restoredBackup:   &core.RestoredBackup{Wallets: 2},
+			restoreBackupErr: test.restoreBackupErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleRestoreBackup(r, test.params)
+		res := new(core.RestoredBackup)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && res.Wallets != 2 {
+			t.Fatalf("%s: wrong result %+v", test.name, res)
+		}
+	}
+}
+
+func TestHandleDBMaintenance(t *testing.T) {
+	tests := []struct {
+		name             string
+		params           *RawParams
+		dbMaintenanceErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      &RawParams{},
+		wantErrCode: -1,
+	}, {
+		name:             "core.DBMaintenance error",
+		params:           &RawParams{},
+		dbMaintenanceErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCDBMaintenanceError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{Args: []string{"arg"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			integrityReport: &db.IntegrityReport{
+				Orders:   3,
+				Problems: []*db.IntegrityProblem{{Kind: db.ProblemOrphanedMatch}},
+			},
+			dbMaintenanceErr: test.dbMaintenanceErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleDBMaintenance(r, test.params)
+		res := new(db.IntegrityReport)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && (res.Orders != 3 || len(res.Problems) != 1) {
+			t.Fatalf("%s: wrong result %+v", test.name, res)
+		}
+	}
+}
+
+func TestHandleSyncProgress(t *testing.T) {
+	tests := []struct {
+		name            string
+		params          *RawParams
+		syncProgressErr error
+		wantErrCode     int
+	}{{
+		name:        "ok",
+		params:      &RawParams{Args: []string{"42"}},
+		wantErrCode: -1,
+	}, {
+		name:            "core.WalletSyncProgress error",
+		params:          &RawParams{Args: []string{"42"}},
+		syncProgressErr: errors.New("error"),
+		wantErrCode:     msgjson.RPCSyncProgressError,
+	}, {
+		name:        "bad asset ID",
+		params:      &RawParams{Args: []string{"dcr"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "no asset ID",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			syncProgress:    &core.WalletSyncProgress{AssetID: 42, Rescan: true, Height: 100},
+			syncProgressErr: test.syncProgressErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleSyncProgress(r, test.params)
+		res := new(core.WalletSyncProgress)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && (!res.Rescan || res.Height != 100) {
+			t.Fatalf("%s: wrong result %+v", test.name, res)
+		}
+	}
+}
+
+func TestParseSetConfTargetArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    *db.ConfTarget
+		wantErr bool
+	}{{
+		name: "speed",
+		args: []string{"0", "Economy"},
+		want: &db.ConfTarget{Speed: db.ConfSpeedEconomy},
+	}, {
+		name: "blocks",
+		args: []string{"0", "6"},
+		want: &db.ConfTarget{Blocks: 6},
+	}, {
+		name: "default",
+		args: []string{"0", "default"},
+	}, {
+		name: "no target",
+		args: []string{"0"},
+	}, {
+		name:    "bad target",
+		args:    []string{"0", "soon"},
+		wantErr: true,
+	}, {
+		name:    "bad asset ID",
+		args:    []string{"btc", "fast"},
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		form, err := parseSetConfTargetArgs(&RawParams{Args: test.args})
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s: no error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if (form.target == nil) != (test.want == nil) || (test.want != nil && *form.target != *test.want) {
+			t.Fatalf("%s: wrong target %+v", test.name, form.target)
+		}
+	}
+}
+
+func TestHandleSetConfTarget(t *testing.T) {
+	tests := []struct {
+		name             string
+		params           *RawParams
+		setConfTargetErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      &RawParams{Args: []string{"42", "fast"}},
+		wantErrCode: -1,
+	}, {
+		name:             "core.SetConfTarget error",
+		params:           &RawParams{Args: []string{"42", "fast"}},
+		setConfTargetErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCConfTargetError,
+	}, {
+		name:        "no args",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{setConfTargetErr: test.setConfTargetErr}
+		r := &RPCServer{core: tc}
+		payload := handleSetConfTarget(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && res != fmt.Sprintf(confTargetSetStr, "dcr") {
+			t.Fatalf("%s: wrong result %q", test.name, res)
+		}
+	}
+}
+
+func TestHandleSetTokenAllowance(t *testing.T) {
+	pw := encode.PassBytes("password123")
+	tests := []struct {
+		name              string
+		params            *RawParams
+		tokenAllowanceErr error
+		wantErrCode       int
+	}{{
+		name:        "ok",
+		params:      &RawParams{PWArgs: []encode.PassBytes{pw}, Args: []string{"60001", "0", "1000000"}},
+		wantErrCode: -1,
+	}, {
+		name:              "core.SetTokenAllowance error",
+		params:            &RawParams{PWArgs: []encode.PassBytes{pw}, Args: []string{"60001", "0", "1000000"}},
+		tokenAllowanceErr: errors.New("error"),
+		wantErrCode:       msgjson.RPCTokenAllowanceError,
+	}, {
+		name:        "bad allowance",
+		params:      &RawParams{PWArgs: []encode.PassBytes{pw}, Args: []string{"60001", "0", "-1"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "no password",
+		params:      &RawParams{Args: []string{"60001", "0", "1000000"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{tokenAllowanceTxID: "abcd", tokenAllowanceErr: test.tokenAllowanceErr}
+		r := &RPCServer{core: tc}
+		payload := handleSetTokenAllowance(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && res != fmt.Sprintf(allowanceSetStr, "usdc.eth", "abcd") {
+			t.Fatalf("%s: wrong result %q", test.name, res)
+		}
+	}
+}
+
+func TestHandleApplyBondPlan(t *testing.T) {
+	dcrID := uint32(42)
+	newPlan := func() *core.BondFundingPlan {
+		return &core.BondFundingPlan{Hosts: []*core.HostBondPlan{
+			{Host: "dex1.org", RecommendedAssetID: &dcrID},
+			{Host: "dex2.org"},
+		}}
+	}
+	tests := []struct {
+		name             string
+		params           *RawParams
+		bondPlanErr      error
+		applyBondPlanErr error
+		wantHosts        int
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      &RawParams{},
+		wantHosts:   2,
+		wantErrCode: -1,
+	}, {
+		name:        "ok one host",
+		params:      &RawParams{Args: []string{"dex2.org"}},
+		wantHosts:   1,
+		wantErrCode: -1,
+	}, {
+		name:        "unknown host",
+		params:      &RawParams{Args: []string{"dex3.org"}},
+		wantErrCode: msgjson.RPCBondPlanError,
+	}, {
+		name:        "core.BondFundingPlan error",
+		params:      &RawParams{},
+		bondPlanErr: errors.New("error"),
+		wantErrCode: msgjson.RPCBondPlanError,
+	}, {
+		name:             "core.ApplyBondFundingPlan error",
+		params:           &RawParams{},
+		applyBondPlanErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCBondPlanError,
+	}, {
+		name:        "too many args",
+		params:      &RawParams{Args: []string{"dex1.org", "dex2.org"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			bondPlan:         newPlan(),
+			bondPlanErr:      test.bondPlanErr,
+			applyBondPlanErr: test.applyBondPlanErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleApplyBondPlan(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		if res != bondPlanDoneStr {
+			t.Fatalf("%s: wrong result %q", test.name, res)
+		}
+		if len(tc.appliedBondPlan.Hosts) != test.wantHosts {
+			t.Fatalf("%s: expected %d hosts applied, got %d", test.name, test.wantHosts, len(tc.appliedBondPlan.Hosts))
+		}
+	}
+}
+
+func TestHandleAnnotateOrder(t *testing.T) {
+	const oid = "fb94fe99e4e32200a341f0f1cb33f34a08ac23eedab636e8adb991fa76343e1e"
+	const mid = "0a94fe99e4e32200a341f0f1cb33f34a08ac23eedab636e8adb991fa76343e1e"
+	tests := []struct {
+		name             string
+		params           *RawParams
+		annotateOrderErr error
+		wantTags         int
+		wantMatch        bool
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      &RawParams{Args: []string{oid, "manual trade"}},
+		wantErrCode: -1,
+	}, {
+		name:        "ok tags and match",
+		params:      &RawParams{Args: []string{oid, "", "bot,arb", mid}},
+		wantTags:    2,
+		wantMatch:   true,
+		wantErrCode: -1,
+	}, {
+		name:        "bad order ID",
+		params:      &RawParams{Args: []string{oid[2:], "note"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad match ID",
+		params:      &RawParams{Args: []string{oid, "note", "", "zz" + mid[2:]}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "missing note",
+		params:      &RawParams{Args: []string{oid}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:             "core.AnnotateOrder error",
+		params:           &RawParams{Args: []string{oid, "note"}},
+		annotateOrderErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCAnnotateOrderError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{annotateOrderErr: test.annotateOrderErr}
+		r := &RPCServer{core: tc}
+		payload := handleAnnotateOrder(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		form := tc.annotationForm
+		if form.OrderID.String() != oid || form.Note != test.params.Args[1] {
+			t.Fatalf("%s: wrong form %+v", test.name, form)
+		}
+		if len(form.Tags) != test.wantTags {
+			t.Fatalf("%s: expected %d tags, got %d", test.name, test.wantTags, len(form.Tags))
+		}
+		if (form.MatchID != nil) != test.wantMatch {
+			t.Fatalf("%s: wrong match ID %s", test.name, form.MatchID)
+		}
+	}
+}
+
+func TestHandleTradingLimits(t *testing.T) {
+	tests := []struct {
+		name             string
+		params           *RawParams
+		tradingLimitsErr error
+		wantHosts        []string
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      &RawParams{},
+		wantHosts:   []string{"dex1.org", "dex2.org"},
+		wantErrCode: -1,
+	}, {
+		name:        "ok one host",
+		params:      &RawParams{Args: []string{"dex2.org"}},
+		wantHosts:   []string{"dex2.org"},
+		wantErrCode: -1,
+	}, {
+		name:        "unknown host",
+		params:      &RawParams{Args: []string{"dex3.org"}},
+		wantErrCode: msgjson.RPCTradingLimitsError,
+	}, {
+		name:             "core.TradingLimitStatus error",
+		params:           &RawParams{Args: []string{"dex1.org"}},
+		tradingLimitsErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCTradingLimitsError,
+	}, {
+		name:        "too many args",
+		params:      &RawParams{Args: []string{"dex1.org", "dex2.org"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			tradingLimits: []*core.TradingLimitStatus{
+				{Host: "dex1.org", ParcelLimit: 4},
+				{Host: "dex2.org", ParcelLimit: 8},
+			},
+			tradingLimitsErr: test.tradingLimitsErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleTradingLimits(r, test.params)
+		var res []*core.TradingLimitStatus
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		if len(res) != len(test.wantHosts) {
+			t.Fatalf("%s: expected %d hosts, got %d", test.name, len(test.wantHosts), len(res))
+		}
+		for i, host := range test.wantHosts {
+			if res[i].Host != host {
+				t.Fatalf("%s: expected host %s, got %s", test.name, host, res[i].Host)
+			}
+		}
+	}
+}
+
+func TestHandleFeeReport(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       *RawParams
+		feeReportErr error
+		wantErrCode  int
+	}{{
+		name:        "ok",
+		params:      &RawParams{Args: []string{"1700000000000", "0"}},
+		wantErrCode: -1,
+	}, {
+		name:        "ok no range",
+		params:      &RawParams{},
+		wantErrCode: -1,
+	}, {
+		name:         "core.FeeReport error",
+		params:       &RawParams{},
+		feeReportErr: errors.New("error"),
+		wantErrCode:  msgjson.RPCFeeReportError,
+	}, {
+		name:        "bad time",
+		params:      &RawParams{Args: []string{"yesterday"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "too many args",
+		params:      &RawParams{Args: []string{"1", "2", "3"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			feeReport:    &core.FeeReport{Assets: []*core.AssetFees{{AssetID: 42, Total: 100}}},
+			feeReportErr: test.feeReportErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleFeeReport(r, test.params)
+		res := new(core.FeeReport)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && (len(res.Assets) != 1 || res.Assets[0].Total != 100) {
+			t.Fatalf("%s: wrong result %+v", test.name, res)
+		}
+	}
+}
+
 func TestHandleGetDEXConfig(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -1382,3 +1901,159 @@ func TestHandleSetVotingPreferences(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      *RawParams
+		disabled    bool
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &RawParams{Args: []string{"bot", "trade,send", "720h"}},
+		wantErrCode: -1,
+	}, {
+		name:        "ok no lifetime",
+		params:      &RawParams{Args: []string{"dashboard", "read"}},
+		wantErrCode: -1,
+	}, {
+		name:        "unknown scope",
+		params:      &RawParams{Args: []string{"bot", "trade,everything"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad lifetime",
+		params:      &RawParams{Args: []string{"bot", "trade", "forever"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "API keys disabled",
+		params:      &RawParams{Args: []string{"bot", "trade"}},
+		disabled:    true,
+		wantErrCode: msgjson.RPCAPIKeyError,
+	}}
+	for _, test := range tests {
+		r := &RPCServer{}
+		if !test.disabled {
+			var err error
+			if r.apiKeys, err = loadAPIKeys(filepath.Join(t.TempDir(), "apikeys.json")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		payload := handleCreateAPIKey(r, test.params)
+		res := new(createdAPIKey)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		if _, err := r.apiKeys.authenticate(res.Key); err != nil {
+			t.Fatalf("%s: created key does not authenticate: %v", test.name, err)
+		}
+		keys := handleAPIKeys(r, &RawParams{})
+		var list []*APIKey
+		if err := verifyResponse(keys, &list, -1); err != nil || len(list) != 1 || list[0].SecretHash != nil {
+			t.Fatalf("%s: wrong key list %v, %v", test.name, list, err)
+		}
+	}
+}
+
+func TestHandleRecoverFromSeed(t *testing.T) {
+	pws := []encode.PassBytes{encode.PassBytes("abc"), encode.PassBytes("seed")}
+	tests := []struct {
+		name        string
+		params      *RawParams
+		recoveryErr error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &RawParams{PWArgs: pws, Args: []string{"42,0", "dex.example.com:7232"}},
+		wantErrCode: -1,
+	}, {
+		name:        "ok no args",
+		params:      &RawParams{PWArgs: pws},
+		wantErrCode: -1,
+	}, {
+		name:        "core.RecoverFromSeed error",
+		params:      &RawParams{PWArgs: pws},
+		recoveryErr: errors.New("error"),
+		wantErrCode: msgjson.RPCRecoveryError,
+	}, {
+		name:        "bad asset ID",
+		params:      &RawParams{PWArgs: pws, Args: []string{"dcr"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "no seed",
+		params:      &RawParams{PWArgs: pws[:1]},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			recoveryReport: &core.RecoveryReport{ManualActions: []string{"do something"}},
+			recoveryErr:    test.recoveryErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleRecoverFromSeed(r, test.params)
+		res := new(core.RecoveryReport)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && len(res.ManualActions) != 1 {
+			t.Fatalf("%s: wrong result %+v", test.name, res)
+		}
+	}
+}
+
+func TestHandleAddClientCert(t *testing.T) {
+	fp := strings.Repeat("ab", 32)
+	tests := []struct {
+		name        string
+		params      *RawParams
+		disabled    bool
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &RawParams{Args: []string{fp, "bot", "trade,send"}},
+		wantErrCode: -1,
+	}, {
+		name:        "bad fingerprint",
+		params:      &RawParams{Args: []string{"abcd", "bot", "trade"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "unknown scope",
+		params:      &RawParams{Args: []string{fp, "bot", "everything"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "client certs disabled",
+		params:      &RawParams{Args: []string{fp, "bot", "trade"}},
+		disabled:    true,
+		wantErrCode: msgjson.RPCClientCertError,
+	}}
+	for _, test := range tests {
+		r := &RPCServer{}
+		if !test.disabled {
+			var err error
+			if r.clientCerts, err = loadClientCerts(filepath.Join(t.TempDir(), "clientcerts.json")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		payload := handleAddClientCert(r, test.params)
+		res := new(ClientCert)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		var list []*ClientCert
+		if err := verifyResponse(handleClientCerts(r, &RawParams{}), &list, -1); err != nil || len(list) != 1 || list[0].Fingerprint != fp {
+			t.Fatalf("%s: wrong cert list %v, %v", test.name, list, err)
+		}
+		revokeRes := ""
+		if err := verifyResponse(handleRevokeClientCert(r, &RawParams{Args: []string{fp}}), &revokeRes, -1); err != nil {
+			t.Fatalf("%s: revoke error: %v", test.name, err)
+		}
+		if err := verifyResponse(handleRevokeClientCert(r, &RawParams{Args: []string{fp}}), &revokeRes, msgjson.RPCClientCertError); err != nil {
+			t.Fatalf("%s: revoking twice: %v", test.name, err)
+		}
+	}
+}
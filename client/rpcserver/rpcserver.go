@@ -17,6 +17,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -76,6 +77,20 @@ type clientCore interface {
 	Wallets() (walletsStates []*core.WalletState)
 	WalletState(assetID uint32) *core.WalletState
 	RescanWallet(assetID uint32, force bool) error
+	WalletSyncProgress(assetID uint32) (*core.WalletSyncProgress, error)
+	FeeReport(from, to time.Time) (*core.FeeReport, error)
+	RecoverFromSeed(form *core.RecoveryForm) (*core.RecoveryReport, error)
+	SetConfTarget(assetID uint32, target *db.ConfTarget) error
+	ConfTargets() map[uint32]*db.ConfTarget
+	TokenAllowances(assetID uint32) ([]*asset.TokenAllowance, error)
+	SetTokenAllowance(appPW []byte, assetID, version uint32, allowance uint64) (string, error)
+	TroubledMatches() ([]*core.TroubledMatch, error)
+	RecoverSwap(pw []byte, form *core.SwapRecoveryForm) error
+	BondFundingPlan() (*core.BondFundingPlan, error)
+	ApplyBondFundingPlan(plan *core.BondFundingPlan) error
+	AnnotateOrder(form *core.AnnotationForm) error
+	TradingLimitStatus(host string) (*core.TradingLimitStatus, error)
+	TradingLimitStatuses() []*core.TradingLimitStatus
 	Send(appPass []byte, assetID uint32, value uint64, addr string, subtract bool) (asset.Coin, error)
 	ExportSeed(pw []byte) (string, error)
 	DeleteArchivedRecords(olderThan *time.Time, matchesFileStr, ordersFileStr string) (int, error)
@@ -86,6 +101,9 @@ type clientCore interface {
 	MultiTrade(pw []byte, form *core.MultiTradeForm) []*core.MultiTradeResult
 	TxHistory(assetID uint32, n int, refID *string, past bool) ([]*asset.WalletTransaction, error)
 	WalletTransaction(assetID uint32, txID string) (*asset.WalletTransaction, error)
+	BackupNow() (string, error)
+	RestoreBackup(backupPath, seed string) (*core.RestoredBackup, error)
+	DBMaintenance() (*db.IntegrityReport, error)
 
 	// These are core's ticket buying interface.
 	StakeStatus(assetID uint32) (*asset.TicketStakingStatus, error)
@@ -98,17 +116,19 @@ type clientCore interface {
 // RPCServer is a single-client http and websocket server enabling a JSON
 // interface to Bison Wallet.
 type RPCServer struct {
-	core      clientCore
-	mm        *mm.MarketMaker
-	mux       *chi.Mux
-	wsServer  *websocket.Server
-	addr      string
-	tlsConfig *tls.Config
-	srv       *http.Server
-	authSHA   [32]byte
-	wg        sync.WaitGroup
-	bwVersion *SemVersion
-	ctx       context.Context
+	core        clientCore
+	mm          *mm.MarketMaker
+	mux         *chi.Mux
+	wsServer    *websocket.Server
+	addr        string
+	tlsConfig   *tls.Config
+	srv         *http.Server
+	authSHA     [32]byte
+	apiKeys     *apiKeyStore     // nil if API keys are disabled
+	clientCerts *clientCertStore // nil if client certificates are disabled
+	wg          sync.WaitGroup
+	bwVersion   *SemVersion
+	ctx         context.Context
 }
 
 // genCertPair generates a key/cert pair to the paths provided.
@@ -184,6 +204,24 @@ func (s *RPCServer) handleJSON(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Responses not accepted", http.StatusMethodNotAllowed)
 		return
 	}
+	var deniedCredential string
+	if k := apiKeyFromContext(r.Context()); k != nil && !k.allows(req.Route) {
+		log.Warnf("API key %s (%s) is not allowed to use route %q", k.ID, k.Label, req.Route)
+		deniedCredential = "API key"
+	} else if c := clientCertFromContext(r.Context()); c != nil && !c.allows(req.Route) {
+		log.Warnf("Client certificate %s (%s) is not allowed to use route %q", c.Fingerprint, c.Label, req.Route)
+		deniedCredential = "client certificate"
+	}
+	if deniedCredential != "" {
+		resErr := msgjson.NewError(msgjson.RPCRouteNotAllowedError, "%s scopes do not allow %s", deniedCredential, req.Route)
+		resp, err := msgjson.NewResponse(req.ID, nil, resErr)
+		if err != nil {
+			http.Error(w, "error encoding response", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, resp)
+		return
+	}
 	s.parseHTTPRequest(w, req)
 }
 
@@ -194,6 +232,12 @@ type Config struct {
 	Addr, User, Pass, Cert, Key string
 	BWVersion                   *SemVersion
 	CertHosts                   []string
+	// APIKeys is the path of the API keys file. API key authentication is
+	// disabled if it is empty.
+	APIKeys string
+	// ClientCerts is the path of the TLS client certificate allowlist file.
+	// Client certificate authentication is disabled if it is empty.
+	ClientCerts string
 }
 
 // SetLogger sets the logger for the RPCServer package.
@@ -257,6 +301,22 @@ func New(cfg *Config) (*RPCServer, error) {
 		base64.StdEncoding.EncodeToString([]byte(login))
 	s.authSHA = sha256.Sum256([]byte(auth))
 
+	if cfg.APIKeys != "" {
+		if s.apiKeys, err = loadAPIKeys(cfg.APIKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.ClientCerts != "" {
+		if s.clientCerts, err = loadClientCerts(cfg.ClientCerts); err != nil {
+			return nil, err
+		}
+		// Ask for, but don't require, a client certificate. The certificate
+		// is checked against the allowlist in authMiddleware, and clients
+		// without one can still use the other authentication methods.
+		tlsConfig.ClientAuth = tls.RequestClientCert
+	}
+
 	// Middleware
 	mux.Use(middleware.Recoverer)
 	mux.Use(middleware.RealIP)
@@ -364,11 +424,32 @@ func (s *RPCServer) authMiddleware(next http.Handler) http.Handler {
 			w.Header().Add("WWW-Authenticate", `Basic realm="dex RPC"`)
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		}
+		if s.clientCerts != nil && r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			c, err := s.clientCerts.authenticate(r.TLS.PeerCertificates[0])
+			if err == nil {
+				log.Debugf("authenticated client certificate %s (%s) with ip: %s", c.Fingerprint, c.Label, r.RemoteAddr)
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientCertCtxKey{}, c)))
+				return
+			}
+			// Fall back to the Authorization header.
+			log.Debugf("Client certificate authentication error: %v", err)
+		}
 		auth := r.Header["Authorization"]
 		if len(auth) == 0 {
 			fail()
 			return
 		}
+		if keyStr, isBearer := strings.CutPrefix(auth[0], "Bearer "); isBearer && s.apiKeys != nil {
+			k, err := s.apiKeys.authenticate(keyStr)
+			if err != nil {
+				log.Debugf("API key authentication error: %v", err)
+				fail()
+				return
+			}
+			log.Debugf("authenticated API key %s (%s) with ip: %s", k.ID, k.Label, r.RemoteAddr)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyCtxKey{}, k)))
+			return
+		}
 		authSHA := sha256.Sum256([]byte(auth[0]))
 		if subtle.ConstantTimeCompare(s.authSHA[:], authSHA[:]) != 1 {
 			fail()
@@ -8,12 +8,21 @@ package rpcserver
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -71,6 +80,33 @@ type TCore struct {
 	stakeStatus              *asset.TicketStakingStatus
 	stakeStatusErr           error
 	setVotingPrefErr         error
+	backupPath               string
+	backupErr                error
+	restoredBackup           *core.RestoredBackup
+	restoreBackupErr         error
+	integrityReport          *db.IntegrityReport
+	dbMaintenanceErr         error
+	syncProgress             *core.WalletSyncProgress
+	syncProgressErr          error
+	feeReport                *core.FeeReport
+	feeReportErr             error
+	recoveryReport           *core.RecoveryReport
+	recoveryErr              error
+	confTargets              map[uint32]*db.ConfTarget
+	setConfTargetErr         error
+	tokenAllowances          []*asset.TokenAllowance
+	tokenAllowanceTxID       string
+	tokenAllowanceErr        error
+	troubledMatches          []*core.TroubledMatch
+	recoverSwapErr           error
+	bondPlan                 *core.BondFundingPlan
+	bondPlanErr              error
+	appliedBondPlan          *core.BondFundingPlan
+	applyBondPlanErr         error
+	annotationForm           *core.AnnotationForm
+	annotateOrderErr         error
+	tradingLimits            []*core.TradingLimitStatus
+	tradingLimitsErr         error
 }
 
 func (c *TCore) Balance(uint32) (uint64, error) {
@@ -201,6 +237,65 @@ func (c *TCore) WalletTransaction(assetID uint32, txID string) (*asset.WalletTra
 func (c *TCore) GenerateBCHRecoveryTransaction(appPW []byte, recipient string) ([]byte, error) {
 	return nil, nil
 }
+func (c *TCore) BackupNow() (string, error) {
+	return c.backupPath, c.backupErr
+}
+func (c *TCore) RestoreBackup(backupPath, seed string) (*core.RestoredBackup, error) {
+	return c.restoredBackup, c.restoreBackupErr
+}
+func (c *TCore) DBMaintenance() (*db.IntegrityReport, error) {
+	return c.integrityReport, c.dbMaintenanceErr
+}
+func (c *TCore) WalletSyncProgress(assetID uint32) (*core.WalletSyncProgress, error) {
+	return c.syncProgress, c.syncProgressErr
+}
+func (c *TCore) FeeReport(from, to time.Time) (*core.FeeReport, error) {
+	return c.feeReport, c.feeReportErr
+}
+func (c *TCore) RecoverFromSeed(form *core.RecoveryForm) (*core.RecoveryReport, error) {
+	return c.recoveryReport, c.recoveryErr
+}
+func (c *TCore) SetConfTarget(assetID uint32, target *db.ConfTarget) error {
+	return c.setConfTargetErr
+}
+func (c *TCore) ConfTargets() map[uint32]*db.ConfTarget {
+	return c.confTargets
+}
+func (c *TCore) TokenAllowances(assetID uint32) ([]*asset.TokenAllowance, error) {
+	return c.tokenAllowances, c.tokenAllowanceErr
+}
+func (c *TCore) SetTokenAllowance(appPW []byte, assetID, version uint32, allowance uint64) (string, error) {
+	return c.tokenAllowanceTxID, c.tokenAllowanceErr
+}
+func (c *TCore) TroubledMatches() ([]*core.TroubledMatch, error) {
+	return c.troubledMatches, nil
+}
+func (c *TCore) RecoverSwap(pw []byte, form *core.SwapRecoveryForm) error {
+	return c.recoverSwapErr
+}
+func (c *TCore) BondFundingPlan() (*core.BondFundingPlan, error) {
+	return c.bondPlan, c.bondPlanErr
+}
+func (c *TCore) ApplyBondFundingPlan(plan *core.BondFundingPlan) error {
+	c.appliedBondPlan = plan
+	return c.applyBondPlanErr
+}
+func (c *TCore) AnnotateOrder(form *core.AnnotationForm) error {
+	c.annotationForm = form
+	return c.annotateOrderErr
+}
+
+func (c *TCore) TradingLimitStatus(host string) (*core.TradingLimitStatus, error) {
+	for _, s := range c.tradingLimits {
+		if s.Host == host {
+			return s, c.tradingLimitsErr
+		}
+	}
+	return nil, fmt.Errorf("unknown host %s", host)
+}
+func (c *TCore) TradingLimitStatuses() []*core.TradingLimitStatus {
+	return c.tradingLimits
+}
 
 type tBookFeed struct{}
 
@@ -523,3 +618,259 @@ func TestAuthMiddleware(t *testing.T) {
 		wantAuthError(test.name, test.wantErr)
 	}
 }
+
+func TestAPIKeys(t *testing.T) {
+	s, shutdown := newTServer(t, false, "", "abc")
+	defer shutdown()
+	keysPath := filepath.Join(t.TempDir(), "apikeys.json")
+	var err error
+	if s.apiKeys, err = loadAPIKeys(keysPath); err != nil {
+		t.Fatalf("loadAPIKeys error: %v", err)
+	}
+
+	_, readKey, err := s.apiKeys.create("dashboard", []APIScope{ScopeRead}, 0)
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	tradeK, tradeKey, err := s.apiKeys.create("bot", []APIScope{ScopeTrade}, time.Hour)
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	if tradeK.SecretHash != nil || tradeK.Expiration == 0 {
+		t.Fatalf("wrong created key %+v", tradeK)
+	}
+	expiredK, expiredKey, err := s.apiKeys.create("old", []APIScope{ScopeAdmin}, time.Hour)
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	s.apiKeys.keys[expiredK.ID].Expiration = uint64(time.Now().Add(-time.Minute).UnixMilli())
+
+	// The keys are persisted.
+	reloaded, err := loadAPIKeys(keysPath)
+	if err != nil {
+		t.Fatalf("reload error: %v", err)
+	}
+	if len(reloaded.list()) != 3 {
+		t.Fatalf("expected 3 reloaded keys, got %d", len(reloaded.list()))
+	}
+
+	// The middleware authenticates the key and the request is limited to the
+	// key's scopes.
+	handler := s.authMiddleware(http.HandlerFunc(s.handleJSON))
+	request := func(key, route string) *tResponseWriter {
+		t.Helper()
+		msg, _ := msgjson.NewRequest(1, route, nil)
+		b, _ := json.Marshal(msg)
+		r, _ := http.NewRequest("POST", "", bytes.NewBuffer(b))
+		r.Header.Set("Authorization", "Bearer "+key)
+		w := &tResponseWriter{}
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	errCode := func(w *tResponseWriter) int {
+		t.Helper()
+		resp := new(msgjson.Message)
+		if err := json.Unmarshal(w.b, resp); err != nil {
+			t.Fatalf("unable to unmarshal response: %v", err)
+		}
+		payload := new(msgjson.ResponsePayload)
+		if err := json.Unmarshal(resp.Payload, payload); err != nil {
+			t.Fatalf("unable to unmarshal payload: %v", err)
+		}
+		if payload.Error == nil {
+			return -1
+		}
+		return payload.Error.Code
+	}
+
+	tests := []struct {
+		name, key, route string
+		wantUnauthorized bool
+		wantErrCode      int
+	}{{
+		name:        "read key read route",
+		key:         readKey,
+		route:       versionRoute,
+		wantErrCode: -1,
+	}, {
+		name:        "read key trade route",
+		key:         readKey,
+		route:       tradeRoute,
+		wantErrCode: msgjson.RPCRouteNotAllowedError,
+	}, {
+		name:        "trade key read route",
+		key:         tradeKey,
+		route:       versionRoute,
+		wantErrCode: -1,
+	}, {
+		name:        "trade key trade route",
+		key:         tradeKey,
+		route:       tradeRoute,
+		wantErrCode: msgjson.RPCArgumentsError, // allowed, but no args
+	}, {
+		name:        "trade key send route",
+		key:         tradeKey,
+		route:       sendRoute,
+		wantErrCode: msgjson.RPCRouteNotAllowedError,
+	}, {
+		name:        "trade key admin route",
+		key:         tradeKey,
+		route:       createAPIKeyRoute,
+		wantErrCode: msgjson.RPCRouteNotAllowedError,
+	}, {
+		name:             "expired key",
+		key:              expiredKey,
+		route:            versionRoute,
+		wantUnauthorized: true,
+	}, {
+		name:             "wrong secret",
+		key:              tradeK.ID + "." + strings.Repeat("00", apiKeySecretSize),
+		route:            versionRoute,
+		wantUnauthorized: true,
+	}, {
+		name:             "malformed key",
+		key:              "abc",
+		route:            versionRoute,
+		wantUnauthorized: true,
+	}}
+	for _, test := range tests {
+		w := request(test.key, test.route)
+		if test.wantUnauthorized {
+			if w.code != http.StatusUnauthorized {
+				t.Fatalf("%s: expected unauthorized, got code %d", test.name, w.code)
+			}
+			continue
+		}
+		if code := errCode(w); code != test.wantErrCode {
+			t.Fatalf("%s: wanted error code %d, got %d", test.name, test.wantErrCode, code)
+		}
+	}
+
+	// Revoked keys can't authenticate.
+	if err := s.apiKeys.revoke(tradeK.ID); err != nil {
+		t.Fatalf("revoke error: %v", err)
+	}
+	if w := request(tradeKey, versionRoute); w.code != http.StatusUnauthorized {
+		t.Fatalf("revoked key not unauthorized, got code %d", w.code)
+	}
+	if err := s.apiKeys.revoke(tradeK.ID); err == nil {
+		t.Fatalf("no error revoking unknown key")
+	}
+}
+
+func TestClientCerts(t *testing.T) {
+	s, shutdown := newTServer(t, false, "", "abc")
+	defer shutdown()
+	certsPath := filepath.Join(t.TempDir(), "clientcerts.json")
+	var err error
+	if s.clientCerts, err = loadClientCerts(certsPath); err != nil {
+		t.Fatalf("loadClientCerts error: %v", err)
+	}
+
+	newCert := func(notAfter time.Time) *x509.Certificate {
+		t.Helper()
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey error: %v", err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "bot"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+		if err != nil {
+			t.Fatalf("CreateCertificate error: %v", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("ParseCertificate error: %v", err)
+		}
+		return cert
+	}
+	tradeCert := newCert(time.Now().Add(time.Hour))
+	expiredCert := newCert(time.Now().Add(-time.Minute))
+	unknownCert := newCert(time.Now().Add(time.Hour))
+
+	// The colon-separated openssl format is accepted.
+	fp := certFingerprint(tradeCert)
+	var colonFP []string
+	for i := 0; i < len(fp); i += 2 {
+		colonFP = append(colonFP, strings.ToUpper(fp[i:i+2]))
+	}
+	parsedFP, err := parseFingerprint(strings.Join(colonFP, ":"))
+	if err != nil || parsedFP != fp {
+		t.Fatalf("wrong parsed fingerprint %s, %v", parsedFP, err)
+	}
+	if _, err := s.clientCerts.add(fp, "bot", []APIScope{ScopeTrade}); err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	if _, err := s.clientCerts.add(certFingerprint(expiredCert), "old", []APIScope{ScopeAdmin}); err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+
+	// The allowlist is persisted.
+	reloaded, err := loadClientCerts(certsPath)
+	if err != nil {
+		t.Fatalf("reload error: %v", err)
+	}
+	if len(reloaded.list()) != 2 {
+		t.Fatalf("expected 2 reloaded certs, got %d", len(reloaded.list()))
+	}
+
+	handler := s.authMiddleware(http.HandlerFunc(s.handleJSON))
+	request := func(cert *x509.Certificate, route string) *tResponseWriter {
+		t.Helper()
+		msg, _ := msgjson.NewRequest(1, route, nil)
+		b, _ := json.Marshal(msg)
+		r, _ := http.NewRequest("POST", "", bytes.NewBuffer(b))
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		w := &tResponseWriter{}
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	errCode := func(w *tResponseWriter) int {
+		t.Helper()
+		resp := new(msgjson.Message)
+		if err := json.Unmarshal(w.b, resp); err != nil {
+			t.Fatalf("unable to unmarshal response: %v", err)
+		}
+		payload := new(msgjson.ResponsePayload)
+		if err := json.Unmarshal(resp.Payload, payload); err != nil {
+			t.Fatalf("unable to unmarshal payload: %v", err)
+		}
+		if payload.Error == nil {
+			return -1
+		}
+		return payload.Error.Code
+	}
+
+	if code := errCode(request(tradeCert, versionRoute)); code != -1 {
+		t.Fatalf("read route not allowed. code %d", code)
+	}
+	if code := errCode(request(tradeCert, tradeRoute)); code != msgjson.RPCArgumentsError {
+		t.Fatalf("trade route not allowed. code %d", code)
+	}
+	if code := errCode(request(tradeCert, sendRoute)); code != msgjson.RPCRouteNotAllowedError {
+		t.Fatalf("send route allowed. code %d", code)
+	}
+	// Without a password, unknown and expired certificates are unauthorized.
+	if w := request(unknownCert, versionRoute); w.code != http.StatusUnauthorized {
+		t.Fatalf("unknown cert not unauthorized, got code %d", w.code)
+	}
+	if w := request(expiredCert, versionRoute); w.code != http.StatusUnauthorized {
+		t.Fatalf("expired cert not unauthorized, got code %d", w.code)
+	}
+
+	// Revoked certificates can't authenticate.
+	if err := s.clientCerts.revoke(fp); err != nil {
+		t.Fatalf("revoke error: %v", err)
+	}
+	if w := request(tradeCert, versionRoute); w.code != http.StatusUnauthorized {
+		t.Fatalf("revoked cert not unauthorized, got code %d", w.code)
+	}
+	if err := s.clientCerts.revoke(fp); err == nil {
+		t.Fatalf("no error revoking unknown cert")
+	}
+}
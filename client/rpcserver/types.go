@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/config"
@@ -206,6 +208,11 @@ type startBotForm struct {
 	mkt         *mm.MarketWithHost
 }
 
+type stopBotForm struct {
+	mkt      *mm.MarketWithHost
+	windDown bool
+}
+
 type updateRunningBotForm struct {
 	cfgFilePath string
 	mkt         *mm.MarketWithHost
@@ -217,6 +224,23 @@ type updateRunningBotInventoryForm struct {
 	balances *mm.BotInventoryDiffs
 }
 
+type mmBotCfgForm struct {
+	cfgFilePath string
+	mkt         *mm.MarketWithHost
+}
+
+type mmBenchmarkForm struct {
+	mkt       *mm.MarketWithHost
+	startTime int64
+}
+
+type convertCEXInventoryForm struct {
+	mkt          *mm.MarketWithHost
+	fromID, toID uint32
+	qty          uint64
+	maxSlippage  float64
+}
+
 type setVSPForm struct {
 	assetID uint32
 	addr    string
@@ -684,6 +708,39 @@ func parseCancelArgs(params *RawParams) (*cancelForm, error) {
 	return &cancelForm{orderID: oidB}, nil
 }
 
+func parseAnnotateOrderArgs(params *RawParams) (*core.AnnotationForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{2, 4}); err != nil {
+		return nil, err
+	}
+	parseID := func(id, name string) (dex.Bytes, error) {
+		if len(id) != orderIdLen {
+			return nil, fmt.Errorf("%w: %s has incorrect length", errArgs, name)
+		}
+		b, err := hex.DecodeString(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s hex", errArgs, name)
+		}
+		return b, nil
+	}
+	oid, err := parseID(params.Args[0], "orderID")
+	if err != nil {
+		return nil, err
+	}
+	form := &core.AnnotationForm{
+		OrderID: oid,
+		Note:    params.Args[1],
+	}
+	if len(params.Args) > 2 && params.Args[2] != "" {
+		form.Tags = strings.Split(params.Args[2], ",")
+	}
+	if len(params.Args) > 3 {
+		if form.MatchID, err = parseID(params.Args[3], "matchID"); err != nil {
+			return nil, err
+		}
+	}
+	return form, nil
+}
+
 func parseSendOrWithdrawArgs(params *RawParams) (*sendOrWithdrawForm, error) {
 	if err := checkNArgs(params, []int{1}, []int{3}); err != nil {
 		return nil, err
@@ -824,6 +881,35 @@ func parseDeleteArchivedRecordsArgs(params *RawParams) (form *deleteRecordsForm,
 	return form, nil
 }
 
+// parseFeeReportArgs parses the optional from and to unix millisecond times.
+// Zero or empty times are unbounded.
+func parseFeeReportArgs(params *RawParams) (from, to time.Time, err error) {
+	if err = checkNArgs(params, []int{0}, []int{0, 2}); err != nil {
+		return
+	}
+	parseTime := func(s, name string) (time.Time, error) {
+		if s == "" || s == "0" {
+			return time.Time{}, nil
+		}
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: invalid %s time %q: %v", errArgs, name, s, err)
+		}
+		return time.UnixMilli(ms), nil
+	}
+	if len(params.Args) > 0 {
+		if from, err = parseTime(params.Args[0], "from"); err != nil {
+			return
+		}
+	}
+	if len(params.Args) > 1 {
+		if to, err = parseTime(params.Args[1], "to"); err != nil {
+			return
+		}
+	}
+	return
+}
+
 func parseWalletPeersArgs(params *RawParams) (uint32, error) {
 	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
 		return 0, err
@@ -835,6 +921,17 @@ func parseWalletPeersArgs(params *RawParams) (uint32, error) {
 	return uint32(assetID), nil
 }
 
+func parseSyncProgressArgs(params *RawParams) (uint32, error) {
+	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
+		return 0, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(assetID), nil
+}
+
 func parseAddRemoveWalletPeerArgs(params *RawParams) (form *addRemovePeerForm, err error) {
 	if err = checkNArgs(params, []int{0}, []int{2}); err != nil {
 		return nil, err
@@ -860,6 +957,23 @@ func parseNotificationsArgs(params *RawParams) (int, error) {
 	return int(num), nil
 }
 
+func parseTranslationReportArgs(params *RawParams) (string, error) {
+	if err := checkNArgs(params, []int{0}, []int{0, 1}); err != nil {
+		return "", err
+	}
+	if len(params.Args) == 0 {
+		return "", nil
+	}
+	return params.Args[0], nil
+}
+
+func parseRestoreBackupArgs(params *RawParams) (seed encode.PassBytes, path string, err error) {
+	if err := checkNArgs(params, []int{1}, []int{1}); err != nil {
+		return nil, "", err
+	}
+	return params.PWArgs[0], params.Args[0], nil
+}
+
 func parseMktWithHost(host, baseID, quoteID string) (*mm.MarketWithHost, error) {
 	mkt := new(mm.MarketWithHost)
 	mkt.Host = host
@@ -939,11 +1053,22 @@ func parseStartBotArgs(params *RawParams) (*startBotForm, error) {
 	return form, nil
 }
 
-func parseStopBotArgs(params *RawParams) (*mm.MarketWithHost, error) {
-	if err := checkNArgs(params, []int{0}, []int{3}); err != nil {
+func parseStopBotArgs(params *RawParams) (*stopBotForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{3, 4}); err != nil {
 		return nil, err
 	}
-	return parseMktWithHost(params.Args[0], params.Args[1], params.Args[2])
+	mkt, err := parseMktWithHost(params.Args[0], params.Args[1], params.Args[2])
+	if err != nil {
+		return nil, err
+	}
+	form := &stopBotForm{mkt: mkt}
+	if len(params.Args) > 3 {
+		form.windDown, err = checkBoolArg(params.Args[3], "winddown")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return form, nil
 }
 
 func parseUpdateRunningBotArgs(params *RawParams) (*updateRunningBotForm, error) {
@@ -1017,6 +1142,80 @@ func parseUpdateRunningBotInventoryArgs(params *RawParams) (*updateRunningBotInv
 	return form, nil
 }
 
+// parseMMBotCfgArgs parses the args for routes that read a bot config from a
+// market maker config file.
+func parseMMBotCfgArgs(params *RawParams) (*mmBotCfgForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{4}); err != nil {
+		return nil, err
+	}
+	mkt, err := parseMktWithHost(params.Args[1], params.Args[2], params.Args[3])
+	if err != nil {
+		return nil, err
+	}
+	return &mmBotCfgForm{
+		cfgFilePath: params.Args[0],
+		mkt:         mkt,
+	}, nil
+}
+
+func parseMMQuoteQualityArgs(params *RawParams) (*mm.MarketWithHost, error) {
+	if err := checkNArgs(params, []int{0}, []int{3}); err != nil {
+		return nil, err
+	}
+	return parseMktWithHost(params.Args[0], params.Args[1], params.Args[2])
+}
+
+func parseMMBenchmarkArgs(params *RawParams) (*mmBenchmarkForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{4}); err != nil {
+		return nil, err
+	}
+	mkt, err := parseMktWithHost(params.Args[0], params.Args[1], params.Args[2])
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := checkIntArg(params.Args[3], "startTime", 64)
+	if err != nil {
+		return nil, err
+	}
+	return &mmBenchmarkForm{
+		mkt:       mkt,
+		startTime: startTime,
+	}, nil
+}
+
+func parseConvertCEXInventoryArgs(params *RawParams) (*convertCEXInventoryForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{7}); err != nil {
+		return nil, err
+	}
+	mkt, err := parseMktWithHost(params.Args[0], params.Args[1], params.Args[2])
+	if err != nil {
+		return nil, err
+	}
+	fromID, err := checkUIntArg(params.Args[3], "fromID", 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fromID: %v", err)
+	}
+	toID, err := checkUIntArg(params.Args[4], "toID", 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid toID: %v", err)
+	}
+	qty, err := checkUIntArg(params.Args[5], "qty", 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid qty: %v", err)
+	}
+	maxSlippage, err := strconv.ParseFloat(params.Args[6], 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot parse maxSlippage: %v", errArgs, err)
+	}
+	return &convertCEXInventoryForm{
+		mkt:         mkt,
+		fromID:      uint32(fromID),
+		toID:        uint32(toID),
+		qty:         qty,
+		maxSlippage: maxSlippage,
+	}, nil
+}
+
 func parseSetVSPArgs(params *RawParams) (*setVSPForm, error) {
 	if err := checkNArgs(params, []int{0}, []int{2}); err != nil {
 		return nil, err
@@ -1168,3 +1367,219 @@ func parseWalletTxArgs(params *RawParams) (*walletTxForm, error) {
 		txID:    params.Args[1],
 	}, nil
 }
+
+// createAPIKeyForm is information necessary to create an API key.
+type createAPIKeyForm struct {
+	label    string
+	scopes   []APIScope
+	lifetime time.Duration
+}
+
+func parseCreateAPIKeyArgs(params *RawParams) (*createAPIKeyForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{2, 3}); err != nil {
+		return nil, err
+	}
+	scopes, err := parseAPIScopes(params.Args[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errArgs, err)
+	}
+	form := &createAPIKeyForm{
+		label:  params.Args[0],
+		scopes: scopes,
+	}
+	if len(params.Args) > 2 {
+		form.lifetime, err = time.ParseDuration(params.Args[2])
+		if err != nil || form.lifetime <= 0 {
+			return nil, fmt.Errorf("%w: invalid lifetime %q", errArgs, params.Args[2])
+		}
+	}
+	return form, nil
+}
+
+// recoverFromSeedForm is information necessary to recover from seed.
+type recoverFromSeedForm struct {
+	appPass encode.PassBytes
+	seed    encode.PassBytes
+	assets  []uint32
+	hosts   []string
+}
+
+func parseRecoverFromSeedArgs(params *RawParams) (*recoverFromSeedForm, error) {
+	if err := checkNArgs(params, []int{2}, []int{0, 2}); err != nil {
+		return nil, err
+	}
+	if len(params.PWArgs[0]) == 0 {
+		return nil, fmt.Errorf("app password cannot be empty")
+	}
+	if len(params.PWArgs[1]) == 0 {
+		return nil, fmt.Errorf("seed cannot be empty")
+	}
+	form := &recoverFromSeedForm{
+		appPass: params.PWArgs[0],
+		seed:    params.PWArgs[1],
+	}
+	if len(params.Args) > 0 && params.Args[0] != "" {
+		for _, s := range strings.Split(params.Args[0], ",") {
+			assetID, err := checkUIntArg(strings.TrimSpace(s), "assetID", 32)
+			if err != nil {
+				return nil, err
+			}
+			form.assets = append(form.assets, uint32(assetID))
+		}
+	}
+	if len(params.Args) > 1 && params.Args[1] != "" {
+		for _, host := range strings.Split(params.Args[1], ",") {
+			form.hosts = append(form.hosts, strings.TrimSpace(host))
+		}
+	}
+	return form, nil
+}
+
+type setConfTargetForm struct {
+	assetID uint32
+	target  *db.ConfTarget
+}
+
+func parseSetConfTargetArgs(params *RawParams) (*setConfTargetForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{1, 2}); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	form := &setConfTargetForm{assetID: uint32(assetID)}
+	if len(params.Args) == 1 {
+		return form, nil
+	}
+	switch target := strings.ToLower(params.Args[1]); target {
+	case "default", "":
+	case db.ConfSpeedFast, db.ConfSpeedNormal, db.ConfSpeedEconomy:
+		form.target = &db.ConfTarget{Speed: target}
+	default:
+		blocks, err := checkUIntArg(target, "target", 64)
+		if err != nil {
+			return nil, err
+		}
+		form.target = &db.ConfTarget{Blocks: blocks}
+	}
+	return form, nil
+}
+
+func parseTokenAllowancesArgs(params *RawParams) (uint32, error) {
+	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
+		return 0, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(assetID), nil
+}
+
+type setTokenAllowanceForm struct {
+	appPass   encode.PassBytes
+	assetID   uint32
+	version   uint32
+	allowance uint64
+}
+
+func parseSetTokenAllowanceArgs(params *RawParams) (*setTokenAllowanceForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{3}); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	version, err := checkUIntArg(params.Args[1], "version", 32)
+	if err != nil {
+		return nil, err
+	}
+	allowance, err := checkUIntArg(params.Args[2], "allowance", 64)
+	if err != nil {
+		return nil, err
+	}
+	return &setTokenAllowanceForm{
+		appPass:   params.PWArgs[0],
+		assetID:   uint32(assetID),
+		version:   uint32(version),
+		allowance: allowance,
+	}, nil
+}
+
+func parseRecoverSwapArgs(params *RawParams) (encode.PassBytes, *core.SwapRecoveryForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{3, 4}); err != nil {
+		return nil, nil, err
+	}
+	oid, err := hex.DecodeString(params.Args[0])
+	if err != nil || len(oid) != order.OrderIDSize {
+		return nil, nil, fmt.Errorf("%w: invalid order ID", errArgs)
+	}
+	mid, err := hex.DecodeString(params.Args[1])
+	if err != nil || len(mid) != order.MatchIDSize {
+		return nil, nil, fmt.Errorf("%w: invalid match ID", errArgs)
+	}
+	form := &core.SwapRecoveryForm{
+		OrderID: oid,
+		MatchID: mid,
+		Action:  core.SwapRecoveryAction(strings.ToLower(params.Args[2])),
+	}
+	switch form.Action {
+	case core.SwapRecoveryRefund, core.SwapRecoveryRedeem, core.SwapRecoveryAbandon:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown action %q", errArgs, params.Args[2])
+	}
+	if len(params.Args) > 3 && params.Args[3] != "" {
+		if form.Secret, err = hex.DecodeString(params.Args[3]); err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid secret hex", errArgs)
+		}
+	}
+	return params.PWArgs[0], form, nil
+}
+
+// addClientCertForm is information necessary to allow a client certificate.
+type addClientCertForm struct {
+	fingerprint string
+	label       string
+	scopes      []APIScope
+}
+
+func parseAddClientCertArgs(params *RawParams) (*addClientCertForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{3}); err != nil {
+		return nil, err
+	}
+	fp, err := parseFingerprint(params.Args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errArgs, err)
+	}
+	scopes, err := parseAPIScopes(params.Args[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errArgs, err)
+	}
+	return &addClientCertForm{
+		fingerprint: fp,
+		label:       params.Args[1],
+		scopes:      scopes,
+	}, nil
+}
+
+func parseTradingLimitsArgs(params *RawParams) (string, error) {
+	if err := checkNArgs(params, []int{0}, []int{0, 1}); err != nil {
+		return "", err
+	}
+	if len(params.Args) == 0 {
+		return "", nil
+	}
+	return params.Args[0], nil
+}
+
+func parseApplyBondPlanArgs(params *RawParams) (string, error) {
+	if err := checkNArgs(params, []int{0}, []int{0, 1}); err != nil {
+		return "", err
+	}
+	if len(params.Args) == 0 {
+		return "", nil
+	}
+	return params.Args[0], nil
+}
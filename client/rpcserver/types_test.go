@@ -7,9 +7,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/order"
 )
 
 func TestCheckNArgs(t *testing.T) {
@@ -790,6 +792,47 @@ func TestParseSetVSPArgs(t *testing.T) {
 	}
 }
 
+func TestParseConvertCEXInventoryArgs(t *testing.T) {
+	paramsWithArgs := func(args ...string) *RawParams {
+		return &RawParams{Args: args}
+	}
+	tests := []struct {
+		name    string
+		params  *RawParams
+		wantErr error
+	}{{
+		name:   "ok",
+		params: paramsWithArgs("dex.org", "42", "0", "42", "60002", "100000000", "0.01"),
+	}, {
+		name:    "bad qty",
+		params:  paramsWithArgs("dex.org", "42", "0", "42", "60002", "1.5", "0.01"),
+		wantErr: errArgs,
+	}, {
+		name:    "bad slippage",
+		params:  paramsWithArgs("dex.org", "42", "0", "42", "60002", "100000000", "abc"),
+		wantErr: errArgs,
+	}, {
+		name:    "too few args",
+		params:  paramsWithArgs("dex.org", "42", "0", "42", "60002", "100000000"),
+		wantErr: errArgs,
+	}}
+	for _, test := range tests {
+		form, err := parseConvertCEXInventoryArgs(test.params)
+		if test.wantErr != nil {
+			if err != nil {
+				continue
+			}
+			t.Fatalf("%q: expected error", test.name)
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if form.fromID != 42 || form.toID != 60002 || form.qty != 1e8 || form.maxSlippage != 0.01 {
+			t.Fatalf("%q: wrong form %+v", test.name, form)
+		}
+	}
+}
+
 func TestPurchaseTicketsArgs(t *testing.T) {
 	pw := encode.PassBytes("password123")
 	pwArgs := []encode.PassBytes{pw}
@@ -902,3 +945,56 @@ func TestParseSetVotingPreferencesArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRecoverSwapArgs(t *testing.T) {
+	oid := strings.Repeat("ab", order.OrderIDSize)
+	mid := strings.Repeat("cd", order.MatchIDSize)
+	pw := encode.PassBytes("password123")
+	paramsWithArgs := func(args ...string) *RawParams {
+		return &RawParams{PWArgs: []encode.PassBytes{pw}, Args: args}
+	}
+	tests := []struct {
+		name       string
+		params     *RawParams
+		wantSecret bool
+		wantErr    error
+	}{{
+		name:   "ok",
+		params: paramsWithArgs(oid, mid, "refund"),
+	}, {
+		name:       "ok with secret",
+		params:     paramsWithArgs(oid, mid, "Redeem", "0102"),
+		wantSecret: true,
+	}, {
+		name:    "unknown action",
+		params:  paramsWithArgs(oid, mid, "cancel"),
+		wantErr: errArgs,
+	}, {
+		name:    "bad match ID",
+		params:  paramsWithArgs(oid, "cdcd", "abandon"),
+		wantErr: errArgs,
+	}, {
+		name:    "bad secret",
+		params:  paramsWithArgs(oid, mid, "redeem", "xyz"),
+		wantErr: errArgs,
+	}, {
+		name:    "no password",
+		params:  &RawParams{Args: []string{oid, mid, "refund"}},
+		wantErr: errArgs,
+	}}
+	for _, test := range tests {
+		_, form, err := parseRecoverSwapArgs(test.params)
+		if test.wantErr != nil {
+			if errors.Is(err, test.wantErr) {
+				continue
+			}
+			t.Fatalf("%q: expected error %v, got %v", test.name, test.wantErr, err)
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if (len(form.Secret) > 0) != test.wantSecret {
+			t.Fatalf("%q: wrong secret %x", test.name, form.Secret)
+		}
+	}
+}
@@ -273,6 +273,63 @@ func (s *WebServer) apiUnapproveToken(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// apiTokenAllowances is the handler for the '/tokenallowances' API request.
+func (s *WebServer) apiTokenAllowances(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		AssetID uint32 `json:"assetID"`
+	}
+	if !readPost(w, r, &form) {
+		return
+	}
+	allowances, err := s.core.TokenAllowances(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	resp := struct {
+		OK         bool                    `json:"ok"`
+		Allowances []*asset.TokenAllowance `json:"allowances"`
+	}{
+		OK:         true,
+		Allowances: allowances,
+	}
+	writeJSON(w, resp)
+}
+
+// apiSetTokenAllowance is the handler for the '/settokenallowance' API
+// request. An allowance of zero revokes the approval.
+func (s *WebServer) apiSetTokenAllowance(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		AssetID   uint32           `json:"assetID"`
+		Version   uint32           `json:"version"`
+		Allowance uint64           `json:"allowance"`
+		Password  encode.PassBytes `json:"pass"`
+	}
+	if !readPost(w, r, &form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Password, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+
+	txID, err := s.core.SetTokenAllowance(pass, form.AssetID, form.Version, form.Allowance)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	resp := struct {
+		OK   bool   `json:"ok"`
+		TxID string `json:"txID"`
+	}{
+		OK:   true,
+		TxID: txID,
+	}
+	writeJSON(w, resp)
+}
+
 // apiGetDEXInfo is the handler for the '/getdexinfo' API request.
 func (s *WebServer) apiGetDEXInfo(w http.ResponseWriter, r *http.Request) {
 	form := new(registrationForm)
@@ -395,6 +452,48 @@ func (s *WebServer) apiPostBond(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, simpleAck())
 }
 
+// apiBondPlan responds with a proposed funding plan for upcoming bond renewals.
+func (s *WebServer) apiBondPlan(w http.ResponseWriter, r *http.Request) {
+	plan, err := s.core.BondFundingPlan()
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("bond funding plan error: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool                  `json:"ok"`
+		Plan *core.BondFundingPlan `json:"plan"`
+	}{
+		OK:   true,
+		Plan: plan,
+	})
+}
+
+// apiTradingLimits responds with the trading limit usage for every DEX
+// account.
+func (s *WebServer) apiTradingLimits(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, &struct {
+		OK     bool                       `json:"ok"`
+		Limits []*core.TradingLimitStatus `json:"limits"`
+	}{
+		OK:     true,
+		Limits: s.core.TradingLimitStatuses(),
+	})
+}
+
+// apiApplyBondPlan is the handler for the '/applybondplan' API request. The
+// posted plan is the one approved by the user.
+func (s *WebServer) apiApplyBondPlan(w http.ResponseWriter, r *http.Request) {
+	plan := new(core.BondFundingPlan)
+	if !readPost(w, r, plan) {
+		return
+	}
+	if err := s.core.ApplyBondFundingPlan(plan); err != nil {
+		s.writeAPIError(w, fmt.Errorf("apply bond funding plan error: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiUpdateBondOptions is the handler for the '/updatebondoptions' API request.
 func (s *WebServer) apiUpdateBondOptions(w http.ResponseWriter, r *http.Request) {
 	form := new(core.BondOptionsForm)
@@ -783,6 +882,24 @@ func (s *WebServer) apiUpdateCert(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, simpleAck())
 }
 
+func (s *WebServer) apiUpdateDEXAltHosts(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		Host     string   `json:"host"`
+		AltHosts []string `json:"altHosts"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+
+	err := s.core.UpdateDEXAltHosts(form.Host, form.AltHosts)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error updating alternate hosts: %w", err))
+		return
+	}
+
+	writeJSON(w, simpleAck())
+}
+
 func (s *WebServer) apiUpdateDEXHost(w http.ResponseWriter, r *http.Request) {
 	form := &struct {
 		Pass    encode.PassBytes `json:"pw"`
@@ -1038,6 +1155,107 @@ func (s *WebServer) apiNotes(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// apiSearchNotes handles the 'searchnotes' API request.
+func (s *WebServer) apiSearchNotes(w http.ResponseWriter, r *http.Request) {
+	form := new(db.NoteFilter)
+	if !readPost(w, r, form) {
+		return
+	}
+	notes, err := s.core.SearchNotifications(form)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool               `json:"ok"`
+		Notes []*db.Notification `json:"notes"`
+	}{
+		OK:    true,
+		Notes: notes,
+	})
+}
+
+// apiTopicSettings handles the 'topicsettings' API request.
+func (s *WebServer) apiTopicSettings(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK       bool                           `json:"ok"`
+		Settings map[db.Topic]*db.TopicSettings `json:"settings"`
+	}{
+		OK:       true,
+		Settings: s.core.TopicSettings(),
+	})
+}
+
+// apiUpdateTopicSettings handles the 'updatetopicsettings' API request.
+func (s *WebServer) apiUpdateTopicSettings(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		Topic    db.Topic          `json:"topic"`
+		Settings *db.TopicSettings `json:"settings"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.UpdateTopicSettings(form.Topic, form.Settings); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error updating notification settings: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiNoteTemplates handles the 'notetemplates' API request.
+func (s *WebServer) apiNoteTemplates(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK        bool                          `json:"ok"`
+		Templates map[db.Topic]*db.NoteTemplate `json:"templates"`
+	}{
+		OK:        true,
+		Templates: s.core.NoteTemplates(),
+	})
+}
+
+// apiUpdateNoteTemplate handles the 'updatenotetemplate' API request.
+func (s *WebServer) apiUpdateNoteTemplate(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		Topic    db.Topic         `json:"topic"`
+		Template *db.NoteTemplate `json:"template"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.UpdateNoteTemplate(form.Topic, form.Template); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error updating notification template: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiConfTargets handles the 'conftargets' API request.
+func (s *WebServer) apiConfTargets(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK      bool                      `json:"ok"`
+		Targets map[uint32]*db.ConfTarget `json:"targets"`
+	}{
+		OK:      true,
+		Targets: s.core.ConfTargets(),
+	})
+}
+
+// apiSetConfTarget handles the 'setconftarget' API request.
+func (s *WebServer) apiSetConfTarget(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32         `json:"assetID"`
+		Target  *db.ConfTarget `json:"target"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.SetConfTarget(form.AssetID, form.Target); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error setting confirmation target: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiLogout handles the 'logout' API request.
 func (s *WebServer) apiLogout(w http.ResponseWriter, r *http.Request) {
 	err := s.core.Logout()
@@ -1226,6 +1444,56 @@ func (s *WebServer) apiAccelerateOrder(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// apiTroubledMatches responds with the matches that need manual action and the
+// actions available for them.
+func (s *WebServer) apiTroubledMatches(w http.ResponseWriter, r *http.Request) {
+	matches, err := s.core.TroubledMatches()
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("troubled matches error: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK      bool                  `json:"ok"`
+		Matches []*core.TroubledMatch `json:"matches"`
+	}{
+		OK:      true,
+		Matches: matches,
+	})
+}
+
+// apiRecoverSwap executes an action to resolve a troubled match.
+func (s *WebServer) apiRecoverSwap(w http.ResponseWriter, r *http.Request) {
+	form := struct {
+		Pass    encode.PassBytes        `json:"pw"`
+		OrderID dex.Bytes               `json:"orderID"`
+		MatchID dex.Bytes               `json:"matchID"`
+		Action  core.SwapRecoveryAction `json:"action"`
+		Secret  dex.Bytes               `json:"secret"`
+	}{}
+	defer form.Pass.Clear()
+	if !readPost(w, r, &form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+
+	err = s.core.RecoverSwap(pass, &core.SwapRecoveryForm{
+		OrderID: form.OrderID,
+		MatchID: form.MatchID,
+		Action:  form.Action,
+		Secret:  form.Secret,
+	})
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("swap recovery error: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiPreAccelerate responds with information about accelerating the mining of
 // swaps in an order
 func (s *WebServer) apiPreAccelerate(w http.ResponseWriter, r *http.Request) {
@@ -1297,6 +1565,20 @@ func (s *WebServer) apiOrder(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// apiAnnotateOrder is the handler for the '/annotateorder' API request. It sets
+// the user's note and tags for an order, or for one of its matches.
+func (s *WebServer) apiAnnotateOrder(w http.ResponseWriter, r *http.Request) {
+	form := new(core.AnnotationForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.AnnotateOrder(form); err != nil {
+		s.writeAPIError(w, fmt.Errorf("annotate order error: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiChangeAppPass updates the application password.
 func (s *WebServer) apiChangeAppPass(w http.ResponseWriter, r *http.Request) {
 	form := &struct {
@@ -1500,6 +1782,27 @@ func (s *WebServer) apiPreOrder(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// apiIndicators handles the 'indicators' API request, which returns a market's
+// candles along with the requested indicator series.
+func (s *WebServer) apiIndicators(w http.ResponseWriter, r *http.Request) {
+	form := new(core.IndicatorsForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	series, err := s.core.Indicators(form)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error computing indicators: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK     bool                  `json:"ok"`
+		Series *core.IndicatorSeries `json:"series"`
+	}{
+		OK:     true,
+		Series: series,
+	})
+}
+
 // apiActuallyLogin logs the user in. login form private data is expected to be
 // cleared by the caller.
 func (s *WebServer) actuallyLogin(w http.ResponseWriter, r *http.Request, login *loginForm) error {
@@ -1702,6 +2005,35 @@ func (s *WebServer) apiRunLogs(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *WebServer) apiBenchmarkReport(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StartTime int64              `json:"startTime"`
+		Market    *mm.MarketWithHost `json:"market"`
+	}
+	if !readPost(w, r, &req) {
+		return
+	}
+
+	if req.Market == nil {
+		s.writeAPIError(w, errors.New("market missing"))
+		return
+	}
+
+	report, err := s.mm.BenchmarkReport(req.StartTime, req.Market)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error getting benchmark report: %w", err))
+		return
+	}
+
+	writeJSON(w, &struct {
+		OK     bool                `json:"ok"`
+		Report *mm.BenchmarkReport `json:"report"`
+	}{
+		OK:     true,
+		Report: report,
+	})
+}
+
 func (s *WebServer) apiCEXBook(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Host    string `json:"host"`
@@ -1918,7 +2250,8 @@ func (s *WebServer) apiStartMarketMakingBot(w http.ResponseWriter, r *http.Reque
 
 func (s *WebServer) apiStopMarketMakingBot(w http.ResponseWriter, r *http.Request) {
 	var form struct {
-		Market *mm.MarketWithHost `json:"market"`
+		Market   *mm.MarketWithHost `json:"market"`
+		WindDown bool               `json:"windDown"`
 	}
 	if !readPost(w, r, &form) {
 		s.writeAPIError(w, fmt.Errorf("failed to read form"))
@@ -1928,6 +2261,14 @@ func (s *WebServer) apiStopMarketMakingBot(w http.ResponseWriter, r *http.Reques
 		s.writeAPIError(w, errors.New("market missing"))
 		return
 	}
+	if form.WindDown {
+		if err := s.mm.WindDownBot(form.Market); err != nil {
+			s.writeAPIError(w, fmt.Errorf("error winding down mm bot %q: %v", form.Market, err))
+			return
+		}
+		writeJSON(w, simpleAck())
+		return
+	}
 	if err := s.mm.StopBot(form.Market); err != nil {
 		s.writeAPIError(w, fmt.Errorf("error stopping mm bot %q: %v", form.Market, err))
 		return
@@ -1965,6 +2306,50 @@ func (s *WebServer) apiUpdateBotConfig(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, simpleAck())
 }
 
+func (s *WebServer) apiDryRunBotConfig(w http.ResponseWriter, r *http.Request) {
+	var cfg *mm.BotConfig
+	if !readPost(w, r, &cfg) {
+		s.writeAPIError(w, fmt.Errorf("failed to read config"))
+		return
+	}
+
+	report, err := s.mm.DryRunBot(cfg)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+
+	writeJSON(w, &struct {
+		OK     bool             `json:"ok"`
+		Report *mm.DryRunReport `json:"report"`
+	}{
+		OK:     true,
+		Report: report,
+	})
+}
+
+func (s *WebServer) apiRecommendedAllocation(w http.ResponseWriter, r *http.Request) {
+	var cfg *mm.BotConfig
+	if !readPost(w, r, &cfg) {
+		s.writeAPIError(w, fmt.Errorf("failed to read config"))
+		return
+	}
+
+	advice, err := s.mm.RecommendedAllocation(cfg)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+
+	writeJSON(w, &struct {
+		OK     bool                 `json:"ok"`
+		Advice *mm.AllocationAdvice `json:"advice"`
+	}{
+		OK:     true,
+		Advice: advice,
+	})
+}
+
 func (s *WebServer) apiRemoveBotConfig(w http.ResponseWriter, r *http.Request) {
 	var form struct {
 		Host    string `json:"host"`
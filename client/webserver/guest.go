@@ -0,0 +1,306 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	crand "crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/msgjson"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// guestTokenLifetime is how long a guest token is valid after guest login.
+	guestTokenLifetime = 24 * time.Hour
+	// maxGuestTokens is the most guest tokens kept. When there are more, the
+	// tokens that expire first are dropped.
+	maxGuestTokens = 1000
+	// Failed guest logins are limited per IP address to guestLoginFailBurst
+	// attempts, replenished at guestLoginFailRate per second.
+	guestLoginFailRate  = 1.0 / 10
+	guestLoginFailBurst = 5
+	// guestLoginFailTTL is how long the failed guest logins from an address
+	// are remembered.
+	guestLoginFailTTL = 10 * time.Minute
+)
+
+// Guest mode gives read-only access to public market data, i.e. the markets,
+// order books, and candles of the connected DEX servers, to anyone with the
+// guest password. Guests have no access to balances, wallets, orders, or
+// notifications, and cannot take any actions.
+
+// guestExchange is the public information about a DEX server and its markets.
+type guestExchange struct {
+	Host             string                  `json:"host"`
+	Markets          map[string]*guestMarket `json:"markets"`
+	Assets           map[uint32]*dex.Asset   `json:"assets"`
+	CandleDurs       []string                `json:"candleDurs"`
+	ConnectionStatus comms.ConnectionStatus  `json:"connectionStatus"`
+}
+
+// guestMarket is core.Market without the user's orders.
+type guestMarket struct {
+	Name        string        `json:"name"`
+	BaseID      uint32        `json:"baseid"`
+	BaseSymbol  string        `json:"basesymbol"`
+	QuoteID     uint32        `json:"quoteid"`
+	QuoteSymbol string        `json:"quotesymbol"`
+	LotSize     uint64        `json:"lotsize"`
+	RateStep    uint64        `json:"ratestep"`
+	EpochLen    uint64        `json:"epochlen"`
+	SpotPrice   *msgjson.Spot `json:"spot"`
+	AtomToConv  float64       `json:"atomToConv"`
+	MinimumRate uint64        `json:"minimumRate"`
+}
+
+// newGuestExchange strips the account and order information from the
+// core.Exchange.
+func newGuestExchange(xc *core.Exchange) *guestExchange {
+	gx := &guestExchange{
+		Host:             xc.Host,
+		Markets:          make(map[string]*guestMarket, len(xc.Markets)),
+		Assets:           xc.Assets,
+		CandleDurs:       xc.CandleDurs,
+		ConnectionStatus: xc.ConnectionStatus,
+	}
+	for name, mkt := range xc.Markets {
+		gx.Markets[name] = &guestMarket{
+			Name:        mkt.Name,
+			BaseID:      mkt.BaseID,
+			BaseSymbol:  mkt.BaseSymbol,
+			QuoteID:     mkt.QuoteID,
+			QuoteSymbol: mkt.QuoteSymbol,
+			LotSize:     mkt.LotSize,
+			RateStep:    mkt.RateStep,
+			EpochLen:    mkt.EpochLen,
+			SpotPrice:   mkt.SpotPrice,
+			AtomToConv:  mkt.AtomToConv,
+			MinimumRate: mkt.MinimumRate,
+		}
+	}
+	return gx
+}
+
+// guestModeEnabled is true if a guest password is configured.
+func (s *WebServer) guestModeEnabled() bool {
+	s.authMtx.RLock()
+	defer s.authMtx.RUnlock()
+	return len(s.guestPass) > 0
+}
+
+// checkGuestPass checks the guest password in constant time.
+func (s *WebServer) checkGuestPass(pass []byte) bool {
+	s.authMtx.RLock()
+	defer s.authMtx.RUnlock()
+	return len(s.guestPass) > 0 && subtle.ConstantTimeCompare(pass, s.guestPass) == 1
+}
+
+// SetGuestPassword changes the guest password, or disables guest mode if the
+// password is empty. All guests are logged out and must log in again with the
+// new password.
+func (s *WebServer) SetGuestPassword(pass string) {
+	s.authMtx.Lock()
+	s.guestPass = []byte(pass)
+	s.guestTokens = make(map[string]time.Time)
+	s.authMtx.Unlock()
+	s.wsServer.DisconnectGuests()
+}
+
+// authorizeGuest creates, stores, and returns a new guest token, and the time
+// that it expires.
+func (s *WebServer) authorizeGuest() (string, time.Time) {
+	b := make([]byte, 32)
+	crand.Read(b)
+	token := hex.EncodeToString(b)
+	now := time.Now()
+	expiration := now.Add(guestTokenLifetime)
+	s.authMtx.Lock()
+	defer s.authMtx.Unlock()
+	for t, exp := range s.guestTokens {
+		if !now.Before(exp) {
+			delete(s.guestTokens, t)
+		}
+	}
+	for len(s.guestTokens) >= maxGuestTokens {
+		var first string
+		var firstExp time.Time
+		for t, exp := range s.guestTokens {
+			if first == "" || exp.Before(firstExp) {
+				first, firstExp = t, exp
+			}
+		}
+		delete(s.guestTokens, first)
+	}
+	s.guestTokens[token] = expiration
+	return token, expiration
+}
+
+// isGuest checks if the incoming request has a valid guest token cookie.
+func (s *WebServer) isGuest(r *http.Request) bool {
+	_, isGuest := s.guestExpiration(r)
+	return isGuest
+}
+
+// guestExpiration returns the expiration of the request's guest token. The
+// bool is false if the request does not have a valid guest token cookie.
+func (s *WebServer) guestExpiration(r *http.Request) (time.Time, bool) {
+	cookie, err := r.Cookie(guestCK)
+	if err != nil || cookie.Value == "" {
+		return time.Time{}, false
+	}
+	s.authMtx.RLock()
+	defer s.authMtx.RUnlock()
+	exp, found := s.guestTokens[cookie.Value]
+	if !found || !time.Now().Before(exp) {
+		return time.Time{}, false
+	}
+	return exp, true
+}
+
+// failedGuestLogins limits the failed guest logins from an address.
+type failedGuestLogins struct {
+	limiter *rate.Limiter
+	last    time.Time
+}
+
+// guestLoginAllowed checks whether a guest login attempt from the address is
+// allowed, i.e. it has not made too many failed attempts recently.
+func (s *WebServer) guestLoginAllowed(ip string) bool {
+	s.guestFailsMtx.Lock()
+	defer s.guestFailsMtx.Unlock()
+	f := s.guestFails[ip]
+	return f == nil || f.limiter.Tokens() >= 1
+}
+
+// guestLoginFailed records a failed guest login attempt from the address.
+func (s *WebServer) guestLoginFailed(ip string) {
+	now := time.Now()
+	s.guestFailsMtx.Lock()
+	defer s.guestFailsMtx.Unlock()
+	f := s.guestFails[ip]
+	if f == nil {
+		for addr, f := range s.guestFails {
+			if now.Sub(f.last) > guestLoginFailTTL {
+				delete(s.guestFails, addr)
+			}
+		}
+		f = &failedGuestLogins{limiter: rate.NewLimiter(guestLoginFailRate, guestLoginFailBurst)}
+		s.guestFails[ip] = f
+	}
+	f.limiter.AllowN(now, 1)
+	f.last = now
+}
+
+// remoteIP is the IP address of the request's remote address.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// apiGuestLogin handles the 'guestlogin' API request, which sets the guest
+// token cookie if the guest password is correct.
+func (s *WebServer) apiGuestLogin(w http.ResponseWriter, r *http.Request) {
+	if !s.guestModeEnabled() {
+		s.writeAPIError(w, errors.New("guest mode is not enabled"))
+		return
+	}
+	form := new(struct {
+		Pass encode.PassBytes `json:"pass"`
+	})
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	ip := remoteIP(r)
+	if !s.guestLoginAllowed(ip) {
+		s.writeAPIError(w, errors.New("too many failed guest logins, try again later"))
+		return
+	}
+	if !s.checkGuestPass(form.Pass) {
+		s.guestLoginFailed(ip)
+		s.writeAPIError(w, errors.New("incorrect guest password"))
+		return
+	}
+	token, expiration := s.authorizeGuest()
+	http.SetCookie(w, &http.Cookie{
+		Name:     guestCK,
+		Path:     "/",
+		Value:    token,
+		Expires:  expiration,
+		SameSite: http.SameSiteStrictMode,
+	})
+	writeJSON(w, simpleAck())
+}
+
+// apiSetGuestPassword handles the 'setguestpassword' API request, which
+// changes the guest password, or disables guest mode if the password is empty.
+func (s *WebServer) apiSetGuestPassword(w http.ResponseWriter, r *http.Request) {
+	form := new(struct {
+		Pass encode.PassBytes `json:"pass"`
+	})
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	s.SetGuestPassword(string(form.Pass))
+	writeJSON(w, simpleAck())
+}
+
+// apiGuestMarkets handles the 'guest/markets' API request, which returns the
+// public market information for the connected DEX servers.
+func (s *WebServer) apiGuestMarkets(w http.ResponseWriter, r *http.Request) {
+	xcs := s.core.Exchanges()
+	exchanges := make(map[string]*guestExchange, len(xcs))
+	for host, xc := range xcs {
+		if xc.Disabled {
+			continue
+		}
+		exchanges[host] = newGuestExchange(xc)
+	}
+	writeJSON(w, &struct {
+		OK        bool                      `json:"ok"`
+		Exchanges map[string]*guestExchange `json:"exchanges"`
+	}{
+		OK:        true,
+		Exchanges: exchanges,
+	})
+}
+
+// apiGuestBook handles the 'guest/book' API request, which returns a market's
+// order book.
+func (s *WebServer) apiGuestBook(w http.ResponseWriter, r *http.Request) {
+	form := new(struct {
+		Host  string `json:"host"`
+		Base  uint32 `json:"base"`
+		Quote uint32 `json:"quote"`
+	})
+	if !readPost(w, r, form) {
+		return
+	}
+	book, err := s.core.Book(form.Host, form.Base, form.Quote)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool            `json:"ok"`
+		Book *core.OrderBook `json:"book"`
+	}{
+		OK:   true,
+		Book: book,
+	})
+}
@@ -22,20 +22,22 @@ import (
 )
 
 const (
-	homeRoute        = "/"
-	registerRoute    = "/register"
-	initRoute        = "/init"
-	loginRoute       = "/login"
-	marketsRoute     = "/markets"
-	walletsRoute     = "/wallets"
-	walletLogRoute   = "/wallets/logfile"
-	settingsRoute    = "/settings"
-	ordersRoute      = "/orders"
-	exportOrderRoute = "/orders/export"
-	marketMakerRoute = "/mm"
-	mmSettingsRoute  = "/mmsettings"
-	mmArchivesRoute  = "/mmarchives"
-	mmLogsRoute      = "/mmlogs"
+	homeRoute          = "/"
+	registerRoute      = "/register"
+	initRoute          = "/init"
+	loginRoute         = "/login"
+	guestRoute         = "/guest"
+	marketsRoute       = "/markets"
+	walletsRoute       = "/wallets"
+	walletLogRoute     = "/wallets/logfile"
+	settingsRoute      = "/settings"
+	ordersRoute        = "/orders"
+	exportOrderRoute   = "/orders/export"
+	exportMatchesRoute = "/orders/exportmatches"
+	marketMakerRoute   = "/mm"
+	mmSettingsRoute    = "/mmsettings"
+	mmArchivesRoute    = "/mmarchives"
+	mmLogsRoute        = "/mmlogs"
 )
 
 // sendTemplate processes the template and sends the result.
@@ -85,6 +87,28 @@ func (s *WebServer) handleLogin(w http.ResponseWriter, r *http.Request) {
 	s.sendTemplate(w, "login", cArgs)
 }
 
+// guestTmplData is template data for the /guest page.
+type guestTmplData struct {
+	CommonArguments
+	// Guest is true if the request has a valid guest token or is authed, in
+	// which case the markets are shown instead of the guest login form.
+	Guest bool
+}
+
+// handleGuest is the handler for the '/guest' page request. The page is a
+// read-only view of the public market data of the connected DEX servers.
+func (s *WebServer) handleGuest(w http.ResponseWriter, r *http.Request) {
+	cArgs := s.commonArgs(r, "Markets | Decred DEX")
+	if !cArgs.UserInfo.Authed && !s.guestModeEnabled() {
+		http.Redirect(w, r, loginRoute, http.StatusSeeOther)
+		return
+	}
+	s.sendTemplate(w, "guest", &guestTmplData{
+		CommonArguments: *cArgs,
+		Guest:           cArgs.UserInfo.Authed || s.isGuest(r),
+	})
+}
+
 // registerTmplData is template data for the /register page.
 type registerTmplData struct {
 	CommonArguments
@@ -350,23 +374,18 @@ func (s *WebServer) handleOrders(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleExportOrders is the handler for the /orders/export page request.
-func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
+// parseOrderFilterForm parses the order filter from the request's form values.
+func parseOrderFilterForm(r *http.Request) (*core.OrderFilter, error) {
 	filter := new(core.OrderFilter)
-	err := r.ParseForm()
-	if err != nil {
-		log.Errorf("error parsing form for export order: %v", err)
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("error parsing form: %w", err)
 	}
 
 	nStr := r.Form.Get("n")
 	if nStr != "" {
 		n, err := strconv.ParseInt(nStr, 10, 32)
 		if err != nil {
-			log.Errorf("error parsing N: %v", err)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
+			return nil, fmt.Errorf("error parsing N: %w", err)
 		}
 		filter.N = int(n)
 	}
@@ -374,9 +393,7 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 	if fresherThanUnixMsStr != "" {
 		fresherThanUnixMs, err := strconv.ParseUint(fresherThanUnixMsStr, 10, 64)
 		if err != nil {
-			log.Errorf("error parsing fresherThanUnixMs: %v", err)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
+			return nil, fmt.Errorf("error parsing fresherThanUnixMs: %w", err)
 		}
 		filter.FresherThanUnixMs = fresherThanUnixMs
 	}
@@ -387,9 +404,7 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 	for k, assetStrID := range assets {
 		assetNumID, err := strconv.ParseUint(assetStrID, 10, 32)
 		if err != nil {
-			log.Errorf("error parsing asset id: %v", err)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
+			return nil, fmt.Errorf("error parsing asset id: %w", err)
 		}
 		filter.Assets[k] = uint32(assetNumID)
 	}
@@ -398,13 +413,24 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 	for k, statusStrID := range statuses {
 		statusNumID, err := strconv.ParseUint(statusStrID, 10, 16)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			log.Errorf("error parsing status id: %v", err)
-			return
+			return nil, fmt.Errorf("error parsing status id: %w", err)
 		}
 		filter.Statuses[k] = order.OrderStatus(statusNumID)
 	}
 	filter.CompletedOnly = r.Form.Get("completedOnly") == "true"
+	filter.Text = r.Form.Get("text")
+
+	return filter, nil
+}
+
+// handleExportOrders is the handler for the /orders/export page request.
+func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOrderFilterForm(r)
+	if err != nil {
+		log.Errorf("error parsing export order filter: %v", err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
 
 	ords, err := s.core.Orders(filter)
 	if err != nil {
@@ -437,6 +463,8 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 		"Filled (%)",
 		"Settled (%)",
 		"Time",
+		"Note",
+		"Tags",
 	})
 	if err != nil {
 		log.Errorf("error writing CSV: %v", err)
@@ -471,6 +499,8 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 			ordReader.FilledPercent(),     // Filled
 			ordReader.SettledPercent(),    // Settled
 			timestamp,                     // Time
+			ord.Note,                      // Note
+			strings.Join(ord.Tags, " "),   // Tags
 		})
 		if err != nil {
 			log.Errorf("error writing CSV: %v", err)
@@ -485,6 +515,42 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleExportMatches is the handler for the /orders/exportmatches page
+// request. It exports the matches of the filtered orders for accounting, with
+// the format specified by the "format" form value.
+func (s *WebServer) handleExportMatches(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOrderFilterForm(r)
+	if err != nil {
+		log.Errorf("error parsing export matches filter: %v", err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	format := core.AccountingFormat(r.Form.Get("format"))
+	switch format {
+	case "":
+		format = core.AccountingFormatGeneric
+	case core.AccountingFormatGeneric, core.AccountingFormatUniversal:
+	default:
+		log.Errorf("unknown match export format %q", format)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	recs, err := s.core.MatchRecords(filter)
+	if err != nil {
+		log.Errorf("error retrieving match records: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=matches-%s.csv", format))
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	if err := core.WriteMatchRecords(w, recs, format, strings.Contains(r.UserAgent(), "Windows")); err != nil {
+		log.Errorf("error writing CSV: %v", err)
+	}
+}
+
 type orderTmplData struct {
 	CommonArguments
 	Order *core.OrderReader
@@ -97,6 +97,7 @@ const (
 	matchStatusRedemptionConfirmedID = "MATCH_REDEMPTION_CONFIRMED"
 	matchStatusRevokedID             = "MATCH_STATUS_REVOKED"
 	matchStatusRefundedID            = "MATCH_STATUS_REFUNDED"
+	matchStatusAbandonedID           = "MATCH_STATUS_ABANDONED"
 	matchStatusRefundPendingID       = "MATCH_STATUS_REFUND_PENDING"
 	matchStatusRedeemPendingID       = "MATCH_STATUS_REDEEM_PENDING"
 	matchStatusCompleteID            = "MATCH_STATUS_COMPLETE"
@@ -321,6 +322,7 @@ var enUS = map[string]*intl.Translation{
 	matchStatusRevokedID:             {T: "Revoked - {{ status }}"},
 	matchStatusRefundPendingID:       {T: "Refund PENDING"},
 	matchStatusRefundedID:            {T: "Refunded"},
+	matchStatusAbandonedID:           {T: "Abandoned"},
 	matchStatusRedeemPendingID:       {T: "Redeem PENDING"},
 	matchStatusRedemptionConfirmedID: {T: "Redemption Confirmed"},
 	matchStatusCompleteID:            {T: "Complete"},
@@ -536,6 +536,9 @@ func (c *TCore) trySend(u *core.BookUpdate) {
 func (c *TCore) Network() dex.Network { return dex.Mainnet }
 
 func (c *TCore) Exchanges() map[string]*core.Exchange { return tExchanges }
+func (c *TCore) Book(host string, base, quote uint32) (*core.OrderBook, error) {
+	return &core.OrderBook{}, nil
+}
 
 func (c *TCore) Exchange(host string) (*core.Exchange, error) {
 	exchange, ok := tExchanges[host]
@@ -626,8 +629,16 @@ func (c *TCore) Notifications(n int) (notes, pokes []*db.Notification, _ error)
 	return []*db.Notification{}, []*db.Notification{}, nil
 }
 
+func (c *TCore) SearchNotifications(*db.NoteFilter) ([]*db.Notification, error) {
+	return []*db.Notification{}, nil
+}
+
 var orderAssets = []string{"dcr", "btc", "ltc", "doge", "mona", "vtc", "usdc.eth"}
 
+func (c *TCore) MatchRecords(*core.OrderFilter) ([]*core.MatchRecord, error) {
+	return nil, nil
+}
+
 func (c *TCore) Orders(filter *core.OrderFilter) ([]*core.Order, error) {
 	var spacing uint64 = 60 * 60 * 1000 / 2 // half an hour
 	t := uint64(time.Now().UnixMilli())
@@ -765,6 +776,9 @@ func (c *TCore) PreOrder(*core.TradeForm) (*core.OrderEstimate, error) {
 		},
 	}, nil
 }
+func (c *TCore) Indicators(form *core.IndicatorsForm) (*core.IndicatorSeries, error) {
+	return nil, nil
+}
 
 func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
 	return nil, nil, nil
@@ -919,6 +933,10 @@ func (c *TCore) Order(dex.Bytes) (*core.Order, error) {
 	return makeCoreOrder(), nil
 }
 
+func (c *TCore) AnnotateOrder(form *core.AnnotationForm) error {
+	return nil
+}
+
 func (c *TCore) SyncBook(dexAddr string, base, quote uint32) (*orderbook.OrderBook, core.BookFeed, error) {
 	mktID, _ := dex.MarketName(base, quote)
 	c.mtx.Lock()
@@ -1611,6 +1629,45 @@ func (c *TCore) AccelerationEstimate(oidB dex.Bytes, newFeeRate uint64) (uint64,
 func (c *TCore) PreAccelerateOrder(oidB dex.Bytes) (*core.PreAccelerate, error) {
 	return nil, nil
 }
+func (c *TCore) TroubledMatches() ([]*core.TroubledMatch, error) {
+	return []*core.TroubledMatch{{
+		Host:      firstDEX,
+		MarketID:  mkid(42, 0),
+		OrderID:   ordertest.RandomOrderID().Bytes(),
+		MatchID:   ordertest.RandomMatchID().Bytes(),
+		Status:    order.TakerSwapCast,
+		Side:      order.Maker,
+		Qty:       3e8,
+		Rate:      1e6,
+		FromAsset: 42,
+		ToAsset:   0,
+		Revoked:   true,
+		LockTime:  uint64(time.Now().Add(-time.Hour).UnixMilli()),
+		Problems:  []string{"match revoked with our swap unredeemed", "swap contract expired"},
+		Options: []*core.SwapRecoveryOption{{
+			Action:      core.SwapRecoveryRefund,
+			Description: "Refund our swap contract. The counterparty can no longer redeem it.",
+		}, {
+			Action:      core.SwapRecoveryRedeem,
+			Description: "Redeem the counterparty's swap contract with our secret.",
+		}, {
+			Action:      core.SwapRecoveryAbandon,
+			Description: "Stop all action on this match. Funds locked in our swap contract must be recovered outside of the app.",
+		}},
+	}}, nil
+}
+func (c *TCore) RecoverSwap(pw []byte, form *core.SwapRecoveryForm) error {
+	return nil
+}
+func (c *TCore) BondFundingPlan() (*core.BondFundingPlan, error) {
+	return nil, nil
+}
+func (c *TCore) ApplyBondFundingPlan(plan *core.BondFundingPlan) error {
+	return nil
+}
+func (c *TCore) TradingLimitStatuses() []*core.TradingLimitStatus {
+	return nil
+}
 func (c *TCore) WalletSettings(assetID uint32) (map[string]string, error) {
 	return c.wallets[assetID].settings, nil
 }
@@ -1915,6 +1972,9 @@ func (c *TCore) UpdateCert(string, []byte) error {
 func (c *TCore) UpdateDEXHost(string, string, []byte, any) (*core.Exchange, error) {
 	return nil, nil
 }
+func (c *TCore) UpdateDEXAltHosts(string, []string) error {
+	return nil
+}
 func (c *TCore) WalletRestorationInfo(pw []byte, assetID uint32) ([]*asset.WalletRestoration, error) {
 	return nil, nil
 }
@@ -1946,6 +2006,12 @@ func (c *TCore) UnapproveToken(appPW []byte, assetID uint32, version uint32) (st
 func (c *TCore) ApproveTokenFee(assetID uint32, version uint32, approval bool) (uint64, error) {
 	return 0, nil
 }
+func (c *TCore) TokenAllowances(assetID uint32) ([]*asset.TokenAllowance, error) {
+	return nil, nil
+}
+func (c *TCore) SetTokenAllowance(appPW []byte, assetID, version uint32, allowance uint64) (string, error) {
+	return "", nil
+}
 
 func (c *TCore) StakeStatus(assetID uint32) (*asset.TicketStakingStatus, error) {
 	res := asset.TicketStakingStatus{
@@ -2007,6 +2073,30 @@ func (c *TCore) Language() string {
 	return c.lang
 }
 
+func (c *TCore) TopicSettings() map[db.Topic]*db.TopicSettings {
+	return nil
+}
+
+func (c *TCore) UpdateTopicSettings(db.Topic, *db.TopicSettings) error {
+	return nil
+}
+
+func (c *TCore) NoteTemplates() map[db.Topic]*db.NoteTemplate {
+	return nil
+}
+
+func (c *TCore) UpdateNoteTemplate(db.Topic, *db.NoteTemplate) error {
+	return nil
+}
+
+func (c *TCore) ConfTargets() map[uint32]*db.ConfTarget {
+	return nil
+}
+
+func (c *TCore) SetConfTarget(uint32, *db.ConfTarget) error {
+	return nil
+}
+
 func (c *TCore) TakeAction(assetID uint32, actionID string, actionB json.RawMessage) error {
 	if rand.Float32() < 0.25 {
 		return fmt.Errorf("it didn't work")
@@ -2215,6 +2305,10 @@ func (m *TMarketMaker) StartBot(startCfg *mm.StartConfig, alternateConfigPath *s
 	return nil
 }
 
+func (m *TMarketMaker) WindDownBot(mkt *mm.MarketWithHost) error {
+	return m.StopBot(mkt)
+}
+
 func (m *TMarketMaker) StopBot(mkt *mm.MarketWithHost) error {
 	m.runningBotsMtx.Lock()
 	startTime, running := m.runningBots[*mkt]
@@ -2603,6 +2697,32 @@ func (m *TMarketMaker) CEXBook(host string, baseID, quoteID uint32) (buys, sells
 	return book.Buys, book.Sells, nil
 }
 
+func (m *TMarketMaker) DryRunBot(cfg *mm.BotConfig) (*mm.DryRunReport, error) {
+	return &mm.DryRunReport{}, nil
+}
+
+func (m *TMarketMaker) RecommendedAllocation(cfg *mm.BotConfig) (*mm.AllocationAdvice, error) {
+	return &mm.AllocationAdvice{}, nil
+}
+
+func (m *TMarketMaker) BenchmarkReport(startTime int64, mkt *mm.MarketWithHost) (*mm.BenchmarkReport, error) {
+	holdUSD := rand.Float64() * 10000
+	excessUSD := (rand.Float64() - 0.5) * holdUSD / 10
+	return &mm.BenchmarkReport{
+		StartTime:      startTime,
+		EndTime:        time.Unix(startTime, 0).Add(time.Hour * 5).Unix(),
+		HoldUSD:        holdUSD,
+		HoldStartUSD:   holdUSD * (0.9 + rand.Float64()*0.2),
+		BotUSD:         holdUSD + excessUSD,
+		ExcessUSD:      excessUSD,
+		ExcessRatio:    excessUSD / holdUSD,
+		FeesUSD:        rand.Float64() * 10,
+		CEXCostsUSD:    rand.Float64() * 10,
+		GrossExcessUSD: excessUSD + 20,
+		BeatsHold:      excessUSD > 0,
+	}, nil
+}
+
 func makeRequiredAction(assetID uint32, actionID string) *asset.ActionRequiredNote {
 	txID := dex.Bytes(encode.RandomBytes(32)).String()
 	var payload any
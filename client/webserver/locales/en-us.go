@@ -3,8 +3,8 @@ package locales
 import "decred.org/dcrdex/client/intl"
 
 var EnUS = map[string]*intl.Translation{
-	"Language":                       {T: "en-US"},   // the bcp47 lang tag
-	"Markets":                        {T: "Markets"}, // unused
+	"Language":                       {T: "en-US"}, // the bcp47 lang tag
+	"Markets":                        {T: "Markets"},
 	"Wallets":                        {T: "Wallets"}, // unused
 	"Notifications":                  {T: "Notifications"},
 	"Recent Activity":                {T: "Recent Activity"},
@@ -203,6 +203,9 @@ var EnUS = map[string]*intl.Translation{
 	"ready":                       {T: "ready"},
 	"off":                         {T: "off"},
 	"Export Trades":               {T: "Export Trades"},
+	"Export Matches":              {T: "Export Matches"},
+	"match_export_generic":        {T: "Generic CSV"},
+	"match_export_universal":      {T: "Tax tool CSV"},
 	"change the wallet type":      {T: "change the wallet type"},
 	"confirmations":               {T: "confirmations"},
 	"pick a different asset":      {T: "pick a different asset"},
@@ -683,4 +686,17 @@ var EnUS = map[string]*intl.Translation{
 	"Wallet Balances":             {T: "Wallet Balances"},
 	"Placements":                  {T: "Placements"},
 	"delete_bot":                  {T: "Delete Bot"},
+	"Search":                      {T: "Search"},
+	"order_search_placeholder":    {T: "Order, match or tx ID, or address"},
+	"troubled_matches":            {T: "Troubled Matches"},
+	"troubled_matches_msg":        {T: "These matches are not progressing on their own. Review the problems before choosing an action."},
+	"troubled_match_problems":     {T: "Problems"},
+	"swap_refundable_after":       {T: "Refundable after"},
+	"Abandon":                     {T: "Abandon"},
+	"swap_secret":                 {T: "Swap Secret (hex)"},
+	"swap_secret_tooltip":         {T: "The secret revealed by the maker's redemption of your swap contract, which is required to redeem the maker's swap contract."},
+	"guest_login":                 {T: "Guest Login"},
+	"guest_password":              {T: "Guest Password"},
+	"guest_view_msg":              {T: "A read-only view of the markets and order books of the connected DEX servers."},
+	"guest_no_markets":            {T: "No markets available"},
 }
@@ -133,6 +133,18 @@ func (s *WebServer) rejectUnauthed(next http.Handler) http.Handler {
 	})
 }
 
+// rejectNonGuests responds with an error unless the request is from a guest
+// or an authenticated user.
+func (s *WebServer) rejectNonGuests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isGuest(r) && !s.isAuthed(r) {
+			http.Error(w, "not authorized - guest login first", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // requireDEXConnection ensures that the user has completely registered with at
 // least 1 DEX before allowing the incoming request to proceed. Redirects to the
 // register page if the user has not connected any DEX.
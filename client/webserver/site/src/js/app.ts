@@ -13,6 +13,7 @@ import DexSettingsPage from './dexsettings'
 import MarketMakerArchivesPage from './mmarchives'
 import MarketMakerLogsPage from './mmlogs'
 import InitPage from './init'
+import GuestPage from './guest'
 import { MM } from './mmutil'
 import { RateEncodingFactor, StatusExecuted, hasActiveMatches } from './orderutil'
 import { getJSON, postJSON, Errors } from './http'
@@ -107,7 +108,8 @@ const constructors: Record<string, PageClass> = {
   mm: MarketMakerPage,
   mmsettings: MarketMakerSettingsPage,
   mmarchives: MarketMakerArchivesPage,
-  mmlogs: MarketMakerLogsPage
+  mmlogs: MarketMakerLogsPage,
+  guest: GuestPage
 }
 
 interface LangData {
@@ -294,6 +296,9 @@ export default class Application {
     this.updateMenuItemsDisplay()
     // initialize desktop notifications
     ntfn.fetchDesktopNtfnSettings()
+    // Guests without an app login are not sent notifications. The guest page
+    // connects the guest websocket itself.
+    if (!this.authed && handler === 'guest') return
     // Connect the websocket and register the notification route.
     ws.connect(getSocketURI(), () => this.reconnected())
     ws.registerRoute(notificationRoute, (note: CoreNote) => {
@@ -1451,21 +1456,27 @@ export default class Application {
   /*
    * canAccelerateOrder returns true if the "from" wallet of the order
    * supports acceleration, and if the order has unconfirmed swap
-   * transactions.
+   * transactions. Wallets that can replace transactions at a higher fee rate
+   * can also accelerate unconfirmed redemptions.
    */
   canAccelerateOrder (order: Order): boolean {
     const walletTraitAccelerator = 1 << 4
-    let fromAssetID
-    if (order.sell) fromAssetID = order.baseID
-    else fromAssetID = order.quoteID
-    const wallet = this.walletMap[fromAssetID]
-    if (!wallet || !(wallet.traits & walletTraitAccelerator)) return false
+    const walletTraitFeeBumper = 1 << 19
+    let fromAssetID, toAssetID
+    if (order.sell) [fromAssetID, toAssetID] = [order.baseID, order.quoteID]
+    else [fromAssetID, toAssetID] = [order.quoteID, order.baseID]
+    const fromWallet = this.walletMap[fromAssetID]
+    const toWallet = this.walletMap[toAssetID]
+    const isFeeBumper = (w?: WalletState) => Boolean(w && (w.traits & walletTraitFeeBumper))
+    const canBumpSwaps = Boolean(fromWallet && (fromWallet.traits & walletTraitAccelerator)) || isFeeBumper(fromWallet)
+    const canBumpRedeems = isFeeBumper(toWallet)
+    if (!canBumpSwaps && !canBumpRedeems) return false
     if (order.matches) {
       for (let i = 0; i < order.matches?.length; i++) {
         const match = order.matches[i]
-        if (match.swap && match.swap.confs && match.swap.confs.count === 0 && !match.revoked) {
-          return true
-        }
+        if (match.revoked) continue
+        if (canBumpSwaps && match.swap && match.swap.confs && match.swap.confs.count === 0) return true
+        if (canBumpRedeems && match.redeem && match.redeem.confs && match.redeem.confs.count === 0) return true
       }
     }
     return false
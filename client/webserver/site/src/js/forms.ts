@@ -2198,7 +2198,8 @@ export class CEXConfigurationForm {
       const res = await MM.updateCEXConfig({
         name: cexName,
         apiKey: apiKey,
-        apiSecret: apiSecret
+        apiSecret: apiSecret,
+        endpoints: app().mmStatus.cexes[cexName]?.config.endpoints
       })
       if (!app().checkResponse(res)) throw res
       this.updated(cexName, true)
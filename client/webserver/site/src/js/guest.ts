@@ -0,0 +1,252 @@
+import Doc from './doc'
+import BasePage from './basepage'
+import OrderBook from './orderbook'
+import ws from './ws'
+import { postJSON, getJSON } from './http'
+import { bind as bindForm } from './forms'
+import {
+  app,
+  PageElement,
+  Asset,
+  Spot,
+  UnitInfo,
+  BookUpdate,
+  MarketOrderBook,
+  CoreOrderBook,
+  MiniOrder,
+  RemainderUpdate,
+  ConnectionStatus
+} from './registry'
+
+const bookRoute = 'book'
+const bookOrderRoute = 'book_order'
+const unbookOrderRoute = 'unbook_order'
+const updateRemainingRoute = 'update_remaining'
+const unmarketRoute = 'unmarket'
+
+// GuestExchange is the public information about a DEX server returned by the
+// /api/guest/markets endpoint.
+interface GuestExchange {
+  host: string
+  markets: Record<string, GuestMarket>
+  assets: Record<number, Asset>
+  candleDurs: string[]
+  connectionStatus: ConnectionStatus
+}
+
+// GuestMarket is a market without the user's orders.
+interface GuestMarket {
+  name: string
+  baseid: number
+  basesymbol: string
+  quoteid: number
+  quotesymbol: string
+  lotsize: number
+  ratestep: number
+  epochlen: number
+  spot: Spot | undefined
+}
+
+interface GuestMarketSelection {
+  host: string
+  mkt: GuestMarket
+  bui: UnitInfo
+  qui: UnitInfo
+}
+
+/*
+ * GuestPage is a read-only view of the markets and order books of the
+ * connected DEX servers for guests, who log in with the guest password and
+ * have no access to wallets, orders, or trading.
+ */
+export default class GuestPage extends BasePage {
+  page: Record<string, PageElement>
+  orderRowTmpl: PageElement
+  markets: GuestMarketSelection[]
+  market: GuestMarketSelection | null
+  book: OrderBook | null
+
+  constructor (main: HTMLElement) {
+    super()
+    const page = this.page = Doc.idDescendants(main)
+    this.markets = []
+    this.market = null
+    this.book = null
+
+    if (page.guestLoginForm) {
+      bindForm(page.guestLoginForm, page.guestLoginSubmit, () => { this.login() })
+      page.guestPass.focus()
+      return
+    }
+
+    this.orderRowTmpl = page.orderRowTmpl
+    this.orderRowTmpl.remove()
+    Doc.bind(page.marketSelect, 'change', () => {
+      const mkt = this.markets[parseInt(page.marketSelect.value || '')]
+      if (mkt) this.setMarket(mkt)
+    })
+    ws.registerRoute(bookRoute, (data: BookUpdate) => { this.handleBookRoute(data) })
+    ws.registerRoute(bookOrderRoute, (data: BookUpdate) => { this.handleBookOrderRoute(data) })
+    ws.registerRoute(unbookOrderRoute, (data: BookUpdate) => { this.handleUnbookOrderRoute(data) })
+    ws.registerRoute(updateRemainingRoute, (data: BookUpdate) => { this.handleUpdateRemainingRoute(data) })
+    // Guests without an app login are only served public market data over the
+    // guest websocket.
+    if (!app().authed) {
+      const protocol = (window.location.protocol === 'https:') ? 'wss' : 'ws'
+      ws.connect(`${protocol}://${window.location.host}/guest/ws`, () => window.location.reload())
+    }
+    this.loadMarkets()
+  }
+
+  /* login submits the guest password, and reloads the page on success. */
+  async login () {
+    const page = this.page
+    Doc.hide(page.guestLoginErr)
+    const loaded = app().loading(page.guestLoginForm)
+    const res = await postJSON('/api/guestlogin', { pass: page.guestPass.value })
+    loaded()
+    page.guestPass.value = ''
+    if (!app().checkResponse(res)) {
+      Doc.showFormError(page.guestLoginErr, res.msg)
+      return
+    }
+    window.location.reload()
+  }
+
+  /* loadMarkets fetches the markets and fills the market selector. */
+  async loadMarkets () {
+    const page = this.page
+    const res = await getJSON('/api/guest/markets')
+    if (!app().checkResponse(res)) {
+      console.error('failed to fetch markets:', res?.msg || String(res))
+      return
+    }
+    Doc.empty(page.marketSelect)
+    this.markets = []
+    for (const xc of Object.values(res.exchanges as Record<string, GuestExchange>)) {
+      for (const mkt of Object.values(xc.markets || {})) {
+        const [b, q] = [xc.assets[mkt.baseid], xc.assets[mkt.quoteid]]
+        if (!b || !q) continue
+        const opt = document.createElement('option')
+        opt.value = String(this.markets.length)
+        opt.textContent = `${mkt.basesymbol.toUpperCase()}-${mkt.quotesymbol.toUpperCase()} @ ${xc.host}`
+        page.marketSelect.appendChild(opt)
+        this.markets.push({ host: xc.host, mkt, bui: b.unitInfo, qui: q.unitInfo })
+      }
+    }
+    Doc.setVis(this.markets.length === 0, page.noMarkets)
+    Doc.setVis(this.markets.length > 0, page.marketSelect)
+    if (this.markets.length) this.setMarket(this.markets[0])
+  }
+
+  /*
+   * setMarket displays the market's order book and subscribes to the book
+   * updates.
+   */
+  async setMarket (market: GuestMarketSelection) {
+    const page = this.page
+    const { host, mkt, bui, qui } = market
+    this.market = market
+    this.book = null
+    page.mktHost.textContent = host
+    page.mktLotSize.textContent = `${Doc.formatCoinAtom(mkt.lotsize, bui)} ${mkt.basesymbol.toUpperCase()}`
+    page.mktPrice.textContent = mkt.spot ? Doc.formatRateAtomToRateStep(mkt.spot.rate, bui, qui, mkt.ratestep) : '-'
+    for (const el of Doc.applySelector(page.marketBox, '[data-base-symbol]')) el.textContent = mkt.basesymbol.toUpperCase()
+    for (const el of Doc.applySelector(page.marketBox, '[data-quote-symbol]')) el.textContent = mkt.quotesymbol.toUpperCase()
+    Doc.empty(page.sellRows, page.buyRows)
+    Doc.show(page.marketBox)
+    ws.request('loadmarket', { host, base: mkt.baseid, quote: mkt.quoteid })
+    const res = await postJSON('/api/guest/book', { host, base: mkt.baseid, quote: mkt.quoteid })
+    if (this.market !== market) return // user already changed markets
+    if (!app().checkResponse(res)) {
+      console.error('failed to fetch order book:', res?.msg || String(res))
+      return
+    }
+    // The websocket book may have arrived first.
+    if (!this.book) this.setBook(res.book)
+  }
+
+  /* setBook replaces the order book. */
+  setBook (book: CoreOrderBook) {
+    if (!this.market) return
+    const { mkt } = this.market
+    const mktBook: MarketOrderBook = { base: mkt.baseid, quote: mkt.quoteid, book }
+    this.book = new OrderBook(mktBook, mkt.basesymbol, mkt.quotesymbol)
+    this.loadTables()
+  }
+
+  /* loadTables redraws the order book tables. */
+  loadTables () {
+    const { page, book } = this
+    if (!book) return
+    Doc.empty(page.sellRows, page.buyRows)
+    // Sells are sorted mid-gap first, but are displayed above the buys.
+    for (const ord of [...book.sells].reverse()) page.sellRows.appendChild(this.orderRow(ord))
+    for (const ord of book.buys) page.buyRows.appendChild(this.orderRow(ord))
+  }
+
+  /* orderRow creates a row for an order book table. */
+  orderRow (ord: MiniOrder): PageElement {
+    const { mkt, bui, qui } = this.market as GuestMarketSelection
+    const tr = this.orderRowTmpl.cloneNode(true) as PageElement
+    const tmpl = Doc.parseTemplate(tr)
+    tmpl.rate.textContent = Doc.formatRateAtomToRateStep(ord.msgRate, bui, qui, mkt.ratestep)
+    tmpl.rate.classList.add(ord.sell ? 'sellcolor' : 'buycolor')
+    tmpl.qty.textContent = Doc.formatCoinAtomToLotSizeBaseCurrency(ord.qtyAtomic, bui, mkt.lotsize)
+    return tr
+  }
+
+  /* isCurrentMarket is true if the book update is for the displayed market. */
+  isCurrentMarket (data: BookUpdate): boolean {
+    return data.host === this.market?.host && data.marketID === this.market?.mkt.name
+  }
+
+  /* handleBookRoute is the handler for the 'book' notification, which is sent
+   * in response to a new market subscription with the entire order book.
+   */
+  handleBookRoute (data: BookUpdate) {
+    const mktBook: MarketOrderBook = data.payload
+    if (!this.market || data.host !== this.market.host) return
+    if (mktBook.base !== this.market.mkt.baseid || mktBook.quote !== this.market.mkt.quoteid) return
+    this.setBook(mktBook.book)
+  }
+
+  /* handleBookOrderRoute is the handler for 'book_order' notifications. */
+  handleBookOrderRoute (data: BookUpdate) {
+    if (!this.book || !this.isCurrentMarket(data)) return
+    const ord = data.payload as MiniOrder
+    if (ord.rate > 0) this.book.add(ord)
+    this.loadTables()
+  }
+
+  /* handleUnbookOrderRoute is the handler for 'unbook_order' notifications. */
+  handleUnbookOrderRoute (data: BookUpdate) {
+    if (!this.book || !this.isCurrentMarket(data)) return
+    this.book.remove(data.payload.id)
+    this.loadTables()
+  }
+
+  /*
+   * handleUpdateRemainingRoute is the handler for 'update_remaining'
+   * notifications.
+   */
+  handleUpdateRemainingRoute (data: BookUpdate) {
+    if (!this.book || !this.isCurrentMarket(data)) return
+    const update = data.payload as RemainderUpdate
+    this.book.updateRemaining(update.id, update.qty, update.qtyAtomic)
+    this.loadTables()
+  }
+
+  /*
+   * unload is called by the Application when the user navigates away from
+   * the page.
+   */
+  unload () {
+    if (!this.page.marketSelect) return
+    ws.request(unmarketRoute, {})
+    ws.deregisterRoute(bookRoute)
+    ws.deregisterRoute(bookOrderRoute)
+    ws.deregisterRoute(unbookOrderRoute)
+    ws.deregisterRoute(updateRemainingRoute)
+  }
+}
@@ -96,6 +96,7 @@ export const ID_MATCH_STATUS_REDEMPTION_SENT = 'MATCH_STATUS_REDEMPTION_SENT'
 export const ID_MATCH_STATUS_REDEMPTION_CONFIRMED = 'MATCH_REDEMPTION_CONFIRMED'
 export const ID_MATCH_STATUS_REVOKED = 'MATCH_STATUS_REVOKED'
 export const ID_MATCH_STATUS_REFUNDED = 'MATCH_STATUS_REFUNDED'
+export const ID_MATCH_STATUS_ABANDONED = 'MATCH_STATUS_ABANDONED'
 export const ID_MATCH_STATUS_REFUND_PENDING = 'MATCH_STATUS_REFUND_PENDING'
 export const ID_MATCH_STATUS_REDEEM_PENDING = 'MATCH_STATUS_REDEEM_PENDING'
 export const ID_MATCH_STATUS_COMPLETE = 'MATCH_STATUS_COMPLETE'
@@ -44,6 +44,7 @@ import {
   SpotPriceNote,
   SupportedAsset,
   TradeForm,
+  TradingLimitNote,
   UnitInfo,
   WalletStateNote
 } from './registry'
@@ -429,6 +430,9 @@ export default class MarketsPage extends BasePage {
       spots: (note: SpotPriceNote) => { this.handlePriceUpdate(note) },
       walletstate: (note: WalletStateNote) => { this.handleWalletState(note) },
       reputation: () => { this.updateReputation() },
+      tradinglimit: (note: TradingLimitNote) => {
+        if (note.host === this.market?.dex.host) this.updateReputation()
+      },
       feepayment: () => { this.updateReputation() },
       runstats: () => {
         // nothing to do, we don't support displaying MM form at the moment
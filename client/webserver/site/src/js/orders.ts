@@ -2,12 +2,15 @@ import Doc from './doc'
 import BasePage from './basepage'
 import * as OrderUtil from './orderutil'
 import * as intl from './locales'
-import { postJSON } from './http'
+import { postJSON, getJSON } from './http'
 import {
   app,
   PageElement,
   OrderFilter,
-  Order
+  Order,
+  Match,
+  TroubledMatch,
+  SwapRecoveryOption
 } from './registry'
 
 const orderBatchSize = 50
@@ -19,6 +22,8 @@ export default class OrdersPage extends BasePage {
   loading: boolean
   currentForm: PageElement
   orderTmpl: PageElement
+  troubledMatchTmpl: PageElement
+  recovery: { match: TroubledMatch, option: SwapRecoveryOption }
   filterState: OrderFilter
   page: Record<string, PageElement>
 
@@ -32,6 +37,8 @@ export default class OrdersPage extends BasePage {
     const page = this.page = Doc.idDescendants(main)
     this.orderTmpl = page.rowTmpl
     this.orderTmpl.remove()
+    this.troubledMatchTmpl = page.troubledMatchTmpl
+    this.troubledMatchTmpl.remove()
 
     // filterState will store arrays of strings. The assets and statuses
     // sub-filters will need to be converted to ints for JSON encoding.
@@ -56,6 +63,7 @@ export default class OrdersPage extends BasePage {
     readFilter(page.hostFilter, 'hosts')
     readFilter(page.assetFilter, 'assets')
     readFilter(page.statusFilter, 'statuses')
+    page.textFilter.value = filterState.text = search.get('text') || ''
 
     const applyButtons: HTMLElement[] = []
     const monitorFilter = (form: HTMLElement, filterKey: string) => {
@@ -82,6 +90,13 @@ export default class OrdersPage extends BasePage {
     monitorFilter(page.assetFilter, 'assets')
     monitorFilter(page.statusFilter, 'statuses')
 
+    // The search text is submitted with the enter key.
+    Doc.bind(page.textFilter, 'keyup', (e: KeyboardEvent) => {
+      if (e.key !== 'Enter') return
+      this.submitFilter()
+      applyButtons.forEach(bttn => Doc.hide(bttn))
+    })
+
     Doc.bind(this.main, 'scroll', () => {
       if (this.loading) return
       const belowBottom = page.ordersTable.offsetHeight - this.main.offsetHeight - this.main.scrollTop
@@ -107,6 +122,10 @@ export default class OrdersPage extends BasePage {
       this.exportOrders()
     })
 
+    Doc.bind(page.exportMatches, 'click', () => {
+      this.exportMatches()
+    })
+
     page.showArchivedDateField.addEventListener('change', () => {
       if (page.showArchivedDateField.checked) Doc.show(page.archivedDateField)
       else Doc.hide(page.archivedDateField, page.deleteArchivedRecordsErr)
@@ -137,14 +156,17 @@ export default class OrdersPage extends BasePage {
       this.deleteArchivedRecords(date)
     })
 
+    Doc.bind(page.recoverSwapSubmit, 'click', () => { this.recoverSwap() })
+
     this.submitFilter()
+    this.loadTroubledMatches()
   }
 
   /* showForm shows a modal form with a little animation. */
   async showForm (form: HTMLElement) {
     this.currentForm = form
     const page = this.page
-    Doc.hide(page.deleteArchivedRecordsForm)
+    Doc.hide(page.deleteArchivedRecordsForm, page.recoverSwapForm)
     form.style.right = '10000px'
     Doc.show(page.forms, form)
     const shift = (page.forms.offsetWidth + form.offsetWidth) / 2
@@ -154,6 +176,101 @@ export default class OrdersPage extends BasePage {
     form.style.right = '0px'
   }
 
+  /*
+   * loadTroubledMatches fetches the matches that need manual action and lists
+   * them with buttons for their available recovery actions.
+   */
+  async loadTroubledMatches () {
+    const page = this.page
+    const res = await getJSON('/api/troubledmatches')
+    if (!app().checkResponse(res)) {
+      console.error('error fetching troubled matches', res.msg)
+      return
+    }
+    Doc.empty(page.troubledMatchesBody)
+    const matches: TroubledMatch[] = res.matches || []
+    Doc.setVis(matches.length > 0, page.troubledMatches)
+    for (const m of matches) {
+      const tr = this.troubledMatchTmpl.cloneNode(true) as HTMLElement
+      const tmpl = Doc.parseTemplate(tr)
+      const xc = app().exchanges[m.host]
+      const mkt = xc?.markets[m.marketID]
+      let mktID = m.marketID
+      if (mkt) {
+        const [baseUnitInfo, quoteUnitInfo] = [app().unitInfo(mkt.baseid, xc), app().unitInfo(mkt.quoteid, xc)]
+        mktID = `${baseUnitInfo.conventional.unit}-${quoteUnitInfo.conventional.unit}`
+        tmpl.qty.textContent = `${Doc.formatCoinAtom(m.qty, baseUnitInfo)} ${baseUnitInfo.conventional.unit}`
+      }
+      tmpl.market.textContent = `${mktID} @ ${m.host}`
+      tmpl.matchLink.textContent = m.matchID.substring(0, 8)
+      tmpl.matchLink.title = m.matchID
+      tmpl.matchLink.href = `order/${m.orderID}`
+      tmpl.side.textContent = intl.prep(m.side === OrderUtil.Maker ? intl.ID_MAKER : intl.ID_TAKER)
+      const status = { status: m.status, revoked: m.revoked, side: m.side, active: true } as Match
+      tmpl.status.textContent = OrderUtil.matchStatusString(status)
+      for (const problem of m.problems) {
+        const div = document.createElement('div')
+        div.textContent = problem
+        tmpl.problems.appendChild(div)
+      }
+      if (m.lockTime) {
+        tmpl.lockTime.textContent = new Date(m.lockTime).toLocaleString()
+        Doc.show(tmpl.lockTimeBox)
+      }
+      const bttns: Record<string, PageElement> = {
+        refund: tmpl.refundBttn,
+        redeem: tmpl.redeemBttn,
+        abandon: tmpl.abandonBttn
+      }
+      for (const opt of m.options) {
+        const bttn = bttns[opt.action]
+        if (!bttn) continue
+        Doc.show(bttn)
+        Doc.bind(bttn, 'click', () => { this.showRecoverSwapForm(m, opt, bttn.textContent || '') })
+      }
+      app().bindInternalNavigation(tr)
+      page.troubledMatchesBody.appendChild(tr)
+    }
+  }
+
+  /*
+   * showRecoverSwapForm shows the confirmation form for a troubled match's
+   * recovery action, with the consequences of the action.
+   */
+  showRecoverSwapForm (match: TroubledMatch, option: SwapRecoveryOption, actionName: string) {
+    const page = this.page
+    this.recovery = { match, option }
+    page.recoverSwapHeader.textContent = actionName
+    page.recoverSwapMatchID.textContent = match.matchID
+    page.recoverSwapDescription.textContent = option.description
+    page.recoverSwapSecret.value = ''
+    Doc.setVis(option.needsSecret, page.recoverSwapSecretBox)
+    Doc.hide(page.recoverSwapErr)
+    this.showForm(page.recoverSwapForm)
+  }
+
+  /* recoverSwap executes the confirmed recovery action. */
+  async recoverSwap () {
+    const page = this.page
+    const { match, option } = this.recovery
+    const req = {
+      orderID: match.orderID,
+      matchID: match.matchID,
+      action: option.action,
+      secret: option.needsSecret ? (page.recoverSwapSecret.value || '').trim() : undefined
+    }
+    const loaded = app().loading(page.recoverSwapForm)
+    const res = await postJSON('/api/recoverswap', req)
+    loaded()
+    if (!app().checkResponse(res)) {
+      Doc.showFormError(page.recoverSwapErr, res.msg)
+      return
+    }
+    Doc.hide(page.forms)
+    this.loadTroubledMatches()
+    this.submitFilter()
+  }
+
   /* setOrders empties the order table and appends the specified orders. */
   setOrders (orders: Order[]) {
     Doc.empty(this.page.tableBody)
@@ -228,6 +345,7 @@ export default class OrdersPage extends BasePage {
     filterState.hosts = parseSubFilter(page.hostFilter)
     filterState.assets = parseSubFilter(page.assetFilter).map((s: string) => parseInt(s))
     filterState.statuses = parseSubFilter(page.statusFilter).map((s: string) => parseInt(s))
+    filterState.text = page.textFilter.value.trim()
     this.setOrders(await this.fetchOrders())
   }
 
@@ -241,6 +359,19 @@ export default class OrdersPage extends BasePage {
 
   /* exportOrders downloads a csv of the user's orders based on the current filter. */
   exportOrders () {
+    this.exportFiltered('/orders/export')
+  }
+
+  /*
+   * exportMatches downloads a csv of the matches of the user's orders based on
+   * the current filter, for accounting.
+   */
+  exportMatches () {
+    this.exportFiltered('/orders/exportmatches', { format: this.page.matchExportFormat.value || 'generic' })
+  }
+
+  /* exportFiltered opens the export path with the current filter. */
+  exportFiltered (path: string, params?: Record<string, string>) {
     this.offset = ''
     const filterState = this.currentFilter()
     const url = new URL(window.location.href)
@@ -254,8 +385,10 @@ export default class OrdersPage extends BasePage {
     setQuery('hosts')
     setQuery('assets')
     setQuery('statuses')
+    if (filterState.text) search.append('text', filterState.text)
+    for (const [k, v] of Object.entries(params ?? {})) search.append(k, v)
     url.search = search.toString()
-    url.pathname = '/orders/export'
+    url.pathname = path
     window.open(url.toString())
   }
 
@@ -303,6 +436,7 @@ export default class OrdersPage extends BasePage {
       hosts: filterState.hosts,
       assets: filterState.assets?.map((s: any) => parseInt(s)),
       statuses: filterState.statuses?.map((s: any) => parseInt(s)),
+      text: filterState.text,
       n: orderBatchSize,
       offset: this.offset
     }
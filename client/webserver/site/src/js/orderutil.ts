@@ -169,6 +169,7 @@ export function orderPortion (order: Order, match: Match) : string {
  * describing the match status.
  */
 export function matchStatusString (m: Match): string {
+  if (m.abandoned) return intl.prep(intl.ID_MATCH_STATUS_ABANDONED)
   if (m.revoked) {
     // When revoked, match status is less important than pending action if still
     // active, or the outcome if inactive.
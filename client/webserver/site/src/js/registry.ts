@@ -134,6 +134,8 @@ export interface Order {
   tif: number // limit only
   targetOrderID: string // cancel only
   readyToTick: boolean
+  note?: string
+  tags?: string[]
 }
 
 export interface Match {
@@ -141,6 +143,7 @@ export interface Match {
   status: number
   active: boolean
   revoked: boolean
+  abandoned?: boolean
   rate: number // in atoms
   qty: number // in atoms
   side: number
@@ -152,6 +155,31 @@ export interface Match {
   refund: Coin
   stamp: number
   isCancel: boolean
+  note?: string
+  tags?: string[]
+}
+
+export interface SwapRecoveryOption {
+  action: string
+  description: string
+  needsSecret?: boolean
+}
+
+export interface TroubledMatch {
+  host: string
+  marketID: string
+  orderID: string
+  matchID: string
+  status: number
+  side: number
+  qty: number // in atoms, in base currency
+  rate: number // in atoms
+  fromAsset: number
+  toAsset: number
+  revoked: boolean
+  lockTime?: number // ms
+  problems: string[]
+  options: SwapRecoveryOption[]
 }
 
 export interface Spot {
@@ -377,6 +405,20 @@ export interface CoreNote {
   stamp: number
   acked: boolean
   id: string
+  priority?: number
+  category?: string
+  data?: NoteData
+}
+
+export interface NoteData {
+  host?: string
+  assetID?: number
+  marketID?: string
+  orderID?: string
+  matchID?: string
+  coinID?: string
+  amount?: number
+  error?: string
 }
 
 export interface BondNote extends CoreNote {
@@ -393,6 +435,21 @@ export interface ReputationNote extends CoreNote {
   rep: Reputation
 }
 
+export interface TradingLimitStatus {
+  host: string
+  tier: number
+  usedParcels: number
+  parcelLimit: number
+  remainingParcels: number
+  remainingLots: Record<string, number>
+  approaching: boolean
+}
+
+export interface TradingLimitNote extends CoreNote {
+  host: string
+  limits: TradingLimitStatus
+}
+
 export interface BalanceNote extends CoreNote {
   assetID: number
   balance: WalletBalance
@@ -752,6 +809,7 @@ export interface OrderFilter {
   market?: OrderFilterMarket
   statuses?: number[]
   completedOnly?: boolean
+  text?: string
 }
 
 export interface OrderPlacement {
@@ -769,6 +827,7 @@ export interface BasicMarketMakingConfig {
   sellPlacements: OrderPlacement[]
   buyPlacements: OrderPlacement[]
   driftTolerance: number
+  minProfitMargin?: number
 }
 
 export interface ArbMarketMakingPlacement {
@@ -827,6 +886,7 @@ export interface BotConfig {
   quoteWalletOptions?: Record<string, string>
   cexName: string
   uiConfig: UIConfig
+  inventoryGroup?: string
   basicMarketMakingConfig?: BasicMarketMakingConfig
   arbMarketMakingConfig?: ArbMarketMakingConfig
   simpleArbConfig?: SimpleArbConfig
@@ -836,6 +896,7 @@ export interface CEXConfig {
   name: string
   apiKey: string
   apiSecret: string
+  endpoints?: string[]
 }
 
 export interface MarketWithHost {
@@ -986,6 +1047,7 @@ export interface TradePlacement {
   usedDex: Record<number, number>
   usedCex: number
   error?: BotProblems
+  unprofitable?: boolean
 }
 
 export interface OrderReport {
@@ -1021,11 +1083,19 @@ export interface MMBotStatus {
   runStats?: RunStats
   latestEpoch?: EpochReport
   cexProblems?: CEXProblems
+  windingDown?: boolean
+}
+
+export interface InventoryGroupStatus {
+  markets: MarketWithHost[]
+  dex: Record<number, BotBalance>
+  profit: number
 }
 
 export interface MarketMakingStatus {
   cexes: Record<string, MMCEXStatus>
   bots: MMBotStatus[]
+  inventoryGroups?: Record<string, InventoryGroupStatus>
 }
 
 export interface DEXOrderEvent {
@@ -1151,10 +1221,33 @@ export interface MarketMakingRunOverview {
   endTime: number
   cfgs: StampedBotConfig[]
   initialBalances: Record<number, number>
+  initialFiatRates?: Record<number, number>
   profitLoss: ProfitLoss
   finalState: BalanceState
 }
 
+export interface BenchmarkReport {
+  startTime: number
+  endTime: number
+  running: boolean
+  hold: Record<number, Amount>
+  holdUSD: number
+  holdStartUSD: number
+  holdReturn: number
+  bot: Record<number, Amount>
+  botUSD: number
+  botReturn: number
+  excessUSD: number
+  excessRatio: number
+  annualizedExcessRatio: number
+  fees: Record<number, Amount>
+  feesUSD: number
+  cexCosts: Record<number, Amount>
+  cexCostsUSD: number
+  grossExcessUSD: number
+  beatsHold: boolean
+}
+
 export interface WalletPeer {
   addr: string
   source: PeerSource
@@ -58,6 +58,8 @@ const (
 	authCK = "dexauth"
 	// pwKeyCK is the cookie used to unencrypt the user's password.
 	pwKeyCK = "sessionkey"
+	// guestCK is the guest token cookie key.
+	guestCK = "dexguest"
 	// ctxKeyUserInfo is used in the authorization middleware for saving user
 	// info in http request contexts.
 	ctxKeyUserInfo = contextKey("userinfo")
@@ -94,6 +96,7 @@ type clientCore interface {
 	websocket.Core
 	Network() dex.Network
 	Exchanges() map[string]*core.Exchange
+	Book(host string, base, quote uint32) (*core.OrderBook, error)
 	Exchange(host string) (*core.Exchange, error)
 	PostBond(form *core.PostBondForm) (*core.PostBondResult, error)
 	RedeemPrepaidBond(appPW []byte, code []byte, host string, certI any) (tier uint64, err error)
@@ -128,7 +131,9 @@ type clientCore interface {
 	NotificationFeed() *core.NoteFeed
 	Logout() error
 	Orders(*core.OrderFilter) ([]*core.Order, error)
+	MatchRecords(*core.OrderFilter) ([]*core.MatchRecord, error)
 	Order(oid dex.Bytes) (*core.Order, error)
+	AnnotateOrder(form *core.AnnotationForm) error
 	MaxBuy(host string, base, quote uint32, rate uint64) (*core.MaxOrderEstimate, error)
 	MaxSell(host string, base, quote uint32) (*core.MaxOrderEstimate, error)
 	AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error)
@@ -137,13 +142,20 @@ type clientCore interface {
 	IsInitialized() bool
 	ExportSeed(pw []byte) (string, error)
 	PreOrder(*core.TradeForm) (*core.OrderEstimate, error)
+	Indicators(form *core.IndicatorsForm) (*core.IndicatorSeries, error)
 	WalletLogFilePath(assetID uint32) (string, error)
 	BondsFeeBuffer(assetID uint32) (uint64, error)
 	PreAccelerateOrder(oidB dex.Bytes) (*core.PreAccelerate, error)
 	AccelerateOrder(pw []byte, oidB dex.Bytes, newFeeRate uint64) (string, error)
+	TroubledMatches() ([]*core.TroubledMatch, error)
+	RecoverSwap(pw []byte, form *core.SwapRecoveryForm) error
+	BondFundingPlan() (*core.BondFundingPlan, error)
+	ApplyBondFundingPlan(plan *core.BondFundingPlan) error
+	TradingLimitStatuses() []*core.TradingLimitStatus
 	AccelerationEstimate(oidB dex.Bytes, newFeeRate uint64) (uint64, error)
 	UpdateCert(host string, cert []byte) error
 	UpdateDEXHost(oldHost, newHost string, appPW []byte, certI any) (*core.Exchange, error)
+	UpdateDEXAltHosts(host string, altHosts []string) error
 	WalletRestorationInfo(pw []byte, assetID uint32) ([]*asset.WalletRestoration, error)
 	ToggleRateSourceStatus(src string, disable bool) error
 	FiatRateSources() map[string]bool
@@ -154,9 +166,12 @@ type clientCore interface {
 	AddWalletPeer(assetID uint32, addr string) error
 	RemoveWalletPeer(assetID uint32, addr string) error
 	Notifications(n int) (notes, pokes []*db.Notification, _ error)
+	SearchNotifications(filter *db.NoteFilter) ([]*db.Notification, error)
 	ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConrim func()) (string, error)
 	UnapproveToken(appPW []byte, assetID uint32, version uint32) (string, error)
 	ApproveTokenFee(assetID uint32, version uint32, approval bool) (uint64, error)
+	TokenAllowances(assetID uint32) ([]*asset.TokenAllowance, error)
+	SetTokenAllowance(appPW []byte, assetID, version uint32, allowance uint64) (string, error)
 	StakeStatus(assetID uint32) (*asset.TicketStakingStatus, error)
 	SetVSP(assetID uint32, addr string) error
 	PurchaseTickets(assetID uint32, pw []byte, n int) error
@@ -168,6 +183,12 @@ type clientCore interface {
 	ConfigureFundsMixer(appPW []byte, assetID uint32, enabled bool) error
 	SetLanguage(string) error
 	Language() string
+	TopicSettings() map[db.Topic]*db.TopicSettings
+	UpdateTopicSettings(topic db.Topic, settings *db.TopicSettings) error
+	NoteTemplates() map[db.Topic]*db.NoteTemplate
+	UpdateNoteTemplate(topic db.Topic, tmpl *db.NoteTemplate) error
+	ConfTargets() map[uint32]*db.ConfTarget
+	SetConfTarget(assetID uint32, target *db.ConfTarget) error
 	TakeAction(assetID uint32, actionID string, actionB json.RawMessage) error
 	RedeemGeocode(appPW, code []byte, msg string) (dex.Bytes, uint64, error)
 	ExtensionModeConfig() *core.ExtensionModeConfig
@@ -177,6 +198,7 @@ type MMCore interface {
 	MarketReport(host string, base, quote uint32) (*mm.MarketReport, error)
 	StartBot(mkt *mm.StartConfig, alternateConfigPath *string, pw []byte) (err error)
 	StopBot(mkt *mm.MarketWithHost) error
+	WindDownBot(mkt *mm.MarketWithHost) error
 	UpdateCEXConfig(updatedCfg *mm.CEXConfig) error
 	CEXBalance(cexName string, assetID uint32) (*libxc.ExchangeBalance, error)
 	UpdateBotConfig(updatedCfg *mm.BotConfig) error
@@ -186,6 +208,9 @@ type MMCore interface {
 	RunOverview(startTime int64, mkt *mm.MarketWithHost) (*mm.MarketMakingRunOverview, error)
 	RunLogs(startTime int64, mkt *mm.MarketWithHost, n uint64, refID *uint64, filter *mm.RunLogFilters) (events, updatedEvents []*mm.MarketMakingEvent, overview *mm.MarketMakingRunOverview, err error)
 	CEXBook(host string, baseID, quoteID uint32) (buys, sells []*core.MiniOrder, _ error)
+	DryRunBot(cfg *mm.BotConfig) (*mm.DryRunReport, error)
+	RecommendedAllocation(cfg *mm.BotConfig) (*mm.AllocationAdvice, error)
+	BenchmarkReport(startTime int64, mkt *mm.MarketWithHost) (*mm.BenchmarkReport, error)
 }
 
 // genCertPair generates a key/cert pair to the paths provided.
@@ -240,6 +265,9 @@ type Config struct {
 	// and execution of html templates on each request.
 	NoEmbed  bool
 	HttpProf bool
+	// GuestPass, if set, enables guest mode, giving read-only access to public
+	// market data to anyone with the password.
+	GuestPass string
 }
 
 type valStamp struct {
@@ -266,6 +294,11 @@ type WebServer struct {
 	authMtx         sync.RWMutex
 	authTokens      map[string]bool
 	cachedPasswords map[string]*cachedPassword // cached passwords keyed by auth token
+	guestPass       []byte
+	guestTokens     map[string]time.Time // token -> expiration
+
+	guestFailsMtx sync.Mutex
+	guestFails    map[string]*failedGuestLogins // keyed by IP address
 
 	bondBufMtx sync.Mutex
 	bondBuf    map[uint32]valStamp
@@ -390,6 +423,9 @@ func New(cfg *Config) (*WebServer, error) {
 		wsServer:        websocket.New(cfg.Core, log.SubLogger("WS")),
 		authTokens:      make(map[string]bool),
 		cachedPasswords: make(map[string]*cachedPassword),
+		guestPass:       []byte(cfg.GuestPass),
+		guestTokens:     make(map[string]time.Time),
+		guestFails:      make(map[string]*failedGuestLogins),
 		bondBuf:         map[uint32]valStamp{},
 		useDEXBranding:  useDEXBranding,
 	}
@@ -457,6 +493,9 @@ func New(cfg *Config) (*WebServer, error) {
 				// The login handler requires init but not auth since
 				// it performs the auth.
 				webNoAuth.Get(loginRoute, s.handleLogin)
+				// The guest page shows the guest login form to anyone
+				// without a guest token if guest mode is enabled.
+				webNoAuth.Get(guestRoute, s.handleGuest)
 
 				// The rest of these handlers require both init and auth.
 				webNoAuth.Group(func(webAuth chi.Router) {
@@ -473,6 +512,7 @@ func New(cfg *Config) (*WebServer, error) {
 				webDC.With(orderIDCtx).Get("/order/{oid}", s.handleOrder)
 				webDC.Get(ordersRoute, s.handleOrders)
 				webDC.Get(exportOrderRoute, s.handleExportOrders)
+				webDC.Get(exportMatchesRoute, s.handleExportMatches)
 				webDC.Get(marketsRoute, s.handleMarkets)
 				webDC.Get(mmSettingsRoute, s.handleMMSettings)
 				webDC.Get(mmArchivesRoute, s.handleMMArchives)
@@ -501,14 +541,32 @@ func New(cfg *Config) (*WebServer, error) {
 			apiInit.Post("/adddex", s.apiAddDEX)
 			apiInit.Post("/discoveracct", s.apiDiscoverAccount)
 			apiInit.Post("/bondsfeebuffer", s.apiBondsFeeBuffer)
+			apiInit.Post("/guestlogin", s.apiGuestLogin)
+			apiInit.Route("/guest", func(guest chi.Router) {
+				guest.Use(s.rejectNonGuests)
+				guest.Get("/markets", s.apiGuestMarkets)
+				guest.Post("/book", s.apiGuestBook)
+				guest.Post("/indicators", s.apiIndicators)
+			})
 		})
 
 		r.Group(func(apiAuth chi.Router) {
 			apiAuth.Use(s.rejectUnauthed)
 			apiAuth.Get("/notes", s.apiNotes)
+			apiAuth.Post("/searchnotes", s.apiSearchNotes)
+			apiAuth.Get("/topicsettings", s.apiTopicSettings)
+			apiAuth.Post("/updatetopicsettings", s.apiUpdateTopicSettings)
+			apiAuth.Get("/notetemplates", s.apiNoteTemplates)
+			apiAuth.Post("/updatenotetemplate", s.apiUpdateNoteTemplate)
+			apiAuth.Get("/conftargets", s.apiConfTargets)
+			apiAuth.Post("/setconftarget", s.apiSetConfTarget)
+			apiAuth.Post("/setguestpassword", s.apiSetGuestPassword)
 			apiAuth.Post("/defaultwalletcfg", s.apiDefaultWalletCfg)
 			apiAuth.Post("/postbond", s.apiPostBond)
 			apiAuth.Post("/updatebondoptions", s.apiUpdateBondOptions)
+			apiAuth.Get("/bondplan", s.apiBondPlan)
+			apiAuth.Post("/applybondplan", s.apiApplyBondPlan)
+			apiAuth.Get("/tradinglimits", s.apiTradingLimits)
 			apiAuth.Post("/redeemprepaidbond", s.apiRedeemPrepaidBond)
 			apiAuth.Post("/newwallet", s.apiNewWallet)
 			apiAuth.Post("/openwallet", s.apiOpenWallet)
@@ -529,10 +587,12 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/togglewalletstatus", s.apiToggleWalletStatus)
 			apiAuth.Post("/orders", s.apiOrders)
 			apiAuth.Post("/order", s.apiOrder)
+			apiAuth.Post("/annotateorder", s.apiAnnotateOrder)
 			apiAuth.Post("/send", s.apiSend)
 			apiAuth.Post("/maxbuy", s.apiMaxBuy)
 			apiAuth.Post("/maxsell", s.apiMaxSell)
 			apiAuth.Post("/preorder", s.apiPreOrder)
+			apiAuth.Post("/indicators", s.apiIndicators)
 			apiAuth.Post("/exportaccount", s.apiAccountExport)
 			apiAuth.Post("/exportseed", s.apiExportSeed)
 			apiAuth.Post("/importaccount", s.apiAccountImport)
@@ -540,8 +600,11 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/accelerateorder", s.apiAccelerateOrder)
 			apiAuth.Post("/preaccelerate", s.apiPreAccelerate)
 			apiAuth.Post("/accelerationestimate", s.apiAccelerationEstimate)
+			apiAuth.Get("/troubledmatches", s.apiTroubledMatches)
+			apiAuth.Post("/recoverswap", s.apiRecoverSwap)
 			apiAuth.Post("/updatecert", s.apiUpdateCert)
 			apiAuth.Post("/updatedexhost", s.apiUpdateDEXHost)
+			apiAuth.Post("/updatedexalthosts", s.apiUpdateDEXAltHosts)
 			apiAuth.Post("/restorewalletinfo", s.apiRestoreWalletInfo)
 			apiAuth.Post("/toggleratesource", s.apiToggleRateSource)
 			apiAuth.Post("/validateaddress", s.apiValidateAddress)
@@ -553,6 +616,8 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/approvetoken", s.apiApproveToken)
 			apiAuth.Post("/unapprovetoken", s.apiUnapproveToken)
 			apiAuth.Post("/approvetokenfee", s.apiApproveTokenFee)
+			apiAuth.Post("/tokenallowances", s.apiTokenAllowances)
+			apiAuth.Post("/settokenallowance", s.apiSetTokenAllowance)
 			apiAuth.Post("/txhistory", s.apiTxHistory)
 			apiAuth.Post("/takeaction", s.apiTakeAction)
 			apiAuth.Post("/redeemgamecode", s.redeemGameCode)
@@ -570,6 +635,8 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/startmarketmakingbot", s.apiStartMarketMakingBot)
 			apiAuth.Post("/stopmarketmakingbot", s.apiStopMarketMakingBot)
 			apiAuth.Post("/updatebotconfig", s.apiUpdateBotConfig)
+			apiAuth.Post("/dryrunbotconfig", s.apiDryRunBotConfig)
+			apiAuth.Post("/recommendedallocation", s.apiRecommendedAllocation)
 			apiAuth.Post("/updatecexconfig", s.apiUpdateCEXConfig)
 			apiAuth.Post("/removebotconfig", s.apiRemoveBotConfig)
 			apiAuth.Get("/marketmakingstatus", s.apiMarketMakingStatus)
@@ -577,6 +644,7 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/cexbalance", s.apiCEXBalance)
 			apiAuth.Get("/archivedmmruns", s.apiArchivedRuns)
 			apiAuth.Post("/mmrunlogs", s.apiRunLogs)
+			apiAuth.Post("/mmbenchmark", s.apiBenchmarkReport)
 			apiAuth.Post("/cexbook", s.apiCEXBook)
 		})
 	})
@@ -629,6 +697,7 @@ func (s *WebServer) buildTemplates(lang string) error {
 	bb := "bodybuilder"
 	html := newTemplates(htmlDir, localeName).
 		addTemplate("login", bb, "forms").
+		addTemplate("guest", bb).
 		addTemplate("register", bb, "forms").
 		addTemplate("markets", bb, "forms").
 		addTemplate("wallets", bb, "forms").
@@ -699,6 +768,16 @@ func (s *WebServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	s.mux.Get("/ws", func(w http.ResponseWriter, r *http.Request) {
 		s.wsServer.HandleConnect(ctx, w, r)
 	})
+	s.mux.With(s.rejectNonGuests).Get("/guest/ws", func(w http.ResponseWriter, r *http.Request) {
+		// A guest's connection is closed when their guest token expires.
+		wsCtx := ctx
+		if exp, isGuest := s.guestExpiration(r); isGuest && !s.isAuthed(r) {
+			var cancel context.CancelFunc
+			wsCtx, cancel = context.WithCancel(ctx)
+			time.AfterFunc(time.Until(exp), cancel)
+		}
+		s.wsServer.HandleGuestConnect(wsCtx, w, r)
+	})
 
 	wg.Add(1)
 	go func() {
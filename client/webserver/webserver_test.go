@@ -13,6 +13,7 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -231,6 +232,10 @@ func (c *TCore) Logout() error { return c.logoutErr }
 
 func (c *TCore) Orders(*core.OrderFilter) ([]*core.Order, error) { return nil, nil }
 func (c *TCore) Order(oid dex.Bytes) (*core.Order, error)        { return nil, nil }
+func (c *TCore) AnnotateOrder(form *core.AnnotationForm) error   { return nil }
+func (c *TCore) MatchRecords(*core.OrderFilter) ([]*core.MatchRecord, error) {
+	return nil, nil
+}
 func (c *TCore) MaxBuy(host string, base, quote uint32, rate uint64) (*core.MaxOrderEstimate, error) {
 	return nil, nil
 }
@@ -240,6 +245,9 @@ func (c *TCore) MaxSell(host string, base, quote uint32) (*core.MaxOrderEstimate
 func (c *TCore) PreOrder(*core.TradeForm) (*core.OrderEstimate, error) {
 	return nil, nil
 }
+func (c *TCore) Indicators(form *core.IndicatorsForm) (*core.IndicatorSeries, error) {
+	return nil, nil
+}
 func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
 	return nil, nil, nil
 }
@@ -263,6 +271,21 @@ func (c *TCore) AccelerationEstimate(oidB dex.Bytes, newFeeRate uint64) (uint64,
 func (c *TCore) PreAccelerateOrder(oidB dex.Bytes) (*core.PreAccelerate, error) {
 	return nil, nil
 }
+func (c *TCore) TroubledMatches() ([]*core.TroubledMatch, error) {
+	return nil, nil
+}
+func (c *TCore) RecoverSwap(pw []byte, form *core.SwapRecoveryForm) error {
+	return nil
+}
+func (c *TCore) BondFundingPlan() (*core.BondFundingPlan, error) {
+	return nil, nil
+}
+func (c *TCore) ApplyBondFundingPlan(plan *core.BondFundingPlan) error {
+	return nil
+}
+func (c *TCore) TradingLimitStatuses() []*core.TradingLimitStatus {
+	return nil
+}
 func (c *TCore) RecoverWallet(uint32, []byte, bool) error {
 	return nil
 }
@@ -272,6 +295,9 @@ func (c *TCore) UpdateCert(string, []byte) error {
 func (c *TCore) UpdateDEXHost(string, string, []byte, any) (*core.Exchange, error) {
 	return nil, nil
 }
+func (c *TCore) UpdateDEXAltHosts(string, []string) error {
+	return nil
+}
 func (c *TCore) WalletRestorationInfo(pw []byte, assetID uint32) ([]*asset.WalletRestoration, error) {
 	return nil, nil
 }
@@ -290,6 +316,9 @@ func (c *TCore) RemoveWalletPeer(assetID uint32, address string) error {
 func (c *TCore) Notifications(n int) (notes, pokes []*db.Notification, _ error) {
 	return c.notes, []*db.Notification{}, c.notesErr
 }
+func (c *TCore) SearchNotifications(*db.NoteFilter) ([]*db.Notification, error) {
+	return c.notes, c.notesErr
+}
 func (c *TCore) ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConfirm func()) (string, error) {
 	return "", nil
 }
@@ -299,6 +328,12 @@ func (c *TCore) UnapproveToken(appPW []byte, assetID uint32, version uint32) (st
 func (c *TCore) ApproveTokenFee(assetID uint32, version uint32, approval bool) (uint64, error) {
 	return 0, nil
 }
+func (c *TCore) TokenAllowances(assetID uint32) ([]*asset.TokenAllowance, error) {
+	return nil, nil
+}
+func (c *TCore) SetTokenAllowance(appPW []byte, assetID, version uint32, allowance uint64) (string, error) {
+	return "", nil
+}
 func (c *TCore) StakeStatus(assetID uint32) (*asset.TicketStakingStatus, error) {
 	return nil, nil
 }
@@ -341,6 +376,18 @@ func (c *TCore) RedeemGeocode(appPW, code []byte, msg string) (dex.Bytes, uint64
 
 func (*TCore) SetLanguage(string) error { return nil }
 func (*TCore) Language() string         { return "en-US" }
+func (*TCore) TopicSettings() map[db.Topic]*db.TopicSettings {
+	return nil
+}
+func (*TCore) UpdateTopicSettings(db.Topic, *db.TopicSettings) error { return nil }
+func (*TCore) NoteTemplates() map[db.Topic]*db.NoteTemplate {
+	return nil
+}
+func (*TCore) UpdateNoteTemplate(db.Topic, *db.NoteTemplate) error { return nil }
+func (*TCore) ConfTargets() map[uint32]*db.ConfTarget {
+	return make(map[uint32]*db.ConfTarget)
+}
+func (*TCore) SetConfTarget(uint32, *db.ConfTarget) error { return nil }
 
 func (*TCore) TakeAction(assetID uint32, actionID string, actionB json.RawMessage) error { return nil }
 
@@ -1077,3 +1124,168 @@ func Test_prepareAddr(t *testing.T) {
 		}
 	}
 }
+
+func TestGuestMode(t *testing.T) {
+	writer := new(TWriter)
+	var body any
+	reader := new(TReader)
+	s, _, shutdown := newTServer(t, false)
+	defer shutdown()
+
+	ensure := func(want string) {
+		t.Helper()
+		ensureResponse(t, s.apiGuestLogin, want, reader, writer, body, nil)
+	}
+
+	body = &struct {
+		Pass string `json:"pass"`
+	}{"guestpass"}
+	ensure(`{"ok":false,"msg":"guest mode is not enabled"}`)
+
+	s.guestPass = []byte("guestpass")
+	ensure(`{"ok":true}`)
+	if len(s.guestTokens) != 1 {
+		t.Fatalf("expected 1 guest token, got %d", len(s.guestTokens))
+	}
+	var guestToken string
+	for token := range s.guestTokens {
+		guestToken = token
+	}
+
+	body = &struct {
+		Pass string `json:"pass"`
+	}{"wrong"}
+	ensure(`{"ok":false,"msg":"incorrect guest password"}`)
+
+	var reached bool
+	guestOnly := s.rejectNonGuests(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		reached = true
+	}))
+	checkAccess := func(cookies map[string]string, wantAccess bool) {
+		t.Helper()
+		reached = false
+		req, _ := http.NewRequest("GET", "/", nil)
+		for name, value := range cookies {
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
+		}
+		guestOnly.ServeHTTP(writer, req)
+		writer.b = nil
+		if reached != wantAccess {
+			t.Fatalf("wanted access = %t, got %t", wantAccess, reached)
+		}
+	}
+	checkAccess(nil, false)
+	checkAccess(map[string]string{guestCK: "abc"}, false)
+	checkAccess(map[string]string{guestCK: guestToken}, true)
+	// A guest token is not an auth token.
+	checkAccess(map[string]string{authCK: guestToken}, false)
+	// Logged in users can use the guest routes too.
+	checkAccess(map[string]string{authCK: s.authorize()}, true)
+
+	// Expired tokens are rejected.
+	s.guestTokens[guestToken] = time.Now().Add(-time.Second)
+	checkAccess(map[string]string{guestCK: guestToken}, false)
+
+	// Changing the guest password logs out all guests.
+	body = &struct {
+		Pass string `json:"pass"`
+	}{"guestpass"}
+	ensure(`{"ok":true}`)
+	for token := range s.guestTokens {
+		guestToken = token
+	}
+	checkAccess(map[string]string{guestCK: guestToken}, true)
+	s.SetGuestPassword("newpass")
+	checkAccess(map[string]string{guestCK: guestToken}, false)
+	ensure(`{"ok":false,"msg":"incorrect guest password"}`)
+	body = &struct {
+		Pass string `json:"pass"`
+	}{"newpass"}
+	ensure(`{"ok":true}`)
+
+	// Failed guest logins are rate limited per address. There have been two
+	// failures so far.
+	body = &struct {
+		Pass string `json:"pass"`
+	}{"wrong"}
+	for i := 2; i < guestLoginFailBurst; i++ {
+		ensure(`{"ok":false,"msg":"incorrect guest password"}`)
+	}
+	body = &struct {
+		Pass string `json:"pass"`
+	}{"newpass"}
+	ensure(`{"ok":false,"msg":"too many failed guest logins, try again later"}`)
+	if !s.guestLoginAllowed("127.0.0.2") {
+		t.Fatalf("guest login not allowed from a different address")
+	}
+
+	// Disabling guest mode logs out all guests.
+	s.guestFails = make(map[string]*failedGuestLogins)
+	ensure(`{"ok":true}`)
+	s.SetGuestPassword("")
+	if len(s.guestTokens) != 0 {
+		t.Fatalf("guest tokens not cleared when disabling guest mode")
+	}
+	ensure(`{"ok":false,"msg":"guest mode is not enabled"}`)
+
+	// The number of guest tokens is capped.
+	s.SetGuestPassword("newpass")
+	for i := 0; i < maxGuestTokens+10; i++ {
+		s.authorizeGuest()
+	}
+	if len(s.guestTokens) != maxGuestTokens {
+		t.Fatalf("expected %d guest tokens, got %d", maxGuestTokens, len(s.guestTokens))
+	}
+
+	// The user's orders are not shared with guests.
+	gx := newGuestExchange(&core.Exchange{
+		Host: "abc",
+		Markets: map[string]*core.Market{
+			"dcr_btc": {Name: "dcr_btc", Orders: []*core.Order{{}}},
+		},
+	})
+	b, _ := json.Marshal(gx)
+	if strings.Contains(string(b), "orders") {
+		t.Fatalf("guest exchange includes orders: %s", string(b))
+	}
+}
+
+func TestGuestPage(t *testing.T) {
+	s, _, shutdown := newTServer(t, false)
+	defer shutdown()
+
+	page := s.authMiddleware(http.HandlerFunc(s.handleGuest))
+	get := func(cookies map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		req, _ := http.NewRequest("GET", guestRoute, nil)
+		for name, value := range cookies {
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
+		}
+		w := httptest.NewRecorder()
+		page.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without guest mode, the page redirects to the login page.
+	w := get(nil)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != loginRoute {
+		t.Fatalf("expected a redirect to the login page, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	s.SetGuestPassword("guestpass")
+	w = get(nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `id="guestLoginForm"`) || strings.Contains(body, `id="marketSelect"`) {
+		t.Fatalf("expected the guest login form without the markets")
+	}
+
+	token, _ := s.authorizeGuest()
+	for _, cookies := range []map[string]string{{guestCK: token}, {authCK: s.authorize()}} {
+		w = get(cookies)
+		if body := w.Body.String(); strings.Contains(body, `id="guestLoginForm"`) || !strings.Contains(body, `id="marketSelect"`) {
+			t.Fatalf("expected the markets without the guest login form")
+		}
+	}
+}
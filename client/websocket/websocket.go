@@ -42,15 +42,19 @@ type bookFeed struct {
 type wsClient struct {
 	*ws.WSLink
 	cid int32
+	// guest clients can only subscribe to public market data, and do not
+	// receive notifications.
+	guest bool
 
 	feedMtx sync.RWMutex
 	feed    *bookFeed
 }
 
-func newWSClient(addr string, conn ws.Connection, hndlr func(msg *msgjson.Message) *msgjson.Error, logger dex.Logger) *wsClient {
+func newWSClient(addr string, conn ws.Connection, hndlr func(msg *msgjson.Message) *msgjson.Error, logger dex.Logger, guest bool) *wsClient {
 	return &wsClient{
 		WSLink: ws.NewWSLink(addr, conn, pingPeriod, hndlr, logger),
 		cid:    atomic.AddInt32(&cidCounter, 1),
+		guest:  guest,
 	}
 }
 
@@ -104,12 +108,34 @@ func (s *Server) Shutdown() {
 	s.wg.Wait()
 }
 
+// DisconnectGuests disconnects all the guest clients, e.g. when the guest
+// password is changed.
+func (s *Server) DisconnectGuests() {
+	s.clientsMtx.RLock()
+	defer s.clientsMtx.RUnlock()
+	for _, cl := range s.clients {
+		if cl.guest {
+			cl.Disconnect()
+		}
+	}
+}
+
 // HandleConnect handles the websocket connection request, creating a
 // ws.Connection and a connect thread. Since the http.Request's Context is
 // canceled after ServerHTTP returns, a separate context must be provided to be
 // able to cancel the hijacked connection handler at a later time since this
 // function is not blocking.
 func (s *Server) HandleConnect(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	s.handleConnect(ctx, w, r, false)
+}
+
+// HandleGuestConnect is like HandleConnect, but the client can only subscribe
+// to public market data with the guestRoutes, and is not sent notifications.
+func (s *Server) HandleGuestConnect(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	s.handleConnect(ctx, w, r, true)
+}
+
+func (s *Server) handleConnect(ctx context.Context, w http.ResponseWriter, r *http.Request, guest bool) {
 	wsConn, err := ws.NewConnection(w, r, pongWait)
 	if err != nil {
 		s.log.Errorf("ws connection error: %v", err)
@@ -123,22 +149,22 @@ func (s *Server) HandleConnect(ctx context.Context, w http.ResponseWriter, r *ht
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		s.connect(ctx, wsConn, r.RemoteAddr)
+		s.connect(ctx, wsConn, r.RemoteAddr, guest)
 	}()
 }
 
 // connect handles a new websocket client by creating a new wsClient, starting
 // it, and blocking until the connection closes. This method should be
 // run as a goroutine.
-func (s *Server) connect(ctx context.Context, conn ws.Connection, addr string) {
-	s.log.Debugf("New websocket client %s", addr)
+func (s *Server) connect(ctx context.Context, conn ws.Connection, addr string, guest bool) {
+	s.log.Debugf("New websocket client %s (guest = %v)", addr, guest)
 	// Create a new websocket client to handle the new websocket connection
 	// and wait for it to shut down.  Once it has shutdown (and hence
 	// disconnected), remove it.
 	var cl *wsClient
 	cl = newWSClient(addr, conn, func(msg *msgjson.Message) *msgjson.Error {
 		return s.handleMessage(cl, msg)
-	}, s.log.SubLogger(addr))
+	}, s.log.SubLogger(addr), guest)
 
 	// Lock the clients map before starting the connection listening so that
 	// synchronized map accesses are guaranteed to reflect this connection.
@@ -182,6 +208,9 @@ func (s *Server) Notify(route string, payload any) {
 	s.clientsMtx.RLock()
 	defer s.clientsMtx.RUnlock()
 	for _, cl := range s.clients {
+		if cl.guest {
+			continue
+		}
 		if err = cl.Send(msg); err != nil {
 			s.log.Warnf("Failed to send %v notification to client %v at %v: %v",
 				msg.Route, cl.cid, cl.Addr(), err)
@@ -198,6 +227,9 @@ func (s *Server) handleMessage(conn *wsClient, msg *msgjson.Message) *msgjson.Er
 		if !found {
 			return msgjson.NewError(msgjson.UnknownMessageType, "unknown route %q", msg.Route)
 		}
+		if conn.guest && !guestRoutes[msg.Route] {
+			return msgjson.NewError(msgjson.UnauthorizedConnection, "route %q not available to guests", msg.Route)
+		}
 		return handler(s, conn, msg)
 	}
 	// Web server doesn't send requests, only responses and notifications, so
@@ -217,6 +249,13 @@ var wsHandlers = map[string]wsHandler{
 	"acknotes":    wsAckNotes,
 }
 
+// guestRoutes are the wsHandlers routes that guest clients may use.
+var guestRoutes = map[string]bool{
+	"loadmarket":  true,
+	"loadcandles": true,
+	"unmarket":    true,
+}
+
 // marketLoad is sent by websocket clients to subscribe to a market and request
 // the order book.
 type marketLoad struct {
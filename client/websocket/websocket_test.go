@@ -119,7 +119,7 @@ func newLink() *tLink {
 		close:     make(chan struct{}, 1),
 	}
 	ipk := dex.IPKey{16, 16, 120, 120 /* ipv6 1010:7878:: */}
-	cl := newWSClient(ipk.String(), conn, func(*msgjson.Message) *msgjson.Error { return nil }, dex.StdOutLogger("ws_TEST", dex.LevelTrace))
+	cl := newWSClient(ipk.String(), conn, func(*msgjson.Message) *msgjson.Error { return nil }, dex.StdOutLogger("ws_TEST", dex.LevelTrace), false)
 	return &tLink{
 		cl:   cl,
 		conn: conn,
@@ -275,7 +275,7 @@ func TestClientMap(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		ipk := dex.IPKey{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255 /* ipv4 */, 127, 0, 0, 1}
-		srv.connect(ctx, conn, ipk.String())
+		srv.connect(ctx, conn, ipk.String(), false)
 		wg.Done()
 	}()
 
@@ -302,3 +302,31 @@ func TestClientMap(t *testing.T) {
 		t.Fatal("connection not closed on server shutdown")
 	}
 }
+
+func TestGuestRoutes(t *testing.T) {
+	srv, tCore := newTServer()
+
+	link := newLink()
+	link.cl.guest = true
+	linkWg, err := link.cl.Connect(tCtx)
+	if err != nil {
+		t.Fatalf("WSLink Start: %v", err)
+	}
+	defer func() {
+		link.cl.shutDownFeed()
+		link.cl.Disconnect()
+		linkWg.Wait()
+	}()
+
+	ackReq, _ := msgjson.NewRequest(1, "acknotes", []dex.Bytes{})
+	msgErr := srv.handleMessage(link.cl, ackReq)
+	if msgErr == nil || msgErr.Code != msgjson.UnauthorizedConnection {
+		t.Fatalf("expected an unauthorized error for a guest 'acknotes' request, got %v", msgErr)
+	}
+
+	tCore.syncFeed = &tBookFeed{}
+	loadReq, _ := msgjson.NewRequest(2, "loadmarket", &marketLoad{Host: "abc", Base: 1, Quote: 2})
+	if msgErr = srv.handleMessage(link.cl, loadReq); msgErr != nil {
+		t.Fatalf("guest 'loadmarket' error: %d: %s", msgErr.Code, msgErr.Message)
+	}
+}
@@ -0,0 +1,159 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package candles
+
+import (
+	"math"
+)
+
+// Indicator series are computed from candle end rates and are expressed in the
+// same units as the candle rates. A point is only reported for a candle once
+// there are enough preceding candles to compute it, so a series with period n
+// has n-1 fewer points than there are candles.
+
+// SeriesPoint is a value of an indicator series at the end of a candle.
+type SeriesPoint struct {
+	EndStamp uint64  `json:"endStamp"`
+	Value    float64 `json:"value"`
+}
+
+// BollingerPoint is a point of a Bollinger band series at the end of a candle.
+type BollingerPoint struct {
+	EndStamp uint64  `json:"endStamp"`
+	Middle   float64 `json:"middle"`
+	Upper    float64 `json:"upper"`
+	Lower    float64 `json:"lower"`
+}
+
+// VolumeBin is the matched volume traded within a rate range.
+type VolumeBin struct {
+	LowRate  float64 `json:"lowRate"`
+	HighRate float64 `json:"highRate"`
+	Volume   float64 `json:"volume"`
+}
+
+// SMA computes the simple moving average of the candle end rates over period
+// candles.
+func SMA(cs []Candle, period int) []SeriesPoint {
+	if period <= 0 || len(cs) < period {
+		return []SeriesPoint{}
+	}
+	pts := make([]SeriesPoint, 0, len(cs)-period+1)
+	var sum float64
+	for i := range cs {
+		sum += float64(cs[i].EndRate)
+		if i >= period {
+			sum -= float64(cs[i-period].EndRate)
+		}
+		if i >= period-1 {
+			pts = append(pts, SeriesPoint{
+				EndStamp: cs[i].EndStamp,
+				Value:    sum / float64(period),
+			})
+		}
+	}
+	return pts
+}
+
+// EMA computes the exponential moving average of the candle end rates with a
+// smoothing factor of 2 / (period + 1). The series is seeded with the simple
+// average of the first period candles.
+func EMA(cs []Candle, period int) []SeriesPoint {
+	if period <= 0 || len(cs) < period {
+		return []SeriesPoint{}
+	}
+	pts := make([]SeriesPoint, 0, len(cs)-period+1)
+	k := 2 / float64(period+1)
+	var ema float64
+	for i := range cs {
+		r := float64(cs[i].EndRate)
+		switch {
+		case i < period-1:
+			ema += r
+			continue
+		case i == period-1:
+			ema = (ema + r) / float64(period)
+		default:
+			ema = r*k + ema*(1-k)
+		}
+		pts = append(pts, SeriesPoint{
+			EndStamp: cs[i].EndStamp,
+			Value:    ema,
+		})
+	}
+	return pts
+}
+
+// BollingerBands computes the simple moving average of the candle end rates
+// over period candles, with upper and lower bands stdDevs population standard
+// deviations away from the average.
+func BollingerBands(cs []Candle, period int, stdDevs float64) []BollingerPoint {
+	sma := SMA(cs, period)
+	pts := make([]BollingerPoint, 0, len(sma))
+	for i, pt := range sma {
+		var sumSq float64
+		for _, c := range cs[i : i+period] {
+			d := float64(c.EndRate) - pt.Value
+			sumSq += d * d
+		}
+		band := stdDevs * math.Sqrt(sumSq/float64(period))
+		pts = append(pts, BollingerPoint{
+			EndStamp: pt.EndStamp,
+			Middle:   pt.Value,
+			Upper:    pt.Value + band,
+			Lower:    pt.Value - band,
+		})
+	}
+	return pts
+}
+
+// VolumeProfile divides the range between the lowest and highest candle rates
+// into nBins equal bins and distributes each candle's match volume among the
+// bins its rate range overlaps, in proportion to the overlap.
+func VolumeProfile(cs []Candle, nBins int) []VolumeBin {
+	if nBins <= 0 || len(cs) == 0 {
+		return []VolumeBin{}
+	}
+	low, high := math.MaxFloat64, 0.0
+	for i := range cs {
+		low = math.Min(low, float64(cs[i].LowRate))
+		high = math.Max(high, float64(cs[i].HighRate))
+	}
+	if high <= low {
+		// Every candle traded at a single rate.
+		var vol float64
+		for i := range cs {
+			vol += float64(cs[i].MatchVolume)
+		}
+		return []VolumeBin{{LowRate: low, HighRate: high, Volume: vol}}
+	}
+	binWidth := (high - low) / float64(nBins)
+	bins := make([]VolumeBin, nBins)
+	for i := range bins {
+		bins[i].LowRate = low + float64(i)*binWidth
+		bins[i].HighRate = low + float64(i+1)*binWidth
+	}
+	binIndex := func(r float64) int {
+		return min(int((r-low)/binWidth), nBins-1)
+	}
+	for i := range cs {
+		c := &cs[i]
+		vol := float64(c.MatchVolume)
+		if vol == 0 {
+			continue
+		}
+		cLow, cHigh := float64(c.LowRate), float64(c.HighRate)
+		if cHigh <= cLow {
+			bins[binIndex(cLow)].Volume += vol
+			continue
+		}
+		for j := binIndex(cLow); j <= binIndex(cHigh); j++ {
+			overlap := math.Min(cHigh, bins[j].HighRate) - math.Max(cLow, bins[j].LowRate)
+			if overlap > 0 {
+				bins[j].Volume += vol * overlap / (cHigh - cLow)
+			}
+		}
+	}
+	return bins
+}
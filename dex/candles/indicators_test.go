@@ -0,0 +1,89 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package candles
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIndicators(t *testing.T) {
+	endRates := []uint64{10, 20, 30, 40, 50}
+	cs := make([]Candle, len(endRates))
+	for i, r := range endRates {
+		cs[i] = Candle{
+			EndStamp: uint64(i+1) * fiveMins,
+			EndRate:  r,
+			LowRate:  r - 5,
+			HighRate: r + 5,
+			// 10 per unit of rate range.
+			MatchVolume: 100,
+		}
+	}
+
+	checkSeries := func(name string, pts []SeriesPoint, exp []float64) {
+		t.Helper()
+		if len(pts) != len(exp) {
+			t.Fatalf("%s: wanted %d points, got %d", name, len(exp), len(pts))
+		}
+		for i, pt := range pts {
+			if math.Abs(pt.Value-exp[i]) > 1e-9 {
+				t.Fatalf("%s: wrong value at index %d. wanted %f, got %f", name, i, exp[i], pt.Value)
+			}
+		}
+		if len(pts) > 0 && pts[len(pts)-1].EndStamp != cs[len(cs)-1].EndStamp {
+			t.Fatalf("%s: last point not aligned with last candle", name)
+		}
+	}
+
+	checkSeries("SMA", SMA(cs, 3), []float64{20, 30, 40})
+	checkSeries("SMA too long", SMA(cs, 6), nil)
+	checkSeries("SMA zero period", SMA(cs, 0), nil)
+	// k = 0.5, seeded with 20.
+	checkSeries("EMA", EMA(cs, 3), []float64{20, 30, 40})
+	checkSeries("EMA period 1", EMA(cs, 1), []float64{10, 20, 30, 40, 50})
+	cs[4].EndRate = 90
+	checkSeries("EMA jump", EMA(cs, 3), []float64{20, 30, 60})
+	cs[4].EndRate = 50
+
+	bb := BollingerBands(cs, 3, 2)
+	if len(bb) != 3 {
+		t.Fatalf("wanted 3 Bollinger points, got %d", len(bb))
+	}
+	// Population std dev of 10, 20, 30 is sqrt(200/3).
+	band := 2 * math.Sqrt(200./3)
+	if math.Abs(bb[0].Middle-20) > 1e-9 || math.Abs(bb[0].Upper-(20+band)) > 1e-9 || math.Abs(bb[0].Lower-(20-band)) > 1e-9 {
+		t.Fatalf("wrong Bollinger point %+v", bb[0])
+	}
+
+	// Range is 5 to 55. With 5 bins of width 10, the bins are [5, 15),
+	// [15, 25), ... Each candle spans exactly one bin.
+	vp := VolumeProfile(cs, 5)
+	if len(vp) != 5 {
+		t.Fatalf("wanted 5 volume bins, got %d", len(vp))
+	}
+	for i, bin := range vp {
+		if math.Abs(bin.Volume-100) > 1e-9 {
+			t.Fatalf("wrong volume %f in bin %d", bin.Volume, i)
+		}
+	}
+	// With 10 bins, each candle's volume is split between two bins.
+	vp = VolumeProfile(cs, 10)
+	var total float64
+	for i, bin := range vp {
+		if math.Abs(bin.Volume-50) > 1e-9 {
+			t.Fatalf("wrong volume %f in split bin %d", bin.Volume, i)
+		}
+		total += bin.Volume
+	}
+	if math.Abs(total-500) > 1e-9 {
+		t.Fatalf("volume not conserved. wanted 500, got %f", total)
+	}
+	// Single rate.
+	flat := []Candle{{EndRate: 10, LowRate: 10, HighRate: 10, MatchVolume: 5}, {EndRate: 10, LowRate: 10, HighRate: 10, MatchVolume: 7}}
+	vp = VolumeProfile(flat, 10)
+	if len(vp) != 1 || vp[0].Volume != 12 {
+		t.Fatalf("wrong volume profile for single rate %+v", vp)
+	}
+}
@@ -4,12 +4,14 @@
 package dex
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/decred/slog"
@@ -49,6 +51,32 @@ type LoggerMaker struct {
 	*slog.Backend
 	DefaultLevel slog.Level
 	Levels       map[string]slog.Level
+
+	// updates is shared with the loggers created by the LoggerMaker, so that
+	// UpdateLevels can change their levels.
+	updates *levelUpdates
+}
+
+// levelUpdates holds the log levels set by LoggerMaker.UpdateLevels. Loggers
+// apply the levels the next time they are used after gen changes.
+type levelUpdates struct {
+	gen          atomic.Uint32
+	mtx          sync.RWMutex
+	defaultLevel slog.Level
+	levels       map[string]slog.Level
+}
+
+// level is the updated level for the logger, which is the level of the nearest
+// of the logger and its parents that is in the levels map, else the default.
+func (u *levelUpdates) level(lggr *logger) slog.Level {
+	u.mtx.RLock()
+	defer u.mtx.RUnlock()
+	for l := lggr; l != nil; l = l.parent {
+		if lvl, ok := u.levels[l.subsystem]; ok {
+			return lvl
+		}
+	}
+	return u.defaultLevel
 }
 
 // logger contains the slog.Logger and fields needed to spawn subloggers. It
@@ -60,6 +88,13 @@ type logger struct {
 	levels  map[string]slog.Level
 	backend *slog.Backend
 
+	// updates, subsystem, parent, and gen are only set for loggers created by
+	// a LoggerMaker, and their subloggers.
+	updates   *levelUpdates
+	subsystem string
+	parent    *logger
+	gen       atomic.Uint32
+
 	meterMtx sync.Mutex
 	meters   map[string]time.Time
 }
@@ -87,15 +122,99 @@ func (lggr *logger) newLoggerWithBackend(backend *slog.Backend, name string) *lo
 	combinedName := fmt.Sprintf("%s[%s]", lggr.name, name)
 	newLggr := backend.Logger(combinedName)
 	newLggr.SetLevel(level)
+	// If the levels have been updated, the update is applied when the new
+	// logger is first used.
 	return &logger{
-		Logger:  newLggr,
-		name:    combinedName,
-		level:   level,
-		levels:  lggr.levels,
-		backend: backend,
+		Logger:    newLggr,
+		name:      combinedName,
+		level:     level,
+		levels:    lggr.levels,
+		backend:   backend,
+		updates:   lggr.updates,
+		subsystem: name,
+		parent:    lggr,
+	}
+}
+
+// applyUpdates sets the logger's level if the levels have been updated since
+// it was last set.
+func (lggr *logger) applyUpdates() {
+	if lggr.updates == nil {
+		return
+	}
+	gen := lggr.updates.gen.Load()
+	if lggr.gen.Swap(gen) != gen {
+		lggr.Logger.SetLevel(lggr.updates.level(lggr))
 	}
 }
 
+// The slog.Logger methods are wrapped to apply level updates.
+
+func (lggr *logger) Tracef(format string, params ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Tracef(format, params...)
+}
+
+func (lggr *logger) Debugf(format string, params ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Debugf(format, params...)
+}
+
+func (lggr *logger) Infof(format string, params ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Infof(format, params...)
+}
+
+func (lggr *logger) Warnf(format string, params ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Warnf(format, params...)
+}
+
+func (lggr *logger) Errorf(format string, params ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Errorf(format, params...)
+}
+
+func (lggr *logger) Criticalf(format string, params ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Criticalf(format, params...)
+}
+
+func (lggr *logger) Trace(v ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Trace(v...)
+}
+
+func (lggr *logger) Debug(v ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Debug(v...)
+}
+
+func (lggr *logger) Info(v ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Info(v...)
+}
+
+func (lggr *logger) Warn(v ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Warn(v...)
+}
+
+func (lggr *logger) Error(v ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Error(v...)
+}
+
+func (lggr *logger) Critical(v ...any) {
+	lggr.applyUpdates()
+	lggr.Logger.Critical(v...)
+}
+
+func (lggr *logger) Level() slog.Level {
+	lggr.applyUpdates()
+	return lggr.Logger.Level()
+}
+
 // Meter enforces a time delay on logging. The first call to a metered logger
 // always logs. Subsequent calls for the same callerID are ignored until the
 // delay is surpassed.
@@ -175,6 +294,7 @@ func NewLoggerMaker(writer io.Writer, debugLevel string, utc ...bool) (*LoggerMa
 		Backend:      slog.NewBackend(writer, opts...),
 		Levels:       make(map[string]slog.Level),
 		DefaultLevel: DefaultLogLevel,
+		updates:      new(levelUpdates),
 	}
 
 	err := lm.SetLevels(debugLevel)
@@ -266,11 +386,13 @@ func (lm *LoggerMaker) NewLogger(name string, level ...slog.Level) Logger {
 	lggr := lm.Backend.Logger(name)
 	lggr.SetLevel(lvl)
 	return &logger{
-		Logger:  lggr,
-		name:    name,
-		level:   lvl,
-		levels:  lm.Levels,
-		backend: lm.Backend,
+		Logger:    lggr,
+		name:      name,
+		level:     lvl,
+		levels:    lm.Levels,
+		backend:   lm.Backend,
+		updates:   lm.updates,
+		subsystem: name,
 	}
 }
 
@@ -282,14 +404,43 @@ func (lm *LoggerMaker) Logger(name string) Logger {
 	lvl := lm.bestLevel(name)
 	lggr.SetLevel(lvl)
 	return &logger{
-		Logger:  lggr,
-		name:    name,
-		level:   lvl,
-		levels:  lm.Levels,
-		backend: lm.Backend,
+		Logger:    lggr,
+		name:      name,
+		level:     lvl,
+		levels:    lm.Levels,
+		backend:   lm.Backend,
+		updates:   lm.updates,
+		subsystem: name,
 	}
 }
 
+// UpdateLevels changes the levels of the loggers that have been created by the
+// LoggerMaker and their subloggers, as well as loggers created later. The
+// debugLevel string is the same as for SetLevels, and is applied to the levels
+// set by the last update, or to the DefaultLevel and Levels if there has been
+// no update. A logger's new level is the level of its subsystem in the levels
+// map, or that of its nearest parent, else the default level. Levels that were
+// given to NewLogger are not preserved.
+func (lm *LoggerMaker) UpdateLevels(debugLevel string) error {
+	u := lm.updates
+	if u == nil {
+		return errors.New("LoggerMaker was not created with NewLoggerMaker")
+	}
+	u.mtx.Lock()
+	lvls := &LoggerMaker{DefaultLevel: u.defaultLevel, Levels: u.levels}
+	if u.gen.Load() == 0 {
+		lvls.DefaultLevel, lvls.Levels = lm.DefaultLevel, lm.Levels
+	}
+	if err := lvls.SetLevels(debugLevel); err != nil {
+		u.mtx.Unlock()
+		return err
+	}
+	u.defaultLevel, u.levels = lvls.DefaultLevel, lvls.Levels
+	u.gen.Add(1)
+	u.mtx.Unlock()
+	return nil
+}
+
 // bestLevel takes a hierarchical list of logger names, least important to most
 // important, and returns the best log level found in the Levels map, else the
 // default.
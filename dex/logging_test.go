@@ -0,0 +1,67 @@
+package dex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/decred/slog"
+)
+
+func TestLoggerMakerUpdateLevels(t *testing.T) {
+	var b bytes.Buffer
+	lm, err := NewLoggerMaker(&b, "info")
+	if err != nil {
+		t.Fatalf("NewLoggerMaker error: %v", err)
+	}
+	lm.SetLevelsFromMap(map[string]slog.Level{"QUIET": LevelError})
+	core := lm.Logger("CORE")
+	wallet := core.SubLogger("DCR")
+	quiet := lm.Logger("QUIET")
+
+	checkLevels := func(tag string, wantCore, wantWallet, wantQuiet slog.Level) {
+		t.Helper()
+		if lvl := core.Level(); lvl != wantCore {
+			t.Fatalf("%s: wrong core level %s", tag, lvl)
+		}
+		if lvl := wallet.Level(); lvl != wantWallet {
+			t.Fatalf("%s: wrong wallet level %s", tag, lvl)
+		}
+		if lvl := quiet.Level(); lvl != wantQuiet {
+			t.Fatalf("%s: wrong quiet level %s", tag, lvl)
+		}
+	}
+	checkLevels("initial", LevelInfo, LevelInfo, LevelError)
+
+	core.Debugf("hidden")
+	if b.Len() != 0 {
+		t.Fatalf("debug message logged at info level")
+	}
+
+	// A single level changes the default, and subsystem levels are kept.
+	if err := lm.UpdateLevels("debug"); err != nil {
+		t.Fatalf("UpdateLevels error: %v", err)
+	}
+	checkLevels("debug", LevelDebug, LevelDebug, LevelError)
+	core.Debugf("shown")
+	if !strings.Contains(b.String(), "shown") {
+		t.Fatalf("debug message not logged after update")
+	}
+
+	// Subsystem levels apply to subloggers, including new ones.
+	if err := lm.UpdateLevels("CORE=trace,QUIET=info"); err != nil {
+		t.Fatalf("UpdateLevels error: %v", err)
+	}
+	checkLevels("subsystems", LevelTrace, LevelTrace, LevelInfo)
+	if lvl := core.SubLogger("BTC").Level(); lvl != LevelTrace {
+		t.Fatalf("wrong level for new sublogger %s", lvl)
+	}
+	if lvl := lm.Logger("NEW").Level(); lvl != LevelDebug {
+		t.Fatalf("wrong level for new logger %s", lvl)
+	}
+
+	if err := lm.UpdateLevels("loud"); err == nil {
+		t.Fatalf("no error for invalid level")
+	}
+	checkLevels("invalid", LevelTrace, LevelTrace, LevelInfo)
+}
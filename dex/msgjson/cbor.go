@@ -0,0 +1,991 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package msgjson
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"decred.org/dcrdex/dex"
+)
+
+// This file implements the subset of CBOR (RFC 8949) needed to encode the
+// types in this package. Go values are mapped to CBOR the same way
+// encoding/json maps them to JSON, using the same struct tags, with the
+// notable difference that byte slices and byte arrays (e.g. dex.Bytes and
+// order.OrderID) are encoded as CBOR byte strings rather than hex strings.
+// Indefinite-length items are not produced and are not accepted.
+
+// CBOR major types, shifted into the high 3 bits of the initial byte.
+const (
+	cborMajorUint   byte = 0 << 5
+	cborMajorNegInt byte = 1 << 5
+	cborMajorBytes  byte = 2 << 5
+	cborMajorText   byte = 3 << 5
+	cborMajorArray  byte = 4 << 5
+	cborMajorMap    byte = 5 << 5
+	cborMajorTag    byte = 6 << 5
+	cborMajorSimple byte = 7 << 5
+
+	cborFalse     byte = cborMajorSimple | 20
+	cborTrue      byte = cborMajorSimple | 21
+	cborNull      byte = cborMajorSimple | 22
+	cborUndefined byte = cborMajorSimple | 23
+	cborFloat16   byte = cborMajorSimple | 25
+	cborFloat32   byte = cborMajorSimple | 26
+	cborFloat64   byte = cborMajorSimple | 27
+
+	// cborMaxDepth limits nesting when decoding untrusted input.
+	cborMaxDepth = 128
+)
+
+var (
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// MarshalCBOR encodes the value as CBOR.
+func MarshalCBOR(v any) ([]byte, error) {
+	e := &cborEncoder{buf: make([]byte, 0, 128)}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// UnmarshalCBOR decodes a single CBOR data item into the value pointed to by v.
+// It is an error if there is trailing data after the item.
+func UnmarshalCBOR(b []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cbor: non-nil pointer required, got %T", v)
+	}
+	d := &cborDecoder{b: b}
+	if err := d.decode(rv.Elem()); err != nil {
+		return err
+	}
+	if d.off != len(b) {
+		return fmt.Errorf("cbor: %d bytes of trailing data", len(b)-d.off)
+	}
+	return nil
+}
+
+// cborToJSON converts a single CBOR data item into JSON. Byte strings are
+// converted to hex strings, the same as dex.Bytes.
+func cborToJSON(b []byte) ([]byte, error) {
+	var v any
+	if err := UnmarshalCBOR(b, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// jsonToCBOR converts JSON into a single CBOR data item. There is no type
+// information to go on, so hex strings remain text strings. Decoding a text
+// string into a byte slice or byte array is permitted for this reason.
+func jsonToCBOR(b []byte) ([]byte, error) {
+	e := &cborEncoder{buf: make([]byte, 0, len(b))}
+	if err := e.encodeJSON(b); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+type cborEncoder struct {
+	buf []byte
+}
+
+// head writes the initial byte(s) of a data item with the given major type and
+// argument.
+func (e *cborEncoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, major|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, major|25)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	case n <= math.MaxUint32:
+		e.buf = append(e.buf, major|26)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	default:
+		e.buf = append(e.buf, major|27)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, n)
+	}
+}
+
+func (e *cborEncoder) int(i int64) {
+	if i < 0 {
+		e.head(cborMajorNegInt, uint64(-1-i))
+		return
+	}
+	e.head(cborMajorUint, uint64(i))
+}
+
+func (e *cborEncoder) float(f float64) {
+	e.buf = append(e.buf, cborFloat64)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(f))
+}
+
+func (e *cborEncoder) text(s string) {
+	e.head(cborMajorText, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *cborEncoder) bytes(b []byte) {
+	e.head(cborMajorBytes, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *cborEncoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, cborNull)
+		return nil
+	}
+	t := v.Type()
+	switch {
+	case t == rawMessageType:
+		if v.Len() == 0 {
+			e.buf = append(e.buf, cborNull)
+			return nil
+		}
+		return e.encodeJSON(v.Bytes())
+	case isByteSlice(t):
+		e.bytes(v.Bytes())
+		return nil
+	case isByteArray(t):
+		e.head(cborMajorBytes, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			e.buf = append(e.buf, byte(v.Index(i).Uint()))
+		}
+		return nil
+	}
+
+	// Types that define their own JSON encoding are transcoded.
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface {
+		if t.Implements(jsonMarshalerType) {
+			return e.encodeMarshaler(v.Interface().(json.Marshaler))
+		}
+		if v.CanAddr() && reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			return e.encodeMarshaler(v.Addr().Interface().(json.Marshaler))
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, cborNull)
+			return nil
+		}
+		if t.Kind() == reflect.Pointer && t.Implements(jsonMarshalerType) && !isByteKind(t.Elem()) {
+			return e.encodeMarshaler(v.Interface().(json.Marshaler))
+		}
+		return e.encode(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, cborTrue)
+		} else {
+			e.buf = append(e.buf, cborFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.head(cborMajorUint, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.float(v.Float())
+	case reflect.String:
+		e.text(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf = append(e.buf, cborNull)
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		e.head(cborMajorArray, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return fmt.Errorf("cbor: unsupported type %s", t)
+	}
+	return nil
+}
+
+func (e *cborEncoder) encodeMarshaler(m json.Marshaler) error {
+	b, err := m.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return e.encodeJSON(b)
+}
+
+func (e *cborEncoder) encodeMap(v reflect.Value) error {
+	if v.IsNil() {
+		e.buf = append(e.buf, cborNull)
+		return nil
+	}
+	keyKind := v.Type().Key().Kind()
+	switch keyKind {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return fmt.Errorf("cbor: unsupported map key type %s", v.Type().Key())
+	}
+	keys := v.MapKeys()
+	// Sort the keys so that the encoding is deterministic.
+	sort.Slice(keys, func(i, j int) bool {
+		switch keyKind {
+		case reflect.String:
+			return keys[i].String() < keys[j].String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return keys[i].Int() < keys[j].Int()
+		default:
+			return keys[i].Uint() < keys[j].Uint()
+		}
+	})
+	e.head(cborMajorMap, uint64(len(keys)))
+	for _, k := range keys {
+		switch keyKind {
+		case reflect.String:
+			e.text(k.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			e.int(k.Int())
+		default:
+			e.head(cborMajorUint, k.Uint())
+		}
+		if err := e.encode(v.MapIndex(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *cborEncoder) encodeStruct(v reflect.Value) error {
+	fields := cachedFields(v.Type())
+	vals := make([]reflect.Value, 0, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		vals = append(vals, fv)
+		names = append(names, f.name)
+	}
+	e.head(cborMajorMap, uint64(len(vals)))
+	for i, fv := range vals {
+		e.text(names[i])
+		if err := e.encode(fv); err != nil {
+			return fmt.Errorf("cbor: field %q: %w", names[i], err)
+		}
+	}
+	return nil
+}
+
+// encodeJSON transcodes JSON to CBOR.
+func (e *cborEncoder) encodeJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	return e.encodeGeneric(v)
+}
+
+func (e *cborEncoder) encodeGeneric(v any) error {
+	switch vt := v.(type) {
+	case nil:
+		e.buf = append(e.buf, cborNull)
+	case bool:
+		if vt {
+			e.buf = append(e.buf, cborTrue)
+		} else {
+			e.buf = append(e.buf, cborFalse)
+		}
+	case json.Number:
+		if i, err := vt.Int64(); err == nil {
+			e.int(i)
+		} else if u, err := strconv.ParseUint(string(vt), 10, 64); err == nil {
+			e.head(cborMajorUint, u)
+		} else {
+			f, err := vt.Float64()
+			if err != nil {
+				return err
+			}
+			e.float(f)
+		}
+	case string:
+		e.text(vt)
+	case []any:
+		e.head(cborMajorArray, uint64(len(vt)))
+		for _, el := range vt {
+			if err := e.encodeGeneric(el); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(vt))
+		for k := range vt {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		e.head(cborMajorMap, uint64(len(keys)))
+		for _, k := range keys {
+			e.text(k)
+			if err := e.encodeGeneric(vt[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cbor: unexpected JSON type %T", v)
+	}
+	return nil
+}
+
+type cborDecoder struct {
+	b     []byte
+	off   int
+	depth int
+}
+
+var errCBORShort = errors.New("cbor: unexpected end of data")
+
+// head reads the initial byte(s) of the next data item, returning the major
+// type, the additional info, and the argument. For major type 7, the argument
+// is the raw bits of a float or the simple value.
+func (d *cborDecoder) head() (major, info byte, arg uint64, err error) {
+	if d.off >= len(d.b) {
+		return 0, 0, 0, errCBORShort
+	}
+	ib := d.b[d.off]
+	d.off++
+	major, info = ib&0xe0, ib&0x1f
+	var n int
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info == 24:
+		n = 1
+	case info == 25:
+		n = 2
+	case info == 26:
+		n = 4
+	case info == 27:
+		n = 8
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: unsupported additional info %d", info)
+	}
+	if len(d.b)-d.off < n {
+		return 0, 0, 0, errCBORShort
+	}
+	b := d.b[d.off : d.off+n]
+	d.off += n
+	switch n {
+	case 1:
+		arg = uint64(b[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(b))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(b))
+	default:
+		arg = binary.BigEndian.Uint64(b)
+	}
+	return major, info, arg, nil
+}
+
+// payload reads n bytes for a byte or text string.
+func (d *cborDecoder) payload(n uint64) ([]byte, error) {
+	if uint64(len(d.b)-d.off) < n {
+		return nil, errCBORShort
+	}
+	b := d.b[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+// skip advances past the next data item.
+func (d *cborDecoder) skip() error {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > cborMaxDepth {
+		return errors.New("cbor: maximum nesting depth exceeded")
+	}
+	major, _, arg, err := d.head()
+	if err != nil {
+		return err
+	}
+	switch major {
+	case cborMajorBytes, cborMajorText:
+		_, err = d.payload(arg)
+		return err
+	case cborMajorArray, cborMajorMap:
+		n := arg
+		if major == cborMajorMap {
+			if n > math.MaxUint64/2 {
+				return errCBORShort
+			}
+			n *= 2
+		}
+		for i := uint64(0); i < n; i++ {
+			if err := d.skip(); err != nil {
+				return err
+			}
+		}
+	case cborMajorTag:
+		return d.skip()
+	}
+	return nil
+}
+
+func (d *cborDecoder) typeError(major byte, t reflect.Type) error {
+	return fmt.Errorf("cbor: cannot decode major type %d into Go value of type %s", major>>5, t)
+}
+
+// floatFromBits interprets the argument of a major type 7 float item.
+func floatFromBits(info byte, bits uint64) float64 {
+	switch info {
+	case 25:
+		return float16ToFloat64(uint16(bits))
+	case 26:
+		return float64(math.Float32frombits(uint32(bits)))
+	default:
+		return math.Float64frombits(bits)
+	}
+}
+
+func float16ToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(mant+1024, exp-25)
+}
+
+func (d *cborDecoder) decode(v reflect.Value) error {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > cborMaxDepth {
+		return errors.New("cbor: maximum nesting depth exceeded")
+	}
+	if d.off >= len(d.b) {
+		return errCBORShort
+	}
+	start := d.off
+	t := v.Type()
+
+	if t == rawMessageType {
+		if err := d.skip(); err != nil {
+			return err
+		}
+		v.SetBytes(append(json.RawMessage(nil), d.b[start:d.off]...))
+		return nil
+	}
+
+	// null and undefined zero pointers, interfaces, maps and slices, and are
+	// otherwise ignored, the same as encoding/json.
+	if ib := d.b[d.off]; ib == cborNull || ib == cborUndefined {
+		d.off++
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return d.decode(v.Elem())
+	}
+
+	if !isByteKind(t) && v.CanAddr() && reflect.PointerTo(t).Implements(jsonUnmarshalType) {
+		if err := d.skip(); err != nil {
+			return err
+		}
+		b, err := cborToJSON(d.b[start:d.off])
+		if err != nil {
+			return err
+		}
+		return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(b)
+	}
+
+	if t.Kind() == reflect.Interface {
+		if t.NumMethod() != 0 {
+			return fmt.Errorf("cbor: cannot decode into non-empty interface %s", t)
+		}
+		gv, err := d.decodeGeneric()
+		if err != nil {
+			return err
+		}
+		if gv == nil {
+			v.Set(reflect.Zero(t))
+		} else {
+			v.Set(reflect.ValueOf(gv))
+		}
+		return nil
+	}
+
+	major, info, arg, err := d.head()
+	if err != nil {
+		return err
+	}
+	switch major {
+	case cborMajorUint:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if arg > math.MaxInt64 || v.OverflowInt(int64(arg)) {
+				return fmt.Errorf("cbor: %d overflows %s", arg, t)
+			}
+			v.SetInt(int64(arg))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if v.OverflowUint(arg) {
+				return fmt.Errorf("cbor: %d overflows %s", arg, t)
+			}
+			v.SetUint(arg)
+		case reflect.Float32, reflect.Float64:
+			v.SetFloat(float64(arg))
+		default:
+			return d.typeError(major, t)
+		}
+	case cborMajorNegInt:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if arg > math.MaxInt64 || v.OverflowInt(-1-int64(arg)) {
+				return fmt.Errorf("cbor: -1-%d overflows %s", arg, t)
+			}
+			v.SetInt(-1 - int64(arg))
+		case reflect.Float32, reflect.Float64:
+			v.SetFloat(-1 - float64(arg))
+		default:
+			return d.typeError(major, t)
+		}
+	case cborMajorBytes, cborMajorText:
+		b, err := d.payload(arg)
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.Kind() == reflect.String && major == cborMajorText:
+			v.SetString(string(b))
+		case isByteKind(t):
+			if major == cborMajorText {
+				if b, err = hex.DecodeString(string(b)); err != nil {
+					return fmt.Errorf("cbor: invalid hex string for %s: %w", t, err)
+				}
+			}
+			if t.Kind() == reflect.Slice {
+				v.SetBytes(append(make([]byte, 0, len(b)), b...))
+				return nil
+			}
+			if len(b) != v.Len() {
+				return fmt.Errorf("cbor: cannot decode %d bytes into %s", len(b), t)
+			}
+			reflect.Copy(v, reflect.ValueOf(b))
+		default:
+			return d.typeError(major, t)
+		}
+	case cborMajorArray:
+		return d.decodeArray(v, arg)
+	case cborMajorMap:
+		switch t.Kind() {
+		case reflect.Struct:
+			return d.decodeStruct(v, arg)
+		case reflect.Map:
+			return d.decodeMap(v, arg)
+		default:
+			return d.typeError(major, t)
+		}
+	case cborMajorTag:
+		return d.decode(v)
+	case cborMajorSimple:
+		switch {
+		case info == 20 || info == 21:
+			if t.Kind() != reflect.Bool {
+				return d.typeError(major, t)
+			}
+			v.SetBool(info == 21)
+		case info >= 25 && info <= 27:
+			if t.Kind() != reflect.Float32 && t.Kind() != reflect.Float64 {
+				return d.typeError(major, t)
+			}
+			v.SetFloat(floatFromBits(info, arg))
+		default:
+			return fmt.Errorf("cbor: unsupported simple value %d", arg)
+		}
+	}
+	return nil
+}
+
+func (d *cborDecoder) decodeArray(v reflect.Value, n uint64) error {
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Slice:
+		if n > uint64(len(d.b)-d.off) { // each element is at least one byte
+			return errCBORShort
+		}
+		s := reflect.MakeSlice(t, int(n), int(n))
+		for i := 0; i < int(n); i++ {
+			if err := d.decode(s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.Array:
+		for i := uint64(0); i < n; i++ {
+			if i >= uint64(v.Len()) {
+				if err := d.skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.decode(v.Index(int(i))); err != nil {
+				return err
+			}
+		}
+	default:
+		return d.typeError(cborMajorArray, t)
+	}
+	return nil
+}
+
+func (d *cborDecoder) decodeStruct(v reflect.Value, n uint64) error {
+	fields := cachedFields(v.Type())
+	for i := uint64(0); i < n; i++ {
+		var name string
+		if err := d.decode(reflect.ValueOf(&name).Elem()); err != nil {
+			return err
+		}
+		f := fields.lookup(name)
+		if f == nil {
+			if err := d.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		fv := fieldByIndexAlloc(v, f.index)
+		if err := d.decode(fv); err != nil {
+			return fmt.Errorf("cbor: field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (d *cborDecoder) decodeMap(v reflect.Value, n uint64) error {
+	t := v.Type()
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(t))
+	}
+	kt := t.Key()
+	for i := uint64(0); i < n; i++ {
+		k := reflect.New(kt).Elem()
+		switch kt.Kind() {
+		case reflect.String:
+			if err := d.decode(k); err != nil {
+				return err
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// Integer keys may arrive as text when transcoded from JSON.
+			if d.off < len(d.b) && d.b[d.off]&0xe0 == cborMajorText {
+				var s string
+				if err := d.decode(reflect.ValueOf(&s).Elem()); err != nil {
+					return err
+				}
+				if err := json.Unmarshal([]byte(s), k.Addr().Interface()); err != nil {
+					return fmt.Errorf("cbor: invalid map key %q for %s", s, kt)
+				}
+			} else if err := d.decode(k); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("cbor: unsupported map key type %s", kt)
+		}
+		el := reflect.New(t.Elem()).Elem()
+		if err := d.decode(el); err != nil {
+			return err
+		}
+		v.SetMapIndex(k, el)
+	}
+	return nil
+}
+
+// decodeGeneric decodes the next item into an untyped Go value. Integers are
+// returned as int64 or uint64, floats as float64, byte strings as dex.Bytes,
+// arrays as []any and maps as map[string]any.
+func (d *cborDecoder) decodeGeneric() (any, error) {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > cborMaxDepth {
+		return nil, errors.New("cbor: maximum nesting depth exceeded")
+	}
+	major, info, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborMajorUint:
+		if arg <= math.MaxInt64 {
+			return int64(arg), nil
+		}
+		return arg, nil
+	case cborMajorNegInt:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("cbor: -1-%d overflows int64", arg)
+		}
+		return -1 - int64(arg), nil
+	case cborMajorBytes:
+		b, err := d.payload(arg)
+		if err != nil {
+			return nil, err
+		}
+		return dex.Bytes(append(make([]byte, 0, len(b)), b...)), nil
+	case cborMajorText:
+		b, err := d.payload(arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case cborMajorArray:
+		if arg > uint64(len(d.b)-d.off) {
+			return nil, errCBORShort
+		}
+		s := make([]any, int(arg))
+		for i := range s {
+			if s[i], err = d.decodeGeneric(); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	case cborMajorMap:
+		if arg > uint64(len(d.b)-d.off) {
+			return nil, errCBORShort
+		}
+		m := make(map[string]any, int(arg))
+		for i := uint64(0); i < arg; i++ {
+			k, err := d.decodeGeneric()
+			if err != nil {
+				return nil, err
+			}
+			var ks string
+			switch kt := k.(type) {
+			case string:
+				ks = kt
+			case int64, uint64:
+				ks = fmt.Sprint(kt)
+			default:
+				return nil, fmt.Errorf("cbor: unsupported map key type %T", k)
+			}
+			if m[ks], err = d.decodeGeneric(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborMajorTag:
+		return d.decodeGeneric()
+	default: // cborMajorSimple
+		switch {
+		case info == 20:
+			return false, nil
+		case info == 21:
+			return true, nil
+		case info == 22 || info == 23:
+			return nil, nil
+		case info >= 25 && info <= 27:
+			return floatFromBits(info, arg), nil
+		}
+		return nil, fmt.Errorf("cbor: unsupported simple value %d", arg)
+	}
+}
+
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+func isByteArray(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8
+}
+
+func isByteKind(t reflect.Type) bool {
+	return isByteSlice(t) || isByteArray(t)
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// cborField describes a struct field, possibly promoted from an embedded
+// struct, and the name it is encoded with.
+type cborField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+type cborFields []*cborField
+
+// lookup finds the field for the name, preferring an exact match but falling
+// back to a case-insensitive match like encoding/json.
+func (fs cborFields) lookup(name string) *cborField {
+	for _, f := range fs {
+		if f.name == name {
+			return f
+		}
+	}
+	for _, f := range fs {
+		if strings.EqualFold(f.name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+var fieldCache sync.Map // reflect.Type -> cborFields
+
+func cachedFields(t reflect.Type) cborFields {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.(cborFields)
+	}
+	fs, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return fs.(cborFields)
+}
+
+// typeFields lists the encodable fields of the struct type using the
+// encoding/json struct tag conventions. Fields of untagged embedded structs
+// are promoted, with shallower fields taking precedence.
+func typeFields(t reflect.Type) cborFields {
+	type queued struct {
+		t     reflect.Type
+		index []int
+	}
+	var fields cborFields
+	seen := make(map[string]bool)
+	current := []queued{{t: t}}
+	visited := map[reflect.Type]bool{}
+	for len(current) > 0 {
+		var next []queued
+		levelNames := make(map[string]bool)
+		for _, q := range current {
+			if visited[q.t] {
+				continue
+			}
+			visited[q.t] = true
+			for i := 0; i < q.t.NumField(); i++ {
+				sf := q.t.Field(i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(append([]int(nil), q.index...), i)
+				if sf.Anonymous && name == "" {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, queued{t: ft, index: index})
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				if seen[name] {
+					continue
+				}
+				levelNames[name] = true
+				fields = append(fields, &cborField{
+					name:      name,
+					index:     index,
+					omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+				})
+			}
+		}
+		for name := range levelNames {
+			seen[name] = true
+		}
+		current = next
+	}
+	return fields
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but reports false instead
+// of panicking when passing through a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex, but allocates nil
+// embedded pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,216 @@
+package msgjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCBORRoundTrip(t *testing.T) {
+	epochNote := &EpochOrderNote{
+		BookOrderNote: BookOrderNote{
+			OrderNote: OrderNote{
+				Seq:      123,
+				MarketID: "dcr_btc",
+				OrderID:  randomBytes(32),
+			},
+			TradeNote: TradeNote{
+				Side:     1,
+				Quantity: 5e8,
+				Rate:     math.MaxUint64,
+				Time:     1585043380123,
+			},
+		},
+		Commit:    randomBytes(32),
+		OrderType: 1,
+		Epoch:     15850433,
+	}
+
+	cfg := &ConfigResult{
+		APIVersion:       1,
+		DEXPubKey:        randomBytes(33),
+		BinSizes:         []string{"24h", "1h"},
+		BondExpiry:       86400,
+		BondAssets:       map[string]*BondAsset{"dcr": {ID: 42, Confs: 2, Amt: 1e8}},
+		Markets:          []*Market{{Name: "dcr_btc", Base: 42, LotSize: 1e8, MarketBuyBuffer: 1.25}},
+		CancelMax:        0.8,
+		BroadcastTimeout: 60000,
+	}
+
+	batch := &BookUpdateBatch{
+		MarketID: "dcr_btc",
+		Updates: []*BookUpdate{
+			{Book: &epochNote.BookOrderNote},
+			{Unbook: &UnbookOrderNote{Seq: 124, MarketID: "dcr_btc", OrderID: randomBytes(32)}},
+			{UpdateRemaining: &UpdateRemainingNote{OrderNote: OrderNote{Seq: 125, MarketID: "dcr_btc", OrderID: randomBytes(32)}, Remaining: 1e8}},
+		},
+	}
+
+	for _, tt := range []struct {
+		name string
+		in   any
+		out  any
+	}{
+		{"epoch_order", epochNote, new(EpochOrderNote)},
+		{"book_update_batch", batch, new(BookUpdateBatch)},
+		{"config", cfg, new(ConfigResult)},
+		{"error", NewError(RPCParseError, "bad"), new(Error)},
+		{"negative", &struct{ A, B int64 }{-1, math.MinInt64}, new(struct{ A, B int64 })},
+	} {
+		b, err := MarshalCBOR(tt.in)
+		if err != nil {
+			t.Fatalf("%s: MarshalCBOR error: %v", tt.name, err)
+		}
+		if err := UnmarshalCBOR(b, tt.out); err != nil {
+			t.Fatalf("%s: UnmarshalCBOR error: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(tt.in, tt.out) {
+			t.Fatalf("%s: wrong round trip value. wanted %+v, got %+v", tt.name, tt.in, tt.out)
+		}
+		// The JSON conversion should match the JSON encoding.
+		jsonB, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("%s: json.Marshal error: %v", tt.name, err)
+		}
+		convB, err := cborToJSON(b)
+		if err != nil {
+			t.Fatalf("%s: cborToJSON error: %v", tt.name, err)
+		}
+		if !jsonEqual(t, jsonB, convB) {
+			t.Fatalf("%s: wrong JSON conversion. wanted %s, got %s", tt.name, jsonB, convB)
+		}
+	}
+
+	// Byte strings are smaller than hex.
+	cborB, _ := MarshalCBOR(epochNote)
+	jsonB, _ := json.Marshal(epochNote)
+	if len(cborB) >= len(jsonB)*2/3 {
+		t.Fatalf("CBOR not compact. %d bytes vs %d bytes of JSON", len(cborB), len(jsonB))
+	}
+
+	// Trailing and truncated data.
+	if err := UnmarshalCBOR(append(cborB, 0), new(EpochOrderNote)); err == nil {
+		t.Fatalf("no error for trailing data")
+	}
+	if err := UnmarshalCBOR(cborB[:len(cborB)-1], new(EpochOrderNote)); err == nil {
+		t.Fatalf("no error for truncated data")
+	}
+	// Wrong types.
+	if err := UnmarshalCBOR(cborB, new(string)); err == nil {
+		t.Fatalf("no error for wrong type")
+	}
+}
+
+func TestCBORMessage(t *testing.T) {
+	note := &UpdateRemainingNote{
+		OrderNote: OrderNote{
+			Seq:      1,
+			MarketID: "dcr_btc",
+			OrderID:  randomBytes(32),
+		},
+		Remaining: 1e8,
+	}
+	msg, err := NewNotification(UpdateRemainingRoute, note)
+	if err != nil {
+		t.Fatalf("NewNotification error: %v", err)
+	}
+
+	checkMsg := func(b []byte) {
+		t.Helper()
+		decoded := new(Message)
+		if err := decoded.Decode(b, CBOREncoding); err != nil {
+			t.Fatalf("Decode error: %v", err)
+		}
+		if decoded.Encoding() != CBOREncoding {
+			t.Fatalf("wrong encoding %s", decoded.Encoding())
+		}
+		if decoded.Type != Notification || decoded.Route != UpdateRemainingRoute {
+			t.Fatalf("wrong message %s", decoded)
+		}
+		var reNote UpdateRemainingNote
+		if err := decoded.Unmarshal(&reNote); err != nil {
+			t.Fatalf("Unmarshal error: %v", err)
+		}
+		if !reflect.DeepEqual(note, &reNote) {
+			t.Fatalf("wrong payload. wanted %+v, got %+v", note, reNote)
+		}
+		// The message can be converted back to JSON.
+		jsonB, err := decoded.Encode(JSONEncoding)
+		if err != nil {
+			t.Fatalf("Encode (JSON) error: %v", err)
+		}
+		origB, _ := json.Marshal(msg)
+		if !jsonEqual(t, origB, jsonB) {
+			t.Fatalf("wrong JSON. wanted %s, got %s", origB, jsonB)
+		}
+	}
+
+	// From the constructor's source value.
+	b, err := msg.Encode(CBOREncoding)
+	if err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	checkMsg(b)
+
+	// Transcoded from a JSON message.
+	jsonMsg, err := DecodeMessage([]byte(msg.String()))
+	if err != nil {
+		t.Fatalf("DecodeMessage error: %v", err)
+	}
+	b, err = jsonMsg.Encode(CBOREncoding)
+	if err != nil {
+		t.Fatalf("Encode error for JSON message: %v", err)
+	}
+	checkMsg(b)
+
+	// Responses.
+	type tResult struct {
+		Preimage Bytes `json:"pimg"`
+	}
+	result := &tResult{Preimage: randomBytes(32)}
+	for _, rpcErr := range []*Error{nil, NewError(InvalidPreimage, "bad")} {
+		resp, _ := NewResponse(5, result, rpcErr)
+		b, err := resp.Encode(CBOREncoding)
+		if err != nil {
+			t.Fatalf("Encode error for response: %v", err)
+		}
+		decoded := new(Message)
+		if err := decoded.Decode(b, CBOREncoding); err != nil {
+			t.Fatalf("Decode error for response: %v", err)
+		}
+		var reResult tResult
+		err = decoded.UnmarshalResult(&reResult)
+		if rpcErr != nil {
+			if err == nil {
+				t.Fatalf("no error for error response")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("UnmarshalResult error: %v", err)
+		}
+		if !bytes.Equal(reResult.Preimage, result.Preimage) {
+			t.Fatalf("wrong result")
+		}
+	}
+
+	// Null response payload.
+	nullMsg := &Message{Type: Response, Payload: []byte{cborNull}, enc: CBOREncoding}
+	if _, err := nullMsg.Response(); err == nil {
+		t.Fatalf("no error for null response payload")
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -1152,3 +1152,54 @@ func randomCoin() *Coin {
 		Redeem:  randomBytes(25),
 	}
 }
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		ours, theirs []uint16
+		wantVer      uint16
+		wantFound    bool
+	}{
+		{"highest common", []uint16{1, 2, 3}, []uint16{0, 2, 3, 4}, 3, true},
+		{"unordered", []uint16{3, 1}, []uint16{1, 3}, 3, true},
+		{"zero", []uint16{0, 1}, []uint16{0}, 0, true},
+		{"none common", []uint16{1, 2}, []uint16{3}, 0, false},
+		{"empty", []uint16{1}, nil, 0, false},
+	}
+	for _, tt := range tests {
+		ver, found := NegotiateVersion(tt.ours, tt.theirs)
+		if found != tt.wantFound || ver != tt.wantVer {
+			t.Fatalf("%s: wanted (%d, %t), got (%d, %t)", tt.name, tt.wantVer, tt.wantFound, ver, found)
+		}
+	}
+}
+
+func TestOrderBookDigest(t *testing.T) {
+	newOrder := func(side uint8, qty, rate uint64) *BookOrderNote {
+		return &BookOrderNote{
+			OrderNote: OrderNote{OrderID: randomBytes(32)},
+			TradeNote: TradeNote{Side: side, Quantity: qty, Rate: rate},
+		}
+	}
+	o1, o2 := newOrder(BuyOrderNum, 1e8, 2e6), newOrder(SellOrderNum, 2e8, 3e6)
+	book := &OrderBook{Seq: 1, Orders: []*BookOrderNote{o1, o2}}
+	digest := book.Digest()
+	if len(digest) != 32 {
+		t.Fatalf("wrong digest length %d", len(digest))
+	}
+	// Order and non-order fields don't matter.
+	reordered := &OrderBook{Seq: 2, Epoch: 5, Orders: []*BookOrderNote{o2, o1}}
+	if !bytes.Equal(digest, reordered.Digest()) {
+		t.Fatalf("digest depends on order")
+	}
+	if book.Orders[0] != o1 {
+		t.Fatalf("Digest sorted the book's orders")
+	}
+	o1.Quantity--
+	if bytes.Equal(digest, book.Digest()) {
+		t.Fatalf("digest did not change with quantity")
+	}
+	if empty := new(OrderBook).Digest(); bytes.Equal(empty, digest) {
+		t.Fatalf("empty book has same digest")
+	}
+}
@@ -4,9 +4,13 @@
 package msgjson
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"time"
 
 	"decred.org/dcrdex/dex"
@@ -98,6 +102,27 @@ const (
 	RPCUpdateRunningBotCfgError          // 80
 	RPCUpdateRunningBotInvError          // 81
 	RPCMMStatusError                     // 82
+	RPCTranslationReportError            // 83
+	RPCBackupError                       // 84
+	RPCRestoreBackupError                // 85
+	RPCDBMaintenanceError                // 86
+	RPCSyncProgressError                 // 87
+	RPCFeeReportError                    // 88
+	RPCAPIKeyError                       // 89
+	RPCRouteNotAllowedError              // 90
+	RPCRecoveryError                     // 91
+	RPCConfTargetError                   // 92
+	RPCCEXConversionError                // 93
+	RPCMMDryRunError                     // 94
+	RPCMMAllocationError                 // 95
+	AccountNotAllowedError               // 96
+	RPCTokenAllowanceError               // 97
+	RPCSwapRecoveryError                 // 98
+	RPCClientCertError                   // 99
+	EpochProofUnavailableError           // 100
+	RPCBondPlanError                     // 101
+	RPCAnnotateOrderError                // 102
+	RPCTradingLimitsError                // 103
 )
 
 // Routes are destinations for a "payload" of data. The type of data being
@@ -117,6 +142,10 @@ const (
 	// OrderStatusRoute is the route of a client-originating request-type
 	// message to retrieve order data from the DEX.
 	OrderStatusRoute = "order_status"
+	// SwapProgressRoute is the route of a client-originating request-type
+	// message to retrieve the server's view of the swap progress of an active
+	// match, i.e. which swap and redeem transactions the server has seen.
+	SwapProgressRoute = "swap_progress"
 	// InitRoute is the route of a client-originating request-type message
 	// notifying the DEX, and subsequently the match counter-party, of the details
 	// of a swap contract.
@@ -169,6 +198,10 @@ const (
 	// indicates the end of an epoch's book updates and provides stats for
 	// maintaining a candlestick cache.
 	EpochReportRoute = "epoch_report"
+	// BookUpdateBatchRoute is the DEX-originating notification-type message
+	// that delivers an epoch's book_order, unbook_order, and update_remaining
+	// notifications together, for subscribers that requested batching.
+	BookUpdateBatchRoute = "book_update_batch"
 	// ConnectRoute is a client-originating request-type message seeking
 	// authentication so that the connection can be used for trading.
 	ConnectRoute = "connect"
@@ -192,6 +225,10 @@ const (
 	// ConfigRoute is the client-originating request-type message requesting the
 	// DEX configuration information.
 	ConfigRoute = "config"
+	// VersionRoute is the client-originating request-type message used to
+	// negotiate the communications API version for the connection. It should
+	// be the first request on a new connection.
+	VersionRoute = "version"
 	// HealthRoute is the client-originating request-type message requesting the
 	// DEX's health status.
 	HealthRoute = "healthy"
@@ -230,6 +267,13 @@ const (
 	// CandlesRoute is the HTTP request to get the set of candlesticks
 	// representing market activity history.
 	CandlesRoute = "candles"
+	// EpochProofRoute is the client-originating request-type message
+	// retrieving the inputs to a recent epoch's order matching, for verifying
+	// the epoch's match proof.
+	EpochProofRoute = "epoch_proof"
+	// BookSnapshotRoute is the HTTP request to get an order book snapshot with
+	// a digest of its orders, for verifying a mirrored order book.
+	BookSnapshotRoute = "booksnapshot"
 )
 
 const errNullRespPayload = dex.ErrorKind("null response payload")
@@ -304,6 +348,22 @@ type ResponsePayload struct {
 	Result json.RawMessage `json:"result,omitempty"`
 	// Error is the error, or nil if none was encountered.
 	Error *Error `json:"error,omitempty"`
+
+	// enc is the encoding of the Result.
+	enc Encoding
+}
+
+// UnmarshalResult decodes the Result into the provided interface, which must
+// contain a pointer. The Error field is not checked.
+func (resp *ResponsePayload) UnmarshalResult(result any) error {
+	return resp.enc.unmarshal(resp.Result, result)
+}
+
+// responseSource is the CBOR encoding source for a ResponsePayload, so that
+// the result need not be JSON-encoded first.
+type responseSource struct {
+	Result any    `json:"result"`
+	Error  *Error `json:"error,omitempty"`
 }
 
 // MessageType indicates the type of message. MessageType is typically the first
@@ -335,6 +395,42 @@ func (mt MessageType) String() string {
 	}
 }
 
+// Encoding is a wire encoding for a Message. JSON is the canonical encoding.
+// CBOR is negotiated per-connection via the websocket subprotocol, and is
+// used for both the Message and its payload.
+type Encoding uint8
+
+const (
+	JSONEncoding Encoding = iota // 0
+	CBOREncoding                 // 1
+)
+
+// CBORSubprotocol is the websocket subprotocol requested by a client that
+// would like to use CBOREncoding. Peers that do not support it will not
+// select the subprotocol, and JSONEncoding should be used.
+const CBORSubprotocol = "dex.cbor.v1"
+
+// EncodingFromSubprotocol returns the Encoding selected by the negotiated
+// websocket subprotocol.
+func EncodingFromSubprotocol(subprotocol string) Encoding {
+	if subprotocol == CBORSubprotocol {
+		return CBOREncoding
+	}
+	return JSONEncoding
+}
+
+// String satisfies the Stringer interface.
+func (enc Encoding) String() string {
+	switch enc {
+	case JSONEncoding:
+		return "json"
+	case CBOREncoding:
+		return "cbor"
+	default:
+		return "unknown encoding"
+	}
+}
+
 // Message is the primary messaging type for websocket communications.
 type Message struct {
 	// Type is the message type.
@@ -351,6 +447,162 @@ type Message struct {
 	// scheme. The old way was to sign individual payloads. Which is used
 	// depends on the route.
 	Sig dex.Bytes `json:"sig"`
+
+	// enc is the encoding of the Payload. This is JSONEncoding unless the
+	// Message was decoded from CBOR.
+	enc Encoding
+	// src is the value that was JSON-encoded for the Payload by one of the
+	// constructors. If set, it is encoded directly when sending the Message
+	// with CBOREncoding, avoiding a JSON round trip.
+	src any
+}
+
+// Encoding is the encoding of the Message's Payload. This is the encoding that
+// the Message was decoded from, or JSONEncoding for a constructed Message.
+func (msg *Message) Encoding() Encoding {
+	return msg.enc
+}
+
+// Encode serializes the Message with the specified Encoding. Like
+// json.Marshal, a nil *Message is encoded as null.
+func (msg *Message) Encode(enc Encoding) ([]byte, error) {
+	if msg == nil {
+		if enc == CBOREncoding {
+			return []byte{cborNull}, nil
+		}
+		return []byte("null"), nil
+	}
+	switch enc {
+	case JSONEncoding:
+		if msg.enc == CBOREncoding {
+			jsonMsg, err := msg.toJSON()
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(jsonMsg)
+		}
+		return json.Marshal(msg)
+	case CBOREncoding:
+		return msg.encodeCBOR()
+	}
+	return nil, fmt.Errorf("unknown encoding %d", enc)
+}
+
+// Decode deserializes the Message from the encoded bytes. Note that for
+// JSONEncoding, []byte("null") leaves the Message unchanged.
+func (msg *Message) Decode(b []byte, enc Encoding) error {
+	var err error
+	switch enc {
+	case JSONEncoding:
+		err = json.Unmarshal(b, msg)
+	case CBOREncoding:
+		err = UnmarshalCBOR(b, msg)
+	default:
+		return fmt.Errorf("unknown encoding %d", enc)
+	}
+	if err != nil {
+		return err
+	}
+	msg.enc, msg.src = enc, nil
+	return nil
+}
+
+// encodeCBOR encodes the Message with CBOR, converting the payload to CBOR if
+// necessary. The fields are the same as for JSON.
+func (msg *Message) encodeCBOR() ([]byte, error) {
+	e := &cborEncoder{buf: make([]byte, 0, 64+len(msg.Payload))}
+	n := uint64(2) // type and sig
+	if msg.Route != "" {
+		n++
+	}
+	if msg.ID != 0 {
+		n++
+	}
+	if len(msg.Payload) > 0 || msg.src != nil {
+		n++
+	}
+	e.head(cborMajorMap, n)
+	e.text("type")
+	e.head(cborMajorUint, uint64(msg.Type))
+	if msg.Route != "" {
+		e.text("route")
+		e.text(msg.Route)
+	}
+	if msg.ID != 0 {
+		e.text("id")
+		e.head(cborMajorUint, msg.ID)
+	}
+	switch {
+	case msg.src != nil:
+		e.text("payload")
+		if err := e.encode(reflect.ValueOf(msg.src)); err != nil {
+			return nil, err
+		}
+	case len(msg.Payload) == 0:
+	case msg.enc == CBOREncoding:
+		e.text("payload")
+		e.buf = append(e.buf, msg.Payload...)
+	default:
+		e.text("payload")
+		if err := e.encodeJSON(msg.Payload); err != nil {
+			return nil, err
+		}
+	}
+	e.text("sig")
+	e.bytes(msg.Sig)
+	return e.buf, nil
+}
+
+// toJSON creates a copy of a CBOR-decoded Message with the Payload converted
+// to JSON.
+func (msg *Message) toJSON() (*Message, error) {
+	jsonMsg := *msg
+	jsonMsg.enc, jsonMsg.src = JSONEncoding, nil
+	if len(msg.Payload) > 0 {
+		b, err := cborToJSON(msg.Payload)
+		if err != nil {
+			return nil, err
+		}
+		jsonMsg.Payload = b
+	}
+	return &jsonMsg, nil
+}
+
+// MessageEncoder encodes a Message at most once for each Encoding. Use it to
+// send the same Message to many peers with different encodings. It is not safe
+// for concurrent use.
+type MessageEncoder struct {
+	msg     *Message
+	encoded map[Encoding][]byte
+}
+
+// NewMessageEncoder is the constructor for a MessageEncoder.
+func NewMessageEncoder(msg *Message) *MessageEncoder {
+	return &MessageEncoder{
+		msg:     msg,
+		encoded: make(map[Encoding][]byte, 2),
+	}
+}
+
+// Encode returns the Message serialized with the specified Encoding.
+func (me *MessageEncoder) Encode(enc Encoding) ([]byte, error) {
+	if b, found := me.encoded[enc]; found {
+		return b, nil
+	}
+	b, err := me.msg.Encode(enc)
+	if err != nil {
+		return nil, err
+	}
+	me.encoded[enc] = b
+	return b, nil
+}
+
+// unmarshal decodes the encoded bytes into the provided interface.
+func (enc Encoding) unmarshal(b []byte, thing any) error {
+	if enc == CBOREncoding {
+		return UnmarshalCBOR(b, thing)
+	}
+	return json.Unmarshal(b, thing)
 }
 
 // DecodeMessage decodes a *Message from JSON-formatted bytes. Note that
@@ -381,6 +633,7 @@ func NewRequest(id uint64, route string, payload any) (*Message, error) {
 		Payload: json.RawMessage(encoded),
 		Route:   route,
 		ID:      id,
+		src:     payload,
 	}, nil
 }
 
@@ -405,6 +658,7 @@ func NewResponse(id uint64, result any, rpcErr *Error) (*Message, error) {
 		Type:    Response,
 		Payload: json.RawMessage(encResp),
 		ID:      id,
+		src:     &responseSource{Result: result, Error: rpcErr},
 	}, nil
 }
 
@@ -416,13 +670,14 @@ func (msg *Message) Response() (*ResponsePayload, error) {
 		return nil, fmt.Errorf("invalid type %d for ResponsePayload", msg.Type)
 	}
 	resp := new(ResponsePayload)
-	err := json.Unmarshal(msg.Payload, &resp)
+	err := msg.enc.unmarshal(msg.Payload, &resp)
 	if err != nil {
 		return nil, err
 	}
 	if resp == nil /* null JSON */ {
 		return nil, errNullRespPayload
 	}
+	resp.enc = msg.enc
 	return resp, nil
 }
 
@@ -439,6 +694,7 @@ func NewNotification(route string, payload any) (*Message, error) {
 		Type:    Notification,
 		Route:   route,
 		Payload: json.RawMessage(encPayload),
+		src:     payload,
 	}, nil
 }
 
@@ -446,7 +702,7 @@ func NewNotification(route string, payload any) (*Message, error) {
 // the payload interface must contain a pointer. If it is a pointer to a
 // pointer, it may become nil for a Message.Payload of []byte("null").
 func (msg *Message) Unmarshal(payload any) error {
-	return json.Unmarshal(msg.Payload, payload)
+	return msg.enc.unmarshal(msg.Payload, payload)
 }
 
 // UnmarshalResult is a convenience method for decoding the Result field of a
@@ -459,12 +715,12 @@ func (msg *Message) UnmarshalResult(result any) error {
 	if resp.Error != nil {
 		return fmt.Errorf("rpc error: %w", resp.Error)
 	}
-	return json.Unmarshal(resp.Result, result)
+	return resp.UnmarshalResult(result)
 }
 
 // String prints the message as a JSON-encoded string.
 func (msg *Message) String() string {
-	b, err := json.Marshal(msg)
+	b, err := msg.Encode(JSONEncoding)
 	if err != nil {
 		return "[Message decode error]"
 	}
@@ -537,6 +793,37 @@ type MatchStatusResult struct {
 	TakerTxData Bytes `json:"takertx,omitempty"`
 }
 
+// SwapProgressRequest is the payload for the SwapProgressRoute request.
+type SwapProgressRequest struct {
+	MatchID Bytes `json:"matchid"`
+}
+
+// SwapProgress is the server's view of the swap and redeem transactions of one
+// party of a match. Times are in unix milliseconds, and are zero if the
+// transaction has not been seen.
+type SwapProgress struct {
+	SwapAsset uint32 `json:"swapasset"`
+	SwapCoin  Bytes  `json:"swapcoin,omitempty"`
+	SwapSeen  uint64 `json:"swapseen,omitempty"`
+	// SwapConfs is the number of confirmations of the swap. It is -1 if the
+	// confirmations could not be checked.
+	SwapConfs         int64  `json:"swapconfs"`
+	SwapConfsRequired uint32 `json:"swapconfsreq"`
+	SwapConfirmed     uint64 `json:"swapconfirmed,omitempty"`
+	RedeemAsset       uint32 `json:"redeemasset"`
+	RedeemCoin        Bytes  `json:"redeemcoin,omitempty"`
+	RedeemSeen        uint64 `json:"redeemseen,omitempty"`
+}
+
+// SwapProgressResult is the successful result for the SwapProgressRoute
+// request.
+type SwapProgressResult struct {
+	MatchID Bytes         `json:"matchid"`
+	Status  uint8         `json:"status"`
+	Maker   *SwapProgress `json:"maker"`
+	Taker   *SwapProgress `json:"taker"`
+}
+
 // OrderStatusRequest details an order for the OrderStatusRoute request. The
 // actual payload is a []OrderStatusRequest.
 type OrderStatusRequest struct {
@@ -814,6 +1101,10 @@ type OrderResult struct {
 type OrderBookSubscription struct {
 	Base  uint32 `json:"base"`
 	Quote uint32 `json:"quote"`
+	// Batch requests that book updates be coalesced into BookUpdateBatch
+	// notifications if the server supports it. Servers that do not will send
+	// the individual notifications.
+	Batch bool `json:"batch,omitempty"`
 }
 
 // UnsubOrderBook is the payload for a client-originating request to the
@@ -870,6 +1161,36 @@ type UpdateRemainingNote struct {
 	Remaining uint64 `json:"remaining"`
 }
 
+// BookUpdate is a single update in a BookUpdateBatch. Exactly one of the
+// fields is set.
+type BookUpdate struct {
+	Book            *BookOrderNote       `json:"book,omitempty"`
+	Unbook          *UnbookOrderNote     `json:"unbook,omitempty"`
+	UpdateRemaining *UpdateRemainingNote `json:"updateRemaining,omitempty"`
+}
+
+// Seq is the sequence number of the update.
+func (u *BookUpdate) Seq() uint64 {
+	switch {
+	case u.Book != nil:
+		return u.Book.Seq
+	case u.Unbook != nil:
+		return u.Unbook.Seq
+	case u.UpdateRemaining != nil:
+		return u.UpdateRemaining.Seq
+	}
+	return 0
+}
+
+// BookUpdateBatch is the payload for a DEX-originating BookUpdateBatchRoute
+// notification. The updates are in the order they were applied to the book,
+// with consecutive sequence numbers. A batch is sent before any other
+// sequenced notification for the market, so the sequence is preserved.
+type BookUpdateBatch struct {
+	MarketID string        `json:"marketid"`
+	Updates  []*BookUpdate `json:"updates"`
+}
+
 // OrderBook is the response to a successful OrderBookSubscription.
 type OrderBook struct {
 	MarketID string `json:"marketid"`
@@ -887,6 +1208,59 @@ type OrderBook struct {
 	RecentMatches [][3]int64 `json:"recentMatches"`
 }
 
+// Digest is the SHA-256 hash of the book's orders, sorted by order ID. Each
+// order is serialized as order ID (32) + side (1) + rate (8) + quantity (8).
+// The epoch, fee rates and recent matches are not included, so anyone applying
+// the book feed from the same sequence number can compute the same digest.
+func (ob *OrderBook) Digest() Bytes {
+	ords := make([]*BookOrderNote, len(ob.Orders))
+	copy(ords, ob.Orders)
+	sort.Slice(ords, func(i, j int) bool {
+		return bytes.Compare(ords[i].OrderID, ords[j].OrderID) < 0
+	})
+	h := sha256.New()
+	for _, o := range ords {
+		h.Write(o.OrderID)
+		h.Write([]byte{o.Side})
+		h.Write(uint64Bytes(o.Rate))
+		h.Write(uint64Bytes(o.Quantity))
+	}
+	return h.Sum(nil)
+}
+
+// BookSnapshot is the response to a BookSnapshotRoute request. The orders and
+// Seq are taken atomically, so the Digest matches a mirrored book that has
+// applied all updates through Seq.
+type BookSnapshot struct {
+	OrderBook
+	Digest Bytes `json:"digest"`
+}
+
+// EpochProofRequest is the payload for a client-originating EpochProofRoute
+// request.
+type EpochProofRequest struct {
+	MarketID string `json:"marketid"`
+	Epoch    uint64 `json:"epoch"`
+}
+
+// EpochProofOrder is an epoch queue order in an EpochProof. Preimage is empty
+// if the order's preimage was not revealed.
+type EpochProofOrder struct {
+	EpochOrderNote
+	Preimage Bytes `json:"pimg,omitempty"`
+}
+
+// EpochProof is the response to an EpochProofRoute request. It has all of the
+// epoch's queued orders, so the CSum and Seed of the epoch's match_proof, and
+// the order in which the orders were matched, can be recomputed.
+type EpochProof struct {
+	MarketID string             `json:"marketid"`
+	Epoch    uint64             `json:"epoch"`
+	Orders   []*EpochProofOrder `json:"orders"`
+	CSum     Bytes              `json:"csum"`
+	Seed     Bytes              `json:"seed"`
+}
+
 // MatchProofNote is the match_proof notification payload.
 type MatchProofNote struct {
 	MarketID  string  `json:"marketid"`
@@ -945,6 +1319,38 @@ func (c *Connect) Serialize() []byte {
 	return append(s, uint64Bytes(c.Time)...)
 }
 
+// VersionRequest is the payload for a client-originating VersionRoute request.
+type VersionRequest struct {
+	// Versions are the communications API versions supported by the client.
+	Versions []uint16 `json:"versions"`
+}
+
+// VersionResult is the result for the VersionRoute request.
+type VersionResult struct {
+	// Version is the communications API version selected by the server. It
+	// will be one of the versions in the VersionRequest.
+	Version uint16 `json:"version"`
+}
+
+// NegotiateVersion picks the highest version that is in both ours and theirs.
+// The bool is false if there is no common version.
+func NegotiateVersion(ours, theirs []uint16) (uint16, bool) {
+	var ver uint16
+	var found bool
+	for _, v := range theirs {
+		if found && v <= ver {
+			continue
+		}
+		for _, w := range ours {
+			if v == w {
+				ver, found = v, true
+				break
+			}
+		}
+	}
+	return ver, found
+}
+
 // Bond is information on a fidelity bond. This is part of the ConnectResult and
 // PostBondResult payloads.
 type Bond struct {
@@ -1257,10 +1663,11 @@ type BondAsset struct {
 
 // ConfigResult is the successful result for the ConfigRoute.
 type ConfigResult struct {
-	// APIVersion is the server's communications API version, but we may
-	// consider APIVersions []uint16, with versioned routes e.g. "initV2".
-	// APIVersions []uint16 `json:"apivers"`
-	APIVersion       uint16    `json:"apiver"`
+	// APIVersion is the server's current communications API version.
+	APIVersion uint16 `json:"apiver"`
+	// APIVersions are all of the communications API versions that the server
+	// will negotiate via the VersionRoute.
+	APIVersions      []uint16  `json:"apivers,omitempty"`
 	DEXPubKey        dex.Bytes `json:"pubkey"`
 	CancelMax        float64   `json:"cancelmax"`
 	BroadcastTimeout uint64    `json:"btimeout"`
@@ -0,0 +1,53 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package msgpb defines the gRPC transport for the DEX client-server protocol.
+// The Message type carries msgjson routes and JSON-encoded payloads unchanged,
+// so the websocket protocol remains canonical.
+//
+// To regenerate dex.pb.go and dex_grpc.pb.go after modifying dex.proto, run
+// go generate with protoc, protoc-gen-go, and protoc-gen-go-grpc installed.
+package msgpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative dex.proto
+
+import (
+	"encoding/json"
+
+	"decred.org/dcrdex/dex/msgjson"
+)
+
+// FromMsgJSON converts a msgjson.Message to a Message. The Message payload is
+// the JSON encoding of the msgjson.Message payload.
+func FromMsgJSON(msg *msgjson.Message) (*Message, error) {
+	payload := []byte(msg.Payload)
+	if msg.Encoding() != msgjson.JSONEncoding {
+		b, err := msg.Encode(msgjson.JSONEncoding)
+		if err != nil {
+			return nil, err
+		}
+		jsonMsg := new(msgjson.Message)
+		if err = json.Unmarshal(b, jsonMsg); err != nil {
+			return nil, err
+		}
+		payload = jsonMsg.Payload
+	}
+	return &Message{
+		Type:    uint32(msg.Type),
+		Route:   msg.Route,
+		Id:      msg.ID,
+		Payload: payload,
+		Sig:     msg.Sig,
+	}, nil
+}
+
+// MsgJSON converts the Message to a msgjson.Message.
+func (x *Message) MsgJSON() *msgjson.Message {
+	return &msgjson.Message{
+		Type:    msgjson.MessageType(x.GetType()),
+		Route:   x.GetRoute(),
+		ID:      x.GetId(),
+		Payload: json.RawMessage(x.GetPayload()),
+		Sig:     x.GetSig(),
+	}
+}
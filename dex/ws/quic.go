@@ -0,0 +1,513 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package ws
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/gorilla/websocket"
+	"github.com/quic-go/quic-go"
+)
+
+// QUIC is an alternative to websockets for the connection between a client
+// and a server. The client dials the server's QUIC listener, which is on the
+// UDP port with the same number as the server's websocket listener, and opens
+// a single bidirectional stream. The message encoding is negotiated in the TLS
+// handshake with the application protocols QUICProtocolCBOR and
+// QUICProtocolJSON, in the same way as the websocket subprotocol. The client
+// then sends a hello with the headers that it would send with a websocket
+// upgrade request, and the server accepts or rejects the connection with an
+// HTTP status code. After that, the stream carries the same messages, pings
+// and close messages as a websocket connection, each in a frame with a 1-byte
+// websocket frame type and a 4-byte big-endian payload length. A single
+// stream keeps the messages in order.
+//
+// A server advertises its QUIC listener with the QUICPortHeader in the
+// response to a websocket upgrade request, so a client only tries QUIC with
+// servers that accept it.
+
+const (
+	// QUICProtocolCBOR is the TLS application protocol for QUIC connections
+	// that use the CBOR message encoding.
+	QUICProtocolCBOR = msgjson.CBORSubprotocol
+	// QUICProtocolJSON is the TLS application protocol for QUIC connections
+	// that use the JSON message encoding.
+	QUICProtocolJSON = "dex.json.v1"
+
+	// QUICPortHeader is the header in a server's response to a websocket
+	// upgrade request that advertises the server's QUIC listener. The value
+	// is the UDP port number.
+	QUICPortHeader = "Dex-Quic-Port"
+
+	// ErrQUICRejected is returned by DialQUIC when the server responds to the
+	// hello with an error status, or closes the connection with RejectQUIC.
+	ErrQUICRejected = dex.ErrorKind("QUIC connection rejected")
+
+	quicFrameHeaderSize = 5
+	// quicHelloTimeout is how long a server waits for the client to open the
+	// stream and send its hello, and how long a client waits for the
+	// server's response.
+	quicHelloTimeout = 10 * time.Second
+	// quicMaxIdleTimeout is the QUIC idle timeout. The peers' pings keep the
+	// connection alive, and a missing ping is detected with read deadlines
+	// well before this.
+	quicMaxIdleTimeout = time.Minute
+	// quicCloseWait is how long Close waits for the peer to close the QUIC
+	// connection.
+	quicCloseWait = time.Second
+)
+
+// quicHello is the first message sent by the client on the stream.
+type quicHello struct {
+	Header http.Header `json:"header,omitempty"`
+}
+
+// quicHelloResponse is the server's response to the hello.
+type quicHelloResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// QUICConn is a message stream on a QUIC connection. It has the methods of a
+// *websocket.Conn that are used by WSLink and client connections, with the
+// same semantics, and satisfies Connection.
+type QUICConn struct {
+	conn     quic.Connection
+	stream   quic.Stream
+	protocol string
+	// r is only used by the reading goroutine, as are the handlers.
+	r           *bufio.Reader
+	readLimit   atomic.Int64
+	pingHandler func(string) error
+	pongHandler func(string) error
+
+	writeMtx      sync.Mutex
+	writeDeadline time.Time
+	closeSent     bool
+
+	closeOnce sync.Once
+	closed    atomic.Bool
+	// peerClosed is set when the peer sends a close message.
+	peerClosed atomic.Bool
+}
+
+var _ Connection = (*QUICConn)(nil)
+
+func newQUICConn(conn quic.Connection, stream quic.Stream) *QUICConn {
+	c := &QUICConn{
+		conn:     conn,
+		stream:   stream,
+		protocol: conn.ConnectionState().TLS.NegotiatedProtocol,
+		r:        bufio.NewReader(stream),
+	}
+	c.SetPingHandler(nil)
+	c.SetPongHandler(nil)
+	return c
+}
+
+// quicConfig is the QUIC configuration. A server accepts a single
+// bidirectional stream from each client, and a client accepts none.
+func quicConfig(server bool) *quic.Config {
+	cfg := &quic.Config{
+		MaxIdleTimeout:        quicMaxIdleTimeout,
+		MaxIncomingStreams:    -1,
+		MaxIncomingUniStreams: -1,
+	}
+	if server {
+		cfg.MaxIncomingStreams = 1
+	}
+	return cfg
+}
+
+// quicTLSConfig is a copy of the TLS config for QUIC, which requires TLS 1.3,
+// with the application protocols.
+func quicTLSConfig(tlsCfg *tls.Config, protocols []string) *tls.Config {
+	tlsCfg = tlsCfg.Clone()
+	tlsCfg.MinVersion = tls.VersionTLS13
+	tlsCfg.NextProtos = protocols
+	return tlsCfg
+}
+
+// QUICListener listens for QUIC connections on a UDP address.
+type QUICListener struct {
+	*quic.Listener
+	udpConn net.PacketConn
+}
+
+// ListenQUIC listens for QUIC connections on the UDP address. The network is
+// "udp4" or "udp6". Clients may select either message encoding. The TLS
+// config must have a certificate.
+func ListenQUIC(network, addr string, tlsCfg *tls.Config) (*QUICListener, error) {
+	udpAddr, err := net.ResolveUDPAddr(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, err := net.ListenUDP(network, udpAddr)
+	if err != nil {
+		return nil, err
+	}
+	// Go's TLS server selects the first of its protocols that the client
+	// offers, so a client that offers CBOR gets it.
+	tlsCfg = quicTLSConfig(tlsCfg, []string{QUICProtocolCBOR, QUICProtocolJSON})
+	ln, err := quic.Listen(udpConn, tlsCfg, quicConfig(true))
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+	return &QUICListener{Listener: ln, udpConn: udpConn}, nil
+}
+
+// Close stops the listener and closes its connections.
+func (l *QUICListener) Close() error {
+	err := l.Listener.Close()
+	l.udpConn.Close()
+	return err
+}
+
+// AcceptQUIC accepts the stream that the client opens on a new QUIC connection
+// and reads the client's hello, returning the headers from it. The caller must
+// then either Accept or Reject the connection. The returned QUICConn is
+// configured like the Connection returned by NewConnection.
+func AcceptQUIC(ctx context.Context, qc quic.Connection, readTimeout time.Duration) (*QUICConn, http.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, quicHelloTimeout)
+	defer cancel()
+	stream, err := qc.AcceptStream(ctx)
+	if err != nil {
+		qc.CloseWithError(0, "")
+		return nil, nil, fmt.Errorf("error accepting QUIC stream: %w", err)
+	}
+	c := newQUICConn(qc, stream)
+	c.SetPongHandler(func(string) error {
+		return c.SetReadDeadline(time.Now().Add(readTimeout))
+	})
+	// Unauthenticated connections have a small read limit.
+	c.SetReadLimit(defaultReadLimit)
+
+	var hello quicHello
+	if err := c.readJSON(time.Now().Add(quicHelloTimeout), &hello); err != nil {
+		c.Close()
+		return nil, nil, dex.NewError(ErrHandshake, err.Error())
+	}
+	// Do not set a read deadline until pinging begins.
+	if err := c.SetReadDeadline(time.Time{}); err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	// Canonicalize the header keys, as for an HTTP request.
+	header := make(http.Header, len(hello.Header))
+	for k, v := range hello.Header {
+		header[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+	return c, header, nil
+}
+
+// Accept sends the response to the client's hello that accepts the
+// connection.
+func (c *QUICConn) Accept() error {
+	return c.writeJSON(&quicHelloResponse{Status: http.StatusOK})
+}
+
+// Reject sends the response to the client's hello that rejects the connection
+// with the HTTP status code and message, and closes the connection.
+func (c *QUICConn) Reject(status int, msg string) {
+	c.writeJSON(&quicHelloResponse{Status: status, Message: msg})
+	c.Close()
+}
+
+// RejectQUIC rejects a new QUIC connection before the client's hello is read,
+// closing it with the HTTP status code and message.
+func RejectQUIC(qc quic.Connection, status int, msg string) {
+	qc.CloseWithError(quic.ApplicationErrorCode(status), msg)
+}
+
+// quicRejection converts the error from a QUIC connection closed by the server
+// with RejectQUIC to an ErrQUICRejected. Other errors are returned unchanged.
+func quicRejection(err error) error {
+	var appErr *quic.ApplicationError
+	if errors.As(err, &appErr) && appErr.Remote && appErr.ErrorCode != 0 {
+		return dex.NewError(ErrQUICRejected, fmt.Sprintf("%d %s", appErr.ErrorCode, appErr.ErrorMessage))
+	}
+	return err
+}
+
+// DialQUIC dials a QUIC connection to the server at addr, which is host:port,
+// and sends the hello with the header. The application protocols are offered
+// to the server in order of preference, and determine the message encoding.
+// If the server rejects the connection, the error is an ErrQUICRejected.
+func DialQUIC(ctx context.Context, addr string, tlsCfg *tls.Config, protocols []string, header http.Header) (*QUICConn, error) {
+	qc, err := quic.DialAddr(ctx, addr, quicTLSConfig(tlsCfg, protocols), quicConfig(false))
+	if err != nil {
+		return nil, err
+	}
+	stream, err := qc.OpenStreamSync(ctx)
+	if err != nil {
+		qc.CloseWithError(0, "")
+		return nil, quicRejection(err)
+	}
+	c := newQUICConn(qc, stream)
+	if err := c.writeJSON(&quicHello{Header: header}); err != nil {
+		c.Close()
+		return nil, quicRejection(err)
+	}
+	deadline := time.Now().Add(quicHelloTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	var resp quicHelloResponse
+	if err := c.readJSON(deadline, &resp); err != nil {
+		c.Close()
+		if err = quicRejection(err); errors.Is(err, ErrQUICRejected) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading QUIC hello response: %w", err)
+	}
+	if resp.Status != http.StatusOK {
+		c.Close()
+		return nil, dex.NewError(ErrQUICRejected, fmt.Sprintf("%d %s", resp.Status, resp.Message))
+	}
+	return c, c.SetReadDeadline(time.Time{})
+}
+
+// readJSON reads a hello or hello response with the deadline.
+func (c *QUICConn) readJSON(deadline time.Time, thing any) error {
+	if err := c.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+	frameType, b, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if frameType != websocket.TextMessage {
+		return fmt.Errorf("unexpected frame type %d", frameType)
+	}
+	return json.Unmarshal(b, thing)
+}
+
+// writeJSON writes a hello or hello response.
+func (c *QUICConn) writeJSON(thing any) error {
+	b, err := json.Marshal(thing)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(websocket.TextMessage, b, time.Now().Add(writeWait))
+}
+
+// Subprotocol is the negotiated application protocol. Like the websocket
+// subprotocol, it determines the message encoding.
+func (c *QUICConn) Subprotocol() string {
+	return c.protocol
+}
+
+// RemoteAddr is the address of the peer.
+func (c *QUICConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetPingHandler sets the handler for pings from the peer. The default handler
+// responds with a pong with the same data. The handler is called by
+// ReadMessage.
+func (c *QUICConn) SetPingHandler(h func(appData string) error) {
+	if h == nil {
+		h = func(appData string) error {
+			err := c.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(writeWait))
+			if errors.Is(err, websocket.ErrCloseSent) {
+				return nil
+			}
+			return err
+		}
+	}
+	c.pingHandler = h
+}
+
+// SetPongHandler sets the handler for pongs from the peer. The default handler
+// does nothing. The handler is called by ReadMessage.
+func (c *QUICConn) SetPongHandler(h func(appData string) error) {
+	if h == nil {
+		h = func(string) error { return nil }
+	}
+	c.pongHandler = h
+}
+
+// SetReadLimit sets the maximum size of a message from the peer. If a message
+// exceeds the limit, the connection is closed and ReadMessage returns
+// websocket.ErrReadLimit.
+func (c *QUICConn) SetReadLimit(limit int64) {
+	c.readLimit.Store(limit)
+}
+
+// SetReadDeadline sets the deadline for reads. Once a read times out, the
+// connection is unusable.
+func (c *QUICConn) SetReadDeadline(t time.Time) error {
+	return c.stream.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for WriteMessage.
+func (c *QUICConn) SetWriteDeadline(t time.Time) error {
+	c.writeMtx.Lock()
+	c.writeDeadline = t
+	c.writeMtx.Unlock()
+	return nil
+}
+
+// ReadMessage reads the next text or binary message from the peer, handling
+// any pings and pongs before it. If the peer sends a close message, the error
+// is a *websocket.CloseError. ReadMessage must not be called concurrently.
+func (c *QUICConn) ReadMessage() (int, []byte, error) {
+	for {
+		frameType, b, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch frameType {
+		case websocket.TextMessage, websocket.BinaryMessage:
+			return frameType, b, nil
+		case websocket.PingMessage:
+			if err := c.pingHandler(string(b)); err != nil {
+				return 0, nil, err
+			}
+		case websocket.PongMessage:
+			if err := c.pongHandler(string(b)); err != nil {
+				return 0, nil, err
+			}
+		case websocket.CloseMessage:
+			closeErr := &websocket.CloseError{Code: websocket.CloseNoStatusReceived}
+			if len(b) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(b))
+				closeErr.Text = string(b[2:])
+			}
+			c.peerClosed.Store(true)
+			return 0, nil, closeErr
+		default:
+			c.Close()
+			return 0, nil, fmt.Errorf("unknown frame type %d", frameType)
+		}
+	}
+}
+
+func (c *QUICConn) readFrame() (int, []byte, error) {
+	var hdr [quicFrameHeaderSize]byte
+	if _, err := io.ReadFull(c.r, hdr[:]); err != nil {
+		return 0, nil, c.readError(err)
+	}
+	frameType, n := int(hdr[0]), binary.BigEndian.Uint32(hdr[1:])
+	if limit := c.readLimit.Load(); limit > 0 && int64(n) > limit {
+		c.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseMessageTooBig, ""), time.Now().Add(writeWait))
+		c.Close()
+		return 0, nil, websocket.ErrReadLimit
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(c.r, b); err != nil {
+		return 0, nil, c.readError(err)
+	}
+	return frameType, b, nil
+}
+
+// readError translates errors from a closed connection to the errors from a
+// closed websocket connection. A normal close by the peer is a
+// *websocket.CloseError, and a close by this side is a *net.OpError for a
+// closed network connection.
+func (c *QUICConn) readError(err error) error {
+	if c.closed.Load() {
+		return &net.OpError{Op: "read", Net: "quic", Addr: c.RemoteAddr(), Err: net.ErrClosed}
+	}
+	var appErr *quic.ApplicationError
+	if errors.As(err, &appErr) && appErr.Remote && appErr.ErrorCode == 0 {
+		return &websocket.CloseError{Code: websocket.CloseNormalClosure, Text: appErr.ErrorMessage}
+	}
+	if errors.Is(err, io.EOF) {
+		return &websocket.CloseError{Code: websocket.CloseAbnormalClosure, Text: io.ErrUnexpectedEOF.Error()}
+	}
+	return err
+}
+
+// WriteMessage writes a text or binary message. WriteMessage may be called
+// concurrently with WriteControl, but not with itself.
+func (c *QUICConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != websocket.TextMessage && messageType != websocket.BinaryMessage {
+		return fmt.Errorf("invalid message type %d", messageType)
+	}
+	c.writeMtx.Lock()
+	deadline := c.writeDeadline
+	c.writeMtx.Unlock()
+	return c.writeFrame(messageType, data, deadline)
+}
+
+// WriteControl writes a close, ping or pong message with the deadline.
+func (c *QUICConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	switch messageType {
+	case websocket.CloseMessage, websocket.PingMessage, websocket.PongMessage:
+	default:
+		return fmt.Errorf("invalid control message type %d", messageType)
+	}
+	return c.writeFrame(messageType, data, deadline)
+}
+
+func (c *QUICConn) writeFrame(frameType int, data []byte, deadline time.Time) error {
+	if uint64(len(data)) > 1<<32-1 {
+		return fmt.Errorf("message too large")
+	}
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	if c.closed.Load() {
+		return &net.OpError{Op: "write", Net: "quic", Addr: c.RemoteAddr(), Err: net.ErrClosed}
+	}
+	if c.closeSent {
+		return websocket.ErrCloseSent
+	}
+	if err := c.stream.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	b := make([]byte, quicFrameHeaderSize, quicFrameHeaderSize+len(data))
+	b[0] = byte(frameType)
+	binary.BigEndian.PutUint32(b[1:], uint32(len(data)))
+	if _, err := c.stream.Write(append(b, data...)); err != nil {
+		return err
+	}
+	if frameType == websocket.CloseMessage {
+		c.closeSent = true
+	}
+	return nil
+}
+
+// Close closes the connection. Reads fail immediately, but the QUIC
+// connection is not closed until the peer closes it or quicCloseWait passes,
+// so that messages already written, such as a close message, can be delivered.
+// If the peer sent a close message, the QUIC connection is closed immediately.
+func (c *QUICConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		c.stream.CancelRead(0)
+		if c.peerClosed.Load() {
+			c.conn.CloseWithError(0, "")
+			return
+		}
+		go func() {
+			c.writeMtx.Lock()
+			c.stream.Close()
+			c.writeMtx.Unlock()
+			select {
+			case <-c.conn.Context().Done():
+			case <-time.After(quicCloseWait):
+			}
+			c.conn.CloseWithError(0, "")
+		}()
+	})
+	return nil
+}
@@ -0,0 +1,282 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package ws
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/decred/dcrd/certgen"
+	"github.com/gorilla/websocket"
+)
+
+// tQUICTLS generates a server TLS config and a client TLS config that trusts
+// it.
+func tQUICTLS(t *testing.T) (srvCfg, clientCfg *tls.Config) {
+	t.Helper()
+	certPEM, keyPEM, err := certgen.NewTLSCertPair(elliptic.P256(), "dcrdex test",
+		time.Now().Add(time.Hour), []string{"localhost"})
+	if err != nil {
+		t.Fatalf("NewTLSCertPair error: %v", err)
+	}
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair error: %v", err)
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM(certPEM)
+	return &tls.Config{Certificates: []tls.Certificate{keyPair}},
+		&tls.Config{RootCAs: rootCAs, ServerName: "localhost"}
+}
+
+func TestQUICConn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srvTLS, clientTLS := tQUICTLS(t)
+	ln, err := ListenQUIC("udp4", "127.0.0.1:0", srvTLS)
+	if err != nil {
+		t.Fatalf("ListenQUIC error: %v", err)
+	}
+	defer ln.Close()
+	addr := ln.Addr().String()
+
+	// The server accepts clients with the right header, and echos their
+	// messages.
+	type accepted struct {
+		conn *QUICConn
+		err  error
+	}
+	acceptC := make(chan *accepted, 1)
+	var rejectEarly atomic.Bool
+	go func() {
+		for {
+			qc, err := ln.Accept(ctx)
+			if err != nil {
+				return
+			}
+			if rejectEarly.Load() {
+				RejectQUIC(qc, http.StatusServiceUnavailable, "busy")
+				acceptC <- &accepted{err: errors.New("rejected")}
+				continue
+			}
+			conn, header, err := AcceptQUIC(ctx, qc, time.Minute)
+			if err != nil {
+				acceptC <- &accepted{err: err}
+				continue
+			}
+			if header.Get("X-Test") != "ok" {
+				conn.Reject(http.StatusUnauthorized, "bad header")
+				acceptC <- &accepted{err: errors.New("rejected")}
+				continue
+			}
+			acceptC <- &accepted{conn: conn, err: conn.Accept()}
+		}
+	}()
+	accept := func() *QUICConn {
+		t.Helper()
+		select {
+		case a := <-acceptC:
+			if a.err != nil {
+				t.Fatalf("accept error: %v", a.err)
+			}
+			return a.conn
+		case <-time.After(5 * time.Second):
+			t.Fatalf("connection not accepted")
+		}
+		return nil
+	}
+
+	header := http.Header{"X-Test": []string{"ok"}}
+	for _, test := range []struct {
+		protocols []string
+		wantEnc   msgjson.Encoding
+	}{
+		{[]string{QUICProtocolJSON}, msgjson.JSONEncoding},
+		{[]string{QUICProtocolCBOR, QUICProtocolJSON}, msgjson.CBOREncoding},
+	} {
+		client, err := DialQUIC(ctx, addr, clientTLS, test.protocols, header)
+		if err != nil {
+			t.Fatalf("DialQUIC error: %v", err)
+		}
+		srv := accept()
+		for _, c := range []*QUICConn{client, srv} {
+			if enc := msgjson.EncodingFromSubprotocol(c.Subprotocol()); enc != test.wantEnc {
+				t.Fatalf("wrong encoding %s, wanted %s", enc, test.wantEnc)
+			}
+		}
+		client.Close()
+		srv.Close()
+	}
+
+	// Messages and pings.
+	client, err := DialQUIC(ctx, addr, clientTLS, []string{QUICProtocolJSON}, header)
+	if err != nil {
+		t.Fatalf("DialQUIC error: %v", err)
+	}
+	srv := accept()
+	pongC := make(chan string, 1)
+	srv.SetPongHandler(func(appData string) error {
+		pongC <- appData
+		return nil
+	})
+	if err := srv.WriteControl(websocket.PingMessage, []byte("ping"), time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("ping error: %v", err)
+	}
+	if err := srv.WriteMessage(websocket.BinaryMessage, []byte{1, 2, 3}); err != nil {
+		t.Fatalf("WriteMessage error: %v", err)
+	}
+	// The client's default ping handler responds before the message is
+	// returned.
+	frameType, b, err := client.ReadMessage()
+	if err != nil || frameType != websocket.BinaryMessage || len(b) != 3 || b[2] != 3 {
+		t.Fatalf("wrong message: %d, %v, %v", frameType, b, err)
+	}
+	if err := client.WriteMessage(websocket.TextMessage, []byte("hi")); err != nil {
+		t.Fatalf("WriteMessage error: %v", err)
+	}
+	if frameType, b, err = srv.ReadMessage(); err != nil || frameType != websocket.TextMessage || string(b) != "hi" {
+		t.Fatalf("wrong message: %d, %q, %v", frameType, b, err)
+	}
+	if appData := <-pongC; appData != "ping" {
+		t.Fatalf("wrong pong data %q", appData)
+	}
+
+	// A message over the read limit closes the connection.
+	srv.SetReadLimit(4)
+	if err := client.WriteMessage(websocket.TextMessage, []byte("too long")); err != nil {
+		t.Fatalf("WriteMessage error: %v", err)
+	}
+	if _, _, err = srv.ReadMessage(); !errors.Is(err, websocket.ErrReadLimit) {
+		t.Fatalf("wrong error for long message: %v", err)
+	}
+	if _, _, err = client.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("wrong close error: %v", err)
+	}
+	client.Close()
+
+	// A close message.
+	client, err = DialQUIC(ctx, addr, clientTLS, []string{QUICProtocolJSON}, header)
+	if err != nil {
+		t.Fatalf("DialQUIC error: %v", err)
+	}
+	srv = accept()
+	err = client.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, "bye"), time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	if err := client.WriteMessage(websocket.TextMessage, []byte("hi")); !errors.Is(err, websocket.ErrCloseSent) {
+		t.Fatalf("wrong error writing after close: %v", err)
+	}
+	client.Close()
+	if _, _, err = srv.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Fatalf("wrong close error: %v", err)
+	}
+	srv.Close()
+
+	// Rejected.
+	_, err = DialQUIC(ctx, addr, clientTLS, []string{QUICProtocolJSON}, nil)
+	if !errors.Is(err, ErrQUICRejected) {
+		t.Fatalf("wrong error for rejected connection: %v", err)
+	}
+	<-acceptC
+
+	// Rejected before the hello is read.
+	rejectEarly.Store(true)
+	_, err = DialQUIC(ctx, addr, clientTLS, []string{QUICProtocolJSON}, header)
+	if !errors.Is(err, ErrQUICRejected) {
+		t.Fatalf("wrong error for connection rejected before the hello: %v", err)
+	}
+	<-acceptC
+}
+
+func TestQUICLink(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srvTLS, clientTLS := tQUICTLS(t)
+	ln, err := ListenQUIC("udp4", "127.0.0.1:0", srvTLS)
+	if err != nil {
+		t.Fatalf("ListenQUIC error: %v", err)
+	}
+	defer ln.Close()
+
+	// A WSLink on each side of the QUIC connection.
+	linkC := make(chan *WSLink, 1)
+	go func() {
+		qc, err := ln.Accept(ctx)
+		if err != nil {
+			return
+		}
+		conn, _, err := AcceptQUIC(ctx, qc, time.Minute)
+		if err != nil {
+			t.Errorf("AcceptQUIC error: %v", err)
+			return
+		}
+		conn.Accept()
+		var link *WSLink
+		link = NewWSLink("client", conn, time.Minute, func(msg *msgjson.Message) *msgjson.Error {
+			resp, _ := msgjson.NewResponse(msg.ID, msg.Route, nil)
+			link.Send(resp)
+			return nil
+		}, dex.Disabled)
+		linkC <- link
+	}()
+
+	conn, err := DialQUIC(ctx, ln.Addr().String(), clientTLS, []string{QUICProtocolCBOR}, nil)
+	if err != nil {
+		t.Fatalf("DialQUIC error: %v", err)
+	}
+	respC := make(chan *msgjson.Message, 1)
+	client := NewWSLink("server", conn, time.Minute, func(msg *msgjson.Message) *msgjson.Error {
+		respC <- msg
+		return nil
+	}, dex.Disabled)
+	if client.Encoding() != msgjson.CBOREncoding {
+		t.Fatalf("wrong encoding %s", client.Encoding())
+	}
+	clientWG, err := client.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	srv := <-linkC
+	srvWG, err := srv.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+
+	req, _ := msgjson.NewRequest(1, "echo", nil)
+	if err := client.SendNow(req); err != nil {
+		t.Fatalf("SendNow error: %v", err)
+	}
+	select {
+	case resp := <-respC:
+		var route string
+		if err := resp.UnmarshalResult(&route); err != nil || route != "echo" {
+			t.Fatalf("wrong response %q: %v", route, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no response")
+	}
+
+	// When one side disconnects, the other side's link goes down.
+	client.Disconnect()
+	clientWG.Wait()
+	select {
+	case <-srv.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server link not down")
+	}
+	srvWG.Wait()
+}
@@ -5,7 +5,6 @@ package ws
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -35,8 +34,11 @@ const (
 )
 
 // websocket.Upgrader is the preferred method of upgrading a request to a
-// websocket connection.
-var upgrader = websocket.Upgrader{}
+// websocket connection. Clients that request the CBOR subprotocol will have it
+// selected, and all other clients will use JSON.
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{msgjson.CBORSubprotocol},
+}
 
 // Connection represents a websocket connection to a remote peer. In practice,
 // it is satisfied by *websocket.Conn. For testing, a stub can be used.
@@ -78,6 +80,8 @@ type WSLink struct {
 	handler func(*msgjson.Message) *msgjson.Error
 	// pingPeriod is how often to ping the peer.
 	pingPeriod time.Duration
+	// encoding is the negotiated message encoding for outgoing messages.
+	encoding msgjson.Encoding
 
 	RawHandler func([]byte)
 }
@@ -87,8 +91,14 @@ type sendData struct {
 	ret  chan<- error
 }
 
-// NewWSLink is a constructor for a new WSLink.
+// NewWSLink is a constructor for a new WSLink. If the Connection reports a
+// negotiated subprotocol (e.g. *websocket.Conn), it determines the encoding of
+// outgoing messages.
 func NewWSLink(addr string, conn Connection, pingPeriod time.Duration, handler func(*msgjson.Message) *msgjson.Error, logger dex.Logger) *WSLink {
+	encoding := msgjson.JSONEncoding
+	if sp, ok := conn.(interface{ Subprotocol() string }); ok {
+		encoding = msgjson.EncodingFromSubprotocol(sp.Subprotocol())
+	}
 	return &WSLink{
 		addr:       addr,
 		log:        logger,
@@ -96,9 +106,16 @@ func NewWSLink(addr string, conn Connection, pingPeriod time.Duration, handler f
 		outChan:    make(chan *sendData, outBufferSize),
 		pingPeriod: pingPeriod,
 		handler:    handler,
+		encoding:   encoding,
 	}
 }
 
+// Encoding is the negotiated encoding of messages sent on the link. Raw
+// messages given to SendRaw must have this encoding.
+func (c *WSLink) Encoding() msgjson.Encoding {
+	return c.encoding
+}
+
 // Send sends the passed Message to the websocket peer. The actual writing of
 // the message on the peer's link occurs asynchronously. As such, a nil error
 // only indicates that the link is believed to be up and the message was
@@ -107,9 +124,10 @@ func (c *WSLink) Send(msg *msgjson.Message) error {
 	return c.send(msg, nil)
 }
 
-// SendRaw sends the passed bytes to the websocket peer. The actual writing of
-// the message on the peer's link occurs asynchronously. As such, a nil error
-// only indicates that the link is believed to be up.
+// SendRaw sends the passed bytes to the websocket peer. The bytes should be
+// encoded with the link's Encoding. The actual writing of the message on the
+// peer's link occurs asynchronously. As such, a nil error only indicates that
+// the link is believed to be up.
 func (c *WSLink) SendRaw(b []byte) error {
 	if c.Off() {
 		return ErrPeerDisconnected
@@ -145,7 +163,7 @@ func (c *WSLink) send(msg *msgjson.Message, writeErr chan<- error) error {
 	if c.Off() {
 		return ErrPeerDisconnected
 	}
-	b, err := json.Marshal(msg)
+	b, err := msg.Encode(c.encoding)
 	if err != nil {
 		return err
 	}
@@ -256,7 +274,7 @@ out:
 			break out
 		}
 		// Block until a message is received or an error occurs.
-		_, msgBytes, err := c.conn.ReadMessage()
+		frameType, msgBytes, err := c.conn.ReadMessage()
 		if err != nil {
 			// Only log the error if it is unexpected (not a disconnect).
 			if websocket.IsCloseError(err, websocket.CloseGoingAway,
@@ -281,9 +299,9 @@ out:
 
 		// Attempt to unmarshal the request. Only requests that successfully decode
 		// will be accepted by the server, though failure to decode does not force
-		// a disconnect.
+		// a disconnect. Binary frames are CBOR-encoded messages.
 		msg := new(msgjson.Message)
-		err = json.Unmarshal(msgBytes, msg)
+		err = msg.Decode(msgBytes, frameEncoding(frameType))
 		if err != nil {
 			c.SendError(1, msgjson.NewError(msgjson.RPCParseError, "failed to parse message"))
 			continue
@@ -337,7 +355,7 @@ func (c *WSLink) outHandler(ctx context.Context) {
 			return
 		}
 		c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-		err := c.conn.WriteMessage(websocket.TextMessage, sd.data)
+		err := c.conn.WriteMessage(frameType(c.encoding), sd.data)
 		if err != nil {
 			lostCount++
 			relayError(sd.ret, err)
@@ -474,6 +492,22 @@ out:
 	}
 }
 
+// frameType is the websocket frame type for messages with the encoding.
+func frameType(enc msgjson.Encoding) int {
+	if enc == msgjson.CBOREncoding {
+		return websocket.BinaryMessage
+	}
+	return websocket.TextMessage
+}
+
+// frameEncoding is the message encoding for the websocket frame type.
+func frameEncoding(frameType int) msgjson.Encoding {
+	if frameType == websocket.BinaryMessage {
+		return msgjson.CBOREncoding
+	}
+	return msgjson.JSONEncoding
+}
+
 // Off will return true if the link has disconnected.
 func (c *WSLink) Off() bool {
 	return atomic.LoadUint32(&c.on) == 0
@@ -495,7 +529,13 @@ func (c *WSLink) SetReadLimit(limit int64) {
 // Connection. If the upgrade fails, a reply will be sent with an appropriate
 // error code.
 func NewConnection(w http.ResponseWriter, r *http.Request, readTimeout time.Duration) (Connection, error) {
-	ws, err := upgrader.Upgrade(w, r, nil)
+	return NewConnectionWithHeader(w, r, readTimeout, nil)
+}
+
+// NewConnectionWithHeader is like NewConnection, but includes the headers in
+// the response to a successful upgrade, e.g. QUICPortHeader.
+func NewConnectionWithHeader(w http.ResponseWriter, r *http.Request, readTimeout time.Duration, responseHeader http.Header) (Connection, error) {
+	ws, err := upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		var hsErr websocket.HandshakeError
 		if errors.As(err, &hsErr) {
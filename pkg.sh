@@ -6,6 +6,8 @@ set -e
 VER="1.0.1-pre" # pre, beta, rc1, etc.
 META= # "release"
 
+# Release builds are pure Go, so they do not include the SQLite database
+# backend, which requires cgo and the sqlite build tag. See build.sh.
 export CGO_ENABLED=0
 export GOWORK=off
 
@@ -36,6 +36,10 @@ done
 
 cd "$dir"
 
+# The SQLite database backend and its migration tool are only built with the
+# sqlite tag. The driver requires cgo.
+env GORACE="halt_on_error=1" go test -race -short -tags sqlite ./client/db/sqlite ./client/cmd/sqlitemigrate
+
 # Print missing Core notification translations.
 go run ./client/core/localetest/main.go
 
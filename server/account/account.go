@@ -188,3 +188,24 @@ func (r *Reputation) EffectiveTier() int64 {
 	tier := r.BondedTier - int64(r.Penalties)
 	return tier
 }
+
+// ParcelLimits are the parameters of the users' parcel limits. A user's parcel
+// limit starts at PerTier parcels per tier, and scales up with the user's score
+// to ScoreMultiplier times that at a score of MaxScore.
+type ParcelLimits struct {
+	PerTier         int64 `json:"perTier"`
+	ScoreMultiplier int64 `json:"scoreMultiplier"`
+	MaxScore        int32 `json:"maxScore"`
+}
+
+// ParcelLimit computes the user's score-scaled parcel limit.
+func (l *ParcelLimits) ParcelLimit(tier int64, score int32) uint32 {
+	lowerLimit := tier * l.PerTier
+	upperLimit := lowerLimit * l.ScoreMultiplier
+	limitRange := upperLimit - lowerLimit
+	var scaleFactor float64
+	if score > 0 {
+		scaleFactor = math.Min(float64(score)/float64(l.MaxScore), 1)
+	}
+	return uint32(lowerLimit) + uint32(math.Round(scaleFactor*float64(limitRange)))
+}
@@ -19,6 +19,8 @@ import (
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/server/db"
 	dexsrv "decred.org/dcrdex/server/dex"
 	"decred.org/dcrdex/server/market"
 	"github.com/go-chi/chi/v5"
@@ -223,6 +225,24 @@ func (s *Server) apiMarketOrderBook(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, res)
 }
 
+// apiMarketBookSnapshot is the handler for the
+// '/market/{marketName}/booksnapshot' API request. Unlike the orderbook
+// request, the book is the book router's copy, with the sequence number of the
+// last change and a digest of the orders, for verifying mirrored books.
+func (s *Server) apiMarketBookSnapshot(w http.ResponseWriter, r *http.Request) {
+	mkt := strings.ToLower(chi.URLParam(r, marketNameKey))
+	if found, _ := s.core.MarketRunning(mkt); !found {
+		http.Error(w, fmt.Sprintf("unknown market %q", mkt), http.StatusBadRequest)
+		return
+	}
+	snap, err := s.core.BookSnapshot(mkt)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to obtain book snapshot: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, snap)
+}
+
 // handler for route '/market/{marketName}/epochorders' API request.
 func (s *Server) apiMarketEpochOrders(w http.ResponseWriter, r *http.Request) {
 	mkt := strings.ToLower(chi.URLParam(r, marketNameKey))
@@ -451,6 +471,53 @@ func (s *Server) apiEnableDataAPI(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, msg)
 }
 
+// apiParcelLimits is the handler for the '/parcellimits' API request.
+func (s *Server) apiParcelLimits(w http.ResponseWriter, _ *http.Request) {
+	limits, changes := s.core.ParcelLimits()
+	writeJSON(w, &ParcelLimitsResult{
+		Limits:  limits,
+		Changes: changes,
+	})
+}
+
+// apiSetParcelLimits is the handler for the
+// '/setparcellimits?pertier=N&multiplier=M&maxscore=S&reason=R' API request.
+// Parameters that are not specified keep their current values. The new
+// limits apply immediately to all users.
+func (s *Server) apiSetParcelLimits(w http.ResponseWriter, r *http.Request) {
+	limits, _ := s.core.ParcelLimits()
+	query := r.URL.Query()
+	if perTierStr := query.Get(perTierKey); perTierStr != "" {
+		perTier, err := strconv.ParseInt(perTierStr, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error parsing pertier: %v", err), http.StatusBadRequest)
+			return
+		}
+		limits.PerTier = perTier
+	}
+	if multiplierStr := query.Get(multiplierKey); multiplierStr != "" {
+		multiplier, err := strconv.ParseInt(multiplierStr, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error parsing multiplier: %v", err), http.StatusBadRequest)
+			return
+		}
+		limits.ScoreMultiplier = multiplier
+	}
+	if maxScoreStr := query.Get(maxScoreKey); maxScoreStr != "" {
+		maxScore, err := strconv.ParseInt(maxScoreStr, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error parsing maxscore: %v", err), http.StatusBadRequest)
+			return
+		}
+		limits.MaxScore = int32(maxScore)
+	}
+	if err := s.core.SetParcelLimits(limits, query.Get(reasonKey)); err != nil {
+		http.Error(w, fmt.Sprintf("error setting parcel limits: %v", err), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, limits)
+}
+
 // apiAccountInfo is the handler for the '/account/{account id}' API request.
 func (s *Server) apiAccountInfo(w http.ResponseWriter, r *http.Request) {
 	acctIDStr := chi.URLParam(r, accountIDKey)
@@ -536,6 +603,107 @@ func decodeAcctID(acctIDStr string) (account.AccountID, error) {
 	return acctID, nil
 }
 
+// decodeAllowlistAcct decodes an account ID or an account pubkey, from which
+// the account ID is derived.
+func decodeAllowlistAcct(acctStr string) (account.AccountID, error) {
+	if len(acctStr) != account.PubKeySize*2 {
+		return decodeAcctID(acctStr)
+	}
+	pubKey, err := hex.DecodeString(acctStr)
+	if err != nil {
+		return account.AccountID{}, fmt.Errorf("could not decode account pubkey: %w", err)
+	}
+	acct, err := account.NewAccountFromPubKey(pubKey)
+	if err != nil {
+		return account.AccountID{}, fmt.Errorf("invalid account pubkey: %w", err)
+	}
+	return acct.ID, nil
+}
+
+// apiAllowlist is the handler for the '/allowlist' API request.
+func (s *Server) apiAllowlist(w http.ResponseWriter, _ *http.Request) {
+	accts, err := s.core.AllowedAccounts()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error retrieving allowlist: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, accts)
+}
+
+// apiAllowAccount is the handler for the
+// '/allowlist/add/{account ID or pubkey}?note=NOTE' API request.
+func (s *Server) apiAllowAccount(w http.ResponseWriter, r *http.Request) {
+	acctID, err := decodeAllowlistAcct(chi.URLParam(r, accountIDKey))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.core.AllowAccount(acctID, r.URL.Query().Get(noteKey)); err != nil {
+		http.Error(w, fmt.Sprintf("error adding account to allowlist: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, fmt.Sprintf("account %v allowed", acctID))
+}
+
+// apiDisallowAccount is the handler for the
+// '/allowlist/remove/{account ID or pubkey}' API request.
+func (s *Server) apiDisallowAccount(w http.ResponseWriter, r *http.Request) {
+	acctID, err := decodeAllowlistAcct(chi.URLParam(r, accountIDKey))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.core.DisallowAccount(acctID); err != nil {
+		http.Error(w, fmt.Sprintf("error removing account from allowlist: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, fmt.Sprintf("account %v removed from allowlist", acctID))
+}
+
+// apiDataAPIKeys is the handler for the '/dataapikeys' API request.
+func (s *Server) apiDataAPIKeys(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, &struct {
+		Tiers []*comms.DataTier        `json:"tiers"`
+		Keys  []*comms.DataAPIKeyUsage `json:"keys"`
+	}{
+		Tiers: comms.SortedDataTiers(),
+		Keys:  s.core.DataAPIKeys(),
+	})
+}
+
+// apiIssueDataAPIKey is the handler for the
+// '/dataapikeys/issue/{tier}?note=NOTE' API request.
+func (s *Server) apiIssueDataAPIKey(w http.ResponseWriter, r *http.Request) {
+	tier := chi.URLParam(r, tierKey)
+	if comms.DataTiers[tier] == nil {
+		http.Error(w, fmt.Sprintf("unknown tier %q", tier), http.StatusBadRequest)
+		return
+	}
+	key, dataKey, err := s.core.IssueDataAPIKey(tier, r.URL.Query().Get(noteKey))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error issuing data API key: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, &struct {
+		Key string `json:"key"`
+		*db.DataAPIKey
+	}{
+		Key:        key,
+		DataAPIKey: dataKey,
+	})
+}
+
+// apiRevokeDataAPIKey is the handler for the '/dataapikeys/revoke/{keyID}' API
+// request.
+func (s *Server) apiRevokeDataAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, keyIDKey)
+	if err := s.core.RevokeDataAPIKey(id); err != nil {
+		http.Error(w, fmt.Sprintf("error revoking data API key: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, fmt.Sprintf("data API key %s revoked", id))
+}
+
 // apiForgiveMatchFail is the handler for the '/account/{accountID}/forgive_match/{matchID}' API request.
 func (s *Server) apiForgiveMatchFail(w http.ResponseWriter, r *http.Request) {
 	acctIDStr := chi.URLParam(r, accountIDKey)
@@ -0,0 +1,88 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// eventsWriteWait is the time allowed to write an event or a ping to the
+	// events websocket.
+	eventsWriteWait = time.Second * 10
+	// eventsPongWait is the time allowed to read the next pong from the
+	// events websocket.
+	eventsPongWait = time.Minute
+	// eventsPingPeriod is how often the events websocket is pinged. It must
+	// be less than eventsPongWait.
+	eventsPingPeriod = eventsPongWait * 9 / 10
+)
+
+var eventsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+}
+
+// apiEvents is the handler for the '/events' API request. The connection is
+// upgraded to a websocket on which the live operational events of the DEX are
+// streamed as JSON until the client disconnects. Messages from the client are
+// ignored.
+func (s *Server) apiEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := eventsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Errorf("Error upgrading events connection from %s: %v", r.RemoteAddr, err)
+		return
+	}
+	defer conn.Close()
+
+	// The deadlines that the http.Server set for the request no longer apply
+	// to the hijacked connection.
+	conn.SetReadDeadline(time.Now().Add(eventsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(eventsPongWait))
+	})
+
+	events, unsubscribe := s.core.AdminEvents()
+	defer unsubscribe()
+
+	// Read until the client disconnects, to process control messages.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	log.Infof("Streaming admin events to %s", r.RemoteAddr)
+	defer log.Infof("Stopped streaming admin events to %s", r.RemoteAddr)
+
+	ticker := time.NewTicker(eventsPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(eventsWriteWait))
+			if err := conn.WriteJSON(ev); err != nil {
+				log.Debugf("Error writing admin event to %s: %v", r.RemoteAddr, err)
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(eventsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/asset"
 	"decred.org/dcrdex/server/auth"
+	"decred.org/dcrdex/server/comms"
 	"decred.org/dcrdex/server/db"
 	dexsrv "decred.org/dcrdex/server/dex"
 	"decred.org/dcrdex/server/market"
@@ -49,6 +50,13 @@ const (
 	nKey               = "n"
 	daysKey            = "days"
 	strengthKey        = "strength"
+	perTierKey         = "pertier"
+	multiplierKey      = "multiplier"
+	maxScoreKey        = "maxscore"
+	reasonKey          = "reason"
+	noteKey            = "note"
+	tierKey            = "tier"
+	keyIDKey           = "keyid"
 )
 
 var (
@@ -73,10 +81,20 @@ type SvrCore interface {
 	ForgiveMatchFail(aid account.AccountID, mid order.MatchID) (forgiven, unbanned bool, err error)
 	AccountMatchOutcomesN(user account.AccountID, n int) ([]*auth.MatchOutcome, error)
 	BookOrders(base, quote uint32) (orders []*order.LimitOrder, err error)
+	BookSnapshot(mktName string) (*msgjson.BookSnapshot, error)
 	EpochOrders(base, quote uint32) (orders []order.Order, err error)
 	MarketMatchesStreaming(base, quote uint32, includeInactive bool, N int64, f func(*dexsrv.MatchData) error) (int, error)
 	EnableDataAPI(yes bool)
 	CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error)
+	AdminEvents() (<-chan *dexsrv.AdminEvent, func())
+	ParcelLimits() (*account.ParcelLimits, []*auth.ParcelLimitsChange)
+	SetParcelLimits(limits *account.ParcelLimits, reason string) error
+	AllowedAccounts() ([]*db.AllowedAccount, error)
+	AllowAccount(aid account.AccountID, note string) error
+	DisallowAccount(aid account.AccountID) error
+	IssueDataAPIKey(tier, note string) (string, *db.DataAPIKey, error)
+	RevokeDataAPIKey(id string) error
+	DataAPIKeys() []*comms.DataAPIKeyUsage
 }
 
 // Server is a multi-client https server.
@@ -167,12 +185,26 @@ func NewServer(cfg *SrvConfig) (*Server, error) {
 		r.Route("/market/{"+marketNameKey+"}", func(rm chi.Router) {
 			rm.Get("/", s.apiMarketInfo)
 			rm.Get("/orderbook", s.apiMarketOrderBook)
+			rm.Get("/booksnapshot", s.apiMarketBookSnapshot)
 			rm.Get("/epochorders", s.apiMarketEpochOrders)
 			rm.Get("/matches", s.apiMarketMatches)
 			rm.Get("/suspend", s.apiSuspend)
 			rm.Get("/resume", s.apiResume)
 		})
 		r.Get("/prepaybonds", s.prepayBonds)
+		r.Get("/parcellimits", s.apiParcelLimits)
+		r.Get("/setparcellimits", s.apiSetParcelLimits)
+		r.Route("/allowlist", func(rm chi.Router) {
+			rm.Get("/", s.apiAllowlist)
+			rm.Get("/add/{"+accountIDKey+"}", s.apiAllowAccount)
+			rm.Get("/remove/{"+accountIDKey+"}", s.apiDisallowAccount)
+		})
+		r.Route("/dataapikeys", func(rm chi.Router) {
+			rm.Get("/", s.apiDataAPIKeys)
+			rm.Get("/issue/{"+tierKey+"}", s.apiIssueDataAPIKey)
+			rm.Get("/revoke/{"+keyIDKey+"}", s.apiRevokeDataAPIKey)
+		})
+		r.Get("/events", s.apiEvents)
 	})
 
 	return s, nil
@@ -24,17 +24,21 @@ import (
 	"time"
 
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/asset"
 	"decred.org/dcrdex/server/auth"
+	"decred.org/dcrdex/server/comms"
 	"decred.org/dcrdex/server/db"
 	dexsrv "decred.org/dcrdex/server/dex"
 	"decred.org/dcrdex/server/market"
 	"github.com/decred/dcrd/certgen"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/slog"
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
 )
 
 func init() {
@@ -63,11 +67,24 @@ type TCore struct {
 	unbanErr         error
 	book             []*order.LimitOrder
 	bookErr          error
+	snapshot         *msgjson.BookSnapshot
+	snapshotErr      error
 	epochOrders      []order.Order
 	epochOrdersErr   error
 	marketMatches    []*dexsrv.MatchData
 	marketMatchesErr error
 	dataEnabled      uint32
+	events           chan *dexsrv.AdminEvent
+	parcelLimits     *account.ParcelLimits
+	setLimitsReason  string
+	setLimitsErr     error
+	allowed          map[account.AccountID]string
+	allowErr         error
+	dataKeys         []*comms.DataAPIKeyUsage
+	issuedTier       string
+	issuedNote       string
+	revokedKey       string
+	revokeErr        error
 }
 
 func (c *TCore) ConfigMsg() json.RawMessage { return nil }
@@ -131,6 +148,10 @@ func (c *TCore) BookOrders(_, _ uint32) ([]*order.LimitOrder, error) {
 	return c.book, c.bookErr
 }
 
+func (c *TCore) BookSnapshot(string) (*msgjson.BookSnapshot, error) {
+	return c.snapshot, c.snapshotErr
+}
+
 func (c *TCore) EpochOrders(_, _ uint32) ([]order.Order, error) {
 	return c.epochOrders, c.epochOrdersErr
 }
@@ -214,6 +235,56 @@ func (c *TCore) Unban(_ account.AccountID) error {
 func (c *TCore) ForgiveMatchFail(_ account.AccountID, _ order.MatchID) (bool, bool, error) {
 	return false, false, nil // TODO: tests
 }
+func (c *TCore) AdminEvents() (<-chan *dexsrv.AdminEvent, func()) {
+	return c.events, func() {}
+}
+func (c *TCore) ParcelLimits() (*account.ParcelLimits, []*auth.ParcelLimitsChange) {
+	l := *c.parcelLimits
+	return &l, nil
+}
+func (c *TCore) SetParcelLimits(limits *account.ParcelLimits, reason string) error {
+	if c.setLimitsErr != nil {
+		return c.setLimitsErr
+	}
+	c.parcelLimits = limits
+	c.setLimitsReason = reason
+	return nil
+}
+func (c *TCore) AllowedAccounts() ([]*db.AllowedAccount, error) {
+	if c.allowErr != nil {
+		return nil, c.allowErr
+	}
+	accts := make([]*db.AllowedAccount, 0, len(c.allowed))
+	for aid, note := range c.allowed {
+		accts = append(accts, &db.AllowedAccount{AccountID: aid, Note: note})
+	}
+	return accts, nil
+}
+func (c *TCore) AllowAccount(aid account.AccountID, note string) error {
+	if c.allowErr != nil {
+		return c.allowErr
+	}
+	c.allowed[aid] = note
+	return nil
+}
+func (c *TCore) DisallowAccount(aid account.AccountID) error {
+	if c.allowErr != nil {
+		return c.allowErr
+	}
+	delete(c.allowed, aid)
+	return nil
+}
+func (c *TCore) IssueDataAPIKey(tier, note string) (string, *db.DataAPIKey, error) {
+	c.issuedTier, c.issuedNote = tier, note
+	return "abcd", &db.DataAPIKey{ID: "01", Tier: tier, Note: note, Stamp: 1}, nil
+}
+func (c *TCore) RevokeDataAPIKey(id string) error {
+	c.revokedKey = id
+	return c.revokeErr
+}
+func (c *TCore) DataAPIKeys() []*comms.DataAPIKeyUsage {
+	return c.dataKeys
+}
 func (c *TCore) CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error) {
 	return nil, nil
 }
@@ -598,6 +669,68 @@ func TestMarketOrderBook(t *testing.T) {
 	}
 }
 
+func TestMarketBookSnapshot(t *testing.T) {
+	core := new(TCore)
+	core.markets = map[string]*TMarket{"dcr_btc": {running: true}}
+	srv := &Server{
+		core: core,
+	}
+	mux := chi.NewRouter()
+	mux.Get("/market/{"+marketNameKey+"}/booksnapshot", srv.apiMarketBookSnapshot)
+	book := msgjson.OrderBook{
+		MarketID: "dcr_btc",
+		Seq:      10,
+		Orders: []*msgjson.BookOrderNote{{
+			OrderNote: msgjson.OrderNote{OrderID: encode.RandomBytes(32)},
+			TradeNote: msgjson.TradeNote{Side: msgjson.SellOrderNum, Quantity: 1e8, Rate: 1e6},
+		}},
+	}
+	snap := &msgjson.BookSnapshot{
+		OrderBook: book,
+		Digest:    book.Digest(),
+	}
+	tests := []struct {
+		name, mkt   string
+		snapshotErr error
+		wantCode    int
+	}{{
+		name:     "ok",
+		mkt:      "dcr_btc",
+		wantCode: http.StatusOK,
+	}, {
+		name:     "no market",
+		mkt:      "btc_dcr",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:        "core.BookSnapshot error",
+		mkt:         "dcr_btc",
+		snapshotErr: errors.New("market dcr_btc not running"),
+		wantCode:    http.StatusInternalServerError,
+	}}
+	for _, test := range tests {
+		core.snapshot = snap
+		core.snapshotErr = test.snapshotErr
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost/market/"+test.mkt+"/booksnapshot", nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: apiMarketBookSnapshot returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code == http.StatusOK {
+			res := new(msgjson.BookSnapshot)
+			if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+				t.Fatalf("%q: unexpected response %v: %v", test.name, w.Body.String(), err)
+			}
+			if res.Seq != book.Seq || !bytes.Equal(res.Digest, res.OrderBook.Digest()) {
+				t.Fatalf("%q: wrong snapshot %+v", test.name, res)
+			}
+		}
+	}
+}
+
 func TestMarketEpochOrders(t *testing.T) {
 	core := new(TCore)
 	core.markets = make(map[string]*TMarket)
@@ -1374,3 +1507,232 @@ func TestEnableDataAPI(t *testing.T) {
 	}
 
 }
+
+func TestEvents(t *testing.T) {
+	core := &TCore{events: make(chan *dexsrv.AdminEvent, 1)}
+	srv := &Server{core: core}
+
+	mux := chi.NewRouter()
+	mux.Get("/events", srv.apiEvents)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/events", nil)
+	if err != nil {
+		t.Fatalf("error connecting to events websocket: %v", err)
+	}
+	defer conn.Close()
+
+	core.events <- &dexsrv.AdminEvent{
+		Type:  dexsrv.AdminEventEpoch,
+		Stamp: 1,
+		Payload: &dexsrv.EpochEvent{
+			Market:  "dcr_btc",
+			Epoch:   10,
+			Matches: 2,
+		},
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	var ev struct {
+		Type    string             `json:"type"`
+		Stamp   int64              `json:"stamp"`
+		Payload *dexsrv.EpochEvent `json:"payload"`
+	}
+	if err := conn.ReadJSON(&ev); err != nil {
+		t.Fatalf("error reading event: %v", err)
+	}
+	if ev.Type != dexsrv.AdminEventEpoch || ev.Stamp != 1 || ev.Payload == nil ||
+		ev.Payload.Market != "dcr_btc" || ev.Payload.Epoch != 10 || ev.Payload.Matches != 2 {
+		t.Fatalf("wrong event: %+v", ev)
+	}
+
+	// The stream ends when the feed is closed.
+	close(core.events)
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Fatalf("connection not closed after the feed was closed")
+	}
+}
+
+func TestSetParcelLimits(t *testing.T) {
+	core := new(TCore)
+	srv := &Server{core: core}
+	mux := chi.NewRouter()
+	mux.Get("/setparcellimits", srv.apiSetParcelLimits)
+
+	initLimits := account.ParcelLimits{PerTier: 2, ScoreMultiplier: 3, MaxScore: 60}
+	tests := []struct {
+		name, query string
+		setErr      error
+		wantCode    int
+		wantLimits  account.ParcelLimits
+		wantReason  string
+	}{{
+		name:       "all",
+		query:      "?pertier=4&multiplier=2&maxscore=30&reason=more%20liquidity",
+		wantCode:   http.StatusOK,
+		wantLimits: account.ParcelLimits{PerTier: 4, ScoreMultiplier: 2, MaxScore: 30},
+		wantReason: "more liquidity",
+	}, {
+		name:       "unspecified unchanged",
+		query:      "?pertier=5",
+		wantCode:   http.StatusOK,
+		wantLimits: account.ParcelLimits{PerTier: 5, ScoreMultiplier: 3, MaxScore: 60},
+	}, {
+		name:       "bad maxscore",
+		query:      "?maxscore=x",
+		wantCode:   http.StatusBadRequest,
+		wantLimits: initLimits,
+	}, {
+		name:       "rejected",
+		query:      "?pertier=0",
+		setErr:     errors.New("per-tier parcel limit must be positive"),
+		wantCode:   http.StatusBadRequest,
+		wantLimits: initLimits,
+	}}
+	for _, test := range tests {
+		l := initLimits
+		core.parcelLimits = &l
+		core.setLimitsReason = ""
+		core.setLimitsErr = test.setErr
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "https://localhost/setparcellimits"+test.query, nil)
+		r.RemoteAddr = "localhost"
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: apiSetParcelLimits returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if *core.parcelLimits != test.wantLimits {
+			t.Fatalf("%q: wanted limits %+v, got %+v", test.name, test.wantLimits, *core.parcelLimits)
+		}
+		if core.setLimitsReason != test.wantReason {
+			t.Fatalf("%q: wanted reason %q, got %q", test.name, test.wantReason, core.setLimitsReason)
+		}
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	core := &TCore{allowed: make(map[account.AccountID]string)}
+	srv := &Server{core: core}
+	mux := chi.NewRouter()
+	mux.Route("/allowlist", func(rm chi.Router) {
+		rm.Get("/", srv.apiAllowlist)
+		rm.Get("/add/{"+accountIDKey+"}", srv.apiAllowAccount)
+		rm.Get("/remove/{"+accountIDKey+"}", srv.apiDisallowAccount)
+	})
+
+	privKey, _ := secp256k1.GeneratePrivateKey()
+	pubKey := privKey.PubKey().SerializeCompressed()
+	acctID := account.NewID(pubKey)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "https://localhost/allowlist"+path, nil)
+		r.RemoteAddr = "localhost"
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	// Add by pubkey.
+	if w := get("/add/" + hex.EncodeToString(pubKey) + "?note=desk%201"); w.Code != http.StatusOK {
+		t.Fatalf("add by pubkey returned code %d", w.Code)
+	}
+	if note, found := core.allowed[acctID]; !found || note != "desk 1" {
+		t.Fatalf("account not allowed by pubkey")
+	}
+
+	w := get("/")
+	if w.Code != http.StatusOK {
+		t.Fatalf("allowlist returned code %d", w.Code)
+	}
+	var accts []struct {
+		AccountID string `json:"accountid"`
+		Note      string `json:"note"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &accts); err != nil {
+		t.Fatalf("error decoding allowlist: %v", err)
+	}
+	if len(accts) != 1 || accts[0].AccountID != acctID.String() || accts[0].Note != "desk 1" {
+		t.Fatalf("wrong allowlist %+v", accts)
+	}
+
+	// Remove by account ID.
+	if w := get("/remove/" + acctID.String()); w.Code != http.StatusOK {
+		t.Fatalf("remove by account ID returned code %d", w.Code)
+	}
+	if len(core.allowed) != 0 {
+		t.Fatalf("account not removed")
+	}
+
+	if w := get("/add/abcd"); w.Code != http.StatusBadRequest {
+		t.Fatalf("bad account returned code %d", w.Code)
+	}
+	core.allowErr = errors.New("allowlist mode is not enabled")
+	if w := get("/add/" + acctID.String()); w.Code != http.StatusInternalServerError {
+		t.Fatalf("allow error returned code %d", w.Code)
+	}
+}
+
+func TestDataAPIKeys(t *testing.T) {
+	core := &TCore{dataKeys: []*comms.DataAPIKeyUsage{{ID: "01", Tier: "partner", Requests: 5}}}
+	srv := &Server{core: core}
+	mux := chi.NewRouter()
+	mux.Route("/dataapikeys", func(rm chi.Router) {
+		rm.Get("/", srv.apiDataAPIKeys)
+		rm.Get("/issue/{"+tierKey+"}", srv.apiIssueDataAPIKey)
+		rm.Get("/revoke/{"+keyIDKey+"}", srv.apiRevokeDataAPIKey)
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "https://localhost/dataapikeys"+path, nil)
+		r.RemoteAddr = "localhost"
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("/")
+	if w.Code != http.StatusOK {
+		t.Fatalf("dataapikeys returned code %d", w.Code)
+	}
+	var keys struct {
+		Tiers []*comms.DataTier        `json:"tiers"`
+		Keys  []*comms.DataAPIKeyUsage `json:"keys"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &keys); err != nil {
+		t.Fatalf("error decoding data API keys: %v", err)
+	}
+	if len(keys.Tiers) != len(comms.DataTiers) || len(keys.Keys) != 1 || keys.Keys[0].Requests != 5 {
+		t.Fatalf("wrong data API keys %+v", keys)
+	}
+
+	w = get("/issue/partner?note=aggregator%20x")
+	if w.Code != http.StatusOK {
+		t.Fatalf("issue returned code %d", w.Code)
+	}
+	var issued struct {
+		Key  string `json:"key"`
+		ID   string `json:"id"`
+		Tier string `json:"tier"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &issued); err != nil {
+		t.Fatalf("error decoding issued key: %v", err)
+	}
+	if issued.Key != "abcd" || issued.ID != "01" || issued.Tier != "partner" ||
+		core.issuedTier != "partner" || core.issuedNote != "aggregator x" {
+		t.Fatalf("wrong issued key %+v", issued)
+	}
+	if w := get("/issue/platinum"); w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown tier returned code %d", w.Code)
+	}
+
+	if w := get("/revoke/01"); w.Code != http.StatusOK || core.revokedKey != "01" {
+		t.Fatalf("revoke returned code %d", w.Code)
+	}
+	core.revokeErr = errors.New("unknown data API key")
+	if w := get("/revoke/02"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("revoke error returned code %d", w.Code)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/auth"
 )
 
 // AssetPost is the expected structure of the asset POST data.
@@ -113,3 +115,10 @@ type ForgiveResult struct {
 	Unbanned    bool    `json:"unbanned"`
 	ForgiveTime APITime `json:"forgivetime"`
 }
+
+// ParcelLimitsResult is the result of the parcel limits GET. Changes is the log
+// of runtime changes to the parcel limits, oldest first.
+type ParcelLimitsResult struct {
+	Limits  *account.ParcelLimits      `json:"limits"`
+	Changes []*auth.ParcelLimitsChange `json:"changes"`
+}
@@ -43,6 +43,7 @@ type MarketSource interface {
 // after construction but before use.
 type BookSource interface {
 	Book(mktName string) (*msgjson.OrderBook, error)
+	Snapshot(mktName string) (*msgjson.BookSnapshot, error)
 }
 
 type cacheWithStoredTime struct {
@@ -76,6 +77,7 @@ func NewDataAPI(dbSrc DBSource, registerHTTP func(route string, handler comms.HT
 		registerHTTP(msgjson.SpotsRoute, s.handleSpots)
 		registerHTTP(msgjson.CandlesRoute, s.handleCandles)
 		registerHTTP(msgjson.OrderBookRoute, s.handleOrderBook)
+		registerHTTP(msgjson.BookSnapshotRoute, s.handleBookSnapshot)
 	}
 	return s
 }
@@ -261,6 +263,21 @@ func (s *DataAPI) handleOrderBook(thing any) (any, error) {
 	return s.bookSource.Book(mkt)
 }
 
+// handleBookSnapshot implements comms.HTTPHandler for the /booksnapshot
+// endpoints.
+func (s *DataAPI) handleBookSnapshot(thing any) (any, error) {
+	req, ok := thing.(*msgjson.OrderBookSubscription)
+	if !ok {
+		return nil, fmt.Errorf("unparseable booksnapshot request")
+	}
+
+	mkt, err := dex.MarketName(req.Base, req.Quote)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse requested market")
+	}
+	return s.bookSource.Snapshot(mkt)
+}
+
 func init() {
 	for _, s := range candles.BinSizes {
 		dur, err := time.ParseDuration(s)
@@ -4,12 +4,14 @@
 package apidata
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
 	"decred.org/dcrdex/dex/candles"
+	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/server/comms"
 	"decred.org/dcrdex/server/matcher"
@@ -49,6 +51,13 @@ func (bs *TBookSource) Book(mktName string) (*msgjson.OrderBook, error) {
 	return bs.book, nil
 }
 
+func (bs *TBookSource) Snapshot(mktName string) (*msgjson.BookSnapshot, error) {
+	return &msgjson.BookSnapshot{
+		OrderBook: *bs.book,
+		Digest:    bs.book.Digest(),
+	}, nil
+}
+
 type testRig struct {
 	db  *TDBSource
 	api *DataAPI
@@ -195,3 +204,32 @@ func TestOrderBook(t *testing.T) {
 		t.Fatalf("where did this book come from?")
 	}
 }
+
+func TestBookSnapshot(t *testing.T) {
+	rig := newTestRig()
+	book := &msgjson.OrderBook{
+		Seq: 5,
+		Orders: []*msgjson.BookOrderNote{{
+			OrderNote: msgjson.OrderNote{OrderID: encode.RandomBytes(32)},
+			TradeNote: msgjson.TradeNote{Side: 1, Quantity: 1e8, Rate: 2e6},
+		}},
+	}
+	rig.api.SetBookSource(&TBookSource{book})
+	snapI, err := rig.api.handleBookSnapshot(&msgjson.OrderBookSubscription{
+		Base:  42,
+		Quote: 0,
+	})
+	if err != nil {
+		t.Fatalf("handleBookSnapshot error: %v", err)
+	}
+	snap, ok := snapI.(*msgjson.BookSnapshot)
+	if !ok {
+		t.Fatalf("wrong type %T", snapI)
+	}
+	if snap.Seq != 5 || len(snap.Orders) != 1 || !bytes.Equal(snap.Digest, book.Digest()) {
+		t.Fatalf("wrong snapshot %+v", snap)
+	}
+	if _, err = rig.api.handleBookSnapshot("not a request"); err == nil {
+		t.Fatalf("no error for bad request")
+	}
+}
@@ -357,6 +357,10 @@ type BackendCloneConfig struct {
 	// input and output amounts. This is a temporary measure until zcashd
 	// encodes valueBalanceOrchard in their getrawtransaction RPC results.
 	ShieldedIO func(tx *VerboseTxExtended) (in, out uint64, err error)
+	// InstantSend indicates that an unmined transaction that is InstantSend
+	// locked cannot be double spent, and should be counted as having one
+	// confirmation. For Dash.
+	InstantSend bool
 	// RelayAddr is an address for a NodeRelay.
 	RelayAddr  string
 	FeeFetcher *txfee.FeeFetcher
@@ -1027,14 +1031,19 @@ func (btc *Backend) newTXIO(txHash *chainhash.Hash) (*TXIO, int64, error) {
 	if tx.isCoinbase {
 		maturity = int32(btc.chainParams.CoinbaseMaturity)
 	}
-	return &TXIO{
-		btc:        btc,
-		tx:         tx,
-		height:     blockHeight,
-		blockHash:  blockHash,
-		maturity:   maturity,
-		lastLookup: lastLookup,
-	}, int64(verboseTx.Confirmations), nil
+	txio := &TXIO{
+		btc:           btc,
+		tx:            tx,
+		height:        blockHeight,
+		blockHash:     blockHash,
+		maturity:      maturity,
+		lastLookup:    lastLookup,
+		instantLocked: btc.cfg.InstantSend && verboseTx.InstantLock,
+	}
+	if verboseTx.Confirmations == 0 && txio.instantLocked {
+		return txio, 1, nil
+	}
+	return txio, int64(verboseTx.Confirmations), nil
 }
 
 // input gets the transaction input.
@@ -25,6 +25,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/config"
 	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"decred.org/dcrdex/server/asset"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/btcjson"
@@ -284,6 +285,8 @@ type testBlockChain struct {
 	txRaws map[chainhash.Hash]*btcjson.TxRawResult
 	blocks map[chainhash.Hash]*btcjson.GetBlockVerboseResult
 	hashes map[int64]*chainhash.Hash
+	// instantLocks are the InstantSend locked transactions.
+	instantLocks map[chainhash.Hash]bool
 }
 
 // The testChain is a "blockchain" to store RPC responses for the Backend
@@ -310,6 +313,8 @@ func cleanTestChain() {
 		txRaws: make(map[chainhash.Hash]*btcjson.TxRawResult),
 		blocks: make(map[chainhash.Hash]*btcjson.GetBlockVerboseResult),
 		hashes: make(map[int64]*chainhash.Hash),
+
+		instantLocks: make(map[chainhash.Hash]bool),
 	}
 }
 
@@ -370,6 +375,12 @@ func (t *testNode) RawRequest(_ context.Context, method string, params []json.Ra
 		if !found {
 			return nil, fmt.Errorf("test transaction not found")
 		}
+		if testChain.instantLocks[*txHash] {
+			return json.Marshal(&struct {
+				*btcjson.TxRawResult
+				InstantLock bool `json:"instantlock"`
+			}{tx, true})
+		}
 		return json.Marshal(tx)
 	case methodGetTxOut:
 		testChainMtx.RLock()
@@ -1188,6 +1199,81 @@ func testRedemption(t *testing.T, segwit bool) {
 	}
 }
 
+func TestInstantSend(t *testing.T) {
+	btc, shutdown := testBackend(false)
+	defer shutdown()
+
+	cleanTestChain()
+	spentHash := randomHash()
+	spentID := toCoinID(spentHash, 0)
+	verboseTx := testAddTxVerbose(testMakeMsgTx(false).tx, spentHash, nil, 0)
+	verboseTx.Vout = append(verboseTx.Vout, btcjson.Vout{Value: 5})
+	txHash := randomHash()
+	redemptionID := toCoinID(txHash, 0)
+	msg := testMakeMsgTx(false)
+	vin := btcjson.Vin{Txid: spentHash.String()}
+	verboseTx = testAddTxVerbose(msg.tx, txHash, nil, 0)
+	verboseTx.Vin = append(verboseTx.Vin, vin)
+
+	checkConfs := func(tag string, coin asset.Coin, expConfs int64) {
+		t.Helper()
+		confs, err := coin.Confirmations(context.Background())
+		if err != nil {
+			t.Fatalf("%s: Confirmations error: %v", tag, err)
+		}
+		if confs != expConfs {
+			t.Fatalf("%s: expected %d confirmations, got %d", tag, expConfs, confs)
+		}
+	}
+
+	// Locks are ignored if the backend isn't InstantSend-aware.
+	testChainMtx.Lock()
+	testChain.instantLocks[*txHash] = true
+	testChainMtx.Unlock()
+	redemption, err := btc.Redemption(redemptionID, spentID, nil)
+	if err != nil {
+		t.Fatalf("Redemption error: %v", err)
+	}
+	checkConfs("not aware", redemption, 0)
+
+	// An unlocked mempool tx has no confirmations.
+	btc.cfg.InstantSend = true
+	testChainMtx.Lock()
+	delete(testChain.instantLocks, *txHash)
+	testChainMtx.Unlock()
+	redemption, err = btc.Redemption(redemptionID, spentID, nil)
+	if err != nil {
+		t.Fatalf("Redemption error: %v", err)
+	}
+	checkConfs("unlocked", redemption, 0)
+
+	// The lock is seen without a new block.
+	testChainMtx.Lock()
+	testChain.instantLocks[*txHash] = true
+	testChainMtx.Unlock()
+	checkConfs("locked", redemption, 1)
+
+	// A new redemption sees the lock right away.
+	redemption, err = btc.Redemption(redemptionID, spentID, nil)
+	if err != nil {
+		t.Fatalf("Redemption error: %v", err)
+	}
+	checkConfs("new locked", redemption, 1)
+
+	// Mined.
+	blockHash := randomHash()
+	testChainMtx.Lock()
+	verboseTx = testAddTxVerbose(msg.tx, txHash, blockHash, 1)
+	verboseTx.Vin = append(verboseTx.Vin, vin)
+	testChainMtx.Unlock()
+	testAddBlockVerbose(blockHash, nil, 1, 1)
+	redemption, err = btc.Redemption(redemptionID, spentID, nil)
+	if err != nil {
+		t.Fatalf("Redemption error: %v", err)
+	}
+	checkConfs("mined", redemption, 1)
+}
+
 // TestReorg tests various reorg paths. Because bitcoind doesn't support
 // websocket notifications, and ZeroMQ is not desirable, Backend polls for
 // new block data every 5 seconds or so. The poll interval means it's possible
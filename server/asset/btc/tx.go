@@ -77,6 +77,11 @@ type VerboseTxExtended struct {
 	// ValueBalanceOrchard is disabled until zcashd encodes valueBalanceOrchard.
 	ValueBalanceOrchard int64 `json:"valueBalanceOrchardZat"` // Orchard pool
 
+	// Dash-specific fields.
+
+	// InstantLock is true if the transaction is InstantSend locked.
+	InstantLock bool `json:"instantlock,omitempty"`
+
 	// Other fields that could be used but aren't right now.
 
 	// Hash      string `json:"hash,omitempty"`
@@ -35,21 +35,28 @@ type TXIO struct {
 	// This enables an optimization in the Confirmations method to return zero
 	// without extraneous RPC calls.
 	lastLookup *chainhash.Hash
+	// instantLocked is set if the backend is InstantSend-aware and the
+	// transaction is InstantSend locked. An unmined transaction that is
+	// InstantSend locked is counted as having one confirmation.
+	instantLocked bool
 }
 
 // confirmations returns the number of confirmations for a TXIO's transaction.
 // Because a tx can become invalid after once being considered valid, validity
 // should be verified again on every call. An error will be returned if this
 // TXIO is no longer ready to spend. An unmined transaction should have zero
-// confirmations. A transaction in the current best block should have one
-// confirmation. The value -1 will be returned with any error.
+// confirmations, or one if it is InstantSend locked. A transaction in the
+// current best block should have one confirmation. The value -1 will be
+// returned with any error.
 func (txio *TXIO) confirmations() (int64, error) {
 	btc := txio.btc
 	tipHash := btc.blockCache.tipHash()
 	// If the tx was a mempool transaction, check if it has been confirmed.
 	if txio.height == 0 {
-		// If the tip hasn't changed, don't do anything here.
-		if txio.lastLookup == nil || *txio.lastLookup != tipHash {
+		// If the tip hasn't changed, don't do anything here, unless we are
+		// waiting for an InstantSend lock, which doesn't need a new block.
+		awaitingLock := btc.cfg.InstantSend && !txio.instantLocked
+		if txio.lastLookup == nil || *txio.lastLookup != tipHash || awaitingLock {
 			txio.lastLookup = &tipHash
 			verboseTx, err := txio.btc.node.GetRawTransactionVerbose(&txio.tx.hash)
 			if err != nil {
@@ -64,8 +71,11 @@ func (txio *TXIO) confirmations() (int64, error) {
 				}
 				txio.height = blk.height
 				txio.blockHash = blk.hash
+				return int64(verboseTx.Confirmations), nil
+			}
+			if btc.cfg.InstantSend && verboseTx.InstantLock {
+				txio.instantLocked = true
 			}
-			return int64(verboseTx.Confirmations), nil
 		}
 	} else {
 		// The tx was included in a block, but make sure that the tx's block has
@@ -77,6 +87,9 @@ func (txio *TXIO) confirmations() (int64, error) {
 	}
 	// If the height is still 0, this is a mempool transaction.
 	if txio.height == 0 {
+		if txio.instantLocked {
+			return 1, nil
+		}
 		return 0, nil
 	}
 	// Otherwise just check that there hasn't been a reorg which would render the
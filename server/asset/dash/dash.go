@@ -109,5 +109,8 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		FeeConfs:     2,
 		MaxFeeBlocks: 16,
 		RelayAddr:    cfg.RelayAddr,
+		// An InstantSend locked tx can't be double spent, so it counts as
+		// confirmed before it is mined.
+		InstantSend: true,
 	})
 }
@@ -0,0 +1,73 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package auth
+
+import (
+	"errors"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/server/account"
+)
+
+// ErrAllowlistDisabled is returned when the allowlist is modified while the
+// AuthManager is not in allowlist mode.
+var ErrAllowlistDisabled = errors.New("allowlist mode is not enabled")
+
+// AllowlistEnabled is true if the AuthManager is in allowlist mode.
+func (auth *AuthManager) AllowlistEnabled() bool {
+	return auth.allowed != nil
+}
+
+// isAllowed checks if the account may register and trade. All accounts are
+// allowed if the AuthManager is not in allowlist mode.
+func (auth *AuthManager) isAllowed(user account.AccountID) bool {
+	if auth.allowed == nil {
+		return true
+	}
+	auth.allowedMtx.RLock()
+	defer auth.allowedMtx.RUnlock()
+	return auth.allowed[user]
+}
+
+// checkAllowed returns an AccountNotAllowedError msgjson.Error if the account
+// may not register.
+func (auth *AuthManager) checkAllowed(user account.AccountID) *msgjson.Error {
+	if auth.isAllowed(user) {
+		return nil
+	}
+	log.Infof("Rejecting registration of account %v, which is not on the allowlist", user)
+	return msgjson.NewError(msgjson.AccountNotAllowedError, "account %v is not allowed on this server", user)
+}
+
+// AllowAccount adds an account to the allowlist. The note is stored with the
+// account for the operator's reference.
+func (auth *AuthManager) AllowAccount(user account.AccountID, note string) error {
+	if auth.allowed == nil {
+		return ErrAllowlistDisabled
+	}
+	if err := auth.storage.AllowAccount(user, note); err != nil {
+		return err
+	}
+	auth.allowedMtx.Lock()
+	auth.allowed[user] = true
+	auth.allowedMtx.Unlock()
+	log.Infof("Account %v added to the allowlist", user)
+	return nil
+}
+
+// DisallowAccount removes an account from the allowlist. The account may no
+// longer place orders, but it may still connect to settle its active matches.
+func (auth *AuthManager) DisallowAccount(user account.AccountID) error {
+	if auth.allowed == nil {
+		return ErrAllowlistDisabled
+	}
+	if err := auth.storage.DisallowAccount(user); err != nil {
+		return err
+	}
+	auth.allowedMtx.Lock()
+	delete(auth.allowed, user)
+	auth.allowedMtx.Unlock()
+	log.Infof("Account %v removed from the allowlist", user)
+	return nil
+}
@@ -57,6 +57,9 @@ type Storage interface {
 	DeletePrepaidBond(coinID []byte) error
 	StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error
 
+	AllowAccount(aid account.AccountID, note string) error
+	DisallowAccount(aid account.AccountID) error
+
 	AccountInfo(aid account.AccountID) (*db.Account, error)
 
 	UserOrderStatuses(aid account.AccountID, base, quote uint32, oids []order.OrderID) ([]*db.OrderStatus, error)
@@ -233,6 +236,7 @@ type AuthManager struct {
 	checkBond      BondCoinChecker // fidelity bond amount, lockTime, acct, and confs
 	miaUserTimeout time.Duration
 	unbookFun      func(account.AccountID)
+	penalized      func(user account.AccountID, lastRule account.Rule)
 	route          func(route string, handler comms.MsgHandler)
 
 	bondExpiry time.Duration // a bond is expired when time.Until(lockTime) < bondExpiry
@@ -261,6 +265,14 @@ type AuthManager struct {
 	txDataSources map[uint32]TxDataSource
 
 	prepaidBondMtx sync.Mutex
+
+	limitsMtx     sync.RWMutex
+	parcelLimits  *account.ParcelLimits
+	limitsChanges []*ParcelLimitsChange
+
+	// allowed is nil unless the AuthManager is in allowlist mode.
+	allowedMtx sync.RWMutex
+	allowed    map[account.AccountID]bool
 }
 
 // violation badness
@@ -398,6 +410,16 @@ type Config struct {
 	// PenaltyThreshold defines the score deficit at which a user's bond is
 	// revoked.
 	PenaltyThreshold uint32
+
+	// Penalized is an optional function that is called when a user is
+	// penalized.
+	Penalized func(user account.AccountID, lastRule account.Rule)
+
+	// Allowlist enables allowlist mode, in which only the AllowedAccounts may
+	// register and trade.
+	Allowlist bool
+	// AllowedAccounts are the accounts on the allowlist at startup.
+	AllowedAccounts []account.AccountID
 }
 
 // NewAuthManager is the constructor for an AuthManager.
@@ -426,6 +448,7 @@ func NewAuthManager(cfg *Config) *AuthManager {
 		checkBond:        cfg.BondChecker,  // e.g. dcr's BondCoin
 		miaUserTimeout:   cfg.MiaUserTimeout,
 		unbookFun:        cfg.UserUnbooker,
+		penalized:        cfg.Penalized,
 		route:            cfg.Route,
 		freeCancels:      cfg.FreeCancels,
 		penaltyThreshold: penaltyThreshold,
@@ -439,6 +462,13 @@ func NewAuthManager(cfg *Config) *AuthManager {
 		preimgOutcomes:   make(map[account.AccountID]*latestPreimageOutcomes),
 		orderOutcomes:    make(map[account.AccountID]*latestOrders),
 		txDataSources:    cfg.TxDataSources,
+		parcelLimits:     defaultParcelLimits(),
+	}
+	if cfg.Allowlist {
+		auth.allowed = make(map[account.AccountID]bool, len(cfg.AllowedAccounts))
+		for _, aid := range cfg.AllowedAccounts {
+			auth.allowed[aid] = true
+		}
 	}
 
 	// Unauthenticated
@@ -804,14 +834,14 @@ func (auth *AuthManager) UserScore(user account.AccountID) (score int32, err err
 // UserReputation calculates some quantities related to the user's reputation.
 // UserReputation satisfies market.AuthManager.
 func (auth *AuthManager) UserReputation(user account.AccountID) (tier int64, score, maxScore int32, err error) {
-	maxScore = ScoringMatchLimit
+	maxScore = auth.ParcelLimits().MaxScore
 	score, err = auth.UserScore(user)
 	if err != nil {
 		return
 	}
 	r, _, _ := auth.computeUserReputation(user, score)
 	if r != nil {
-		return r.EffectiveTier(), r.Score, ScoringMatchLimit, nil
+		return r.EffectiveTier(), r.Score, maxScore, nil
 
 	}
 	return
@@ -1033,6 +1063,9 @@ func (auth *AuthManager) Penalize(user account.AccountID, lastRule account.Rule,
 	auth.unbookUserOrders(user)
 
 	log.Debugf("User %v account penalized. Last rule broken = %v. Detail: %s", user, lastRule, extraDetails)
+	if auth.penalized != nil {
+		auth.penalized(user, lastRule)
+	}
 
 	// Notify user of penalty.
 	details := "Ordering has been suspended for this account. Post additional bond to offset violations."
@@ -1057,6 +1090,11 @@ func (auth *AuthManager) Penalize(user account.AccountID, lastRule account.Rule,
 // AcctStatus indicates if the user is presently connected and their tier.
 func (auth *AuthManager) AcctStatus(user account.AccountID) (connected bool, tier int64) {
 	client := auth.user(user)
+	// Accounts that are not allowed may connect to settle their active matches,
+	// but may not trade.
+	if !auth.isAllowed(user) {
+		return client != nil, 0
+	}
 	if client == nil {
 		// Load user info from DB.
 		rep := auth.ComputeUserReputation(user)
@@ -9,6 +9,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
@@ -82,6 +83,10 @@ func (s *TStorage) FetchPrepaidBond([]byte) (uint32, int64, error) {
 	return 1, time.Now().Add(time.Hour * 48).Unix(), nil
 }
 func (s *TStorage) DeletePrepaidBond(coinID []byte) (err error) { return nil }
+func (s *TStorage) AllowAccount(aid account.AccountID, note string) error {
+	return nil
+}
+func (s *TStorage) DisallowAccount(aid account.AccountID) error { return nil }
 func (s *TStorage) StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error {
 	return nil
 }
@@ -177,6 +182,8 @@ func (c *TRPCClient) Send(msg *msgjson.Message) error {
 	c.sends = append(c.sends, msg)
 	return c.sendErr
 }
+func (c *TRPCClient) Encoding() msgjson.Encoding { return msgjson.JSONEncoding }
+func (c *TRPCClient) APIVersion() uint16         { return 0 }
 func (c *TRPCClient) SendRaw(b []byte) error {
 	if c.sendRawErr != nil {
 		return c.sendRawErr
@@ -1527,3 +1534,85 @@ func Test_checkSigS256(t *testing.T) {
 	sig = []byte{0x30, 1, 0x02, 0x01, 9, 0x2, 0x01, 10}
 	ecdsa.ParseDERSignature(sig) // panic on line 139: rLen := int(sigStr[index]) with index=3 and len = 3
 }
+
+func TestSetParcelLimits(t *testing.T) {
+	defaultLimits := defaultParcelLimits()
+	defer rig.mgr.SetParcelLimits(defaultLimits, "")
+
+	if l := rig.mgr.ParcelLimits(); *l != *defaultLimits {
+		t.Fatalf("wrong initial limits %+v", l)
+	}
+
+	for _, l := range []*account.ParcelLimits{
+		{PerTier: 0, ScoreMultiplier: 3, MaxScore: 60},
+		{PerTier: 2, ScoreMultiplier: 0, MaxScore: 60},
+		{PerTier: 2, ScoreMultiplier: 3, MaxScore: 0},
+		{PerTier: 2, ScoreMultiplier: 3, MaxScore: ScoringMatchLimit + 1},
+	} {
+		if err := rig.mgr.SetParcelLimits(l, ""); err == nil {
+			t.Fatalf("no error for invalid limits %+v", l)
+		}
+	}
+	if len(rig.mgr.ParcelLimitsChanges()) != 0 {
+		t.Fatalf("invalid limits were logged")
+	}
+
+	newLimits := &account.ParcelLimits{PerTier: 4, ScoreMultiplier: 2, MaxScore: 30}
+	if err := rig.mgr.SetParcelLimits(newLimits, "test"); err != nil {
+		t.Fatalf("error setting limits: %v", err)
+	}
+	if l := rig.mgr.ParcelLimits(); *l != *newLimits {
+		t.Fatalf("limits not set. wanted %+v, got %+v", newLimits, l)
+	}
+	changes := rig.mgr.ParcelLimitsChanges()
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 logged change, got %d", len(changes))
+	}
+	if c := changes[0]; *c.Old != *defaultLimits || *c.New != *newLimits || c.Reason != "test" {
+		t.Fatalf("wrong logged change %+v", c)
+	}
+	// Limits are scaled to the new max score.
+	if limit := rig.mgr.ParcelLimits().ParcelLimit(1, 15); limit != 6 {
+		t.Fatalf("wrong parcel limit %d", limit)
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	user := tNewUser(t)
+	rig.signer.sig = user.randomSignature()
+	connectUser(t, user)
+	defer func() { rig.mgr.allowed = nil }()
+
+	if err := rig.mgr.AllowAccount(user.acctID, ""); !errors.Is(err, ErrAllowlistDisabled) {
+		t.Fatalf("expected ErrAllowlistDisabled, got %v", err)
+	}
+	if _, tier := rig.mgr.AcctStatus(user.acctID); tier < 1 {
+		t.Fatalf("account not trading without allowlist mode")
+	}
+
+	rig.mgr.allowed = make(map[account.AccountID]bool)
+	if msgErr := rig.mgr.checkAllowed(user.acctID); msgErr == nil || msgErr.Code != msgjson.AccountNotAllowedError {
+		t.Fatalf("expected AccountNotAllowedError, got %v", msgErr)
+	}
+	// A connected account that is not allowed may not trade.
+	if connected, tier := rig.mgr.AcctStatus(user.acctID); !connected || tier != 0 {
+		t.Fatalf("wrong status for unallowed account. connected = %t, tier = %d", connected, tier)
+	}
+
+	if err := rig.mgr.AllowAccount(user.acctID, "test"); err != nil {
+		t.Fatalf("AllowAccount error: %v", err)
+	}
+	if msgErr := rig.mgr.checkAllowed(user.acctID); msgErr != nil {
+		t.Fatalf("allowed account rejected: %v", msgErr)
+	}
+	if _, tier := rig.mgr.AcctStatus(user.acctID); tier < 1 {
+		t.Fatalf("allowed account not trading")
+	}
+
+	if err := rig.mgr.DisallowAccount(user.acctID); err != nil {
+		t.Fatalf("DisallowAccount error: %v", err)
+	}
+	if _, tier := rig.mgr.AcctStatus(user.acctID); tier != 0 {
+		t.Fatalf("disallowed account still trading")
+	}
+}
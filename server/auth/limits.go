@@ -0,0 +1,85 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/account"
+)
+
+// maxParcelLimitsChanges is the number of parcel limits changes that are kept
+// in the change log.
+const maxParcelLimitsChanges = 100
+
+// ParcelLimitsChange is a record of a change to the parcel limits.
+type ParcelLimitsChange struct {
+	Stamp  int64                 `json:"stamp"` // unix ms
+	Old    *account.ParcelLimits `json:"old"`
+	New    *account.ParcelLimits `json:"new"`
+	Reason string                `json:"reason,omitempty"`
+}
+
+// defaultParcelLimits are the parcel limits that are in effect until changed
+// with SetParcelLimits.
+func defaultParcelLimits() *account.ParcelLimits {
+	return &account.ParcelLimits{
+		PerTier:         dex.PerTierBaseParcelLimit,
+		ScoreMultiplier: dex.ParcelLimitScoreMultiplier,
+		MaxScore:        ScoringMatchLimit,
+	}
+}
+
+// ParcelLimits returns the current parcel limits. ParcelLimits satisfies
+// market.AuthManager.
+func (auth *AuthManager) ParcelLimits() *account.ParcelLimits {
+	auth.limitsMtx.RLock()
+	defer auth.limitsMtx.RUnlock()
+	l := *auth.parcelLimits
+	return &l
+}
+
+// SetParcelLimits changes the parcel limits. The new limits apply to all
+// subsequent orders, including those of connected users. The change is
+// recorded in the parcel limits change log.
+func (auth *AuthManager) SetParcelLimits(limits *account.ParcelLimits, reason string) error {
+	if limits.PerTier <= 0 {
+		return fmt.Errorf("per-tier parcel limit must be positive")
+	}
+	if limits.ScoreMultiplier < 1 {
+		return fmt.Errorf("score multiplier must be at least 1")
+	}
+	// A user's score cannot exceed the number of matches that are considered
+	// in scoring.
+	if limits.MaxScore <= 0 || limits.MaxScore > ScoringMatchLimit {
+		return fmt.Errorf("max score must be between 1 and %d", ScoringMatchLimit)
+	}
+
+	newLimits := *limits
+	auth.limitsMtx.Lock()
+	change := &ParcelLimitsChange{
+		Stamp:  time.Now().UnixMilli(),
+		Old:    auth.parcelLimits,
+		New:    &newLimits,
+		Reason: reason,
+	}
+	auth.parcelLimits = &newLimits
+	auth.limitsChanges = append(auth.limitsChanges, change)
+	if len(auth.limitsChanges) > maxParcelLimitsChanges {
+		auth.limitsChanges = auth.limitsChanges[len(auth.limitsChanges)-maxParcelLimitsChanges:]
+	}
+	auth.limitsMtx.Unlock()
+
+	log.Infof("Parcel limits changed from %+v to %+v. Reason: %q", *change.Old, newLimits, reason)
+	return nil
+}
+
+// ParcelLimitsChanges returns the log of parcel limits changes, oldest first.
+func (auth *AuthManager) ParcelLimitsChanges() []*ParcelLimitsChange {
+	auth.limitsMtx.RLock()
+	defer auth.limitsMtx.RUnlock()
+	return append([]*ParcelLimitsChange(nil), auth.limitsChanges...)
+}
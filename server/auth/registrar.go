@@ -88,6 +88,9 @@ func (auth *AuthManager) handlePreValidateBond(conn comms.Link, msg *msgjson.Mes
 			Message: "signature error: " + err.Error(),
 		}
 	}
+	if msgErr := auth.checkAllowed(acctID); msgErr != nil {
+		return msgErr
+	}
 
 	// A bond's lockTime must be after bondExpiry from now.
 	lockTimeThresh := time.Now().Add(auth.bondExpiry)
@@ -175,6 +178,9 @@ func (auth *AuthManager) handlePostBond(conn comms.Link, msg *msgjson.Message) *
 			Message: "signature error: " + err.Error(),
 		}
 	}
+	if msgErr := auth.checkAllowed(acctID); msgErr != nil {
+		return msgErr
+	}
 
 	if assetID == account.PrepaidBondID {
 		return auth.processPrepaidBond(conn, msg, acct, postBond.CoinID)
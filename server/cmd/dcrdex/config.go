@@ -48,6 +48,7 @@ const (
 	defaultRPCPort             = "7232"
 	defaultHSHost              = defaultRPCHost // should be a loopback address
 	defaultHSPort              = "7252"
+	defaultGRPCPort            = "7262"
 	defaultAdminSrvAddr        = "127.0.0.1:6542"
 	defaultMaxUserCancels      = 2
 	defaultPenaltyThresh       = 20
@@ -86,6 +87,8 @@ type dexConf struct {
 	RPCKey           string
 	NoTLS            bool
 	RPCListen        []string
+	GRPCListen       []string
+	QUIC             bool
 	HiddenService    string
 	BroadcastTimeout time.Duration
 	TxWaitExpiration time.Duration
@@ -97,6 +100,10 @@ type dexConf struct {
 	AdminSrvPW       []byte
 	AdminSrvNoTLS    bool
 	NoResumeSwaps    bool
+	BookBatching     bool
+	Allowlist        bool
+	TradeReport      string
+	TradeReportAuth  string
 	DisableDataAPI   bool
 	NodeRelayAddr    string
 	ValidateMarkets  bool
@@ -121,6 +128,8 @@ type flagsData struct {
 	RPCListen     []string `long:"rpclisten" description:"IP addresses on which the RPC server should listen for incoming connections."`
 	NoTLS         bool     `long:"notls" description:"Run without TLS encryption."`
 	AltDNSNames   []string `long:"altdnsnames" description:"A list of hostnames to include in the RPC certificate (X509v3 Subject Alternative Name)."`
+	GRPCListen    []string `long:"grpclisten" description:"IP addresses on which the gRPC service should listen for incoming connections. The gRPC service is disabled unless specified. The TLS settings are the same as for the RPC server."`
+	QUIC          bool     `long:"quic" description:"Also accept QUIC connections on the UDP ports of the RPC listen addresses. The QUIC port is advertised in websocket handshakes, and clients that prefer QUIC use it for later connections. Requires TLS."`
 	HiddenService string   `long:"hiddenservice" description:"A host:port on which the RPC server should listen for incoming hidden service connections. No TLS is used for these connections."`
 
 	MarketsConfPath  string        `long:"marketsconfpath" description:"Path to the markets configuration JSON file."`
@@ -149,6 +158,13 @@ type flagsData struct {
 
 	NoResumeSwaps bool `long:"noresumeswaps" description:"Do not attempt to resume swaps that are active in the DB."`
 
+	BookBatching bool `long:"bookbatch" description:"Coalesce each epoch's order book updates into a single batched notification for subscribers that request it."`
+
+	Allowlist bool `long:"allowlist" description:"Only allow accounts on the allowlist to register and trade. The allowlist is managed with the admin server."`
+
+	TradeReport     string `long:"tradereport" description:"Report each completed match to this destination. An https:// URL receives one JSON record per POST request. A kafka://host:port[,host:port...]/topic URL writes one JSON record per message to the topic over TLS. Anything else is a file path, relative to the appdata directory if not absolute, to which JSON records are appended one per line."`
+	TradeReportAuth string `long:"tradereportauth" description:"The Authorization header value for requests to an https:// tradereport destination, e.g. \"Bearer abc\", or the user:password for SASL PLAIN authentication with the brokers of a kafka:// destination."`
+
 	DisableDataAPI bool `long:"nodata" description:"Disable the HTTP data API."`
 
 	NodeRelayAddr string `long:"noderelayaddr" description:"The public address by which node sources should connect to the node relay"`
@@ -446,6 +462,9 @@ func loadConfig() (*dexConf, *procOpts, error) {
 	if !filepath.IsAbs(cfg.MarketsConfPath) {
 		cfg.MarketsConfPath = filepath.Join(cfg.AppDataDir, cfg.MarketsConfPath)
 	}
+	if cfg.TradeReport != "" && !strings.Contains(cfg.TradeReport, "://") && !filepath.IsAbs(cfg.TradeReport) {
+		cfg.TradeReport = filepath.Join(cfg.AppDataDir, cfg.TradeReport)
+	}
 	if !filepath.IsAbs(cfg.DEXPrivKeyPath) {
 		cfg.DEXPrivKeyPath = filepath.Join(cfg.AppDataDir, cfg.DEXPrivKeyPath)
 	}
@@ -462,6 +481,14 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		}
 		RPCListen = append(RPCListen, listen)
 	}
+	var GRPCListen []string
+	for i := range cfg.GRPCListen {
+		listen, err := normalizeNetworkAddress(cfg.GRPCListen[i], defaultRPCHost, defaultGRPCPort)
+		if err != nil {
+			return loadConfigError(err)
+		}
+		GRPCListen = append(GRPCListen, listen)
+	}
 	var HiddenService string
 	if cfg.HiddenService != "" {
 		HiddenService, err = normalizeNetworkAddress(cfg.HiddenService, defaultHSHost, defaultHSPort)
@@ -548,6 +575,8 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		RPCKey:           cfg.RPCKey,
 		NoTLS:            cfg.NoTLS,
 		RPCListen:        RPCListen,
+		GRPCListen:       GRPCListen,
+		QUIC:             cfg.QUIC,
 		HiddenService:    HiddenService,
 		BroadcastTimeout: cfg.BroadcastTimeout,
 		TxWaitExpiration: cfg.TxWaitExpiration,
@@ -559,6 +588,10 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		AdminSrvPW:       []byte(cfg.AdminSrvPassword),
 		AdminSrvNoTLS:    cfg.AdminSrvNoTLS,
 		NoResumeSwaps:    cfg.NoResumeSwaps,
+		BookBatching:     cfg.BookBatching,
+		Allowlist:        cfg.Allowlist,
+		TradeReport:      cfg.TradeReport,
+		TradeReportAuth:  cfg.TradeReportAuth,
 		DisableDataAPI:   cfg.DisableDataAPI,
 		NodeRelayAddr:    cfg.NodeRelayAddr,
 		ValidateMarkets:  cfg.ValidateMarkets,
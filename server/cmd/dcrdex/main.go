@@ -20,6 +20,7 @@ import (
 	"decred.org/dcrdex/server/admin"
 	_ "decred.org/dcrdex/server/asset/importall"
 	dexsrv "decred.org/dcrdex/server/dex"
+	"decred.org/dcrdex/server/tradereport"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
@@ -115,6 +116,15 @@ func mainCore(ctx context.Context) error {
 		return err
 	}
 
+	var tradeReportSink tradereport.Sink
+	if cfg.TradeReport != "" {
+		tradeReportSink, err = tradereport.NewSink(cfg.TradeReport, cfg.TradeReportAuth)
+		if err != nil {
+			return err
+		}
+		log.Infof("Completed matches will be reported to the configured trade report destination")
+	}
+
 	// Create the DEX manager.
 	dexConf := &dexsrv.DexConf{
 		DataDir:    cfg.DataDir,
@@ -141,12 +151,17 @@ func mainCore(ctx context.Context) error {
 			NoTLS:             cfg.NoTLS,
 			RPCKey:            cfg.RPCKey,
 			ListenAddrs:       cfg.RPCListen,
+			GRPCListenAddrs:   cfg.GRPCListen,
+			QUIC:              cfg.QUIC,
 			AltDNSNames:       cfg.AltDNSNames,
 			DisableDataAPI:    cfg.DisableDataAPI,
 			HiddenServiceAddr: cfg.HiddenService,
 		},
-		NoResumeSwaps: cfg.NoResumeSwaps,
-		NodeRelayAddr: cfg.NodeRelayAddr,
+		NoResumeSwaps:   cfg.NoResumeSwaps,
+		NodeRelayAddr:   cfg.NodeRelayAddr,
+		BookBatching:    cfg.BookBatching,
+		Allowlist:       cfg.Allowlist,
+		TradeReportSink: tradeReportSink,
 	}
 	dexMan, err := dexsrv.NewDEX(ctx, dexConf) // ctx cancel just aborts setup; Stop does normal shutdown
 	if err != nil {
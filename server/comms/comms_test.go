@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -388,7 +389,7 @@ func TestClientRequests(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			server.websocketHandler(testCtx, conn, stubAddr)
+			server.websocketHandler(testCtx, conn, stubAddr, nil)
 		}()
 
 		if !giveItASecond(func() bool {
@@ -531,6 +532,70 @@ func TestClientRequests(t *testing.T) {
 
 }
 
+func TestVersionNegotiation(t *testing.T) {
+	server := newServer()
+	server.apiVersions = []uint16{1, 2, 3}
+	var wg sync.WaitGroup
+	defer func() {
+		server.disconnectClients()
+		wg.Wait()
+	}()
+
+	conn := newWsStub()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		server.websocketHandler(testCtx, conn, dex.IPKey{}, nil)
+	}()
+	if !giveItASecond(func() bool {
+		return server.clientCount() == 1
+	}) {
+		t.Fatalf("failed to add client")
+	}
+	server.clientMtx.RLock()
+	var client *wsLink
+	for _, cl := range server.clients {
+		client = cl
+	}
+	server.clientMtx.RUnlock()
+	conn.addChan()
+
+	tests := []struct {
+		name     string
+		versions string
+		wantVer  uint16
+		wantCode int
+	}{
+		{"highest common", `{"versions":[0,1,2,5]}`, 2, -1},
+		{"single", `{"versions":[3]}`, 3, -1},
+		{"none common", `{"versions":[0,4]}`, 3, msgjson.RPCVersionUnsupported},
+		{"bad payload", `{"versions":"x"}`, 3, msgjson.RPCParseError},
+	}
+	for _, tt := range tests {
+		sendToConn(t, conn, msgjson.VersionRoute, tt.versions)
+		resp := decodeResponse(t, <-conn.recv)
+		if tt.wantCode >= 0 {
+			if resp.Error == nil || resp.Error.Code != tt.wantCode {
+				t.Fatalf("%s: expected error code %d, got %v", tt.name, tt.wantCode, resp.Error)
+			}
+		} else {
+			if resp.Error != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, resp.Error)
+			}
+			res := new(msgjson.VersionResult)
+			if err := json.Unmarshal(resp.Result, res); err != nil {
+				t.Fatalf("%s: error decoding result: %v", tt.name, err)
+			}
+			if res.Version != tt.wantVer {
+				t.Fatalf("%s: wrong version %d, wanted %d", tt.name, res.Version, tt.wantVer)
+			}
+		}
+		if client.APIVersion() != tt.wantVer {
+			t.Fatalf("%s: wrong link version %d, wanted %d", tt.name, client.APIVersion(), tt.wantVer)
+		}
+	}
+}
+
 func TestClientResponses(t *testing.T) {
 	server := newServer()
 	var client *wsLink
@@ -579,7 +644,7 @@ func TestClientResponses(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			server.websocketHandler(testCtx, conn, stubAddr)
+			server.websocketHandler(testCtx, conn, stubAddr, nil)
 		}()
 		getClient()
 	}
@@ -839,6 +904,122 @@ func TestOnline(t *testing.T) {
 	conn.Close()
 }
 
+func TestQUIC(t *testing.T) {
+	tempDir := t.TempDir()
+	keyPath := filepath.Join(tempDir, "rpc.key")
+	certPath := filepath.Join(tempDir, "rpc.cert")
+
+	if _, err := NewServer(&RPCConfig{
+		ListenAddrs: []string{"127.0.0.1:0"},
+		NoTLS:       true,
+		QUIC:        true,
+	}); err == nil {
+		t.Fatalf("no error for QUIC without TLS")
+	}
+
+	server, err := NewServer(&RPCConfig{
+		ListenAddrs: []string{"127.0.0.1:0"},
+		RPCKey:      keyPath,
+		RPCCert:     certPath,
+		QUIC:        true,
+	})
+	if err != nil {
+		t.Fatalf("server constructor error: %v", err)
+	}
+	// The QUIC port is the same as the websocket port.
+	addr := server.listeners[0].Addr().String()
+	if quicAddr := server.quicListeners[0].Addr().String(); quicAddr != addr {
+		t.Fatalf("QUIC listening on %s, not %s", quicAddr, addr)
+	}
+	server.Route("ok", func(c Link, msg *msgjson.Message) *msgjson.Error {
+		resp, _ := msgjson.NewResponse(msg.ID, c.Encoding().String(), nil)
+		c.Send(resp)
+		return nil
+	})
+
+	ssw := dex.NewStartStopWaiter(server)
+	ssw.Start(testCtx)
+	defer func() {
+		ssw.Stop()
+		ssw.WaitForShutdown()
+	}()
+
+	tlsCfg := &tls.Config{InsecureSkipVerify: true}
+	dial := func(header http.Header) (*ws.QUICConn, error) {
+		ctx, cancel := context.WithTimeout(testCtx, 5*time.Second)
+		defer cancel()
+		return ws.DialQUIC(ctx, addr, tlsCfg, []string{ws.QUICProtocolCBOR, ws.QUICProtocolJSON}, header)
+	}
+
+	conn, err := dial(nil)
+	if err != nil {
+		t.Fatalf("DialQUIC error: %v", err)
+	}
+	b, _ := makeReq("ok", "{}").Encode(msgjson.CBOREncoding)
+	if err := conn.WriteMessage(websocket.BinaryMessage, b); err != nil {
+		t.Fatalf("WriteMessage error: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	frameType, b, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage error: %v", err)
+	}
+	if frameType != websocket.BinaryMessage {
+		t.Fatalf("wrong frame type %d", frameType)
+	}
+	msg := new(msgjson.Message)
+	if err := msg.Decode(b, msgjson.CBOREncoding); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	var enc string
+	if err := msg.UnmarshalResult(&enc); err != nil || enc != msgjson.CBOREncoding.String() {
+		t.Fatalf("wrong response %q: %v", enc, err)
+	}
+	if !giveItASecond(func() bool { return server.clientCount() == 1 }) {
+		t.Fatalf("QUIC client not added")
+	}
+	conn.Close()
+	if !giveItASecond(func() bool { return server.clientCount() == 0 }) {
+		t.Fatalf("QUIC client not removed")
+	}
+
+	// Clients are checked like websocket clients.
+	_, err = dial(http.Header{DataAPIKeyHeader: []string{"unknown"}})
+	if !errors.Is(err, ws.ErrQUICRejected) {
+		t.Fatalf("wrong error for unknown data API key: %v", err)
+	}
+	// An address with too many connections is rejected before the hello.
+	ip := dex.NewIPKey(addr)
+	server.wsLimiterMtx.Lock()
+	server.wsLimiters[ip] = &ipWsLimiter{conns: rpcMaxConnsPerIP}
+	server.wsLimiterMtx.Unlock()
+	_, err = dial(nil)
+	if !errors.Is(err, ws.ErrQUICRejected) {
+		t.Fatalf("wrong error for too many connections: %v", err)
+	}
+	server.wsLimiterMtx.Lock()
+	delete(server.wsLimiters, ip)
+	server.wsLimiterMtx.Unlock()
+
+	// The websocket handshake advertises the QUIC port.
+	dialer := &websocket.Dialer{TLSClientConfig: tlsCfg, HandshakeTimeout: 5 * time.Second}
+	wsConn, resp, err := dialer.DialContext(testCtx, "wss://"+addr+"/ws", nil)
+	if err != nil {
+		t.Fatalf("websocket dial error: %v", err)
+	}
+	wsConn.Close()
+	_, port, _ := net.SplitHostPort(addr)
+	if advertised := resp.Header.Get(ws.QUICPortHeader); advertised != port {
+		t.Fatalf("advertised QUIC port %q, wanted %q", advertised, port)
+	}
+
+	server.banish(ip)
+	_, err = dial(nil)
+	if !errors.Is(err, ws.ErrQUICRejected) {
+		t.Fatalf("wrong error for quarantined client: %v", err)
+	}
+}
+
 func TestParseListeners(t *testing.T) {
 	ipv6wPort := "[fdc5:f621:d3b4:923f::]:80"
 	ipv6wZonePort := "[a:b:c:d::%123]:45"
@@ -959,8 +1140,8 @@ func TestWSRateLimiter(t *testing.T) {
 			t.Errorf("bad addr")
 			return
 		}
-		server.websocketHandler(testCtx, conn, stubAddr) // newWSLink -> Connect -> readloop will call handleMessage
-		close(conn.nextRead)                             // must be after read loop has quit (sends on nextRead)
+		server.websocketHandler(testCtx, conn, stubAddr, nil) // newWSLink -> Connect -> readloop will call handleMessage
+		close(conn.nextRead)                                  // must be after read loop has quit (sends on nextRead)
 	}(conn)
 
 	<-conn.nextRead
@@ -1029,8 +1210,8 @@ func TestWSRateLimiter(t *testing.T) {
 			t.Errorf("bad addr")
 			return
 		}
-		server.websocketHandler(testCtx, conn, stubAddr) // newWSLink -> Connect -> readloop will call handleMessage
-		close(conn.nextRead)                             // must be after read loop has quit (sends on nextRead)
+		server.websocketHandler(testCtx, conn, stubAddr, nil) // newWSLink -> Connect -> readloop will call handleMessage
+		close(conn.nextRead)                                  // must be after read loop has quit (sends on nextRead)
 	}(conn)
 
 	<-conn.nextRead
@@ -1039,3 +1220,76 @@ func TestWSRateLimiter(t *testing.T) {
 		}
 	}()
 }
+
+func TestDataAPIKeys(t *testing.T) {
+	tHandler := &tHTTPHandler{}
+	s := Server{dataEnabled: 1}
+	f := s.LimitRate(tHandler)
+
+	const key = "abc"
+	if err := s.AddDataAPIKey("k1", HashDataAPIKey(key), "nonsense", "", 1); err == nil {
+		t.Fatalf("no error for unknown tier")
+	}
+	if err := s.AddDataAPIKey("k1", HashDataAPIKey(key), "basic", "partner", 1); err != nil {
+		t.Fatalf("AddDataAPIKey error: %v", err)
+	}
+	if err := s.AddDataAPIKey("k1", HashDataAPIKey("def"), "basic", "", 2); err == nil {
+		t.Fatalf("no error for duplicate ID")
+	}
+
+	send := func(apiKey string, inQuery bool) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/spots", nil)
+		req.RemoteAddr = "10.0.0.7:4321"
+		if inQuery {
+			req.URL.RawQuery = dataAPIKeyParam + "=" + apiKey
+		} else if apiKey != "" {
+			req.Header.Set(DataAPIKeyHeader, apiKey)
+		}
+		recorder := httptest.NewRecorder()
+		f.ServeHTTP(recorder, req)
+		return recorder.Result().StatusCode
+	}
+
+	if code := send("def", false); code != http.StatusUnauthorized {
+		t.Fatalf("wrong status code for unknown key: %d", code)
+	}
+
+	// The key's tier allows more than the anonymous limits.
+	burst := DataTiers["basic"].Burst
+	for i := 0; i < burst; i++ {
+		if code := send(key, i%2 == 0); code != http.StatusOK {
+			t.Fatalf("request %d with key failed with status %d", i, code)
+		}
+	}
+	if code := send(key, false); code != http.StatusTooManyRequests {
+		t.Fatalf("wrong status code after key's burst: %d", code)
+	}
+	// Anonymous requests from the same address are limited separately.
+	if code := send("", false); code != http.StatusOK {
+		t.Fatalf("anonymous request failed with status %d", code)
+	}
+
+	usage := s.DataAPIKeyUsage()
+	if len(usage) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(usage))
+	}
+	u := usage[0]
+	if u.ID != "k1" || u.Tier != "basic" || u.Note != "partner" ||
+		u.Requests != uint64(burst) || u.Throttled != 1 || u.LastUsed == 0 {
+		t.Fatalf("wrong usage: %+v", u)
+	}
+
+	if !s.RemoveDataAPIKey("k1") {
+		t.Fatalf("key not removed")
+	}
+	if s.RemoveDataAPIKey("k1") {
+		t.Fatalf("key removed twice")
+	}
+	if code := send(key, false); code != http.StatusUnauthorized {
+		t.Fatalf("wrong status code for removed key: %d", code)
+	}
+	if n := atomic.LoadUint32(&tHandler.count); n != uint32(burst)+1 {
+		t.Fatalf("expected %d requests handled, got %d", burst+1, n)
+	}
+}
@@ -0,0 +1,270 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package comms
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DataAPIKeyHeader is the HTTP header with which a client presents a data
+	// API key, both with data API requests and when opening a websocket
+	// connection.
+	DataAPIKeyHeader = "X-API-Key"
+	// dataAPIKeyParam is the URL query parameter that may be used instead of
+	// DataAPIKeyHeader, e.g. by browser websocket clients that cannot set
+	// headers.
+	dataAPIKeyParam = "apikey"
+)
+
+// DataTier is a rate tier for data API keys. Requests with a key are limited
+// by the key's tier instead of the global and per-IP data API limits, so
+// partners and aggregators with heavier access do not reduce the capacity
+// available to anonymous clients.
+type DataTier struct {
+	Name string `json:"name"`
+	// RatePerSec and Burst limit the data API requests made with the key,
+	// over HTTP and websocket combined.
+	RatePerSec float64 `json:"ratePerSec"`
+	Burst      int     `json:"burst"`
+	// SubsRatePerSec and SubsBurst limit the order book and price feed
+	// subscriptions on each websocket connection made with the key.
+	SubsRatePerSec float64 `json:"subsRatePerSec"`
+	SubsBurst      int     `json:"subsBurst"`
+	// MaxConns is the number of websocket connections that may be made with
+	// the key, from any address.
+	MaxConns int64 `json:"maxConns"`
+}
+
+// DataTiers are the rate tiers that may be assigned to data API keys. For
+// reference, anonymous clients are limited to ipMaxRatePerSec data API
+// requests per second and rpcMaxConnsPerIP connections per address.
+var DataTiers = map[string]*DataTier{
+	"basic": {
+		Name:           "basic",
+		RatePerSec:     5,
+		Burst:          50,
+		SubsRatePerSec: 1,
+		SubsBurst:      200,
+		MaxConns:       16,
+	},
+	"partner": {
+		Name:           "partner",
+		RatePerSec:     20,
+		Burst:          200,
+		SubsRatePerSec: 5,
+		SubsBurst:      500,
+		MaxConns:       32,
+	},
+	"aggregator": {
+		Name:           "aggregator",
+		RatePerSec:     100,
+		Burst:          1000,
+		SubsRatePerSec: 20,
+		SubsBurst:      2000,
+		MaxConns:       64,
+	},
+}
+
+// SortedDataTiers returns the DataTiers, from lowest to highest rate.
+func SortedDataTiers() []*DataTier {
+	tiers := make([]*DataTier, 0, len(DataTiers))
+	for _, tier := range DataTiers {
+		tiers = append(tiers, tier)
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].RatePerSec < tiers[j].RatePerSec
+	})
+	return tiers
+}
+
+// HashDataAPIKey is the hash of a data API key by which it is stored and
+// looked up.
+func HashDataAPIKey(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
+
+// DataAPIKeyUsage is a data API key's tier and usage since the server was
+// started.
+type DataAPIKeyUsage struct {
+	ID    string `json:"id"`
+	Tier  string `json:"tier"`
+	Note  string `json:"note,omitempty"`
+	Stamp int64  `json:"stamp"` // unix ms
+	// Requests is the number of data API requests allowed.
+	Requests uint64 `json:"requests"`
+	// Throttled is the number of data API requests denied by the rate limit.
+	Throttled uint64 `json:"throttled"`
+	// Conns is the number of active websocket connections.
+	Conns int64 `json:"conns"`
+	// LastUsed is the time of the last request, in unix ms, or zero.
+	LastUsed int64 `json:"lastUsed"`
+}
+
+// dataKey is a registered data API key.
+type dataKey struct {
+	id      string
+	tier    *DataTier
+	note    string
+	stamp   int64
+	limiter *rate.Limiter
+
+	requests  atomic.Uint64
+	throttled atomic.Uint64
+	conns     atomic.Int64
+	lastUsed  atomic.Int64
+	revoked   atomic.Bool
+}
+
+func (k *dataKey) usage() *DataAPIKeyUsage {
+	return &DataAPIKeyUsage{
+		ID:        k.id,
+		Tier:      k.tier.Name,
+		Note:      k.note,
+		Stamp:     k.stamp,
+		Requests:  k.requests.Load(),
+		Throttled: k.throttled.Load(),
+		Conns:     k.conns.Load(),
+		LastUsed:  k.lastUsed.Load(),
+	}
+}
+
+// AddDataAPIKey registers a data API key by its hash. See HashDataAPIKey.
+func (s *Server) AddDataAPIKey(id string, keyHash []byte, tierName, note string, stamp int64) error {
+	tier := DataTiers[tierName]
+	if tier == nil {
+		return fmt.Errorf("unknown data API tier %q", tierName)
+	}
+	s.dataKeyMtx.Lock()
+	defer s.dataKeyMtx.Unlock()
+	if s.dataKeys == nil {
+		s.dataKeys = make(map[string]*dataKey)
+	}
+	for _, k := range s.dataKeys {
+		if k.id == id {
+			return fmt.Errorf("duplicate data API key ID %s", id)
+		}
+	}
+	s.dataKeys[string(keyHash)] = &dataKey{
+		id:      id,
+		tier:    tier,
+		note:    note,
+		stamp:   stamp,
+		limiter: rate.NewLimiter(rate.Limit(tier.RatePerSec), tier.Burst),
+	}
+	return nil
+}
+
+// RemoveDataAPIKey unregisters the data API key with the ID, and disconnects
+// any websocket clients using it. The returned bool is false if there was no
+// such key.
+func (s *Server) RemoveDataAPIKey(id string) bool {
+	s.dataKeyMtx.Lock()
+	var key *dataKey
+	for h, k := range s.dataKeys {
+		if k.id == id {
+			key = k
+			delete(s.dataKeys, h)
+			break
+		}
+	}
+	s.dataKeyMtx.Unlock()
+	if key == nil {
+		return false
+	}
+	key.revoked.Store(true)
+
+	s.clientMtx.RLock()
+	defer s.clientMtx.RUnlock()
+	for _, link := range s.clients {
+		if link.dataKey == key {
+			link.Disconnect()
+		}
+	}
+	return true
+}
+
+// DataAPIKeyUsage returns the usage of each registered data API key.
+func (s *Server) DataAPIKeyUsage() []*DataAPIKeyUsage {
+	s.dataKeyMtx.RLock()
+	usage := make([]*DataAPIKeyUsage, 0, len(s.dataKeys))
+	for _, k := range s.dataKeys {
+		usage = append(usage, k.usage())
+	}
+	s.dataKeyMtx.RUnlock()
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Stamp < usage[j].Stamp
+	})
+	return usage
+}
+
+// dataKeyFromRequest gets the data API key presented with the request, if
+// any. An error is returned if a key is presented but is not registered.
+func (s *Server) dataKeyFromRequest(r *http.Request) (*dataKey, error) {
+	key := r.Header.Get(DataAPIKeyHeader)
+	if key == "" && r.URL != nil {
+		key = r.URL.Query().Get(dataAPIKeyParam)
+	}
+	return s.lookupDataKey(key)
+}
+
+// dataKeyFromHeader gets the data API key presented in the header of a QUIC
+// client's hello, if any. An error is returned if a key is presented but is
+// not registered.
+func (s *Server) dataKeyFromHeader(header http.Header) (*dataKey, error) {
+	return s.lookupDataKey(header.Get(DataAPIKeyHeader))
+}
+
+// lookupDataKey gets the registered data API key. It is nil if key is empty.
+func (s *Server) lookupDataKey(key string) (*dataKey, error) {
+	if key == "" {
+		return nil, nil
+	}
+	s.dataKeyMtx.RLock()
+	k := s.dataKeys[string(HashDataAPIKey(key))]
+	s.dataKeyMtx.RUnlock()
+	if k == nil {
+		return nil, errors.New("unknown API key")
+	}
+	return k, nil
+}
+
+// meterKey applies the dataEnabled flag and the data API key's rate limiter.
+// Requests with a key are not subject to the global or IP-based limiters.
+func (s *Server) meterKey(k *dataKey) (int, error) {
+	if atomic.LoadUint32(&s.dataEnabled) != 1 {
+		return http.StatusServiceUnavailable, fmt.Errorf("data API is disabled")
+	}
+	if k.revoked.Load() {
+		return http.StatusUnauthorized, fmt.Errorf("API key revoked")
+	}
+	k.lastUsed.Store(time.Now().UnixMilli())
+	if !k.limiter.Allow() {
+		k.throttled.Add(1)
+		return http.StatusTooManyRequests, fmt.Errorf("too many requests for API key tier %s", k.tier.Name)
+	}
+	k.requests.Add(1)
+	return 0, nil
+}
+
+// newDataKeyRouteLimiter creates a route-based rate limiter for a websocket
+// connection made with a data API key. The market feed subscription limits
+// are those of the key's tier.
+func newDataKeyRouteLimiter(tier *DataTier) *routeLimiter {
+	rl := newRouteLimiter()
+	subsLimiter := rate.NewLimiter(rate.Limit(tier.SubsRatePerSec), tier.SubsBurst)
+	rl.routes[msgjson.OrderBookRoute] = subsLimiter
+	rl.routes[msgjson.PriceFeedRoute] = subsLimiter
+	return rl
+}
@@ -0,0 +1,244 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package comms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/msgpb"
+	"decred.org/dcrdex/dex/ws"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// errGRPCConnClosed is returned from grpcConn methods after Close.
+var errGRPCConnClosed = errors.New("grpc stream closed")
+
+// grpcConn adapts a gRPC stream to the ws.Connection interface, so that gRPC
+// clients are served by a wsLink with the same route handlers, rate limiters,
+// and message semantics as websocket clients. Liveness of the stream is
+// checked by gRPC keepalives, so read deadlines and pings are ignored.
+type grpcConn struct {
+	// in delivers messages from the client. It is closed when the client
+	// closes its side of the stream.
+	in chan *msgpb.Message
+	// send sends a message to the client. It is only called from the
+	// wsLink's output goroutine.
+	send func(*msgpb.Message) error
+
+	readLimit atomic.Int64
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ ws.Connection = (*grpcConn)(nil)
+
+// newGRPCConn is the constructor for a grpcConn.
+func newGRPCConn(inBuffer int, send func(*msgpb.Message) error) *grpcConn {
+	return &grpcConn{
+		in:   make(chan *msgpb.Message, inBuffer),
+		send: send,
+		done: make(chan struct{}),
+	}
+}
+
+// ReadMessage returns the next message from the client, JSON-encoded as a
+// websocket text frame.
+func (c *grpcConn) ReadMessage() (int, []byte, error) {
+	select {
+	case m, ok := <-c.in:
+		if !ok {
+			return 0, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}
+		}
+		b, err := json.Marshal(m.MsgJSON())
+		if err != nil {
+			return 0, nil, err
+		}
+		if limit := c.readLimit.Load(); limit > 0 && int64(len(b)) > limit {
+			return 0, nil, websocket.ErrReadLimit
+		}
+		return websocket.TextMessage, b, nil
+	case <-c.done:
+		return 0, nil, errGRPCConnClosed
+	}
+}
+
+// WriteMessage decodes the JSON-encoded message and sends it to the client.
+func (c *grpcConn) WriteMessage(_ int, b []byte) error {
+	select {
+	case <-c.done:
+		return errGRPCConnClosed
+	default:
+	}
+	msg := new(msgjson.Message)
+	if err := msg.Decode(b, msgjson.JSONEncoding); err != nil {
+		return err
+	}
+	m, err := msgpb.FromMsgJSON(msg)
+	if err != nil {
+		return err
+	}
+	return c.send(m)
+}
+
+// SetReadLimit sets the maximum size of a JSON-encoded message from the
+// client.
+func (c *grpcConn) SetReadLimit(limit int64) {
+	c.readLimit.Store(limit)
+}
+
+// SetReadDeadline is a no-op. gRPC keepalives detect dead streams.
+func (c *grpcConn) SetReadDeadline(time.Time) error { return nil }
+
+// SetWriteDeadline is a no-op. gRPC flow control applies to writes.
+func (c *grpcConn) SetWriteDeadline(time.Time) error { return nil }
+
+// WriteControl is a no-op. There are no control frames for a gRPC stream.
+func (c *grpcConn) WriteControl(int, []byte, time.Time) error { return nil }
+
+// Close signals the end of the stream. The gRPC handler returns when the link
+// is done, which ends the stream for the client.
+func (c *grpcConn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return nil
+}
+
+// grpcService implements msgpb.DEXServer.
+type grpcService struct {
+	msgpb.UnimplementedDEXServer
+	s *Server
+}
+
+// newGRPCServer creates a grpc.Server with the DEX service registered.
+func (s *Server) newGRPCServer() *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    pingPeriod,
+			Timeout: pongWait,
+		}),
+		grpc.MaxRecvMsgSize(readLimitAuthorized),
+	}
+	if s.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	srv := grpc.NewServer(opts...)
+	msgpb.RegisterDEXServer(srv, &grpcService{s: s})
+	return srv
+}
+
+// serve checks that a new gRPC client may connect, then serves the grpcConn
+// like a websocket connection, blocking until the link is done.
+func (g *grpcService) serve(ctx context.Context, conn *grpcConn) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Internal, "unknown peer")
+	}
+	ip := dex.NewIPKey(p.Addr.String())
+	if g.s.isQuarantined(ip) {
+		return status.Error(codes.PermissionDenied, "unauthorized")
+	}
+	if g.s.clientCount() >= rpcMaxClients {
+		return status.Error(codes.Unavailable, "server at maximum capacity")
+	}
+	if g.s.ipConnCount(ip) >= rpcMaxConnsPerIP {
+		return status.Error(codes.ResourceExhausted, "too many connections from your address")
+	}
+	log.Debugf("Starting gRPC handler for %s", p.Addr)
+	g.s.websocketHandler(ctx, conn, ip, nil)
+	return nil
+}
+
+// Request handles a single request on a short-lived link, returning the
+// response.
+func (g *grpcService) Request(ctx context.Context, req *msgpb.Message) (*msgpb.Message, error) {
+	if msgjson.MessageType(req.GetType()) != msgjson.Request {
+		return nil, status.Errorf(codes.InvalidArgument, "message type %d is not a request", req.GetType())
+	}
+	respC := make(chan *msgpb.Message, 1)
+	conn := newGRPCConn(1, func(m *msgpb.Message) error {
+		if msgjson.MessageType(m.GetType()) == msgjson.Response && m.GetId() == req.GetId() {
+			select {
+			case respC <- m:
+			default:
+			}
+		}
+		return nil
+	})
+	conn.in <- req
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errC := make(chan error, 1)
+	go func() { errC <- g.serve(ctx, conn) }()
+
+	select {
+	case resp := <-respC:
+		conn.Close()
+		<-errC
+		return resp, nil
+	case err := <-errC:
+		if err == nil {
+			err = status.Error(codes.Unavailable, "connection closed without a response")
+		}
+		return nil, err
+	case <-time.After(rpcTimeoutSeconds * time.Second):
+		conn.Close()
+		<-errC
+		return nil, status.Error(codes.DeadlineExceeded, "timed out waiting for response")
+	}
+}
+
+// Connect serves the stream like a websocket connection.
+func (g *grpcService) Connect(stream msgpb.DEX_ConnectServer) error {
+	conn := newGRPCConn(0, stream.Send)
+	ctx := stream.Context()
+	go func() {
+		defer close(conn.in)
+		for {
+			m, err := stream.Recv()
+			if err != nil {
+				return // io.EOF if the client closed the stream
+			}
+			select {
+			case conn.in <- m:
+			case <-conn.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return g.serve(ctx, conn)
+}
+
+// Book subscribes to the order book feed for the market and streams the
+// snapshot and book updates.
+func (g *grpcService) Book(sub *msgpb.BookSubscription, stream msgpb.DEX_BookServer) error {
+	req, err := msgjson.NewRequest(1, msgjson.OrderBookRoute, &msgjson.OrderBookSubscription{
+		Base:  sub.GetBase(),
+		Quote: sub.GetQuote(),
+	})
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid subscription: %v", err)
+	}
+	m, err := msgpb.FromMsgJSON(req)
+	if err != nil {
+		return status.Errorf(codes.Internal, "error encoding subscription: %v", err)
+	}
+	conn := newGRPCConn(1, stream.Send)
+	conn.in <- m
+	return g.serve(stream.Context(), conn)
+}
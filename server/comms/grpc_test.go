@@ -0,0 +1,115 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package comms
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/msgpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestGRPC(t *testing.T) {
+	server := newServer()
+	server.RegisterHTTP("grpchttp", func(any) (any, error) {
+		return map[string]string{"key": "value"}, nil
+	})
+	server.Route("grpcping", func(c Link, msg *msgjson.Message) *msgjson.Error {
+		// Respond, then send a notification.
+		resp, _ := msgjson.NewResponse(msg.ID, "pong", nil)
+		if err := c.Send(resp); err != nil {
+			t.Errorf("error sending response: %v", err)
+		}
+		ntfn, _ := msgjson.NewNotification("grpcntfn", "hi")
+		if err := c.Send(ntfn); err != nil {
+			t.Errorf("error sending notification: %v", err)
+		}
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error creating listener: %v", err)
+	}
+	grpcServer := server.newGRPCServer()
+	go grpcServer.Serve(listener)
+	defer func() {
+		server.disconnectClients()
+		grpcServer.Stop()
+	}()
+
+	cc, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("error dialing: %v", err)
+	}
+	defer cc.Close()
+	client := msgpb.NewDEXClient(cc)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Unary request for an HTTP route.
+	req, _ := msgjson.NewRequest(5, "grpchttp", nil)
+	pbReq, _ := msgpb.FromMsgJSON(req)
+	pbResp, err := client.Request(ctx, pbReq)
+	if err != nil {
+		t.Fatalf("Request error: %v", err)
+	}
+	var res map[string]string
+	if err = pbResp.MsgJSON().UnmarshalResult(&res); err != nil {
+		t.Fatalf("error decoding result: %v", err)
+	}
+	if pbResp.GetId() != 5 || res["key"] != "value" {
+		t.Fatalf("wrong response: %v", pbResp)
+	}
+
+	// Not a request.
+	ntfn, _ := msgjson.NewNotification("grpchttp", nil)
+	pbNtfn, _ := msgpb.FromMsgJSON(ntfn)
+	if _, err = client.Request(ctx, pbNtfn); err == nil {
+		t.Fatalf("no error for notification")
+	}
+
+	// A stream works like a websocket connection.
+	stream, err := client.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	req, _ = msgjson.NewRequest(6, "grpcping", nil)
+	pbReq, _ = msgpb.FromMsgJSON(req)
+	if err = stream.Send(pbReq); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	pbResp, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv error: %v", err)
+	}
+	var pong string
+	if err = pbResp.MsgJSON().UnmarshalResult(&pong); err != nil || pong != "pong" {
+		t.Fatalf("wrong response %v, err = %v", pbResp, err)
+	}
+	pbNtfn, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv error: %v", err)
+	}
+	if pbNtfn.GetRoute() != "grpcntfn" || !json.Valid(pbNtfn.GetPayload()) {
+		t.Fatalf("wrong notification: %v", pbNtfn)
+	}
+	if server.clientCount() != 1 {
+		t.Fatalf("expected 1 client, got %d", server.clientCount())
+	}
+
+	// Closing the stream removes the client.
+	if err = stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend error: %v", err)
+	}
+	if !giveItASecond(func() bool { return server.clientCount() == 0 }) {
+		t.Fatalf("client not removed after closing stream")
+	}
+}
@@ -4,7 +4,6 @@
 package comms
 
 import (
-	"encoding/json"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -32,10 +31,15 @@ type Link interface {
 	Addr() string
 	// Send sends the msgjson.Message to the peer.
 	Send(msg *msgjson.Message) error
-	// SendRaw sends the raw bytes which is assumed to be a marshalled
-	// msgjson.Message to the peer. Can be used to avoid marshalling the
-	// same message multiple times.
+	// SendRaw sends the raw bytes which is assumed to be a msgjson.Message
+	// marshalled with the link's Encoding to the peer. Can be used to avoid
+	// marshalling the same message multiple times.
 	SendRaw(b []byte) error
+	// Encoding is the negotiated message encoding for the link.
+	Encoding() msgjson.Encoding
+	// APIVersion is the communications API version negotiated via the
+	// version route. It is zero if the client did not negotiate a version.
+	APIVersion() uint16
 	// SendError sends the msgjson.Error to the peer, with reference to a
 	// request message ID.
 	SendError(id uint64, rpcErr *msgjson.Error)
@@ -70,6 +74,8 @@ type wsLink struct {
 	// The id is the unique identifier assigned to this client.
 	id       uint64
 	customID atomic.Value
+	// apiVer is the negotiated communications API version.
+	apiVer atomic.Uint32
 	// For DEX-originating requests, the response handler is mapped to the
 	// resquest ID.
 	reqMtx       sync.Mutex
@@ -83,6 +89,8 @@ type wsLink struct {
 	dataMeter func() (int, error)
 	// wsLimiter is a route-based rate limiter. This applies to rpcRoutes.
 	wsLimiter *routeLimiter
+	// dataKey is the data API key presented by the client, if any.
+	dataKey *dataKey
 }
 
 // newWSLink is a constructor for a new wsLink.
@@ -121,6 +129,12 @@ func (c *wsLink) CustomID() string {
 	return ""
 }
 
+// APIVersion is the communications API version negotiated via the version
+// route. It is zero if the client did not negotiate a version.
+func (c *wsLink) APIVersion() uint16 {
+	return uint16(c.apiVer.Load())
+}
+
 // Addr returns the string-encoded IP address.
 func (c *wsLink) Addr() string {
 	return c.WSLink.Addr()
@@ -142,6 +156,14 @@ func (s *Server) handleMessage(c *wsLink, msg *msgjson.Message) *msgjson.Error {
 		if msg.ID == 0 {
 			return msgjson.NewError(msgjson.RPCParseError, "request id cannot be zero")
 		}
+		// Version negotiation is handled by the Server since the result
+		// applies to the link itself.
+		if msg.Route == msgjson.VersionRoute {
+			if !c.wsLimiter.allow(msg.Route) {
+				return msgjson.NewError(msgjson.TooManyRequestsError, "too many requests to %s", msg.Route)
+			}
+			return s.handleVersion(c, msg)
+		}
 		// Look for a registered WebSocket route handler. This excludes the data
 		// API routes, which are part of the httpHandler map.
 		handler := s.rpcRoutes[msg.Route]
@@ -229,6 +251,31 @@ func (s *Server) handleMessage(c *wsLink, msg *msgjson.Message) *msgjson.Error {
 	return msgjson.NewError(msgjson.UnknownMessageType, "unknown message type")
 }
 
+// handleVersion handles the version route, selecting the highest
+// communications API version supported by both the client and the server and
+// recording it for the link.
+func (s *Server) handleVersion(c *wsLink, msg *msgjson.Message) *msgjson.Error {
+	req := new(msgjson.VersionRequest)
+	if err := msg.Unmarshal(req); err != nil {
+		return msgjson.NewError(msgjson.RPCParseError, "error parsing version request")
+	}
+	ver, found := msgjson.NegotiateVersion(s.apiVersions, req.Versions)
+	if !found {
+		return msgjson.NewError(msgjson.RPCVersionUnsupported,
+			"no supported API version in %v, server supports %v", req.Versions, s.apiVersions)
+	}
+	c.apiVer.Store(uint32(ver))
+	resp, err := msgjson.NewResponse(msg.ID, &msgjson.VersionResult{Version: ver}, nil)
+	if err != nil {
+		log.Errorf("Error encoding version response: %v", err)
+		return msgjson.NewError(msgjson.RPCInternal, "internal error")
+	}
+	if err = c.Send(resp); err != nil {
+		log.Errorf("Error sending version response to %s: %v", c.Addr(), err)
+	}
+	return nil
+}
+
 func (c *wsLink) expire(id uint64) bool {
 	c.reqMtx.Lock()
 	defer c.reqMtx.Unlock()
@@ -261,7 +308,7 @@ func (c *wsLink) logReq(id uint64, respHandler func(Link, *msgjson.Message), exp
 // is equal to the response Message.ID passed to the handler (see the
 // msgjson.Response case in handleMessage).
 func (c *wsLink) Request(msg *msgjson.Message, f func(conn Link, msg *msgjson.Message), expireTime time.Duration, expire func()) error {
-	rawMsg, err := json.Marshal(msg)
+	rawMsg, err := msg.Encode(c.Encoding())
 	if err != nil {
 		log.Errorf("Failed to marshal message: %v", err)
 		return err
@@ -20,10 +20,21 @@ const (
 )
 
 // LimitRate is rate-limiting middleware that checks whether a request can be
-// fulfilled. This is intended for the /api HTTP endpoints.
+// fulfilled. This is intended for the /api HTTP endpoints. Requests with a
+// data API key are limited by the key's tier.
 func (s *Server) LimitRate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		code, err := s.meterIP(dex.NewIPKey(r.RemoteAddr))
+		dataKey, err := s.dataKeyFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var code int
+		if dataKey != nil {
+			code, err = s.meterKey(dataKey)
+		} else {
+			code, err = s.meterIP(dex.NewIPKey(r.RemoteAddr))
+		}
 		if err != nil {
 			http.Error(w, err.Error(), code)
 			return
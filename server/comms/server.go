@@ -13,6 +13,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -24,7 +25,9 @@ import (
 	"github.com/decred/dcrd/certgen"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/quic-go/quic-go"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -173,6 +176,17 @@ type RPCConfig struct {
 	AltDNSNames []string
 	// DisableDataAPI will disable all traffic to the HTTP data API routes.
 	DisableDataAPI bool
+	// GRPCListenAddrs are the addresses on which the gRPC service will
+	// listen. The gRPC service is disabled if there are none. The TLS
+	// settings are the same as for ListenAddrs.
+	GRPCListenAddrs []string
+	// APIVersions are the communications API versions that the server will
+	// negotiate with clients via the version route.
+	APIVersions []uint16
+	// QUIC enables the QUIC transport on the UDP ports of ListenAddrs. The port
+	// is advertised in websocket handshakes, and clients that prefer QUIC use
+	// it instead of a websocket for later connections. QUIC requires TLS.
+	QUIC bool
 }
 
 // allower is satisfied by rate.Limiter.
@@ -216,8 +230,9 @@ func newRouteLimiter() *routeLimiter {
 			// Connect (authorize) route
 			msgjson.ConnectRoute: rate.NewLimiter(wsRateConnect, wsBurstConnect),
 			// Status checking of matches and orders
-			msgjson.MatchStatusRoute: statusLimiter,
-			msgjson.OrderStatusRoute: statusLimiter,
+			msgjson.MatchStatusRoute:  statusLimiter,
+			msgjson.OrderStatusRoute:  statusLimiter,
+			msgjson.SwapProgressRoute: statusLimiter,
 			// Order submission
 			msgjson.LimitRoute:  orderLimiter,
 			msgjson.MarketRoute: orderLimiter,
@@ -225,11 +240,13 @@ func newRouteLimiter() *routeLimiter {
 			// Order book and price feed subscriptions
 			msgjson.OrderBookRoute: marketSubsLimiter,
 			msgjson.PriceFeedRoute: marketSubsLimiter,
-			// Config, fee rate, spot prices, and candles
-			msgjson.FeeRateRoute: infoLimiter,
-			msgjson.ConfigRoute:  infoLimiter,
-			msgjson.SpotsRoute:   infoLimiter,
-			msgjson.CandlesRoute: infoLimiter,
+			// Config, fee rate, spot prices, candles, and epoch proofs
+			msgjson.FeeRateRoute:    infoLimiter,
+			msgjson.ConfigRoute:     infoLimiter,
+			msgjson.VersionRoute:    infoLimiter,
+			msgjson.SpotsRoute:      infoLimiter,
+			msgjson.CandlesRoute:    infoLimiter,
+			msgjson.EpochProofRoute: infoLimiter,
 		},
 	}
 }
@@ -248,6 +265,14 @@ type Server struct {
 	mux *chi.Mux
 	// One listener for each address specified at (RPCConfig).ListenAddrs.
 	listeners []net.Listener
+	// One listener for each address specified at
+	// (RPCConfig).GRPCListenAddrs.
+	grpcListeners []net.Listener
+	// One QUIC listener for each address specified at
+	// (RPCConfig).ListenAddrs if (RPCConfig).QUIC is set.
+	quicListeners []*ws.QUICListener
+	// tlsConfig is nil if TLS is disabled.
+	tlsConfig *tls.Config
 
 	// The client map indexes each wsLink by its id.
 	clientMtx sync.RWMutex
@@ -268,10 +293,18 @@ type Server struct {
 
 	dataEnabled uint32 // atomic
 
+	// dataKeys are the registered data API keys, keyed by the hash of the
+	// key. See HashDataAPIKey.
+	dataKeyMtx sync.RWMutex
+	dataKeys   map[string]*dataKey
+
 	// rpcRoutes maps message routes to the handlers.
 	rpcRoutes map[string]MsgHandler
 	// httpRoutes maps HTTP routes to the handlers.
 	httpRoutes map[string]HTTPHandler
+	// apiVersions are the communications API versions supported for
+	// negotiation.
+	apiVersions []uint16
 }
 
 // NewServer constructs a Server that should be started with Run. The server is
@@ -363,6 +396,58 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 	if len(listeners) == 0 {
 		return nil, fmt.Errorf("RPCS: No valid listen address")
 	}
+
+	// The QUIC listeners are on the UDP ports with the same numbers as the
+	// TCP listeners.
+	var quicListeners []*ws.QUICListener
+	if cfg.QUIC {
+		if cfg.NoTLS {
+			return nil, fmt.Errorf("QUIC requires TLS")
+		}
+		for _, listener := range listeners {
+			if _, isOnion := listener.(onionListener); isOnion {
+				continue
+			}
+			tcpAddr, ok := listener.Addr().(*net.TCPAddr)
+			if !ok {
+				continue
+			}
+			network := "udp4"
+			if tcpAddr.IP.To4() == nil {
+				network = "udp6"
+			}
+			quicListener, err := ws.ListenQUIC(network, tcpAddr.String(), tlsConfig)
+			if err != nil {
+				for _, l := range quicListeners {
+					l.Close()
+				}
+				return nil, fmt.Errorf("cannot listen for QUIC on %s: %w", tcpAddr, err)
+			}
+			quicListeners = append(quicListeners, quicListener)
+		}
+	}
+
+	// The gRPC listeners do not use tls.Listen since the grpc.Server performs
+	// the TLS handshake itself.
+	var grpcListeners []net.Listener
+	if len(cfg.GRPCListenAddrs) > 0 {
+		ipv4ListenAddrs, ipv6ListenAddrs, _, err := parseListeners(cfg.GRPCListenAddrs)
+		if err != nil {
+			return nil, err
+		}
+		for _, addrs := range []struct {
+			network string
+			addrs   []string
+		}{{"tcp4", ipv4ListenAddrs}, {"tcp6", ipv6ListenAddrs}} {
+			for _, addr := range addrs.addrs {
+				listener, err := net.Listen(addrs.network, addr)
+				if err != nil {
+					return nil, fmt.Errorf("cannot listen on %s: %w", addr, err)
+				}
+				grpcListeners = append(grpcListeners, listener)
+			}
+		}
+	}
 	var dataEnabled uint32 = 1
 	if cfg.DisableDataAPI {
 		dataEnabled = 0
@@ -374,15 +459,20 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 	mux.Use(middleware.Recoverer)
 
 	return &Server{
-		mux:         mux,
-		listeners:   listeners,
-		clients:     make(map[uint64]*wsLink),
-		wsLimiters:  make(map[dex.IPKey]*ipWsLimiter),
-		v6Prefixes:  make(map[dex.IPKey]int),
-		quarantine:  make(map[dex.IPKey]time.Time),
-		dataEnabled: dataEnabled,
-		rpcRoutes:   make(map[string]MsgHandler),
-		httpRoutes:  make(map[string]HTTPHandler),
+		mux:           mux,
+		listeners:     listeners,
+		grpcListeners: grpcListeners,
+		quicListeners: quicListeners,
+		tlsConfig:     tlsConfig,
+		clients:       make(map[uint64]*wsLink),
+		wsLimiters:    make(map[dex.IPKey]*ipWsLimiter),
+		v6Prefixes:    make(map[dex.IPKey]int),
+		quarantine:    make(map[dex.IPKey]time.Time),
+		dataEnabled:   dataEnabled,
+		dataKeys:      make(map[string]*dataKey),
+		rpcRoutes:     make(map[string]MsgHandler),
+		httpRoutes:    make(map[string]HTTPHandler),
+		apiVersions:   cfg.APIVersions,
 	}, nil
 }
 
@@ -397,24 +487,29 @@ func (s *Server) Run(ctx context.Context) {
 	// Websocket endpoint.
 	mux.Get("/ws", func(w http.ResponseWriter, r *http.Request) {
 		ip := dex.NewIPKey(r.RemoteAddr)
-		if s.isQuarantined(ip) {
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-			return
-		}
-		if s.clientCount() >= rpcMaxClients {
-			http.Error(w, "server at maximum capacity", http.StatusServiceUnavailable)
+		// Check the connection counts before upgrading the conn so we can
+		// send an HTTP error code, but they are checked again after
+		// upgrade/hijack so they cannot initiate many simultaneously.
+		dataKey, status, err := s.admitClient(ip, func() (*dataKey, error) {
+			return s.dataKeyFromRequest(r)
+		})
+		if err != nil {
+			http.Error(w, err.Error(), status)
 			return
 		}
 
-		// Check websocket connection count for this IP before upgrading the
-		// conn so we can send an HTTP error code, but check again after
-		// upgrade/hijack so they cannot initiate many simultaneously.
-		if s.ipConnCount(ip) >= rpcMaxConnsPerIP {
-			http.Error(w, "too many connections from your address", http.StatusServiceUnavailable)
-			return
+		// Advertise the QUIC listener on the UDP port with the same number as
+		// the TCP listener.
+		var respHeader http.Header
+		if l, ok := r.Context().Value(ctxListener).(net.Listener); ok && len(s.quicListeners) > 0 {
+			if tcpAddr, ok := l.Addr().(*net.TCPAddr); ok {
+				if _, isOnion := l.(onionListener); !isOnion {
+					respHeader = http.Header{ws.QUICPortHeader: []string{strconv.Itoa(tcpAddr.Port)}}
+				}
+			}
 		}
 
-		wsConn, err := ws.NewConnection(w, r, pongWait)
+		wsConn, err := ws.NewConnectionWithHeader(w, r, pongWait, respHeader)
 		if err != nil {
 			if errors.Is(err, ws.ErrHandshake) {
 				log.Debug(err)
@@ -439,7 +534,7 @@ func (s *Server) Run(ctx context.Context) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			s.websocketHandler(ctx, wsConn, ip)
+			s.websocketHandler(ctx, wsConn, ip, dataKey)
 		}()
 	})
 
@@ -466,6 +561,47 @@ func (s *Server) Run(ctx context.Context) {
 		}(listener)
 	}
 
+	// Accept QUIC connections.
+	for _, listener := range s.quicListeners {
+		wg.Add(1)
+		go func(listener *ws.QUICListener) {
+			defer wg.Done()
+			log.Infof("Server listening for QUIC on %s", listener.Addr())
+			for {
+				qc, err := listener.Accept(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Warnf("unexpected QUIC Accept error: %v", err)
+					}
+					break
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					s.quicHandler(ctx, qc)
+				}()
+			}
+			log.Debugf("QUIC listener done for %s", listener.Addr())
+		}(listener)
+	}
+
+	// Start the gRPC service.
+	var grpcServer *grpc.Server
+	if len(s.grpcListeners) > 0 {
+		grpcServer = s.newGRPCServer()
+		for _, listener := range s.grpcListeners {
+			wg.Add(1)
+			go func(listener net.Listener) {
+				defer wg.Done()
+				log.Infof("gRPC server listening on %s", listener.Addr())
+				if err := grpcServer.Serve(listener); err != nil {
+					log.Warnf("unexpected (grpc.Server).Serve error: %v", err)
+				}
+				log.Debugf("gRPC listener done for %s", listener.Addr())
+			}(listener)
+		}
+	}
+
 	// Run a periodic routine to keep the ipHTTPRateLimiter map clean.
 	go func() {
 		ticker := time.NewTicker(time.Minute * 5)
@@ -500,9 +636,17 @@ func (s *Server) Run(ctx context.Context) {
 	// Stop and disconnect websocket clients.
 	s.disconnectClients()
 
+	// With the clients disconnected, the gRPC streams will end.
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
 	// When the http.Server is shut down, all websocket clients are gone, and
 	// the listener goroutines have returned, the server is shut down.
 	wg.Wait()
+	for _, listener := range s.quicListeners {
+		listener.Close()
+	}
 	log.Infof("Server shutdown complete")
 }
 
@@ -630,24 +774,100 @@ func (s *Server) wsLimiterDone(ip dex.IPKey) {
 	}
 }
 
+// admitClient checks whether a new websocket or QUIC connection from the IP
+// address may be established, and gets the data API key presented with it, if
+// any. If the connection is not allowed, the HTTP status code for the
+// rejection is returned with the error.
+func (s *Server) admitClient(ip dex.IPKey, getDataKey func() (*dataKey, error)) (*dataKey, int, error) {
+	if s.isQuarantined(ip) {
+		return nil, http.StatusUnauthorized, errors.New(http.StatusText(http.StatusUnauthorized))
+	}
+	if s.clientCount() >= rpcMaxClients {
+		return nil, http.StatusServiceUnavailable, errors.New("server at maximum capacity")
+	}
+	dataKey, err := getDataKey()
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	// Check the connection count for this IP, or for the data API key.
+	if dataKey != nil {
+		if dataKey.conns.Load() >= dataKey.tier.MaxConns {
+			return nil, http.StatusServiceUnavailable, errors.New("too many connections with your API key")
+		}
+	} else if s.ipConnCount(ip) >= rpcMaxConnsPerIP {
+		return nil, http.StatusServiceUnavailable, errors.New("too many connections from your address")
+	}
+	return dataKey, http.StatusOK, nil
+}
+
+// quicHandler handles a new QUIC connection. The client's hello is checked like
+// a websocket upgrade request, and if the connection is accepted, it is handled
+// by websocketHandler. This method should be run as a goroutine.
+func (s *Server) quicHandler(ctx context.Context, qc quic.Connection) {
+	// Check the quarantine and the connection counts before waiting for the
+	// hello, so that an address cannot hold open many connections that have
+	// not sent one. The data API key is not known yet, so the address's
+	// connection limit applies to clients with a key too.
+	ip := dex.NewIPKey(qc.RemoteAddr().String())
+	if _, status, err := s.admitClient(ip, func() (*dataKey, error) { return nil, nil }); err != nil {
+		ws.RejectQUIC(qc, status, err.Error())
+		return
+	}
+	conn, header, err := ws.AcceptQUIC(ctx, qc, pongWait)
+	if err != nil {
+		log.Debugf("QUIC connection from %s failed: %v", qc.RemoteAddr(), err)
+		return
+	}
+	// Check again with the data API key, which may have its own limits.
+	dataKey, status, err := s.admitClient(ip, func() (*dataKey, error) {
+		return s.dataKeyFromHeader(header)
+	})
+	if err != nil {
+		conn.Reject(status, err.Error())
+		return
+	}
+	if err := conn.Accept(); err != nil {
+		log.Debugf("QUIC connection from %s failed: %v", qc.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	log.Debugf("Starting QUIC handler for %s", qc.RemoteAddr()) // includes source port
+	s.websocketHandler(ctx, conn, ip, dataKey)
+}
+
 // websocketHandler handles a new websocket client by creating a new wsClient,
 // starting it, and blocking until the connection closes. This method should be
-// run as a goroutine.
-func (s *Server) websocketHandler(ctx context.Context, conn ws.Connection, ip dex.IPKey) {
+// run as a goroutine. If the client presented a data API key, dataKey is
+// non-nil, and the key's limits apply instead of those for the IP address.
+func (s *Server) websocketHandler(ctx context.Context, conn ws.Connection, ip dex.IPKey, dataKey *dataKey) {
 	addr := ip.String()
 	log.Tracef("New websocket client %s", addr)
 
 	// Create a new websocket client to handle the new websocket connection
 	// and wait for it to shutdown.  Once it has shutdown (and hence
 	// disconnected), remove it.
-	dataRoutesMeter := func() (int, error) { return s.meterIP(ip) } // includes global limiter and may be disabled
-	wsLimiter := s.wsLimiter(ip)
-	if wsLimiter == nil { // too many active ws conns from this IP
-		log.Warnf("Too many websocket connections from %v", ip)
-		return
+	var dataRoutesMeter func() (int, error)
+	var wsLimiter *routeLimiter
+	if dataKey != nil {
+		if dataKey.conns.Add(1) > dataKey.tier.MaxConns {
+			dataKey.conns.Add(-1)
+			log.Warnf("Too many websocket connections with data API key %s", dataKey.id)
+			return
+		}
+		defer dataKey.conns.Add(-1)
+		dataRoutesMeter = func() (int, error) { return s.meterKey(dataKey) }
+		wsLimiter = newDataKeyRouteLimiter(dataKey.tier)
+	} else {
+		dataRoutesMeter = func() (int, error) { return s.meterIP(ip) } // includes global limiter and may be disabled
+		wsLimiter = s.wsLimiter(ip)
+		if wsLimiter == nil { // too many active ws conns from this IP
+			log.Warnf("Too many websocket connections from %v", ip)
+			return
+		}
+		defer s.wsLimiterDone(ip)
 	}
-	defer s.wsLimiterDone(ip)
 	client := s.newWSLink(addr, conn, wsLimiter, dataRoutesMeter)
+	client.dataKey = dataKey
 
 	cm, err := s.addClient(ctx, client)
 	if err != nil {
@@ -671,8 +891,8 @@ func (s *Server) websocketHandler(ctx context.Context, conn ws.Connection, ip de
 // notification. See msgjson.NewNotification.
 func (s *Server) Broadcast(msg *msgjson.Message) {
 	// Marshal and send the bytes to avoid multiple marshals when sending.
-	b, err := json.Marshal(msg)
-	if err != nil {
+	enc := msgjson.NewMessageEncoder(msg)
+	if _, err := enc.Encode(msgjson.JSONEncoding); err != nil {
 		log.Errorf("unable to marshal broadcast Message: %v", err)
 		return
 	}
@@ -686,6 +906,11 @@ func (s *Server) Broadcast(msg *msgjson.Message) {
 	}
 
 	for id, cl := range s.clients {
+		b, err := enc.Encode(cl.Encoding())
+		if err != nil {
+			log.Errorf("unable to marshal broadcast Message with %s encoding: %v", cl.Encoding(), err)
+			continue
+		}
 		if err := cl.SendRaw(b); err != nil {
 			log.Debugf("Send to client %d at %s failed: %v", id, cl.Addr(), err)
 			cl.Disconnect() // triggers return of websocketHandler, and removeClient
@@ -116,6 +116,87 @@ func (a *Archiver) StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime
 	return nil
 }
 
+// AllowAccount adds an account to the allowlist, replacing the note of an
+// account that is already allowed.
+func (a *Archiver) AllowAccount(aid account.AccountID, note string) error {
+	stmt := fmt.Sprintf(internal.UpsertAllowedAccount, allowedAcctsTableName)
+	_, err := a.db.ExecContext(a.ctx, stmt, aid, note, time.Now().UnixMilli())
+	return err
+}
+
+// DisallowAccount removes an account from the allowlist.
+func (a *Archiver) DisallowAccount(aid account.AccountID) error {
+	stmt := fmt.Sprintf(internal.DeleteAllowedAccount, allowedAcctsTableName)
+	_, err := a.db.ExecContext(a.ctx, stmt, aid)
+	return err
+}
+
+// AllowedAccounts returns the accounts on the allowlist.
+func (a *Archiver) AllowedAccounts() ([]*db.AllowedAccount, error) {
+	stmt := fmt.Sprintf(internal.SelectAllowedAccounts, allowedAcctsTableName)
+	rows, err := a.db.QueryContext(a.ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accts []*db.AllowedAccount
+	for rows.Next() {
+		var acct db.AllowedAccount
+		var note sql.NullString
+		if err = rows.Scan(&acct.AccountID, &note, &acct.Stamp); err != nil {
+			return nil, err
+		}
+		acct.Note = note.String
+		accts = append(accts, &acct)
+	}
+	return accts, rows.Err()
+}
+
+// StoreDataAPIKey stores a data API key.
+func (a *Archiver) StoreDataAPIKey(key *db.DataAPIKey) error {
+	stmt := fmt.Sprintf(internal.InsertDataAPIKey, dataAPIKeysTableName)
+	_, err := a.db.ExecContext(a.ctx, stmt, key.ID, key.KeyHash, key.Tier, key.Note, key.Stamp)
+	return err
+}
+
+// DeleteDataAPIKey deletes the data API key with the ID. The returned bool is
+// false if there was no such key.
+func (a *Archiver) DeleteDataAPIKey(id string) (bool, error) {
+	stmt := fmt.Sprintf(internal.DeleteDataAPIKey, dataAPIKeysTableName)
+	res, err := a.db.ExecContext(a.ctx, stmt, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DataAPIKeys returns the data API keys.
+func (a *Archiver) DataAPIKeys() ([]*db.DataAPIKey, error) {
+	stmt := fmt.Sprintf(internal.SelectDataAPIKeys, dataAPIKeysTableName)
+	rows, err := a.db.QueryContext(a.ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*db.DataAPIKey
+	for rows.Next() {
+		var key db.DataAPIKey
+		var note sql.NullString
+		if err = rows.Scan(&key.ID, &key.KeyHash, &key.Tier, &note, &key.Stamp); err != nil {
+			return nil, err
+		}
+		key.Note = note.String
+		keys = append(keys, &key)
+	}
+	return keys, rows.Err()
+}
+
 // KeyIndex returns the current child index for the an xpub. If it is not
 // known, this creates a new entry with index zero.
 func (a *Archiver) KeyIndex(xpub string) (uint32, error) {
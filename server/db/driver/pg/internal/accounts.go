@@ -89,4 +89,36 @@ const (
 	DeletePrepaidBond = `DELETE FROM %s WHERE coin_id = $1;`
 
 	InsertPrepaidBond = `INSERT INTO %s (coin_id, strength, lock_time) VALUES ($1, $2, $3);`
+
+	// CreateAllowedAccountsTable creates the table of accounts that are
+	// permitted to register and trade in allowlist mode.
+	CreateAllowedAccountsTable = `CREATE TABLE IF NOT EXISTS %s (
+		account_id BYTEA PRIMARY KEY,
+		note TEXT,
+		stamp INT8
+	);`
+
+	UpsertAllowedAccount = `INSERT INTO %s (account_id, note, stamp) VALUES ($1, $2, $3)
+		ON CONFLICT (account_id) DO UPDATE
+		SET note = $2;`
+
+	DeleteAllowedAccount = `DELETE FROM %s WHERE account_id = $1;`
+
+	SelectAllowedAccounts = `SELECT account_id, note, stamp FROM %s;`
+
+	// CreateDataAPIKeysTable creates the table of API keys issued for the
+	// data API. Only a hash of each key is stored.
+	CreateDataAPIKeysTable = `CREATE TABLE IF NOT EXISTS %s (
+		id TEXT PRIMARY KEY,
+		key_hash BYTEA UNIQUE,
+		tier TEXT,
+		note TEXT,
+		stamp INT8
+	);`
+
+	InsertDataAPIKey = `INSERT INTO %s (id, key_hash, tier, note, stamp) VALUES ($1, $2, $3, $4, $5);`
+
+	DeleteDataAPIKey = `DELETE FROM %s WHERE id = $1;`
+
+	SelectDataAPIKeys = `SELECT id, key_hash, tier, note, stamp FROM %s;`
 )
@@ -20,6 +20,8 @@ const (
 	accountsTableName     = "accounts"
 	bondsTableName        = "bonds"
 	prepaidBondsTableName = "prepaid_bonds"
+	allowedAcctsTableName = "allowed_accounts"
+	dataAPIKeysTableName  = "data_api_keys"
 
 	indexBondsOnAccountName  = "idx_bonds_on_acct"
 	indexBondsOnLockTimeName = "idx_bonds_on_locktime"
@@ -51,6 +53,8 @@ var createAccountTableStatements = []tableStmt{
 	{accountsTableName, internal.CreateAccountsTable},
 	{bondsTableName, internal.CreateBondsTable},
 	{prepaidBondsTableName, internal.CreatePrepaidBondsTable},
+	{allowedAcctsTableName, internal.CreateAllowedAccountsTable},
+	{dataAPIKeysTableName, internal.CreateDataAPIKeysTable},
 }
 
 type indexStmt struct {
@@ -220,6 +220,24 @@ type Account struct {
 	Pubkey    dex.Bytes         `json:"pubkey"`
 }
 
+// AllowedAccount is an account that is permitted to register and trade when
+// the server is in allowlist mode.
+type AllowedAccount struct {
+	AccountID account.AccountID `json:"accountid"`
+	Note      string            `json:"note,omitempty"`
+	Stamp     int64             `json:"stamp"` // unix ms
+}
+
+// DataAPIKey is an API key issued for heavier use of the data API. Only a hash
+// of the key is stored.
+type DataAPIKey struct {
+	ID      string    `json:"id"`
+	KeyHash dex.Bytes `json:"-"`
+	Tier    string    `json:"tier"`
+	Note    string    `json:"note,omitempty"`
+	Stamp   int64     `json:"stamp"` // unix ms
+}
+
 // Bond represents a time-locked fidelity bond posted by a user.
 type Bond struct {
 	Version  uint16
@@ -264,6 +282,22 @@ type AccountArchiver interface {
 	DeletePrepaidBond(coinID []byte) error
 	StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error
 
+	// AllowAccount adds an account to the allowlist, replacing the note of an
+	// account that is already allowed.
+	AllowAccount(aid account.AccountID, note string) error
+	// DisallowAccount removes an account from the allowlist.
+	DisallowAccount(aid account.AccountID) error
+	// AllowedAccounts returns the accounts on the allowlist.
+	AllowedAccounts() ([]*AllowedAccount, error)
+
+	// StoreDataAPIKey stores a data API key.
+	StoreDataAPIKey(key *DataAPIKey) error
+	// DeleteDataAPIKey deletes the data API key with the ID. The returned bool
+	// is false if there was no such key.
+	DeleteDataAPIKey(id string) (bool, error)
+	// DataAPIKeys returns the data API keys.
+	DataAPIKeys() ([]*DataAPIKey, error)
+
 	// AccountInfo returns data for an account.
 	AccountInfo(account.AccountID) (*Account, error)
 }
@@ -5,6 +5,7 @@ package dex
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,6 +22,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/calc"
 	"decred.org/dcrdex/dex/candles"
+	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/fiatrates"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
@@ -35,6 +37,7 @@ import (
 	"decred.org/dcrdex/server/market"
 	"decred.org/dcrdex/server/noderelay"
 	"decred.org/dcrdex/server/swap"
+	"decred.org/dcrdex/server/tradereport"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"github.com/go-chi/chi/v5"
@@ -51,6 +54,10 @@ const (
 	APIVersion = V1APIVersion
 )
 
+// APIVersions are the API versions that the server will negotiate with
+// clients, in ascending order. APIVersion must be the last element.
+var APIVersions = []uint16{V1APIVersion}
+
 // Asset represents an asset in the Config file.
 type Asset struct {
 	Symbol      string `json:"bip44symbol"`
@@ -423,6 +430,15 @@ type DexConf struct {
 	CommsCfg         *RPCConfig
 	NoResumeSwaps    bool
 	NodeRelayAddr    string
+	// BookBatching enables coalescing of an epoch's book updates into
+	// book_update_batch notifications for subscribers that request them.
+	BookBatching bool
+	// Allowlist enables allowlist mode, in which only accounts on the
+	// allowlist may register and trade.
+	Allowlist bool
+	// TradeReportSink is an optional sink to which a report of each completed
+	// match is sent.
+	TradeReportSink tradereport.Sink
 }
 
 type signer struct {
@@ -463,6 +479,7 @@ type DEX struct {
 	bookRouter  *market.BookRouter
 	subsystems  []subsystem
 	server      *comms.Server
+	events      *adminEventFeed
 
 	configRespMtx sync.RWMutex
 	configResp    *configResponse
@@ -481,6 +498,7 @@ func newConfigResponse(cfg *DexConf, bondAssets map[string]*msgjson.BondAsset,
 
 	configMsg := &msgjson.ConfigResult{
 		APIVersion:       uint16(APIVersion),
+		APIVersions:      APIVersions,
 		DEXPubKey:        cfg.DEXPrivKey.PubKey().SerializeCompressed(),
 		BroadcastTimeout: uint64(cfg.BroadcastTimeout.Milliseconds()),
 		CancelMax:        cfg.CancelThreshold,
@@ -919,13 +937,31 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	}
 
 	// Client comms RPC server.
-	server, err := comms.NewServer(cfg.CommsCfg)
+	commsCfg := *cfg.CommsCfg
+	commsCfg.APIVersions = APIVersions
+	server, err := comms.NewServer(&commsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("NewServer failed: %w", err)
 	}
 
+	dataKeys, err := storage.DataAPIKeys()
+	if err != nil {
+		return nil, fmt.Errorf("error loading data API keys: %w", err)
+	}
+	for _, key := range dataKeys {
+		if err := server.AddDataAPIKey(key.ID, key.KeyHash, key.Tier, key.Note, key.Stamp); err != nil {
+			log.Errorf("Error loading data API key %s: %v", key.ID, err)
+		}
+	}
+	if len(dataKeys) > 0 {
+		log.Infof("Loaded %d data API keys", len(dataKeys))
+	}
+
 	dataAPI := apidata.NewDataAPI(storage, server.RegisterHTTP)
 
+	// Operational events for the admin server.
+	events := newAdminEventFeed()
+
 	authCfg := auth.Config{
 		Storage:          storage,
 		Signer:           signer{cfg.DEXPrivKey},
@@ -940,6 +976,18 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		PenaltyThreshold: cfg.PenaltyThreshold,
 		TxDataSources:    txDataSources,
 		Route:            server.Route,
+		Penalized:        events.penalized,
+		Allowlist:        cfg.Allowlist,
+	}
+	if cfg.Allowlist {
+		allowed, err := storage.AllowedAccounts()
+		if err != nil {
+			return nil, fmt.Errorf("error loading allowlist: %w", err)
+		}
+		for _, acct := range allowed {
+			authCfg.AllowedAccounts = append(authCfg.AllowedAccounts, acct.AccountID)
+		}
+		log.Infof("Allowlist mode enabled with %d allowed accounts", len(allowed))
 	}
 
 	authMgr := auth.NewAuthManager(&authCfg)
@@ -958,9 +1006,28 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			log.Errorf("bad market for order %v: %v", ord.ID(), err)
 			return
 		}
+		if fail {
+			events.swapFailed(name, ord, match)
+		}
 		markets[name].SwapDone(ord, match, fail)
 	}
 
+	// Completed matches are reported to the operator's sink, if configured.
+	var reporter *tradereport.Reporter
+	var matchComplete func(match *order.Match, completed time.Time)
+	if cfg.TradeReportSink != nil {
+		reporter, err = tradereport.NewReporter(&tradereport.Config{
+			Sink:      cfg.TradeReportSink,
+			Storage:   storage,
+			Logger:    cfg.LogBackend.NewLogger("TRPT", log.Level()),
+			SpoolPath: filepath.Join(cfg.DataDir, "tradereport.spool"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("NewReporter: %w", err)
+		}
+		matchComplete = reporter.MatchComplete
+	}
+
 	// Create the swapper.
 	swapperCfg := &swap.Config{
 		Assets:           lockableAssets,
@@ -971,6 +1038,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		LockTimeTaker:    dex.LockTimeTaker(cfg.Network),
 		LockTimeMaker:    dex.LockTimeMaker(cfg.Network),
 		SwapDone:         swapDone,
+		MatchComplete:    matchComplete,
 		NoResume:         cfg.NoResumeSwaps,
 		// TODO: set the AllowPartialRestore bool to allow startup with a
 		// missing asset backend if necessary in an emergency.
@@ -1038,7 +1106,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			CoinLockerBase:  baseCoinLocker,
 			FeeFetcherQuote: feeMgr.FeeFetcher(mktInf.Quote),
 			CoinLockerQuote: quoteCoinLocker,
-			DataCollector:   dataAPI,
+			DataCollector:   &eventingCollector{dataAPI, events},
 			Balancer:        dexBalancer,
 			CheckParcelLimit: func(user account.AccountID, calcParcels market.MarketParcelCalculator) bool {
 				return orderRouter.CheckParcelLimit(user, mktInf.Name, calcParcels)
@@ -1074,6 +1142,11 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	// Start the AuthManager and Swapper subsystems after populating the markets
 	// map used by the unbook callbacks, and setting the AuthManager's unbook
 	// timers for the users with currently booked orders.
+	// The trade reporter is started first so that it is stopped after the
+	// Swapper.
+	if reporter != nil {
+		startSubSys("Trade reporter", reporter)
+	}
 	startSubSys("Auth manager", authMgr)
 	startSubSys("Swapper", swapper)
 
@@ -1102,9 +1175,15 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	}
 
 	// Book router
-	bookRouter := market.NewBookRouter(bookSources, feeMgr, server.Route)
+	bookRouter := market.NewBookRouter(bookSources, feeMgr, server.Route, cfg.BookBatching)
 	startSubSys("BookRouter", bookRouter)
 
+	startSubSys("Health monitor", &healthMonitor{
+		assets:  lockableAssets,
+		storage: storage,
+		events:  events,
+	})
+
 	// The data API gets the order book from the book router.
 	dataAPI.SetBookSource(bookRouter)
 
@@ -1144,6 +1223,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		bookRouter:  bookRouter,
 		subsystems:  subsystems,
 		server:      server,
+		events:      events,
 		configResp:  cfgResp,
 	}
 
@@ -1164,6 +1244,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		rr.With(candleParamsParser).Get("/candles/{baseSymbol}/{quoteSymbol}/{binSize}", server.NewRouteHandler(msgjson.CandlesRoute))
 		rr.With(candleParamsParser).Get("/candles/{baseSymbol}/{quoteSymbol}/{binSize}/{count}", server.NewRouteHandler(msgjson.CandlesRoute))
 		rr.With(orderBookParamsParser).Get("/orderbook/{baseSymbol}/{quoteSymbol}", server.NewRouteHandler(msgjson.OrderBookRoute))
+		rr.With(orderBookParamsParser).Get("/booksnapshot/{baseSymbol}/{quoteSymbol}", server.NewRouteHandler(msgjson.BookSnapshotRoute))
 	})
 
 	startSubSys("Comms Server", server)
@@ -1376,6 +1457,89 @@ func (dm *DEX) ForgiveMatchFail(aid account.AccountID, mid order.MatchID) (forgi
 	return dm.authMgr.ForgiveMatchFail(aid, mid)
 }
 
+// ParcelLimits returns the current parcel limits and the log of changes to
+// them.
+func (dm *DEX) ParcelLimits() (*account.ParcelLimits, []*auth.ParcelLimitsChange) {
+	return dm.authMgr.ParcelLimits(), dm.authMgr.ParcelLimitsChanges()
+}
+
+// SetParcelLimits changes the parcel limits. The limits are applied to the
+// next order of every user, and the max score in the config response is
+// updated.
+func (dm *DEX) SetParcelLimits(limits *account.ParcelLimits, reason string) error {
+	if err := dm.authMgr.SetParcelLimits(limits, reason); err != nil {
+		return err
+	}
+	dm.configRespMtx.Lock()
+	dm.configResp.configMsg.MaxScore = uint32(limits.MaxScore)
+	dm.configResp.remarshal()
+	dm.configRespMtx.Unlock()
+	return nil
+}
+
+// AllowedAccounts returns the accounts on the allowlist.
+func (dm *DEX) AllowedAccounts() ([]*db.AllowedAccount, error) {
+	if !dm.authMgr.AllowlistEnabled() {
+		return nil, auth.ErrAllowlistDisabled
+	}
+	return dm.storage.AllowedAccounts()
+}
+
+// AllowAccount adds an account to the allowlist.
+func (dm *DEX) AllowAccount(aid account.AccountID, note string) error {
+	return dm.authMgr.AllowAccount(aid, note)
+}
+
+// DisallowAccount removes an account from the allowlist.
+func (dm *DEX) DisallowAccount(aid account.AccountID) error {
+	return dm.authMgr.DisallowAccount(aid)
+}
+
+// IssueDataAPIKey creates a data API key with the rate tier. The key is
+// returned only here, since just its hash is stored.
+func (dm *DEX) IssueDataAPIKey(tier, note string) (string, *db.DataAPIKey, error) {
+	if comms.DataTiers[tier] == nil {
+		return "", nil, fmt.Errorf("unknown data API tier %q", tier)
+	}
+	key := hex.EncodeToString(encode.RandomBytes(32))
+	keyHash := comms.HashDataAPIKey(key)
+	dataKey := &db.DataAPIKey{
+		ID:      hex.EncodeToString(keyHash[:8]),
+		KeyHash: keyHash,
+		Tier:    tier,
+		Note:    note,
+		Stamp:   time.Now().UnixMilli(),
+	}
+	if err := dm.storage.StoreDataAPIKey(dataKey); err != nil {
+		return "", nil, fmt.Errorf("error storing data API key: %w", err)
+	}
+	if err := dm.server.AddDataAPIKey(dataKey.ID, keyHash, tier, note, dataKey.Stamp); err != nil {
+		return "", nil, err
+	}
+	log.Infof("Issued data API key %s with tier %s", dataKey.ID, tier)
+	return key, dataKey, nil
+}
+
+// RevokeDataAPIKey deletes the data API key with the ID, and disconnects any
+// clients using it.
+func (dm *DEX) RevokeDataAPIKey(id string) error {
+	found, err := dm.storage.DeleteDataAPIKey(id)
+	if err != nil {
+		return fmt.Errorf("error deleting data API key: %w", err)
+	}
+	if !dm.server.RemoveDataAPIKey(id) && !found {
+		return fmt.Errorf("unknown data API key %s", id)
+	}
+	log.Infof("Revoked data API key %s", id)
+	return nil
+}
+
+// DataAPIKeys returns the data API keys and their usage since the server was
+// started.
+func (dm *DEX) DataAPIKeys() []*comms.DataAPIKeyUsage {
+	return dm.server.DataAPIKeyUsage()
+}
+
 func (dm *DEX) CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error) {
 	return dm.authMgr.CreatePrepaidBonds(n, strength, durSecs)
 }
@@ -1403,6 +1567,12 @@ func (dm *DEX) BookOrders(base, quote uint32) ([]*order.LimitOrder, error) {
 	return dm.storage.BookOrders(base, quote)
 }
 
+// BookSnapshot returns a snapshot of the named market's order book with its
+// sequence number and a digest of its orders.
+func (dm *DEX) BookSnapshot(mktName string) (*msgjson.BookSnapshot, error) {
+	return dm.bookRouter.Snapshot(mktName)
+}
+
 // EpochOrders returns epoch orders for market with base and quote.
 func (dm *DEX) EpochOrders(base, quote uint32) ([]order.Order, error) {
 	return dm.storage.EpochOrders(base, quote)
@@ -1558,8 +1728,8 @@ func candleParamsParser(next http.Handler) http.Handler {
 	})
 }
 
-// orderBookParamsParser is middleware for the /orderbook route. Parses the
-// *msgjson.OrderBookSubscription from the URL parameters.
+// orderBookParamsParser is middleware for the /orderbook and /booksnapshot
+// routes. Parses the *msgjson.OrderBookSubscription from the URL parameters.
 func orderBookParamsParser(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		baseID, quoteID, errMsg := parseBaseQuoteIDs(r)
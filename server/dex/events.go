@@ -0,0 +1,222 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dex
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/market"
+	"decred.org/dcrdex/server/matcher"
+	"decred.org/dcrdex/server/swap"
+)
+
+// AdminEvent types.
+const (
+	AdminEventEpoch    = "epoch"
+	AdminEventSwapFail = "swapfail"
+	AdminEventPenalty  = "penalty"
+	AdminEventHealth   = "health"
+)
+
+const (
+	// adminEventBuffer is the number of events that are buffered for a
+	// subscriber. Events are dropped for subscribers that fall behind.
+	adminEventBuffer = 256
+	// healthCheckInterval is how often the health of the asset backends and
+	// the storage is checked for changes.
+	healthCheckInterval = time.Second * 10
+)
+
+// AdminEvent is a live operational event for the admin server.
+type AdminEvent struct {
+	Type    string `json:"type"`
+	Stamp   int64  `json:"stamp"` // unix ms
+	Payload any    `json:"payload"`
+}
+
+// EpochEvent is the payload of an AdminEventEpoch event. It is sent when a
+// market has processed an epoch.
+type EpochEvent struct {
+	Market      string `json:"market"`
+	Epoch       uint64 `json:"epoch"`
+	Matches     uint64 `json:"matches"`
+	MatchVolume uint64 `json:"matchVolume"`
+	QuoteVolume uint64 `json:"quoteVolume"`
+}
+
+// SwapFailEvent is the payload of an AdminEventSwapFail event. It is sent when
+// a swap fails because a user did not act.
+type SwapFailEvent struct {
+	Market  string `json:"market"`
+	OrderID string `json:"orderID"`
+	MatchID string `json:"matchID"`
+	User    string `json:"user"`
+}
+
+// PenaltyEvent is the payload of an AdminEventPenalty event. It is sent when a
+// user's trading privileges are suspended.
+type PenaltyEvent struct {
+	AccountID string `json:"accountID"`
+	Rule      string `json:"rule"`
+}
+
+// HealthEvent is the payload of an AdminEventHealth event. It is sent when an
+// asset backend's sync status or the storage's status changes. AssetID and
+// Symbol are not set for storage events.
+type HealthEvent struct {
+	AssetID *uint32 `json:"assetID,omitempty"`
+	Symbol  string  `json:"symbol,omitempty"`
+	Healthy bool    `json:"healthy"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// adminEventFeed distributes AdminEvents to subscribers.
+type adminEventFeed struct {
+	mtx    sync.RWMutex
+	subs   map[uint64]chan *AdminEvent
+	nextID uint64
+}
+
+func newAdminEventFeed() *adminEventFeed {
+	return &adminEventFeed{subs: make(map[uint64]chan *AdminEvent)}
+}
+
+// subscribe returns a channel of AdminEvents and a function that must be
+// called to unsubscribe.
+func (f *adminEventFeed) subscribe() (<-chan *AdminEvent, func()) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	id := f.nextID
+	f.nextID++
+	c := make(chan *AdminEvent, adminEventBuffer)
+	f.subs[id] = c
+	return c, func() {
+		f.mtx.Lock()
+		defer f.mtx.Unlock()
+		if _, found := f.subs[id]; found {
+			delete(f.subs, id)
+			close(c)
+		}
+	}
+}
+
+// send sends the event to all subscribers. Subscribers with full buffers miss
+// the event.
+func (f *adminEventFeed) send(typ string, payload any) {
+	ev := &AdminEvent{
+		Type:    typ,
+		Stamp:   time.Now().UnixMilli(),
+		Payload: payload,
+	}
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	for _, c := range f.subs {
+		select {
+		case c <- ev:
+		default:
+			log.Debugf("Admin event subscriber is behind. Dropping %s event.", typ)
+		}
+	}
+}
+
+// eventingCollector is a market.DataCollector that sends an AdminEventEpoch
+// event for each reported epoch.
+type eventingCollector struct {
+	market.DataCollector
+	events *adminEventFeed
+}
+
+func (c *eventingCollector) ReportEpoch(base, quote uint32, epochIdx uint64, stats *matcher.MatchCycleStats) (*msgjson.Spot, error) {
+	spot, err := c.DataCollector.ReportEpoch(base, quote, epochIdx, stats)
+	mktName, _ := dex.MarketName(base, quote)
+	c.events.send(AdminEventEpoch, &EpochEvent{
+		Market:      mktName,
+		Epoch:       epochIdx,
+		Matches:     stats.Matches,
+		MatchVolume: stats.MatchVolume,
+		QuoteVolume: stats.QuoteVolume,
+	})
+	return spot, err
+}
+
+// swapFailed sends an AdminEventSwapFail event.
+func (f *adminEventFeed) swapFailed(mktName string, ord order.Order, match *order.Match) {
+	f.send(AdminEventSwapFail, &SwapFailEvent{
+		Market:  mktName,
+		OrderID: ord.ID().String(),
+		MatchID: match.ID().String(),
+		User:    ord.User().String(),
+	})
+}
+
+// penalized sends an AdminEventPenalty event.
+func (f *adminEventFeed) penalized(user account.AccountID, rule account.Rule) {
+	f.send(AdminEventPenalty, &PenaltyEvent{
+		AccountID: user.String(),
+		Rule:      rule.String(),
+	})
+}
+
+// healthMonitor sends AdminEventHealth events when the sync status of an
+// asset backend or the status of the storage changes.
+type healthMonitor struct {
+	assets  map[uint32]*swap.SwapperAsset
+	storage db.DEXArchivist
+	events  *adminEventFeed
+}
+
+// Run runs the health monitor until the context is canceled. Run satisfies
+// dex.Runner.
+func (m *healthMonitor) Run(ctx context.Context) {
+	synced := make(map[uint32]bool, len(m.assets))
+	for assetID, a := range m.assets {
+		synced[assetID], _ = a.Backend.Synced()
+	}
+	storageOK := m.storage.LastErr() == nil
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		for assetID, a := range m.assets {
+			s, err := a.Backend.Synced()
+			if s == synced[assetID] {
+				continue
+			}
+			synced[assetID] = s
+			assetID := assetID
+			ev := &HealthEvent{AssetID: &assetID, Symbol: a.Symbol, Healthy: s}
+			if err != nil {
+				ev.Error = err.Error()
+			}
+			m.events.send(AdminEventHealth, ev)
+		}
+		err := m.storage.LastErr()
+		if ok := err == nil; ok != storageOK {
+			storageOK = ok
+			ev := &HealthEvent{Healthy: ok}
+			if err != nil {
+				ev.Error = err.Error()
+			}
+			m.events.send(AdminEventHealth, ev)
+		}
+	}
+}
+
+// AdminEvents returns a channel of live operational events and a function
+// that must be called to unsubscribe.
+func (dm *DEX) AdminEvents() (<-chan *AdminEvent, func()) {
+	return dm.events.subscribe()
+}
@@ -5,7 +5,6 @@ package market
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sync"
 
@@ -132,6 +131,14 @@ type sigDataMatchProof struct {
 	matchProof *order.MatchProof
 }
 
+// maxBatchUpdates is the maximum number of updates in a BookUpdateBatch. If an
+// epoch has more book updates, they are sent in multiple batches.
+const maxBatchUpdates = 1000
+
+// maxEpochProofs is the number of recent epochs for which the inputs to order
+// matching are retained for the epoch_proof route.
+const maxEpochProofs = 100
+
 // BookSource is a source of a market's order book and a feed of updates to the
 // order book and epoch queue.
 type BookSource interface {
@@ -150,14 +157,31 @@ type BookSource interface {
 type subscribers struct {
 	mtx   sync.RWMutex
 	conns map[uint64]comms.Link
-	seq   uint64
+	// batched are the IDs of subscribers that receive book updates in
+	// BookUpdateBatch notifications.
+	batched map[uint64]bool
+	seq     uint64
+}
+
+// newSubscribers is the constructor for a subscribers.
+func newSubscribers() *subscribers {
+	return &subscribers{
+		conns:   make(map[uint64]comms.Link),
+		batched: make(map[uint64]bool),
+	}
 }
 
-// add adds a new subscriber.
-func (s *subscribers) add(conn comms.Link) {
+// add adds a new subscriber. If batch is true, the subscriber receives book
+// updates in BookUpdateBatch notifications.
+func (s *subscribers) add(conn comms.Link, batch bool) {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 	s.conns[conn.ID()] = conn
+	if batch {
+		s.batched[conn.ID()] = true
+	} else {
+		delete(s.batched, conn.ID())
+	}
 }
 
 func (s *subscribers) remove(id uint64) bool {
@@ -168,9 +192,22 @@ func (s *subscribers) remove(id uint64) bool {
 		return false
 	}
 	delete(s.conns, id)
+	delete(s.batched, id)
 	return true
 }
 
+// isBatched checks if the subscriber receives batched book updates. The mtx
+// must be at least read locked.
+func (s *subscribers) isBatched(id uint64) bool {
+	return s.batched[id]
+}
+
+// notBatched checks if the subscriber receives individual book updates. The
+// mtx must be at least read locked.
+func (s *subscribers) notBatched(id uint64) bool {
+	return !s.batched[id]
+}
+
 // nextSeq gets the next sequence number by incrementing the counter. This
 // should be used when the book and orders are modified. Currently this applies
 // to the routes: book_order, unbook_order, update_remaining, and epoch_order,
@@ -193,7 +230,9 @@ func (s *subscribers) lastSeq() uint64 {
 // as msgjson.BookOrderNote structures.
 type msgBook struct {
 	name string
-	// mtx guards orders and epochIdx
+	// mtx guards orders and epochIdx. Changes to orders are assigned their
+	// sequence number while mtx is held, so that a copy of the orders taken
+	// with mtx held is consistent with subs.lastSeq.
 	mtx           sync.RWMutex
 	running       bool
 	orders        map[order.OrderID]*msgjson.BookOrderNote
@@ -203,6 +242,13 @@ type msgBook struct {
 	source        BookSource
 	baseID        uint32
 	quoteID       uint32
+
+	// proofMtx guards epochOrders and proofs. epochOrders are the epoch queue
+	// notes of epochs awaiting a match proof, and proofs are the completed
+	// proofs of recent epochs.
+	proofMtx    sync.RWMutex
+	epochOrders map[uint64][]*msgjson.EpochOrderNote
+	proofs      map[uint64]*msgjson.EpochProof
 }
 
 func (book *msgBook) setEpoch(idx int64) {
@@ -229,7 +275,7 @@ func (book *msgBook) epoch() int64 {
 
 // insert adds the information for a new order into the order book. If the order
 // is already found, it is inserted, but an error is logged since update should
-// be used in that case.
+// be used in that case. The returned note has the change's sequence number.
 func (book *msgBook) insert(lo *order.LimitOrder) *msgjson.BookOrderNote {
 	msgOrder := limitOrderToMsgOrder(lo, book.name)
 	book.mtx.Lock()
@@ -240,12 +286,14 @@ func (book *msgBook) insert(lo *order.LimitOrder) *msgjson.BookOrderNote {
 		//panic("bad insert")
 	}
 	book.orders[lo.ID()] = msgOrder
+	msgOrder.Seq = book.subs.nextSeq()
 	return msgOrder
 }
 
 // update updates the order book with the new order information, such as when an
 // order's filled amount changes. If the order is not found, it is inserted, but
-// an error is logged since insert should be used in that case.
+// an error is logged since insert should be used in that case. The returned
+// note has the change's sequence number.
 func (book *msgBook) update(lo *order.LimitOrder) *msgjson.BookOrderNote {
 	msgOrder := limitOrderToMsgOrder(lo, book.name)
 	book.mtx.Lock()
@@ -256,14 +304,103 @@ func (book *msgBook) update(lo *order.LimitOrder) *msgjson.BookOrderNote {
 		//panic("bad update")
 	}
 	book.orders[lo.ID()] = msgOrder
+	msgOrder.Seq = book.subs.nextSeq()
 	return msgOrder
 }
 
-// Remove the order from the order book.
-func (book *msgBook) remove(lo *order.LimitOrder) {
+// Remove the order from the order book, returning the change's sequence
+// number.
+func (book *msgBook) remove(lo *order.LimitOrder) uint64 {
 	book.mtx.Lock()
 	defer book.mtx.Unlock()
 	delete(book.orders, lo.ID())
+	return book.subs.nextSeq()
+}
+
+// purge removes all orders from the order book, returning the change's sequence
+// number.
+func (book *msgBook) purge() uint64 {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+	book.orders = make(map[order.OrderID]*msgjson.BookOrderNote)
+	return book.subs.nextSeq()
+}
+
+// addEpochOrder records the epoch queue order note for the note's epoch's
+// EpochProof.
+func (book *msgBook) addEpochOrder(note *msgjson.EpochOrderNote) {
+	book.proofMtx.Lock()
+	defer book.proofMtx.Unlock()
+	book.epochOrders[note.Epoch] = append(book.epochOrders[note.Epoch], note)
+}
+
+// addMatchProof creates and stores the EpochProof for the match proof's epoch
+// from the recorded epoch queue notes. The preimages are paired with the orders
+// by their commitments, and every order without a preimage must be one of the
+// match proof's misses. A proof that does not account for exactly the recorded
+// epoch orders is not stored. Proofs older than maxEpochProofs epochs are
+// pruned.
+func (book *msgBook) addMatchProof(mp *order.MatchProof) (*msgjson.EpochProof, error) {
+	idx := mp.Epoch.Idx
+	pimgs := make(map[order.Commitment]order.Preimage, len(mp.Preimages))
+	for i := range mp.Preimages {
+		pimgs[mp.Preimages[i].Commit()] = mp.Preimages[i]
+	}
+	misses := make(map[order.OrderID]bool, len(mp.Misses))
+	for _, o := range mp.Misses {
+		misses[o.ID()] = true
+	}
+
+	book.proofMtx.Lock()
+	defer book.proofMtx.Unlock()
+	notes := book.epochOrders[idx]
+	// There will be no more orders for this or earlier epochs.
+	for epoch := range book.epochOrders {
+		if epoch <= idx {
+			delete(book.epochOrders, epoch)
+		}
+	}
+	if len(notes) != len(pimgs)+len(misses) {
+		return nil, fmt.Errorf("epoch %d match proof has %d preimages and %d misses, but %d orders were queued",
+			idx, len(pimgs), len(misses), len(notes))
+	}
+	proof := &msgjson.EpochProof{
+		MarketID: book.name,
+		Epoch:    idx,
+		Orders:   make([]*msgjson.EpochProofOrder, 0, len(notes)),
+		CSum:     mp.CSum,
+		Seed:     mp.Seed,
+	}
+	for _, note := range notes {
+		ord := &msgjson.EpochProofOrder{EpochOrderNote: *note}
+		var commit order.Commitment
+		copy(commit[:], note.Commit)
+		if pimg, found := pimgs[commit]; found {
+			ord.Preimage = pimg[:]
+		} else {
+			var oid order.OrderID
+			copy(oid[:], note.OrderID)
+			if !misses[oid] {
+				return nil, fmt.Errorf("epoch %d order %v has neither a preimage nor a miss in the match proof",
+					idx, oid)
+			}
+		}
+		proof.Orders = append(proof.Orders, ord)
+	}
+	book.proofs[idx] = proof
+	for epoch := range book.proofs {
+		if epoch+maxEpochProofs <= idx {
+			delete(book.proofs, epoch)
+		}
+	}
+	return proof, nil
+}
+
+// epochProof returns the stored EpochProof for the epoch, if it is available.
+func (book *msgBook) epochProof(idx uint64) *msgjson.EpochProof {
+	book.proofMtx.RLock()
+	defer book.proofMtx.RUnlock()
+	return book.proofs[idx]
 }
 
 // addBulkOrders adds the lists of orders to the order book, and records the
@@ -285,6 +422,9 @@ func (book *msgBook) addBulkOrders(epoch int64, orderSets ...[]*order.LimitOrder
 type BookRouter struct {
 	books     map[string]*msgBook
 	feeSource FeeSource
+	// batchUpdates enables BookUpdateBatch notifications for subscribers
+	// that request them.
+	batchUpdates bool
 
 	priceFeeders *subscribers
 	spotsMtx     sync.RWMutex
@@ -294,20 +434,18 @@ type BookRouter struct {
 // NewBookRouter is a constructor for a BookRouter. Routes are registered with
 // comms and a monitoring goroutine is started for each BookSource specified.
 // The input sources is a mapping of market names to sources for order and epoch
-// queue information.
-func NewBookRouter(sources map[string]BookSource, feeSource FeeSource, route func(route string, handler comms.MsgHandler)) *BookRouter {
+// queue information. If batchUpdates is true, subscribers may request that an
+// epoch's book updates be coalesced into a single BookUpdateBatch notification.
+func NewBookRouter(sources map[string]BookSource, feeSource FeeSource, route func(route string, handler comms.MsgHandler), batchUpdates bool) *BookRouter {
 	router := &BookRouter{
-		books:     make(map[string]*msgBook),
-		feeSource: feeSource,
-		priceFeeders: &subscribers{
-			conns: make(map[uint64]comms.Link),
-		},
-		spots: make(map[string]*msgjson.Spot),
+		books:        make(map[string]*msgBook),
+		feeSource:    feeSource,
+		batchUpdates: batchUpdates,
+		priceFeeders: newSubscribers(),
+		spots:        make(map[string]*msgjson.Spot),
 	}
 	for mkt, src := range sources {
-		subs := &subscribers{
-			conns: make(map[uint64]comms.Link),
-		}
+		subs := newSubscribers()
 		book := &msgBook{
 			name:    mkt,
 			orders:  make(map[order.OrderID]*msgjson.BookOrderNote),
@@ -315,6 +453,9 @@ func NewBookRouter(sources map[string]BookSource, feeSource FeeSource, route fun
 			source:  src,
 			baseID:  src.Base(),
 			quoteID: src.Quote(),
+
+			epochOrders: make(map[uint64][]*msgjson.EpochOrderNote),
+			proofs:      make(map[uint64]*msgjson.EpochProof),
 		}
 		router.books[mkt] = book
 	}
@@ -322,6 +463,7 @@ func NewBookRouter(sources map[string]BookSource, feeSource FeeSource, route fun
 	route(msgjson.UnsubOrderBookRoute, router.handleUnsubOrderBook)
 	route(msgjson.FeeRateRoute, router.handleFeeRate)
 	route(msgjson.PriceFeedRoute, router.handlePriceFeeder)
+	route(msgjson.EpochProofRoute, router.handleEpochProof)
 
 	return router
 }
@@ -358,6 +500,18 @@ func (r *BookRouter) runBook(ctx context.Context, book *msgBook) {
 	book.running = true
 	book.mtx.Unlock()
 
+	// batch collects the book updates for batched subscribers. It is sent
+	// before any other notification, which is typically the epoch_report that
+	// follows the epoch's book updates, so the sequence is preserved.
+	var batch *msgjson.BookUpdateBatch
+	sendBatch := func() {
+		if batch == nil {
+			return
+		}
+		r.sendNoteFiltered(msgjson.BookUpdateBatchRoute, subs, batch, subs.isBatched)
+		batch = nil
+	}
+
 out:
 	for {
 		select {
@@ -372,6 +526,7 @@ out:
 			var note any
 			var route string
 			var spot *msgjson.Spot
+			var update *msgjson.BookUpdate // for a batch
 			switch sigData := u.data.(type) {
 			case sigDataNewEpoch:
 				// New epoch index should be sent here by the market following
@@ -388,8 +543,8 @@ out:
 					panic("non-limit order received with bookAction")
 				}
 				n := book.insert(lo)
-				n.Seq = subs.nextSeq()
 				note = n
+				update = &msgjson.BookUpdate{Book: n}
 
 			case sigDataUnbookedOrder:
 				route = msgjson.UnbookOrderRoute
@@ -397,13 +552,15 @@ out:
 				if !ok {
 					panic("non-limit order received with unbookAction")
 				}
-				book.remove(lo)
+				seq := book.remove(lo)
 				oid := sigData.order.ID()
-				note = &msgjson.UnbookOrderNote{
-					Seq:      subs.nextSeq(),
+				n := &msgjson.UnbookOrderNote{
+					Seq:      seq,
 					MarketID: book.name,
 					OrderID:  oid[:],
 				}
+				note = n
+				update = &msgjson.BookUpdate{Unbook: n}
 
 			case sigDataUpdateRemaining:
 				route = msgjson.UpdateRemainingRoute
@@ -416,8 +573,9 @@ out:
 					OrderNote: bookNote.OrderNote,
 					Remaining: lo.Remaining(),
 				}
-				n.Seq = subs.nextSeq()
+				n.Seq = bookNote.Seq
 				note = n
+				update = &msgjson.BookUpdate{UpdateRemaining: n}
 
 			case sigDataEpochReport:
 				route = msgjson.EpochReportRoute
@@ -474,6 +632,7 @@ out:
 				epochNote.Epoch = uint64(sigData.epochIdx)
 				c := sigData.order.Commitment()
 				epochNote.Commit = c[:]
+				book.addEpochOrder(epochNote)
 
 				note = epochNote
 
@@ -497,6 +656,9 @@ out:
 					CSum:      mp.CSum,
 					Seed:      mp.Seed,
 				}
+				if _, err := book.addMatchProof(mp); err != nil {
+					log.Errorf("Not storing %s epoch proof: %v", book.name, err)
+				}
 
 			case sigDataSuspend:
 				// When sent with seq set, it indicates immediate stop, and may
@@ -510,10 +672,7 @@ out:
 				}
 				// Only set Seq if there is a book update.
 				if !sigData.persistBook {
-					susp.Seq = subs.nextSeq() // book purge
-					book.mtx.Lock()
-					book.orders = make(map[order.OrderID]*msgjson.BookOrderNote)
-					book.mtx.Unlock()
+					susp.Seq = book.purge()
 					// The router is "running" although the market is suspended.
 				}
 				note = susp
@@ -536,7 +695,19 @@ out:
 				continue
 			}
 
-			r.sendNote(route, subs, note)
+			if update != nil && r.batchUpdates {
+				if batch == nil {
+					batch = &msgjson.BookUpdateBatch{MarketID: book.name}
+				}
+				batch.Updates = append(batch.Updates, update)
+				if len(batch.Updates) >= maxBatchUpdates {
+					sendBatch()
+				}
+				r.sendNoteFiltered(route, subs, note, subs.notBatched)
+			} else {
+				sendBatch()
+				r.sendNote(route, subs, note)
+			}
 
 			if spot != nil {
 				r.sendNote(msgjson.PriceUpdateRoute, r.priceFeeders, spot)
@@ -560,6 +731,19 @@ func (r *BookRouter) Book(mktName string) (*msgjson.OrderBook, error) {
 	return msgOB, nil
 }
 
+// Snapshot creates a copy of the book with a digest of its orders, for
+// verifying mirrored order books. The orders and seq are consistent.
+func (r *BookRouter) Snapshot(mktName string) (*msgjson.BookSnapshot, error) {
+	msgOB, err := r.Book(mktName)
+	if err != nil {
+		return nil, err
+	}
+	return &msgjson.BookSnapshot{
+		OrderBook: *msgOB,
+		Digest:    msgOB.Digest(),
+	}, nil
+}
+
 // sendBook encodes and sends the the entire order book to the specified client.
 func (r *BookRouter) sendBook(conn comms.Link, book *msgBook, msgID uint64) {
 	msgOB := r.msgOrderBook(book)
@@ -590,6 +774,7 @@ func (r *BookRouter) msgOrderBook(book *msgBook) *msgjson.OrderBook {
 		ords = append(ords, o)
 	}
 	epochIdx := book.epochIdx // instead of book.epoch() while already locked
+	seq := book.subs.lastSeq()
 
 	recentMatches := make([][3]int64, len(book.recentMatches))
 	copy(recentMatches, book.recentMatches)
@@ -597,7 +782,7 @@ func (r *BookRouter) msgOrderBook(book *msgBook) *msgjson.OrderBook {
 	book.mtx.RUnlock()
 
 	return &msgjson.OrderBook{
-		Seq:           book.subs.lastSeq(),
+		Seq:           seq,
 		MarketID:      book.name,
 		Epoch:         uint64(epochIdx),
 		Orders:        ords,
@@ -634,7 +819,7 @@ func (r *BookRouter) handleOrderBook(conn comms.Link, msg *msgjson.Message) *msg
 			Message: "unknown market",
 		}
 	}
-	book.subs.add(conn)
+	book.subs.add(conn, sub.Batch && r.batchUpdates)
 	r.sendBook(conn, book, msg.ID)
 	return nil
 }
@@ -702,6 +887,47 @@ func (r *BookRouter) handleFeeRate(conn comms.Link, msg *msgjson.Message) *msgjs
 	return nil
 }
 
+// handleEpochProof is the handler for the non-authenticated 'epoch_proof'
+// route. Clients use this route to retrieve the inputs to a recent epoch's
+// order matching, for verifying the epoch's match_proof.
+func (r *BookRouter) handleEpochProof(conn comms.Link, msg *msgjson.Message) *msgjson.Error {
+	req := new(msgjson.EpochProofRequest)
+	err := msg.Unmarshal(&req)
+	if err != nil || req == nil {
+		return &msgjson.Error{
+			Code:    msgjson.RPCParseError,
+			Message: "error parsing epoch_proof request",
+		}
+	}
+	book := r.books[req.MarketID]
+	if book == nil {
+		return &msgjson.Error{
+			Code:    msgjson.UnknownMarket,
+			Message: "unknown market: " + req.MarketID,
+		}
+	}
+	proof := book.epochProof(req.Epoch)
+	if proof == nil {
+		return &msgjson.Error{
+			Code:    msgjson.EpochProofUnavailableError,
+			Message: fmt.Sprintf("no proof available for epoch %d", req.Epoch),
+		}
+	}
+
+	resp, err := msgjson.NewResponse(msg.ID, proof, nil)
+	if err != nil {
+		log.Errorf("failed to encode epoch_proof response: %v", err)
+		return &msgjson.Error{
+			Code:    msgjson.RPCInternal,
+			Message: "encoding error",
+		}
+	}
+	if err = conn.Send(resp); err != nil {
+		log.Debugf("error sending epoch_proof response: %v", err)
+	}
+	return nil
+}
+
 func (r *BookRouter) handlePriceFeeder(conn comms.Link, msg *msgjson.Message) *msgjson.Error {
 	r.spotsMtx.RLock()
 	msg, err := msgjson.NewResponse(msg.ID, r.spots, nil)
@@ -714,7 +940,7 @@ func (r *BookRouter) handlePriceFeeder(conn comms.Link, msg *msgjson.Message) *m
 	}
 
 	if err := conn.Send(msg); err == nil {
-		r.priceFeeders.add(conn)
+		r.priceFeeders.add(conn, false)
 	} else {
 		log.Debugf("error sending price_feed response: %v", err)
 	}
@@ -724,6 +950,13 @@ func (r *BookRouter) handlePriceFeeder(conn comms.Link, msg *msgjson.Message) *m
 
 // sendNote sends a notification to the specified subscribers.
 func (r *BookRouter) sendNote(route string, subs *subscribers, note any) {
+	r.sendNoteFiltered(route, subs, note, nil)
+}
+
+// sendNoteFiltered sends a notification to the subscribers for which include
+// returns true. include is called with the subscribers' mtx read locked. If
+// include is nil, the notification is sent to all subscribers.
+func (r *BookRouter) sendNoteFiltered(route string, subs *subscribers, note any, include func(id uint64) bool) {
 	msg, err := msgjson.NewNotification(route, note)
 	if err != nil {
 		log.Errorf("error creating notification-type Message: %v", err)
@@ -732,16 +965,24 @@ func (r *BookRouter) sendNote(route string, subs *subscribers, note any) {
 	}
 
 	// Marshal and send the bytes to avoid multiple marshals when sending.
-	b, err := json.Marshal(msg)
-	if err != nil {
+	enc := msgjson.NewMessageEncoder(msg)
+	if _, err := enc.Encode(msgjson.JSONEncoding); err != nil {
 		log.Errorf("unable to marshal notification-type Message: %v", err)
 		return
 	}
 
 	var deletes []uint64
 	subs.mtx.RLock()
-	for _, conn := range subs.conns {
-		err := conn.SendRaw(b)
+	for id, conn := range subs.conns {
+		if include != nil && !include(id) {
+			continue
+		}
+		b, err := enc.Encode(conn.Encoding())
+		if err != nil {
+			log.Errorf("unable to marshal notification-type Message with %s encoding: %v", conn.Encoding(), err)
+			continue
+		}
+		err = conn.SendRaw(b)
 		if err != nil {
 			deletes = append(deletes, conn.ID())
 		}
@@ -751,6 +992,7 @@ func (r *BookRouter) sendNote(route string, subs *subscribers, note any) {
 		subs.mtx.Lock()
 		for _, id := range deletes {
 			delete(subs.conns, id)
+			delete(subs.batched, id)
 		}
 		subs.mtx.Unlock()
 	}
@@ -5,7 +5,6 @@ package market
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -1985,7 +1984,7 @@ func (m *Market) handlePreimageResp(msg *msgjson.Message, reqData *piData) {
 		sendPI(nil)
 		return
 	}
-	err = json.Unmarshal(resp.Result, &piResp)
+	err = resp.UnmarshalResult(&piResp)
 	if err != nil {
 		sendPI(nil)
 		m.respondError(msg.ID, reqData.ord.User(), msgjson.RPCParseError,
@@ -270,7 +270,11 @@ const (
 	tUserTier, tUserScore, tMaxScore = int64(1), int32(30), int32(60)
 )
 
-var parcelLimit = float64(calcParcelLimit(tUserTier, tUserScore, tMaxScore))
+var parcelLimit = float64((&account.ParcelLimits{
+	PerTier:         dex.PerTierBaseParcelLimit,
+	ScoreMultiplier: dex.ParcelLimitScoreMultiplier,
+	MaxScore:        tMaxScore,
+}).ParcelLimit(tUserTier, tUserScore))
 
 func newTestMarket(opts ...any) (*Market, *TArchivist, *TAuth, func(), error) {
 	// The DEX will make MasterCoinLockers for each asset.
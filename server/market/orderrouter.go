@@ -38,6 +38,7 @@ type AuthManager interface {
 	RecordCancel(user account.AccountID, oid, target order.OrderID, epochGap int32, t time.Time)
 	RecordCompletedOrder(user account.AccountID, oid order.OrderID, t time.Time)
 	UserReputation(user account.AccountID) (tier int64, score, maxScore int32, err error)
+	ParcelLimits() *account.ParcelLimits
 }
 
 const (
@@ -676,20 +677,6 @@ func (r *OrderRouter) sufficientAccountBalance(accountAddr string, ord order.Ord
 	return r.dexBalancer.CheckBalance(accountAddr, assetID, redeemAssetID, fundingQty, fundingLots, redeems)
 }
 
-// calcParcelLimit computes the users score-scaled user parcel limit.
-func calcParcelLimit(tier int64, score, maxScore int32) uint32 {
-	// Users limit starts at 2 parcels per tier.
-	lowerLimit := tier * dex.PerTierBaseParcelLimit
-	// Limit can scale up to 3x with score.
-	upperLimit := lowerLimit * dex.ParcelLimitScoreMultiplier
-	limitRange := upperLimit - lowerLimit
-	var scaleFactor float64
-	if score > 0 {
-		scaleFactor = float64(score) / float64(maxScore)
-	}
-	return uint32(lowerLimit) + uint32(math.Round(scaleFactor*float64(limitRange)))
-}
-
 // CheckParcelLimit checks that the user does not exceed their parcel limit.
 // The calcParcels function must be provided by the order's targeted Market, and
 // calculate the number of parcels from that market when quantity from settling
@@ -697,7 +684,7 @@ func calcParcelLimit(tier int64, score, maxScore int32) uint32 {
 // parcel limit, based on the users tier and score and active orders for ALL
 // markets.
 func (r *OrderRouter) CheckParcelLimit(user account.AccountID, targetMarketName string, calcParcels MarketParcelCalculator) bool {
-	tier, score, maxScore, err := r.auth.UserReputation(user)
+	tier, score, _, err := r.auth.UserReputation(user)
 	if err != nil {
 		log.Errorf("error getting user score for parcel limit check: %w", err)
 		return false
@@ -715,7 +702,7 @@ func (r *OrderRouter) CheckParcelLimit(user account.AccountID, targetMarketName
 		return uint32(math.Round(parcels*1e8) / 1e8)
 	}
 
-	parcelLimit := calcParcelLimit(tier, score, maxScore)
+	parcelLimit := r.auth.ParcelLimits().ParcelLimit(tier, score)
 
 	settlingQuantities := make(map[string]uint64)
 	for bq, qty := range r.swapper.UnsettledQuantity(user) {
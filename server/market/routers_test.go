@@ -258,6 +258,17 @@ func (a *TAuth) UserReputation(user account.AccountID) (tier int64, score, maxSc
 	}
 	return a.rep.tier, a.rep.score, a.rep.maxScore, a.rep.err
 }
+func (a *TAuth) ParcelLimits() *account.ParcelLimits {
+	maxScore := a.rep.maxScore
+	if maxScore == 0 {
+		maxScore = 60
+	}
+	return &account.ParcelLimits{
+		PerTier:         dex.PerTierBaseParcelLimit,
+		ScoreMultiplier: dex.ParcelLimitScoreMultiplier,
+		MaxScore:        maxScore,
+	}
+}
 func (a *TAuth) AcctStatus(user account.AccountID) (connected bool, tier int64) {
 	return true, 1
 }
@@ -776,7 +787,7 @@ func TestMain(m *testing.M) {
 		// Not counted as coverage, must test Archiver constructor explicitly.
 		var shutdown context.CancelFunc
 		testCtx, shutdown = context.WithCancel(context.Background())
-		rig.router = NewBookRouter(rig.sources(), &tFeeSource{}, func(route string, handler comms.MsgHandler) {})
+		rig.router = NewBookRouter(rig.sources(), &tFeeSource{}, func(route string, handler comms.MsgHandler) {}, false)
 		var wg sync.WaitGroup
 		wg.Add(1)
 		go func() {
@@ -1548,6 +1559,9 @@ func (conn *TLink) Send(msg *msgjson.Message) error {
 	conn.sendTrigger <- struct{}{}
 	return nil
 }
+func (conn *TLink) Encoding() msgjson.Encoding { return msgjson.JSONEncoding }
+func (conn *TLink) APIVersion() uint16         { return 0 }
+
 func (conn *TLink) SendRaw(b []byte) error {
 	conn.mtx.Lock()
 	defer conn.mtx.Unlock()
@@ -2192,6 +2206,347 @@ func TestPriceFeed(t *testing.T) {
 	}
 }
 
+func TestBookSnapshot(t *testing.T) {
+	src := tNewBookSource(btcID, ltcID)
+	router := NewBookRouter(map[string]BookSource{mktName1: src}, &tFeeSource{},
+		func(route string, handler comms.MsgHandler) {}, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		router.Run(ctx)
+		wg.Done()
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	tick(100) // let runBook start
+
+	if _, err := router.Snapshot("abc_xyz"); err == nil {
+		t.Fatalf("no error for unknown market")
+	}
+
+	link, sub := newSubscriber(mkt1)
+	if err := router.handleOrderBook(link, sub); err != nil {
+		t.Fatalf("handleOrderBook: %v", err)
+	}
+	link.getSend()
+
+	// mirror is the subscriber's copy of the book, built from the feed.
+	mirror := make(map[string]*msgjson.BookOrderNote)
+	checkSnapshot := func(lastSeq uint64) {
+		t.Helper()
+		snap, err := router.Snapshot(mktName1)
+		if err != nil {
+			t.Fatalf("Snapshot error: %v", err)
+		}
+		if snap.Seq != lastSeq {
+			t.Fatalf("wrong snapshot seq. wanted %d, got %d", lastSeq, snap.Seq)
+		}
+		mirrorBook := &msgjson.OrderBook{}
+		for _, n := range mirror {
+			mirrorBook.Orders = append(mirrorBook.Orders, n)
+		}
+		if !bytes.Equal(snap.Digest, mirrorBook.Digest()) {
+			t.Fatalf("snapshot digest does not match mirrored book")
+		}
+	}
+
+	lo1 := makeLO(seller1, mkRate1(1.0, 1.2), randLots(10)+1, order.StandingTiF)
+	lo2 := makeLO(buyer1, mkRate1(0.8, 1.0), randLots(10)+1, order.StandingTiF)
+	for _, lo := range []*order.LimitOrder{lo1, lo2} {
+		src.feed <- &updateSignal{
+			action: bookAction,
+			data:   sigDataBookedOrder{order: lo, epochIdx: 12345678},
+		}
+		n := getBookNoteFromLink(t, link)
+		mirror[n.OrderID.String()] = n
+		checkSnapshot(n.Seq)
+	}
+
+	lo1.FillAmt = mkt1.LotSize
+	src.feed <- &updateSignal{
+		action: updateRemainingAction,
+		data:   sigDataUpdateRemaining{order: lo1, epochIdx: 12345678},
+	}
+	urNote := getUpdateRemainingNoteFromLink(t, link)
+	mirror[urNote.OrderID.String()].Quantity = urNote.Remaining
+	checkSnapshot(urNote.Seq)
+
+	src.feed <- &updateSignal{
+		action: unbookAction,
+		data:   sigDataUnbookedOrder{order: lo2, epochIdx: 12345678},
+	}
+	unbookNote := getUnbookNoteFromLink(t, link)
+	delete(mirror, unbookNote.OrderID.String())
+	checkSnapshot(unbookNote.Seq)
+}
+
+func TestEpochProof(t *testing.T) {
+	src := tNewBookSource(btcID, ltcID)
+	router := NewBookRouter(map[string]BookSource{mktName1: src}, &tFeeSource{},
+		func(route string, handler comms.MsgHandler) {}, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		router.Run(ctx)
+		wg.Done()
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	tick(100) // let runBook start
+
+	link, sub := newSubscriber(mkt1)
+	if err := router.handleOrderBook(link, sub); err != nil {
+		t.Fatalf("handleOrderBook: %v", err)
+	}
+	link.getSend()
+
+	const epochIdx = 12345678
+	lo1, pimg1 := makeLORevealed(seller1, mkRate1(1.0, 1.2), randLots(10)+1, order.StandingTiF)
+	lo2, pimg2 := makeLORevealed(buyer1, mkRate1(0.8, 1.0), randLots(10)+1, order.StandingTiF)
+	mo := makeMO(buyer1, randLots(10)+1) // misses
+	for _, ord := range []order.Order{lo1, lo2, mo} {
+		src.feed <- &updateSignal{
+			action: epochAction,
+			data:   sigDataEpochOrder{order: ord, epochIdx: epochIdx},
+		}
+		getEpochNoteFromLink(t, link)
+	}
+
+	sendMatchProof := func(idx uint64, pimgs []order.Preimage, misses []order.Order, csum, seed []byte) {
+		t.Helper()
+		src.feed <- &updateSignal{
+			action: matchProofAction,
+			data: sigDataMatchProof{
+				matchProof: &order.MatchProof{
+					Epoch:     order.EpochID{Idx: idx, Dur: 1000},
+					Preimages: pimgs,
+					Misses:    misses,
+					CSum:      csum,
+					Seed:      seed,
+				},
+			},
+		}
+		if link.getSend() == nil {
+			t.Fatalf("no match_proof sent")
+		}
+	}
+	csum, seed := randomBytes(32), randomBytes(32)
+	sendMatchProof(epochIdx, []order.Preimage{pimg2, pimg1}, []order.Order{mo}, csum, seed)
+
+	requestProof := func(mktID string, idx uint64) (*msgjson.EpochProof, *msgjson.Error) {
+		t.Helper()
+		req, _ := msgjson.NewRequest(1, msgjson.EpochProofRoute, &msgjson.EpochProofRequest{
+			MarketID: mktID,
+			Epoch:    idx,
+		})
+		if rpcErr := router.handleEpochProof(link, req); rpcErr != nil {
+			return nil, rpcErr
+		}
+		resp := link.getSend()
+		if resp == nil {
+			t.Fatalf("no epoch_proof response sent")
+		}
+		proof := new(msgjson.EpochProof)
+		if err := resp.UnmarshalResult(proof); err != nil {
+			t.Fatalf("error unmarshaling epoch_proof response: %v", err)
+		}
+		return proof, nil
+	}
+
+	if _, rpcErr := requestProof("abc_xyz", epochIdx); rpcErr == nil || rpcErr.Code != msgjson.UnknownMarket {
+		t.Fatalf("wrong error for unknown market: %v", rpcErr)
+	}
+	if _, rpcErr := requestProof(mktName1, epochIdx+1); rpcErr == nil || rpcErr.Code != msgjson.EpochProofUnavailableError {
+		t.Fatalf("wrong error for unknown epoch: %v", rpcErr)
+	}
+
+	proof, rpcErr := requestProof(mktName1, epochIdx)
+	if rpcErr != nil {
+		t.Fatalf("epoch_proof error: %v", rpcErr)
+	}
+	if proof.MarketID != mktName1 || proof.Epoch != epochIdx {
+		t.Fatalf("wrong proof market or epoch: %s, %d", proof.MarketID, proof.Epoch)
+	}
+	if !bytes.Equal(proof.CSum, csum) || !bytes.Equal(proof.Seed, seed) {
+		t.Fatalf("wrong proof csum or seed")
+	}
+	expPimgs := map[order.OrderID][]byte{
+		lo1.ID(): pimg1[:],
+		lo2.ID(): pimg2[:],
+		mo.ID():  nil,
+	}
+	if len(proof.Orders) != len(expPimgs) {
+		t.Fatalf("expected %d proof orders, got %d", len(expPimgs), len(proof.Orders))
+	}
+	for _, ord := range proof.Orders {
+		var oid order.OrderID
+		copy(oid[:], ord.OrderID)
+		expPimg, found := expPimgs[oid]
+		if !found {
+			t.Fatalf("unknown order %s in proof", oid)
+		}
+		if !bytes.Equal(ord.Preimage, expPimg) {
+			t.Fatalf("wrong preimage for order %s", oid)
+		}
+	}
+
+	// A match proof that does not account for every queued order is not
+	// stored, whether an order is dropped or an unqueued miss is added.
+	queueEpoch := func(idx uint64, ords ...order.Order) {
+		t.Helper()
+		for _, ord := range ords {
+			src.feed <- &updateSignal{
+				action: epochAction,
+				data:   sigDataEpochOrder{order: ord, epochIdx: int64(idx)},
+			}
+			getEpochNoteFromLink(t, link)
+		}
+	}
+	lo3, pimg3 := makeLORevealed(seller1, mkRate1(1.0, 1.2), randLots(10)+1, order.StandingTiF)
+	queueEpoch(epochIdx+1, lo3, mo)
+	sendMatchProof(epochIdx+1, []order.Preimage{pimg3}, nil, randomBytes(32), randomBytes(32))
+	if _, rpcErr := requestProof(mktName1, epochIdx+1); rpcErr == nil || rpcErr.Code != msgjson.EpochProofUnavailableError {
+		t.Fatalf("wrong error for proof with a dropped order: %v", rpcErr)
+	}
+	lo4, pimg4 := makeLORevealed(seller1, mkRate1(1.0, 1.2), randLots(10)+1, order.StandingTiF)
+	queueEpoch(epochIdx+2, lo4)
+	sendMatchProof(epochIdx+2, []order.Preimage{pimg4}, []order.Order{mo}, randomBytes(32), randomBytes(32))
+	if _, rpcErr := requestProof(mktName1, epochIdx+2); rpcErr == nil || rpcErr.Code != msgjson.EpochProofUnavailableError {
+		t.Fatalf("wrong error for proof with an unqueued miss: %v", rpcErr)
+	}
+	// The original proof is unaffected.
+	if _, rpcErr := requestProof(mktName1, epochIdx); rpcErr != nil {
+		t.Fatalf("epoch_proof error: %v", rpcErr)
+	}
+
+	// Old proofs are pruned.
+	sendMatchProof(epochIdx+maxEpochProofs, nil, nil, randomBytes(32), nil)
+	if _, rpcErr := requestProof(mktName1, epochIdx); rpcErr == nil || rpcErr.Code != msgjson.EpochProofUnavailableError {
+		t.Fatalf("wrong error for pruned epoch: %v", rpcErr)
+	}
+}
+
+func TestBookUpdateBatch(t *testing.T) {
+	src := tNewBookSource(btcID, ltcID)
+	router := NewBookRouter(map[string]BookSource{mktName1: src}, &tFeeSource{},
+		func(route string, handler comms.MsgHandler) {}, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		router.Run(ctx)
+		wg.Done()
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	tick(100) // let runBook start
+
+	// link1 requests batches. link2 does not.
+	link1 := tNewLink()
+	sub, _ := msgjson.NewRequest(1, msgjson.OrderBookRoute, &msgjson.OrderBookSubscription{
+		Base:  mkt1.Base,
+		Quote: mkt1.Quote,
+		Batch: true,
+	})
+	if err := router.handleOrderBook(link1, sub); err != nil {
+		t.Fatalf("handleOrderBook: %v", err)
+	}
+	link1.getSend()
+	link2, sub := newSubscriber(mkt1)
+	if err := router.handleOrderBook(link2, sub); err != nil {
+		t.Fatalf("handleOrderBook: %v", err)
+	}
+	link2.getSend()
+
+	// The unbatched subscriber gets the individual notifications.
+	lo := makeLO(seller1, mkRate1(1.0, 1.2), randLots(10)+1, order.StandingTiF)
+	src.feed <- &updateSignal{
+		action: bookAction,
+		data:   sigDataBookedOrder{order: lo, epochIdx: 12345678},
+	}
+	bookNote := getBookNoteFromLink(t, link2)
+	lo.FillAmt = mkt1.LotSize
+	src.feed <- &updateSignal{
+		action: updateRemainingAction,
+		data:   sigDataUpdateRemaining{order: lo, epochIdx: 12345678},
+	}
+	urNote := getUpdateRemainingNoteFromLink(t, link2)
+	src.feed <- &updateSignal{
+		action: unbookAction,
+		data:   sigDataUnbookedOrder{order: lo, epochIdx: 12345678},
+	}
+	unbookNote := getUnbookNoteFromLink(t, link2)
+	if urNote.Seq != bookNote.Seq+1 || unbookNote.Seq != urNote.Seq+1 {
+		t.Fatalf("wrong sequence %d, %d, %d", bookNote.Seq, urNote.Seq, unbookNote.Seq)
+	}
+
+	src.feed <- &updateSignal{
+		action: epochReportAction,
+		data: sigDataEpochReport{
+			epochIdx: 12345678,
+			epochDur: 1000,
+			stats:    &matcher.MatchCycleStats{},
+		},
+	}
+
+	// The batch is sent to the batched subscriber before the epoch report.
+	msg := link1.getSend()
+	if msg.Route != msgjson.BookUpdateBatchRoute {
+		t.Fatalf("expected %s, got %s", msgjson.BookUpdateBatchRoute, msg.Route)
+	}
+	batch := new(msgjson.BookUpdateBatch)
+	if err := msg.Unmarshal(batch); err != nil {
+		t.Fatalf("error unmarshaling batch: %v", err)
+	}
+	if batch.MarketID != mktName1 || len(batch.Updates) != 3 {
+		t.Fatalf("wrong batch for market %s with %d updates", batch.MarketID, len(batch.Updates))
+	}
+	u := batch.Updates
+	if u[0].Book == nil || u[1].UpdateRemaining == nil || u[2].Unbook == nil {
+		t.Fatalf("wrong update types in batch")
+	}
+	for i, want := range []uint64{bookNote.Seq, urNote.Seq, unbookNote.Seq} {
+		if u[i].Seq() != want {
+			t.Fatalf("update %d: wanted seq %d, got %d", i, want, u[i].Seq())
+		}
+	}
+	if u[0].Book.OrderID.String() != lo.ID().String() {
+		t.Fatalf("wrong order ID in batch")
+	}
+	if u[1].UpdateRemaining.Remaining != lo.Remaining() {
+		t.Fatalf("wrong remaining in batch. wanted %d, got %d", lo.Remaining(), u[1].UpdateRemaining.Remaining)
+	}
+
+	for _, link := range []*TLink{link1, link2} {
+		if msg := link.getSend(); msg.Route != msgjson.EpochReportRoute {
+			t.Fatalf("expected %s, got %s", msgjson.EpochReportRoute, msg.Route)
+		}
+	}
+
+	// Nothing is sent to the batched subscriber when there are no updates.
+	src.feed <- &updateSignal{
+		action: epochReportAction,
+		data: sigDataEpochReport{
+			epochIdx: 12345679,
+			epochDur: 1000,
+			stats:    &matcher.MatchCycleStats{},
+		},
+	}
+	for _, link := range []*TLink{link1, link2} {
+		if msg := link.getSend(); msg.Route != msgjson.EpochReportRoute {
+			t.Fatalf("expected %s, got %s", msgjson.EpochReportRoute, msg.Route)
+		}
+	}
+}
+
 func TestParcelLimits(t *testing.T) {
 	mkt0 := tNewMarket(oRig.auth)
 	mkt1 := tNewMarket(oRig.auth)
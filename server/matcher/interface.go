@@ -20,6 +20,7 @@ type Booker interface {
 
 // MatchCycleStats is data about the results of a match cycle.
 type MatchCycleStats struct {
+	Matches     uint64
 	MatchVolume uint64
 	QuoteVolume uint64
 	BookSells   uint64
@@ -156,6 +156,7 @@ func (m *Matcher) Match(book Booker, queue []*OrderRevealed) (seed []byte, match
 	appendTradeSet := func(matchSet *order.MatchSet) {
 		matches = append(matches, matchSet)
 
+		stats.Matches += uint64(len(matchSet.Makers))
 		stats.MatchVolume += matchSet.Total
 		high, low := matchSet.HighLowRates()
 		if high > stats.HighRate {
@@ -0,0 +1,108 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package swap
+
+import (
+	"context"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/asset"
+)
+
+// swapProgressConfsTimeout is the timeout for checking the confirmations of a
+// swap for a swap_progress request.
+const swapProgressConfsTimeout = time.Second * 5
+
+// swapProgress returns the server's view of a party's swap and redeem.
+func (s *Swapper) swapProgress(ctx context.Context, status *swapStatus) *msgjson.SwapProgress {
+	toMS := func(t time.Time) uint64 {
+		if t.IsZero() {
+			return 0
+		}
+		return uint64(t.UnixMilli())
+	}
+
+	status.mtx.RLock()
+	p := &msgjson.SwapProgress{
+		SwapAsset:     status.swapAsset,
+		SwapSeen:      toMS(status.swapTime),
+		SwapConfirmed: toMS(status.swapConfirmed),
+		RedeemAsset:   status.redeemAsset,
+		RedeemSeen:    toMS(status.redeemTime),
+	}
+	var swap *asset.Contract
+	if status.swap != nil {
+		swap = status.swap
+		p.SwapCoin = swap.ID()
+	}
+	if status.redemption != nil {
+		p.RedeemCoin = status.redemption.ID()
+	}
+	status.mtx.RUnlock()
+
+	if a := s.coins[p.SwapAsset]; a != nil {
+		p.SwapConfsRequired = a.SwapConf
+	}
+	if swap != nil {
+		confs, err := swap.Confirmations(ctx)
+		if err != nil {
+			log.Debugf("Error checking confirmations of swap %v: %v", swap, err)
+			confs = -1
+		}
+		p.SwapConfs = confs
+	}
+	return p
+}
+
+// handleSwapProgress handles the 'swap_progress' request, which reports the
+// server's view of an active match's swap and redeem transactions to either
+// party of the match. This lets a client distinguish a counterparty that has
+// not acted from a counterparty transaction that the client's wallet cannot
+// find.
+func (s *Swapper) handleSwapProgress(user account.AccountID, msg *msgjson.Message) *msgjson.Error {
+	params := new(msgjson.SwapProgressRequest)
+	err := msg.Unmarshal(&params)
+	if err != nil || params == nil {
+		return &msgjson.Error{
+			Code:    msgjson.RPCParseError,
+			Message: "Error decoding 'swap_progress' request payload",
+		}
+	}
+	if len(params.MatchID) != order.MatchIDSize {
+		return &msgjson.Error{
+			Code:    msgjson.RPCParseError,
+			Message: "Invalid 'matchid' in 'swap_progress' message",
+		}
+	}
+
+	var matchID order.MatchID
+	copy(matchID[:], params.MatchID)
+	s.matchMtx.RLock()
+	match, found := s.matches[matchID]
+	s.matchMtx.RUnlock()
+	// Users may only see the progress of their own matches.
+	if !found || (match.Maker.User() != user && match.Taker.User() != user) {
+		return &msgjson.Error{
+			Code:    msgjson.RPCUnknownMatch,
+			Message: "unknown or inactive match ID",
+		}
+	}
+
+	match.mtx.RLock()
+	matchStatus := match.Status
+	match.mtx.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), swapProgressConfsTimeout)
+	defer cancel()
+	s.respondSuccess(msg.ID, user, &msgjson.SwapProgressResult{
+		MatchID: matchID[:],
+		Status:  uint8(matchStatus),
+		Maker:   s.swapProgress(ctx, match.makerStatus),
+		Taker:   s.swapProgress(ctx, match.takerStatus),
+	})
+	return nil
+}
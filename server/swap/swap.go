@@ -229,6 +229,8 @@ type Swapper struct {
 	authMgr AuthManager
 	// swapDone is callback for reporting a swap outcome.
 	swapDone func(oid order.Order, match *order.Match, fail bool)
+	// matchComplete is an optional callback for completed matches.
+	matchComplete func(match *order.Match, completed time.Time)
 
 	// The matches maps and the contained matches are protected by the matchMtx.
 	matchMtx    sync.RWMutex
@@ -285,6 +287,9 @@ type Config struct {
 	// SwapDone registers a match with the DEX manager (or other consumer) for a
 	// given order as being finished.
 	SwapDone func(oid order.Order, match *order.Match, fail bool)
+	// MatchComplete is an optional function that is called when a match is
+	// complete, i.e. when the taker's redeem is recorded.
+	MatchComplete func(match *order.Match, completed time.Time)
 }
 
 // NewSwapper is a constructor for a Swapper.
@@ -308,6 +313,7 @@ func NewSwapper(cfg *Config) (*Swapper, error) {
 		storage:          cfg.Storage,
 		authMgr:          authMgr,
 		swapDone:         cfg.SwapDone,
+		matchComplete:    cfg.MatchComplete,
 		latencyQ:         wait.NewTaperingTickerQueue(fastRecheckInterval, taperedRecheckInterval),
 		matches:          make(map[order.MatchID]*matchTracker),
 		userMatches:      make(map[account.AccountID]map[order.MatchID]*matchTracker),
@@ -330,10 +336,11 @@ func NewSwapper(cfg *Config) (*Swapper, error) {
 		}
 	}
 
-	// The swapper is only concerned with two types of client-originating
-	// method requests.
+	// The swapper handles the client-originating method requests for swap
+	// negotiation, and requests for the progress of active swaps.
 	authMgr.Route(msgjson.InitRoute, swapper.handleInit)
 	authMgr.Route(msgjson.RedeemRoute, swapper.handleRedeem)
+	authMgr.Route(msgjson.SwapProgressRoute, swapper.handleSwapProgress)
 
 	return swapper, nil
 }
@@ -1850,6 +1857,9 @@ func (s *Swapper) processRedeem(msg *msgjson.Message, params *msgjson.Redeem, st
 	}
 
 	s.swapDone(ord, match.Match, false)
+	if newStatus == order.MatchComplete && s.matchComplete != nil {
+		s.matchComplete(match.Match, redeemTime)
+	}
 
 	// Inform the counterparty, even though the maker doesn't really care about
 	// the taker's redeem details.
@@ -577,6 +577,7 @@ type testRig struct {
 	matches       *tMatchSet
 	matchInfo     *tMatch
 	noResume      bool
+	completed     chan order.MatchID
 }
 
 func tNewTestRig(matchInfo *tMatch) (*testRig, func()) {
@@ -596,6 +597,7 @@ func tNewTestRig(matchInfo *tMatch) (*testRig, func()) {
 
 	acctAsset := TNewAsset(acctBackend, ACCTID)
 
+	completed := make(chan order.MatchID, 16)
 	swapper, err := NewSwapper(&Config{
 		Assets: map[uint32]*SwapperAsset{
 			ABCID:  {abcAsset, abcCoinLocker},
@@ -609,6 +611,12 @@ func tNewTestRig(matchInfo *tMatch) (*testRig, func()) {
 		LockTimeTaker:    dex.LockTimeTaker(dex.Testnet),
 		LockTimeMaker:    dex.LockTimeMaker(dex.Testnet),
 		SwapDone:         func(ord order.Order, match *order.Match, fail bool) {},
+		MatchComplete: func(match *order.Match, _ time.Time) {
+			select {
+			case completed <- match.ID():
+			default:
+			}
+		},
 	})
 	if err != nil {
 		panic(err.Error())
@@ -634,6 +642,7 @@ func tNewTestRig(matchInfo *tMatch) (*testRig, func()) {
 		storage:       storage,
 		matchInfo:     matchInfo,
 		noResume:      noResume,
+		completed:     completed,
 	}, cleanup
 }
 
@@ -1005,6 +1014,14 @@ func (rig *testRig) redeem_taker(expectSuccess bool) error {
 		if tracker.Status != order.MatchComplete {
 			return fmt.Errorf("unexpected swap status %d after taker redeem notification", tracker.Status)
 		}
+		select {
+		case mid := <-rig.completed:
+			if mid != tracker.ID() {
+				return fmt.Errorf("match %s reported complete instead of %s", mid, tracker.ID())
+			}
+		default:
+			return fmt.Errorf("match %s not reported complete", tracker.ID())
+		}
 		err = rig.checkServerResponseSuccess(matchInfo.taker)
 		if err != nil {
 			return fmt.Errorf("check server response success: %w", err)
@@ -2349,3 +2366,64 @@ func TestAccountTracking(t *testing.T) {
 
 // TODO: TestSwapper_restoreActiveSwaps? It would be almost entirely driven by
 // stubbed out asset backend and storage.
+
+func TestSwapProgress(t *testing.T) {
+	set := tPerfectLimitLimit(uint64(1e8), uint64(1e8), true)
+	matchInfo := set.matchInfos[0]
+	rig, cleanup := tNewTestRig(matchInfo)
+	defer cleanup()
+
+	rig.auth.auditReq = make(chan struct{}, 1)
+	rig.auth.swapReceived = make(chan struct{}, 1)
+
+	rig.swapper.Negotiate([]*order.MatchSet{set.matchSet})
+	ensureNilErr := makeEnsureNilErr(t)
+	ensureNilErr(rig.ackMatch_maker(true))
+	ensureNilErr(rig.ackMatch_taker(true))
+	ensureNilErr(rig.sendSwap_maker(true))
+
+	progress := func(user account.AccountID) (*msgjson.SwapProgressResult, *msgjson.Error) {
+		t.Helper()
+		req, _ := msgjson.NewRequest(1, msgjson.SwapProgressRoute, &msgjson.SwapProgressRequest{
+			MatchID: matchInfo.matchID[:],
+		})
+		if rpcErr := rig.swapper.handleSwapProgress(user, req); rpcErr != nil {
+			return nil, rpcErr
+		}
+		_, resp := rig.auth.popResp(user)
+		if resp == nil {
+			t.Fatalf("no swap_progress response")
+		}
+		if resp.Error != nil {
+			t.Fatalf("swap_progress error response: %v", resp.Error)
+		}
+		res := new(msgjson.SwapProgressResult)
+		if err := json.Unmarshal(resp.Result, res); err != nil {
+			t.Fatalf("error decoding swap_progress result: %v", err)
+		}
+		return res, nil
+	}
+
+	// The taker sees the maker's swap.
+	res, rpcErr := progress(matchInfo.taker.acct)
+	if rpcErr != nil {
+		t.Fatalf("swap_progress error: %v", rpcErr)
+	}
+	if res.Status != uint8(order.MakerSwapCast) {
+		t.Fatalf("wrong status %d", res.Status)
+	}
+	if !bytes.Equal(res.Maker.SwapCoin, matchInfo.db.makerSwap.coin.ID()) {
+		t.Fatalf("wrong maker swap coin")
+	}
+	if res.Maker.SwapSeen == 0 || res.Maker.SwapConfsRequired != rig.abc.SwapConf {
+		t.Fatalf("wrong maker swap progress: %+v", res.Maker)
+	}
+	if len(res.Taker.SwapCoin) != 0 || res.Taker.SwapSeen != 0 {
+		t.Fatalf("taker swap reported before it was sent")
+	}
+
+	// Users that are not party to the match can't see it.
+	if _, rpcErr = progress(tNewUser("other").acct); rpcErr == nil || rpcErr.Code != msgjson.RPCUnknownMatch {
+		t.Fatalf("expected unknown match error for another user, got %v", rpcErr)
+	}
+}
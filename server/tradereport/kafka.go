@@ -0,0 +1,99 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package tradereport
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// kafkaWriter is satisfied by *kafka.Writer.
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaSink writes each report to a Kafka topic as a message with the match ID
+// as the key and the JSON report as the value. Connections to the brokers use
+// TLS. A report is sent when it is acknowledged by all in-sync replicas.
+type KafkaSink struct {
+	w kafkaWriter
+}
+
+var _ Sink = (*KafkaSink)(nil)
+
+// NewKafkaSink is the constructor for a KafkaSink. The destination has the
+// form kafka://host:port[,host:port...]/topic. If auth is not empty, it is the
+// "user:password" for SASL PLAIN authentication with the brokers.
+func NewKafkaSink(dest, auth string) (*KafkaSink, error) {
+	brokersTopic, ok := strings.CutPrefix(dest, "kafka://")
+	if !ok {
+		return nil, fmt.Errorf("trade report destination %q is not a kafka:// URL", dest)
+	}
+	brokerList, topic, _ := strings.Cut(brokersTopic, "/")
+	if topic == "" || strings.Contains(topic, "/") {
+		return nil, fmt.Errorf("no Kafka topic in trade report destination %q", dest)
+	}
+	var brokers []string
+	for _, broker := range strings.Split(brokerList, ",") {
+		if host, port, err := net.SplitHostPort(broker); err != nil || host == "" || port == "" {
+			return nil, fmt.Errorf("invalid Kafka broker address %q. expected host:port", broker)
+		}
+		brokers = append(brokers, broker)
+	}
+
+	transport := &kafka.Transport{
+		DialTimeout: httpTimeout,
+		TLS:         &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	if auth != "" {
+		user, pass, ok := strings.Cut(auth, ":")
+		if !ok || user == "" {
+			return nil, errors.New(`Kafka trade report authorization must be "user:password"`)
+		}
+		transport.SASL = plain.Mechanism{Username: user, Password: pass}
+	}
+
+	return &KafkaSink{
+		w: &kafka.Writer{
+			Addr:      kafka.TCP(brokers...),
+			Topic:     topic,
+			Transport: transport,
+			Balancer:  &kafka.Hash{},
+			// The Reporter retries failed reports, and sends them one at a
+			// time.
+			MaxAttempts:  1,
+			BatchSize:    1,
+			BatchTimeout: time.Millisecond,
+			WriteTimeout: httpTimeout,
+			RequiredAcks: kafka.RequireAll,
+		},
+	}, nil
+}
+
+// Send writes the report to the topic.
+func (s *KafkaSink) Send(ctx context.Context, rpt *MatchReport) error {
+	b, err := json.Marshal(rpt)
+	if err != nil {
+		return err
+	}
+	return s.w.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(rpt.MatchID),
+		Value: b,
+	})
+}
+
+// Close closes the connections to the brokers.
+func (s *KafkaSink) Close() error {
+	return s.w.Close()
+}
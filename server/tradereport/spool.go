@@ -0,0 +1,182 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package tradereport
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// compactAcks is the number of acknowledgements after which the spool file is
+// rewritten with only the pending reports, if they outnumber the pending
+// reports.
+const compactAcks = 1000
+
+// spoolRecord is a line of the spool file. Either Report is a report to be
+// sent, or Sent is the match ID of a report that the sink acknowledged.
+type spoolRecord struct {
+	Report *MatchReport `json:"report,omitempty"`
+	Sent   string       `json:"sent,omitempty"`
+}
+
+// spool is an append-only file of the reports that the sink has not yet
+// acknowledged, so that they are not lost if the sink is down when the server
+// is stopped. The spool is not safe for concurrent use.
+type spool struct {
+	path string
+	f    *os.File
+	// acks is the number of acknowledgements since the file was rewritten.
+	acks int
+}
+
+// openSpool opens the spool file, creating it if necessary, and returns the
+// reports that were not acknowledged, in the order they were added. The file
+// is rewritten with only those reports. A partial last line, as left by a
+// crash during a write, is discarded.
+func openSpool(path string) (*spool, []*MatchReport, error) {
+	if path == "" {
+		return nil, nil, errors.New("no trade report spool path")
+	}
+	rpts, err := readSpool(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &spool{path: path}
+	if err := s.rewrite(rpts); err != nil {
+		return nil, nil, err
+	}
+	return s, rpts, nil
+}
+
+// readSpool reads the unacknowledged reports from the spool file.
+func readSpool(path string) ([]*MatchReport, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening trade report spool: %w", err)
+	}
+	defer f.Close()
+
+	var rpts []*MatchReport
+	idx := make(map[string]int) // match ID => index in rpts
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	var badLine error
+	for n := 1; scanner.Scan(); n++ {
+		if badLine != nil {
+			// Only the last line may be partial.
+			return nil, badLine
+		}
+		var rec spoolRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			badLine = fmt.Errorf("error decoding line %d of trade report spool: %w", n, err)
+			continue
+		}
+		switch {
+		case rec.Report != nil:
+			idx[rec.Report.MatchID] = len(rpts)
+			rpts = append(rpts, rec.Report)
+		case rec.Sent != "":
+			if i, found := idx[rec.Sent]; found {
+				rpts[i] = nil
+				delete(idx, rec.Sent)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading trade report spool: %w", err)
+	}
+	pending := make([]*MatchReport, 0, len(idx))
+	for _, rpt := range rpts {
+		if rpt != nil {
+			pending = append(pending, rpt)
+		}
+	}
+	return pending, nil
+}
+
+// add appends the report to the spool and syncs it to disk.
+func (s *spool) add(rpt *MatchReport) error {
+	return s.append(&spoolRecord{Report: rpt})
+}
+
+// sent records that the sink acknowledged the report. pending are the
+// reports that are still unacknowledged. If there are none, the file is
+// truncated. If the acknowledgements far outnumber them, the file is
+// rewritten with only the pending reports.
+func (s *spool) sent(matchID string, pending []*MatchReport) error {
+	if len(pending) == 0 || (s.acks >= compactAcks && s.acks > len(pending)) {
+		return s.rewrite(pending)
+	}
+	if err := s.append(&spoolRecord{Sent: matchID}); err != nil {
+		return err
+	}
+	s.acks++
+	return nil
+}
+
+func (s *spool) append(rec *spoolRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+// rewrite replaces the spool file with one containing only the reports. The
+// new file is written and synced before it replaces the old one.
+func (s *spool) rewrite(rpts []*MatchReport) error {
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating trade report spool: %w", err)
+	}
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, rpt := range rpts {
+		if err := enc.Encode(&spoolRecord{Report: rpt}); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if s.f != nil {
+		s.f.Close()
+		s.f = nil
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("error replacing trade report spool: %w", err)
+	}
+	s.f, err = os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening trade report spool: %w", err)
+	}
+	s.acks = 0
+	return nil
+}
+
+func (s *spool) close() error {
+	if s.f == nil {
+		return nil
+	}
+	return s.f.Close()
+}
@@ -0,0 +1,523 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package tradereport emits a record of each completed match to an
+// operator-configured sink, for operators that must report trades to a
+// compliance or accounting system.
+//
+// Each completed match is reported as a MatchReport. The JSON encoding of a
+// MatchReport is the documented schema, and is versioned by the Version field.
+// Fields are only ever added within a version. The built-in sinks are a file
+// of newline-delimited JSON records (see NewFileSink), an HTTPS endpoint that
+// receives one JSON record per POST request (see NewHTTPSink), and a Kafka
+// topic (see NewKafkaSink). Other destinations can be supported by
+// implementing Sink.
+//
+// Reports are kept in a spool file until the sink accepts them, so reports
+// are not lost while the sink is down or when the server is stopped. A report
+// may be sent more than once if the server stops after the sink accepts it but
+// before that is recorded, so consumers should deduplicate by match ID.
+package tradereport
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/asset"
+	"decred.org/dcrdex/server/db"
+)
+
+// SchemaVersion is the version of the MatchReport schema.
+const SchemaVersion = 1
+
+const (
+	// minRetryDelay and maxRetryDelay bound the delay between attempts to
+	// send a report that failed, and after the sink fails repeatedly.
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+	// httpTimeout is the timeout for a request to an HTTPS sink.
+	httpTimeout = 30 * time.Second
+)
+
+// MatchParty is one party of a reported match. The swap is the party's swap
+// contract on the asset they sold, and the redeem is their redemption of the
+// counterparty's swap on the asset they bought. Coin IDs are in the asset's
+// human-readable format, or hex if the format is not known.
+type MatchParty struct {
+	AccountID    string `json:"accountID"`
+	OrderID      string `json:"orderID"`
+	Sell         bool   `json:"sell"`
+	SwapCoinID   string `json:"swapCoinID,omitempty"`
+	SwapTime     int64  `json:"swapTime,omitempty"` // unix ms
+	RedeemCoinID string `json:"redeemCoinID,omitempty"`
+	RedeemTime   int64  `json:"redeemTime,omitempty"` // unix ms
+}
+
+// MatchReport is the record of a completed match. Quantities are in atoms of
+// the asset. Rate is the price of one unit of the base asset in the quote
+// asset, in the encoding used by the DEX's messages. See calc.BaseToQuote.
+type MatchReport struct {
+	Version      uint32     `json:"version"`
+	MatchID      string     `json:"matchID"`
+	Market       string     `json:"market"`
+	BaseID       uint32     `json:"baseID"`
+	QuoteID      uint32     `json:"quoteID"`
+	Epoch        uint64     `json:"epoch"`
+	EpochDur     uint64     `json:"epochDur"`     // ms
+	MatchTime    int64      `json:"matchTime"`    // unix ms, the close of the epoch
+	CompleteTime int64      `json:"completeTime"` // unix ms, the taker's redeem
+	Rate         uint64     `json:"rate"`
+	Quantity     uint64     `json:"qty"`
+	QuoteQty     uint64     `json:"quoteQty"`
+	Maker        MatchParty `json:"maker"`
+	Taker        MatchParty `json:"taker"`
+}
+
+// Sink is a destination for match reports. Send is only called from a single
+// goroutine. If Send returns an error, the report is sent again after a delay,
+// so Send should not partially record a report. A Sink that is also an
+// io.Closer is closed when the Reporter stops.
+type Sink interface {
+	Send(ctx context.Context, rpt *MatchReport) error
+}
+
+// NewSink creates one of the built-in sinks from a destination string. An
+// https:// URL creates an HTTPS sink that sets the Authorization header of each
+// request to auth, if auth is not empty. A kafka:// URL creates a Kafka sink,
+// for which auth is the "user:password" for SASL PLAIN authentication. Any
+// other destination is a file path, optionally prefixed with file://.
+func NewSink(dest, auth string) (Sink, error) {
+	switch {
+	case strings.HasPrefix(dest, "https://"):
+		return NewHTTPSink(dest, auth)
+	case strings.HasPrefix(dest, "http://"):
+		return nil, errors.New("trade reports may only be sent to an https:// endpoint")
+	case strings.HasPrefix(dest, "kafka://"):
+		return NewKafkaSink(dest, auth)
+	}
+	return NewFileSink(strings.TrimPrefix(dest, "file://"))
+}
+
+// FileSink appends each report to a file as a line of JSON.
+type FileSink struct {
+	mtx sync.Mutex
+	f   *os.File
+}
+
+var _ Sink = (*FileSink)(nil)
+
+// NewFileSink opens the file for appending, creating it if necessary.
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("no trade report file path")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening trade report file: %w", err)
+	}
+	return &FileSink{f: f}, nil
+}
+
+// Send writes the report to the file and syncs it to disk.
+func (s *FileSink) Send(_ context.Context, rpt *MatchReport) error {
+	b, err := json.Marshal(rpt)
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, err := s.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.f.Close()
+}
+
+// HTTPSink POSTs each report as JSON to an HTTPS endpoint. Any response status
+// other than 2XX is an error, and the report will be sent again.
+type HTTPSink struct {
+	url    string
+	auth   string
+	client *http.Client
+}
+
+var _ Sink = (*HTTPSink)(nil)
+
+// NewHTTPSink is the constructor for an HTTPSink. If auth is not empty, it is
+// the value of the Authorization header of each request, e.g. "Bearer abc".
+func NewHTTPSink(endpoint, auth string) (*HTTPSink, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trade report URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("trade report URL %q is not an https URL", endpoint)
+	}
+	return &HTTPSink{
+		url:    endpoint,
+		auth:   auth,
+		client: &http.Client{Timeout: httpTimeout},
+	}, nil
+}
+
+// Send POSTs the report to the endpoint.
+func (s *HTTPSink) Send(ctx context.Context, rpt *MatchReport) error {
+	b, err := json.Marshal(rpt)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.auth != "" {
+		req.Header.Set("Authorization", s.auth)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("trade report endpoint responded with status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Storage is the storage that the swap data of completed matches is read from.
+type Storage interface {
+	SwapData(mid db.MarketMatchID) (order.MatchStatus, *db.SwapData, error)
+}
+
+// Config is the configuration for a Reporter.
+type Config struct {
+	Sink    Sink
+	Storage Storage
+	Logger  dex.Logger
+	// SpoolPath is the path of the file that reports are kept in until the
+	// sink accepts them.
+	SpoolPath string
+}
+
+// Reporter sends a MatchReport to the Sink for each completed match. Reports
+// are sent in the order that the matches complete, except that a report that
+// the sink fails to accept is retried after a delay, while later reports are
+// sent. If the sink fails repeatedly, sending pauses for a delay. Reports that
+// were not sent when the Reporter stops are sent when the next Reporter with
+// the same spool runs. Reporter satisfies dex.Runner.
+type Reporter struct {
+	sink    Sink
+	storage Storage
+	log     dex.Logger
+	// newReport is signaled when a report is added.
+	newReport chan struct{}
+
+	mtx     sync.Mutex
+	spool   *spool
+	pending []*pendingReport
+
+	// sinkRetry is when the sink is tried again after failing repeatedly, and
+	// sinkDelay is the delay after the next repeated failure. They are only
+	// accessed by the Run goroutine.
+	sinkRetry time.Time
+	sinkDelay time.Duration
+}
+
+var _ dex.Runner = (*Reporter)(nil)
+
+// pendingReport is a report that the sink has not accepted. The swap data is
+// loaded from storage by the Run goroutine before the first attempt to send
+// the report. All fields other than the embedded *MatchReport are only
+// accessed by the Run goroutine.
+type pendingReport struct {
+	*MatchReport
+	mid         db.MarketMatchID
+	swapDataSet bool
+	// retry is when a report that failed can be sent again, and delay is the
+	// delay after the next failure.
+	retry time.Time
+	delay time.Duration
+}
+
+// newPendingReport creates a pendingReport, parsing the market and match ID
+// from the report.
+func newPendingReport(rpt *MatchReport) (*pendingReport, error) {
+	mid, err := order.DecodeMatchID(rpt.MatchID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid match ID %q: %w", rpt.MatchID, err)
+	}
+	return &pendingReport{
+		MatchReport: rpt,
+		mid: db.MarketMatchID{
+			MatchID: mid,
+			Base:    rpt.BaseID,
+			Quote:   rpt.QuoteID,
+		},
+		delay: minRetryDelay,
+	}, nil
+}
+
+// NewReporter is the constructor for a Reporter. The reports that were not
+// sent by a previous Reporter with the same spool are loaded to be sent.
+func NewReporter(cfg *Config) (*Reporter, error) {
+	sp, rpts, err := openSpool(cfg.SpoolPath)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reporter{
+		sink:      cfg.Sink,
+		storage:   cfg.Storage,
+		log:       cfg.Logger,
+		newReport: make(chan struct{}, 1),
+		spool:     sp,
+		pending:   make([]*pendingReport, 0, len(rpts)),
+		sinkDelay: minRetryDelay,
+	}
+	for _, rpt := range rpts {
+		pr, err := newPendingReport(rpt)
+		if err != nil {
+			r.log.Errorf("Discarding spooled trade report: %v", err)
+			continue
+		}
+		r.pending = append(r.pending, pr)
+	}
+	if len(r.pending) > 0 {
+		r.log.Infof("%d completed matches from before the last shutdown will be reported", len(r.pending))
+	}
+	return r, nil
+}
+
+// MatchComplete spools a report for the completed match, to be sent by the Run
+// goroutine. MatchComplete does not wait for the sink.
+func (r *Reporter) MatchComplete(match *order.Match, completed time.Time) {
+	mid := db.MatchID(match)
+	mktName, _ := dex.MarketName(mid.Base, mid.Quote)
+	rpt := &pendingReport{
+		MatchReport: &MatchReport{
+			Version:      SchemaVersion,
+			MatchID:      mid.MatchID.String(),
+			Market:       mktName,
+			BaseID:       mid.Base,
+			QuoteID:      mid.Quote,
+			Epoch:        match.Epoch.Idx,
+			EpochDur:     match.Epoch.Dur,
+			MatchTime:    match.Epoch.End().UnixMilli(),
+			CompleteTime: completed.UnixMilli(),
+			Rate:         match.Rate,
+			Quantity:     match.Quantity,
+			QuoteQty:     calc.BaseToQuote(match.Rate, match.Quantity),
+			Maker: MatchParty{
+				AccountID: match.Maker.User().String(),
+				OrderID:   match.Maker.ID().String(),
+				Sell:      match.Maker.Sell,
+			},
+			Taker: MatchParty{
+				AccountID: match.Taker.User().String(),
+				OrderID:   match.Taker.ID().String(),
+				Sell:      !match.Maker.Sell,
+			},
+		},
+		mid:   mid,
+		delay: minRetryDelay,
+	}
+	r.mtx.Lock()
+	if err := r.spool.add(rpt.MatchReport); err != nil {
+		// The report is still sent if the server keeps running.
+		r.log.Errorf("Error spooling report for match %s: %v", rpt.MatchID, err)
+	}
+	r.pending = append(r.pending, rpt)
+	r.mtx.Unlock()
+	select {
+	case r.newReport <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends the pending reports to the sink until the context is canceled.
+func (r *Reporter) Run(ctx context.Context) {
+	defer func() {
+		r.mtx.Lock()
+		if n := len(r.pending); n > 0 {
+			r.log.Warnf("%d completed matches were not reported before shutdown. "+
+				"They will be reported after the next start.", n)
+		}
+		if err := r.spool.close(); err != nil {
+			r.log.Errorf("Error closing trade report spool: %v", err)
+		}
+		r.mtx.Unlock()
+		if c, is := r.sink.(io.Closer); is {
+			if err := c.Close(); err != nil {
+				r.log.Errorf("Error closing trade report sink: %v", err)
+			}
+		}
+	}()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		r.sendDue(ctx)
+		var wait <-chan time.Time
+		if next, ok := r.nextRetry(); ok {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(time.Until(next))
+			wait = timer.C
+		}
+		select {
+		case <-r.newReport:
+		case <-wait:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendDue sends the pending reports that are not waiting to be retried, in
+// order. If two reports in a row fail, the sink is assumed to be failing, and
+// the rest of the reports wait until the sink is tried again.
+func (r *Reporter) sendDue(ctx context.Context) {
+	now := time.Now()
+	if now.Before(r.sinkRetry) {
+		return
+	}
+	r.mtx.Lock()
+	due := make([]*pendingReport, 0, len(r.pending))
+	for _, rpt := range r.pending {
+		if !now.Before(rpt.retry) {
+			due = append(due, rpt)
+		}
+	}
+	r.mtx.Unlock()
+
+	var fails int
+	for _, rpt := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		if !rpt.swapDataSet {
+			r.addSwapData(rpt)
+			rpt.swapDataSet = true
+		}
+		if err := r.sink.Send(ctx, rpt.MatchReport); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.log.Errorf("Error reporting match %s. Retrying in %v: %v", rpt.MatchID, rpt.delay, err)
+			rpt.retry = time.Now().Add(rpt.delay)
+			rpt.delay = nextDelay(rpt.delay)
+			if fails++; fails > 1 {
+				r.log.Errorf("Trade report sink is failing. Pausing reports for %v", r.sinkDelay)
+				r.sinkRetry = time.Now().Add(r.sinkDelay)
+				r.sinkDelay = nextDelay(r.sinkDelay)
+				return
+			}
+			continue
+		}
+		fails = 0
+		r.sinkDelay = minRetryDelay
+		r.sent(rpt)
+	}
+}
+
+// sent removes the report from the pending reports and the spool.
+func (r *Reporter) sent(rpt *pendingReport) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	rpts := make([]*MatchReport, 0, len(r.pending))
+	for i := 0; i < len(r.pending); i++ {
+		if r.pending[i] == rpt {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			i--
+			continue
+		}
+		rpts = append(rpts, r.pending[i].MatchReport)
+	}
+	if err := r.spool.sent(rpt.MatchID, rpts); err != nil {
+		r.log.Errorf("Error recording sent report for match %s in spool: %v", rpt.MatchID, err)
+	}
+}
+
+// nextRetry is the earliest time that a pending report can be sent. ok is
+// false if there are no pending reports.
+func (r *Reporter) nextRetry() (next time.Time, ok bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for _, rpt := range r.pending {
+		if !ok || rpt.retry.Before(next) {
+			next, ok = rpt.retry, true
+		}
+	}
+	if ok && next.Before(r.sinkRetry) {
+		next = r.sinkRetry
+	}
+	return next, ok
+}
+
+// nextDelay doubles the retry delay, up to maxRetryDelay.
+func nextDelay(delay time.Duration) time.Duration {
+	if delay *= 2; delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// addSwapData adds the swap and redeem transactions from storage to the
+// report. The report is still sent if the swap data cannot be loaded.
+func (r *Reporter) addSwapData(rpt *pendingReport) {
+	_, sd, err := r.storage.SwapData(rpt.mid)
+	if err != nil {
+		r.log.Errorf("Error loading swap data for match %s report: %v", rpt.MatchID, err)
+		return
+	}
+	// The maker is party A, and the taker is party B.
+	makerSwapAsset, takerSwapAsset := rpt.QuoteID, rpt.BaseID
+	if rpt.Maker.Sell {
+		makerSwapAsset, takerSwapAsset = rpt.BaseID, rpt.QuoteID
+	}
+	rpt.Maker.SwapCoinID = coinIDString(makerSwapAsset, sd.ContractACoinID)
+	rpt.Maker.SwapTime = sd.ContractATime
+	rpt.Maker.RedeemCoinID = coinIDString(takerSwapAsset, sd.RedeemACoinID)
+	rpt.Maker.RedeemTime = sd.RedeemATime
+	rpt.Taker.SwapCoinID = coinIDString(takerSwapAsset, sd.ContractBCoinID)
+	rpt.Taker.SwapTime = sd.ContractBTime
+	rpt.Taker.RedeemCoinID = coinIDString(makerSwapAsset, sd.RedeemBCoinID)
+	rpt.Taker.RedeemTime = sd.RedeemBTime
+}
+
+// coinIDString is the human-readable coin ID, or the hex encoding if the asset
+// cannot decode it.
+func coinIDString(assetID uint32, coinID []byte) string {
+	if len(coinID) == 0 {
+		return ""
+	}
+	if s, err := asset.DecodeCoinID(assetID, coinID); err == nil {
+		return s
+	}
+	return hex.EncodeToString(coinID)
+}
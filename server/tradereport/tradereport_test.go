@@ -0,0 +1,471 @@
+package tradereport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"github.com/segmentio/kafka-go"
+)
+
+type tStorage struct {
+	swapData *db.SwapData
+	err      error
+}
+
+func (s *tStorage) SwapData(mid db.MarketMatchID) (order.MatchStatus, *db.SwapData, error) {
+	return order.MatchComplete, s.swapData, s.err
+}
+
+type tSink struct {
+	mtx   sync.Mutex
+	fails int
+	// reject is the match ID of a report that always fails.
+	reject string
+	rpts   []*MatchReport
+	sent   chan struct{}
+}
+
+func (s *tSink) Send(_ context.Context, rpt *MatchReport) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.fails > 0 || rpt.MatchID == s.reject {
+		if s.fails > 0 {
+			s.fails--
+		}
+		return errors.New("test error")
+	}
+	s.rpts = append(s.rpts, rpt)
+	s.sent <- struct{}{}
+	return nil
+}
+
+func tMatch() *order.Match {
+	return tMatchQty(1e8)
+}
+
+// tMatchQty is a match with the quantity, which is part of the match ID.
+func tMatchQty(qty uint64) *order.Match {
+	maker := &order.LimitOrder{
+		P: order.Prefix{
+			AccountID:  account.AccountID{0x01},
+			BaseAsset:  42,
+			QuoteAsset: 0,
+			OrderType:  order.LimitOrderType,
+			ServerTime: time.UnixMilli(1700000000000),
+		},
+		T:    order.Trade{Sell: true, Quantity: 4e8},
+		Rate: 2e6,
+	}
+	taker := &order.MarketOrder{
+		P: order.Prefix{
+			AccountID:  account.AccountID{0x02},
+			BaseAsset:  42,
+			QuoteAsset: 0,
+			OrderType:  order.MarketOrderType,
+			ServerTime: time.UnixMilli(1700000001000),
+		},
+		T: order.Trade{Quantity: 1e8},
+	}
+	return &order.Match{
+		Maker:    maker,
+		Taker:    taker,
+		Quantity: qty,
+		Rate:     2e6,
+		Epoch:    order.EpochID{Idx: 100, Dur: 6000},
+	}
+}
+
+func TestReporter(t *testing.T) {
+	sink := &tSink{fails: 1, sent: make(chan struct{}, 1)}
+	storage := &tStorage{swapData: &db.SwapData{
+		ContractACoinID: []byte{0x0a},
+		ContractATime:   1,
+		ContractBCoinID: []byte{0x0b},
+		ContractBTime:   2,
+		RedeemACoinID:   []byte{0x1a},
+		RedeemATime:     3,
+		RedeemBCoinID:   []byte{0x1b},
+		RedeemBTime:     4,
+	}}
+	r, err := NewReporter(&Config{
+		Sink:      sink,
+		Storage:   storage,
+		Logger:    dex.StdOutLogger("T", dex.LevelOff),
+		SpoolPath: filepath.Join(t.TempDir(), "tradereport.spool"),
+	})
+	if err != nil {
+		t.Fatalf("NewReporter error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ssw := dex.NewStartStopWaiter(r)
+	ssw.Start(ctx)
+	defer func() {
+		ssw.Stop()
+		ssw.WaitForShutdown()
+	}()
+
+	match := tMatch()
+	completed := time.UnixMilli(1700000100000)
+	r.MatchComplete(match, completed)
+
+	// The first send fails and is retried.
+	select {
+	case <-sink.sent:
+	case <-time.After(minRetryDelay * 5):
+		t.Fatalf("report not sent")
+	}
+	sink.mtx.Lock()
+	rpt := sink.rpts[0]
+	sink.mtx.Unlock()
+
+	if rpt.Version != SchemaVersion || rpt.MatchID != match.ID().String() || rpt.Market != "dcr_btc" {
+		t.Fatalf("wrong match info: %+v", rpt)
+	}
+	if rpt.MatchTime != 101*6000 || rpt.CompleteTime != completed.UnixMilli() {
+		t.Fatalf("wrong times: match %d, complete %d", rpt.MatchTime, rpt.CompleteTime)
+	}
+	if rpt.Quantity != 1e8 || rpt.QuoteQty != 2e6 {
+		t.Fatalf("wrong quantities: %d, %d", rpt.Quantity, rpt.QuoteQty)
+	}
+	if !rpt.Maker.Sell || rpt.Taker.Sell || rpt.Maker.OrderID != match.Maker.ID().String() ||
+		rpt.Taker.AccountID != match.Taker.User().String() {
+		t.Fatalf("wrong parties: %+v, %+v", rpt.Maker, rpt.Taker)
+	}
+	// No asset drivers are registered, so coin IDs are hex.
+	if rpt.Maker.SwapCoinID != "0a" || rpt.Maker.RedeemCoinID != "1a" || rpt.Maker.RedeemTime != 3 ||
+		rpt.Taker.SwapCoinID != "0b" || rpt.Taker.RedeemCoinID != "1b" || rpt.Taker.SwapTime != 2 {
+		t.Fatalf("wrong swap data: %+v, %+v", rpt.Maker, rpt.Taker)
+	}
+
+	// Reports are still sent without swap data.
+	storage.err = errors.New("test error")
+	r.MatchComplete(match, completed)
+	select {
+	case <-sink.sent:
+	case <-time.After(time.Second):
+		t.Fatalf("report without swap data not sent")
+	}
+}
+
+// runReporter runs a Reporter with the spool until stop is called.
+func runReporter(t *testing.T, sink Sink, spoolPath string) (stop func(), r *Reporter) {
+	t.Helper()
+	r, err := NewReporter(&Config{
+		Sink:      sink,
+		Storage:   &tStorage{swapData: &db.SwapData{}},
+		Logger:    dex.StdOutLogger("T", dex.LevelOff),
+		SpoolPath: spoolPath,
+	})
+	if err != nil {
+		t.Fatalf("NewReporter error: %v", err)
+	}
+	ssw := dex.NewStartStopWaiter(r)
+	ssw.Start(context.Background())
+	return func() {
+		ssw.Stop()
+		ssw.WaitForShutdown()
+	}, r
+}
+
+func TestReporterSpool(t *testing.T) {
+	spoolPath := filepath.Join(t.TempDir(), "tradereport.spool")
+	completed := time.UnixMilli(1700000100000)
+
+	// The sink is down, so the reports stay in the spool.
+	down := &tSink{fails: 100, sent: make(chan struct{}, 10)}
+	stop, r := runReporter(t, down, spoolPath)
+	matches := []*order.Match{tMatchQty(1e8), tMatchQty(2e8), tMatchQty(3e8)}
+	for _, match := range matches {
+		r.MatchComplete(match, completed)
+	}
+	stop()
+
+	// The next Reporter sends them in order.
+	up := &tSink{sent: make(chan struct{}, 10)}
+	stop, _ = runReporter(t, up, spoolPath)
+	for i := range matches {
+		select {
+		case <-up.sent:
+		case <-time.After(time.Second):
+			t.Fatalf("spooled report %d not sent", i)
+		}
+	}
+	stop()
+	for i, match := range matches {
+		if up.rpts[i].MatchID != match.ID().String() {
+			t.Fatalf("wrong report %d: %s", i, up.rpts[i].MatchID)
+		}
+	}
+
+	// The sent reports are removed from the spool.
+	rpts, err := readSpool(spoolPath)
+	if err != nil {
+		t.Fatalf("readSpool error: %v", err)
+	}
+	if len(rpts) != 0 {
+		t.Fatalf("%d sent reports still spooled", len(rpts))
+	}
+}
+
+func TestReporterFailingReport(t *testing.T) {
+	bad, good := tMatchQty(1e8), tMatchQty(2e8)
+	sink := &tSink{reject: bad.ID().String(), sent: make(chan struct{}, 10)}
+	stop, r := runReporter(t, sink, filepath.Join(t.TempDir(), "tradereport.spool"))
+	defer stop()
+
+	// A report that the sink rejects does not block later reports.
+	completed := time.UnixMilli(1700000100000)
+	r.MatchComplete(bad, completed)
+	r.MatchComplete(good, completed)
+	select {
+	case <-sink.sent:
+	case <-time.After(time.Second):
+		t.Fatalf("report not sent after a rejected report")
+	}
+	sink.mtx.Lock()
+	defer sink.mtx.Unlock()
+	if len(sink.rpts) != 1 || sink.rpts[0].MatchID != good.ID().String() {
+		t.Fatalf("wrong reports sent: %+v", sink.rpts)
+	}
+}
+
+func TestSpool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tradereport.spool")
+	sp, rpts, err := openSpool(path)
+	if err != nil {
+		t.Fatalf("openSpool error: %v", err)
+	}
+	if len(rpts) != 0 {
+		t.Fatalf("new spool has %d reports", len(rpts))
+	}
+	all := []*MatchReport{{MatchID: "aa"}, {MatchID: "bb"}, {MatchID: "cc"}}
+	for _, rpt := range all {
+		if err := sp.add(rpt); err != nil {
+			t.Fatalf("add error: %v", err)
+		}
+	}
+	if err := sp.sent("bb", []*MatchReport{all[0], all[2]}); err != nil {
+		t.Fatalf("sent error: %v", err)
+	}
+	sp.close()
+
+	checkSpool := func(wantIDs ...string) {
+		t.Helper()
+		sp, rpts, err := openSpool(path)
+		if err != nil {
+			t.Fatalf("openSpool error: %v", err)
+		}
+		sp.close()
+		if len(rpts) != len(wantIDs) {
+			t.Fatalf("expected %d spooled reports, got %d", len(wantIDs), len(rpts))
+		}
+		for i, id := range wantIDs {
+			if rpts[i].MatchID != id {
+				t.Fatalf("wrong report %d: %s", i, rpts[i].MatchID)
+			}
+		}
+	}
+	checkSpool("aa", "cc")
+
+	// A partial last line is discarded.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("error opening spool: %v", err)
+	}
+	f.WriteString(`{"report":{"matchID":"dd"`)
+	f.Close()
+	checkSpool("aa", "cc")
+
+	// A bad line that is not the last is an error.
+	if err := os.WriteFile(path, []byte("{\n{\"sent\":\"aa\"}\n"), 0600); err != nil {
+		t.Fatalf("error writing spool: %v", err)
+	}
+	if _, _, err := openSpool(path); err == nil {
+		t.Fatalf("no error for corrupt spool")
+	}
+}
+
+type tKafkaWriter struct {
+	msgs []kafka.Message
+	err  error
+}
+
+func (w *tKafkaWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func (w *tKafkaWriter) Close() error { return nil }
+
+func TestKafkaSink(t *testing.T) {
+	if _, err := NewKafkaSink("kafka://broker:9092/matches", "nopassword"); err == nil {
+		t.Fatalf("no error for bad auth")
+	}
+	sink, err := NewKafkaSink("kafka://broker:9092/matches", "user:pass")
+	if err != nil {
+		t.Fatalf("NewKafkaSink error: %v", err)
+	}
+	kw := sink.w.(*kafka.Writer)
+	if kw.Topic != "matches" || kw.RequiredAcks != kafka.RequireAll || kw.MaxAttempts != 1 {
+		t.Fatalf("wrong writer config: %+v", kw)
+	}
+	if transport := kw.Transport.(*kafka.Transport); transport.SASL == nil || transport.TLS == nil {
+		t.Fatalf("no SASL or TLS config")
+	}
+	sink.Close()
+	w := new(tKafkaWriter)
+	sink.w = w
+
+	if err := sink.Send(context.Background(), &MatchReport{MatchID: "ab", Epoch: 5}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if len(w.msgs) != 1 || string(w.msgs[0].Key) != "ab" {
+		t.Fatalf("wrong messages: %+v", w.msgs)
+	}
+	var rpt MatchReport
+	if err := json.Unmarshal(w.msgs[0].Value, &rpt); err != nil || rpt.Epoch != 5 {
+		t.Fatalf("wrong message value %s: %v", w.msgs[0].Value, err)
+	}
+
+	w.err = errors.New("test error")
+	if err := sink.Send(context.Background(), &MatchReport{MatchID: "ab"}); err == nil {
+		t.Fatalf("no error for failed write")
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		dest    string
+		wantErr bool
+	}{{
+		name: "https",
+		dest: "https://example.com/matches",
+	}, {
+		name: "file",
+		dest: filepath.Join(dir, "a.jsonl"),
+	}, {
+		name: "file scheme",
+		dest: "file://" + filepath.Join(dir, "b.jsonl"),
+	}, {
+		name:    "http",
+		dest:    "http://example.com/matches",
+		wantErr: true,
+	}, {
+		name: "kafka",
+		dest: "kafka://broker1:9092,broker2:9092/matches",
+	}, {
+		name:    "kafka no topic",
+		dest:    "kafka://broker:9092",
+		wantErr: true,
+	}, {
+		name:    "kafka no port",
+		dest:    "kafka://broker/matches",
+		wantErr: true,
+	}, {
+		name:    "no host",
+		dest:    "https:///matches",
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		sink, err := NewSink(test.dest, "")
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s: no error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if c, is := sink.(io.Closer); is {
+			c.Close()
+		}
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trades.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := sink.Send(context.Background(), &MatchReport{Version: SchemaVersion, Epoch: uint64(i)}); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("error opening report file: %v", err)
+	}
+	defer f.Close()
+	var n uint64
+	scanner := bufio.NewScanner(f)
+	for ; scanner.Scan(); n++ {
+		var rpt MatchReport
+		if err := json.Unmarshal(scanner.Bytes(), &rpt); err != nil {
+			t.Fatalf("error decoding line %d: %v", n, err)
+		}
+		if rpt.Epoch != n {
+			t.Fatalf("wrong report on line %d: %+v", n, rpt)
+		}
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 lines, got %d", n)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var status int
+	var gotAuth string
+	var got MatchReport
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink, err := NewHTTPSink(srv.URL, "Bearer abc")
+	if err != nil {
+		t.Fatalf("NewHTTPSink error: %v", err)
+	}
+	sink.client = srv.Client()
+
+	status = http.StatusOK
+	if err := sink.Send(context.Background(), &MatchReport{MatchID: "ab"}); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if gotAuth != "Bearer abc" || got.MatchID != "ab" {
+		t.Fatalf("wrong request: auth %q, report %+v", gotAuth, got)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Send(context.Background(), &MatchReport{MatchID: "ab"}); err == nil {
+		t.Fatalf("no error for failed request")
+	}
+}